@@ -2,6 +2,7 @@
 
 import chalk from 'chalk';
 import { Command } from 'commander';
+import { apiCommand } from './commands/api.js';
 import { cleanCommand } from './commands/clean.js';
 import { compactCommand } from './commands/compact.js';
 import { dashboardCommand } from './commands/dashboard.js';
@@ -38,6 +39,7 @@ program.addCommand(planCommand);
 program.addCommand(githubCommand);
 program.addCommand(gitCommand);
 program.addCommand(mapCommand);
+program.addCommand(apiCommand);
 program.addCommand(updateCommand);
 program.addCommand(statsCommand);
 program.addCommand(dashboardCommand);
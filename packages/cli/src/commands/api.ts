@@ -0,0 +1,115 @@
+/**
+ * API Command
+ * Print the exported API surface of a package, or check it against a committed file
+ */
+
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+import {
+  ensureStorageDirectory,
+  formatApiSurface,
+  generateApiSurface,
+  getStorageFilePaths,
+  getStoragePath,
+  RepositoryIndexer,
+} from '@lytics/dev-agent-core';
+import chalk from 'chalk';
+import { Command } from 'commander';
+import { loadConfig } from '../utils/config.js';
+import { logger } from '../utils/logger.js';
+import { output } from '../utils/output.js';
+
+export const apiCommand = new Command('api')
+  .description('Show the exported API surface of a package')
+  .argument('[package]', 'Package directory relative to the repository root', '.')
+  .option('-r, --recursive', 'Include subdirectories', false)
+  .option('--include-tests', 'Include symbols from test files', false)
+  .option('--no-docs', 'Omit doc comment summaries')
+  .option('-o, --output <file>', 'Write the report to a file')
+  .option('--check <file>', 'Exit with an error if the API differs from a committed report')
+  .addHelpText(
+    'after',
+    `
+Examples:
+  $ dev api pkg/server                     Print the API of one package
+  $ dev api pkg/server -o api/server.txt   Write a report to commit
+  $ dev api pkg/server --check api/server.txt
+                                           Fail if the public API changed
+
+Use Case:
+  - Reviewing public API changes in pull requests
+  - Gating CI on unintended API changes
+
+Output is sorted and contains no line numbers, so reports are stable and diffable.
+Run 'dev update' first so the index reflects the working tree.
+`
+  )
+  .action(async (pkg: string, options) => {
+    try {
+      const config = await loadConfig();
+      if (!config) {
+        logger.error('No config found');
+        logger.log('Run "dev init" first to initialize dev-agent');
+        process.exit(1);
+      }
+
+      const repositoryPath = path.resolve(
+        config.repository?.path || config.repositoryPath || process.cwd()
+      );
+      const storagePath = await getStoragePath(repositoryPath);
+      await ensureStorageDirectory(storagePath);
+      const filePaths = getStorageFilePaths(storagePath);
+
+      const indexer = new RepositoryIndexer({
+        repositoryPath,
+        vectorStorePath: filePaths.vectors,
+        statePath: filePaths.indexerState,
+      });
+
+      // Read-only access: no embeddings needed
+      await indexer.initialize({ skipEmbedder: true });
+
+      const stats = await indexer.getBasicStats();
+      if (!stats || stats.filesScanned === 0) {
+        await indexer.close();
+        logger.error('Repository not indexed. Run "dev index ." first.');
+        process.exit(1);
+      }
+
+      const surface = await generateApiSurface(indexer, {
+        path: pkg,
+        recursive: options.recursive,
+        includeTests: options.includeTests,
+      });
+      await indexer.close();
+
+      const report = formatApiSurface(surface, { includeDocs: options.docs });
+
+      if (options.check) {
+        const expected = await fs.readFile(options.check, 'utf-8').catch(() => null);
+        if (expected === null) {
+          logger.error(`Cannot read ${options.check}`);
+          process.exit(1);
+        }
+        if (expected !== report) {
+          logger.error(`API surface of ${surface.package} differs from ${options.check}`);
+          output.log(chalk.dim(`Run "dev api ${pkg} -o ${options.check}" to update it.`));
+          process.exit(1);
+        }
+        logger.success(`API surface of ${surface.package} matches ${options.check}`);
+        return;
+      }
+
+      if (options.output) {
+        await fs.mkdir(path.dirname(path.resolve(options.output)), { recursive: true });
+        await fs.writeFile(options.output, report, 'utf-8');
+        logger.success(`Wrote API surface of ${surface.package} to ${options.output}`);
+        return;
+      }
+
+      process.stdout.write(report);
+    } catch (error) {
+      logger.error(`Failed to build API surface: ${(error as Error).message}`);
+      process.exit(1);
+    }
+  });
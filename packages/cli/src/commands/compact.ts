@@ -42,6 +42,7 @@ export const compactCommand = new Command('compact')
         vectorStorePath: filePaths.vectors,
         statePath: filePaths.indexerState,
         excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+        ignorePatterns: config.repository?.ignorePatterns,
         languages: config.repository?.languages || config.languages,
       });
 
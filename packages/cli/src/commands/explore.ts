@@ -1,6 +1,8 @@
 import * as path from 'node:path';
 import {
+  detectClones,
   ensureStorageDirectory,
+  formatCloneReport,
   getStorageFilePaths,
   getStoragePath,
   RepositoryIndexer,
@@ -46,6 +48,7 @@ explore
         vectorStorePath: filePaths.vectors,
         statePath: filePaths.indexerState,
         excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+        ignorePatterns: config.repository?.ignorePatterns,
         languages: config.repository?.languages || config.languages,
       });
 
@@ -134,6 +137,7 @@ explore
         vectorStorePath: filePaths.vectors,
         statePath: filePaths.indexerState,
         excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+        ignorePatterns: config.repository?.ignorePatterns,
         languages: config.repository?.languages || config.languages,
       });
 
@@ -185,4 +189,68 @@ explore
     }
   });
 
+// Clone detection subcommand
+explore
+  .command('clones')
+  .description('Find clusters of duplicated or near-duplicate functions')
+  .argument('[path]', 'Only check symbols under this path prefix')
+  .option('--min-lines <number>', 'Ignore bodies shorter than this', '5')
+  .option('-t, --threshold <number>', 'Embedding similarity for semantic clones (0-1)', '0.95')
+  .option('--structural-only', 'Only report copy-paste clones (skip embedding comparison)', false)
+  .option('--include-tests', 'Include test files', false)
+  .action(async (pathPrefix: string | undefined, options) => {
+    const spinner = ora('Detecting clones...').start();
+
+    try {
+      const config = await loadConfig();
+      if (!config) {
+        spinner.fail('No config found');
+        logger.error('Run "dev init" first');
+        process.exit(1);
+        return;
+      }
+
+      // Resolve repository path
+      const repositoryPath = config.repository?.path || config.repositoryPath || process.cwd();
+      const resolvedRepoPath = path.resolve(repositoryPath);
+
+      // Get centralized storage paths
+      const storagePath = await getStoragePath(resolvedRepoPath);
+      await ensureStorageDirectory(storagePath);
+      const filePaths = getStorageFilePaths(storagePath);
+
+      const indexer = new RepositoryIndexer({
+        repositoryPath: resolvedRepoPath,
+        vectorStorePath: filePaths.vectors,
+        statePath: filePaths.indexerState,
+      });
+
+      // Stored vectors are reused, so no embedding model is needed
+      await indexer.initialize({ skipEmbedder: true });
+
+      const clusters = await detectClones(indexer, {
+        path: pathPrefix,
+        minLines: Number.parseInt(options.minLines, 10),
+        threshold: Number.parseFloat(options.threshold),
+        semantic: !options.structuralOnly,
+        includeTests: options.includeTests,
+      });
+
+      await indexer.close();
+
+      spinner.succeed(`Found ${clusters.length} clone clusters`);
+
+      if (clusters.length === 0) {
+        return;
+      }
+
+      console.log(chalk.cyan('\n🧬 Clone Clusters\n'));
+      console.log(formatCloneReport(clusters));
+    } catch (error) {
+      spinner.fail('Clone detection failed');
+      logger.error((error as Error).message);
+      process.exit(1);
+    }
+  });
+
 export { explore as exploreCommand };
@@ -1,5 +1,6 @@
 import { execSync } from 'node:child_process';
 import { existsSync } from 'node:fs';
+import { writeFile } from 'node:fs/promises';
 import { join, resolve } from 'node:path';
 import {
   AsyncEventBus,
@@ -64,6 +65,24 @@ export const indexCommand = new Command('index')
   .option('--no-github', 'Skip GitHub issues/PRs indexing')
   .option('--git-limit <number>', 'Max git commits to index (default: 500)', Number.parseInt, 500)
   .option('--gh-limit <number>', 'Max GitHub issues/PRs to fetch (default: 500)', Number.parseInt)
+  .option('--batch-size <number>', 'Documents per embedding batch (default: 32)', Number.parseInt)
+  .option(
+    '--max-retries <number>',
+    'Retries per embedding batch on rate limits and server errors (default: 5)',
+    Number.parseInt
+  )
+  .option(
+    '--embedding-concurrency <number>',
+    'Max embedding requests in flight (default: DEV_AGENT_EMBEDDING_CONCURRENCY or 4)',
+    Number.parseInt
+  )
+  .option('--blame', "Record each symbol's last commit date and author (slower)", false)
+  .option('--go-diagnostics', 'Attach go build and go vet findings to Go symbols', false)
+  .option(
+    '--embedding-text <mode>',
+    'Embed each symbol\'s "source" or a metadata "card" (default: source; needs --force to switch)'
+  )
+  .option('--stats-json <file>', 'Write scan statistics and phase timings as JSON to a file')
   .action(async (repositoryPath: string, options) => {
     const spinner = ora('Checking prerequisites...').start();
 
@@ -113,6 +132,13 @@ export const indexCommand = new Command('index')
         config = getDefaultConfig(repositoryPath);
       }
 
+      const embeddingText = options.embeddingText ?? config.repository?.embeddingText;
+      if (embeddingText && embeddingText !== 'source' && embeddingText !== 'card') {
+        spinner.fail('Invalid embedding text');
+        logger.error('Embedding text must be "source" or "card"');
+        process.exit(1);
+      }
+
       // Get centralized storage path
       spinner.text = 'Resolving storage path...';
       const storagePath = await getStoragePath(resolvedRepoPath);
@@ -154,9 +180,27 @@ export const indexCommand = new Command('index')
           vectorStorePath: filePaths.vectors,
           statePath: filePaths.indexerState,
           excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+          ignorePatterns: config.repository?.ignorePatterns,
           languages: config.repository?.languages || config.languages,
           embeddingModel: config.embeddingModel,
           embeddingDimension: config.dimension,
+          embeddingMaxTokens: config.repository?.embeddingMaxTokens,
+          embeddingText,
+          maxDocumentBytes: config.repository?.maxDocumentBytes,
+          embeddingProfiles: config.repository?.embeddingProfiles,
+          embeddingProfile: config.repository?.embeddingProfile,
+          embeddingProfileBindings: config.repository?.embeddingProfileBindings,
+          similarityMetric: config.repository?.similarityMetric,
+          quantization: config.repository?.quantization,
+          truncateDimensions: config.repository?.truncateDimensions,
+          blame: options.blame || config.repository?.blame,
+          goDiagnostics: options.goDiagnostics || config.repository?.goDiagnostics,
+          boilerplateDocThreshold: config.repository?.boilerplateDocThreshold,
+          symbolFilters: config.repository?.symbolFilters,
+          enrichers: config.repository?.enrichers,
+          targetPlatform: config.repository?.targetPlatform,
+          embeddingRetry: { maxRetries: options.maxRetries },
+          maxConcurrentEmbeddings: options.embeddingConcurrency,
         },
         eventBus
       );
@@ -183,6 +227,7 @@ export const indexCommand = new Command('index')
 
       const stats = await indexer.index({
         force: options.force,
+        batchSize: options.batchSize,
         logger: indexLogger,
         onProgress: (progress) => {
           if (progress.phase === 'storing' && progress.totalDocuments) {
@@ -339,6 +384,14 @@ export const indexCommand = new Command('index')
         })
       );
 
+      // Unchanged text reuses cached vectors instead of being re-embedded
+      const cache = stats.embeddingCache;
+      if (cache && cache.hits > 0) {
+        const hitRate = Math.round((cache.hits / (cache.hits + cache.misses)) * 100);
+        const reused = `Reused ${cache.hits.toLocaleString()} cached embeddings`;
+        output.log(`  ${chalk.gray(`${reused} (${hitRate}% hit rate)`)}`);
+      }
+
       // Show errors if any
       if (stats.errors.length > 0) {
         output.log('');
@@ -354,6 +407,49 @@ export const indexCommand = new Command('index')
         }
       }
 
+      // Report documents that could not be embedded after retries
+      const failedDocuments = stats.failedDocuments ?? [];
+      if (failedDocuments.length > 0) {
+        output.log('');
+        output.warn(`${failedDocuments.length} document(s) could not be embedded after retries`);
+        const failedFiles = [...new Set(failedDocuments.map((doc) => doc.file))];
+        for (const file of failedFiles.slice(0, options.verbose ? undefined : 10)) {
+          output.log(`  ${chalk.gray(file)}`);
+        }
+        if (!options.verbose && failedFiles.length > 10) {
+          output.log(`  ${chalk.gray(`...and ${failedFiles.length - 10} more files`)}`);
+        }
+        output.log(
+          `  ${chalk.gray('Run')} ${chalk.cyan('dev update')} ${chalk.gray('to retry them')}`
+        );
+      }
+
+      // Languages with files but no documents usually mean a broken scanner
+      for (const [language, languageStats] of Object.entries(stats.scanStats?.byLanguage ?? {})) {
+        if (languageStats.files > 0 && languageStats.documents === 0) {
+          output.log('');
+          output.warn(
+            `No symbols extracted from ${languageStats.files} ${language} file(s) ` +
+              `(${languageStats.parseFailures} parse failures)`
+          );
+        }
+      }
+
+      if (options.statsJson) {
+        const report = {
+          filesScanned: stats.filesScanned,
+          documentsExtracted: stats.documentsExtracted,
+          documentsIndexed: stats.documentsIndexed,
+          bytesEmbedded: stats.bytesEmbedded ?? 0,
+          embeddingCache: stats.embeddingCache,
+          duration: stats.duration,
+          timings: stats.timings,
+          scan: stats.scanStats,
+        };
+        await writeFile(options.statsJson, `${JSON.stringify(report, null, 2)}\n`, 'utf-8');
+        output.log(`  ${chalk.gray('Wrote index stats to')} ${chalk.cyan(options.statsJson)}`);
+      }
+
       output.log('');
     } catch (error) {
       spinner.fail('Failed to index repository');
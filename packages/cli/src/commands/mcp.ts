@@ -13,23 +13,59 @@ import {
   GitIndexer,
   getStorageFilePaths,
   getStoragePath,
+  keywordSettingsFromEnv,
   LocalGitExtractor,
+  OutputTokenizer,
   RepositoryIndexer,
+  rerankerFromEnv,
   SearchService,
   StatsService,
   VectorStorage,
 } from '@lytics/dev-agent-core';
 import {
+  AUTO_REINDEX_ENV,
+  CallPathAdapter,
+  ChangelogAdapter,
+  ConstraintsAdapter,
+  ContextAdapter,
+  DepsAdapter,
+  DiffAdapter,
+  FeedbackAdapter,
+  ExplainAdapter,
   ExploreAdapter,
+  formatFreshness,
   GitHubAdapter,
+  GraphAdapter,
   HealthAdapter,
   HistoryAdapter,
+  ImplAdapter,
+  IndexFreshnessMonitor,
+  isAutoReindexEnabled,
+  LookupAdapter,
   MapAdapter,
+  NeighborsAdapter,
+  MAX_OUTPUT_TOKENS_ENV,
   MCPServer,
+  OpenApiAdapter,
+  OutlineAdapter,
+  parseMaxOutputTokens,
+  parseResultCacheSize,
   PlanAdapter,
+  RecursionAdapter,
   RefsAdapter,
+  ReindexAdapter,
+  ReindexJobs,
+  RESULT_CACHE_SIZE_ENV,
+  ResultCache,
+  RoutesAdapter,
   SearchAdapter,
+  SimilarAdapter,
+  SqlAdapter,
   StatusAdapter,
+  TestAdapter,
+  type ToolResult,
+  UsageAdapter,
+  WhereisAdapter,
 } from '@lytics/dev-agent-mcp';
 import type { SubagentCoordinator } from '@lytics/dev-agent-subagents';
 import chalk from 'chalk';
@@ -60,9 +96,14 @@ Setup:
   2. Install MCP integration: dev mcp install --cursor
   3. Restart Cursor to activate
 
-Available Tools (9):
+Available Tools (31):
   dev_search, dev_status, dev_plan, dev_inspect, dev_gh,
-  dev_health, dev_refs, dev_map, dev_history
+  dev_health, dev_refs, dev_map, dev_history, dev_diff,
+  dev_lookup, dev_similar, dev_context, dev_usage, dev_test,
+  dev_outline, dev_impl, dev_changelog, dev_whereis, dev_routes,
+  dev_graph, dev_recursion, dev_neighbors, dev_callpath, dev_sql,
+  dev_openapi, dev_constraints, dev_deps, dev_feedback, dev_explain,
+  dev_reindex
 `
   )
   .addCommand(
@@ -71,6 +112,19 @@ Available Tools (9):
       .option('-p, --port <port>', 'Port for HTTP transport (if not using stdio)')
       .option('-t, --transport <type>', 'Transport type: stdio (default) or http', 'stdio')
       .option('-v, --verbose', 'Verbose logging', false)
+      .option(
+        '--auto-reindex',
+        `Re-index files changed since the last index on startup (or set ${AUTO_REINDEX_ENV}=1)`,
+        false
+      )
+      .option(
+        '--cache-size <n>',
+        `Results to cache for repeated queries, 0 to disable (or set ${RESULT_CACHE_SIZE_ENV})`
+      )
+      .option(
+        '--max-tokens <n>',
+        `Trim any tool response over this many tokens (or set ${MAX_OUTPUT_TOKENS_ENV})`
+      )
       .action(async (options) => {
         // Smart workspace detection:
         // Priority: WORKSPACE_FOLDER_PATHS (Cursor) > REPOSITORY_PATH (explicit) > cwd (fallback)
@@ -106,7 +160,9 @@ Available Tools (9):
             statePath: getStorageFilePaths(storagePath).indexerState,
           });
 
-          await indexer.initialize();
+          // The embedding model loads on first semantic search, so the server starts, and
+          // name-based tools keep working, when the model can't be loaded
+          await indexer.initialize({ skipEmbedder: true });
 
           // Create and configure the subagent coordinator using CoordinatorService
           const coordinatorService = new CoordinatorService({
@@ -121,13 +177,25 @@ Available Tools (9):
           )) as SubagentCoordinator;
 
           // Create services
-          const searchService = new SearchService({ repositoryPath });
+          // DEV_AGENT_RERANK_MODEL turns on cross-encoder reranking of the top results,
+          // DEV_AGENT_KEYWORD_WEIGHT IDF keyword boosting
+          const searchService = new SearchService({
+            repositoryPath,
+            reranker: rerankerFromEnv(),
+            ...keywordSettingsFromEnv(),
+          });
+
+          // Repeated queries against the same index are answered from memory
+          const resultCache = new ResultCache<ToolResult>(
+            parseResultCacheSize(options.cacheSize ?? process.env[RESULT_CACHE_SIZE_ENV])
+          );
 
           // Create all adapters
           const searchAdapter = new SearchAdapter({
             searchService,
             defaultFormat: 'compact',
             defaultLimit: 10,
+            resultCache,
           });
 
           const statsService = new StatsService({ repositoryPath });
@@ -139,12 +207,24 @@ Available Tools (9):
 
           const githubService = new GitHubService({ repositoryPath }, createGitHubIndexer);
 
+          const freshness = new IndexFreshnessMonitor({
+            indexer,
+            autoReindex: options.autoReindex || isAutoReindexEnabled(process.env[AUTO_REINDEX_ENV]),
+            logger,
+          });
+
+          // dev_reindex jobs run in the background; dev_status reports the latest
+          const reindexJobs = new ReindexJobs({ indexer, freshness, logger });
+
           const statusAdapter = new StatusAdapter({
             statsService,
             githubService,
             repositoryPath,
             vectorStorePath: vectors,
             defaultSection: 'summary',
+            freshness,
+            resultCache,
+            reindexJobs,
           });
 
           const exploreAdapter = new ExploreAdapter({
@@ -173,6 +253,41 @@ Available Tools (9):
             defaultLimit: 20,
           });
 
+          const lookupAdapter = new LookupAdapter({
+            searchService,
+            defaultLimit: 10,
+          });
+
+          const similarAdapter = new SimilarAdapter({
+            searchService,
+            defaultLimit: 10,
+          });
+
+          const contextAdapter = new ContextAdapter({
+            searchService,
+            defaultTokenBudget: 4000,
+          });
+
+          const usageAdapter = new UsageAdapter({
+            searchService,
+            defaultLimit: 5,
+          });
+
+          const outlineAdapter = new OutlineAdapter({
+            searchService,
+            defaultLimit: 30,
+          });
+
+          const implAdapter = new ImplAdapter({
+            searchService,
+            defaultLimit: 50,
+          });
+
+          const testAdapter = new TestAdapter({
+            searchService,
+            defaultDepth: 5,
+          });
+
           const mapAdapter = new MapAdapter({
             repositoryIndexer: indexer,
             repositoryPath,
@@ -185,7 +300,7 @@ Available Tools (9):
           const gitVectorStorage = new VectorStorage({
             storePath: `${vectors}-git`,
           });
-          await gitVectorStorage.initialize();
+          await gitVectorStorage.initialize({ skipEmbedder: true });
 
           const gitIndexer = new GitIndexer({
             extractor: gitExtractor,
@@ -199,6 +314,70 @@ Available Tools (9):
             defaultTokenBudget: 2000,
           });
 
+          const diffAdapter = new DiffAdapter({
+            repositoryPath,
+            gitExtractor,
+            defaultTokenBudget: 2000,
+          });
+
+          const changelogAdapter = new ChangelogAdapter({
+            repositoryPath,
+            gitExtractor,
+            defaultTokenBudget: 4000,
+          });
+
+          const whereisAdapter = new WhereisAdapter({
+            searchService,
+          });
+
+          const routesAdapter = new RoutesAdapter({
+            searchService,
+          });
+
+          const graphAdapter = new GraphAdapter({
+            searchService,
+          });
+
+          const recursionAdapter = new RecursionAdapter({
+            searchService,
+          });
+
+          const neighborsAdapter = new NeighborsAdapter({
+            searchService,
+          });
+
+          const callPathAdapter = new CallPathAdapter({
+            searchService,
+          });
+
+          const sqlAdapter = new SqlAdapter({
+            searchService,
+          });
+
+          const openApiAdapter = new OpenApiAdapter({
+            searchService,
+          });
+
+          const constraintsAdapter = new ConstraintsAdapter({
+            searchService,
+          });
+
+          const depsAdapter = new DepsAdapter({
+            searchService,
+          });
+
+          const feedbackAdapter = new FeedbackAdapter({
+            searchService,
+          });
+
+          const explainAdapter = new ExplainAdapter({
+            searchService,
+          });
+
+          const reindexAdapter = new ReindexAdapter({
+            jobs: reindexJobs,
+          });
+
           // Update plan adapter to include git indexer
           const planAdapterWithGit = new PlanAdapter({
             repositoryIndexer: indexer,
@@ -208,7 +387,51 @@ Available Tools (9):
             timeout: 60000,
           });
 
-          // Create MCP server with all 9 adapters
+          // Responses over the budget are trimmed, counted with a chat-model tokenizer
+          // that loads in the background (estimates until it has)
+          const maxTokens = parseMaxOutputTokens(
+            options.maxTokens ?? process.env[MAX_OUTPUT_TOKENS_ENV]
+          );
+          const outputTokenizer = new OutputTokenizer();
+          if (maxTokens !== undefined) {
+            void outputTokenizer.initialize();
+          }
+
+          const adapters = [
+            searchAdapter,
+            statusAdapter,
+            planAdapterWithGit,
+            exploreAdapter,
+            githubAdapter,
+            healthAdapter,
+            refsAdapter,
+            mapAdapter,
+            historyAdapter,
+            diffAdapter,
+            lookupAdapter,
+            similarAdapter,
+            contextAdapter,
+            usageAdapter,
+            testAdapter,
+            outlineAdapter,
+            implAdapter,
+            changelogAdapter,
+            whereisAdapter,
+            routesAdapter,
+            graphAdapter,
+            recursionAdapter,
+            neighborsAdapter,
+            callPathAdapter,
+            sqlAdapter,
+            openApiAdapter,
+            constraintsAdapter,
+            depsAdapter,
+            feedbackAdapter,
+            explainAdapter,
+            reindexAdapter,
+          ];
+
+          // Create MCP server with all adapters
           const server = new MCPServer({
             serverInfo: {
               name: 'dev-agent',
@@ -219,17 +442,9 @@ Available Tools (9):
               logLevel: logLevel as 'debug' | 'info' | 'warn' | 'error',
             },
             transport: options.transport === 'stdio' ? 'stdio' : undefined,
-            adapters: [
-              searchAdapter,
-              statusAdapter,
-              planAdapterWithGit,
-              exploreAdapter,
-              githubAdapter,
-              healthAdapter,
-              refsAdapter,
-              mapAdapter,
-              historyAdapter,
-            ],
+            maxTokens,
+            countTokens: (text) => outputTokenizer.countTokens(text),
+            adapters,
             coordinator,
           });
 
@@ -250,15 +465,27 @@ Available Tools (9):
           await server.start();
 
           logger.info(chalk.green('MCP server started successfully!'));
-          logger.info(
-            'Available tools: dev_search, dev_status, dev_plan, dev_inspect, dev_gh, dev_health, dev_refs, dev_map, dev_history'
-          );
+          const toolNames = adapters.map((adapter) => adapter.getToolDefinition().name);
+          logger.info(`Available tools: ${toolNames.join(', ')}`);
 
           if (options.transport === 'stdio') {
             logger.info('Server running on stdio transport (for AI tools)');
           } else {
             logger.info(`Server running on http://localhost:${options.port || 3000}`);
           }
+
+          // Report staleness without holding up startup
+          freshness
+            .check()
+            .then((report) => {
+              if (report.freshness) {
+                logger.info(formatFreshness(report.freshness));
+              }
+            })
+            .catch((error) => {
+              const message = error instanceof Error ? error.message : String(error);
+              logger.warn(`Index freshness check failed: ${message}`);
+            });
         } catch (error) {
           logger.error('Failed to start MCP server');
           logger.error(error instanceof Error ? error.message : String(error));
@@ -113,6 +113,7 @@ export const planCommand = new Command('plan')
           vectorStorePath: filePaths.vectors,
           statePath: filePaths.indexerState,
           excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+          ignorePatterns: config.repository?.ignorePatterns,
           languages: config.repository?.languages || config.languages,
         });
 
@@ -1,12 +1,16 @@
 import * as path from 'node:path';
 import {
+  DEFAULT_RERANK_CANDIDATES,
+  DEFAULT_RERANK_MODEL,
   ensureStorageDirectory,
   getStorageFilePaths,
   getStoragePath,
   RepositoryIndexer,
+  rerankResults,
+  TransformersReranker,
 } from '@lytics/dev-agent-core';
 import chalk from 'chalk';
-import { Command } from 'commander';
+import { Command, InvalidArgumentError } from 'commander';
 import ora from 'ora';
 import { loadConfig } from '../utils/config.js';
 import { logger } from '../utils/logger.js';
@@ -17,6 +21,19 @@ export const searchCommand = new Command('search')
   .argument('<query>', 'Search query')
   .option('-l, --limit <number>', 'Maximum number of results', '10')
   .option('-t, --threshold <number>', 'Minimum similarity score (0-1)', '0.7')
+  .option('--changed-since <date>', 'Only symbols changed since an ISO date (needs blame data)')
+  .option(
+    '--where <field=value>',
+    'Only symbols whose metadata field matches, e.g. team=payments (repeatable)',
+    collectFilter,
+    {}
+  )
+  .option('--include-generated', 'Also search generated files, e.g. mocks', false)
+  .option(
+    '--rerank [model]',
+    `Reorder the top ${DEFAULT_RERANK_CANDIDATES} matches with a cross-encoder ` +
+      `(default model: ${DEFAULT_RERANK_MODEL})`
+  )
   .option('--json', 'Output results as JSON', false)
   .option('-v, --verbose', 'Show detailed results with signatures and docs', false)
   .action(async (query: string, options) => {
@@ -47,6 +64,7 @@ export const searchCommand = new Command('search')
         vectorStorePath: filePaths.vectors,
         statePath: filePaths.indexerState,
         excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+        ignorePatterns: config.repository?.ignorePatterns,
         languages: config.repository?.languages || config.languages,
       });
 
@@ -54,13 +72,28 @@ export const searchCommand = new Command('search')
 
       spinner.text = `Searching for: ${chalk.cyan(query)}`;
 
-      const results = await indexer.search(query, {
-        limit: Number.parseInt(options.limit, 10),
+      const limit = Number.parseInt(options.limit, 10);
+      const reranker = options.rerank
+        ? new TransformersReranker(options.rerank === true ? undefined : options.rerank)
+        : undefined;
+      let results = await indexer.search(query, {
+        // The reranker picks from more candidates than are shown
+        limit: reranker ? Math.max(limit, DEFAULT_RERANK_CANDIDATES) : limit,
         scoreThreshold: Number.parseFloat(options.threshold),
+        changedSince: options.changedSince,
+        filter: Object.keys(options.where).length > 0 ? options.where : undefined,
+        excludeGenerated: !options.includeGenerated && options.where.generated === undefined,
       });
 
       await indexer.close();
 
+      if (reranker && results.length > 0) {
+        spinner.text = `Reranking with ${chalk.cyan(reranker.modelName)}...`;
+        await reranker.initialize();
+        results = await rerankResults(query, results, reranker);
+      }
+      results = results.slice(0, limit);
+
       spinner.stop();
 
       if (results.length === 0) {
@@ -91,3 +124,17 @@ export const searchCommand = new Command('search')
       process.exit(1);
     }
   });
+
+/**
+ * Add a `--where field=value` pair to the metadata filter; `true` and `false`
+ * match boolean fields, anything else matches as a string
+ */
+function collectFilter(pair: string, filter: Record<string, unknown>): Record<string, unknown> {
+  const separator = pair.indexOf('=');
+  if (separator <= 0) {
+    throw new InvalidArgumentError(`Expected field=value, got "${pair}"`);
+  }
+  const value = pair.slice(separator + 1);
+  const parsed = value === 'true' ? true : value === 'false' ? false : value;
+  return { ...filter, [pair.slice(0, separator)]: parsed };
+}
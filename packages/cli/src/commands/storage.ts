@@ -11,13 +11,14 @@ import {
   getStorageFilePaths,
   getStoragePath,
   loadMetadata,
+  RepositoryIndexer,
   type RepositoryMetadata,
   saveMetadata,
 } from '@lytics/dev-agent-core';
 import chalk from 'chalk';
 import { Command } from 'commander';
 import ora from 'ora';
-import { loadConfig } from '../utils/config.js';
+import { type DevAgentConfig, loadConfig } from '../utils/config.js';
 import { formatBytes, getDirectorySize } from '../utils/file.js';
 import { logger } from '../utils/logger.js';
 import { printStorageInfo } from '../utils/output.js';
@@ -93,6 +94,8 @@ const storageCommand = new Command('storage')
 Examples:
   $ dev storage info                    Show storage location and size
   $ dev storage migrate                 Migrate from old storage layout
+  $ dev storage export index.ndjson     Dump documents and vectors as NDJSON
+  $ dev storage import index.ndjson     Replace the index with a dump
 
 Storage Location:
   All indexed data is stored in ~/.dev-agent/indexes/
@@ -299,6 +302,97 @@ storageCommand
     }
   });
 
+/**
+ * Open the configured repository's indexer without loading the embedding model
+ */
+async function openIndexer(config: DevAgentConfig): Promise<RepositoryIndexer> {
+  const repositoryPath = config.repository?.path || config.repositoryPath || process.cwd();
+  const resolvedRepoPath = path.resolve(repositoryPath);
+  const storagePath = await getStoragePath(resolvedRepoPath);
+  await ensureStorageDirectory(storagePath);
+  const filePaths = getStorageFilePaths(storagePath);
+
+  const indexer = new RepositoryIndexer({
+    repositoryPath: resolvedRepoPath,
+    vectorStorePath: filePaths.vectors,
+    statePath: filePaths.indexerState,
+    embeddingModel: config.embeddingModel,
+    embeddingDimension: config.dimension,
+    similarityMetric: config.repository?.similarityMetric,
+    quantization: config.repository?.quantization,
+    truncateDimensions: config.repository?.truncateDimensions,
+  });
+  await indexer.initialize({ skipEmbedder: true });
+  return indexer;
+}
+
+/**
+ * Export command - Dump the index to NDJSON
+ */
+storageCommand
+  .command('export')
+  .description('Export documents and vectors to an NDJSON dump (one document per line)')
+  .argument('<file>', 'Dump file to write')
+  .action(async (file: string) => {
+    const spinner = ora('Exporting index...').start();
+
+    try {
+      const config = await loadConfig();
+      if (!config) {
+        spinner.fail('No config found');
+        logger.error('Run "dev init" first to initialize dev-agent');
+        process.exit(1);
+        return;
+      }
+
+      const indexer = await openIndexer(config);
+      try {
+        const documents = await indexer.exportIndex(path.resolve(file));
+        spinner.succeed(`Exported ${documents} documents to ${chalk.cyan(file)}`);
+      } finally {
+        await indexer.close();
+      }
+    } catch (error) {
+      spinner.fail('Export failed');
+      logger.error(error instanceof Error ? error.message : String(error));
+      process.exit(1);
+    }
+  });
+
+/**
+ * Import command - Replace the index with an NDJSON dump
+ */
+storageCommand
+  .command('import')
+  .description('Replace the index with an NDJSON dump from "dev storage export"')
+  .argument('<file>', 'Dump file to read')
+  .action(async (file: string) => {
+    const spinner = ora('Importing index...').start();
+
+    try {
+      const config = await loadConfig();
+      if (!config) {
+        spinner.fail('No config found');
+        logger.error('Run "dev init" first to initialize dev-agent');
+        process.exit(1);
+        return;
+      }
+
+      const indexer = await openIndexer(config);
+      try {
+        const documents = await indexer.importIndex(path.resolve(file));
+        spinner.succeed(`Imported ${documents} documents from ${chalk.cyan(file)}`);
+        logger.info('Run "dev update" to re-index files that changed since the export');
+      } finally {
+        await indexer.close();
+      }
+    } catch (error) {
+      spinner.fail('Import failed');
+      logger.error(error instanceof Error ? error.message : String(error));
+      process.exit(1);
+    }
+  });
+
 /**
  * Info command - Show storage information
  */
@@ -7,6 +7,8 @@ import {
   type IndexUpdatedEvent,
   MetricsStore,
   RepositoryIndexer,
+  RepositoryWatcher,
+  type WatchReindexedEvent,
 } from '@lytics/dev-agent-core';
 import chalk from 'chalk';
 import { Command } from 'commander';
@@ -19,6 +21,7 @@ import { ProgressRenderer } from '../utils/progress.js';
 export const updateCommand = new Command('update')
   .description('Update index with changed files')
   .option('-v, --verbose', 'Verbose output', false)
+  .option('-w, --watch', 'Keep running and re-index files as they change', false)
   .action(async (options) => {
     const spinner = ora('Checking for changes...').start();
 
@@ -70,13 +73,38 @@ export const updateCommand = new Command('update')
         }
       });
 
+      const excludePatterns = config.repository?.excludePatterns || config.excludePatterns;
+      const ignorePatterns = config.repository?.ignorePatterns;
+      const watchOptions = {
+        repositoryPath: resolvedRepoPath,
+        excludePatterns,
+        ignorePatterns,
+        verbose: options.verbose,
+      };
+
       const indexer = new RepositoryIndexer(
         {
           repositoryPath: resolvedRepoPath,
           vectorStorePath: filePaths.vectors,
           statePath: filePaths.indexerState,
-          excludePatterns: config.repository?.excludePatterns || config.excludePatterns,
+          excludePatterns,
+          ignorePatterns,
           languages: config.repository?.languages || config.languages,
+          embeddingMaxTokens: config.repository?.embeddingMaxTokens,
+          embeddingText: config.repository?.embeddingText,
+          maxDocumentBytes: config.repository?.maxDocumentBytes,
+          embeddingProfiles: config.repository?.embeddingProfiles,
+          embeddingProfile: config.repository?.embeddingProfile,
+          embeddingProfileBindings: config.repository?.embeddingProfileBindings,
+          similarityMetric: config.repository?.similarityMetric,
+          quantization: config.repository?.quantization,
+          truncateDimensions: config.repository?.truncateDimensions,
+          blame: config.repository?.blame,
+          goDiagnostics: config.repository?.goDiagnostics,
+          boilerplateDocThreshold: config.repository?.boilerplateDocThreshold,
+          symbolFilters: config.repository?.symbolFilters,
+          enrichers: config.repository?.enrichers,
+          targetPlatform: config.repository?.targetPlatform,
         },
         eventBus
       );
@@ -91,6 +119,9 @@ export const updateCommand = new Command('update')
 
       if (!updatePlan || updatePlan.total === 0) {
         output.success('No changes detected');
+        if (options.watch) {
+          await watchForChanges(indexer, eventBus, watchOptions);
+        }
         await indexer.close();
         metricsStore.close();
         return;
@@ -203,9 +234,6 @@ export const updateCommand = new Command('update')
         );
       }
 
-      await indexer.close();
-      metricsStore.close();
-
       const duration = (Date.now() - startTime) / 1000;
 
       // Finalize progress display
@@ -234,6 +262,12 @@ export const updateCommand = new Command('update')
       }
 
       output.log('');
+
+      if (options.watch) {
+        await watchForChanges(indexer, eventBus, watchOptions);
+      }
+      await indexer.close();
+      metricsStore.close();
     } catch (error) {
       spinner.fail('Failed to update index');
       logger.error(error instanceof Error ? error.message : String(error));
@@ -243,3 +277,46 @@ export const updateCommand = new Command('update')
       process.exit(1);
     }
   });
+
+/**
+ * Watch the repository and re-index changes until interrupted
+ */
+async function watchForChanges(
+  indexer: RepositoryIndexer,
+  eventBus: AsyncEventBus,
+  options: {
+    repositoryPath: string;
+    excludePatterns?: string[];
+    ignorePatterns?: string[];
+    verbose: boolean;
+  }
+): Promise<void> {
+  const watcher = new RepositoryWatcher({
+    repositoryPath: options.repositoryPath,
+    indexer,
+    eventBus,
+    excludePatterns: options.excludePatterns,
+    ignorePatterns: options.ignorePatterns,
+    logger: createIndexLogger(options.verbose),
+  });
+
+  eventBus.on<WatchReindexedEvent>('watch.reindexed', (event) => {
+    output.log(
+      chalk.dim(
+        `Re-indexed ${event.files.length} file(s), ${event.documentsIndexed} document(s) in ${event.duration}ms`
+      )
+    );
+  });
+
+  await watcher.start();
+  output.info('Watching for changes (Ctrl+C to stop)...');
+
+  await new Promise<void>((resolve) => {
+    process.once('SIGINT', () => resolve());
+    process.once('SIGTERM', () => resolve());
+  });
+
+  await watcher.stop();
+  output.log('');
+  output.success('Stopped watching');
+}
@@ -1,5 +1,14 @@
 import * as fs from 'node:fs/promises';
 import * as path from 'node:path';
+import type {
+  EmbeddingProfile,
+  EmbeddingProfileBinding,
+  EmbeddingTextMode,
+  GoPlatform,
+  QuantizationConfig,
+  SimilarityMetric,
+  SymbolFilters,
+} from '@lytics/dev-agent-core';
 import { logger } from './logger.js';
 
 /**
@@ -19,7 +28,39 @@ export interface DevAgentConfig {
   repository: {
     path?: string;
     excludePatterns?: string[];
+    /** Index-only ignore globs, layered on .gitignore (prefix with `!` to override defaults) */
+    ignorePatterns?: string[];
     languages?: string[];
+    /** Token budget per symbol's embedding text; bodies are trimmed to fit (default: 256) */
+    embeddingMaxTokens?: number;
+    /** Embed each symbol's source, or a card built from its metadata (default: source) */
+    embeddingText?: EmbeddingTextMode;
+    /** Largest document in bytes; bigger ones are chunked or summarized (default: 65536) */
+    maxDocumentBytes?: number;
+    /** Named embedding settings (embeddingText, embeddingMaxTokens, maxDocumentBytes) */
+    embeddingProfiles?: Record<string, EmbeddingProfile>;
+    /** Profile for files no binding matches (default: the settings above, `default`) */
+    embeddingProfile?: string;
+    /** Profiles by path glob, first match wins: `{ "paths": ["docs/**"], "profile": "docs" }` */
+    embeddingProfileBindings?: EmbeddingProfileBinding[];
+    /** Similarity metric for the vector index: cosine, dot, or euclidean (default: cosine) */
+    similarityMetric?: SimilarityMetric;
+    /** Vector quantization, e.g. `{ "scheme": "int8" }` for ~4x less memory (default: none) */
+    quantization?: QuantizationConfig;
+    /** Keep the first K dimensions of each vector, for Matryoshka-trained models (default: all) */
+    truncateDimensions?: number;
+    /** Record each symbol's last commit date and author from git blame (default: false) */
+    blame?: boolean;
+    /** Attach `go build` and `go vet` findings to Go symbols; needs a buildable checkout */
+    goDiagnostics?: boolean;
+    /** Symbols sharing a doc comment before it's left out of embedding text (default: 10) */
+    boilerplateDocThreshold?: number;
+    /** Symbol kinds and visibilities to leave out at index time, by language (default: none) */
+    symbolFilters?: SymbolFilters;
+    /** Modules (relative to the repository) whose default export adds custom metadata fields */
+    enrichers?: string[];
+    /** Go platform picking which variant of a per-platform symbol is indexed (default: host) */
+    targetPlatform?: Partial<GoPlatform>;
   };
   mcp?: {
     adapters?: Record<string, AdapterConfig>;
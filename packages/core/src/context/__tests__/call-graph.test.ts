@@ -0,0 +1,232 @@
+import { describe, expect, it } from 'vitest';
+import type { CalleeInfo } from '../../scanner/types';
+import type { SearchResult } from '../../vector/types';
+import { buildCallGraph, formatCallGraph } from '../call-graph';
+import type { CallGraph } from '../types';
+
+function symbol(
+  name: string,
+  type: string,
+  file: string,
+  snippet: string,
+  callees: CalleeInfo[] = []
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type,
+      path: file,
+      language: 'go',
+      startLine: 1,
+      endLine: snippet.split('\n').length,
+      signature: snippet.split('\n')[0].replace(/ \{$/, ''),
+      snippet,
+      callees,
+    },
+  };
+}
+
+describe('buildCallGraph', () => {
+  const docs: SearchResult[] = [
+    symbol(
+      'Checkout',
+      'function',
+      'shop/checkout.go',
+      'func Checkout(cart *Cart) error {\n\tfmt.Println("checkout")\n\treturn Total(cart)\n}',
+      [
+        { name: 'fmt.Println', line: 2 },
+        { name: 'Total', line: 3 },
+      ]
+    ),
+    symbol('Total', 'function', 'shop/cart.go', 'func Total(cart *Cart) error {\n\treturn nil\n}'),
+    symbol('Handle', 'function', 'api/handler.go', 'func Handle() {\n\tshop.Checkout(nil)\n}', [
+      { name: 'shop.Checkout', line: 2 },
+    ]),
+    symbol('TestCheckout', 'function', 'shop/checkout_test.go', 'func TestCheckout() {}', [
+      { name: 'Checkout', line: 2 },
+    ]),
+    symbol(
+      'Store',
+      'interface',
+      'shop/store.go',
+      'type Store interface {\n\tSave(o *Order) error\n}'
+    ),
+    symbol('Memory', 'class', 'shop/memory.go', 'type Memory struct {\n\torders []*Order\n}'),
+    symbol(
+      'Memory.Save',
+      'method',
+      'shop/memory.go',
+      'func (m *Memory) Save(o *Order) error {\n\treturn nil\n}'
+    ),
+  ];
+  const labels = (graph: CallGraph | null) => graph?.nodes.map((node) => node.label);
+
+  it('should return null for an unknown symbol or package', () => {
+    expect(buildCallGraph(docs, { symbol: 'Missing' })).toBeNull();
+    expect(buildCallGraph(docs, { package: 'billing' })).toBeNull();
+  });
+
+  it('should follow callers and callees one hop from a symbol', () => {
+    const graph = buildCallGraph(docs, { symbol: 'Checkout' });
+
+    expect(graph?.scope).toBe('shop.Checkout');
+    expect(labels(graph)).toEqual(['shop.Checkout', 'shop.Total', 'api.Handle']);
+    expect(graph?.edges).toEqual([
+      { from: 'shop/checkout.go:Checkout', to: 'shop/cart.go:Total', kind: 'call' },
+      { from: 'api/handler.go:Handle', to: 'shop/checkout.go:Checkout', kind: 'call' },
+    ]);
+    expect(graph?.omitted).toBe(0);
+  });
+
+  it('should follow one direction and the requested number of hops', () => {
+    expect(labels(buildCallGraph(docs, { symbol: 'Handle', direction: 'callees' }))).toEqual([
+      'api.Handle',
+      'shop.Checkout',
+    ]);
+    expect(
+      labels(buildCallGraph(docs, { symbol: 'Handle', direction: 'callees', depth: 2 }))
+    ).toEqual(['api.Handle', 'shop.Checkout', 'shop.Total']);
+  });
+
+  it('should add external calls only on request', () => {
+    const graph = buildCallGraph(docs, {
+      symbol: 'Checkout',
+      direction: 'callees',
+      includeExternal: true,
+    });
+
+    expect(graph?.nodes.at(-1)).toEqual({
+      id: 'external:fmt.Println',
+      label: 'fmt.Println',
+      kind: 'function',
+      external: true,
+    });
+    expect(graph?.edges).toContainEqual({
+      from: 'shop/checkout.go:Checkout',
+      to: 'external:fmt.Println',
+      kind: 'call',
+    });
+  });
+
+  it('should link a package with call and implements edges', () => {
+    const graph = buildCallGraph(docs, { package: 'shop', depth: 0 });
+
+    expect(graph?.scope).toBe('shop');
+    expect(labels(graph)).toEqual([
+      'shop.Checkout',
+      'shop.Total',
+      'shop.Store',
+      'shop.Memory',
+      'shop.Memory.Save',
+    ]);
+    expect(graph?.edges).toEqual([
+      { from: 'shop/checkout.go:Checkout', to: 'shop/cart.go:Total', kind: 'call' },
+      { from: 'shop/memory.go:Memory', to: 'shop/store.go:Store', kind: 'implements' },
+    ]);
+  });
+
+  it('should mark call edges that only hold after a type check as guarded', () => {
+    const save = symbol('Save', 'function', 'shop/save.go', 'func Save(s Store) {}', [
+      { name: 'm.Save', line: 2, receiverType: 'Memory', guarded: true },
+    ]);
+    const graph = buildCallGraph([...docs, save], { symbol: 'Save', direction: 'callees' });
+
+    expect(graph?.edges).toEqual([
+      {
+        from: 'shop/save.go:Save',
+        to: 'shop/memory.go:Memory.Save',
+        kind: 'call',
+        guarded: true,
+      },
+    ]);
+    expect(formatCallGraph(graph as CallGraph)).toContain(
+      '"shop/save.go:Save" -> "shop/memory.go:Memory.Save" [style=dotted, label="guarded"];'
+    );
+  });
+
+  it('should include test callers on request', () => {
+    const graph = buildCallGraph(docs, { symbol: 'Checkout', includeTests: true });
+
+    expect(labels(graph)).toContain('shop.TestCheckout');
+  });
+
+  it('should count nodes past the limit as omitted', () => {
+    const graph = buildCallGraph(docs, { symbol: 'Checkout', limit: 2 });
+
+    expect(graph?.nodes).toHaveLength(2);
+    expect(graph?.omitted).toBe(1);
+  });
+});
+
+describe('formatCallGraph', () => {
+  const graph: CallGraph = {
+    scope: 'shop',
+    depth: 1,
+    nodes: [
+      {
+        id: 'shop/memory.go:Memory',
+        label: 'shop.Memory',
+        kind: 'class',
+        file: 'shop/memory.go',
+        line: 3,
+        external: false,
+      },
+      {
+        id: 'shop/store.go:Store',
+        label: 'shop.Store',
+        kind: 'interface',
+        file: 'shop/store.go',
+        line: 5,
+        external: false,
+      },
+      { id: 'external:fmt.Sprintf', label: 'fmt.Sprintf', kind: 'function', external: true },
+    ],
+    edges: [
+      { from: 'shop/memory.go:Memory', to: 'shop/store.go:Store', kind: 'implements' },
+      { from: 'shop/memory.go:Memory', to: 'external:fmt.Sprintf', kind: 'call' },
+    ],
+    omitted: 4,
+  };
+
+  it('should render Graphviz DOT with edge kinds and external nodes', () => {
+    const output = formatCallGraph(graph, 'dot');
+
+    expect(output).toContain('digraph "shop" {');
+    expect(output).toContain(
+      '"shop/memory.go:Memory" [label="shop.Memory", tooltip="shop/memory.go:3"];'
+    );
+    expect(output).toContain(
+      '"shop/store.go:Store" [label="shop.Store", tooltip="shop/store.go:5", shape=ellipse];'
+    );
+    expect(output).toContain(
+      '"external:fmt.Sprintf" [label="fmt.Sprintf", style=dashed, color=gray50];'
+    );
+    expect(output).toContain(
+      '"shop/memory.go:Memory" -> "shop/store.go:Store" ' +
+        '[style=dashed, arrowhead=empty, label="implements"];'
+    );
+    expect(output).toContain('"shop/memory.go:Memory" -> "external:fmt.Sprintf";');
+    expect(output).toContain('// 4 more node(s) omitted');
+    expect(output.trimEnd().endsWith('}')).toBe(true);
+  });
+
+  it('should escape quotes in DOT strings', () => {
+    const output = formatCallGraph({
+      ...graph,
+      nodes: [{ id: 'a"b', label: 'say "hi"', kind: 'function', external: true }],
+      edges: [],
+    });
+
+    expect(output).toContain('"a\\"b" [label="say \\"hi\\""');
+  });
+
+  it('should render a JSON node and edge list', () => {
+    const parsed = JSON.parse(formatCallGraph(graph, 'json'));
+
+    expect(parsed.nodes).toHaveLength(3);
+    expect(parsed.edges[0]).toEqual(graph.edges[0]);
+    expect(parsed.omitted).toBe(4);
+  });
+});
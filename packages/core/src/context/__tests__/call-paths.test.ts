@@ -0,0 +1,98 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { buildCallPaths, formatCallPaths } from '../call-paths';
+import type { CallPaths } from '../types';
+
+/** A function whose calls are on lines 10, 11, ... of its snippet */
+function fn(name: string, file: string, calls: string[] = []): SearchResult {
+  const body = calls.map((call) => `\t${call}(ctx)`);
+  const lines = [`func ${name}(ctx context.Context) {`, ...body, '}'];
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type: 'function',
+      path: file,
+      language: 'go',
+      startLine: 9,
+      endLine: 10 + calls.length,
+      snippet: lines.join('\n'),
+      callees: calls.map((call, i) => ({ name: call, line: 10 + i })),
+    },
+  };
+}
+
+describe('buildCallPaths', () => {
+  const docs: SearchResult[] = [
+    fn('Handle', 'api/handler.go', ['shop.Checkout', 'audit.Record']),
+    fn('Checkout', 'shop/checkout.go', ['validate', 'store.Save']),
+    fn('validate', 'shop/checkout.go', ['store.Save']),
+    fn('Record', 'audit/audit.go', ['store.Save']),
+    fn('Save', 'store/store.go', ['encode']),
+    fn('encode', 'store/encode.go'),
+    fn('Seed', 'store/seed.go', ['fixture']),
+    fn('fixture', 'store/store_test.go', ['Save']),
+  ];
+  const route = (steps: CallPaths['paths'][number]) => [
+    ...steps.map((step) => step.caller.metadata.name),
+    steps.at(-1)?.callee.metadata.name,
+  ];
+
+  it('should find the shortest path with call-site snippets', () => {
+    const result = buildCallPaths(docs, 'Handle', 'encode');
+
+    expect(result.shortest).toBe(3);
+    expect(result.paths).toHaveLength(1);
+    const [path] = result.paths;
+    expect(route(path)).toEqual(['Handle', 'Checkout', 'Save', 'encode']);
+    expect(path.map((step) => step.line)).toEqual([10, 11, 10]);
+    expect(path[0].snippet).toBe('shop.Checkout(ctx)');
+  });
+
+  it('should return up to limit paths, shortest first', () => {
+    const result = buildCallPaths(docs, 'Handle', 'Save', { limit: 5 });
+
+    expect(result.paths.map(route)).toEqual([
+      ['Handle', 'Checkout', 'Save'],
+      ['Handle', 'Record', 'Save'],
+      ['Handle', 'Checkout', 'validate', 'Save'],
+    ]);
+  });
+
+  it('should report when there is no path, or only a longer one', () => {
+    const none = buildCallPaths(docs, 'encode', 'Handle');
+    expect(none.shortest).toBeNull();
+    expect(none.paths).toEqual([]);
+    expect(formatCallPaths(none)).toContain('No call path from store.encode to api.Handle');
+
+    const deep = buildCallPaths(docs, 'Handle', 'encode', { maxDepth: 2 });
+    expect(deep.shortest).toBe(3);
+    expect(deep.paths).toEqual([]);
+    expect(formatCallPaths(deep)).toContain('takes 3 calls, more than maxDepth (2)');
+  });
+
+  it('should only follow calls from test files when asked', () => {
+    expect(buildCallPaths(docs, 'Seed', 'encode').shortest).toBeNull();
+    expect(buildCallPaths(docs, 'Seed', 'encode', { includeTests: true }).shortest).toBe(3);
+  });
+
+  it('should leave unknown symbols null', () => {
+    const result = buildCallPaths(docs, 'Handle', 'Missing');
+
+    expect(result.from?.metadata.name).toBe('Handle');
+    expect(result.to).toBeNull();
+  });
+});
+
+describe('formatCallPaths', () => {
+  it('should list each call with its location and source line', () => {
+    const docs = [fn('Handle', 'api/handler.go', ['store.Save']), fn('Save', 'store/store.go')];
+    const output = formatCallPaths(buildCallPaths(docs, 'Handle', 'Save'));
+
+    expect(output).toContain('# Call path from api.Handle to store.Save');
+    expect(output).toContain('## Path 1 (1 call)');
+    expect(output).toContain('1. api.Handle → Save (api/handler.go:10)');
+    expect(output).toContain('   `store.Save(ctx)`');
+  });
+});
@@ -0,0 +1,118 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildConstraintUsages, formatConstraintUsages } from '../constraints';
+
+function symbol(
+  name: string,
+  file: string,
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: { name, type: 'function', path: file, language: 'go', startLine: 3, ...metadata },
+  };
+}
+
+describe('buildConstraintUsages', () => {
+  const docs: SearchResult[] = [
+    symbol('Integer', 'num/constraints.go', {
+      type: 'interface',
+      typeSet: { terms: ['~int', '~int64'], comparable: false },
+    }),
+    symbol('Ordered', 'num/constraints.go', {
+      type: 'interface',
+      startLine: 8,
+      typeSet: { terms: ['Integer', '~float64', '~string'], comparable: false },
+    }),
+    symbol('Key', 'num/constraints.go', {
+      type: 'interface',
+      startLine: 12,
+      typeSet: { terms: [], comparable: true },
+    }),
+    symbol('Stringer', 'num/format.go', { type: 'interface' }),
+    symbol('Max', 'num/max.go', {
+      signature: 'func Max[T Ordered](a, b T) T',
+      typeConstraints: ['Ordered'],
+    }),
+    symbol('Join', 'num/format.go', { typeConstraints: ['Stringer'] }),
+    symbol('Tree', 'tree/tree.go', { type: 'class', typeConstraints: ['num.Ordered'] }),
+    symbol('Sum', 'stats/sum.go', { typeConstraints: ['constraints.Integer', 'Ordered'] }),
+    symbol('TestMax', 'num/max_test.go', { typeConstraints: ['Ordered'] }),
+  ];
+  const users = (entry: { users: SearchResult[] } | undefined) =>
+    entry?.users.map((user) => user.metadata.name);
+
+  it('should classify constraints and link their users across packages', () => {
+    const { constraints, total } = buildConstraintUsages(docs);
+
+    expect(total).toBe(6);
+    const ordered = constraints.find((c) => c.definition?.id === 'num/constraints.go:Ordered');
+    expect(ordered).toMatchObject({ kind: 'constraint' });
+    expect(users(ordered)).toEqual(['Max', 'Tree']);
+    expect(constraints.find((c) => c.name === 'Stringer')?.kind).toBe('interface');
+    expect(constraints.find((c) => c.name === 'constraints.Integer')?.kind).toBe('external');
+    expect(constraints.find((c) => c.name === 'Key')?.users).toEqual([]);
+  });
+
+  it('should keep unqualified names in their own package', () => {
+    const { constraints } = buildConstraintUsages(docs, { name: 'Ordered' });
+
+    expect(constraints.map((c) => [c.name, c.kind])).toEqual([
+      ['Ordered', 'constraint'],
+      ['Ordered', 'external'],
+    ]);
+    expect(users(constraints[1])).toEqual(['Sum']);
+  });
+
+  it('should expand nested constraints into the underlying types', () => {
+    const [ordered] = buildConstraintUsages(docs, { name: 'Ordered[T]' }).constraints;
+
+    expect(ordered.underlying).toEqual(['~int', '~int64', '~float64', '~string']);
+  });
+
+  it('should include test files only when asked', () => {
+    const [ordered] = buildConstraintUsages(docs, { name: 'Ordered', includeTests: true })
+      .constraints;
+
+    expect(users(ordered)).toEqual(['TestMax', 'Max', 'Tree']);
+  });
+
+  it('should apply the limit', () => {
+    const result = buildConstraintUsages(docs, { limit: 2 });
+
+    expect(result.constraints).toHaveLength(2);
+    expect(result.omitted).toBe(4);
+  });
+});
+
+describe('formatConstraintUsages', () => {
+  it('should show the type set and users of each constraint', () => {
+    const definition = symbol('Ordered', 'num/constraints.go', {
+      type: 'interface',
+      typeSet: { terms: ['Integer', '~string'], comparable: false },
+    });
+    const output = formatConstraintUsages({
+      constraints: [
+        {
+          name: 'Ordered',
+          kind: 'constraint',
+          definition,
+          typeSet: { terms: ['Integer', '~string'], comparable: false },
+          underlying: ['~int', '~string'],
+          users: [symbol('Max', 'num/max.go', { signature: 'func Max[T Ordered](a, b T) T' })],
+        },
+        { name: 'constraints.Float', kind: 'external', users: [] },
+      ],
+      total: 2,
+      omitted: 0,
+    });
+
+    expect(output).toContain('## Ordered: constraint interface (num/constraints.go:3)');
+    expect(output).toContain('**Type set:** Integer | ~string');
+    expect(output).toContain('**Underlying types:** ~int | ~string');
+    expect(output).toContain('- Max (num/max.go:3): `func Max[T Ordered](a, b T) T`');
+    expect(output).toContain('## constraints.Float: not indexed');
+    expect(output).toContain('**Used by:** nothing indexed');
+  });
+});
@@ -0,0 +1,98 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { buildDefinitions, formatDefinitions } from '../definitions';
+import type { Definitions } from '../types';
+
+function symbol(name: string, file: string, startLine: number, type = 'function'): SearchResult {
+  const signature =
+    type === 'method'
+      ? `func (c *${name.split('.')[0]}) ${name.split('.')[1]}(key string) ([]byte, error)`
+      : `func ${name}(key string) ([]byte, error)`;
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: { name, type, path: file, language: 'go', startLine, signature },
+  };
+}
+
+describe('buildDefinitions', () => {
+  const docs: SearchResult[] = [
+    symbol('Get', 'internal/store/store.go', 12),
+    symbol('Get', 'internal/cache/get.go', 5),
+    symbol('Cache.Get', 'internal/cache/cache.go', 40, 'method'),
+    symbol('Getter', 'internal/store/store.go', 30),
+    symbol('Get', 'internal/store/store_test.go', 8),
+    {
+      id: 'docs/get.md:Get:1',
+      score: 1,
+      metadata: { name: 'Get', type: 'documentation', path: 'docs/get.md' },
+    },
+  ];
+
+  it('should return every package defining an exact name', () => {
+    const result = buildDefinitions(docs, 'Get');
+
+    expect(result?.definitions.map((d) => d.symbol.id)).toEqual([
+      'internal/cache/get.go:Get:5',
+      'internal/store/store.go:Get:12',
+    ]);
+    expect(result?.definitions.map((d) => d.package)).toEqual(['internal/cache', 'internal/store']);
+    expect(result?.omittedTests).toBe(1);
+  });
+
+  it('should disambiguate by package name or directory', () => {
+    expect(buildDefinitions(docs, 'store.Get')?.definitions).toHaveLength(1);
+    expect(buildDefinitions(docs, 'internal/store.Get')?.definitions[0].package).toBe(
+      'internal/store'
+    );
+    expect(buildDefinitions(docs, 'other.Get')).toBeNull();
+  });
+
+  it('should match methods by receiver-qualified name only', () => {
+    expect(buildDefinitions(docs, 'Cache.Get')?.definitions[0].symbol.metadata.type).toBe(
+      'method'
+    );
+    expect(buildDefinitions(docs, 'cache.Cache.Get')?.definitions).toHaveLength(1);
+    expect(buildDefinitions(docs, 'Get')?.definitions.map((d) => d.symbol.metadata.name)).toEqual([
+      'Get',
+      'Get',
+    ]);
+  });
+
+  it('should not match fuzzily', () => {
+    expect(buildDefinitions(docs, 'get')).toBeNull();
+    expect(buildDefinitions(docs, 'Gett')).toBeNull();
+  });
+
+  it('should include test definitions when asked', () => {
+    const result = buildDefinitions(docs, 'store.Get', { includeTests: true });
+
+    expect(result?.definitions.map((d) => d.isTest)).toEqual([false, true]);
+    expect(result?.omittedTests).toBe(0);
+  });
+});
+
+describe('formatDefinitions', () => {
+  const docs = [
+    symbol('Get', 'internal/store/store.go', 12),
+    symbol('Get', 'internal/cache/get.go', 5),
+    symbol('Get', 'internal/store/store_test.go', 8),
+  ];
+
+  it('should list locations and signatures with package disambiguation', () => {
+    const output = formatDefinitions(buildDefinitions(docs, 'Get') as Definitions);
+
+    expect(output).toContain('# Definitions of Get (2 in 2 packages)');
+    expect(output).toContain('## internal/store.Get - internal/store/store.go:12 (function)');
+    expect(output).toContain('`func Get(key string) ([]byte, error)`');
+    expect(output).toContain('1 more in test files.');
+  });
+
+  it('should explain when only tests define the name', () => {
+    const testOnly = [symbol('helper', 'internal/store/store_test.go', 3)];
+    const output = formatDefinitions(buildDefinitions(testOnly, 'helper') as Definitions);
+
+    expect(output).toContain('# Definitions of helper (0)');
+    expect(output).toContain('Only defined in test files (1)');
+  });
+});
@@ -0,0 +1,139 @@
+import { describe, expect, it } from 'vitest';
+import type { GoModule } from '../../scanner/go-modules';
+import type { SearchResult } from '../../vector/types';
+import { buildDependencies, formatDependencies } from '../dependencies';
+
+function symbol(name: string, file: string, imports: string[]): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: { name, type: 'function', path: file, language: 'go', imports },
+  };
+}
+
+describe('buildDependencies', () => {
+  const modules: GoModule[] = [
+    {
+      path: 'github.com/acme/api',
+      dir: '.',
+      dependencies: [
+        { path: 'github.com/gorilla/mux', version: 'v1.8.1', indirect: false },
+        {
+          path: 'golang.org/x/net',
+          version: 'v0.23.0',
+          indirect: true,
+          replacedBy: { path: 'golang.org/x/net', version: 'v0.23.0' },
+          requiredVersion: 'v0.17.0',
+        },
+        { path: 'golang.org/x/text', version: 'v0.14.0', indirect: true },
+      ],
+    },
+    {
+      path: 'github.com/acme/api/tools',
+      dir: 'tools',
+      dependencies: [{ path: 'golang.org/x/net', version: 'v0.20.0', indirect: false }],
+    },
+  ];
+  const docs: SearchResult[] = [
+    symbol('Routes', 'server/routes.go', ['net/http', 'github.com/gorilla/mux']),
+    symbol('Handle', 'server/routes.go', ['net/http', 'github.com/gorilla/mux']),
+    symbol('Dial', 'client/dial.go', ['golang.org/x/net/http2', 'golang.org/x/net/proxy']),
+    symbol('Serve', 'server/serve.go', ['golang.org/x/net/http2']),
+    symbol('TestRoutes', 'server/routes_test.go', ['github.com/gorilla/mux']),
+    symbol('Gen', 'tools/gen/main.go', ['golang.org/x/net/html']),
+  ];
+
+  it('should list every dependency with the packages importing it', () => {
+    const result = buildDependencies(modules, docs);
+
+    expect(result.total).toBe(4);
+    expect(result.modules).toBe(2);
+    expect(
+      result.dependencies.map((entry) => [entry.dependency.path, entry.moduleDir])
+    ).toEqual([
+      ['github.com/gorilla/mux', '.'],
+      ['golang.org/x/net', '.'],
+      ['golang.org/x/net', 'tools'],
+      ['golang.org/x/text', '.'],
+    ]);
+    expect(result.dependencies[0].importers).toEqual([
+      { dir: 'server', files: ['server/routes.go'] },
+    ]);
+    expect(result.dependencies[1].importers).toEqual([
+      { dir: 'client', files: ['client/dial.go'] },
+      { dir: 'server', files: ['server/serve.go'] },
+    ]);
+    // Resolved against the go.mod owning the importing file
+    expect(result.dependencies[2].importers).toEqual([
+      { dir: 'tools/gen', files: ['tools/gen/main.go'] },
+    ]);
+    expect(result.dependencies[3].importers).toEqual([]);
+  });
+
+  it('should filter by module path and leave out indirect dependencies on request', () => {
+    expect(
+      buildDependencies(modules, docs, { module: 'X/NET' }).dependencies.map((e) => e.moduleDir)
+    ).toEqual(['.', 'tools']);
+
+    const direct = buildDependencies(modules, docs, { includeIndirect: false });
+    expect(direct.dependencies.map((entry) => entry.dependency.path)).toEqual([
+      'github.com/gorilla/mux',
+      'golang.org/x/net',
+    ]);
+  });
+
+  it('should count test files only when asked', () => {
+    const result = buildDependencies(modules, docs, { module: 'mux', includeTests: true });
+
+    expect(result.dependencies[0].importers[0].files).toEqual([
+      'server/routes.go',
+      'server/routes_test.go',
+    ]);
+  });
+
+  it('should cap the number of dependencies', () => {
+    const result = buildDependencies(modules, docs, { limit: 1 });
+
+    expect(result.dependencies).toHaveLength(1);
+    expect(result.omitted).toBe(3);
+  });
+});
+
+describe('formatDependencies', () => {
+  it('should show versions, replacements, and importers', () => {
+    const modules: GoModule[] = [
+      {
+        path: 'github.com/acme/api',
+        dir: '.',
+        dependencies: [
+          {
+            path: 'golang.org/x/net',
+            version: 'v0.23.0',
+            indirect: true,
+            replacedBy: { path: 'golang.org/x/net', version: 'v0.23.0' },
+            requiredVersion: 'v0.17.0',
+          },
+          {
+            path: 'github.com/acme/lib',
+            version: 'v1.2.0',
+            indirect: false,
+            replacedBy: { path: '../lib' },
+          },
+        ],
+      },
+    ];
+    const output = formatDependencies(
+      buildDependencies(modules, [symbol('Dial', 'client/dial.go', ['golang.org/x/net/http2'])])
+    );
+
+    expect(output).toContain('# Go Dependencies (2)');
+    expect(output).toContain(
+      '## golang.org/x/net v0.23.0 (indirect; replaced by golang.org/x/net v0.23.0; ' +
+        'requires v0.17.0)'
+    );
+    expect(output).toContain('**Imported by (1):**\n- client: dial.go');
+    expect(output).toContain('## github.com/acme/lib v1.2.0 (direct; replaced by ../lib)');
+    expect(output).toContain('**Imported by:** no indexed code');
+    expect(output).not.toContain('Required by');
+  });
+});
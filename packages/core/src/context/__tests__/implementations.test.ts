@@ -0,0 +1,303 @@
+import { describe, expect, it } from 'vitest';
+import type { StructField } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildImplementations, formatImplementations } from '../implementations';
+import { normalizedMethodSignature } from '../method-sets';
+import type { InterfaceImplementations } from '../types';
+
+function doc(
+  name: string,
+  type: string,
+  file: string,
+  startLine: number,
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: {
+      name,
+      type,
+      path: file,
+      language: 'go',
+      startLine,
+      exported: /^[A-Z]/.test(name.split('.').pop() ?? ''),
+      ...metadata,
+    },
+  };
+}
+
+const method = (
+  receiver: string,
+  name: string,
+  file: string,
+  line: number,
+  pointer = false,
+  tail = '() error'
+) =>
+  doc(`${receiver}.${name}`, 'method', file, line, {
+    signature: `func (r ${pointer ? '*' : ''}${receiver}) ${name}${tail}`,
+  });
+
+const GET = '(key string) ([]byte, error)';
+
+const embedded = (type: string): StructField => ({
+  name: type.replace(/^\*/, ''),
+  type,
+  exported: true,
+  embedded: true,
+});
+
+describe('buildImplementations', () => {
+  const docs: SearchResult[] = [
+    doc('Store', 'interface', 'internal/store/store.go', 5, {
+      snippet: 'type Store interface {\n\tGet(key string) ([]byte, error)\n\tio.Closer\n}',
+    }),
+    doc('Memory', 'class', 'internal/store/memory.go', 3, { fields: [] }),
+    method('Memory', 'Get', 'internal/store/memory.go', 10, true, GET),
+    method('Memory', 'Close', 'internal/store/memory.go', 20, true),
+    doc('base', 'class', 'internal/disk/base.go', 3, { fields: [] }),
+    method('base', 'Close', 'internal/disk/base.go', 8),
+    doc('Disk', 'class', 'internal/disk/disk.go', 5, { fields: [embedded('base')] }),
+    method('Disk', 'Get', 'internal/disk/disk.go', 12, false, GET),
+    doc('_', 'variable', 'internal/disk/disk.go', 30, {
+      asserts: { interface: 'store.Store', type: 'Disk', pointer: false },
+    }),
+    doc('File', 'class', 'internal/files/file.go', 3, { fields: [] }),
+    method('File', 'Read', 'internal/files/file.go', 8, true),
+    doc('_', 'variable', 'internal/files/file.go', 20, {
+      asserts: { interface: 'io.Reader', type: 'File', pointer: true },
+    }),
+    doc('Fake', 'class', 'internal/store/fake_test.go', 3, { fields: [] }),
+    method('Fake', 'Get', 'internal/store/fake_test.go', 6, false, GET),
+    method('Fake', 'Close', 'internal/store/fake_test.go', 9),
+  ];
+
+  it('should combine structural matches with explicit assertions', () => {
+    const result = buildImplementations(docs, 'Store');
+
+    expect(result?.interface).toBe('store.Store');
+    expect(result?.methods).toEqual(['Get', 'Close']);
+    expect(result?.implementations.map((impl) => impl.type)).toEqual(['Disk', 'Memory']);
+
+    const disk = result?.implementations.find((impl) => impl.type === 'Disk');
+    expect(disk?.structural).toBe(true);
+    expect(disk?.assertion?.metadata.startLine).toBe(30);
+    expect(disk?.pointer).toBe(false);
+    // Close is promoted from the embedded base
+    expect(disk?.methods.map((m) => m.symbol.metadata.name)).toEqual(['Disk.Get', 'base.Close']);
+
+    const memory = result?.implementations.find((impl) => impl.type === 'Memory');
+    expect(memory).toMatchObject({ structural: true, pointer: true });
+    expect(memory?.assertion).toBeUndefined();
+  });
+
+  it('should record receiver kinds and how each method matched', () => {
+    const result = buildImplementations(docs, 'Store');
+
+    const memory = result?.implementations.find((impl) => impl.type === 'Memory');
+    expect(memory?.methods).toMatchObject([
+      { name: 'Get', receiver: 'pointer', pointerOnly: true, match: 'exact' },
+      // Close comes from the embedded io.Closer, known by name only
+      { name: 'Close', receiver: 'pointer', pointerOnly: true, match: 'name' },
+    ]);
+    expect(memory?.confidence).toBe('medium');
+
+    // Asserted implementations are checked by the compiler
+    const disk = result?.implementations.find((impl) => impl.type === 'Disk');
+    expect(disk?.confidence).toBe('high');
+    expect(disk?.methods.every((m) => m.receiver === 'value' && !m.pointerOnly)).toBe(true);
+  });
+
+  it('should include test types on request', () => {
+    const result = buildImplementations(docs, 'store.Store', { includeTests: true });
+
+    const fake = result?.implementations.find((impl) => impl.type === 'Fake');
+    expect(fake).toMatchObject({ isTest: true, structural: true });
+  });
+
+  it('should resolve well-known standard library interfaces', () => {
+    const result = buildImplementations(docs, 'io.Reader');
+
+    expect(result?.declaration).toBeUndefined();
+    expect(result?.methods).toEqual(['Read']);
+    expect(result?.implementations).toHaveLength(1);
+    expect(result?.implementations[0]).toMatchObject({
+      type: 'File',
+      package: 'internal/files',
+      structural: true,
+      pointer: true,
+    });
+    expect(result?.implementations[0].assertion).toBeDefined();
+  });
+
+  it('should list assertions of interfaces it cannot resolve', () => {
+    const result = buildImplementations(
+      [
+        doc('Plugin', 'class', 'plugins/plugin.go', 3, { fields: [] }),
+        doc('_', 'variable', 'plugins/plugin.go', 9, {
+          asserts: { interface: 'sdk.Plugin', type: 'Plugin', pointer: true },
+        }),
+      ],
+      'sdk.Plugin'
+    );
+
+    expect(result?.methodsKnown).toBe(false);
+    expect(result?.implementations.map((impl) => impl.type)).toEqual(['Plugin']);
+    expect(result?.implementations[0].structural).toBe(false);
+  });
+
+  it('should return null for unknown interfaces', () => {
+    expect(buildImplementations(docs, 'Missing')).toBeNull();
+  });
+});
+
+describe('buildImplementations across packages', () => {
+  const FIND = '(id domain.ID) (*domain.User, error)';
+  const docs: SearchResult[] = [
+    doc('Repository', 'interface', 'domain/repo.go', 5, {
+      snippet:
+        'type Repository interface {\n\tFind(id ID) (*User, error)\n\tSave(u *User) error\n}',
+    }),
+    doc('Base', 'class', 'sqlbase/base.go', 3, { fields: [] }),
+    method('Base', 'Save', 'sqlbase/base.go', 8, true, '(u *domain.User) error'),
+    // Save promoted through an embedded *Base: the value type implements
+    doc('UserStore', 'class', 'postgres/users.go', 5, { fields: [embedded('*sqlbase.Base')] }),
+    method('UserStore', 'Find', 'postgres/users.go', 9, false, FIND),
+    // Save promoted through an embedded Base value: only *Users implements
+    doc('Users', 'class', 'mysql/users.go', 5, { fields: [embedded('sqlbase.Base')] }),
+    method('Users', 'Find', 'mysql/users.go', 9, false, FIND),
+    doc('Users', 'class', 'memory/users.go', 5, { fields: [] }),
+    method('Users', 'Find', 'memory/users.go', 9, true, FIND),
+    doc('Users', 'class', 'legacy/users.go', 5, { fields: [embedded('*sqlbase.Base')] }),
+    method('Users', 'Find', 'legacy/users.go', 9, false, '(id string) (*domain.User, error)'),
+  ];
+
+  it('should match types in other packages by qualified signatures and promoted methods', () => {
+    const result = buildImplementations(docs, 'domain.Repository');
+
+    expect(result?.methods).toEqual(['Find', 'Save']);
+    expect(result?.implementations.map((impl) => [impl.package, impl.pointer])).toEqual([
+      ['mysql', true],
+      ['postgres', false],
+    ]);
+    const store = result?.implementations.find((impl) => impl.type === 'UserStore');
+    expect(store?.methods.map((m) => m.symbol.metadata.name)).toEqual([
+      'UserStore.Find',
+      'Base.Save',
+    ]);
+  });
+
+  it('should report types one method short as partial implementations', () => {
+    const result = buildImplementations(docs, 'Repository');
+
+    expect(result?.partial.map((impl) => impl.package)).toEqual(['legacy', 'memory']);
+    const memory = result?.partial.find((impl) => impl.package === 'memory');
+    expect(memory).toMatchObject({ type: 'Users', missing: 'Save', pointer: true });
+    expect(memory?.mismatch).toBeUndefined();
+
+    const legacy = result?.partial.find((impl) => impl.package === 'legacy');
+    expect(legacy?.missing).toBe('Find');
+    expect(legacy?.mismatch).toMatchObject({
+      expected: '(domain.ID) (*domain.User, error)',
+      actual: '(string) (*domain.User, error)',
+    });
+    expect(legacy?.methods.map((m) => m.name)).toEqual(['Save']);
+  });
+
+  it('should show partial implementations after full ones', () => {
+    const text = formatImplementations(
+      buildImplementations(docs, 'Repository') as InterfaceImplementations
+    );
+
+    expect(text).toContain('# Partial implementations (2, one method short)');
+    expect(text).toContain('## *Users (memory) - memory/users.go:5');
+    expect(text).toContain('- Save: missing');
+    expect(text).toContain(
+      '- Find: signature differs at legacy/users.go:9 ' +
+        '(has `(string) (*domain.User, error)`, needs `(domain.ID) (*domain.User, error)`)'
+    );
+  });
+});
+
+describe('normalizedMethodSignature', () => {
+  it('should drop parameter names, including grouped ones', () => {
+    const tail = '(dst, src []byte, n int) (int, error)';
+    const symbol = method('T', 'Copy', 'pkg/t.go', 1, false, tail);
+    expect(normalizedMethodSignature('Copy', symbol)).toBe('([]byte, []byte, int) (int, error)');
+  });
+
+  it("should qualify the declaring package's types, variadics included", () => {
+    const tail = '(ctx context.Context, opts ...Option)';
+    const symbol = method('T', 'Run', 'pkg/t.go', 1, false, tail);
+    expect(normalizedMethodSignature('Run', symbol)).toBe('(context.Context, ...pkg.Option) ()');
+  });
+
+  it('should return undefined for signatures cut short', () => {
+    const symbol = method('T', 'Set', 'pkg/t.go', 1, false, '(v interface');
+    expect(normalizedMethodSignature('Set', symbol)).toBeUndefined();
+  });
+});
+
+describe('formatImplementations', () => {
+  it('should show locations and which methods satisfy the interface', () => {
+    const result = buildImplementations(
+      [
+        doc('Stringer', 'interface', 'names/names.go', 3, {
+          snippet: 'type Stringer interface {\n\tString() string\n}',
+        }),
+        doc('Name', 'type', 'names/names.go', 8),
+        method('Name', 'String', 'names/names.go', 10, false, '() string'),
+      ],
+      'Stringer'
+    );
+    const text = formatImplementations(result as NonNullable<typeof result>);
+
+    expect(text).toContain('# Implementations of names.Stringer');
+    expect(text).toContain('Declared at names/names.go:3');
+    expect(text).toContain('Requires: String');
+    expect(text).toContain('## Name (names) - names/names.go:8');
+    expect(text).toContain('- confidence: high (every signature matches)');
+    expect(text).toContain('- String: Name.String (names/names.go:10), value receiver');
+    expect(text).toContain('- Name and *Name both implement names.Stringer');
+    expect(text).not.toContain('only *Name');
+  });
+
+  it('should flag types whose pointer alone implements the interface', () => {
+    const result = buildImplementations(
+      [
+        doc('Counter', 'interface', 'stats/stats.go', 3, {
+          snippet: 'type Counter interface {\n\tInc()\n\tValue() int\n}',
+        }),
+        doc('Hits', 'class', 'stats/hits.go', 3, { fields: [] }),
+        method('Hits', 'Inc', 'stats/hits.go', 8, true, '()'),
+        method('Hits', 'Value', 'stats/hits.go', 12, false, '() int'),
+      ],
+      'Counter'
+    );
+    const text = formatImplementations(result as NonNullable<typeof result>);
+
+    expect(text).toContain('## *Hits (stats) - stats/hits.go:3');
+    expect(text).toContain(
+      '- only *Hits implements stats.Counter: Inc has a pointer receiver, so a Hits value does not'
+    );
+    expect(text).toContain('- Inc: Hits.Inc (stats/hits.go:8), pointer receiver');
+    expect(text).toContain('- Value: Hits.Value (stats/hits.go:12), value receiver');
+    expect(text).not.toContain('both implement');
+  });
+
+  it('should lower confidence for methods matched by name only', () => {
+    const result = buildImplementations(
+      [
+        doc('File', 'class', 'files/file.go', 3, { fields: [] }),
+        method('File', 'Read', 'files/file.go', 8, false, '(p []byte) (int, error)'),
+      ],
+      'io.Reader'
+    );
+    const text = formatImplementations(result as NonNullable<typeof result>);
+
+    expect(result?.implementations[0].confidence).toBe('low');
+    expect(text).toContain('- confidence: low (methods matched by name only)');
+    expect(text).toContain('- Read: File.Read (files/file.go:8), value receiver, matched by name');
+  });
+});
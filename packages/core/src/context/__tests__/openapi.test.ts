@@ -0,0 +1,187 @@
+import { describe, expect, it } from 'vitest';
+import type { HttpRoute, OpenApiOperation } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildOpenApiOperations, formatOpenApiOperations } from '../openapi';
+
+function symbol(
+  name: string,
+  file: string,
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type: name.includes('.') ? 'method' : 'function',
+      path: file,
+      language: 'go',
+      startLine: 10,
+      ...metadata,
+    },
+  };
+}
+
+function route(method: string | undefined, path: string, handler: string): HttpRoute {
+  return { ...(method ? { method } : {}), path, handler, framework: 'chi', line: 12 };
+}
+
+function operation(
+  method: string,
+  path: string,
+  startLine: number,
+  fields: Partial<OpenApiOperation> = {},
+  file = 'api/openapi.yaml'
+): SearchResult {
+  return {
+    id: `${file}:${method} ${path}:${startLine}`,
+    score: 1,
+    metadata: {
+      name: `${method} ${path}`,
+      type: 'documentation',
+      path: file,
+      language: 'openapi',
+      startLine,
+      openApiOperation: { method, path, ...fields },
+    },
+  };
+}
+
+describe('buildOpenApiOperations', () => {
+  const v1 = { basePath: '/api/v1', tags: ['users'] };
+  const docs: SearchResult[] = [
+    symbol('Server.Routes', 'api/server.go', {
+      routes: [
+        route('POST', '/api/v1/users', 's.CreateUser'),
+        route('GET', '/api/v1/users', 's.ListUsers'),
+        route('DELETE', '/api/v1/users/{userID}', 's.RemoveUser'),
+        route('GET', '/profile', 's.GetProfile'),
+        route(undefined, '/healthz', 'healthz'),
+      ],
+    }),
+    symbol('Server.CreateUser', 'api/handlers.go'),
+    symbol('Server.ListUsers', 'api/handlers.go'),
+    symbol('Server.GetProfile', 'api/handlers.go'),
+    symbol('Register', 'orders/routes.go', {
+      routes: [route('GET', '/shop/orders', 'ordersIndex')],
+    }),
+    operation('GET', '/users', 10, { operationId: 'listUsers', ...v1 }),
+    operation('POST', '/users', 17, { operationId: 'createUser', summary: 'Create a user', ...v1 }),
+    operation('DELETE', '/users/{id}', 39, { operationId: 'deleteUser', ...v1 }),
+    operation('PATCH', '/users/{id}', 48, { operationId: 'updateUser', ...v1 }),
+    operation('GET', '/me', 60, { operationId: 'getProfile', basePath: '/api/v1' }),
+    operation('GET', '/orders', 6, { operationId: 'listOrders', basePath: '/v2' }, 'swagger.json'),
+  ];
+  const linked = (docs: SearchResult[], options = {}) =>
+    buildOpenApiOperations(docs, options).specs.flatMap((spec) =>
+      spec.operations.map((op) => {
+        const [match] = op.matches;
+        const handler = match?.route.handlerSymbol?.metadata.name ?? match?.route.handler;
+        return `${op.method} ${op.path}: ${match ? `${handler} (${match.confidence})` : 'none'}`;
+      })
+    );
+
+  it('should link operations to routes matching method and path, base path applied', () => {
+    expect(linked(docs)).toEqual([
+      'GET /users: Server.ListUsers (high)',
+      'POST /users: Server.CreateUser (high)',
+      'DELETE /users/{id}: s.RemoveUser (high)',
+      'PATCH /users/{id}: none',
+      'GET /me: Server.GetProfile (medium)',
+      'GET /orders: ordersIndex (medium)',
+    ]);
+
+    const [, createUser] = buildOpenApiOperations(docs).specs[0].operations;
+    expect(createUser.fullPath).toBe('/api/v1/users');
+    expect(createUser.matches[0].reason).toBe(
+      'method and path match; operationId matches the handler name'
+    );
+  });
+
+  it('should explain looser matches', () => {
+    const result = buildOpenApiOperations(docs);
+    const reasons = result.specs
+      .flatMap((spec) => spec.operations)
+      .filter((op) => op.path === '/me' || op.path === '/orders')
+      .map((op) => op.matches[0].reason);
+
+    expect(reasons).toEqual([
+      'method matches; operationId matches the handler name',
+      'method matches, path matches under a prefix',
+    ]);
+  });
+
+  it('should rate a route serving any method below one serving the operation method', () => {
+    const result = buildOpenApiOperations([
+      symbol('main', 'main.go', { routes: [route(undefined, '/users/:id', 'userHandler')] }),
+      operation('GET', '/users/{id}', 5),
+    ]);
+
+    expect(result.specs[0].operations[0].matches[0]).toMatchObject({
+      confidence: 'medium',
+      reason: 'path matches, route serves any method',
+    });
+  });
+
+  it('should list routes no operation describes', () => {
+    const result = buildOpenApiOperations(docs);
+
+    expect(result.total).toBe(6);
+    expect(result.linked).toBe(5);
+    expect(result.unspecifiedRoutes.map((r) => r.path)).toEqual(['/healthz']);
+  });
+
+  it('should filter by method, tag, path prefix, and unlinked operations', () => {
+    expect(linked(docs, { method: 'delete' })).toEqual(['DELETE /users/{id}: s.RemoveUser (high)']);
+    expect(linked(docs, { tag: 'users' })).toHaveLength(4);
+    expect(linked(docs, { pathPrefix: '/api/v1/users/' })).toHaveLength(2);
+    expect(linked(docs, { pathPrefix: '/orders' })).toEqual(['GET /orders: ordersIndex (medium)']);
+    expect(linked(docs, { unlinkedOnly: true })).toEqual(['PATCH /users/{id}: none']);
+  });
+
+  it('should count operations past the limit as omitted', () => {
+    const result = buildOpenApiOperations(docs, { limit: 2 });
+
+    expect(result.specs.flatMap((spec) => spec.operations)).toHaveLength(2);
+    expect(result.omitted).toBe(4);
+  });
+
+  it('should not report routes when no spec is indexed', () => {
+    const result = buildOpenApiOperations(docs.filter((doc) => !doc.metadata.openApiOperation));
+
+    expect(result).toEqual({ specs: [], total: 0, linked: 0, omitted: 0, unspecifiedRoutes: [] });
+  });
+});
+
+describe('formatOpenApiOperations', () => {
+  it('should list operations per spec with their handlers, then unspecified routes', () => {
+    const output = formatOpenApiOperations(
+      buildOpenApiOperations([
+        symbol('Server.Routes', 'api/server.go', {
+          routes: [
+            route('POST', '/api/v1/users', 's.CreateUser'),
+            route(undefined, '/healthz', 'healthz'),
+          ],
+        }),
+        symbol('Server.CreateUser', 'api/handlers.go', { startLine: 40 }),
+        operation('POST', '/users', 17, {
+          operationId: 'createUser',
+          summary: 'Create a user',
+          basePath: '/api/v1',
+        }),
+        operation('DELETE', '/users/{id}', 39, { basePath: '/api/v1' }),
+      ])
+    );
+
+    expect(output).toContain('# OpenAPI Operations (2 in 1 spec, 1 linked to handlers)');
+    expect(output).toContain('## api/openapi.yaml');
+    expect(output).toContain('- **POST /api/v1/users** createUser: Create a user (line 17)');
+    expect(output).toContain(
+      '  - high confidence: Server.CreateUser (api/handlers.go:40), POST /api/v1/users ' +
+        '(method and path match; operationId matches the handler name)'
+    );
+    expect(output).toContain('- **DELETE /api/v1/users/{id}** (line 39)\n  - no matching route');
+    expect(output).toContain('## Routes without a spec operation (1)');
+    expect(output).toContain('- **ANY /healthz** → `healthz` (not indexed)');
+  });
+});
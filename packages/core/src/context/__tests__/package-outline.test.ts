@@ -0,0 +1,142 @@
+import { describe, expect, it } from 'vitest';
+import type { StructField } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildPackageOutline, formatPackageOutline } from '../package-outline';
+
+function doc(
+  name: string,
+  type: string,
+  file: string,
+  startLine: number,
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: {
+      name,
+      type,
+      path: file,
+      language: 'go',
+      startLine,
+      exported: /^[A-Z]/.test(name.split('.').pop() ?? ''),
+      ...metadata,
+    },
+  };
+}
+
+const embedded = (type: string): StructField => ({
+  name: type.replace(/^\*/, ''),
+  type,
+  exported: true,
+  embedded: true,
+});
+
+describe('buildPackageOutline', () => {
+  const docs: SearchResult[] = [
+    doc('Doer', 'interface', 'internal/retry/retry.go', 5, {
+      snippet: 'type Doer interface {\n\tDo(req *Request) error // send once\n}',
+    }),
+    doc('Closer', 'interface', 'internal/retry/retry.go', 9, {
+      snippet: 'type Closer interface {\n\tClose() error\n}',
+    }),
+    doc('DoCloser', 'interface', 'internal/retry/retry.go', 13, {
+      snippet: 'type DoCloser interface {\n\tDoer\n\tCloser\n}',
+    }),
+    doc('base', 'class', 'internal/retry/base.go', 3, { fields: [] }),
+    doc('base.Close', 'method', 'internal/retry/base.go', 8),
+    doc('Client', 'class', 'internal/retry/client.go', 10, {
+      docstring: 'Client retries requests.\nIt is safe for concurrent use.',
+      fields: [embedded('*base'), { name: 'max', type: 'int', exported: false, embedded: false }],
+    }),
+    doc('Client.Do', 'method', 'internal/retry/client.go', 20),
+    doc('Client.reset', 'method', 'internal/retry/client.go', 30),
+    doc('NewClient', 'function', 'internal/retry/client.go', 40, {
+      signature: 'func NewClient(max int) (*Client, error)',
+      constructs: 'Client',
+      constructorConfidence: 'high',
+    }),
+    doc('Retry', 'function', 'internal/retry/client.go', 50, {
+      signature: 'func Retry(fn func() error) error',
+    }),
+    doc('helper', 'function', 'internal/retry/client.go', 60, {
+      signature: 'func helper()',
+    }),
+    doc('TestClient', 'function', 'internal/retry/client_test.go', 5),
+    doc('Other', 'class', 'internal/other/other.go', 1, { fields: [] }),
+  ];
+
+  it('should relate exported types by embedding, implementation, and construction', () => {
+    const outline = buildPackageOutline(docs, 'internal/retry');
+
+    expect(outline?.package).toBe('internal/retry');
+    const client = outline?.types.find((t) => t.symbol.metadata.name === 'Client');
+    expect(client).toMatchObject({
+      kind: 'struct',
+      embeds: ['base'],
+      // Close is promoted from the embedded base
+      implements: ['Doer', 'Closer', 'DoCloser'],
+      constructors: ['NewClient'],
+      methods: ['Close', 'Do'],
+    });
+    const doer = outline?.types.find((t) => t.symbol.metadata.name === 'Doer');
+    expect(doer?.implementedBy).toEqual(['Client']);
+    const doCloser = outline?.types.find((t) => t.symbol.metadata.name === 'DoCloser');
+    expect(doCloser?.embeds).toEqual(['Doer', 'Closer']);
+  });
+
+  it('should keep constructors out of the function list and hide unexported symbols', () => {
+    const outline = buildPackageOutline(docs, 'internal/retry');
+
+    expect(outline?.functions.map((f) => f.metadata.name)).toEqual(['Retry']);
+    expect(outline?.types.map((t) => t.symbol.metadata.name)).toEqual([
+      'Client',
+      'Closer',
+      'DoCloser',
+      'Doer',
+    ]);
+  });
+
+  it('should include unexported symbols on request', () => {
+    const outline = buildPackageOutline(docs, 'internal/retry', { includeUnexported: true });
+
+    expect(outline?.types.map((t) => t.symbol.metadata.name)).toContain('base');
+    expect(outline?.functions.map((f) => f.metadata.name)).toEqual(['helper', 'Retry']);
+  });
+
+  it('should keep the most connected types when capped', () => {
+    const outline = buildPackageOutline(docs, 'internal/retry', { limit: 1 });
+
+    expect(outline?.types.map((t) => t.symbol.metadata.name)).toEqual(['Client']);
+    expect(outline?.omittedTypes).toBe(3);
+  });
+
+  it('should resolve a trailing part of the package path', () => {
+    expect(buildPackageOutline(docs, 'retry')?.package).toBe('internal/retry');
+    expect(buildPackageOutline(docs, 'missing')).toBeNull();
+  });
+});
+
+describe('formatPackageOutline', () => {
+  it('should render a compact structural map', () => {
+    const outline = buildPackageOutline(
+      [
+        doc('Store', 'interface', 'cache/cache.go', 3, {
+          snippet: 'type Store interface {\n\tGet(key string) any\n}',
+        }),
+        doc('LRU', 'class', 'cache/lru.go', 5, { docstring: 'LRU evicts old entries.' }),
+        doc('LRU.Get', 'method', 'cache/lru.go', 12),
+        doc('NewLRU', 'function', 'cache/lru.go', 20, { constructs: 'LRU' }),
+      ],
+      'cache'
+    );
+    const text = formatPackageOutline(outline as NonNullable<typeof outline>);
+
+    expect(text).toContain('# Package cache');
+    expect(text).toContain('### LRU (struct) - lru.go:5\nLRU evicts old entries.');
+    expect(text).toContain('- implements: Store');
+    expect(text).toContain('- constructed by: NewLRU');
+    expect(text).toContain('- implemented by: LRU');
+    expect(text).not.toContain('## Functions');
+  });
+});
@@ -0,0 +1,123 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { buildRecursion, formatRecursion } from '../recursion';
+
+function fn(name: string, file: string, calls: string[] = []): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type: 'function',
+      path: file,
+      language: 'go',
+      startLine: 1,
+      callees: calls.map((call, i) => ({ name: call, line: 10 + i })),
+    },
+  };
+}
+
+describe('buildRecursion', () => {
+  const docs: SearchResult[] = [
+    fn('parseExpr', 'parser/expr.go', ['parseBinary']),
+    fn('parseBinary', 'parser/expr.go', ['parseUnary', 'fmt.Errorf']),
+    fn('parseUnary', 'parser/expr.go', ['parsePrimary', 'parseUnary']),
+    fn('parsePrimary', 'parser/expr.go', ['parseExpr', 'next']),
+    fn('next', 'parser/lexer.go'),
+    fn('walk', 'ast/walk.go', ['walk', 'walk']),
+    fn('Even', 'parity/parity.go', ['Odd']),
+    fn('Odd', 'parity/parity.go', ['Even']),
+    fn('fuzzParse', 'parser/expr_test.go', ['fuzzParse']),
+  ];
+  const names = (symbols: SearchResult[]) => symbols.map((symbol) => symbol.metadata.name);
+
+  it('should find mutual recursion groups and self-recursive functions', () => {
+    const report = buildRecursion(docs);
+
+    expect(report?.scope).toBe('.');
+    expect(report?.cycles.map((cycle) => cycle.kind)).toEqual(['mutual', 'self', 'mutual']);
+    const [parser, walk, parity] = report?.cycles ?? [];
+    expect(names(parser.symbols)).toEqual([
+      'parseExpr',
+      'parseBinary',
+      'parseUnary',
+      'parsePrimary',
+    ]);
+    expect(parser.path).toEqual([
+      'parser.parseExpr',
+      'parser.parseBinary',
+      'parser.parseUnary',
+      'parser.parsePrimary',
+      'parser.parseExpr',
+    ]);
+    // The self-call inside the group is one of its calls
+    expect(parser.calls.map((call) => `${call.caller.metadata.name}:${call.line}`)).toEqual([
+      'parseExpr:10',
+      'parseBinary:10',
+      'parseUnary:10',
+      'parseUnary:11',
+      'parsePrimary:10',
+    ]);
+    expect(walk.calls.map((call) => call.line)).toEqual([10, 11]);
+    expect(parity.path).toEqual(['parity.Even', 'parity.Odd', 'parity.Even']);
+  });
+
+  it('should only report cycles touching a package', () => {
+    const report = buildRecursion(docs, { package: 'parity' });
+
+    expect(report?.scope).toBe('parity');
+    expect(report?.cycles.map((cycle) => names(cycle.symbols))).toEqual([['Even', 'Odd']]);
+    expect(buildRecursion(docs, { package: 'missing' })).toBeNull();
+  });
+
+  it('should include test files only when asked', () => {
+    const withTests = buildRecursion(docs, { includeTests: true });
+
+    expect(withTests?.cycles.map((cycle) => cycle.symbols[0].metadata.name)).toContain(
+      'fuzzParse'
+    );
+  });
+
+  it('should cap the cycles at the limit', () => {
+    const report = buildRecursion(docs, { limit: 1 });
+
+    expect(report?.cycles).toHaveLength(1);
+    expect(report?.omitted).toBe(2);
+  });
+});
+
+describe('formatRecursion', () => {
+  const docs: SearchResult[] = [
+    fn('Even', 'parity/parity.go', ['Odd']),
+    fn('Odd', 'parity/parity.go', ['Even']),
+    fn('walk', 'ast/walk.go', ['walk']),
+  ];
+
+  it('should list mutual recursion, then self-recursive functions', () => {
+    const report = buildRecursion(docs);
+    if (!report) throw new Error('expected a report');
+
+    expect(formatRecursion(report)).toBe(
+      [
+        '# Recursion in the repository (2 cycles)',
+        '',
+        '## Mutual recursion',
+        '',
+        '- **parity.Even → parity.Odd → parity.Even** (2 functions)',
+        '  - parity.Even calls parity.Odd (parity/parity.go:10)',
+        '  - parity.Odd calls parity.Even (parity/parity.go:10)',
+        '',
+        '## Self-recursive',
+        '',
+        '- **ast.walk** calls itself (ast/walk.go:10)',
+        '',
+      ].join('\n')
+    );
+  });
+
+  it('should say when there is no recursion', () => {
+    expect(formatRecursion({ scope: 'ast', cycles: [], omitted: 0 })).toBe(
+      'No recursive functions found in ast.\n'
+    );
+  });
+});
@@ -0,0 +1,124 @@
+import { describe, expect, it } from 'vitest';
+import type { HttpRoute } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildRoutes, formatRoutes } from '../routes';
+
+function symbol(
+  name: string,
+  file: string,
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type: name.includes('.') ? 'method' : 'function',
+      path: file,
+      language: 'go',
+      startLine: 10,
+      ...metadata,
+    },
+  };
+}
+
+function route(path: string, handler?: string, method?: string, line = 12): HttpRoute {
+  return {
+    ...(method ? { method } : {}),
+    path,
+    ...(handler ? { handler } : {}),
+    framework: 'net/http',
+    line,
+  };
+}
+
+describe('buildRoutes', () => {
+  const docs: SearchResult[] = [
+    symbol('Server.Routes', 'users/server.go', {
+      routes: [
+        route('/users', 's.CreateUser', 'POST', 14),
+        route('/users', 's.ListUsers', 'GET', 13),
+        route('/healthz', undefined, undefined, 15),
+      ],
+    }),
+    symbol('Server.CreateUser', 'users/handlers.go', {
+      callees: [
+        { name: 'validate', line: 22 },
+        { name: 's.store.Insert', line: 23 },
+      ],
+    }),
+    symbol('Server.ListUsers', 'users/handlers.go'),
+    symbol('validate', 'users/validate.go'),
+    symbol('Store.Insert', 'users/store.go'),
+    symbol('Register', 'orders/routes.go', {
+      module: 'example.com/orders',
+      routes: [route('/orders/{id}', 'auth(getOrder)', 'DELETE')],
+    }),
+    symbol('getOrder', 'orders/get.go'),
+    symbol('TestRoutes', 'orders/routes_test.go', { routes: [route('/test-only', 'h')] }),
+  ];
+
+  it('should group routes by package and link handlers with their callees', () => {
+    const result = buildRoutes(docs);
+
+    expect(result.total).toBe(4);
+    expect(result.groups.map((g) => g.package)).toEqual(['orders', 'users']);
+    expect(result.groups[0].module).toBe('example.com/orders');
+
+    const users = result.groups[1].routes;
+    expect(users.map((r) => `${r.method ?? 'ANY'} ${r.path}`)).toEqual([
+      'ANY /healthz',
+      'GET /users',
+      'POST /users',
+    ]);
+    expect(users[2].handlerSymbol?.metadata.name).toBe('Server.CreateUser');
+    expect(users[2].callees.map((c) => c.metadata.name)).toEqual(['validate', 'Store.Insert']);
+    expect(users[0].handlerSymbol).toBeUndefined();
+  });
+
+  it('should see through middleware wrapping the handler', () => {
+    const [order] = buildRoutes(docs).groups[0].routes;
+
+    expect(order.handler).toBe('auth(getOrder)');
+    expect(order.handlerSymbol?.metadata.name).toBe('getOrder');
+  });
+
+  it('should filter by path prefix and method, keeping any-method routes', () => {
+    expect(buildRoutes(docs, { pathPrefix: '/orders' }).total).toBe(1);
+
+    const posts = buildRoutes(docs, { method: 'post' }).groups.flatMap((g) => g.routes);
+    expect(posts.map((r) => r.path)).toEqual(['/healthz', '/users']);
+  });
+
+  it('should include test routes on request', () => {
+    expect(buildRoutes(docs, { includeTests: true }).total).toBe(5);
+  });
+
+  it('should count routes past the limit as omitted', () => {
+    const result = buildRoutes(docs, { limit: 2 });
+
+    expect(result.total).toBe(4);
+    expect(result.omitted).toBe(2);
+    expect(result.groups.flatMap((g) => g.routes)).toHaveLength(2);
+  });
+});
+
+describe('formatRoutes', () => {
+  it('should list each route with its handler, registration, and callees', () => {
+    const docs = [
+      symbol('Routes', 'api/routes.go', {
+        routes: [route('/items', 'createItem', 'POST'), route('/ping', 'ping()')],
+      }),
+      symbol('createItem', 'api/items.go', { callees: [{ name: 'save', line: 11 }] }),
+      symbol('save', 'api/items.go', { startLine: 30 }),
+    ];
+    const output = formatRoutes(buildRoutes(docs));
+
+    expect(output).toContain('# HTTP Routes (2 in 1 package)');
+    expect(output).toContain('## api');
+    expect(output).toContain('- **POST /items** → createItem (api/items.go:10)');
+    expect(output).toContain('  - registered in Routes (api/routes.go:12, net/http)');
+    expect(output).toContain('  - calls save');
+    expect(output).toContain('- **ANY /ping** → `ping()` (not indexed)');
+  });
+});
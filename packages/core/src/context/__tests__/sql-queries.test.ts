@@ -0,0 +1,136 @@
+import { describe, expect, it } from 'vitest';
+import type { SqlQuery } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildSqlQueries, formatSqlQueries } from '../sql-queries';
+
+function symbol(
+  name: string,
+  file: string,
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type: name.includes('.') ? 'method' : 'function',
+      path: file,
+      language: 'go',
+      startLine: 10,
+      ...metadata,
+    },
+  };
+}
+
+function sql(query: string, tables: string[], line = 12): SqlQuery {
+  const operation = query.split(' ')[0].toLowerCase() as SqlQuery['operation'];
+  return { operation, query, tables, line };
+}
+
+describe('buildSqlQueries', () => {
+  const docs: SearchResult[] = [
+    symbol('UserStore.Get', 'store/users.go', {
+      sqlQueries: [sql('SELECT id FROM users WHERE id = $1', ['users'], 14)],
+    }),
+    symbol('UserStore.Rename', 'store/users.go', {
+      sqlQueries: [
+        sql('INSERT INTO audit_log (action) VALUES ($1)', ['audit_log'], 31),
+        sql('UPDATE users SET email = $1', ['users'], 30),
+      ],
+    }),
+    symbol('ListOrders', 'billing/orders.go', {
+      sqlQueries: [
+        sql('SELECT * FROM public.orders JOIN users u ON u.id = user_id', [
+          'public.orders',
+          'users',
+        ]),
+      ],
+    }),
+    symbol('Purge', 'store/purge.go', {
+      sqlQueries: [sql('DELETE FROM ? WHERE deleted', [])],
+    }),
+    symbol('TestGet', 'store/users_test.go', {
+      sqlQueries: [sql('INSERT INTO users (id) VALUES (1)', ['users'])],
+    }),
+    symbol('Helper', 'store/helper.go'),
+  ];
+  const described = (docs: SearchResult[], options = {}) =>
+    buildSqlQueries(docs, options).groups.flatMap((group) =>
+      group.queries.map((q) => `${q.runBy.metadata.name}:${q.line}`)
+    );
+
+  it('should group queries by package, then symbol and line', () => {
+    const queryMap = buildSqlQueries(docs);
+
+    expect(queryMap.groups.map((group) => group.package)).toEqual(['billing', 'store']);
+    expect(described(docs)).toEqual([
+      'ListOrders:12',
+      'Purge:12',
+      'UserStore.Get:14',
+      'UserStore.Rename:30',
+      'UserStore.Rename:31',
+    ]);
+    expect(queryMap.total).toBe(5);
+  });
+
+  it('should count the tables the queries name', () => {
+    expect(buildSqlQueries(docs).tables).toEqual([
+      { name: 'users', queries: 3 },
+      { name: 'audit_log', queries: 1 },
+      { name: 'public.orders', queries: 1 },
+    ]);
+  });
+
+  it('should find the functions querying a table, schema-qualified or not', () => {
+    expect(described(docs, { table: 'users' })).toEqual([
+      'ListOrders:12',
+      'UserStore.Get:14',
+      'UserStore.Rename:30',
+    ]);
+    expect(described(docs, { table: 'ORDERS' })).toEqual(['ListOrders:12']);
+  });
+
+  it('should filter by operation and path prefix', () => {
+    expect(described(docs, { operation: 'update' })).toEqual(['UserStore.Rename:30']);
+    expect(described(docs, { pathPrefix: 'billing/' })).toEqual(['ListOrders:12']);
+  });
+
+  it('should include test files on request', () => {
+    expect(described(docs, { table: 'users', includeTests: true })).toContain('TestGet:12');
+  });
+
+  it('should count queries past the limit as omitted', () => {
+    const queryMap = buildSqlQueries(docs, { limit: 2 });
+
+    expect(queryMap.groups.flatMap((group) => group.queries)).toHaveLength(2);
+    expect(queryMap.omitted).toBe(3);
+  });
+});
+
+describe('formatSqlQueries', () => {
+  it('should list tables, then queries with the symbol running them', () => {
+    const output = formatSqlQueries(
+      buildSqlQueries([
+        symbol('UserStore.Get', 'store/users.go', {
+          sqlQueries: [sql('SELECT id FROM users WHERE id = $1', ['users'], 14)],
+        }),
+        symbol('Purge', 'store/purge.go', {
+          sqlQueries: [sql('DELETE FROM ? WHERE deleted', [], 8)],
+        }),
+      ])
+    );
+
+    expect(output).toContain('# SQL Queries (2 in 1 package)');
+    expect(output).toContain('**Tables:** users (1)');
+    expect(output).toContain('## store');
+    expect(output).toContain('- **SELECT** users in UserStore.Get (store/users.go:14)');
+    expect(output).toContain('  - `SELECT id FROM users WHERE id = $1`');
+    expect(output).toContain('- **DELETE** unknown tables in Purge (store/purge.go:8)');
+  });
+
+  it('should note omitted queries', () => {
+    const output = formatSqlQueries({ groups: [], tables: [], total: 3, omitted: 3 });
+
+    expect(output).toContain('*3 more queries omitted; narrow with a table or path prefix*');
+  });
+});
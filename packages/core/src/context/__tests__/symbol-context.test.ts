@@ -0,0 +1,118 @@
+import { describe, expect, it } from 'vitest';
+import type { CalleeInfo } from '../../scanner/types';
+import type { SearchResult } from '../../vector/types';
+import { buildSymbolContext, formatSymbolContext } from '../symbol-context';
+
+function symbol(
+  name: string,
+  type: string,
+  file: string,
+  snippet: string,
+  callees: CalleeInfo[] = []
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type,
+      path: file,
+      language: 'go',
+      startLine: 1,
+      endLine: snippet.split('\n').length,
+      signature: snippet.split('\n')[0],
+      snippet,
+      callees,
+    },
+  };
+}
+
+describe('buildSymbolContext', () => {
+  const docs: SearchResult[] = [
+    symbol(
+      'Checkout',
+      'function',
+      'shop/checkout.go',
+      'func Checkout(cart *Cart) (*Order, error) {\n\ttotal := Total(cart)\n\treturn Charge(total)\n}',
+      [
+        { name: 'Total', line: 2 },
+        { name: 'Charge', line: 3, file: 'shop/pay.go' },
+      ]
+    ),
+    symbol('Total', 'function', 'shop/cart.go', 'func Total(cart *Cart) int {\n\treturn 0\n}', [
+      { name: 'round', line: 2 },
+    ]),
+    symbol('round', 'function', 'shop/cart.go', 'func round(v int) int {\n\treturn v\n}'),
+    symbol('Charge', 'function', 'shop/pay.go', 'func Charge(amount int) (*Order, error) {}'),
+    symbol('Handle', 'function', 'api/handler.go', 'func Handle() {\n\tshop.Checkout(nil)\n}', [
+      { name: 'shop.Checkout', line: 2 },
+    ]),
+    symbol('Cart', 'class', 'shop/cart.go', 'type Cart struct {\n\tItems []Item\n}'),
+    symbol('Order', 'class', 'shop/order.go', 'type Order struct {\n\tID string\n}'),
+    { id: 'README.md:Checkout', score: 1, metadata: { name: 'Checkout', type: 'documentation' } },
+  ];
+
+  it('should return null for an unknown symbol', () => {
+    expect(buildSymbolContext(docs, 'Missing')).toBeNull();
+  });
+
+  it('should collect direct callees, callers, and referenced types', () => {
+    const context = buildSymbolContext(docs, 'Checkout');
+
+    expect(context?.target.symbol.metadata.path).toBe('shop/checkout.go');
+    expect(context?.entries.map((e) => [e.relation, e.symbol.metadata.name])).toEqual([
+      ['callee', 'Total'],
+      ['callee', 'Charge'],
+      ['caller', 'Handle'],
+      ['type', 'Cart'],
+      ['type', 'Order'],
+    ]);
+    expect(context?.entries.every((e) => e.hop === 1 && e.via === 'Checkout')).toBe(true);
+  });
+
+  it('should expand to further hops without repeating symbols', () => {
+    const context = buildSymbolContext(docs, 'Checkout', { depth: 2 });
+    const hop2 = context?.entries.filter((e) => e.hop === 2);
+
+    expect(hop2?.map((e) => [e.relation, e.symbol.metadata.name, e.via])).toEqual([
+      ['callee', 'round', 'Total'],
+    ]);
+    const names = context?.entries.map((e) => e.symbol.metadata.name) ?? [];
+    expect(new Set(names).size).toBe(names.length);
+  });
+
+  it('should skip referenced types when disabled', () => {
+    const context = buildSymbolContext(docs, 'Checkout', { includeTypes: false });
+    expect(context?.entries.some((e) => e.relation === 'type')).toBe(false);
+  });
+
+  it('should fall back to signatures and omit symbols past the token budget', () => {
+    const long = `func Total(cart *Cart) int {\n${'\tx := 1\n'.repeat(200)}}`;
+    const withLongTotal = docs.map((d) =>
+      d.metadata.name === 'Total' ? symbol('Total', 'function', 'shop/cart.go', long) : d
+    );
+
+    const context = buildSymbolContext(withLongTotal, 'Checkout', { maxTokens: 60 });
+
+    expect(context?.target.signatureOnly).toBe(false);
+    const total = context?.entries.find((e) => e.symbol.metadata.name === 'Total');
+    expect(total?.signatureOnly).toBe(true);
+    expect(total?.source).toBe('func Total(cart *Cart) int {');
+    expect(context?.omitted).toBeGreaterThan(0);
+    expect(context?.tokens).toBeLessThanOrEqual(60);
+  });
+
+  it('should format entries grouped by hop and relationship', () => {
+    const context = buildSymbolContext(docs, 'Checkout', { depth: 2 });
+    const output = formatSymbolContext(context as NonNullable<typeof context>);
+
+    expect(output).toContain('# Context for Checkout');
+    expect(output).toContain('## Checkout (function) - shop/checkout.go:1-4');
+    expect(output.indexOf('## Hop 1: Callees')).toBeLessThan(output.indexOf('## Hop 1: Callers'));
+    expect(output.indexOf('## Hop 1: Callers')).toBeLessThan(
+      output.indexOf('## Hop 1: Referenced Types')
+    );
+    expect(output).toContain('### round (function) - shop/cart.go:1-3 via `Total`');
+    expect(output).toContain('```go\ntype Cart struct');
+  });
+});
@@ -0,0 +1,138 @@
+import { describe, expect, it } from 'vitest';
+import type { CalleeInfo } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildSymbolExplanation, formatSymbolExplanation } from '../symbol-explanation';
+
+function symbol(
+  name: string,
+  file: string,
+  startLine: number,
+  snippet: string,
+  callees: CalleeInfo[] = [],
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: {
+      name,
+      type: 'function',
+      path: file,
+      language: 'go',
+      startLine,
+      endLine: startLine + snippet.split('\n').length - 1,
+      snippet,
+      callees,
+      ...metadata,
+    },
+  };
+}
+
+const call = (line: number): CalleeInfo => ({ name: 'backoff.NewExpBackoff', line });
+
+describe('buildSymbolExplanation', () => {
+  const docs: SearchResult[] = [
+    symbol(
+      'NewExpBackoff',
+      'backoff/backoff.go',
+      10,
+      [
+        'func NewExpBackoff(base time.Duration) *ExpBackoff {',
+        '\treturn &ExpBackoff{base: clamp(base)}',
+        '}',
+      ].join('\n'),
+      [{ name: 'clamp', line: 11 }],
+      {
+        signature: 'func NewExpBackoff(base time.Duration) *ExpBackoff',
+        docstring: 'NewExpBackoff returns a backoff doubling from base.',
+      }
+    ),
+    symbol('clamp', 'backoff/backoff.go', 20, 'func clamp(d time.Duration) time.Duration {}', [], {
+      docstring: 'clamp bounds a delay. Zero becomes the minimum.',
+    }),
+    symbol(
+      'NewClient',
+      'client/client.go',
+      20,
+      [
+        'func NewClient(cfg Config) *Client {',
+        '\tb := backoff.NewExpBackoff(cfg.Base)',
+        '\treturn nil',
+        '}',
+      ].join('\n'),
+      [call(21)]
+    ),
+    symbol(
+      'newPoller',
+      'poller/poller.go',
+      5,
+      'func newPoller() *Poller {\n\treturn &Poller{b: backoff.NewExpBackoff(time.Second)}\n}',
+      [call(6)]
+    ),
+    symbol(
+      'TestNewExpBackoff',
+      'backoff/backoff_test.go',
+      3,
+      'func TestNewExpBackoff(t *testing.T) {\n\t_ = NewExpBackoff(time.Second)\n}',
+      [{ name: 'NewExpBackoff', line: 4 }]
+    ),
+  ];
+  const example = symbol('ExampleNewExpBackoff', 'backoff/example_test.go', 30, 'func Ex() {}');
+  example.metadata.example = {
+    target: 'NewExpBackoff',
+    code: 'b := backoff.NewExpBackoff(time.Second)',
+    output: '1s',
+  };
+  const names = (explanation: ReturnType<typeof buildSymbolExplanation>) =>
+    explanation?.examples.map((e) => e.caller.metadata.name);
+
+  it('should return null for an unknown symbol', () => {
+    expect(buildSymbolExplanation(docs, 'Missing')).toBeNull();
+  });
+
+  it('should fill the example slots with call sites, non-test first', () => {
+    const explanation = buildSymbolExplanation(docs, 'NewExpBackoff');
+
+    expect(explanation?.documentedExamples).toEqual([]);
+    expect(names(explanation)).toEqual(['NewClient', 'newPoller']);
+    expect(explanation?.totalCalls).toBe(3);
+    expect(names(buildSymbolExplanation(docs, 'NewExpBackoff', { examples: 3 }))).toEqual([
+      'NewClient',
+      'newPoller',
+      'TestNewExpBackoff',
+    ]);
+  });
+
+  it('should give the first slot to a Go Example function', () => {
+    const explanation = buildSymbolExplanation([...docs, example], 'NewExpBackoff');
+
+    expect(explanation?.documentedExamples.map((d) => d.metadata.name)).toEqual([
+      'ExampleNewExpBackoff',
+    ]);
+    expect(names(explanation)).toEqual(['NewClient']);
+  });
+
+  it('should list direct callees up to the limit', () => {
+    expect(buildSymbolExplanation(docs, 'NewExpBackoff')?.callees[0]?.metadata.name).toBe('clamp');
+
+    const limited = buildSymbolExplanation(docs, 'NewExpBackoff', { maxCallees: 0 });
+    expect(limited?.callees).toEqual([]);
+    expect(limited?.omittedCallees).toBe(1);
+  });
+
+  it('should format doc, signature, examples, and callee summaries', () => {
+    const explanation = buildSymbolExplanation([...docs, example], 'NewExpBackoff');
+    const output = formatSymbolExplanation(explanation as NonNullable<typeof explanation>);
+
+    expect(output).toContain('# NewExpBackoff (function) - backoff/backoff.go:10');
+    expect(output).toContain('```go\nfunc NewExpBackoff(base time.Duration) *ExpBackoff\n```');
+    expect(output).toContain('NewExpBackoff returns a backoff doubling from base.');
+    expect(output).toContain('## Example: ExampleNewExpBackoff - backoff/example_test.go:30');
+    expect(output).toContain('Output:\n\n```\n1s\n```');
+    expect(output).toContain('## Used in NewClient - client/client.go:21');
+    expect(output).toContain('Called from 3 place(s) in the index.');
+    expect(output).toContain('## Calls (1)');
+    expect(output).toContain('- clamp - backoff/backoff.go:20: clamp bounds a delay.\n');
+    expect(output.indexOf('## Example:')).toBeLessThan(output.indexOf('## Used in'));
+  });
+});
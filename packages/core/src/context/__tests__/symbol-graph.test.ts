@@ -0,0 +1,229 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { SymbolGraph, SymbolGraphCache } from '../symbol-graph';
+
+function fn(
+  name: string,
+  file: string,
+  startLine: number,
+  calls: string[] = [],
+  signature = `func ${name}()`
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: {
+      name,
+      type: 'function',
+      path: file,
+      language: 'go',
+      startLine,
+      signature,
+      callees: calls.map((call, i) => ({ name: call, line: startLine + i + 1 })),
+    },
+  };
+}
+
+/**
+ * Every edge, from both directions, so a patched graph can be compared with a rebuilt one
+ */
+function edges(graph: SymbolGraph, symbols: SearchResult[]): string[] {
+  return symbols.flatMap((symbol) => [
+    ...graph.calleesOf(symbol).map((callee) => `${symbol.id} -> ${callee.id}`),
+    ...graph.callersOf(symbol).map((caller) => `${symbol.id} <- ${caller.id}`),
+    ...graph.callSitesOf(symbol).map((site) => `${symbol.id} <@ ${site.call.line}`),
+  ]);
+}
+
+/**
+ * Apply a file change to a graph and check it against a full rebuild
+ */
+function expectPatchedToMatchRebuild(
+  before: SearchResult[],
+  files: string[],
+  replacements: SearchResult[]
+): SymbolGraph {
+  const after = [
+    ...before.filter((symbol) => !files.includes(symbol.metadata.path ?? '')),
+    ...replacements,
+  ];
+  const graph = new SymbolGraph(before);
+  graph.update(files, replacements);
+
+  expect(edges(graph, after).sort()).toEqual(edges(new SymbolGraph(after), after).sort());
+  return graph;
+}
+
+describe('SymbolGraph internal visibility', () => {
+  const open = fn('Open', 'svc/internal/db/db.go', 3);
+  const load = fn('Load', 'svc/api/load.go', 3, ['db.Open']);
+  const leak = fn('Leak', 'web/leak.go', 3, ['db.Open']);
+
+  it('should resolve calls from within the internal tree', () => {
+    const graph = new SymbolGraph([open, load, leak]);
+
+    expect(graph.calleesOf(load)).toEqual([open]);
+    expect(graph.callersOf(open)).toEqual([load]);
+  });
+
+  it('should not resolve calls across an internal boundary, and report them', () => {
+    const graph = new SymbolGraph([open, load, leak]);
+
+    expect(graph.calleesOf(leak)).toEqual([]);
+    expect(graph.internalViolations(leak)).toEqual([
+      { caller: leak, call: { name: 'db.Open', line: 4 }, target: open },
+    ]);
+    expect(graph.internalViolations()).toHaveLength(1);
+  });
+
+  it('should prefer a visible symbol over an internal one with the same name', () => {
+    const other = fn('Open', 'web/db/db.go', 3);
+    const graph = new SymbolGraph([open, other, leak]);
+
+    expect(graph.calleesOf(leak)).toEqual([other]);
+    expect(graph.internalViolations(leak)).toEqual([]);
+  });
+
+  it('should drop violations once the caller moves inside the tree', () => {
+    const graph = new SymbolGraph([open, leak]);
+    const moved = fn('Leak', 'svc/leak.go', 3, ['db.Open']);
+    graph.update(['web/leak.go', 'svc/leak.go'], [moved]);
+
+    expect(graph.internalViolations()).toEqual([]);
+    expect(graph.calleesOf(moved)).toEqual([open]);
+  });
+});
+
+describe('SymbolGraph.update', () => {
+  const retry = fn('Retry', 'retry/retry.go', 10, ['sleep']);
+  const sleep = fn('sleep', 'retry/retry.go', 30);
+  const clientDo = fn('Do', 'client/client.go', 5, ['Retry', 'RetryWithBackoff']);
+  const poll = fn('poll', 'poller/poller.go', 8, ['Retry']);
+  const base = [retry, sleep, clientDo, poll];
+
+  it('should re-extract calls out of a changed file', () => {
+    const graph = expectPatchedToMatchRebuild(
+      base,
+      ['poller/poller.go'],
+      [fn('poll', 'poller/poller.go', 8, ['Do'])]
+    );
+
+    expect(graph.callersOf(retry).map((s) => s.metadata.name)).toEqual(['Do']);
+    expect(graph.callersOf(clientDo).map((s) => s.metadata.name)).toEqual(['poll']);
+  });
+
+  it('should keep calls into symbols whose declaration did not change', () => {
+    const moved = fn('Retry', 'retry/retry.go', 10, []);
+    const graph = expectPatchedToMatchRebuild(base, ['retry/retry.go'], [moved, sleep]);
+
+    expect(graph.callersOf(moved).map((s) => s.metadata.name)).toEqual(['Do', 'poll']);
+    expect(graph.callersOf(sleep)).toEqual([]);
+  });
+
+  it('should re-resolve calls across a rename', () => {
+    const renamed = fn('RetryWithBackoff', 'retry/retry.go', 10, ['sleep']);
+    const graph = expectPatchedToMatchRebuild(base, ['retry/retry.go'], [renamed, sleep]);
+
+    // Do already called RetryWithBackoff, which now resolves; poll's call to Retry no longer does
+    expect(graph.callersOf(renamed).map((s) => s.metadata.name)).toEqual(['Do']);
+    expect(graph.calleesOf(poll)).toEqual([]);
+  });
+
+  it('should drop edges into a deleted file', () => {
+    const graph = expectPatchedToMatchRebuild(base, ['retry/retry.go'], []);
+
+    expect(graph.calleesOf(clientDo)).toEqual([]);
+    expect(graph.callersOf(retry)).toEqual([]);
+  });
+
+  it('should stop resolving calls that a new symbol makes ambiguous', () => {
+    const graph = expectPatchedToMatchRebuild(
+      base,
+      ['backoff/retry.go'],
+      [fn('Retry', 'backoff/retry.go', 3)]
+    );
+
+    expect(graph.calleesOf(poll)).toEqual([]);
+  });
+
+  it('should re-resolve callers when a signature changes', () => {
+    const changed = fn('Retry', 'retry/retry.go', 10, ['sleep'], 'func Retry(n int)');
+    const graph = expectPatchedToMatchRebuild(base, ['retry/retry.go'], [changed, sleep]);
+
+    expect(graph.callersOf(changed).map((s) => s.metadata.name)).toEqual(['Do', 'poll']);
+  });
+});
+
+describe('SymbolGraphCache', () => {
+  const retry = fn('Retry', 'retry/retry.go', 10);
+  const poll = fn('poll', 'poller/poller.go', 8, ['Retry']);
+
+  it('should reuse the graph while the index version is unchanged', () => {
+    const cache = new SymbolGraphCache();
+    const graph = cache.get([retry, poll], 'v1');
+
+    expect(cache.get([retry, poll], 'v1')).toBe(graph);
+  });
+
+  it('should patch the cached graph when files change', () => {
+    const cache = new SymbolGraphCache();
+    const graph = cache.get([retry, poll], 'v1');
+
+    const renamed = fn('Backoff', 'retry/retry.go', 10);
+    const symbols = [renamed, fn('poll', 'poller/poller.go', 8, ['Backoff'])];
+    const patched = cache.get(symbols, 'v2');
+
+    expect(patched).toBe(graph);
+    expect(edges(patched, symbols)).toEqual(edges(new SymbolGraph(symbols), symbols));
+    expect(patched.callersOf(retry)).toEqual([]);
+  });
+});
+
+describe('SymbolGraph type narrowing', () => {
+  const method = (name: string, file: string): SearchResult => {
+    const symbol = fn(name, file, 3);
+    return { ...symbol, metadata: { ...symbol.metadata, type: 'method' } };
+  };
+  const fileRead = method('FileReader.Read', 'reader/file.go');
+  const bufferRead = method('BufferReader.Read', 'reader/buffer.go');
+  const otherRead = method('FileReader.Read', 'other/file.go');
+
+  it('should resolve a narrowed receiver to the concrete type in the caller package', () => {
+    const narrowed = { name: 'f.Read', line: 12, receiverType: 'FileReader', guarded: true };
+    const drain = fn('Drain', 'reader/drain.go', 10);
+    drain.metadata.callees = [narrowed, { name: 'r.Read', line: 14 }];
+    const graph = new SymbolGraph([fileRead, bufferRead, otherRead, drain]);
+
+    expect(graph.calleesOf(drain)).toEqual([fileRead]);
+    expect(graph.outgoingCalls(drain)).toEqual([{ call: narrowed, target: fileRead }]);
+  });
+
+  it('should look up a qualified narrowed type in its package', () => {
+    const use = fn('Use', 'app/use.go', 10);
+    use.metadata.callees = [{ name: 'r.Read', line: 11, receiverType: 'other.FileReader' }];
+    const graph = new SymbolGraph([fileRead, bufferRead, otherRead, use]);
+
+    expect(graph.calleesOf(use)).toEqual([otherRead]);
+  });
+});
+
+describe('SymbolGraph type references', () => {
+  const type = (name: string, file: string): SearchResult => {
+    const symbol = fn(name, file, 3, [], `type ${name} struct`);
+    return { ...symbol, metadata: { ...symbol.metadata, type: 'class' } };
+  };
+  const config = type('Config', 'server/config.go');
+  const server = type('Server', 'server/server.go');
+  const otherConfig = type('Config', 'client/config.go');
+
+  it('should resolve recorded references in the package or the one they name', () => {
+    const newServer = fn('NewServer', 'server/server.go', 10);
+    newServer.metadata.referencesTypes = ['Config', 'Server'];
+    const run = fn('Run', 'cmd/main.go', 10);
+    run.metadata.referencesTypes = ['client.Config', 'http.Request'];
+    const graph = new SymbolGraph([config, server, otherConfig, newServer, run]);
+
+    expect(graph.typesReferencedBy(newServer)).toEqual([config, server]);
+    expect(graph.typesReferencedBy(run)).toEqual([otherConfig]);
+  });
+});
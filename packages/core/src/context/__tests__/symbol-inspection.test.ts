@@ -0,0 +1,164 @@
+import { describe, expect, it } from 'vitest';
+import type { CalleeInfo } from '../../scanner/types';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildSymbolInspection, formatSymbolInspection } from '../symbol-inspection';
+import type { SymbolInspection } from '../types';
+
+function symbol(
+  name: string,
+  type: string,
+  file: string,
+  snippet: string,
+  callees: CalleeInfo[] = [],
+  metadata: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: {
+      name,
+      type,
+      path: file,
+      language: 'go',
+      startLine: 1,
+      endLine: snippet.split('\n').length,
+      signature: snippet.split('\n')[0].replace(/ \{$/, ''),
+      snippet,
+      callees,
+      ...metadata,
+    },
+  };
+}
+
+describe('buildSymbolInspection', () => {
+  const docs: SearchResult[] = [
+    symbol(
+      'Checkout',
+      'function',
+      'shop/checkout.go',
+      'func Checkout(cart *Cart) error {\n\tstore.Save(Total(cart))\n\treturn nil\n}',
+      [{ name: 'Total', line: 2 }],
+      {
+        docstring: 'Checkout places an order for a cart.',
+        lastModified: '2026-03-02T10:00:00Z',
+        lastAuthor: 'Sam',
+      }
+    ),
+    symbol('Total', 'function', 'shop/cart.go', 'func Total(cart *Cart) *Order {\n\treturn nil\n}'),
+    symbol('Handle', 'function', 'api/handler.go', 'func Handle() {\n\tshop.Checkout(nil)\n}', [
+      { name: 'shop.Checkout', line: 2 },
+    ]),
+    symbol('TestCheckout', 'function', 'shop/checkout_test.go', 'func TestCheckout() {}', [
+      { name: 'Checkout', line: 2 },
+    ]),
+    symbol(
+      'Store',
+      'interface',
+      'shop/store.go',
+      'type Store interface {\n\tSave(o *Order) error\n}'
+    ),
+    symbol('Memory', 'class', 'shop/memory.go', 'type Memory struct {\n\torders []*Order\n}', [], {
+      fields: [],
+    }),
+    symbol(
+      'Memory.Save',
+      'method',
+      'shop/memory.go',
+      'func (m *Memory) Save(o *Order) error {\n\treturn nil\n}'
+    ),
+  ];
+
+  it('should return null for an unknown symbol', () => {
+    expect(buildSymbolInspection(docs, 'Missing')).toBeNull();
+  });
+
+  it('should gather the definition, callers, callees, and git info', () => {
+    const inspection = buildSymbolInspection(docs, 'Checkout');
+
+    expect(inspection?.package).toBe('shop');
+    expect(inspection?.source).toContain('store.Save(Total(cart))');
+    expect(inspection?.callers.map((s) => s.metadata.name)).toEqual(['Handle']);
+    expect(inspection?.callees.map((s) => s.metadata.name)).toEqual(['Total']);
+    expect(inspection?.git).toEqual({ lastModified: '2026-03-02T10:00:00Z', lastAuthor: 'Sam' });
+    expect(inspection?.omitted).toBe(0);
+  });
+
+  it('should include test callers on request', () => {
+    const inspection = buildSymbolInspection(docs, 'Checkout', { includeTests: true });
+
+    expect(inspection?.callers.map((s) => s.metadata.name)).toEqual(['Handle', 'TestCheckout']);
+  });
+
+  it('should link types and interfaces in both directions', () => {
+    const memory = buildSymbolInspection(docs, 'Memory');
+    expect(memory?.implements).toEqual([{ name: 'shop.Store', symbol: docs[4], pointer: true }]);
+
+    const store = buildSymbolInspection(docs, 'Store');
+    expect(store?.implementedBy.map(({ name, pointer }) => ({ name, pointer }))).toEqual([
+      { name: 'shop.Memory', pointer: true },
+    ]);
+  });
+
+  it('should only gather the requested sections', () => {
+    const inspection = buildSymbolInspection(docs, 'Checkout', { sections: ['git', 'callers'] });
+
+    expect(inspection?.sections).toEqual(['callers', 'git']);
+    expect(inspection?.source).toBe('');
+    expect(inspection?.callees).toEqual([]);
+    expect(inspection?.callers).toHaveLength(1);
+  });
+
+  it('should drop source and list entries that exceed the token budget', () => {
+    const inspection = buildSymbolInspection(docs, 'Checkout', { maxTokens: 5 });
+
+    expect(inspection?.source).toBe('');
+    expect(inspection?.omitted).toBeGreaterThan(0);
+    expect(inspection?.tokens).toBeLessThanOrEqual(5);
+  });
+});
+
+describe('formatSymbolInspection', () => {
+  const docs = [
+    symbol(
+      'Get',
+      'function',
+      'cache/get.go',
+      'func Get(key string) []byte {\n\treturn nil\n}',
+      [],
+      { docstring: 'Get returns a cached value.' }
+    ),
+    symbol('Load', 'function', 'cache/load.go', 'func Load() {\n\tGet("a")\n}', [
+      { name: 'Get', line: 2 },
+    ]),
+  ];
+
+  it('should render each requested section', () => {
+    const inspection = buildSymbolInspection(docs, 'Get', {
+      sections: ['definition', 'callers', 'git'],
+    });
+    const output = formatSymbolInspection(inspection as SymbolInspection);
+
+    expect(output).toContain('# Get (function) - cache/get.go:1-3');
+    expect(output).toContain('Package: cache');
+    expect(output).toContain('`func Get(key string) []byte`');
+    expect(output).toContain('Get returns a cached value.');
+    expect(output).toContain('## Callers (1)');
+    expect(output).toContain('- Load - cache/load.go:1');
+    expect(output).toContain('Not recorded; index with `dev index --blame`');
+    expect(output).not.toContain('## Callees');
+  });
+
+  it('should list go build and go vet findings with the definition', () => {
+    const withDiagnostics = symbol('Get', 'function', 'cache/get.go', 'func Get() {}', [], {
+      diagnostics: [
+        { tool: 'vet', line: 1, column: 2, message: 'unreachable code' },
+        { tool: 'build', line: 1, message: 'declared and not used: x' },
+      ],
+    });
+    const inspection = buildSymbolInspection([withDiagnostics], 'Get');
+    const output = formatSymbolInspection(inspection as SymbolInspection);
+
+    expect(output).toContain('- **go vet** line 1: unreachable code');
+    expect(output).toContain('- **go build** line 1: declared and not used: x');
+  });
+});
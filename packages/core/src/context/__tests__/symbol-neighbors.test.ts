@@ -0,0 +1,134 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult, SearchResultMetadata } from '../../vector/types';
+import { buildSymbolNeighbors, formatSymbolNeighbors } from '../symbol-neighbors';
+import type { SymbolNeighbors } from '../types';
+
+function symbol(
+  name: string,
+  type: SearchResultMetadata['type'],
+  file: string,
+  startLine: number,
+  endLine: number,
+  extra: Partial<SearchResultMetadata> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: { name, type, path: file, language: 'go', startLine, endLine, ...extra },
+  };
+}
+
+describe('buildSymbolNeighbors', () => {
+  const docs: SearchResult[] = [
+    symbol('Cache', 'struct', 'store/cache.go', 10, 15, {
+      packageDoc: 'Package store keeps entries in memory.',
+    }),
+    symbol('NewCache', 'function', 'store/cache.go', 17, 20),
+    symbol('Cache.Get', 'method', 'store/cache.go', 22, 30, { signature: 'func (c *Cache) Get()' }),
+    symbol('Cache.Get.func1', 'function', 'store/cache.go', 25, 27, {
+      funcLiteral: { enclosing: 'Cache.Get', captures: [], usage: 'defer' },
+    }),
+    symbol('Cache.Set', 'method', 'store/cache.go', 32, 40),
+    symbol('evict', 'function', 'store/cache.go', 45, 50),
+    symbol('maxEntries', 'variable', 'store/cache.go', 52, 52),
+    symbol('Cache.Len', 'method', 'store/len.go', 3, 5),
+    symbol('Cache.Get', 'method', 'other/cache.go', 3, 5),
+  ];
+  const names = (symbols: SearchResult[]) => symbols.map((s) => s.metadata.name);
+
+  it('should list the symbols before and after a symbol in its file', () => {
+    const result = buildSymbolNeighbors(docs, { symbol: 'Cache.Get', path: 'store/' });
+
+    expect(result?.target?.metadata.name).toBe('Cache.Get');
+    expect(result?.file).toBe('store/cache.go');
+    expect(result?.package).toBe('store');
+    expect(result?.packageDoc).toBe('Package store keeps entries in memory.');
+    expect(names(result?.before ?? [])).toEqual(['Cache', 'NewCache']);
+    // Func literals inside Get aren't neighbors of it
+    expect(names(result?.after ?? [])).toEqual(['Cache.Set', 'evict', 'maxEntries']);
+  });
+
+  it('should limit each side to the nearest symbols', () => {
+    const result = buildSymbolNeighbors(docs, { symbol: 'NewCache', count: 1 });
+
+    expect(names(result?.before ?? [])).toEqual(['Cache']);
+    expect(names(result?.after ?? [])).toEqual(['Cache.Get']);
+  });
+
+  it('should group methods with their receiver across the package', () => {
+    const result = buildSymbolNeighbors(docs, { symbol: 'Cache.Set' });
+
+    expect(result?.group).toBe('Cache');
+    // The type first, then methods by name; other packages left out
+    expect(names(result?.groupMembers ?? [])).toEqual(['Cache', 'Cache.Get', 'Cache.Len']);
+    expect(result?.omittedGroupMembers).toBe(0);
+  });
+
+  it('should group a type with its methods and cap the group', () => {
+    const result = buildSymbolNeighbors(docs, { symbol: 'Cache', groupLimit: 2 });
+
+    expect(result?.group).toBe('Cache');
+    expect(names(result?.groupMembers ?? [])).toEqual(['Cache.Get', 'Cache.Len']);
+    expect(result?.omittedGroupMembers).toBe(1);
+  });
+
+  it('should not group plain functions', () => {
+    const result = buildSymbolNeighbors(docs, { symbol: 'evict' });
+
+    expect(result?.group).toBeUndefined();
+    expect(result?.groupMembers).toEqual([]);
+  });
+
+  it('should find the innermost symbol enclosing a line', () => {
+    const result = buildSymbolNeighbors(docs, { file: './store/cache.go', line: 26 });
+
+    expect(result?.line).toBe(26);
+    expect(result?.target?.metadata.name).toBe('Cache.Get');
+  });
+
+  it('should list symbols around a line between symbols', () => {
+    const result = buildSymbolNeighbors(docs, { file: 'store/cache.go', line: 42 });
+
+    expect(result?.target).toBeNull();
+    expect(names(result?.before ?? [])).toEqual(['NewCache', 'Cache.Get', 'Cache.Set']);
+    expect(names(result?.after ?? [])).toEqual(['evict', 'maxEntries']);
+    expect(result?.group).toBeUndefined();
+  });
+
+  it('should return null for unknown symbols and files', () => {
+    expect(buildSymbolNeighbors(docs, { symbol: 'Missing' })).toBeNull();
+    expect(buildSymbolNeighbors(docs, { file: 'missing.go', line: 1 })).toBeNull();
+    expect(buildSymbolNeighbors(docs, {})).toBeNull();
+  });
+});
+
+describe('formatSymbolNeighbors', () => {
+  const docs: SearchResult[] = [
+    symbol('Cache', 'struct', 'store/cache.go', 10, 15, {
+      packageDoc: 'Package store keeps entries in memory.',
+    }),
+    symbol('Cache.Get', 'method', 'store/cache.go', 22, 30, { signature: 'func (c *Cache) Get()' }),
+  ];
+
+  it('should format the neighborhood, the group, and the package doc', () => {
+    const result = buildSymbolNeighbors(docs, { symbol: 'Cache.Get' });
+    const output = formatSymbolNeighbors(result as SymbolNeighbors);
+
+    expect(output).toContain('# Neighbors of store.Cache.Get (store/cache.go:22)');
+    expect(output).toContain('Package store: Package store keeps entries in memory.');
+    expect(output).toContain('- **Cache** (struct, line 10)');
+    expect(output).toContain('- **Cache.Get** (method, line 22): `func (c *Cache) Get()`');
+    expect(output).toContain('Nothing defined after it in this file.');
+    expect(output).toContain('## Cache (1 other member)');
+    expect(output).toContain('- **Cache** (struct, store/cache.go:10)');
+  });
+
+  it('should say when a line is outside any symbol', () => {
+    const result = buildSymbolNeighbors(docs, { file: 'store/cache.go', line: 1 });
+    const output = formatSymbolNeighbors(result as SymbolNeighbors);
+
+    expect(output).toContain('# Neighbors of store/cache.go:1');
+    expect(output).toContain('Line is outside any indexed symbol.');
+    expect(output).toContain('Nothing defined before it in this file.');
+  });
+});
@@ -0,0 +1,89 @@
+import * as crypto from 'node:crypto';
+import { describe, expect, it, vi } from 'vitest';
+import { InMemoryFileSystem } from '../../utils/file-validator';
+import { SymbolSourceReader } from '../symbol-source';
+
+const body = [
+  'func NewExpBackoff(base time.Duration) *ExpBackoff {',
+  '\treturn &ExpBackoff{base: base}',
+  '}',
+];
+const source = [
+  'package backoff',
+  '',
+  '// NewExpBackoff returns a backoff doubling from base.',
+  ...body,
+  '',
+].join('\n');
+
+const sha256 = (content: string) => crypto.createHash('sha256').update(content).digest('hex');
+
+describe('SymbolSourceReader', () => {
+  const files = () => new InMemoryFileSystem({ 'backoff/backoff.go': source }, '/repo');
+
+  it('should read the exact lines of a symbol', () => {
+    const reader = new SymbolSourceReader('/repo', { fs: files() });
+
+    expect(reader.read({ path: 'backoff/backoff.go', startLine: 4, endLine: 6 })).toEqual({
+      file: 'backoff/backoff.go',
+      startLine: 4,
+      endLine: 6,
+      text: body.join('\n'),
+      stale: false,
+    });
+  });
+
+  it('should clamp ranges past the end of the file and skip missing files', () => {
+    const reader = new SymbolSourceReader('/repo', { fs: files() });
+
+    expect(reader.read({ path: 'backoff/backoff.go', startLine: 6, endLine: 40 })?.text).toBe(
+      '}\n'
+    );
+    expect(reader.read({ path: 'backoff/backoff.go', startLine: 50, endLine: 52 })).toBeNull();
+    expect(reader.read({ path: 'backoff/missing.go', startLine: 1, endLine: 2 })).toBeNull();
+    expect(reader.read({ startLine: 1, endLine: 2 })).toBeNull();
+  });
+
+  it('should read each file once', () => {
+    const fs = files();
+    const readText = vi.spyOn(fs, 'readText');
+    const reader = new SymbolSourceReader('/repo', { fs });
+
+    reader.read({ path: 'backoff/backoff.go', startLine: 1, endLine: 1 });
+    reader.read({ path: 'backoff/backoff.go', startLine: 4, endLine: 6 });
+    reader.read({ path: 'backoff/missing.go', startLine: 1, endLine: 1 });
+    reader.read({ path: 'backoff/missing.go', startLine: 1, endLine: 1 });
+
+    expect(readText).toHaveBeenCalledTimes(2);
+  });
+
+  it('should flag and warn once about files changed since indexing', () => {
+    const logger = { warn: vi.fn() };
+    const reader = new SymbolSourceReader('/repo', {
+      fs: files(),
+      indexedHash: (file) => (file === 'backoff/backoff.go' ? sha256('package old\n') : undefined),
+      logger: logger as never,
+    });
+
+    expect(reader.read({ path: 'backoff/backoff.go', startLine: 4, endLine: 6 })?.stale).toBe(
+      true
+    );
+    reader.read({ path: 'backoff/backoff.go', startLine: 1, endLine: 1 });
+
+    expect(reader.staleFiles()).toEqual(['backoff/backoff.go']);
+    expect(logger.warn).toHaveBeenCalledTimes(1);
+    expect(logger.warn.mock.calls[0][0]).toContain('backoff/backoff.go changed since');
+  });
+
+  it('should accept files matching their indexed hash', () => {
+    const reader = new SymbolSourceReader('/repo', {
+      fs: files(),
+      indexedHash: () => sha256(source),
+    });
+
+    expect(reader.read({ path: 'backoff/backoff.go', startLine: 4, endLine: 4 })?.stale).toBe(
+      false
+    );
+    expect(reader.staleFiles()).toEqual([]);
+  });
+});
@@ -0,0 +1,133 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { buildSymbolTests, formatSymbolTests, isTestFunction } from '../symbol-tests';
+
+function symbol(
+  name: string,
+  file: string,
+  startLine: number,
+  calls: string[] = [],
+  overrides: Partial<SearchResult['metadata']> = {}
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: {
+      name,
+      type: 'function',
+      path: file,
+      language: 'go',
+      startLine,
+      endLine: startLine + 5,
+      callees: calls.map((callee, i) => ({ name: callee, line: startLine + i + 1 })),
+      ...overrides,
+    },
+  };
+}
+
+describe('isTestFunction', () => {
+  it('should recognize go test entry points in test files', () => {
+    expect(isTestFunction(symbol('TestRetry', 'retry_test.go', 1))).toBe(true);
+    expect(isTestFunction(symbol('BenchmarkRetry', 'retry_test.go', 1))).toBe(true);
+    expect(isTestFunction(symbol('FuzzParse', 'parse_test.go', 1))).toBe(true);
+    expect(isTestFunction(symbol('Example', 'retry_test.go', 1))).toBe(true);
+    expect(isTestFunction(symbol('Suite.TestRetry', 'retry_test.go', 1))).toBe(true);
+  });
+
+  it('should not treat go helpers or lookalikes as tests', () => {
+    expect(isTestFunction(symbol('newTestServer', 'retry_test.go', 1))).toBe(false);
+    expect(isTestFunction(symbol('Testify', 'retry_test.go', 1))).toBe(false);
+    expect(isTestFunction(symbol('TestRetry', 'retry.go', 1))).toBe(false);
+  });
+
+  it('should treat named functions in other test files as tests', () => {
+    const ts = { language: 'typescript' };
+    expect(isTestFunction(symbol('setup', 'src/__tests__/retry.test.ts', 1, [], ts))).toBe(true);
+    expect(
+      isTestFunction(symbol('Config', 'src/retry.test.ts', 1, [], { ...ts, type: 'interface' }))
+    ).toBe(false);
+  });
+});
+
+describe('buildSymbolTests', () => {
+  const docs: SearchResult[] = [
+    symbol('Retry', 'retry/retry.go', 10),
+    symbol('Client.Do', 'client/client.go', 20, ['c.retry']),
+    symbol('Client.retry', 'client/client.go', 40, ['Retry']),
+    symbol('TestRetry', 'retry/retry_test.go', 5, ['Retry']),
+    symbol('TestClientDo', 'client/client_test.go', 8, ['c.Do']),
+    symbol('newTestClient', 'client/client_test.go', 30, ['c.Do']),
+    symbol('TestWithHelper', 'client/helper_test.go', 3, ['newTestClient']),
+    symbol('Unused', 'retry/retry.go', 60, [], { exported: true }),
+  ];
+
+  it('should separate direct and transitive tests', () => {
+    const tests = buildSymbolTests(docs, 'Retry');
+
+    expect(tests?.direct.map((r) => r.test.metadata.name)).toEqual(['TestRetry']);
+    expect(tests?.transitive.map((r) => [r.test.metadata.name, r.depth])).toEqual([
+      ['TestClientDo', 3],
+      ['TestWithHelper', 4],
+    ]);
+  });
+
+  it('should record the chain from each test to the symbol', () => {
+    const tests = buildSymbolTests(docs, 'Retry');
+    const helper = tests?.transitive.find((r) => r.test.metadata.name === 'TestWithHelper');
+
+    expect(helper?.via.map((s) => s.metadata.name)).toEqual([
+      'newTestClient',
+      'Client.Do',
+      'Client.retry',
+    ]);
+  });
+
+  it('should not report test helpers as tests', () => {
+    const tests = buildSymbolTests(docs, 'Client.Do');
+    const names = [...(tests?.direct ?? []), ...(tests?.transitive ?? [])].map(
+      (r) => r.test.metadata.name
+    );
+
+    expect(names).toEqual(['TestClientDo', 'TestWithHelper']);
+  });
+
+  it('should stop at the depth limit', () => {
+    const tests = buildSymbolTests(docs, 'Retry', { depth: 3 });
+
+    expect(tests?.transitive.map((r) => r.test.metadata.name)).toEqual(['TestClientDo']);
+  });
+
+  it('should return null for unknown symbols', () => {
+    expect(buildSymbolTests(docs, 'Missing')).toBeNull();
+  });
+});
+
+describe('formatSymbolTests', () => {
+  it('should list direct and transitive tests with their call chains', () => {
+    const tests = buildSymbolTests(
+      [
+        symbol('Retry', 'retry/retry.go', 10),
+        symbol('do', 'client/client.go', 20, ['Retry']),
+        symbol('TestRetry', 'retry/retry_test.go', 5, ['Retry']),
+        symbol('TestDo', 'client/client_test.go', 8, ['do']),
+      ],
+      'Retry'
+    );
+    const output = formatSymbolTests(tests as NonNullable<typeof tests>);
+
+    expect(output).toContain('# Tests for Retry');
+    expect(output).toContain('## Direct (1)');
+    expect(output).toContain('- **TestRetry** - retry/retry_test.go:5');
+    expect(output).toContain('## Transitive (1)');
+    expect(output).toContain('- **TestDo** - client/client_test.go:8 via do');
+  });
+
+  it('should flag exported symbols no test reaches', () => {
+    const unused = symbol('Unused', 'retry/retry.go', 60, [], { exported: true });
+    const tests = buildSymbolTests([unused], 'Unused');
+    const output = formatSymbolTests(tests as NonNullable<typeof tests>);
+
+    expect(output).toContain('**Untested:** no test reaches Unused within 5 calls.');
+    expect(output).toContain('It is exported');
+  });
+});
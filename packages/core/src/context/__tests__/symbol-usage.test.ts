@@ -0,0 +1,212 @@
+import { describe, expect, it } from 'vitest';
+import type { CalleeInfo } from '../../scanner/types';
+import { InMemoryFileSystem } from '../../utils/file-validator';
+import type { SearchResult } from '../../vector/types';
+import { SymbolSourceReader } from '../symbol-source';
+import { buildSymbolUsages, formatSymbolUsages } from '../symbol-usage';
+
+function symbol(
+  name: string,
+  file: string,
+  startLine: number,
+  snippet: string,
+  callees: CalleeInfo[] = []
+): SearchResult {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    score: 1,
+    metadata: {
+      name,
+      type: 'function',
+      path: file,
+      language: 'go',
+      startLine,
+      endLine: startLine + snippet.split('\n').length - 1,
+      snippet,
+      callees,
+    },
+  };
+}
+
+const call = (line: number): CalleeInfo => ({ name: 'backoff.NewExpBackoff', line });
+
+describe('buildSymbolUsages', () => {
+  const docs: SearchResult[] = [
+    symbol(
+      'NewExpBackoff',
+      'backoff/backoff.go',
+      10,
+      'func NewExpBackoff(base time.Duration, opts ...Option) *ExpBackoff {\n\treturn nil\n}'
+    ),
+    symbol(
+      'NewClient',
+      'client/client.go',
+      20,
+      [
+        'func NewClient(cfg Config) *Client {',
+        '\tb := backoff.NewExpBackoff(cfg.Base)',
+        '\treturn &Client{backoff: b}',
+        '}',
+      ].join('\n'),
+      [call(21)]
+    ),
+    symbol(
+      'newPoller',
+      'poller/poller.go',
+      5,
+      [
+        'func newPoller() *Poller {',
+        '\treturn &Poller{b: backoff.NewExpBackoff(',
+        '\t\t100*time.Millisecond,',
+        '\t\tbackoff.WithMax(5),',
+        '\t)}',
+        '}',
+      ].join('\n'),
+      [call(6)]
+    ),
+    symbol(
+      'newWorker',
+      'worker/worker.go',
+      8,
+      [
+        'func newWorker(cfg Config) *Worker {',
+        '\treturn &Worker{b: backoff.NewExpBackoff(cfg.Base)}',
+        '}',
+      ].join('\n'),
+      [call(9)]
+    ),
+    symbol(
+      'TestNewExpBackoff',
+      'backoff/backoff_test.go',
+      3,
+      'func TestNewExpBackoff(t *testing.T) {\n\tb := NewExpBackoff(time.Second)\n\t_ = b\n}',
+      [{ name: 'NewExpBackoff', line: 4 }]
+    ),
+  ];
+
+  it('should return null for an unknown symbol', () => {
+    expect(buildSymbolUsages(docs, 'Missing')).toBeNull();
+  });
+
+  it('should extract calls with their arguments and surrounding source', () => {
+    const usages = buildSymbolUsages(docs, 'NewExpBackoff');
+    const poller = usages?.examples.find((e) => e.caller.metadata.name === 'newPoller');
+
+    expect(poller?.call).toBe(
+      'backoff.NewExpBackoff( 100*time.Millisecond, backoff.WithMax(5), )'
+    );
+    expect(poller?.shape).toBe('(expr, call)');
+    expect(poller?.source).toContain('func newPoller() *Poller {');
+    expect(poller?.source).toContain('\t)}');
+  });
+
+  it('should rank different argument shapes before repeats', () => {
+    const usages = buildSymbolUsages(docs, 'NewExpBackoff', { limit: 2 });
+
+    expect(usages?.totalCalls).toBe(3);
+    expect(usages?.examples.map((e) => [e.caller.metadata.name, e.shape])).toEqual([
+      ['NewClient', '(selector)'],
+      ['newPoller', '(expr, call)'],
+    ]);
+  });
+
+  it('should list test usages separately', () => {
+    const usages = buildSymbolUsages(docs, 'NewExpBackoff');
+
+    expect(usages?.examples.some((e) => e.isTest)).toBe(false);
+    expect(usages?.totalTestCalls).toBe(1);
+    expect(usages?.testExamples[0]?.call).toBe('NewExpBackoff(time.Second)');
+
+    const withoutTests = buildSymbolUsages(docs, 'NewExpBackoff', { includeTests: false });
+    expect(withoutTests?.testExamples).toEqual([]);
+    expect(withoutTests?.totalTestCalls).toBe(1);
+  });
+
+  it('should format examples and test examples as markdown', () => {
+    const usages = buildSymbolUsages(docs, 'NewExpBackoff');
+    const output = formatSymbolUsages(usages as NonNullable<typeof usages>);
+
+    expect(output).toContain('# Usage of NewExpBackoff');
+    expect(output).toContain('## Examples (3 of 3 call sites)');
+    expect(output).toContain('### NewClient - client/client.go:21');
+    expect(output).toContain('`backoff.NewExpBackoff(cfg.Base)` - args: (selector)');
+    expect(output.indexOf('## Examples')).toBeLessThan(output.indexOf('## Test Examples'));
+  });
+
+  it('should put runnable examples for the symbol first', () => {
+    const example = (name: string, target: string, output?: string): SearchResult => {
+      const doc = symbol(name, 'backoff/example_test.go', 10, `func ${name}() {}`);
+      doc.metadata.example = { target, code: 'b := backoff.NewExpBackoff(time.Second)', output };
+      return doc;
+    };
+    const usages = buildSymbolUsages(
+      [
+        ...docs,
+        example('ExampleNewExpBackoff', 'NewExpBackoff', '1s'),
+        example('ExampleExpBackoff_Next', 'ExpBackoff.Next'),
+      ],
+      'NewExpBackoff'
+    );
+    const output = formatSymbolUsages(usages as NonNullable<typeof usages>);
+
+    expect(usages?.documentedExamples.map((doc) => doc.metadata.name)).toEqual([
+      'ExampleNewExpBackoff',
+    ]);
+    expect(output).toContain('## Documented Examples (1)');
+    expect(output).toContain('Output:\n\n```\n1s\n```');
+    expect(output.indexOf('## Documented Examples')).toBeLessThan(output.indexOf('## Examples'));
+  });
+
+  it('should read calls past a truncated snippet from the current source', () => {
+    const header = 'func newWorker(cfg Config) *Worker {';
+    const truncated = symbol('newWorker', 'worker/worker.go', 8, header);
+    const fs = new InMemoryFileSystem(
+      {
+        'worker/worker.go': [
+          'package worker',
+          ...Array(6).fill(''),
+          header,
+          '\treturn &Worker{b: backoff.NewExpBackoff(cfg.Base)}',
+          '}',
+        ].join('\n'),
+      },
+      '/repo'
+    );
+    truncated.metadata.callees = [call(9)];
+    truncated.metadata.endLine = 10;
+    const callers = [docs[0], truncated];
+
+    const fromIndex = buildSymbolUsages(callers, 'NewExpBackoff');
+    const fromSource = buildSymbolUsages(
+      callers,
+      'NewExpBackoff',
+      {},
+      undefined,
+      new SymbolSourceReader('/repo', { fs })
+    );
+
+    expect(fromIndex?.examples[0]?.call).toBe('');
+    expect(fromSource?.examples[0]?.call).toBe('backoff.NewExpBackoff(cfg.Base)');
+    expect(fromSource?.staleFiles).toEqual([]);
+  });
+
+  it('should keep indexed snippets for files changed since indexing', () => {
+    const fs = new InMemoryFileSystem({ 'client/client.go': 'package client\n' }, '/repo');
+    const sources = new SymbolSourceReader('/repo', { fs, indexedHash: () => 'indexed' });
+
+    const usages = buildSymbolUsages(docs, 'NewExpBackoff', {}, undefined, sources);
+    const output = formatSymbolUsages(usages as NonNullable<typeof usages>);
+
+    const client = usages?.examples.find((e) => e.caller.metadata.name === 'NewClient');
+    expect(client?.call).toBe('backoff.NewExpBackoff(cfg.Base)');
+    expect(usages?.staleFiles).toEqual(['client/client.go']);
+    expect(output).toContain('*Changed since indexing, shown as indexed: client/client.go*');
+  });
+
+  it('should report symbols without callers', () => {
+    const usages = buildSymbolUsages(docs, 'NewClient');
+    const output = formatSymbolUsages(usages as NonNullable<typeof usages>);
+
+    expect(output).toContain('No call sites found in the index.');
+  });
+});
@@ -0,0 +1,284 @@
+/**
+ * Call Graph
+ * Exports caller/callee and implements relationships as Graphviz DOT or a
+ * JSON node/edge list, for rendering and sharing in design reviews
+ *
+ * The graph starts from one symbol, or every symbol in a package, and
+ * follows calls and implements edges a bounded number of hops. Calls to
+ * names no indexed symbol has (the standard library, third-party packages)
+ * are left out unless asked for, and then end the path.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { implementedInterfaces, implementingTypes, qualifiedSymbolName } from './implementations';
+import { packageDir, resolvePackageDir } from './method-sets';
+import {
+  findTarget,
+  graphSymbols,
+  inTestFile,
+  SymbolGraph,
+  type SymbolGraphCache,
+} from './symbol-graph';
+import type {
+  CallGraph,
+  CallGraphEdge,
+  CallGraphEdgeKind,
+  CallGraphFormat,
+  CallGraphNode,
+  CallGraphOptions,
+  ImplementsEdge,
+} from './types';
+
+/** Default hops followed from the starting symbols */
+export const DEFAULT_GRAPH_DEPTH = 1;
+
+/** Default maximum nodes in a graph */
+export const DEFAULT_GRAPH_LIMIT = 200;
+
+/** Document types that can implement an interface */
+const IMPLEMENTING_KINDS = new Set(['class', 'type']);
+
+/**
+ * Export the call graph around a symbol or package from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Starting symbol or package, depth, direction, and filters
+ * @param graphs - Graph cache to reuse across calls
+ * @returns The graph, or null if the symbol or package isn't indexed
+ */
+export async function collectCallGraph(
+  indexer: RepositoryIndexer,
+  options: CallGraphOptions,
+  graphs?: SymbolGraphCache
+): Promise<CallGraph | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildCallGraph(docs, options, graph);
+}
+
+/**
+ * Export the call graph around a symbol or package from a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildCallGraph(
+  docs: SearchResult[],
+  options: CallGraphOptions,
+  symbolGraph?: SymbolGraph
+): CallGraph | null {
+  const {
+    depth = DEFAULT_GRAPH_DEPTH,
+    direction = 'both',
+    includeExternal = false,
+    includeImplements = true,
+    includeTests = false,
+    limit = DEFAULT_GRAPH_LIMIT,
+  } = options;
+  const symbols = graphSymbols(docs);
+  const keep = (symbol: SearchResult) => includeTests || !inTestFile(symbol.metadata.path ?? '');
+
+  let scope: string;
+  let start: SearchResult[];
+  if (options.symbol) {
+    const target = findTarget(symbols, options.symbol, options.path);
+    if (!target) return null;
+    scope = qualifiedSymbolName(target);
+    start = [target];
+  } else if (options.package !== undefined) {
+    const dir = resolvePackageDir(symbols, options.package);
+    if (dir === null) return null;
+    scope = dir || '.';
+    start = symbols.filter((symbol) => packageDir(symbol) === dir && keep(symbol));
+  } else {
+    throw new Error('A symbol or package is required to build a call graph');
+  }
+
+  const graph = symbolGraph ?? new SymbolGraph(symbols);
+  const outgoing = direction !== 'callers';
+  const incoming = direction !== 'callees';
+
+  // Finding implements edges means matching method sets, so do it once per symbol
+  const relations = new Map<string, { interfaces: ImplementsEdge[]; types: ImplementsEdge[] }>();
+  const implementsOf = (symbol: SearchResult) => {
+    let found = relations.get(symbol.id);
+    if (!found) {
+      const kind = String(symbol.metadata.type);
+      found = {
+        interfaces: IMPLEMENTING_KINDS.has(kind)
+          ? implementedInterfaces(docs, symbol, includeTests)
+          : [],
+        types: kind === 'interface' ? implementingTypes(docs, symbol, includeTests) : [],
+      };
+      relations.set(symbol.id, found);
+    }
+    return found;
+  };
+
+  const neighbours = (symbol: SearchResult): SearchResult[] => {
+    const next: SearchResult[] = [];
+    if (outgoing) next.push(...graph.calleesOf(symbol));
+    if (incoming) next.push(...graph.callersOf(symbol));
+    if (includeImplements) {
+      const { interfaces, types } = implementsOf(symbol);
+      if (outgoing) next.push(...indexedEnds(interfaces));
+      if (incoming) next.push(...indexedEnds(types));
+    }
+    return next.filter(keep);
+  };
+
+  // Breadth first, so the limit keeps the symbols closest to the start
+  const included = new Map<string, SearchResult>();
+  const reached = new Set<string>();
+  const reach = (symbol: SearchResult): boolean => {
+    if (reached.has(symbol.id)) return false;
+    reached.add(symbol.id);
+    if (included.size >= limit) return false;
+    included.set(symbol.id, symbol);
+    return true;
+  };
+
+  let frontier = start.filter(reach);
+  for (let hop = 0; hop < depth && frontier.length > 0; hop++) {
+    const next: SearchResult[] = [];
+    for (const symbol of frontier) {
+      for (const neighbour of neighbours(symbol)) {
+        if (reach(neighbour)) next.push(neighbour);
+      }
+    }
+    frontier = next;
+  }
+
+  const nodes = new Map<string, CallGraphNode>();
+  for (const symbol of included.values()) {
+    nodes.set(symbol.id, indexedNode(symbol));
+  }
+
+  const edges = new Map<string, CallGraphEdge>();
+  const addEdge = (from: string, to: string, kind: CallGraphEdgeKind, guarded = false) => {
+    if (from === to) return;
+    edges.set(`${from}\0${to}\0${kind}`, { from, to, kind, ...(guarded ? { guarded } : {}) });
+  };
+  let omittedExternal = 0;
+  const addExternal = (name: string, kind: string): string | null => {
+    const id = `external:${name}`;
+    if (!nodes.has(id)) {
+      if (nodes.size >= limit) {
+        omittedExternal++;
+        return null;
+      }
+      nodes.set(id, { id, label: name, kind, external: true });
+    }
+    return id;
+  };
+
+  for (const symbol of included.values()) {
+    // A call edge is guarded when every call behind it depends on a type check
+    const guarded = new Map<string, boolean>();
+    for (const { call, target } of graph.outgoingCalls(symbol)) {
+      if (!included.has(target.id)) continue;
+      guarded.set(target.id, (guarded.get(target.id) ?? true) && call.guarded === true);
+    }
+    for (const [target, isGuarded] of guarded) {
+      addEdge(symbol.id, target, 'call', isGuarded);
+    }
+    if (includeImplements) {
+      const { interfaces, types } = implementsOf(symbol);
+      for (const edge of interfaces) {
+        if (edge.symbol && included.has(edge.symbol.id)) {
+          addEdge(symbol.id, edge.symbol.id, 'implements');
+        } else if (!edge.symbol && includeExternal) {
+          const id = addExternal(edge.name, 'interface');
+          if (id) addEdge(symbol.id, id, 'implements');
+        }
+      }
+      for (const edge of types) {
+        if (edge.symbol && included.has(edge.symbol.id)) {
+          addEdge(edge.symbol.id, symbol.id, 'implements');
+        }
+      }
+    }
+    if (includeExternal) {
+      for (const call of graph.externalCallsOf(symbol)) {
+        const id = addExternal(call.name, 'function');
+        if (id) addEdge(symbol.id, id, 'call');
+      }
+    }
+  }
+
+  return {
+    scope,
+    depth,
+    nodes: [...nodes.values()],
+    edges: [...edges.values()],
+    omitted: reached.size - included.size + omittedExternal,
+  };
+}
+
+/**
+ * Format a call graph as Graphviz DOT or a JSON node/edge list
+ */
+export function formatCallGraph(callGraph: CallGraph, format: CallGraphFormat = 'dot'): string {
+  if (format === 'json') {
+    return `${JSON.stringify(callGraph, null, 2)}\n`;
+  }
+
+  const lines = [
+    `digraph ${quote(callGraph.scope)} {`,
+    `  label=${quote(`Call graph: ${callGraph.scope} (depth ${callGraph.depth})`)};`,
+    '  labelloc=t;',
+    '  rankdir=LR;',
+    '  node [shape=box, fontname="Helvetica"];',
+    '',
+  ];
+
+  for (const node of callGraph.nodes) {
+    const attributes = [`label=${quote(node.label)}`];
+    if (node.file) attributes.push(`tooltip=${quote(`${node.file}:${node.line ?? 1}`)}`);
+    if (node.kind === 'interface') attributes.push('shape=ellipse');
+    if (node.external) attributes.push('style=dashed', 'color=gray50');
+    lines.push(`  ${quote(node.id)} [${attributes.join(', ')}];`);
+  }
+  if (callGraph.edges.length > 0) lines.push('');
+
+  for (const edge of callGraph.edges) {
+    let attributes = '';
+    if (edge.kind === 'implements') {
+      attributes = ' [style=dashed, arrowhead=empty, label="implements"]';
+    } else if (edge.guarded) {
+      attributes = ' [style=dotted, label="guarded"]';
+    }
+    lines.push(`  ${quote(edge.from)} -> ${quote(edge.to)}${attributes};`);
+  }
+
+  if (callGraph.omitted > 0) {
+    const omitted = `${callGraph.omitted} more node(s) omitted; lower the depth or raise the limit`;
+    lines.push('', `  // ${omitted}`);
+  }
+  lines.push('}');
+
+  return `${lines.join('\n')}\n`;
+}
+
+function indexedNode(symbol: SearchResult): CallGraphNode {
+  const { type, path: file, startLine } = symbol.metadata;
+  return {
+    id: symbol.id,
+    label: qualifiedSymbolName(symbol),
+    kind: String(type ?? 'symbol'),
+    ...(file ? { file } : {}),
+    ...(startLine !== undefined ? { line: startLine } : {}),
+    external: false,
+  };
+}
+
+function indexedEnds(edges: ImplementsEdge[]): SearchResult[] {
+  return edges.flatMap((edge) => (edge.symbol ? [edge.symbol] : []));
+}
+
+/**
+ * A DOT double-quoted string
+ */
+function quote(value: string): string {
+  return `"${value.replace(/\\/g, '\\\\').replace(/"/g, '\\"')}"`;
+}
@@ -0,0 +1,242 @@
+/**
+ * Call Paths
+ * How one symbol can reach another through calls, for tracing a request
+ * down to a low-level call
+ *
+ * Distances to the target come first, from a breadth-first walk back from
+ * it over callers. They answer the no-path case outright and bound the
+ * forward search: a path is only extended to a callee that can still reach
+ * the target in the calls left. Paths are then enumerated one length at a
+ * time, shortest first, and a budget on extended paths keeps dense graphs
+ * from blowing up. Each call is shown with its source line, read from the
+ * current file when a SymbolSourceReader is given.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { CalleeInfo } from '../scanner/types';
+import type { SearchResult } from '../vector/types';
+import { qualifiedSymbolName } from './implementations';
+import {
+  findTarget,
+  graphSymbols,
+  inTestFile,
+  SymbolGraph,
+  type SymbolGraphCache,
+} from './symbol-graph';
+import type { SymbolSourceReader } from './symbol-source';
+import { formatStaleFiles } from './symbol-usage';
+import type { CallPathOptions, CallPaths, CallPathStep } from './types';
+
+/** Default paths returned: the shortest */
+export const DEFAULT_CALLPATH_LIMIT = 1;
+
+/** Default longest path searched, in calls */
+export const DEFAULT_CALLPATH_DEPTH = 6;
+
+/** Partial paths extended before the search stops looking for more */
+const MAX_EXTENDED_PATHS = 10000;
+
+/** A call on a path, before its source line is read */
+type PathStep = Omit<CallPathStep, 'snippet'>;
+
+/**
+ * Find call paths between two symbols from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param from - Symbol to start from (e.g. "Server.Handle")
+ * @param to - Symbol to reach (e.g. "Store.Save")
+ * @param options - Path limit, depth bound, and disambiguation options
+ * @param graphs - Graph cache to reuse across calls
+ * @param sources - Reader for callers' current source; indexed snippets when omitted
+ */
+export async function collectCallPaths(
+  indexer: RepositoryIndexer,
+  from: string,
+  to: string,
+  options?: CallPathOptions,
+  graphs?: SymbolGraphCache,
+  sources?: SymbolSourceReader
+): Promise<CallPaths> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildCallPaths(docs, from, to, options, graph, sources);
+}
+
+/**
+ * Find call paths between two symbols in a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ * @param sources - Reader for callers' current source; indexed snippets when omitted
+ */
+export function buildCallPaths(
+  docs: SearchResult[],
+  fromName: string,
+  toName: string,
+  options: CallPathOptions = {},
+  symbolGraph?: SymbolGraph,
+  sources?: SymbolSourceReader
+): CallPaths {
+  const {
+    limit = DEFAULT_CALLPATH_LIMIT,
+    maxDepth = DEFAULT_CALLPATH_DEPTH,
+    includeTests = false,
+  } = options;
+  const symbols = graphSymbols(docs);
+  const from = findTarget(symbols, fromName, options.fromPath);
+  const to = findTarget(symbols, toName, options.toPath);
+  const result: CallPaths = {
+    from,
+    to,
+    paths: [],
+    shortest: null,
+    maxDepth,
+    truncated: false,
+    staleFiles: [],
+  };
+  if (!from || !to) return result;
+
+  const graph = symbolGraph ?? new SymbolGraph(symbols);
+  // Calls out of test files are only followed when asked for; the start is always kept
+  const follows = (symbol: SearchResult) =>
+    symbol.id === from.id || includeTests || !inTestFile(symbol.metadata.path ?? '');
+
+  const distance = distancesTo(to, graph, follows);
+  result.shortest = distance.get(from.id) ?? null;
+  if (from.id === to.id || result.shortest === null || result.shortest > maxDepth) {
+    return result;
+  }
+
+  // First call to each callee that can still reach `to`, in line order
+  const outgoing = new Map<string, { call: CalleeInfo; target: SearchResult }[]>();
+  const callsFrom = (caller: SearchResult) => {
+    let calls = outgoing.get(caller.id);
+    if (!calls) {
+      calls = [];
+      const seen = new Set<string>();
+      for (const edge of graph.outgoingCalls(caller).sort((a, b) => a.call.line - b.call.line)) {
+        if (!distance.has(edge.target.id) || seen.has(edge.target.id)) continue;
+        seen.add(edge.target.id);
+        calls.push(edge);
+      }
+      outgoing.set(caller.id, calls);
+    }
+    return calls;
+  };
+
+  const found: PathStep[][] = [];
+  let budget = MAX_EXTENDED_PATHS;
+
+  // Depth first over paths of exactly `length` calls, no symbol twice
+  const walk = (symbol: SearchResult, steps: PathStep[], visited: Set<string>, length: number) => {
+    const remaining = length - steps.length;
+    for (const { call, target } of callsFrom(symbol)) {
+      if (found.length >= limit || budget <= 0) return;
+      const needed = distance.get(target.id) ?? Number.POSITIVE_INFINITY;
+      // `to` only ends a path
+      if (needed > remaining - 1 || visited.has(target.id)) continue;
+      if (target.id === to.id && remaining !== 1) continue;
+
+      budget--;
+      const path = [...steps, { caller: symbol, callee: target, line: call.line }];
+      if (target.id === to.id) {
+        found.push(path);
+        continue;
+      }
+      visited.add(target.id);
+      walk(target, path, visited, length);
+      visited.delete(target.id);
+    }
+  };
+
+  for (let length = result.shortest; length <= maxDepth && found.length < limit; length++) {
+    if (budget <= 0) break;
+    walk(from, [], new Set([from.id]), length);
+  }
+
+  result.truncated = found.length < limit && budget <= 0;
+  result.paths = found.map((path) => path.map((step) => withSnippet(step, sources)));
+  result.staleFiles = sources?.staleFiles() ?? [];
+  return result;
+}
+
+/**
+ * Format call paths as markdown: each path as numbered calls with their source lines
+ */
+export function formatCallPaths(result: CallPaths): string {
+  const { from, to, paths, shortest, maxDepth } = result;
+  if (!from || !to) return '';
+  const fromName = qualifiedSymbolName(from);
+  const toName = qualifiedSymbolName(to);
+
+  if (from.id === to.id) {
+    return `${fromName} and ${toName} are the same symbol.\n`;
+  }
+  if (shortest === null) {
+    return (
+      `No call path from ${fromName} to ${toName} in the index. Only calls resolved at ` +
+      'index time are followed, not calls through function values or reflection.\n'
+    );
+  }
+  if (shortest > maxDepth) {
+    return (
+      `The shortest call path from ${fromName} to ${toName} takes ${shortest} calls, ` +
+      `more than maxDepth (${maxDepth}). Raise maxDepth to see it.\n`
+    );
+  }
+
+  const title = paths.length === 1 ? 'Call path' : 'Call paths';
+  const lines = [`# ${title} from ${fromName} to ${toName}`, ''];
+  paths.forEach((path, index) => {
+    const calls = path.length === 1 ? '1 call' : `${path.length} calls`;
+    lines.push(`## Path ${index + 1} (${calls})`, '');
+    path.forEach((step, i) => {
+      const call = `${qualifiedSymbolName(step.caller)} → ${step.callee.metadata.name}`;
+      lines.push(`${i + 1}. ${call} (${step.caller.metadata.path}:${step.line})`);
+      if (step.snippet) lines.push(`   \`${step.snippet}\``);
+    });
+    lines.push('');
+  });
+
+  if (result.truncated) {
+    lines.push('*Search budget reached before finding more paths; lower maxDepth*', '');
+  }
+  lines.push(...formatStaleFiles(result.staleFiles));
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+/**
+ * Calls needed from each symbol to reach `target`, walking callers breadth first
+ */
+function distancesTo(
+  target: SearchResult,
+  graph: SymbolGraph,
+  follows: (symbol: SearchResult) => boolean
+): Map<string, number> {
+  const distance = new Map([[target.id, 0]]);
+  let frontier = [target];
+  for (let depth = 1; frontier.length > 0; depth++) {
+    const next: SearchResult[] = [];
+    for (const symbol of frontier) {
+      for (const caller of graph.callersOf(symbol)) {
+        if (distance.has(caller.id) || !follows(caller)) continue;
+        distance.set(caller.id, depth);
+        next.push(caller);
+      }
+    }
+    frontier = next;
+  }
+  return distance;
+}
+
+/**
+ * A step with the call's source line, from the current file unless it changed since indexing
+ */
+function withSnippet(step: PathStep, sources?: SymbolSourceReader): CallPathStep {
+  const { caller, line } = step;
+  const source = sources?.read(caller.metadata);
+  const text = source && !source.stale ? source.text : caller.metadata.snippet;
+  const index = line - (caller.metadata.startLine ?? 1);
+  const lines = (text ?? '').split('\n');
+  const snippet = index >= 0 && index < lines.length ? lines[index].trim() : '';
+  return { ...step, snippet };
+}
@@ -0,0 +1,205 @@
+/**
+ * Generic Constraints
+ * Constraint interfaces and the generic code using them, from the type sets
+ * and type-parameter constraints the Go scanner records
+ *
+ * Answers "what uses the Ordered constraint": references resolve like Go
+ * does, unqualified names to the same package and `pkg.Name` to an indexed
+ * package of that name, so two packages' Number constraints stay apart.
+ * Union terms naming other constraints (`Integer | Float`) are expanded to
+ * the underlying types.
+ */
+
+import * as path from 'node:path';
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { packageDir } from './method-sets';
+import { inTestFile, shortName } from './symbol-graph';
+import type { ConstraintEntry, ConstraintMap, ConstraintOptions } from './types';
+
+/** Default maximum constraints returned */
+export const DEFAULT_CONSTRAINT_LIMIT = 100;
+
+/**
+ * List generic constraints and their users in indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Constraint name, test inclusion, limit
+ */
+export async function collectConstraintUsages(
+  indexer: RepositoryIndexer,
+  options?: ConstraintOptions
+): Promise<ConstraintMap> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  return buildConstraintUsages(docs, options);
+}
+
+/**
+ * List generic constraints and their users in a set of indexed documents
+ *
+ * Every interface with a type set is listed, used or not; other interfaces
+ * and unindexed constraints are listed once something uses them.
+ */
+export function buildConstraintUsages(
+  docs: SearchResult[],
+  options: ConstraintOptions = {}
+): ConstraintMap {
+  const { name, includeTests = false, limit = DEFAULT_CONSTRAINT_LIMIT } = options;
+  const included = docs.filter((doc) => includeTests || !inTestFile(doc.metadata.path ?? ''));
+
+  const interfaces = new Map<string, SearchResult[]>();
+  for (const doc of included) {
+    if (doc.metadata.type !== 'interface' || !doc.metadata.name) continue;
+    const named = interfaces.get(doc.metadata.name) ?? [];
+    named.push(doc);
+    interfaces.set(doc.metadata.name, named);
+  }
+  const resolve = (reference: string, from: SearchResult) =>
+    resolveConstraint(interfaces, reference, from);
+
+  const entries = new Map<string, ConstraintEntry>();
+  const entryFor = (reference: string, definition: SearchResult | undefined) => {
+    const key = definition?.id ?? reference;
+    let entry = entries.get(key);
+    if (!entry) {
+      const typeSet = definition?.metadata.typeSet;
+      entry = {
+        name: definition?.metadata.name ?? reference,
+        kind: !definition ? 'external' : typeSet ? 'constraint' : 'interface',
+        ...(definition ? { definition } : {}),
+        ...(typeSet ? { typeSet, underlying: underlyingTerms(definition, resolve) } : {}),
+        users: [],
+      };
+      entries.set(key, entry);
+    }
+    return entry;
+  };
+
+  for (const doc of included) {
+    if (doc.metadata.typeSet) entryFor(doc.metadata.name ?? '', doc);
+  }
+  for (const doc of included) {
+    for (const reference of doc.metadata.typeConstraints ?? []) {
+      entryFor(reference, resolve(reference, doc)).users.push(doc);
+    }
+  }
+
+  const wanted = name?.replace(/\[[\s\S]*\]$/, '');
+  const matching = [...entries.values()]
+    .filter((entry) => !wanted || entry.name === wanted || shortName(entry.name) === wanted)
+    .sort(
+      (a, b) =>
+        b.users.length - a.users.length ||
+        a.name.localeCompare(b.name) ||
+        (a.definition?.metadata.path ?? '').localeCompare(b.definition?.metadata.path ?? '')
+    );
+  for (const entry of matching) {
+    entry.users.sort(
+      (a, b) =>
+        (a.metadata.path ?? '').localeCompare(b.metadata.path ?? '') ||
+        (a.metadata.startLine ?? 0) - (b.metadata.startLine ?? 0)
+    );
+  }
+
+  return {
+    constraints: matching.slice(0, limit),
+    total: matching.length,
+    omitted: Math.max(0, matching.length - limit),
+  };
+}
+
+/**
+ * Indexed interface a constraint reference names: an unqualified name in
+ * the referencing package, or `pkg.Name` in a package directory named pkg
+ */
+function resolveConstraint(
+  interfaces: Map<string, SearchResult[]>,
+  reference: string,
+  from: SearchResult
+): SearchResult | undefined {
+  const dot = reference.indexOf('.');
+  const candidates = interfaces.get(reference.slice(dot + 1)) ?? [];
+  if (dot === -1) {
+    const dir = packageDir(from);
+    return candidates.find((doc) => packageDir(doc) === dir);
+  }
+  const qualifier = reference.slice(0, dot);
+  return candidates
+    .filter((doc) => path.posix.basename(packageDir(doc)) === qualifier)
+    .sort((a, b) => packageDir(a).localeCompare(packageDir(b)))[0];
+}
+
+/**
+ * Union terms of a constraint with terms naming indexed constraints replaced
+ * by their own terms, recursively
+ */
+function underlyingTerms(
+  definition: SearchResult,
+  resolve: (reference: string, from: SearchResult) => SearchResult | undefined,
+  seen = new Set<string>()
+): string[] {
+  seen.add(definition.id);
+  const terms: string[] = [];
+  for (const term of definition.metadata.typeSet?.terms ?? []) {
+    const nested = /^[A-Za-z_][\w.]*$/.test(term) ? resolve(term, definition) : undefined;
+    if (nested?.metadata.typeSet && !seen.has(nested.id)) {
+      terms.push(...underlyingTerms(nested, resolve, seen));
+    } else {
+      terms.push(term);
+    }
+  }
+  return [...new Set(terms)];
+}
+
+/**
+ * Format generic constraints as markdown: one section per constraint with
+ * its type set and users
+ */
+export function formatConstraintUsages(constraintMap: ConstraintMap): string {
+  const lines = [`# Generic Constraints (${constraintMap.total})`, ''];
+
+  for (const entry of constraintMap.constraints) {
+    const where = entry.definition
+      ? ` (${entry.definition.metadata.path}:${entry.definition.metadata.startLine})`
+      : '';
+    lines.push(`## ${entry.name}: ${describeKind(entry)}${where}`, '');
+
+    if (entry.typeSet) {
+      const set = entry.typeSet.terms.join(' | ');
+      const comparable = entry.typeSet.comparable ? 'comparable' : '';
+      lines.push(`**Type set:** ${[set, comparable].filter(Boolean).join(', ')}`);
+      if (entry.underlying && entry.underlying.join(' | ') !== set) {
+        lines.push(`**Underlying types:** ${entry.underlying.join(' | ')}`);
+      }
+    }
+
+    if (entry.users.length === 0) {
+      lines.push('**Used by:** nothing indexed', '');
+      continue;
+    }
+    lines.push(`**Used by (${entry.users.length}):**`);
+    for (const user of entry.users) {
+      const { name, path: file, startLine, signature } = user.metadata;
+      const declaration = signature ? `: \`${signature}\`` : '';
+      lines.push(`- ${name} (${file}:${startLine})${declaration}`);
+    }
+    lines.push('');
+  }
+
+  if (constraintMap.omitted > 0) {
+    lines.push(`*${constraintMap.omitted} more constraints omitted; ask for one by name*`);
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function describeKind(entry: ConstraintEntry): string {
+  switch (entry.kind) {
+    case 'constraint':
+      return 'constraint interface';
+    case 'interface':
+      return 'method interface used as a constraint';
+    case 'external':
+      return 'not indexed';
+  }
+}
@@ -0,0 +1,124 @@
+/**
+ * Definitions
+ * Exact "go to definition" lookup by symbol name
+ *
+ * Names resolve through the symbol graph's name index instead of a vector
+ * search, so there is no fuzziness and no embedding. A name may be qualified
+ * by its package (`store.Get`, `store.Cache.Get`) or by a trailing part of the
+ * package directory (`internal/store.Get`); unqualified names match in every
+ * package, so the same name defined twice comes back twice.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { packageDir } from './method-sets';
+import {
+  graphSymbols,
+  inTestFile,
+  shortName,
+  SymbolGraph,
+  type SymbolGraphCache,
+} from './symbol-graph';
+import type { Definition, DefinitionOptions, Definitions } from './types';
+
+/**
+ * Look up the definitions of a symbol name in indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param name - Exact symbol name, optionally package-qualified
+ * @param options - Test inclusion
+ * @param graphs - Graph cache to reuse across calls
+ * @returns The definitions, or null if nothing has that name
+ */
+export async function collectDefinitions(
+  indexer: RepositoryIndexer,
+  name: string,
+  options?: DefinitionOptions,
+  graphs?: SymbolGraphCache
+): Promise<Definitions | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildDefinitions(docs, name, options, graph);
+}
+
+/**
+ * Look up the definitions of a symbol name in a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildDefinitions(
+  docs: SearchResult[],
+  name: string,
+  options: DefinitionOptions = {},
+  symbolGraph?: SymbolGraph
+): Definitions | null {
+  const { includeTests = false } = options;
+  const graph = symbolGraph ?? new SymbolGraph(graphSymbols(docs));
+
+  const matches = graph
+    .symbolsNamed(shortName(name))
+    .filter((symbol) => matchesName(symbol, name))
+    .map(
+      (symbol): Definition => ({
+        symbol,
+        package: packageDir(symbol),
+        isTest: inTestFile(symbol.metadata.path ?? ''),
+      })
+    )
+    .sort(
+      (a, b) =>
+        (a.symbol.metadata.path ?? '').localeCompare(b.symbol.metadata.path ?? '') ||
+        (a.symbol.metadata.startLine ?? 0) - (b.symbol.metadata.startLine ?? 0)
+    );
+  if (matches.length === 0) return null;
+
+  const definitions = includeTests ? matches : matches.filter((d) => !d.isTest);
+  return { name, definitions, omittedTests: matches.length - definitions.length };
+}
+
+/**
+ * Format definitions as markdown, one section per definition
+ */
+export function formatDefinitions(result: Definitions): string {
+  const { definitions } = result;
+  const packages = new Set(definitions.map((d) => d.package));
+  const count =
+    packages.size > 1
+      ? `${definitions.length} in ${packages.size} packages`
+      : `${definitions.length}`;
+  const lines = [`# Definitions of ${result.name} (${count})`, ''];
+
+  if (definitions.length === 0) {
+    lines.push(
+      `Only defined in test files (${result.omittedTests}); include tests to list them.`,
+      ''
+    );
+  }
+
+  for (const { symbol, package: pkg, isTest } of definitions) {
+    const { name, path: file, startLine, type, signature } = symbol.metadata;
+    const qualified = pkg ? `${pkg}.${name}` : name;
+    lines.push(`## ${qualified} - ${file}:${startLine} (${type}${isTest ? ', test' : ''})`);
+    if (signature) lines.push(`\`${signature}\``);
+    lines.push('');
+  }
+
+  if (definitions.length > 0 && result.omittedTests > 0) {
+    lines.push(`${result.omittedTests} more in test files.`, '');
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+/**
+ * Whether a symbol is the one a possibly package-qualified name refers to
+ */
+function matchesName(symbol: SearchResult, name: string): boolean {
+  const symbolName = symbol.metadata.name ?? '';
+  if (symbolName === name) return true;
+  if (!name.endsWith(`.${symbolName}`)) return false;
+
+  const qualifier = name.slice(0, -(symbolName.length + 1));
+  const dir = packageDir(symbol);
+  return dir === qualifier || dir.endsWith(`/${qualifier}`);
+}
@@ -0,0 +1,175 @@
+/**
+ * Go Module Dependencies
+ * Modules the repository's Go modules require, at their effective versions,
+ * and the indexed packages importing each
+ *
+ * Answers "do we depend on golang.org/x/net, at what version, and what code
+ * uses it". Versions come from go.mod as it is now, with replace directives
+ * applied; importers come from the indexed imports, each resolved against the
+ * go.mod of the module owning the importing file.
+ */
+
+import * as path from 'node:path';
+import type { RepositoryIndexer } from '../indexer';
+import {
+  findGoModules,
+  findOwningModule,
+  type GoDependency,
+  type GoModule,
+  type GoReplacement,
+  resolveGoDependency,
+} from '../scanner/go-modules';
+import type { SearchResult } from '../vector/types';
+import { inTestFile } from './symbol-graph';
+import type {
+  DependencyEntry,
+  DependencyImporter,
+  DependencyMap,
+  DependencyOptions,
+} from './types';
+
+/** Default maximum dependencies returned */
+export const DEFAULT_DEPENDENCY_LIMIT = 200;
+
+/**
+ * List the Go module dependencies of a repository and the indexed code importing them
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param repositoryPath - Repository root, searched for go.mod files
+ * @param options - Module filter, indirect and test inclusion, limit
+ */
+export async function collectDependencies(
+  indexer: RepositoryIndexer,
+  repositoryPath: string,
+  options?: DependencyOptions
+): Promise<DependencyMap> {
+  const [docs, modules] = await Promise.all([
+    indexer.getAll({ limit: 100000 }),
+    findGoModules(repositoryPath),
+  ]);
+  return buildDependencies(modules, docs, options);
+}
+
+/**
+ * List the dependencies of Go modules and the packages among `docs` importing them
+ */
+export function buildDependencies(
+  modules: GoModule[],
+  docs: SearchResult[],
+  options: DependencyOptions = {}
+): DependencyMap {
+  const {
+    module: wanted,
+    includeIndirect = true,
+    includeTests = false,
+    limit = DEFAULT_DEPENDENCY_LIMIT,
+  } = options;
+
+  // Files importing each dependency, keyed by requiring module and dependency path
+  const importers = new Map<string, Set<string>>();
+  for (const doc of docs) {
+    const file = doc.metadata.path;
+    if (!file || doc.metadata.language !== 'go') continue;
+    if (!includeTests && inTestFile(file)) continue;
+    const owner = findOwningModule(file, modules);
+    if (!owner) continue;
+    for (const importPath of doc.metadata.imports ?? []) {
+      const dependency = resolveGoDependency(importPath, owner);
+      if (!dependency) continue;
+      const key = dependencyKey(owner, dependency);
+      const files = importers.get(key) ?? new Set<string>();
+      files.add(file);
+      importers.set(key, files);
+    }
+  }
+
+  const needle = wanted?.toLowerCase();
+  const entries: DependencyEntry[] = [];
+  for (const owner of modules) {
+    for (const dependency of owner.dependencies ?? []) {
+      if (!includeIndirect && dependency.indirect) continue;
+      if (needle && !matchesModule(dependency, needle)) continue;
+      entries.push({
+        dependency,
+        requiredBy: owner.path,
+        moduleDir: owner.dir,
+        importers: groupByPackage(importers.get(dependencyKey(owner, dependency))),
+      });
+    }
+  }
+  entries.sort(
+    (a, b) =>
+      a.dependency.path.localeCompare(b.dependency.path) || a.moduleDir.localeCompare(b.moduleDir)
+  );
+
+  return {
+    dependencies: entries.slice(0, limit),
+    total: entries.length,
+    omitted: Math.max(0, entries.length - limit),
+    modules: modules.length,
+  };
+}
+
+/**
+ * Format dependencies as markdown: one section per dependency with its
+ * version, replacement, and importing packages
+ */
+export function formatDependencies(dependencyMap: DependencyMap): string {
+  const lines = [`# Go Dependencies (${dependencyMap.total})`, ''];
+
+  for (const entry of dependencyMap.dependencies) {
+    const { dependency } = entry;
+    const { replacedBy } = dependency;
+    const tags = [
+      dependency.indirect ? 'indirect' : 'direct',
+      ...(replacedBy ? [`replaced by ${describeReplacement(replacedBy)}`] : []),
+      ...(dependency.requiredVersion ? [`requires ${dependency.requiredVersion}`] : []),
+    ];
+    lines.push(`## ${dependency.path} ${dependency.version} (${tags.join('; ')})`, '');
+    if (dependencyMap.modules > 1) {
+      lines.push(`**Required by:** ${entry.requiredBy} (${entry.moduleDir})`);
+    }
+
+    if (entry.importers.length === 0) {
+      lines.push('**Imported by:** no indexed code', '');
+      continue;
+    }
+    lines.push(`**Imported by (${entry.importers.length}):**`);
+    for (const importer of entry.importers) {
+      const names = importer.files.map((file) => path.posix.basename(file));
+      lines.push(`- ${importer.dir || '.'}: ${names.join(', ')}`);
+    }
+    lines.push('');
+  }
+
+  if (dependencyMap.omitted > 0) {
+    lines.push(`*${dependencyMap.omitted} more dependencies omitted; filter by module*`);
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function dependencyKey(owner: GoModule, dependency: GoDependency): string {
+  return `${owner.dir}\n${dependency.path}`;
+}
+
+function matchesModule(dependency: GoDependency, needle: string): boolean {
+  return (
+    dependency.path.toLowerCase().includes(needle) ||
+    !!dependency.replacedBy?.path.toLowerCase().includes(needle)
+  );
+}
+
+function groupByPackage(files: Set<string> | undefined): DependencyImporter[] {
+  const packages = new Map<string, string[]>();
+  for (const file of [...(files ?? [])].sort()) {
+    const dir = path.posix.dirname(file);
+    const key = dir === '.' ? '' : dir;
+    packages.set(key, [...(packages.get(key) ?? []), file]);
+  }
+  return [...packages.entries()].map(([dir, packageFiles]) => ({ dir, files: packageFiles }));
+}
+
+function describeReplacement(replacement: GoReplacement): string {
+  return replacement.version ? `${replacement.path} ${replacement.version}` : replacement.path;
+}
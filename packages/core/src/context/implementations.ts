@@ -0,0 +1,551 @@
+/**
+ * Interface Implementations
+ * Lists the types that implement an interface, for "who satisfies this?" questions
+ *
+ * Two sources are combined: explicit compile-time assertions the scanner
+ * recorded (`var _ io.Reader = (*File)(nil)`), and structural matches where a
+ * type in any package has a method set, promoted methods included (also from
+ * types embedded across packages), covering the interface's methods with
+ * matching signatures. Interfaces are resolved in the repository first, then
+ * in a table of well-known standard library interfaces, whose signatures
+ * aren't known and are matched by name. Types one method short are reported
+ * separately as partial implementations.
+ */
+
+import * as path from 'node:path';
+import type { RepositoryIndexer } from '../indexer';
+import type { InterfaceAssertion } from '../scanner/types';
+import type { SearchResult } from '../vector/types';
+import {
+  buildMethodSets,
+  hasPointerReceiver,
+  KNOWN_INTERFACES,
+  type MethodSet,
+  normalizedMethodSignature,
+  packageDir,
+} from './method-sets';
+import { inTestFile } from './symbol-graph';
+import type {
+  Implementation,
+  ImplementationConfidence,
+  ImplementationOptions,
+  ImplementsEdge,
+  InterfaceImplementations,
+  PartialImplementation,
+  SatisfyingMethod,
+  SignatureMismatch,
+} from './types';
+
+/** Default implementations returned */
+export const DEFAULT_IMPLEMENTATION_LIMIT = 50;
+
+/** Implementations considered per interface when looking for a type's interfaces */
+const MAX_IMPLEMENTATIONS = 10000;
+
+const TYPE_KINDS = new Set(['class', 'interface', 'type']);
+
+/**
+ * Collect the implementations of an interface from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param iface - Interface name, optionally package-qualified (e.g. "io.Reader", "store.Store")
+ * @param options - Test inclusion and limit
+ * @returns The implementations, or null if the interface is neither in the
+ * repository, a known standard library interface, nor named by any assertion
+ */
+export async function collectImplementations(
+  indexer: RepositoryIndexer,
+  iface: string,
+  options?: ImplementationOptions
+): Promise<InterfaceImplementations | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  return buildImplementations(docs, iface, options);
+}
+
+/**
+ * Find the implementations of an interface in a set of indexed documents
+ */
+export function buildImplementations(
+  docs: SearchResult[],
+  iface: string,
+  options: ImplementationOptions = {}
+): InterfaceImplementations | null {
+  const { includeTests = false, limit = DEFAULT_IMPLEMENTATION_LIMIT } = options;
+  const requested = iface.trim();
+  const { qualifier, name } = splitQualified(requested);
+  const visible = docs.filter(
+    (doc) => doc.metadata.language === 'go' && (includeTests || !isTestDoc(doc))
+  );
+
+  const packages = new Map<string, SearchResult[]>();
+  for (const doc of visible) {
+    const dir = packageDir(doc);
+    packages.set(dir, [...(packages.get(dir) ?? []), doc]);
+  }
+  const dirs = [...packages.keys()];
+  const resolveDir = (qualifier: string) =>
+    dirs.filter((d) => inPackage(d, qualifier)).sort((a, b) => a.length - b.length)[0];
+
+  // Method sets are built per package on demand, so a type embedding `other.Base`
+  // picks up the methods of Base from the package that declares it
+  const methodSets = new Map<string, Map<string, MethodSet>>();
+  const building = new Set<string>();
+  const setsFor = (dir: string): Map<string, MethodSet> => {
+    let sets = methodSets.get(dir);
+    if (!sets) {
+      const members = packages.get(dir) ?? [];
+      const typeDocs = members.filter((doc) => TYPE_KINDS.has(doc.metadata.type ?? ''));
+      building.add(dir);
+      sets = buildMethodSets(members, typeDocs, (qualified) => {
+        const target = splitQualified(qualified);
+        const targetDir = resolveDir(target.qualifier as string);
+        if (targetDir === undefined || building.has(targetDir)) return undefined;
+        return setsFor(targetDir).get(target.name);
+      });
+      building.delete(dir);
+      methodSets.set(dir, sets);
+    }
+    return sets;
+  };
+
+  // Resolve the interface: a repository declaration, else a known library interface
+  const declarations = visible
+    .filter((doc) => doc.metadata.type === 'interface' && doc.metadata.name === name)
+    .filter((doc) => !qualifier || inPackage(packageDir(doc), qualifier))
+    .sort((a, b) => packageDir(a).length - packageDir(b).length);
+  const declaration = declarations[0];
+  const declarationDir = declaration ? packageDir(declaration) : undefined;
+
+  let required: string[] | undefined;
+  const expected = new Map<string, string | undefined>();
+  if (declaration) {
+    const set = setsFor(declarationDir as string).get(name) ?? new Map<string, SearchResult>();
+    required = [...set.keys()];
+    for (const [method, doc] of set) expected.set(method, normalizedMethodSignature(method, doc));
+  } else if (KNOWN_INTERFACES[requested]) {
+    required = KNOWN_INTERFACES[requested];
+  }
+
+  const assertsInterface = (written: string, dir: string): boolean => {
+    const parsed = splitQualified(written);
+    if (parsed.name !== name) return false;
+    if (declarationDir !== undefined) {
+      return parsed.qualifier
+        ? path.posix.basename(declarationDir) === parsed.qualifier
+        : dir === declarationDir;
+    }
+    return parsed.qualifier === (qualifier ? path.posix.basename(qualifier) : undefined);
+  };
+  const assertions = visible.filter((doc) => {
+    const asserts = doc.metadata.asserts;
+    return asserts !== undefined && assertsInterface(asserts.interface, packageDir(doc));
+  });
+
+  if (!required && assertions.length === 0) return null;
+
+  const typeDoc = (dir: string, type: string) =>
+    packages
+      .get(dir)
+      ?.find((doc) => doc.metadata.name === type && TYPE_KINDS.has(doc.metadata.type ?? ''));
+
+  // Go method sets: T has its value-receiver methods, *T all of them. A method
+  // promoted through an embedded *E is in T's set whatever E's receiver is, so
+  // look for a pointer embedding on the path down to the method's receiver.
+  const pointerOnPath = (
+    dir: string,
+    type: string,
+    method: SearchResult,
+    seen: Set<string>
+  ): boolean | undefined => {
+    if (dir === packageDir(method) && type === (method.metadata.name ?? '').split('.')[0]) {
+      return false;
+    }
+    const key = `${dir}\0${type}`;
+    if (seen.has(key)) return undefined;
+    seen.add(key);
+    for (const field of typeDoc(dir, type)?.metadata.fields ?? []) {
+      if (!field.embedded) continue;
+      const target = splitQualified(field.type.replace(/^\*/, '').replace(/\[.*\]$/, ''));
+      const targetDir = target.qualifier ? resolveDir(target.qualifier) : dir;
+      if (targetDir === undefined) continue;
+      const below = pointerOnPath(targetDir, target.name, method, seen);
+      if (below !== undefined) return field.type.startsWith('*') || below;
+    }
+    return undefined;
+  };
+  const needsPointer = (dir: string, type: string, method: SearchResult) =>
+    hasPointerReceiver(method) && pointerOnPath(dir, type, method, new Set()) !== true;
+
+  const found = new Map<string, Implementation>();
+  const entry = (dir: string, type: string): Implementation => {
+    const key = `${dir}\0${type}`;
+    let impl = found.get(key);
+    if (!impl) {
+      impl = {
+        type,
+        package: dir,
+        symbol: typeDoc(dir, type),
+        pointer: false,
+        structural: false,
+        methods: [],
+        confidence: 'high',
+        isTest: false,
+      };
+      found.set(key, impl);
+    }
+    return impl;
+  };
+
+  // Structural matches: concrete types in any package whose method set covers
+  // the interface, with signatures that agree wherever both are known. Types
+  // one method short are kept as partial implementations.
+  const partial: PartialImplementation[] = [];
+  if (required && required.length > 0) {
+    for (const dir of dirs) {
+      for (const [type, methods] of setsFor(dir)) {
+        const symbol = typeDoc(dir, type);
+        if (!symbol || symbol.metadata.type === 'interface') continue;
+
+        const satisfying: SatisfyingMethod[] = [];
+        const unsatisfied: { name: string; mismatch?: SignatureMismatch }[] = [];
+        for (const method of required) {
+          const declared = methods.get(method);
+          const want = expected.get(method);
+          const have = declared && normalizedMethodSignature(method, declared);
+          if (!declared) {
+            unsatisfied.push({ name: method });
+          } else if (want && have && want !== have) {
+            unsatisfied.push({
+              name: method,
+              mismatch: { symbol: declared, expected: want, actual: have },
+            });
+          } else {
+            satisfying.push({
+              name: method,
+              symbol: declared,
+              receiver:
+                declared.metadata.type !== 'method'
+                  ? 'interface'
+                  : hasPointerReceiver(declared)
+                    ? 'pointer'
+                    : 'value',
+              pointerOnly: needsPointer(dir, type, declared),
+              match: want && have ? 'exact' : 'name',
+            });
+          }
+        }
+        if (unsatisfied.length > 1 || satisfying.length === 0) continue;
+
+        const pointer = satisfying.some((method) => method.pointerOnly);
+        if (unsatisfied.length === 1) {
+          partial.push({
+            type,
+            package: dir,
+            symbol,
+            pointer,
+            methods: satisfying,
+            missing: unsatisfied[0].name,
+            mismatch: unsatisfied[0].mismatch,
+            isTest: isTestDoc(symbol),
+          });
+          continue;
+        }
+
+        const impl = entry(dir, type);
+        impl.structural = true;
+        impl.methods = satisfying;
+        impl.pointer = pointer;
+        impl.confidence = structuralConfidence(satisfying);
+        impl.isTest = isTestDoc(symbol);
+      }
+    }
+  }
+
+  // Explicit assertions, which may name a type from another package
+  for (const doc of assertions) {
+    const asserts = doc.metadata.asserts as InterfaceAssertion;
+    const target = splitQualified(asserts.type);
+    const dir = target.qualifier
+      ? (resolveDir(target.qualifier) ?? target.qualifier)
+      : packageDir(doc);
+
+    const impl = entry(dir, target.name);
+    if (impl.assertion) continue;
+    impl.assertion = doc;
+    // The compiler checks assertions, whatever the method signatures look like here
+    impl.confidence = 'high';
+    if (!impl.structural) impl.pointer = asserts.pointer;
+    impl.isTest = impl.isTest || isTestDoc(doc);
+  }
+
+  const implementations = [...found.values()].sort(
+    (a, b) => a.package.localeCompare(b.package) || a.type.localeCompare(b.type)
+  );
+
+  return {
+    interface: declaration ? qualifiedName(declarationDir as string, name) : requested,
+    declaration,
+    alternatives: declarations.slice(1).map((doc) => qualifiedName(packageDir(doc), name)),
+    methods: required ?? [],
+    methodsKnown: required !== undefined,
+    implementations: implementations.slice(0, limit),
+    omitted: Math.max(0, implementations.length - limit),
+    partial: partial
+      .filter((impl) => !found.get(`${impl.package}\0${impl.type}`)?.assertion)
+      .sort((a, b) => a.package.localeCompare(b.package) || a.type.localeCompare(b.type))
+      .slice(0, limit),
+  };
+}
+
+/**
+ * Types implementing an interface
+ */
+export function implementingTypes(
+  docs: SearchResult[],
+  iface: SearchResult,
+  includeTests: boolean
+): ImplementsEdge[] {
+  const result = buildImplementations(docs, qualifiedSymbolName(iface), {
+    includeTests,
+    limit: MAX_IMPLEMENTATIONS,
+  });
+  return (result?.implementations ?? []).map((impl) => ({
+    name: impl.package ? `${impl.package}.${impl.type}` : impl.type,
+    symbol: impl.symbol,
+    pointer: impl.pointer,
+  }));
+}
+
+/**
+ * Interfaces a type implements
+ *
+ * TypeScript classes declare them; Go types are checked against every
+ * interface sharing a method name with them (indexed or well known), and
+ * every interface the type is asserted to implement.
+ */
+export function implementedInterfaces(
+  docs: SearchResult[],
+  type: SearchResult,
+  includeTests: boolean
+): ImplementsEdge[] {
+  const typeName = type.metadata.name ?? '';
+  const dir = packageDir(type);
+
+  if (type.metadata.language !== 'go') {
+    const clause = (type.metadata.signature ?? '').match(/\bimplements\s+(.+)$/)?.[1] ?? '';
+    return clause
+      .split(',')
+      .map((name) => name.trim().replace(/<.*$/, ''))
+      .filter(Boolean)
+      .map((name) => ({
+        name,
+        symbol: docs.find((d) => d.metadata.type === 'interface' && d.metadata.name === name),
+        pointer: false,
+      }));
+  }
+
+  const methods = new Set(
+    docs
+      .filter((d) => d.metadata.type === 'method' && packageDir(d) === dir)
+      .map((d) => d.metadata.name ?? '')
+      .filter((name) => name.startsWith(`${typeName}.`))
+      .map((name) => name.slice(typeName.length + 1))
+  );
+  const declares = (iface: SearchResult) =>
+    [...methods].some((method) =>
+      new RegExp(`^\\s*${method}\\s*\\(`, 'm').test(iface.metadata.snippet ?? '')
+    );
+
+  const candidates = new Set<string>();
+  for (const doc of docs) {
+    const { type: kind, asserts } = doc.metadata;
+    if (kind === 'interface' && doc.id !== type.id && declares(doc)) {
+      candidates.add(qualifiedSymbolName(doc));
+    } else if (asserts?.type === typeName && packageDir(doc) === dir) {
+      candidates.add(asserts.interface);
+    }
+  }
+  for (const [iface, required] of Object.entries(KNOWN_INTERFACES)) {
+    if (required.every((method) => methods.has(method))) candidates.add(iface);
+  }
+
+  const edges: ImplementsEdge[] = [];
+  for (const iface of candidates) {
+    const result = buildImplementations(docs, iface, { includeTests, limit: MAX_IMPLEMENTATIONS });
+    const impl = result?.implementations.find(
+      (candidate) => candidate.type === typeName && candidate.package === dir
+    );
+    if (result && impl) {
+      edges.push({ name: result.interface, symbol: result.declaration, pointer: impl.pointer });
+    }
+  }
+  return edges.sort((a, b) => a.name.localeCompare(b.name));
+}
+
+/**
+ * Name of a symbol qualified by its package directory
+ */
+export function qualifiedSymbolName(symbol: SearchResult): string {
+  const dir = packageDir(symbol);
+  return dir ? `${dir}.${symbol.metadata.name}` : (symbol.metadata.name ?? '');
+}
+
+/**
+ * Format implementations as markdown, with how each type satisfies the interface
+ */
+export function formatImplementations(result: InterfaceImplementations): string {
+  const { implementations, declaration } = result;
+  const lines = [`# Implementations of ${result.interface}`, ''];
+
+  if (declaration) {
+    lines.push(`Declared at ${declaration.metadata.path}:${declaration.metadata.startLine}`);
+  }
+  if (result.methodsKnown) {
+    lines.push(
+      result.methods.length > 0
+        ? `Requires: ${result.methods.join(', ')}`
+        : 'Requires no methods; only explicit assertions are listed'
+    );
+  } else {
+    lines.push('Methods unknown (interface not indexed); only explicit assertions are listed');
+  }
+  if (result.alternatives.length > 0) {
+    lines.push(`Also named ${result.alternatives.join(', ')}; qualify the name to pick one`);
+  }
+  lines.push('');
+
+  const total = implementations.length + result.omitted;
+  if (total === 0) {
+    lines.push('No implementations found.');
+    return `${lines.join('\n')}\n`;
+  }
+  const asserted = implementations.filter((impl) => impl.assertion).length;
+  lines.push(`${total} implementation${total === 1 ? '' : 's'} (${asserted} asserted)`, '');
+
+  for (const impl of implementations) {
+    const type = `${impl.pointer ? '*' : ''}${impl.type}`;
+    const location = impl.symbol
+      ? ` - ${impl.symbol.metadata.path}:${impl.symbol.metadata.startLine}`
+      : '';
+    const test = impl.isTest ? ' [test]' : '';
+    lines.push(`## ${type} (${impl.package || '.'})${location}${test}`);
+
+    lines.push(`- confidence: ${impl.confidence} (${confidenceReason(impl)})`);
+    if (impl.assertion) {
+      const { path: file, startLine, asserts } = impl.assertion.metadata;
+      lines.push(`- asserted as ${asserts?.interface} at ${file}:${startLine}`);
+    }
+    const pointerOnly = impl.methods.filter((method) => method.pointerOnly);
+    if (impl.structural && pointerOnly.length > 0) {
+      const names = pointerOnly.map((method) => method.name).join(', ');
+      lines.push(
+        `- only *${impl.type} implements ${result.interface}: ${names} ` +
+          `${pointerOnly.length === 1 ? 'has a pointer receiver' : 'have pointer receivers'}, ` +
+          `so a ${impl.type} value does not`
+      );
+    } else if (impl.structural) {
+      lines.push(`- ${impl.type} and *${impl.type} both implement ${result.interface}`);
+    }
+    if (impl.structural) {
+      for (const method of impl.methods) {
+        lines.push(`- ${method.name}: ${describeMethod(impl, method)}`);
+      }
+    } else if (result.methodsKnown && result.methods.length > 0) {
+      lines.push('- method set not fully indexed (methods may come from another package)');
+    }
+    lines.push('');
+  }
+  if (result.omitted > 0) {
+    lines.push(`*${result.omitted} more implementations omitted*`, '');
+  }
+  formatPartial(lines, result.partial);
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+/**
+ * Append types one method short of the interface, with what's missing
+ */
+function formatPartial(lines: string[], partial: PartialImplementation[]): void {
+  if (partial.length === 0) return;
+  lines.push(`# Partial implementations (${partial.length}, one method short)`, '');
+
+  for (const impl of partial) {
+    const type = `${impl.pointer ? '*' : ''}${impl.type}`;
+    const { path: file, startLine } = impl.symbol.metadata;
+    const test = impl.isTest ? ' [test]' : '';
+    lines.push(`## ${type} (${impl.package || '.'}) - ${file}:${startLine}${test}`);
+    if (impl.mismatch) {
+      const { symbol, expected, actual } = impl.mismatch;
+      const at = `${symbol.metadata.path}:${symbol.metadata.startLine}`;
+      lines.push(
+        `- ${impl.missing}: signature differs at ${at} (has \`${actual}\`, needs \`${expected}\`)`
+      );
+    } else {
+      lines.push(`- ${impl.missing}: missing`);
+    }
+    lines.push(`- has ${impl.methods.map((method) => method.name).join(', ')}`, '');
+  }
+}
+
+function describeMethod(impl: Implementation, method: SatisfyingMethod): string {
+  const { name, path: file, startLine } = method.symbol.metadata;
+  const at = `${file}:${startLine}`;
+  const byName = method.match === 'name' ? ', matched by name' : '';
+  if (method.receiver === 'interface') {
+    return `via an interface embedded in ${name} (${at})${byName}`;
+  }
+  const receiver = (name ?? '').split('.')[0];
+  const source = receiver === impl.type ? `${name} (${at})` : `promoted from ${name} (${at})`;
+  return `${source}, ${method.receiver} receiver${byName}`;
+}
+
+/**
+ * Confidence of a structural match, from how its methods were compared
+ */
+function structuralConfidence(methods: SatisfyingMethod[]): ImplementationConfidence {
+  const exact = methods.filter((method) => method.match === 'exact').length;
+  return exact === methods.length ? 'high' : exact > 0 ? 'medium' : 'low';
+}
+
+function confidenceReason(impl: Implementation): string {
+  if (impl.assertion) return 'compile-time assertion';
+  switch (impl.confidence) {
+    case 'high':
+      return 'every signature matches';
+    case 'medium':
+      return 'some methods matched by name only';
+    case 'low':
+      return 'methods matched by name only';
+  }
+}
+
+/**
+ * Split `pkg.Name` (or `path/to/pkg.Name`) into qualifier and name
+ */
+function splitQualified(name: string): { qualifier?: string; name: string } {
+  const dot = name.lastIndexOf('.');
+  return dot === -1
+    ? { name }
+    : { qualifier: name.slice(0, dot).replace(/^\*/, ''), name: name.slice(dot + 1) };
+}
+
+/**
+ * Whether a package directory is the one a qualifier names: the directory
+ * itself, a trailing part of it, or its last element (the package name)
+ */
+function inPackage(dir: string, qualifier: string): boolean {
+  return (
+    dir === qualifier ||
+    dir.endsWith(`/${qualifier}`) ||
+    path.posix.basename(dir) === path.posix.basename(qualifier)
+  );
+}
+
+function qualifiedName(dir: string, name: string): string {
+  return dir ? `${path.posix.basename(dir)}.${name}` : name;
+}
+
+function isTestDoc(doc: SearchResult): boolean {
+  return inTestFile(doc.metadata.path ?? '');
+}
@@ -1,4 +1,31 @@
 // Context provider module
+export * from './call-graph';
+export * from './call-paths';
+export * from './constraints';
+export * from './definitions';
+export * from './dependencies';
+export * from './implementations';
+export * from './openapi';
+export * from './package-outline';
+export * from './recursion';
+export * from './routes';
+export * from './sql-queries';
+export * from './symbol-context';
+export * from './symbol-explanation';
+export * from './symbol-inspection';
+export * from './symbol-neighbors';
+export * from './symbol-source';
+export {
+  graphSymbols,
+  type InternalViolation,
+  referencesType,
+  SymbolGraph,
+  SymbolGraphCache,
+} from './symbol-graph';
+export * from './symbol-tests';
+export * from './symbol-usage';
+export * from './types';
+
 export interface ContextProviderOptions {
   repositoryPath: string;
   maxContextItems: number;
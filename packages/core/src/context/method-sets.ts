@@ -0,0 +1,303 @@
+/**
+ * Method Sets
+ * Go method sets computed from indexed documents, shared by package outlines
+ * and implementation lookup
+ *
+ * Indexed metadata has no type checker behind it, so signatures are compared
+ * textually after normalization (see normalizedMethodSignature).
+ */
+
+import * as path from 'node:path';
+import type { SearchResult } from '../vector/types';
+
+/**
+ * Methods of commonly implemented standard library interfaces, used when an
+ * interface is embedded from, or looked up in, a package that isn't indexed
+ */
+export const KNOWN_INTERFACES: Record<string, string[]> = {
+  error: ['Error'],
+  'fmt.Stringer': ['String'],
+  'fmt.GoStringer': ['GoString'],
+  'fmt.Formatter': ['Format'],
+  'io.Reader': ['Read'],
+  'io.Writer': ['Write'],
+  'io.Closer': ['Close'],
+  'io.Seeker': ['Seek'],
+  'io.ReaderAt': ['ReadAt'],
+  'io.WriterAt': ['WriteAt'],
+  'io.ReaderFrom': ['ReadFrom'],
+  'io.WriterTo': ['WriteTo'],
+  'io.ByteReader': ['ReadByte'],
+  'io.ByteWriter': ['WriteByte'],
+  'io.RuneReader': ['ReadRune'],
+  'io.StringWriter': ['WriteString'],
+  'io.ReadCloser': ['Read', 'Close'],
+  'io.WriteCloser': ['Write', 'Close'],
+  'io.ReadWriter': ['Read', 'Write'],
+  'io.ReadWriteCloser': ['Read', 'Write', 'Close'],
+  'io.ReadSeeker': ['Read', 'Seek'],
+  'io.ReadSeekCloser': ['Read', 'Seek', 'Close'],
+  'io.WriteSeeker': ['Write', 'Seek'],
+  'io.ReadWriteSeeker': ['Read', 'Write', 'Seek'],
+  'sort.Interface': ['Len', 'Less', 'Swap'],
+  'heap.Interface': ['Len', 'Less', 'Swap', 'Push', 'Pop'],
+  'http.Handler': ['ServeHTTP'],
+  'http.RoundTripper': ['RoundTrip'],
+  'http.Flusher': ['Flush'],
+  'http.Hijacker': ['Hijack'],
+  'http.ResponseWriter': ['Header', 'Write', 'WriteHeader'],
+  'json.Marshaler': ['MarshalJSON'],
+  'json.Unmarshaler': ['UnmarshalJSON'],
+  'encoding.TextMarshaler': ['MarshalText'],
+  'encoding.TextUnmarshaler': ['UnmarshalText'],
+  'encoding.BinaryMarshaler': ['MarshalBinary'],
+  'encoding.BinaryUnmarshaler': ['UnmarshalBinary'],
+  'context.Context': ['Deadline', 'Done', 'Err', 'Value'],
+  'sync.Locker': ['Lock', 'Unlock'],
+  'driver.Valuer': ['Value'],
+  'sql.Scanner': ['Scan'],
+  'flag.Value': ['String', 'Set'],
+  'hash.Hash': ['Write', 'Sum', 'Reset', 'Size', 'BlockSize'],
+  'net.Conn': [
+    'Read',
+    'Write',
+    'Close',
+    'LocalAddr',
+    'RemoteAddr',
+    'SetDeadline',
+    'SetReadDeadline',
+    'SetWriteDeadline',
+  ],
+  'net.Listener': ['Accept', 'Close', 'Addr'],
+  'fs.File': ['Stat', 'Read', 'Close'],
+  'fs.FS': ['Open'],
+  'slog.Handler': ['Enabled', 'Handle', 'WithAttrs', 'WithGroup'],
+};
+
+/**
+ * Methods in a method set, each with the document that declares it: the
+ * method itself, or the type whose embedded external interface supplies it
+ */
+export type MethodSet = Map<string, SearchResult>;
+
+/**
+ * Package directory of a document, relative to the repository root
+ */
+export function packageDir(doc: SearchResult): string {
+  const dir = path.posix.dirname(doc.metadata.path ?? '');
+  return dir === '.' ? '' : dir;
+}
+
+/**
+ * Find the indexed directory for a package: an exact match, or the shortest
+ * directory ending in it
+ */
+export function resolvePackageDir(docs: SearchResult[], pkg: string): string | null {
+  const wanted = pkg.replace(/^\.\//, '').replace(/\/+$/, '');
+  const dirs = new Set(docs.map(packageDir));
+  if (dirs.has(wanted)) return wanted;
+
+  const suffix = `/${wanted}`;
+  const matches = [...dirs].filter((dir) => dir.endsWith(suffix));
+  if (matches.length === 0) return null;
+  return matches.sort((a, b) => a.length - b.length || a.localeCompare(b))[0];
+}
+
+/**
+ * Method sets per type, including methods promoted from embedded package types
+ * (for interfaces, the methods required by embedded interfaces)
+ *
+ * @param members - Documents of one package
+ * @param typeDocs - The package's struct, interface, and named type documents
+ * @param resolveExternal - Method set of a qualified embedded type (`store.Base`)
+ * declared in another package; without it only well-known interfaces resolve
+ */
+export function buildMethodSets(
+  members: SearchResult[],
+  typeDocs: SearchResult[],
+  resolveExternal?: (qualified: string) => MethodSet | undefined
+): Map<string, MethodSet> {
+  const own = new Map<string, MethodSet>();
+  const add = (type: string, method: string, doc: SearchResult) => {
+    const methods = own.get(type) ?? new Map<string, SearchResult>();
+    if (!methods.has(method)) methods.set(method, doc);
+    own.set(type, methods);
+  };
+
+  for (const doc of typeDocs.filter((d) => d.metadata.type === 'interface')) {
+    for (const method of interfaceMethods(doc)) add(doc.metadata.name as string, method, doc);
+  }
+  for (const doc of members.filter((d) => d.metadata.type === 'method')) {
+    // Methods are named Receiver.Method
+    const [receiver, method] = (doc.metadata.name as string).split('.');
+    if (method) add(receiver, method, doc);
+  }
+
+  const embeds = new Map(typeDocs.map((doc) => [doc.metadata.name as string, embeddedTypes(doc)]));
+  const collect = (type: string, seen: Set<string>): MethodSet => {
+    const methods: MethodSet = new Map(own.get(type));
+    seen.add(type);
+    for (const embedded of embeds.get(type) ?? []) {
+      if (seen.has(embedded)) continue;
+      const external =
+        !embeds.has(embedded) && embedded.includes('.') ? resolveExternal?.(embedded) : undefined;
+      if (external) {
+        for (const [method, doc] of external) {
+          if (!methods.has(method)) methods.set(method, doc);
+        }
+        continue;
+      }
+      if (!embeds.has(embedded) && KNOWN_INTERFACES[embedded]) {
+        const doc = typeDocs.find((d) => d.metadata.name === type) as SearchResult;
+        for (const method of KNOWN_INTERFACES[embedded]) {
+          if (!methods.has(method)) methods.set(method, doc);
+        }
+        continue;
+      }
+      for (const [method, doc] of collect(embedded, seen)) {
+        if (!methods.has(method)) methods.set(method, doc);
+      }
+    }
+    return methods;
+  };
+
+  return new Map([...embeds.keys()].map((type) => [type, collect(type, new Set())]));
+}
+
+/**
+ * Types embedded in a struct or interface, without pointer markers
+ */
+export function embeddedTypes(doc: SearchResult): string[] {
+  if (doc.metadata.type === 'interface') {
+    return interfaceBody(doc).filter((line) => /^[\w.]+$/.test(line));
+  }
+  const fields = doc.metadata.fields ?? [];
+  return fields.filter((field) => field.embedded).map((field) => field.type.replace(/^\*/, ''));
+}
+
+/**
+ * Methods an interface declares directly (embedded interfaces not expanded)
+ */
+export function interfaceMethods(doc: SearchResult): Set<string> {
+  const methods = new Set<string>();
+  for (const line of interfaceBody(doc)) {
+    const match = line.match(/^(\w+)\s*\(/);
+    if (match) methods.add(match[1]);
+  }
+  return methods;
+}
+
+/**
+ * Whether a method is declared on a pointer receiver (`func (s *Server) ...`)
+ */
+export function hasPointerReceiver(doc: SearchResult): boolean {
+  const signature = doc.metadata.signature ?? '';
+  return doc.metadata.type === 'method' && /^func\s*\(\s*\w*\s*\*/.test(signature);
+}
+
+/**
+ * Parameter and result types of a method as `(T1, T2) (R1, R2)`: names are
+ * dropped, and the declaring package's own exported types are qualified with
+ * its name (`Item` declared in store becomes `store.Item`), so the same types
+ * written from two packages compare equal
+ *
+ * @param name - Method name
+ * @param symbol - Declaring document: a method, or an interface that lists it
+ * @returns undefined when the signature isn't known, e.g. a method supplied by
+ * a well-known library interface or a signature the index cut short
+ */
+export function normalizedMethodSignature(name: string, symbol: SearchResult): string | undefined {
+  let tail: string | undefined;
+  if (symbol.metadata.type === 'method') {
+    const match = (symbol.metadata.signature ?? '').match(/^func\s*\([^)]*\)\s*\w+\s*(\(.*)$/s);
+    tail = match?.[1];
+  } else if (symbol.metadata.type === 'interface') {
+    const line = interfaceBody(symbol).find((l) => new RegExp(`^${name}\\s*\\(`).test(l));
+    tail = line?.slice(name.length).trim();
+  }
+  if (!tail) return undefined;
+
+  const paramsEnd = closingParen(tail, 0);
+  if (paramsEnd === -1) return undefined;
+  let results = tail.slice(paramsEnd + 1).trim();
+  if (results.startsWith('(')) {
+    const resultsEnd = closingParen(results, 0);
+    if (resultsEnd === -1) return undefined;
+    results = results.slice(1, resultsEnd);
+  }
+
+  const pkg = path.posix.basename(packageDir(symbol));
+  const qualify = (type: string) =>
+    pkg ? type.replace(/(^|\.\.\.|[^\w.])([A-Z]\w*)/g, `$1${pkg}.$2`) : type;
+  const params = parameterTypes(tail.slice(1, paramsEnd)).map(qualify);
+  return `(${params.join(', ')}) (${parameterTypes(results).map(qualify).join(', ')})`;
+}
+
+export function isExportedName(name: string): boolean {
+  return /^[A-Z]/.test(name);
+}
+
+/**
+ * Trimmed, non-comment lines between an interface's braces
+ */
+function interfaceBody(doc: SearchResult): string[] {
+  const snippet = doc.metadata.snippet ?? '';
+  const body = snippet.slice(snippet.indexOf('{') + 1, snippet.lastIndexOf('}'));
+  return body
+    .split('\n')
+    .map((line) => line.replace(/\/\/.*$/, '').trim())
+    .filter((line) => line.length > 0);
+}
+
+/**
+ * Types of a parameter or result list, without names (`a, b int` is `int, int`)
+ */
+function parameterTypes(list: string): string[] {
+  const parts = splitTopLevel(list.replace(/\s+/g, ' '));
+  const named = parts.some(
+    (part) => /^\w+ \S/.test(part) && !/^(?:chan|func|map|struct|interface)\b/.test(part)
+  );
+  if (!named) return parts;
+
+  // Names share the type that follows them: in `a, b int` both are ints
+  const types: string[] = [];
+  let type = '';
+  for (const part of [...parts].reverse()) {
+    type = part.match(/^\w+ (.+)$/)?.[1] ?? type;
+    types.unshift(type);
+  }
+  return types;
+}
+
+/**
+ * Split a comma-separated list, ignoring commas inside brackets
+ */
+function splitTopLevel(list: string): string[] {
+  const parts: string[] = [];
+  let depth = 0;
+  let current = '';
+  for (const char of list) {
+    if ('([{'.includes(char)) depth++;
+    if (')]}'.includes(char)) depth--;
+    if (char === ',' && depth === 0) {
+      parts.push(current.trim());
+      current = '';
+    } else {
+      current += char;
+    }
+  }
+  if (current.trim()) parts.push(current.trim());
+  return parts;
+}
+
+/**
+ * Index of the parenthesis closing the one at `start`, or -1 if unbalanced
+ */
+function closingParen(text: string, start: number): number {
+  let depth = 0;
+  for (let i = start; i < text.length; i++) {
+    if (text[i] === '(') depth++;
+    if (text[i] === ')' && --depth === 0) return i;
+  }
+  return -1;
+}
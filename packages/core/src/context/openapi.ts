@@ -0,0 +1,283 @@
+/**
+ * OpenAPI
+ * Spec operations linked to the Go handlers implementing them, from the
+ * operations the OpenAPI scanner indexes (see scanner/openapi.ts) and the
+ * routes the Go scanner records (see routes.ts)
+ *
+ * Links are heuristic. An operation matches a route when the route serves its
+ * method (or any method) and their paths agree once parameters are written
+ * alike (`{id}`, `:id`, and `*rest` are all a parameter), either exactly or
+ * with one path mounted under a prefix of the other. An operationId matching
+ * the handler name adds confidence, or links the two on its own when the
+ * paths differ. Routes no operation matches are listed too, so drift between
+ * spec and implementation shows up in review.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { OpenApiOperation } from '../scanner/types';
+import type { SearchResult } from '../vector/types';
+import { buildRoutes, handlerText } from './routes';
+import { graphSymbols, type SymbolGraph, type SymbolGraphCache, shortName } from './symbol-graph';
+import type {
+  OpenApiHandlerMatch,
+  OpenApiMap,
+  OpenApiMatchConfidence,
+  OpenApiOperationEntry,
+  OpenApiOptions,
+  OpenApiSpecGroup,
+  RouteEntry,
+} from './types';
+
+/** Default maximum operations returned */
+export const DEFAULT_OPENAPI_LIMIT = 200;
+
+/** Routes listed per operation, among equally good matches */
+const MAX_MATCHES = 3;
+
+/** Routes without a spec operation listed in the markdown output */
+const MAX_UNSPECIFIED_LISTED = 20;
+
+/**
+ * List spec operations in indexed documents with the routes implementing them
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Path prefix, method, and tag filters, unlinked-only, limit
+ * @param graphs - Graph cache to reuse across calls
+ */
+export async function collectOpenApiOperations(
+  indexer: RepositoryIndexer,
+  options?: OpenApiOptions,
+  graphs?: SymbolGraphCache
+): Promise<OpenApiMap> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildOpenApiOperations(docs, options, graph);
+}
+
+/**
+ * List spec operations in a set of indexed documents with the routes implementing them
+ *
+ * @param symbolGraph - Graph over the same documents, to resolve handlers
+ */
+export function buildOpenApiOperations(
+  docs: SearchResult[],
+  options: OpenApiOptions = {},
+  symbolGraph?: SymbolGraph
+): OpenApiMap {
+  const { pathPrefix, tag, unlinkedOnly = false, limit = DEFAULT_OPENAPI_LIMIT } = options;
+  const method = options.method?.toUpperCase();
+
+  const specDocs = docs
+    .filter((doc) => doc.metadata.openApiOperation)
+    .sort(
+      (a, b) =>
+        (a.metadata.path ?? '').localeCompare(b.metadata.path ?? '') ||
+        (a.metadata.startLine ?? 0) - (b.metadata.startLine ?? 0)
+    );
+  const routes =
+    specDocs.length > 0
+      ? buildRoutes(docs, { limit: Number.MAX_SAFE_INTEGER }, symbolGraph).groups.flatMap(
+          (group) => group.routes
+        )
+      : [];
+
+  const operations = specDocs.map((doc) => linkOperation(doc, routes));
+  const implemented = new Set(operations.flatMap((op) => op.matches.map((m) => m.route)));
+
+  const matching = operations.filter((op) => {
+    const operation = op.spec.metadata.openApiOperation;
+    if (method && op.method !== method) return false;
+    if (pathPrefix && !op.path.startsWith(pathPrefix) && !op.fullPath.startsWith(pathPrefix)) {
+      return false;
+    }
+    if (tag && !operation?.tags?.includes(tag)) return false;
+    return !unlinkedOnly || op.matches.length === 0;
+  });
+  const included = matching.slice(0, limit);
+
+  const specs = new Map<string, OpenApiSpecGroup>();
+  for (const op of included) {
+    const file = op.spec.metadata.path ?? '';
+    let group = specs.get(file);
+    if (!group) {
+      group = { file, operations: [] };
+      specs.set(file, group);
+    }
+    group.operations.push(op);
+  }
+
+  return {
+    specs: [...specs.values()],
+    total: matching.length,
+    linked: matching.filter((op) => op.matches.length > 0).length,
+    omitted: matching.length - included.length,
+    unspecifiedRoutes: routes.filter((route) => !implemented.has(route)),
+  };
+}
+
+/**
+ * Format spec operations as markdown, one section per spec, then the routes
+ * no operation describes
+ */
+export function formatOpenApiOperations(openApiMap: OpenApiMap): string {
+  const specs = openApiMap.specs.length;
+  const lines = [
+    `# OpenAPI Operations (${openApiMap.total} in ${specs} ${specs === 1 ? 'spec' : 'specs'}, ` +
+      `${openApiMap.linked} linked to handlers)`,
+    '',
+  ];
+
+  for (const group of openApiMap.specs) {
+    lines.push(`## ${group.file}`, '');
+
+    for (const op of group.operations) {
+      const id = op.operationId ? ` ${op.operationId}` : '';
+      const summary = op.summary ? `: ${op.summary}` : '';
+      const line = op.spec.metadata.startLine;
+      lines.push(`- **${op.method} ${op.fullPath}**${id}${summary} (line ${line})`);
+      if (op.matches.length === 0) {
+        lines.push('  - no matching route');
+      }
+      for (const match of op.matches) {
+        const { route } = match;
+        lines.push(
+          `  - ${match.confidence} confidence: ${handlerText(route)}, ` +
+            `${route.method ?? 'ANY'} ${route.path} (${match.reason})`
+        );
+      }
+    }
+    lines.push('');
+  }
+
+  if (openApiMap.omitted > 0) {
+    const omitted = `${openApiMap.omitted} more operation(s) omitted`;
+    lines.push(`*${omitted}; narrow with a path prefix, method, or tag*`, '');
+  }
+
+  const unspecified = openApiMap.unspecifiedRoutes;
+  if (unspecified.length > 0) {
+    lines.push(`## Routes without a spec operation (${unspecified.length})`, '');
+    for (const route of unspecified.slice(0, MAX_UNSPECIFIED_LISTED)) {
+      lines.push(`- **${route.method ?? 'ANY'} ${route.path}** → ${handlerText(route)}`);
+    }
+    if (unspecified.length > MAX_UNSPECIFIED_LISTED) {
+      lines.push(`- ... and ${unspecified.length - MAX_UNSPECIFIED_LISTED} more`);
+    }
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+/**
+ * An operation document with its best matching routes
+ */
+function linkOperation(spec: SearchResult, routes: RouteEntry[]): OpenApiOperationEntry {
+  const operation = spec.metadata.openApiOperation ?? { method: '', path: '' };
+  const fullPath = `${operation.basePath ?? ''}${operation.path}`;
+
+  const scored = routes
+    .map((route) => scoreRoute(operation, fullPath, route))
+    .filter((match): match is OpenApiHandlerMatch & { score: number } => match !== null);
+  const best = Math.max(0, ...scored.map((match) => match.score));
+  const matches = scored
+    .filter((match) => match.score === best)
+    .slice(0, MAX_MATCHES)
+    .map(({ score: _score, ...match }) => match);
+
+  return {
+    spec,
+    method: operation.method,
+    path: operation.path,
+    fullPath,
+    ...(operation.operationId ? { operationId: operation.operationId } : {}),
+    ...(operation.summary ? { summary: operation.summary } : {}),
+    matches,
+  };
+}
+
+/**
+ * How well a route matches an operation; null when it doesn't
+ *
+ * An exact path counts 2 and a path under a prefix 1; a route serving the
+ * operation's method (rather than any method) and an operationId naming the
+ * handler count 1 each. 3 or more is high confidence, 2 medium, 1 low.
+ */
+function scoreRoute(
+  operation: OpenApiOperation,
+  fullPath: string,
+  route: RouteEntry
+): (OpenApiHandlerMatch & { score: number }) | null {
+  if (route.method && route.method !== operation.method) return null;
+
+  const routePath = normalizePath(route.path);
+  const specPaths = [operation.path, fullPath].map(normalizePath);
+  const exact = specPaths.includes(routePath);
+  const prefixed = !exact && specPaths.some((specPath) => underPrefix(specPath, routePath));
+  // The indexed handler's name, or the last identifier of the handler as written
+  const handlerName =
+    route.handlerSymbol?.metadata.name ?? route.handler?.match(/\w+(?=\W*$)/)?.[0];
+  const sameName =
+    !!operation.operationId &&
+    !!handlerName &&
+    nameKey(operation.operationId) !== '' &&
+    nameKey(operation.operationId) === nameKey(handlerName);
+  if (!exact && !prefixed && !sameName) return null;
+
+  const reasons: string[] = [];
+  if (exact) {
+    reasons.push(route.method ? 'method and path match' : 'path matches, route serves any method');
+  } else if (prefixed) {
+    reasons.push(
+      route.method
+        ? 'method matches, path matches under a prefix'
+        : 'path matches under a prefix, route serves any method'
+    );
+  } else if (route.method) {
+    reasons.push('method matches');
+  }
+  if (sameName) reasons.push('operationId matches the handler name');
+
+  const score = (exact ? 2 : prefixed ? 1 : 0) + (route.method ? 1 : 0) + (sameName ? 1 : 0);
+  return { route, confidence: confidenceFor(score), reason: reasons.join('; '), score };
+}
+
+function confidenceFor(score: number): OpenApiMatchConfidence {
+  if (score >= 3) return 'high';
+  return score === 2 ? 'medium' : 'low';
+}
+
+/**
+ * A path with parameters written alike, so `/users/{id}`, `/users/:id`, and
+ * `/users/{id...}` compare equal
+ */
+function normalizePath(routePath: string): string {
+  const segments = routePath
+    .split('/')
+    .filter(Boolean)
+    .map((segment) => (/^[{:*<]/.test(segment) ? '{}' : segment));
+  return `/${segments.join('/')}`;
+}
+
+/**
+ * Whether one path is the other mounted under a prefix; the shorter path needs
+ * a literal segment, or `/{id}` would match everything
+ */
+function underPrefix(a: string, b: string): boolean {
+  const [shorter, longer] = a.length <= b.length ? [a, b] : [b, a];
+  return (
+    shorter !== longer &&
+    longer.endsWith(shorter) &&
+    shorter.split('/').some((segment) => segment !== '' && segment !== '{}')
+  );
+}
+
+/**
+ * A name reduced for comparison: `createUser`, `CreateUser`, `handleCreateUser`,
+ * and `CreateUserHandler` all become `createuser`
+ */
+function nameKey(name: string): string {
+  return shortName(name)
+    .toLowerCase()
+    .replace(/[^a-z0-9]/g, '')
+    .replace(/^handle(r)?|handler$/g, '');
+}
@@ -0,0 +1,205 @@
+/**
+ * Package Outline
+ * Summarizes a package's types and how they relate, for onboarding
+ *
+ * Relationships come from indexed metadata: embedded struct fields and
+ * interfaces, method sets matched against the package's interfaces (by
+ * method name), and functions the scanner flagged as constructors. Exported
+ * surface comes first, and detail is capped so the outline fits a prompt.
+ */
+
+import * as path from 'node:path';
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import {
+  buildMethodSets,
+  embeddedTypes,
+  isExportedName,
+  packageDir,
+  resolvePackageDir,
+} from './method-sets';
+import { inTestFile } from './symbol-graph';
+import type { OutlineType, PackageOutline, PackageOutlineOptions } from './types';
+
+/** Default types shown in an outline */
+export const DEFAULT_OUTLINE_LIMIT = 30;
+
+/** Methods listed per type before the rest are counted */
+const MAX_LISTED_METHODS = 10;
+
+const TYPE_KINDS: Record<string, OutlineType['kind']> = {
+  class: 'struct',
+  interface: 'interface',
+  type: 'type',
+};
+
+/**
+ * Collect the outline of a package from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param pkg - Package directory (e.g. "internal/retry"), or a trailing part of it
+ * @param options - Visibility and size options
+ * @returns The outline, or null if no indexed file is in the package
+ */
+export async function collectPackageOutline(
+  indexer: RepositoryIndexer,
+  pkg: string,
+  options?: PackageOutlineOptions
+): Promise<PackageOutline | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  return buildPackageOutline(docs, pkg, options);
+}
+
+/**
+ * Build the outline of a package from a set of indexed documents
+ */
+export function buildPackageOutline(
+  docs: SearchResult[],
+  pkg: string,
+  options: PackageOutlineOptions = {}
+): PackageOutline | null {
+  const { includeUnexported = false, limit = DEFAULT_OUTLINE_LIMIT } = options;
+
+  const dir = resolvePackageDir(docs, pkg);
+  if (dir === null) return null;
+
+  const members = docs.filter(
+    (doc) => packageDir(doc) === dir && !inTestFile(doc.metadata.path ?? '') && doc.metadata.name
+  );
+  const visible = (doc: SearchResult) => includeUnexported || doc.metadata.exported !== false;
+
+  const typeDocs = members.filter((doc) => TYPE_KINDS[doc.metadata.type ?? '']);
+  const methodSets = new Map<string, Set<string>>();
+  for (const [type, methods] of buildMethodSets(members, typeDocs)) {
+    methodSets.set(type, new Set(methods.keys()));
+  }
+  const interfaces = typeDocs
+    .filter((doc) => doc.metadata.type === 'interface')
+    .map((doc) => doc.metadata.name as string)
+    .map((name) => ({ name, methods: methodSets.get(name) ?? new Set<string>() }));
+
+  const types = new Map<string, OutlineType>();
+  for (const doc of typeDocs.filter(visible)) {
+    const name = doc.metadata.name as string;
+    const kind = TYPE_KINDS[doc.metadata.type as string];
+    const methods = methodSets.get(name) ?? new Set<string>();
+    types.set(name, {
+      symbol: doc,
+      kind,
+      embeds: embeddedTypes(doc),
+      implements:
+        kind === 'interface'
+          ? []
+          : interfaces
+              .filter(({ methods: required }) => required.size > 0)
+              .filter(({ methods: required }) => [...required].every((m) => methods.has(m)))
+              .map(({ name: iface }) => iface),
+      implementedBy: [],
+      constructors: [],
+      methods: [...methods].filter((m) => includeUnexported || isExportedName(m)).sort(),
+    });
+  }
+
+  for (const type of types.values()) {
+    for (const iface of type.implements) {
+      types.get(iface)?.implementedBy.push(type.symbol.metadata.name as string);
+    }
+  }
+
+  const functions: SearchResult[] = [];
+  const isFunction = (doc: SearchResult) =>
+    doc.metadata.type === 'function' && !doc.metadata.funcLiteral;
+  for (const doc of members.filter((d) => isFunction(d) && visible(d))) {
+    const constructed = doc.metadata.constructs ? types.get(doc.metadata.constructs) : undefined;
+    if (constructed) {
+      constructed.constructors.push(doc.metadata.name as string);
+    } else {
+      functions.push(doc);
+    }
+  }
+
+  // Keep the most connected types when the outline is capped, then list them by name
+  const ranked = [...types.values()].sort((a, b) => weight(b) - weight(a) || byName(a, b));
+  const kept = ranked.slice(0, limit).sort(byName);
+  const sortedFunctions = functions.sort(compareNames);
+
+  return {
+    package: dir,
+    types: kept,
+    functions: sortedFunctions.slice(0, limit),
+    omittedTypes: ranked.length - kept.length,
+    omittedFunctions: Math.max(0, sortedFunctions.length - limit),
+  };
+}
+
+/**
+ * Format an outline as a compact markdown map
+ */
+export function formatPackageOutline(outline: PackageOutline): string {
+  const { types, functions } = outline;
+  const lines = [
+    `# Package ${outline.package || '.'}`,
+    '',
+    `${types.length + outline.omittedTypes} types, ` +
+      `${functions.length + outline.omittedFunctions} other functions`,
+    '',
+  ];
+
+  if (types.length > 0) {
+    lines.push('## Types', '');
+  }
+  for (const type of types) {
+    const { name, path: file, startLine, docstring } = type.symbol.metadata;
+    lines.push(`### ${name} (${type.kind}) - ${path.basename(file ?? '')}:${startLine}`);
+    const summary = docstring?.split('\n')[0].trim();
+    if (summary) lines.push(summary);
+    if (type.embeds.length > 0) lines.push(`- embeds: ${type.embeds.join(', ')}`);
+    if (type.implements.length > 0) lines.push(`- implements: ${type.implements.join(', ')}`);
+    if (type.implementedBy.length > 0) {
+      lines.push(`- implemented by: ${type.implementedBy.join(', ')}`);
+    }
+    if (type.constructors.length > 0) {
+      lines.push(`- constructed by: ${type.constructors.join(', ')}`);
+    }
+    if (type.methods.length > 0) {
+      const listed = type.methods.slice(0, MAX_LISTED_METHODS).join(', ');
+      const more = type.methods.length - MAX_LISTED_METHODS;
+      lines.push(`- methods: ${listed}${more > 0 ? ` (+${more} more)` : ''}`);
+    }
+    lines.push('');
+  }
+  if (outline.omittedTypes > 0) {
+    lines.push(`*${outline.omittedTypes} less connected types omitted*`, '');
+  }
+
+  if (functions.length > 0) {
+    lines.push('## Functions', '');
+    for (const fn of functions) {
+      lines.push(`- ${fn.metadata.signature ?? fn.metadata.name}`);
+    }
+    lines.push('');
+  }
+  if (outline.omittedFunctions > 0) {
+    lines.push(`*${outline.omittedFunctions} more functions omitted*`, '');
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function weight(type: OutlineType): number {
+  return (
+    type.embeds.length +
+    type.implements.length +
+    type.implementedBy.length +
+    type.constructors.length +
+    type.methods.length
+  );
+}
+
+function byName(a: OutlineType, b: OutlineType): number {
+  return compareNames(a.symbol, b.symbol);
+}
+
+function compareNames(a: SearchResult, b: SearchResult): number {
+  return (a.metadata.name ?? '').localeCompare(b.metadata.name ?? '');
+}
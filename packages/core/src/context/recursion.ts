@@ -0,0 +1,239 @@
+/**
+ * Recursion
+ * Finds functions that can reach themselves through calls, for stack-depth reviews
+ *
+ * Functions calling each other in a cycle form a strongly connected
+ * component of the call graph, so one pass of Tarjan's algorithm over the
+ * caller/callee edges finds every mutually recursive group. The graph leaves
+ * out self-calls, so functions calling themselves directly are read from
+ * those calls separately. Recursion isn't a bug, but an unbounded cycle is
+ * the first place to look for a stack overflow.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { qualifiedSymbolName } from './implementations';
+import { packageDir, resolvePackageDir } from './method-sets';
+import { graphSymbols, inTestFile, SymbolGraph, type SymbolGraphCache } from './symbol-graph';
+import type { RecursionOptions, RecursionReport, RecursiveCall, RecursiveCycle } from './types';
+
+/** Default maximum cycles in a report */
+export const DEFAULT_RECURSION_LIMIT = 50;
+
+/**
+ * Find recursive cycles from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Package, test inclusion, limit
+ * @param graphs - Graph cache to reuse across calls
+ * @returns The cycles, or null if the package isn't indexed
+ */
+export async function collectRecursion(
+  indexer: RepositoryIndexer,
+  options: RecursionOptions = {},
+  graphs?: SymbolGraphCache
+): Promise<RecursionReport | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildRecursion(docs, options, graph);
+}
+
+/**
+ * Find recursive cycles in a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildRecursion(
+  docs: SearchResult[],
+  options: RecursionOptions = {},
+  symbolGraph?: SymbolGraph
+): RecursionReport | null {
+  const { includeTests = false, limit = DEFAULT_RECURSION_LIMIT } = options;
+  const symbols = graphSymbols(docs).filter(
+    (symbol) => includeTests || !inTestFile(symbol.metadata.path ?? '')
+  );
+
+  let dir: string | null = null;
+  if (options.package !== undefined) {
+    dir = resolvePackageDir(symbols, options.package);
+    if (dir === null) return null;
+  }
+
+  const graph = symbolGraph ?? new SymbolGraph(graphSymbols(docs));
+  const included = new Set(symbols.map((symbol) => symbol.id));
+  const order = new Map(symbols.map((symbol, i) => [symbol.id, i]));
+  const callees = (symbol: SearchResult) =>
+    graph.calleesOf(symbol).filter((callee) => included.has(callee.id));
+
+  const cycles: RecursiveCycle[] = [];
+  for (const component of stronglyConnected(symbols, callees)) {
+    if (component.length === 1 && graph.selfCallsOf(component[0]).length === 0) continue;
+    if (dir !== null && !component.some((symbol) => packageDir(symbol) === dir)) continue;
+    component.sort((a, b) => (order.get(a.id) ?? 0) - (order.get(b.id) ?? 0));
+    cycles.push(toCycle(component, graph));
+  }
+  cycles.sort((a, b) => (order.get(a.symbols[0].id) ?? 0) - (order.get(b.symbols[0].id) ?? 0));
+
+  return {
+    scope: dir || '.',
+    cycles: cycles.slice(0, limit),
+    omitted: Math.max(cycles.length - limit, 0),
+  };
+}
+
+/**
+ * Format recursive cycles as markdown, mutual recursion first
+ */
+export function formatRecursion(report: RecursionReport): string {
+  const { cycles, scope } = report;
+  const where = scope === '.' ? 'the repository' : scope;
+  if (cycles.length === 0) {
+    return `No recursive functions found in ${where}.\n`;
+  }
+
+  const total = cycles.length + report.omitted;
+  const lines = [`# Recursion in ${where} (${total} ${total === 1 ? 'cycle' : 'cycles'})`, ''];
+
+  const mutual = cycles.filter((cycle) => cycle.kind === 'mutual');
+  if (mutual.length > 0) {
+    lines.push('## Mutual recursion', '');
+    for (const cycle of mutual) {
+      lines.push(`- **${cycle.path.join(' → ')}** (${cycle.symbols.length} functions)`);
+      for (const call of cycle.calls) {
+        const from = qualifiedSymbolName(call.caller);
+        const to = qualifiedSymbolName(call.callee);
+        lines.push(`  - ${from} calls ${to} (${call.caller.metadata.path}:${call.line})`);
+      }
+    }
+    lines.push('');
+  }
+
+  const self = cycles.filter((cycle) => cycle.kind === 'self');
+  if (self.length > 0) {
+    lines.push('## Self-recursive', '');
+    for (const cycle of self) {
+      const symbol = cycle.symbols[0];
+      const callLines = cycle.calls.map((call) => call.line).join(', ');
+      lines.push(
+        `- **${qualifiedSymbolName(symbol)}** calls itself (${symbol.metadata.path}:${callLines})`
+      );
+    }
+    lines.push('');
+  }
+
+  if (report.omitted > 0) {
+    lines.push(`*${report.omitted} more cycle(s) omitted; narrow with a package*`);
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function toCycle(symbols: SearchResult[], graph: SymbolGraph): RecursiveCycle {
+  const members = new Set(symbols.map((symbol) => symbol.id));
+  const calls: RecursiveCall[] = [];
+  for (const caller of symbols) {
+    const out = graph
+      .outgoingCalls(caller)
+      .filter(({ target }) => members.has(target.id))
+      .map(({ call, target }) => ({ caller, callee: target, line: call.line }));
+    const self = graph
+      .selfCallsOf(caller)
+      .map((call) => ({ caller, callee: caller, line: call.line }));
+    calls.push(...[...out, ...self].sort((a, b) => a.line - b.line));
+  }
+
+  const kind = symbols.length === 1 ? 'self' : 'mutual';
+  const path = kind === 'self' ? [symbols[0], symbols[0]] : shortestCycle(symbols[0], calls);
+  return { kind, symbols, path: path.map(qualifiedSymbolName), calls };
+}
+
+/**
+ * Shortest path of calls from a function back to itself, breadth first
+ */
+function shortestCycle(start: SearchResult, calls: RecursiveCall[]): SearchResult[] {
+  const previous = new Map<string, SearchResult>();
+  let frontier = [start];
+  while (frontier.length > 0) {
+    const next: SearchResult[] = [];
+    for (const symbol of frontier) {
+      for (const call of calls) {
+        if (call.caller.id !== symbol.id || call.callee.id === symbol.id) continue;
+        if (call.callee.id === start.id) {
+          const path = [start];
+          for (let at: SearchResult | undefined = symbol; at; at = previous.get(at.id)) {
+            path.unshift(at);
+          }
+          return path;
+        }
+        if (!previous.has(call.callee.id)) {
+          previous.set(call.callee.id, symbol);
+          next.push(call.callee);
+        }
+      }
+    }
+    frontier = next;
+  }
+  return [start, start];
+}
+
+/**
+ * Strongly connected components (Tarjan), iterative so deep call chains
+ * can't overflow the stack
+ */
+function stronglyConnected(
+  nodes: SearchResult[],
+  edges: (node: SearchResult) => SearchResult[]
+): SearchResult[][] {
+  const visits = new Map<string, { index: number; low: number }>();
+  const onStack = new Set<string>();
+  const stack: SearchResult[] = [];
+  const components: SearchResult[][] = [];
+
+  for (const root of nodes) {
+    if (visits.has(root.id)) continue;
+
+    const work: Array<{ node: SearchResult; next: SearchResult[]; i: number }> = [];
+    const visit = (node: SearchResult) => {
+      visits.set(node.id, { index: visits.size, low: visits.size });
+      stack.push(node);
+      onStack.add(node.id);
+      work.push({ node, next: edges(node), i: 0 });
+    };
+    visit(root);
+
+    while (work.length > 0) {
+      const frame = work[work.length - 1];
+      const state = visits.get(frame.node.id) as { index: number; low: number };
+      if (frame.i < frame.next.length) {
+        const target = frame.next[frame.i++];
+        const seen = visits.get(target.id);
+        if (!seen) {
+          visit(target);
+        } else if (onStack.has(target.id)) {
+          state.low = Math.min(state.low, seen.index);
+        }
+        continue;
+      }
+
+      work.pop();
+      const parent = work.at(-1);
+      if (parent) {
+        const parentState = visits.get(parent.node.id) as { index: number; low: number };
+        parentState.low = Math.min(parentState.low, state.low);
+      }
+      if (state.low === state.index) {
+        const component: SearchResult[] = [];
+        let member: SearchResult | undefined;
+        do {
+          member = stack.pop();
+          if (!member) break;
+          onStack.delete(member.id);
+          component.push(member);
+        } while (member.id !== frame.node.id);
+        components.push(component);
+      }
+    }
+  }
+
+  return components;
+}
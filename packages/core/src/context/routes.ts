@@ -0,0 +1,160 @@
+/**
+ * Routes
+ * The HTTP API surface of the indexed repository, from the route
+ * registrations the Go scanner records (see scanner/http-routes.ts)
+ *
+ * Each route links to its handler symbol when the handler resolves the way
+ * a call from the registering function would, and lists what the handler
+ * calls, so a request can be followed from "POST /users" into the code.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { packageDir } from './method-sets';
+import { graphSymbols, inTestFile, SymbolGraph, type SymbolGraphCache } from './symbol-graph';
+import type { RouteEntry, RouteGroup, RouteMap, RouteOptions } from './types';
+
+/** Default maximum routes returned */
+export const DEFAULT_ROUTE_LIMIT = 200;
+
+/** Handler callees listed per route */
+const MAX_HANDLER_CALLEES = 8;
+
+/**
+ * List the HTTP routes in indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Path prefix and method filters, test inclusion, limit
+ * @param graphs - Graph cache to reuse across calls
+ */
+export async function collectRoutes(
+  indexer: RepositoryIndexer,
+  options?: RouteOptions,
+  graphs?: SymbolGraphCache
+): Promise<RouteMap> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildRoutes(docs, options, graph);
+}
+
+/**
+ * List the HTTP routes in a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildRoutes(
+  docs: SearchResult[],
+  options: RouteOptions = {},
+  symbolGraph?: SymbolGraph
+): RouteMap {
+  const { pathPrefix, includeTests = false, limit = DEFAULT_ROUTE_LIMIT } = options;
+  const method = options.method?.toUpperCase();
+  const graph = symbolGraph ?? new SymbolGraph(graphSymbols(docs));
+
+  const matching: RouteEntry[] = [];
+  for (const doc of docs) {
+    if (!doc.metadata.routes) continue;
+    if (!includeTests && inTestFile(doc.metadata.path ?? '')) continue;
+
+    for (const route of doc.metadata.routes) {
+      if (pathPrefix && !route.path.startsWith(pathPrefix)) continue;
+      if (method && route.method && route.method !== method) continue;
+
+      const reference = route.handler ? handlerReference(route.handler) : null;
+      const handlerSymbol = reference ? graph.resolveName(reference, doc) : null;
+      matching.push({
+        ...(route.method ? { method: route.method } : {}),
+        path: route.path,
+        framework: route.framework,
+        registeredBy: doc,
+        line: route.line,
+        ...(route.handler ? { handler: route.handler } : {}),
+        ...(handlerSymbol ? { handlerSymbol } : {}),
+        callees: handlerSymbol ? graph.calleesOf(handlerSymbol).slice(0, MAX_HANDLER_CALLEES) : [],
+      });
+    }
+  }
+
+  matching.sort(
+    (a, b) =>
+      packageDir(a.registeredBy).localeCompare(packageDir(b.registeredBy)) ||
+      a.path.localeCompare(b.path) ||
+      (a.method ?? '').localeCompare(b.method ?? '')
+  );
+  const included = matching.slice(0, limit);
+
+  const groups = new Map<string, RouteGroup>();
+  for (const route of included) {
+    const pkg = packageDir(route.registeredBy);
+    let group = groups.get(pkg);
+    if (!group) {
+      const { module } = route.registeredBy.metadata;
+      group = { package: pkg, ...(module ? { module } : {}), routes: [] };
+      groups.set(pkg, group);
+    }
+    group.routes.push(route);
+  }
+
+  return {
+    groups: [...groups.values()],
+    total: matching.length,
+    omitted: matching.length - included.length,
+  };
+}
+
+/**
+ * Format routes as markdown, one section per package
+ */
+export function formatRoutes(routeMap: RouteMap): string {
+  const packages = routeMap.groups.length;
+  const lines = [
+    `# HTTP Routes (${routeMap.total} in ${packages} ${packages === 1 ? 'package' : 'packages'})`,
+    '',
+  ];
+
+  for (const group of routeMap.groups) {
+    const module = group.module ? ` (module ${group.module})` : '';
+    lines.push(`## ${group.package || '.'}${module}`, '');
+
+    for (const route of group.routes) {
+      const { name, path: file } = route.registeredBy.metadata;
+      lines.push(`- **${route.method ?? 'ANY'} ${route.path}** → ${handlerText(route)}`);
+      lines.push(`  - registered in ${name} (${file}:${route.line}, ${route.framework})`);
+      if (route.callees.length > 0) {
+        const callees = route.callees.map((callee) => callee.metadata.name).join(', ');
+        lines.push(`  - calls ${callees}`);
+      }
+    }
+    lines.push('');
+  }
+
+  if (routeMap.omitted > 0) {
+    lines.push(`*${routeMap.omitted} more route(s) omitted; narrow with a path prefix or method*`);
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+/**
+ * The function a handler expression refers to: the handler itself (`h.Get`),
+ * the handler a middleware wraps (`auth(h.Get)`), or a handler factory
+ * (`metricsHandler()`)
+ */
+function handlerReference(handler: string): string | null {
+  const wrapped = handler.match(/\(([\w.]+)\)$/);
+  if (wrapped) return wrapped[1];
+  const factory = handler.match(/^([\w.]+)\(\)$/);
+  if (factory) return factory[1];
+  return /^[\w.]+$/.test(handler) ? handler : null;
+}
+
+/**
+ * A route's handler as markdown: the indexed symbol and where it is, or the
+ * handler as written when it isn't indexed
+ */
+export function handlerText(route: RouteEntry): string {
+  if (!route.handler) return 'inline handler';
+  if (!route.handlerSymbol) return `\`${route.handler}\` (not indexed)`;
+  const { name, path: file, startLine } = route.handlerSymbol.metadata;
+  return `${name} (${file}:${startLine})`;
+}
@@ -0,0 +1,134 @@
+/**
+ * SQL Queries
+ * The SQL the indexed repository runs, from the queries the Go scanner
+ * records in string literals (see scanner/sql-queries.ts)
+ *
+ * Answers "which functions run queries against the users table": every
+ * query links to the function or method containing it, and the tables the
+ * matching queries name are counted so the schema a package touches is
+ * visible at a glance.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import { queriesTable } from '../scanner/sql-queries';
+import type { SearchResult } from '../vector/types';
+import { packageDir } from './method-sets';
+import { inTestFile } from './symbol-graph';
+import type { SqlQueryEntry, SqlQueryGroup, SqlQueryMap, SqlQueryOptions } from './types';
+
+/** Default maximum queries returned */
+export const DEFAULT_SQL_QUERY_LIMIT = 200;
+
+/**
+ * List the SQL queries in indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Table, operation, and path filters, test inclusion, limit
+ */
+export async function collectSqlQueries(
+  indexer: RepositoryIndexer,
+  options?: SqlQueryOptions
+): Promise<SqlQueryMap> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  return buildSqlQueries(docs, options);
+}
+
+/**
+ * List the SQL queries in a set of indexed documents
+ */
+export function buildSqlQueries(docs: SearchResult[], options: SqlQueryOptions = {}): SqlQueryMap {
+  const {
+    table,
+    operation,
+    pathPrefix,
+    includeTests = false,
+    limit = DEFAULT_SQL_QUERY_LIMIT,
+  } = options;
+
+  const matching: SqlQueryEntry[] = [];
+  for (const doc of docs) {
+    if (!doc.metadata.sqlQueries) continue;
+    const file = doc.metadata.path ?? '';
+    if (!includeTests && inTestFile(file)) continue;
+    if (pathPrefix && !file.startsWith(pathPrefix)) continue;
+
+    for (const query of doc.metadata.sqlQueries) {
+      if (operation && query.operation !== operation) continue;
+      if (table && !queriesTable(query, table)) continue;
+      matching.push({ ...query, runBy: doc });
+    }
+  }
+
+  matching.sort(
+    (a, b) =>
+      packageDir(a.runBy).localeCompare(packageDir(b.runBy)) ||
+      (a.runBy.metadata.name ?? '').localeCompare(b.runBy.metadata.name ?? '') ||
+      a.line - b.line
+  );
+  const included = matching.slice(0, limit);
+
+  const tableCounts = new Map<string, number>();
+  for (const query of matching) {
+    for (const name of query.tables) {
+      tableCounts.set(name, (tableCounts.get(name) ?? 0) + 1);
+    }
+  }
+
+  const groups = new Map<string, SqlQueryGroup>();
+  for (const query of included) {
+    const pkg = packageDir(query.runBy);
+    let group = groups.get(pkg);
+    if (!group) {
+      const { module } = query.runBy.metadata;
+      group = { package: pkg, ...(module ? { module } : {}), queries: [] };
+      groups.set(pkg, group);
+    }
+    group.queries.push(query);
+  }
+
+  return {
+    groups: [...groups.values()],
+    tables: [...tableCounts]
+      .map(([name, queries]) => ({ name, queries }))
+      .sort((a, b) => b.queries - a.queries || a.name.localeCompare(b.name)),
+    total: matching.length,
+    omitted: matching.length - included.length,
+  };
+}
+
+/**
+ * Format SQL queries as markdown: a table summary, then one section per package
+ */
+export function formatSqlQueries(queryMap: SqlQueryMap): string {
+  const packages = queryMap.groups.length;
+  const lines = [
+    `# SQL Queries (${queryMap.total} in ${packages} ${packages === 1 ? 'package' : 'packages'})`,
+    '',
+  ];
+
+  if (queryMap.tables.length > 0) {
+    const tables = queryMap.tables.map((t) => `${t.name} (${t.queries})`).join(', ');
+    lines.push(`**Tables:** ${tables}`, '');
+  }
+
+  for (const group of queryMap.groups) {
+    const module = group.module ? ` (module ${group.module})` : '';
+    lines.push(`## ${group.package || '.'}${module}`, '');
+
+    for (const query of group.queries) {
+      const { name, path: file } = query.runBy.metadata;
+      const tables = query.tables.length > 0 ? query.tables.join(', ') : 'unknown tables';
+      const operation = query.operation.toUpperCase();
+      lines.push(`- **${operation}** ${tables} in ${name} (${file}:${query.line})`);
+      lines.push(`  - \`${query.query}\``);
+    }
+    lines.push('');
+  }
+
+  if (queryMap.omitted > 0) {
+    const queries = queryMap.omitted === 1 ? 'query' : 'queries';
+    lines.push(`*${queryMap.omitted} more ${queries} omitted; narrow with a table or path prefix*`);
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
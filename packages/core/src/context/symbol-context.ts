@@ -0,0 +1,194 @@
+/**
+ * Symbol Context
+ * Assembles a symbol's source with its callers, callees, and referenced types
+ *
+ * Relationships are followed breadth-first up to a hop depth, deduplicated
+ * across hops, and packed into a token budget closest hops first. When a
+ * symbol's full source doesn't fit, its signature is used instead.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import { estimateTokenCount } from '../indexer/utils/truncation';
+import type { SearchResult } from '../vector/types';
+import { findTarget, graphSymbols, SymbolGraph, type SymbolGraphCache } from './symbol-graph';
+import type { ContextEntry, ContextRelation, SymbolContext, SymbolContextOptions } from './types';
+
+/** Default token budget for assembled context */
+export const DEFAULT_CONTEXT_MAX_TOKENS = 4000;
+
+/** Order of relationships within a hop */
+const RELATION_ORDER: Record<ContextRelation, number> = {
+  target: 0,
+  callee: 1,
+  caller: 2,
+  type: 3,
+};
+
+/** A related symbol before its source is fitted into the budget */
+type ReachedSymbol = Omit<ContextEntry, 'source' | 'signatureOnly'>;
+
+/**
+ * Assemble context for a symbol from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param name - Symbol name (e.g. "retryRequest" or "Client.Do")
+ * @param options - Depth, token budget, and disambiguation options
+ * @param graphs - Graph cache to reuse across calls
+ * @returns The assembled context, or null if the symbol isn't indexed
+ */
+export async function assembleSymbolContext(
+  indexer: RepositoryIndexer,
+  name: string,
+  options?: SymbolContextOptions,
+  graphs?: SymbolGraphCache
+): Promise<SymbolContext | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildSymbolContext(docs, name, options, graph);
+}
+
+/**
+ * Build context for a symbol from a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildSymbolContext(
+  docs: SearchResult[],
+  name: string,
+  options: SymbolContextOptions = {},
+  symbolGraph?: SymbolGraph
+): SymbolContext | null {
+  const { depth = 1, maxTokens = DEFAULT_CONTEXT_MAX_TOKENS, includeTypes = true } = options;
+
+  const symbols = graphSymbols(docs);
+  const target = findTarget(symbols, name, options.path);
+  if (!target) return null;
+
+  const graph = symbolGraph ?? new SymbolGraph(symbols);
+  const seen = new Set([target.id]);
+  const reached: ReachedSymbol[] = [];
+  let frontier = [target];
+
+  for (let hop = 1; hop <= depth && frontier.length > 0; hop++) {
+    const next: SearchResult[] = [];
+    for (const current of frontier) {
+      const related: Array<{ symbol: SearchResult; relation: ContextRelation }> = [
+        ...graph.calleesOf(current).map((symbol) => ({ symbol, relation: 'callee' as const })),
+        ...graph.callersOf(current).map((symbol) => ({ symbol, relation: 'caller' as const })),
+        ...(includeTypes ? graph.typesReferencedBy(current) : []).map((symbol) => ({
+          symbol,
+          relation: 'type' as const,
+        })),
+      ];
+
+      for (const { symbol, relation } of related) {
+        if (seen.has(symbol.id)) continue;
+        seen.add(symbol.id);
+        reached.push({ symbol, relation, hop, via: current.metadata.name });
+        next.push(symbol);
+      }
+    }
+    frontier = next;
+  }
+
+  // Stable sort keeps discovery order within a hop and relationship
+  reached.sort((a, b) => a.hop - b.hop || RELATION_ORDER[a.relation] - RELATION_ORDER[b.relation]);
+
+  let remaining = maxTokens;
+  const fit = (entry: ReachedSymbol): ContextEntry | null => {
+    const full = sourceOf(entry.symbol);
+    const signature = entry.symbol.metadata.signature ?? full.split('\n')[0];
+    for (const source of full === signature ? [full] : [full, signature]) {
+      const tokens = estimateTokenCount(source);
+      if (tokens <= remaining) {
+        remaining -= tokens;
+        return { ...entry, source, signatureOnly: source !== full };
+      }
+    }
+    return null;
+  };
+
+  // The target is always included, as a signature if nothing else fits
+  const targetEntry = fit({ symbol: target, relation: 'target', hop: 0 }) ?? {
+    symbol: target,
+    relation: 'target' as const,
+    hop: 0,
+    source: target.metadata.signature ?? '',
+    signatureOnly: true,
+  };
+
+  const entries: ContextEntry[] = [];
+  let omitted = 0;
+  for (const entry of reached) {
+    const included = fit(entry);
+    if (included) {
+      entries.push(included);
+    } else {
+      omitted++;
+    }
+  }
+
+  return {
+    target: targetEntry,
+    entries,
+    omitted,
+    tokens: Math.max(0, maxTokens - remaining),
+  };
+}
+
+/**
+ * Format assembled context as markdown, grouped by hop and relationship
+ */
+export function formatSymbolContext(context: SymbolContext): string {
+  const lines: string[] = [];
+  const { target } = context;
+
+  lines.push(`# Context for ${target.symbol.metadata.name}`);
+  lines.push('');
+  lines.push(...formatEntry(target));
+
+  const headings: Record<Exclude<ContextRelation, 'target'>, string> = {
+    callee: 'Callees',
+    caller: 'Callers',
+    type: 'Referenced Types',
+  };
+
+  let section = '';
+  for (const entry of context.entries) {
+    const heading = `## Hop ${entry.hop}: ${headings[entry.relation as keyof typeof headings]}`;
+    if (heading !== section) {
+      section = heading;
+      lines.push(heading, '');
+    }
+    lines.push(...formatEntry(entry));
+  }
+
+  if (context.omitted > 0) {
+    lines.push(
+      `*${context.omitted} more related symbol(s) omitted to stay within the token budget*`
+    );
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function formatEntry(entry: ContextEntry): string[] {
+  const { name, type, path: file, startLine, endLine, language } = entry.symbol.metadata;
+  const location = `${file}:${startLine}-${endLine}`;
+  const via = entry.via ? ` via \`${entry.via}\`` : '';
+  const note = entry.signatureOnly ? ' (signature only)' : '';
+  const heading = entry.relation === 'target' ? '##' : '###';
+
+  return [
+    `${heading} ${name} (${type}) - ${location}${via}${note}`,
+    '',
+    `\`\`\`${language ?? ''}`,
+    entry.source,
+    '```',
+    '',
+  ];
+}
+
+function sourceOf(symbol: SearchResult): string {
+  return symbol.metadata.snippet ?? symbol.metadata.signature ?? '';
+}
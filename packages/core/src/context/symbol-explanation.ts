@@ -0,0 +1,154 @@
+/**
+ * Symbol Explanation
+ * "What does this do and how is it used" in one compact block
+ *
+ * Where an inspection is exhaustive, an explanation is curated for reading:
+ * the doc comment and signature, a couple of usage examples, and the direct
+ * callees with the first sentence of their docs. Go `Example` functions for
+ * the symbol fill the example slots first, since they are the package's own
+ * documentation; real call sites (from symbol-usage.ts, most varied argument
+ * shapes first, non-test before test) fill the rest, read from the current
+ * source when a SymbolSourceReader is given.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import { summarizeDoc } from '../surface';
+import type { SearchResult } from '../vector/types';
+import { packageDir } from './method-sets';
+import { graphSymbols, SymbolGraph, type SymbolGraphCache } from './symbol-graph';
+import type { SymbolSourceReader } from './symbol-source';
+import { buildSymbolUsages, formatStaleFiles } from './symbol-usage';
+import type { SymbolExplanation, SymbolExplanationOptions } from './types';
+
+/** Default usage examples in an explanation */
+export const DEFAULT_EXPLANATION_EXAMPLES = 2;
+
+/** Default callees listed in an explanation */
+export const DEFAULT_EXPLANATION_CALLEES = 10;
+
+/**
+ * Explain a symbol from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param name - Symbol name (e.g. "NewExpBackoff" or "Client.Do")
+ * @param options - Example and callee limits, and disambiguation options
+ * @param graphs - Graph cache to reuse across calls
+ * @param sources - Reader for callers' full source; indexed snippets when omitted
+ * @returns The explanation, or null if the symbol isn't indexed
+ */
+export async function collectSymbolExplanation(
+  indexer: RepositoryIndexer,
+  name: string,
+  options?: SymbolExplanationOptions,
+  graphs?: SymbolGraphCache,
+  sources?: SymbolSourceReader
+): Promise<SymbolExplanation | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildSymbolExplanation(docs, name, options, graph, sources);
+}
+
+/**
+ * Explain a symbol from a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ * @param sources - Reader for callers' full source; indexed snippets when omitted
+ */
+export function buildSymbolExplanation(
+  docs: SearchResult[],
+  name: string,
+  options: SymbolExplanationOptions = {},
+  symbolGraph?: SymbolGraph,
+  sources?: SymbolSourceReader
+): SymbolExplanation | null {
+  const {
+    examples: exampleLimit = DEFAULT_EXPLANATION_EXAMPLES,
+    maxCallees = DEFAULT_EXPLANATION_CALLEES,
+  } = options;
+  const graph = symbolGraph ?? new SymbolGraph(graphSymbols(docs));
+
+  const usages = buildSymbolUsages(
+    docs,
+    name,
+    {
+      path: options.path,
+      limit: exampleLimit,
+      includeTests: true,
+      contextLines: options.contextLines,
+    },
+    graph,
+    sources
+  );
+  if (!usages) return null;
+
+  const documentedExamples = usages.documentedExamples.slice(0, exampleLimit);
+  // A call site past the indexed snippet has no source to show, so it explains nothing
+  const examples = [...usages.examples, ...usages.testExamples]
+    .filter((example) => example.source)
+    .slice(0, exampleLimit - documentedExamples.length);
+  const callees = graph.calleesOf(usages.target);
+
+  return {
+    target: usages.target,
+    package: packageDir(usages.target),
+    documentedExamples,
+    examples,
+    callees: callees.slice(0, maxCallees),
+    omittedCallees: Math.max(0, callees.length - maxCallees),
+    totalCalls: usages.totalCalls + usages.totalTestCalls,
+    staleFiles: usages.staleFiles,
+  };
+}
+
+/**
+ * Format an explanation as markdown: doc and signature, examples, callees
+ */
+export function formatSymbolExplanation(explanation: SymbolExplanation): string {
+  const { target } = explanation;
+  const { name, type, path: file, startLine, language, signature, docstring } = target.metadata;
+  const lines = [`# ${name} (${type}) - ${file}:${startLine}`, ''];
+
+  if (signature) lines.push(`\`\`\`${language ?? ''}`, signature, '```', '');
+  lines.push(docstring?.trim() || '*No doc comment.*', '');
+
+  for (const doc of explanation.documentedExamples) {
+    const { example } = doc.metadata;
+    if (!example) continue;
+    lines.push(`## Example: ${doc.metadata.name} - ${doc.metadata.path}:${doc.metadata.startLine}`);
+    lines.push('', '```go', example.code, '```', '');
+    if (example.output !== undefined) {
+      const label = example.unordered ? 'Output (any order)' : 'Output';
+      lines.push(`${label}:`, '', '```', example.output, '```', '');
+    }
+  }
+
+  for (const example of explanation.examples) {
+    const { name: caller, path: at, language: callerLanguage } = example.caller.metadata;
+    const where = example.isTest ? ' (test)' : '';
+    lines.push(`## Used in ${caller}${where} - ${at}:${example.line}`, '');
+    lines.push(`\`\`\`${callerLanguage ?? ''}`, example.source, '```', '');
+  }
+
+  const shown = explanation.documentedExamples.length + explanation.examples.length;
+  if (explanation.totalCalls === 0) {
+    lines.push(shown > 0 ? 'No call sites in the index.' : 'No usages in the index.', '');
+  } else if (explanation.totalCalls > explanation.examples.length) {
+    lines.push(`Called from ${explanation.totalCalls} place(s) in the index.`, '');
+  }
+  lines.push(...formatStaleFiles(explanation.staleFiles));
+
+  if (explanation.callees.length > 0) {
+    const total = explanation.callees.length + explanation.omittedCallees;
+    lines.push(`## Calls (${total})`, '');
+    for (const callee of explanation.callees) {
+      const { name: calleeName, path: at, startLine: line, docstring: doc } = callee.metadata;
+      const summary = summarizeDoc(doc);
+      lines.push(`- ${calleeName} - ${at}:${line}${summary ? `: ${summary}` : ''}`);
+    }
+    if (explanation.omittedCallees > 0) {
+      lines.push(`- ...and ${explanation.omittedCallees} more`);
+    }
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
@@ -0,0 +1,606 @@
+/**
+ * Symbol Graph
+ * Caller/callee resolution over indexed symbols, shared by context and usage lookups
+ */
+
+import * as crypto from 'node:crypto';
+import * as path from 'node:path';
+import { canImportInternal } from '../scanner/go-modules';
+import type { CalleeInfo } from '../scanner/types';
+import { isTestFile } from '../utils/test-utils';
+import type { SearchResult } from '../vector/types';
+
+/** Document types that declare a named type */
+const TYPE_KINDS = new Set(['class', 'interface', 'type']);
+
+/**
+ * A resolved call from one indexed symbol to another
+ */
+export interface CallSite {
+  /** The calling symbol */
+  caller: SearchResult;
+  /** The call as recorded by the scanner (name as written, line) */
+  call: CalleeInfo;
+}
+
+/**
+ * A Go call that would only resolve into an `internal` package the caller
+ * can't import: left unresolved, and a possible layering violation
+ */
+export interface InternalViolation {
+  /** The calling symbol */
+  caller: SearchResult;
+  /** The call as recorded by the scanner */
+  call: CalleeInfo;
+  /** The symbol the call would have reached */
+  target: SearchResult;
+}
+
+/**
+ * A call resolved to the ID of the symbol it reaches
+ */
+interface ResolvedCall {
+  call: CalleeInfo;
+  target: string;
+}
+
+/**
+ * Caller, callee, and type relationships between indexed symbols
+ *
+ * Edges are kept by symbol ID, so a file can be re-indexed with `update`
+ * without rebuilding the graph for the whole repository.
+ */
+export class SymbolGraph {
+  private readonly symbols = new Map<string, SearchResult>();
+  /** Position of each symbol in the input, so results keep a stable order */
+  private readonly order = new Map<string, number>();
+  private nextOrder = 0;
+  private readonly byFile = new Map<string, Set<string>>();
+  private readonly byShortName = new Map<string, SearchResult[]>();
+  private readonly typesByName = new Map<string, SearchResult[]>();
+  /** Resolved calls out of each symbol, self-calls excluded */
+  private readonly calls = new Map<string, ResolvedCall[]>();
+  /** Symbols with at least one resolved call into each symbol */
+  private readonly callerIds = new Map<string, Set<string>>();
+  /** Symbols making a call by each short name, whether it resolved or not */
+  private readonly callersByName = new Map<string, Set<string>>();
+  /** Calls out of each symbol that only reach across an `internal` boundary */
+  private readonly violations = new Map<string, InternalViolation[]>();
+  /** Calls out of each symbol that resolve to the symbol itself */
+  private readonly selfCalls = new Map<string, CalleeInfo[]>();
+
+  constructor(symbols: SearchResult[]) {
+    for (const symbol of symbols) {
+      this.insert(symbol);
+    }
+    for (const symbol of symbols) {
+      this.resolve(symbol);
+    }
+  }
+
+  calleesOf(symbol: SearchResult): SearchResult[] {
+    const targets = new Set((this.calls.get(symbol.id) ?? []).map((c) => c.target));
+    return Array.from(targets, (id) => this.symbols.get(id)).filter(isDefined);
+  }
+
+  callersOf(symbol: SearchResult): SearchResult[] {
+    return this.sortedCallerIds(symbol.id)
+      .map((id) => this.symbols.get(id))
+      .filter(isDefined);
+  }
+
+  /**
+   * Every resolved call out of a symbol, with the symbol each one reaches
+   */
+  outgoingCalls(symbol: SearchResult): { call: CalleeInfo; target: SearchResult }[] {
+    return (this.calls.get(symbol.id) ?? []).flatMap(({ call, target }) => {
+      const reached = this.symbols.get(target);
+      return reached ? [{ call, target: reached }] : [];
+    });
+  }
+
+  /**
+   * Calls a symbol makes to itself, which the caller and callee edges leave out
+   */
+  selfCallsOf(symbol: SearchResult): CalleeInfo[] {
+    return this.selfCalls.get(symbol.id) ?? [];
+  }
+
+  /**
+   * Every resolved call to a symbol, including repeated calls from one caller
+   */
+  callSitesOf(symbol: SearchResult): CallSite[] {
+    const sites: CallSite[] = [];
+    for (const callerId of this.sortedCallerIds(symbol.id)) {
+      const caller = this.symbols.get(callerId);
+      for (const resolved of this.calls.get(callerId) ?? []) {
+        if (caller && resolved.target === symbol.id) {
+          sites.push({ caller, call: resolved.call });
+        }
+      }
+    }
+    return sites;
+  }
+
+  /**
+   * Calls left unresolved because they would cross Go's `internal` boundary,
+   * out of one symbol or, without one, the whole graph in input order
+   */
+  internalViolations(symbol?: SearchResult): InternalViolation[] {
+    if (symbol) return this.violations.get(symbol.id) ?? [];
+    const order = (id: string) => this.order.get(id) ?? 0;
+    return Array.from(this.violations.keys())
+      .sort((a, b) => order(a) - order(b))
+      .flatMap((id) => this.violations.get(id) ?? []);
+  }
+
+  /**
+   * Calls out of a symbol to names no indexed symbol has: the standard
+   * library, third-party packages, and builtins
+   */
+  externalCallsOf(symbol: SearchResult): CalleeInfo[] {
+    return (symbol.metadata.callees ?? []).filter(
+      (callee) => !this.byShortName.has(shortName(callee.name))
+    );
+  }
+
+  /**
+   * Resolve a name as written in a symbol's body, the way its calls resolve
+   *
+   * @returns The symbol the name refers to, or null if it's unknown or ambiguous
+   */
+  resolveName(name: string, from: SearchResult): SearchResult | null {
+    const candidates = (this.byShortName.get(shortName(name)) ?? []).filter((candidate) =>
+      importable(from, candidate)
+    );
+    return this.resolveCallee({ name, line: 0 }, from, candidates);
+  }
+
+  /**
+   * Symbols whose name, or method name after the receiver, is exactly `name`
+   */
+  symbolsNamed(name: string): SearchResult[] {
+    return this.byShortName.get(name) ?? [];
+  }
+
+  /**
+   * Indexed types named in a symbol's signature or body
+   *
+   * Types the scanner recorded (`referencesTypes`, Go) are resolved in the
+   * symbol's package or the one they're qualified with, in name order. Other
+   * symbols fall back to identifiers in their source, in order of first use.
+   */
+  typesReferencedBy(symbol: SearchResult): SearchResult[] {
+    const recorded = symbol.metadata.referencesTypes;
+    if (recorded) {
+      return recorded.flatMap((reference) => {
+        const candidates = this.typesByName.get(shortName(reference)) ?? [];
+        const match = candidates.find((type) => referencesType(reference, symbol, type));
+        return match && match.id !== symbol.id ? [match] : [];
+      });
+    }
+
+    const text = `${symbol.metadata.signature ?? ''}\n${symbol.metadata.snippet ?? ''}`;
+    const types = new Map<string, SearchResult>();
+    for (const [identifier] of text.matchAll(/[A-Za-z_][A-Za-z0-9_]*/g)) {
+      const candidates = this.typesByName.get(identifier);
+      if (!candidates || types.has(identifier) || identifier === symbol.metadata.name) continue;
+      const match = closest(candidates, symbol);
+      if (match.id !== symbol.id) {
+        types.set(identifier, match);
+      }
+    }
+    return Array.from(types.values());
+  }
+
+  /**
+   * Replace the symbols of re-indexed files and patch the edges they touch
+   *
+   * Calls out of those files are dropped and resolved again. Calls into them
+   * from other files are resolved again only when they could now resolve
+   * differently: their target was removed or re-declared (a rename is a
+   * removal plus an addition), or a symbol with the called name was added or
+   * removed, which can make a call resolve, stop resolving, or turn ambiguous.
+   * Calls to symbols whose declaration didn't change keep their edges.
+   *
+   * @param files - Changed, added, and deleted files
+   * @param symbols - Current symbols of those files (none for deleted files)
+   */
+  update(files: Iterable<string>, symbols: SearchResult[]): void {
+    const changedFiles = new Set(files);
+    const next = new Map(symbols.map((symbol) => [symbol.id, symbol]));
+    const previous: SearchResult[] = [];
+    for (const file of changedFiles) {
+      for (const id of this.byFile.get(file) ?? []) {
+        const symbol = this.symbols.get(id);
+        if (symbol) previous.push(symbol);
+      }
+    }
+
+    // Short names whose candidates change, and callers that must resolve again
+    const changedNames = new Set<string>();
+    const stale = new Set<string>();
+    for (const before of previous) {
+      const after = next.get(before.id);
+      if (after && !declarationChanged(before, after)) continue;
+      changedNames.add(shortName(before.metadata.name ?? ''));
+      for (const callerId of this.callerIds.get(before.id) ?? []) {
+        stale.add(callerId);
+      }
+    }
+    const previousIds = new Set(previous.map((symbol) => symbol.id));
+    for (const symbol of symbols) {
+      const before = this.symbols.get(symbol.id);
+      if (!previousIds.has(symbol.id) || (before && declarationChanged(before, symbol))) {
+        changedNames.add(shortName(symbol.metadata.name ?? ''));
+      }
+    }
+    for (const name of changedNames) {
+      for (const callerId of this.callersByName.get(name) ?? []) {
+        stale.add(callerId);
+      }
+    }
+
+    for (const before of previous) {
+      this.unresolve(before.id);
+      this.remove(before.id, next.has(before.id));
+    }
+    for (const symbol of symbols) {
+      this.insert(symbol);
+    }
+
+    for (const callerId of stale) {
+      const caller = this.symbols.get(callerId);
+      if (!caller || changedFiles.has(caller.metadata.path ?? '')) continue;
+      this.unresolve(callerId);
+      this.resolve(caller);
+    }
+    for (const symbol of symbols) {
+      this.resolve(symbol);
+    }
+
+    // Nothing can still call a removed symbol; drop its entry so none dangle
+    for (const before of previous) {
+      if (!next.has(before.id)) {
+        this.callerIds.delete(before.id);
+      }
+    }
+  }
+
+  private insert(symbol: SearchResult): void {
+    const name = symbol.metadata.name ?? '';
+    this.symbols.set(symbol.id, symbol);
+    if (!this.order.has(symbol.id)) {
+      this.order.set(symbol.id, this.nextOrder++);
+    }
+    addToSet(this.byFile, symbol.metadata.path ?? '', symbol.id);
+    append(this.byShortName, shortName(name), symbol);
+    if (TYPE_KINDS.has(String(symbol.metadata.type))) {
+      append(this.typesByName, name, symbol);
+    }
+  }
+
+  /**
+   * Remove a symbol from the name indexes
+   *
+   * @param keepPosition - The symbol is re-inserted right after, so it keeps its place in results
+   */
+  private remove(id: string, keepPosition: boolean): void {
+    const symbol = this.symbols.get(id);
+    if (!symbol) return;
+    const name = symbol.metadata.name ?? '';
+    this.symbols.delete(id);
+    if (!keepPosition) {
+      this.order.delete(id);
+    }
+    removeFromSet(this.byFile, symbol.metadata.path ?? '', id);
+    removeFromList(this.byShortName, shortName(name), id);
+    removeFromList(this.typesByName, name, id);
+  }
+
+  /**
+   * Resolve a symbol's calls and record the edges
+   */
+  private resolve(symbol: SearchResult): void {
+    const resolved: ResolvedCall[] = [];
+    const violations: InternalViolation[] = [];
+    const selfCalls: CalleeInfo[] = [];
+    for (const callee of symbol.metadata.callees ?? []) {
+      addToSet(this.callersByName, shortName(callee.name), symbol.id);
+      const candidates = this.byShortName.get(shortName(callee.name)) ?? [];
+      const visible = candidates.filter((candidate) => importable(symbol, candidate));
+      const match = this.resolveCallee(callee, symbol, visible);
+      if (match && match.id !== symbol.id) {
+        resolved.push({ call: callee, target: match.id });
+        addToSet(this.callerIds, match.id, symbol.id);
+      } else if (match) {
+        selfCalls.push(callee);
+      } else if (!match && visible.length < candidates.length) {
+        const hidden = this.resolveCallee(callee, symbol, candidates);
+        if (hidden && hidden.id !== symbol.id) {
+          violations.push({ caller: symbol, call: callee, target: hidden });
+        }
+      }
+    }
+    this.calls.set(symbol.id, resolved);
+    if (violations.length > 0) {
+      this.violations.set(symbol.id, violations);
+    }
+    if (selfCalls.length > 0) {
+      this.selfCalls.set(symbol.id, selfCalls);
+    }
+  }
+
+  /**
+   * Drop the edges recorded for a symbol's calls
+   */
+  private unresolve(id: string): void {
+    for (const resolved of this.calls.get(id) ?? []) {
+      removeFromSet(this.callerIds, resolved.target, id);
+    }
+    this.calls.delete(id);
+    this.violations.delete(id);
+    this.selfCalls.delete(id);
+    for (const callee of this.symbols.get(id)?.metadata.callees ?? []) {
+      removeFromSet(this.callersByName, shortName(callee.name), id);
+    }
+  }
+
+  private sortedCallerIds(id: string): string[] {
+    const order = (callerId: string) => this.order.get(callerId) ?? 0;
+    return Array.from(this.callerIds.get(id) ?? []).sort((a, b) => order(a) - order(b));
+  }
+
+  /**
+   * Resolve a call to an indexed symbol
+   *
+   * Uses the callee's file when the scanner resolved it, then the method of
+   * the concrete type a Go receiver was narrowed to. Otherwise an exact
+   * qualified name wins, then a unique short name, then one in the caller's
+   * file; anything more ambiguous is dropped rather than guessed.
+   *
+   * @param candidates - Symbols with the called short name the caller may reach
+   */
+  private resolveCallee(
+    callee: CalleeInfo,
+    caller: SearchResult,
+    candidates: SearchResult[]
+  ): SearchResult | null {
+    if (candidates.length === 0) return null;
+
+    if (callee.file) {
+      const inFile = candidates.filter((c) => c.metadata.path === callee.file);
+      if (inFile.length > 0) return inFile[0];
+    }
+
+    if (callee.receiverType) {
+      const narrowed = narrowedTarget(callee, caller, candidates);
+      if (narrowed) return narrowed;
+    }
+
+    const exact = candidates.filter((c) => c.metadata.name === callee.name);
+    if (exact.length === 1) return exact[0];
+    if (candidates.length === 1) return candidates[0];
+
+    const sameFile = candidates.filter((c) => c.metadata.path === caller.metadata.path);
+    return sameFile.length === 1 ? sameFile[0] : null;
+  }
+}
+
+/**
+ * Keeps a SymbolGraph current as the index changes
+ *
+ * Each new index version is compared with the last one file by file, and
+ * only files whose symbols changed are patched into the graph.
+ */
+export class SymbolGraphCache {
+  private graph: SymbolGraph | null = null;
+  private version: string | null = null;
+  private fileHashes = new Map<string, string>();
+
+  /**
+   * Graph over the given symbols
+   *
+   * @param symbols - Current graph symbols (see graphSymbols)
+   * @param version - Index version the symbols were read at; null disables reuse
+   */
+  get(symbols: SearchResult[], version: string | null): SymbolGraph {
+    if (this.graph && version !== null && version === this.version) {
+      return this.graph;
+    }
+
+    const byFile = new Map<string, SearchResult[]>();
+    for (const symbol of symbols) {
+      append(byFile, symbol.metadata.path ?? '', symbol);
+    }
+    const hashes = new Map<string, string>();
+    for (const [file, fileSymbols] of byFile) {
+      hashes.set(file, hashSymbols(fileSymbols));
+    }
+
+    if (this.graph) {
+      const changed = new Set<string>();
+      for (const [file, hash] of hashes) {
+        if (this.fileHashes.get(file) !== hash) changed.add(file);
+      }
+      for (const file of this.fileHashes.keys()) {
+        if (!hashes.has(file)) changed.add(file);
+      }
+      if (changed.size > 0) {
+        this.graph.update(changed, Array.from(changed).flatMap((file) => byFile.get(file) ?? []));
+      }
+    } else {
+      this.graph = new SymbolGraph(symbols);
+    }
+
+    this.version = version;
+    this.fileHashes = hashes;
+    return this.graph;
+  }
+}
+
+/**
+ * Documents that take part in the symbol graph: named, non-documentation symbols
+ */
+export function graphSymbols(docs: SearchResult[]): SearchResult[] {
+  return docs.filter((doc) => doc.metadata.type !== 'documentation' && doc.metadata.name);
+}
+
+/**
+ * Pick the symbol to build context for: an exact name, or a method whose
+ * qualified name ends with it. Ties resolve by path and line.
+ */
+export function findTarget(
+  symbols: SearchResult[],
+  name: string,
+  pathPrefix?: string
+): SearchResult | null {
+  const inScope = symbols.filter((s) => !pathPrefix || s.metadata.path?.startsWith(pathPrefix));
+  const exact = inScope.filter((s) => s.metadata.name === name);
+  const candidates =
+    exact.length > 0 ? exact : inScope.filter((s) => s.metadata.name?.endsWith(`.${name}`));
+
+  candidates.sort(
+    (a, b) =>
+      (a.metadata.path ?? '').localeCompare(b.metadata.path ?? '') ||
+      (a.metadata.startLine ?? 0) - (b.metadata.startLine ?? 0)
+  );
+  return candidates[0] ?? null;
+}
+
+/**
+ * Prefer a candidate in the same file, then the same directory
+ */
+function closest(candidates: SearchResult[], from: SearchResult): SearchResult {
+  const file = from.metadata.path ?? '';
+  const dir = path.posix.dirname(file);
+  return (
+    candidates.find((c) => c.metadata.path === file) ??
+    candidates.find((c) => path.posix.dirname(c.metadata.path ?? '') === dir) ??
+    candidates[0]
+  );
+}
+
+/**
+ * The method a call reaches on the type its receiver was narrowed to (`r.Read`
+ * after `r, ok := x.(*FileReader)` reaches `FileReader.Read`). A qualified
+ * type (`bufio.Reader`) is looked up in that package, an unqualified one in
+ * the caller's.
+ */
+function narrowedTarget(
+  callee: CalleeInfo,
+  caller: SearchResult,
+  candidates: SearchResult[]
+): SearchResult | null {
+  const type = callee.receiverType ?? '';
+  const dot = type.lastIndexOf('.');
+  const qualifier = dot >= 0 ? type.slice(0, dot) : undefined;
+  const method = `${type.slice(dot + 1)}.${shortName(callee.name)}`;
+  const matches = candidates.filter((c) => c.metadata.name === method);
+
+  const callerDir = path.posix.dirname(caller.metadata.path ?? '');
+  const inPackage = matches.filter((c) => {
+    const dir = path.posix.dirname(c.metadata.path ?? '');
+    return qualifier ? path.posix.basename(dir) === qualifier : dir === callerDir;
+  });
+  if (inPackage.length === 1) return inPackage[0];
+  return matches.length === 1 ? matches[0] : null;
+}
+
+/**
+ * Whether Go's `internal` rule lets a caller's package reach a symbol's package
+ * (other languages have no such rule)
+ */
+function importable(caller: SearchResult, symbol: SearchResult): boolean {
+  if (caller.metadata.language !== 'go' || symbol.metadata.language !== 'go') return true;
+  return canImportInternal(
+    path.posix.dirname(caller.metadata.path ?? ''),
+    path.posix.dirname(symbol.metadata.path ?? '')
+  );
+}
+
+/**
+ * Whether a type reference a symbol recorded (`Config`, `config.Config`) names
+ * a type: unqualified in the symbol's own package, qualified in the package
+ * whose directory is the qualifier
+ */
+export function referencesType(
+  reference: string,
+  symbol: SearchResult,
+  type: SearchResult
+): boolean {
+  if (shortName(reference) !== type.metadata.name) return false;
+  const dot = reference.lastIndexOf('.');
+  const typeDir = path.posix.dirname(type.metadata.path ?? '');
+  if (dot < 0) return typeDir === path.posix.dirname(symbol.metadata.path ?? '');
+  return path.posix.basename(typeDir) === reference.slice(0, dot) && importable(symbol, type);
+}
+
+/**
+ * Whether a file holds tests (`*.test.*`, `*.spec.*`, `_test.go`, or under `__tests__/`)
+ */
+export function inTestFile(file: string): boolean {
+  return isTestFile(file) || file.endsWith('_test.go') || file.includes('/__tests__/');
+}
+
+export function shortName(name: string): string {
+  return name.slice(name.lastIndexOf('.') + 1);
+}
+
+/**
+ * Whether a symbol's declaration changed in a way that can affect how calls resolve to it
+ */
+function declarationChanged(before: SearchResult, after: SearchResult): boolean {
+  return (
+    before.metadata.name !== after.metadata.name ||
+    before.metadata.type !== after.metadata.type ||
+    before.metadata.signature !== after.metadata.signature
+  );
+}
+
+function hashSymbols(symbols: SearchResult[]): string {
+  const hash = crypto.createHash('sha1');
+  for (const symbol of symbols) {
+    hash.update(symbol.id).update('\u0000').update(JSON.stringify(symbol.metadata));
+  }
+  return hash.digest('hex');
+}
+
+function isDefined<T>(value: T | undefined): value is T {
+  return value !== undefined;
+}
+
+function append<K, V>(map: Map<K, V[]>, key: K, value: V): void {
+  const list = map.get(key);
+  if (list) {
+    list.push(value);
+  } else {
+    map.set(key, [value]);
+  }
+}
+
+function removeFromList(map: Map<string, SearchResult[]>, key: string, id: string): void {
+  const list = map.get(key)?.filter((symbol) => symbol.id !== id);
+  if (list && list.length > 0) {
+    map.set(key, list);
+  } else {
+    map.delete(key);
+  }
+}
+
+function addToSet<K, V>(map: Map<K, Set<V>>, key: K, value: V): void {
+  const set = map.get(key);
+  if (set) {
+    set.add(value);
+  } else {
+    map.set(key, new Set([value]));
+  }
+}
+
+function removeFromSet<K, V>(map: Map<K, Set<V>>, key: K, value: V): void {
+  const set = map.get(key);
+  set?.delete(value);
+  if (set?.size === 0) {
+    map.delete(key);
+  }
+}
@@ -0,0 +1,239 @@
+/**
+ * Symbol Inspection
+ * Everything about a symbol in one call: definition, callers, callees,
+ * implements edges, and last-modified git info
+ *
+ * Agents otherwise piece this together from several lookups. Sections are
+ * selectable, and the source and listed symbols are packed into a token
+ * budget in section order, so the definition wins over long caller lists.
+ * Git info comes from the blame annotations stored at index time, so nothing
+ * here shells out to git; go build and go vet findings likewise come from
+ * the index, when it was built with Go diagnostics.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import { estimateTokenCount } from '../indexer/utils/truncation';
+import type { SearchResult } from '../vector/types';
+import { implementedInterfaces, implementingTypes } from './implementations';
+import { packageDir } from './method-sets';
+import {
+  findTarget,
+  graphSymbols,
+  inTestFile,
+  SymbolGraph,
+  type SymbolGraphCache,
+} from './symbol-graph';
+import type {
+  ImplementsEdge,
+  InspectionSection,
+  SymbolInspection,
+  SymbolInspectionOptions,
+} from './types';
+
+/** Default token budget for an inspection */
+export const DEFAULT_INSPECTION_MAX_TOKENS = 2000;
+
+/** Every section, in output order */
+export const INSPECTION_SECTIONS: InspectionSection[] = [
+  'definition',
+  'callers',
+  'callees',
+  'implements',
+  'git',
+];
+
+/**
+ * Inspect a symbol from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param name - Symbol name (e.g. "retryRequest" or "Client.Do")
+ * @param options - Sections, token budget, and disambiguation options
+ * @param graphs - Graph cache to reuse across calls
+ * @returns The inspection, or null if the symbol isn't indexed
+ */
+export async function collectSymbolInspection(
+  indexer: RepositoryIndexer,
+  name: string,
+  options?: SymbolInspectionOptions,
+  graphs?: SymbolGraphCache
+): Promise<SymbolInspection | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildSymbolInspection(docs, name, options, graph);
+}
+
+/**
+ * Inspect a symbol from a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildSymbolInspection(
+  docs: SearchResult[],
+  name: string,
+  options: SymbolInspectionOptions = {},
+  symbolGraph?: SymbolGraph
+): SymbolInspection | null {
+  const { maxTokens = DEFAULT_INSPECTION_MAX_TOKENS, includeTests = false } = options;
+  const selected = new Set(options.sections ?? INSPECTION_SECTIONS);
+  const sections = INSPECTION_SECTIONS.filter((section) => selected.has(section));
+
+  const symbols = graphSymbols(docs);
+  const target = findTarget(symbols, name, options.path);
+  if (!target) return null;
+
+  const graph = symbolGraph ?? new SymbolGraph(symbols);
+  const keep = (symbol: SearchResult) => includeTests || !inTestFile(symbol.metadata.path ?? '');
+
+  let remaining = maxTokens;
+  let omitted = 0;
+  const fit = <T>(items: T[], text: (item: T) => string): T[] => {
+    const included: T[] = [];
+    for (const item of items) {
+      const tokens = estimateTokenCount(text(item));
+      if (tokens <= remaining) {
+        remaining -= tokens;
+        included.push(item);
+      } else {
+        omitted++;
+      }
+    }
+    return included;
+  };
+
+  // The signature is always shown, so a source that doesn't fit is dropped, not counted
+  let source = '';
+  const full = target.metadata.snippet ?? target.metadata.signature ?? '';
+  if (selected.has('definition') && estimateTokenCount(full) <= remaining) {
+    source = full;
+    remaining -= estimateTokenCount(full);
+  }
+
+  const callers = selected.has('callers')
+    ? fit(graph.callersOf(target).filter(keep), entryText)
+    : [];
+  const callees = selected.has('callees') ? fit(graph.calleesOf(target), entryText) : [];
+  const internalViolations = selected.has('callees') ? graph.internalViolations(target) : [];
+
+  let implementsEdges: ImplementsEdge[] = [];
+  let implementedBy: ImplementsEdge[] = [];
+  if (selected.has('implements')) {
+    if (target.metadata.type === 'interface') {
+      implementedBy = fit(implementingTypes(docs, target, includeTests), edgeText);
+    } else if (target.metadata.type === 'class' || target.metadata.type === 'type') {
+      implementsEdges = fit(implementedInterfaces(docs, target, includeTests), edgeText);
+    }
+  }
+
+  const { lastModified, lastAuthor } = target.metadata;
+  const git = selected.has('git') && lastModified ? { lastModified, lastAuthor } : undefined;
+
+  return {
+    target,
+    package: packageDir(target),
+    sections,
+    source,
+    callers,
+    callees,
+    internalViolations,
+    implements: implementsEdges,
+    implementedBy,
+    git,
+    omitted,
+    tokens: Math.max(0, maxTokens - remaining),
+  };
+}
+
+/**
+ * Format an inspection as markdown, one section per requested part
+ */
+export function formatSymbolInspection(inspection: SymbolInspection): string {
+  const { target, sections } = inspection;
+  const { name, type, path: file, startLine, endLine, language } = target.metadata;
+  const lines = [
+    `# ${name} (${type}) - ${file}:${startLine}-${endLine}`,
+    '',
+    `Package: ${inspection.package || '.'}`,
+    '',
+  ];
+
+  for (const section of sections) {
+    switch (section) {
+      case 'definition': {
+        lines.push('## Definition', '');
+        if (target.metadata.signature) lines.push(`\`${target.metadata.signature}\``, '');
+        if (target.metadata.docstring) lines.push(target.metadata.docstring.trim(), '');
+        for (const { tool, line, message } of target.metadata.diagnostics ?? []) {
+          lines.push(`- **go ${tool}** line ${line}: ${message}`);
+        }
+        if (target.metadata.diagnostics?.length) lines.push('');
+        if (inspection.source) {
+          lines.push(`\`\`\`${language ?? ''}`, inspection.source, '```', '');
+        } else {
+          lines.push('*Source omitted to stay within the token budget*', '');
+        }
+        break;
+      }
+      case 'callers':
+        lines.push(...formatSymbols('Callers', inspection.callers));
+        break;
+      case 'callees':
+        lines.push(...formatSymbols('Callees', inspection.callees));
+        if (inspection.internalViolations.length > 0) {
+          lines.push('## Internal Boundary Violations', '');
+          for (const { call, target: reached } of inspection.internalViolations) {
+            const { path: at, startLine } = reached.metadata;
+            lines.push(
+              `- \`${call.name}\` (line ${call.line}) would reach ${reached.metadata.name} ` +
+                `(${at}:${startLine}), in an internal package this one can't import`
+            );
+          }
+          lines.push('');
+        }
+        break;
+      case 'implements':
+        if (type === 'interface') {
+          lines.push(...formatEdges('Implemented by', inspection.implementedBy));
+        } else if (type === 'class' || type === 'type') {
+          lines.push(...formatEdges('Implements', inspection.implements));
+        }
+        break;
+      case 'git':
+        lines.push('## Last Modified', '');
+        if (inspection.git) {
+          const { lastModified, lastAuthor } = inspection.git;
+          lines.push(`${lastModified}${lastAuthor ? ` by ${lastAuthor}` : ''}`, '');
+        } else {
+          lines.push('Not recorded; index with `dev index --blame` to track it.', '');
+        }
+        break;
+    }
+  }
+
+  if (inspection.omitted > 0) {
+    lines.push(`*${inspection.omitted} more item(s) omitted to stay within the token budget*`);
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function entryText(symbol: SearchResult): string {
+  const { name, path: file, startLine } = symbol.metadata;
+  return `- ${name} - ${file}:${startLine}`;
+}
+
+function edgeText(edge: ImplementsEdge): string {
+  const text = `- ${edge.name}${edge.pointer ? ' (pointer only)' : ''}`;
+  if (!edge.symbol) return text;
+  const { path: file, startLine } = edge.symbol.metadata;
+  return `${text} - ${file}:${startLine}`;
+}
+
+function formatSymbols(heading: string, symbols: SearchResult[]): string[] {
+  if (symbols.length === 0) return [`## ${heading}`, '', 'None found.', ''];
+  return [`## ${heading} (${symbols.length})`, '', ...symbols.map(entryText), ''];
+}
+
+function formatEdges(heading: string, edges: ImplementsEdge[]): string[] {
+  if (edges.length === 0) return [`## ${heading}`, '', 'None found.', ''];
+  return [`## ${heading} (${edges.length})`, '', ...edges.map(edgeText), ''];
+}
@@ -0,0 +1,198 @@
+/**
+ * Symbol Neighbors
+ * What's defined near a symbol or line: the declarations around it in its
+ * file, the rest of its type, and its package's doc
+ *
+ * Code is usually written next to the code it belongs with, so the
+ * declarations just before and after a symbol are often the helpers it uses
+ * or the variants of it. A method's receiver type, the type's other methods,
+ * and the package doc round that out. Everything comes from indexed line
+ * ranges and names; nothing is embedded or searched.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { qualifiedSymbolName } from './implementations';
+import { packageDir } from './method-sets';
+import { findTarget, graphSymbols } from './symbol-graph';
+import type { SymbolNeighborOptions, SymbolNeighbors } from './types';
+
+/** Default symbols listed on each side */
+export const DEFAULT_NEIGHBOR_COUNT = 3;
+
+/** Default maximum group members listed */
+export const DEFAULT_NEIGHBOR_GROUP_LIMIT = 30;
+
+/** Symbol types that other symbols group under */
+const GROUP_TYPES = new Set(['class', 'interface', 'type', 'struct']);
+
+/**
+ * Find what's defined near a symbol or line from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Symbol, or file and line; neighbor counts
+ * @returns The neighbors, or null if the symbol or file isn't indexed
+ */
+export async function collectSymbolNeighbors(
+  indexer: RepositoryIndexer,
+  options: SymbolNeighborOptions
+): Promise<SymbolNeighbors | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  return buildSymbolNeighbors(docs, options);
+}
+
+/**
+ * Find what's defined near a symbol or line in a set of indexed documents
+ */
+export function buildSymbolNeighbors(
+  docs: SearchResult[],
+  options: SymbolNeighborOptions
+): SymbolNeighbors | null {
+  const { count = DEFAULT_NEIGHBOR_COUNT, groupLimit = DEFAULT_NEIGHBOR_GROUP_LIMIT } = options;
+  const symbols = graphSymbols(docs).filter((symbol) => !symbol.metadata.funcLiteral);
+
+  let target: SearchResult | null = null;
+  let anchor: SearchResult | undefined;
+  let line: number | undefined;
+  if (options.symbol) {
+    target = findTarget(symbols, options.symbol, options.path);
+    anchor = target ?? undefined;
+  } else if (options.file && options.line !== undefined) {
+    const wanted = options.file.replace(/^\.\//, '');
+    line = options.line;
+    anchor = docs.find((doc) => doc.metadata.path === wanted);
+    target = enclosingSymbol(symbols, wanted, line);
+  }
+  if (!anchor?.metadata.path) return null;
+  const file = anchor.metadata.path;
+
+  const inFile = symbols
+    .filter((symbol) => symbol.metadata.path === file && symbol.id !== target?.id)
+    .sort(byPosition);
+  const start = target?.metadata.startLine ?? line ?? 0;
+  const end = target?.metadata.endLine ?? target?.metadata.startLine ?? line ?? 0;
+  const before = inFile.filter((symbol) => endOf(symbol) < start).slice(-count);
+  const after = inFile.filter((symbol) => (symbol.metadata.startLine ?? 0) > end).slice(0, count);
+
+  const pkg = packageDir(anchor);
+  const documented = docs.find((doc) => doc.metadata.packageDoc && packageDir(doc) === pkg);
+  const packageDoc = documented?.metadata.packageDoc;
+
+  const group = target ? groupOf(target) : undefined;
+  const members = group
+    ? symbols
+        .filter(
+          (symbol) =>
+            symbol.id !== target?.id &&
+            packageDir(symbol) === pkg &&
+            (symbol.metadata.name === group || symbol.metadata.name?.startsWith(`${group}.`))
+        )
+        .sort(
+          (a, b) =>
+            Number(isGroupType(b)) - Number(isGroupType(a)) ||
+            (a.metadata.name ?? '').localeCompare(b.metadata.name ?? '')
+        )
+    : [];
+
+  return {
+    file,
+    package: pkg,
+    ...(packageDoc ? { packageDoc } : {}),
+    target,
+    ...(line !== undefined ? { line } : {}),
+    before,
+    after,
+    ...(group ? { group } : {}),
+    groupMembers: members.slice(0, groupLimit),
+    omittedGroupMembers: Math.max(members.length - groupLimit, 0),
+  };
+}
+
+/**
+ * Format symbol neighbors as markdown: the file's neighborhood, then the group
+ */
+export function formatSymbolNeighbors(result: SymbolNeighbors): string {
+  const { target, file } = result;
+  const subject = target
+    ? `${qualifiedSymbolName(target)} (${file}:${target.metadata.startLine})`
+    : `${file}:${result.line}`;
+  const lines = [`# Neighbors of ${subject}`, ''];
+
+  if (result.packageDoc) {
+    lines.push(`Package ${result.package || '.'}: ${result.packageDoc}`, '');
+  }
+  if (!target) {
+    lines.push('Line is outside any indexed symbol.', '');
+  }
+
+  lines.push('## Before', '');
+  lines.push(...symbolLines(result.before, 'Nothing defined before it in this file.'), '');
+  if (target) {
+    lines.push(`## ${target.metadata.name}`, '', ...symbolLines([target], ''), '');
+  }
+  lines.push('## After', '');
+  lines.push(...symbolLines(result.after, 'Nothing defined after it in this file.'), '');
+
+  if (result.group) {
+    const total = result.groupMembers.length + result.omittedGroupMembers;
+    lines.push(`## ${result.group} (${total} other ${total === 1 ? 'member' : 'members'})`, '');
+    lines.push(...symbolLines(result.groupMembers, 'No other members.', true), '');
+    if (result.omittedGroupMembers > 0) {
+      lines.push(`*${result.omittedGroupMembers} more member(s) omitted*`, '');
+    }
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+/**
+ * Innermost symbol whose line range contains a line
+ */
+function enclosingSymbol(
+  symbols: SearchResult[],
+  file: string,
+  line: number
+): SearchResult | null {
+  const span = (symbol: SearchResult) => endOf(symbol) - (symbol.metadata.startLine ?? 0);
+  const containing = symbols.filter(
+    (symbol) =>
+      symbol.metadata.path === file &&
+      (symbol.metadata.startLine ?? 0) <= line &&
+      endOf(symbol) >= line
+  );
+  containing.sort((a, b) => span(a) - span(b));
+  return containing[0] ?? null;
+}
+
+/**
+ * Type a symbol groups under: a method's receiver, or the type itself
+ */
+function groupOf(symbol: SearchResult): string | undefined {
+  const name = symbol.metadata.name ?? '';
+  if (symbol.metadata.type === 'method' && name.includes('.')) {
+    return name.slice(0, name.lastIndexOf('.'));
+  }
+  return isGroupType(symbol) ? name : undefined;
+}
+
+function isGroupType(symbol: SearchResult): boolean {
+  return GROUP_TYPES.has(symbol.metadata.type ?? '');
+}
+
+function symbolLines(symbols: SearchResult[], empty: string, withPath = false): string[] {
+  if (symbols.length === 0) return empty ? [empty] : [];
+  return symbols.map((symbol) => {
+    const { path: file, startLine, type, signature } = symbol.metadata;
+    const where = withPath ? `${file}:${startLine}` : `line ${startLine}`;
+    const sig = signature ? `: \`${signature}\`` : '';
+    return `- **${symbol.metadata.name}** (${type}, ${where})${sig}`;
+  });
+}
+
+function endOf(symbol: SearchResult): number {
+  return symbol.metadata.endLine ?? symbol.metadata.startLine ?? 0;
+}
+
+function byPosition(a: SearchResult, b: SearchResult): number {
+  return (a.metadata.startLine ?? 0) - (b.metadata.startLine ?? 0) || endOf(a) - endOf(b);
+}
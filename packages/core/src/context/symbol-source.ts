@@ -0,0 +1,128 @@
+/**
+ * Symbol Source
+ * Reads a symbol's exact source from the working tree on demand
+ *
+ * The index keeps a bounded snippet per symbol rather than its full body, so
+ * it stays small. When a tool needs the exact text, a reader takes the lines
+ * between the symbol's indexed start and end from the current file. Each file
+ * is read once per reader, so tools create one per request.
+ *
+ * Line ranges are only right while the file matches what was indexed. A file
+ * whose content hash differs from the one recorded at indexing is reported
+ * stale, with a warning, and callers fall back to the indexed snippet.
+ */
+
+import * as crypto from 'node:crypto';
+import * as path from 'node:path';
+import type { Logger } from '@lytics/kero';
+import { type FileSystemValidator, NodeFileSystemValidator } from '../utils/file-validator';
+import type { SearchResultMetadata } from '../vector/types';
+
+/**
+ * Source text of a symbol as currently on disk
+ */
+export interface SymbolSource {
+  /** File path relative to the repository root */
+  file: string;
+  /** First line of the text (1-based) */
+  startLine: number;
+  /** Last line of the text, clamped to the end of the file */
+  endLine: number;
+  /** The lines, exactly as in the file */
+  text: string;
+  /** True when the file changed since indexing, so the range may be off */
+  stale: boolean;
+}
+
+/**
+ * Options for reading symbol source
+ */
+export interface SymbolSourceReaderOptions {
+  /** Filesystem to read, e.g. an InMemoryFileSystem over editor buffers (default: disk) */
+  fs?: FileSystemValidator;
+  /** SHA-256 of a file's content when it was indexed; undefined skips the check */
+  indexedHash?: (file: string) => string | undefined;
+  /** Logger for stale file warnings */
+  logger?: Logger;
+}
+
+interface SourceFile {
+  lines: string[];
+  stale: boolean;
+}
+
+/**
+ * Reads symbol source by indexed file and line range, caching each file
+ */
+export class SymbolSourceReader {
+  private readonly fs: FileSystemValidator;
+  private files = new Map<string, SourceFile | null>();
+
+  constructor(
+    private readonly repositoryPath: string,
+    private readonly options: SymbolSourceReaderOptions = {}
+  ) {
+    this.fs = options.fs ?? new NodeFileSystemValidator();
+  }
+
+  /**
+   * Read the source of an indexed symbol
+   *
+   * @param metadata - The symbol's path and line range
+   * @returns The source, or null if the file can't be read or is shorter than the range
+   */
+  read(
+    metadata: Pick<SearchResultMetadata, 'path' | 'startLine' | 'endLine'>
+  ): SymbolSource | null {
+    const { path: file, startLine } = metadata;
+    if (!file || !startLine) return null;
+
+    const source = this.load(file);
+    if (!source || startLine > source.lines.length) return null;
+
+    const end = Math.max(metadata.endLine ?? startLine, startLine);
+    const endLine = Math.min(end, source.lines.length);
+    return {
+      file,
+      startLine,
+      endLine,
+      text: source.lines.slice(startLine - 1, endLine).join('\n'),
+      stale: source.stale,
+    };
+  }
+
+  /**
+   * Files read so far that changed since indexing, in sorted order
+   */
+  staleFiles(): string[] {
+    return [...this.files.entries()]
+      .filter(([, source]) => source?.stale)
+      .map(([file]) => file)
+      .sort();
+  }
+
+  private load(file: string): SourceFile | null {
+    const cached = this.files.get(file);
+    if (cached !== undefined) return cached;
+
+    let source: SourceFile | null = null;
+    try {
+      const content = this.fs.readText(path.join(this.repositoryPath, file));
+      const indexed = this.options.indexedHash?.(file);
+      const stale =
+        indexed !== undefined &&
+        crypto.createHash('sha256').update(content).digest('hex') !== indexed;
+      if (stale) {
+        this.options.logger?.warn(
+          `${file} changed since it was indexed, so symbol line ranges may be off. ` +
+            'Run "dev update" to refresh.'
+        );
+      }
+      source = { lines: content.split('\n'), stale };
+    } catch {
+      // Deleted or unreadable: callers keep the indexed snippet
+    }
+    this.files.set(file, source);
+    return source;
+  }
+}
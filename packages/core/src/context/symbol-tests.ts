@@ -0,0 +1,161 @@
+/**
+ * Symbol Tests
+ * Finds the test functions that exercise a symbol through the call graph
+ *
+ * Walks callers outward from the symbol until it reaches test functions.
+ * Tests that call the symbol themselves are direct coverage; tests that reach
+ * it through helpers or other production code are transitive. Calls the
+ * scanner couldn't resolve are missing from the graph, so a symbol reported
+ * as untested may still be covered dynamically (interfaces, reflection).
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import {
+  findTarget,
+  graphSymbols,
+  inTestFile,
+  shortName,
+  SymbolGraph,
+  type SymbolGraphCache,
+} from './symbol-graph';
+import type { SymbolTestOptions, SymbolTests, TestReach } from './types';
+
+/** Default call hops followed from the symbol toward tests */
+export const DEFAULT_TEST_DEPTH = 5;
+
+/** Go test, benchmark, fuzz, and example function names */
+const GO_TEST_NAME = /^(Test|Benchmark|Fuzz|Example)($|[^a-z])/;
+
+/**
+ * Whether a symbol is a test function
+ *
+ * Go tests are the `go test` entry points (TestXxx, BenchmarkXxx, FuzzXxx,
+ * ExampleXxx) in `_test.go` files. In other languages test cases are usually
+ * anonymous callbacks, so any named function in a test file counts.
+ */
+export function isTestFunction(symbol: SearchResult): boolean {
+  const { type, name, path: file, language } = symbol.metadata;
+  if ((type !== 'function' && type !== 'method') || !name || !inTestFile(file ?? '')) {
+    return false;
+  }
+  return language === 'go' ? GO_TEST_NAME.test(shortName(name)) : true;
+}
+
+/**
+ * Collect the tests reaching a symbol from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param name - Symbol name (e.g. "NewExpBackoff" or "Client.Do")
+ * @param options - Depth and disambiguation options
+ * @param graphs - Graph cache to reuse across calls
+ * @returns The tests, or null if the symbol isn't indexed
+ */
+export async function collectSymbolTests(
+  indexer: RepositoryIndexer,
+  name: string,
+  options?: SymbolTestOptions,
+  graphs?: SymbolGraphCache
+): Promise<SymbolTests | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildSymbolTests(docs, name, options, graph);
+}
+
+/**
+ * Find the tests reaching a symbol in a set of indexed documents
+ *
+ * Callers are searched breadth-first, so each test is reported at its
+ * shortest distance. The search stops at tests rather than continuing
+ * through them.
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ */
+export function buildSymbolTests(
+  docs: SearchResult[],
+  name: string,
+  options: SymbolTestOptions = {},
+  symbolGraph?: SymbolGraph
+): SymbolTests | null {
+  const { depth = DEFAULT_TEST_DEPTH } = options;
+
+  const symbols = graphSymbols(docs);
+  const target = findTarget(symbols, name, options.path);
+  if (!target) return null;
+
+  const graph = symbolGraph ?? new SymbolGraph(symbols);
+  const reached: TestReach[] = [];
+  const visited = new Set([target.id]);
+  // Each frontier entry carries the symbols between it and the target, inclusive of itself
+  let frontier: Array<{ symbol: SearchResult; chain: SearchResult[] }> = [
+    { symbol: target, chain: [] },
+  ];
+
+  for (let hop = 1; hop <= depth && frontier.length > 0; hop++) {
+    const next: typeof frontier = [];
+    for (const { symbol, chain } of frontier) {
+      for (const caller of graph.callersOf(symbol)) {
+        if (visited.has(caller.id)) continue;
+        visited.add(caller.id);
+
+        if (isTestFunction(caller)) {
+          reached.push({ test: caller, depth: hop, via: chain });
+        } else {
+          next.push({ symbol: caller, chain: [caller, ...chain] });
+        }
+      }
+    }
+    frontier = next;
+  }
+
+  reached.sort(
+    (a, b) =>
+      a.depth - b.depth ||
+      (a.test.metadata.path ?? '').localeCompare(b.test.metadata.path ?? '') ||
+      (a.test.metadata.startLine ?? 0) - (b.test.metadata.startLine ?? 0)
+  );
+
+  return {
+    target,
+    direct: reached.filter((r) => r.depth === 1),
+    transitive: reached.filter((r) => r.depth > 1),
+    depth,
+  };
+}
+
+/**
+ * Format the tests reaching a symbol as markdown, direct tests first
+ */
+export function formatSymbolTests(tests: SymbolTests): string {
+  const { name, path: file, startLine, exported } = tests.target.metadata;
+  const lines = [`# Tests for ${name}`, '', `Defined at ${file}:${startLine}`, ''];
+
+  if (tests.direct.length + tests.transitive.length === 0) {
+    lines.push(`**Untested:** no test reaches ${name} within ${tests.depth} calls.`);
+    if (exported) {
+      lines.push('', 'It is exported, so callers outside this package rely on untested code.');
+    }
+    return `${lines.join('\n')}\n`;
+  }
+
+  if (tests.direct.length === 0) {
+    lines.push('*Only covered transitively - no test calls it directly*', '');
+  }
+  lines.push(...formatGroup('Direct', tests.direct));
+  lines.push(...formatGroup('Transitive', tests.transitive));
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function formatGroup(title: string, reached: TestReach[]): string[] {
+  if (reached.length === 0) return [];
+
+  const lines = [`## ${title} (${reached.length})`, ''];
+  for (const { test, via } of reached) {
+    const { name, path: file, startLine } = test.metadata;
+    const chain = via.length > 0 ? ` via ${via.map((s) => s.metadata.name).join(' → ')}` : '';
+    lines.push(`- **${name}** - ${file}:${startLine}${chain}`);
+  }
+  lines.push('');
+  return lines;
+}
@@ -0,0 +1,323 @@
+/**
+ * Symbol Usage
+ * Collects real call sites of a symbol as copy-pasteable examples
+ *
+ * Call sites come from the stored call graph; each call and its arguments are
+ * read from the caller's source on disk when a SymbolSourceReader is given
+ * (see symbol-source.ts), else from its indexed snippet. Examples are ranked by argument
+ * shape, so different ways of calling the symbol come before repeats of the
+ * same one. Test usages are kept apart since they often show canonical usage.
+ * Go `Example` functions for the symbol come before both: they are the
+ * package's own documentation, usually with the expected output.
+ */
+
+import type { RepositoryIndexer } from '../indexer';
+import type { SearchResult } from '../vector/types';
+import { packageDir } from './method-sets';
+import {
+  type CallSite,
+  findTarget,
+  graphSymbols,
+  inTestFile,
+  shortName,
+  SymbolGraph,
+  type SymbolGraphCache,
+} from './symbol-graph';
+import type { SymbolSourceReader } from './symbol-source';
+import type { SymbolUsageOptions, SymbolUsages, UsageExample } from './types';
+
+/** Default examples per group */
+export const DEFAULT_USAGE_LIMIT = 5;
+
+/** Default lines shown before and after each call */
+const DEFAULT_USAGE_CONTEXT_LINES = 2;
+
+/** Lines a call's arguments may span before it is treated as unreadable */
+const MAX_CALL_LINES = 20;
+
+/**
+ * Collect usage examples for a symbol from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param name - Symbol name (e.g. "NewExpBackoff" or "Client.Do")
+ * @param options - Example limit, test inclusion, and disambiguation options
+ * @param graphs - Graph cache to reuse across calls
+ * @param sources - Reader for callers' full source; indexed snippets when omitted
+ * @returns The usages, or null if the symbol isn't indexed
+ */
+export async function collectSymbolUsages(
+  indexer: RepositoryIndexer,
+  name: string,
+  options?: SymbolUsageOptions,
+  graphs?: SymbolGraphCache,
+  sources?: SymbolSourceReader
+): Promise<SymbolUsages | null> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const graph = graphs?.get(graphSymbols(docs), indexer.getIndexVersion());
+  return buildSymbolUsages(docs, name, options, graph, sources);
+}
+
+/**
+ * Build usage examples for a symbol from a set of indexed documents
+ *
+ * @param symbolGraph - Graph over the same documents; built from them when omitted
+ * @param sources - Reader for callers' full source; indexed snippets when omitted
+ */
+export function buildSymbolUsages(
+  docs: SearchResult[],
+  name: string,
+  options: SymbolUsageOptions = {},
+  symbolGraph?: SymbolGraph,
+  sources?: SymbolSourceReader
+): SymbolUsages | null {
+  const {
+    limit = DEFAULT_USAGE_LIMIT,
+    includeTests = true,
+    contextLines = DEFAULT_USAGE_CONTEXT_LINES,
+  } = options;
+
+  const symbols = graphSymbols(docs);
+  const target = findTarget(symbols, name, options.path);
+  if (!target) return null;
+
+  const examples = (symbolGraph ?? new SymbolGraph(symbols))
+    .callSitesOf(target)
+    .map((site) => toExample(site, contextLines, sources))
+    .sort(
+      (a, b) =>
+        (a.caller.metadata.path ?? '').localeCompare(b.caller.metadata.path ?? '') ||
+        a.line - b.line
+    );
+  const calls = examples.filter((e) => !e.isTest);
+  const testCalls = examples.filter((e) => e.isTest);
+
+  // Examples live in the symbol's directory, often in an external _test package
+  const documentedExamples = docs
+    .filter(
+      (doc) =>
+        doc.metadata.example?.target === target.metadata.name &&
+        packageDir(doc) === packageDir(target)
+    )
+    .sort((a, b) => (a.metadata.name ?? '').localeCompare(b.metadata.name ?? ''))
+    .slice(0, limit);
+
+  return {
+    target,
+    documentedExamples,
+    examples: rankByDiversity(calls, limit),
+    testExamples: includeTests ? rankByDiversity(testCalls, limit) : [],
+    totalCalls: calls.length,
+    totalTestCalls: testCalls.length,
+    staleFiles: sources?.staleFiles(),
+  };
+}
+
+/**
+ * Format usage examples as markdown, non-test examples first
+ */
+export function formatSymbolUsages(usages: SymbolUsages): string {
+  const { name, path: file, startLine } = usages.target.metadata;
+  const lines = [`# Usage of ${name}`, '', `Defined at ${file}:${startLine}`, ''];
+
+  lines.push(...formatDocumentedExamples(usages.documentedExamples));
+
+  if (usages.totalCalls + usages.totalTestCalls === 0) {
+    lines.push('No call sites found in the index.');
+    return `${lines.join('\n')}\n`;
+  }
+
+  if (usages.totalCalls === 0) {
+    lines.push('*No calls outside tests*', '');
+  }
+  lines.push(...formatGroup('Examples', usages.examples, usages.totalCalls));
+  lines.push(...formatGroup('Test Examples', usages.testExamples, usages.totalTestCalls));
+  lines.push(...formatStaleFiles(usages.staleFiles));
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function formatDocumentedExamples(examples: SearchResult[]): string[] {
+  if (examples.length === 0) return [];
+
+  const lines = [`## Documented Examples (${examples.length})`, ''];
+  for (const doc of examples) {
+    const { name, path: file, startLine, example } = doc.metadata;
+    if (!example) continue;
+    lines.push(`### ${name} - ${file}:${startLine}`, '', '```go', example.code, '```', '');
+    if (example.output !== undefined) {
+      const label = example.unordered ? 'Output (any order)' : 'Output';
+      lines.push(`${label}:`, '', '```', example.output, '```', '');
+    }
+  }
+  return lines;
+}
+
+/**
+ * Note caller files that changed since indexing, whose examples come from the index
+ */
+export function formatStaleFiles(files: string[] = []): string[] {
+  if (files.length === 0) return [];
+  return [`*Changed since indexing, shown as indexed: ${files.join(', ')}*`, ''];
+}
+
+function formatGroup(title: string, examples: UsageExample[], total: number): string[] {
+  if (examples.length === 0) return [];
+
+  const lines = [`## ${title} (${examples.length} of ${total} call sites)`, ''];
+  for (const example of examples) {
+    const { name, path: file, language } = example.caller.metadata;
+    lines.push(`### ${name} - ${file}:${example.line}`, '');
+    if (example.call) {
+      lines.push(`\`${example.call}\` - args: ${example.shape}`, '');
+    }
+    lines.push(`\`\`\`${language ?? ''}`, example.source, '```', '');
+  }
+  return lines;
+}
+
+function toExample(
+  { caller, call }: CallSite,
+  contextLines: number,
+  sources?: SymbolSourceReader
+): UsageExample {
+  const file = caller.metadata.path ?? '';
+  const isTest = inTestFile(file);
+  // Line numbers are from indexing, so a changed file's source can't be trusted
+  const source = sources?.read(caller.metadata);
+  const text = source && !source.stale ? source.text : caller.metadata.snippet;
+  const lines = (text ?? '').split('\n');
+  const index = call.line - (caller.metadata.startLine ?? 1);
+
+  // Calls past a truncated snippet can still be listed, just without arguments
+  const extracted =
+    index >= 0 && index < lines.length ? extractCall(lines, index, shortName(call.name)) : null;
+  if (!extracted) {
+    return { caller, line: call.line, call: '', shape: '(unknown)', source: '', isTest };
+  }
+
+  const first = Math.max(0, index - contextLines);
+  const last = Math.min(lines.length - 1, extracted.endIndex + contextLines);
+  return {
+    caller,
+    line: call.line,
+    call: extracted.text,
+    shape: `(${extracted.args.map(argumentKind).join(', ')})`,
+    source: lines.slice(first, last + 1).join('\n'),
+    isTest,
+  };
+}
+
+/**
+ * Find a call to `name` starting on a snippet line and split its arguments
+ *
+ * Arguments are split on top-level commas; brackets inside string literals
+ * are skipped. Returns null if the call isn't on the line or doesn't close.
+ */
+function extractCall(
+  lines: string[],
+  index: number,
+  name: string
+): { text: string; args: string[]; endIndex: number } | null {
+  const pattern = new RegExp(`\\b${escapeRegExp(name)}\\s*(?:\\[[^\\]]*\\])?\\s*\\(`);
+  const match = pattern.exec(lines[index]);
+  if (!match) return null;
+
+  const text = lines.slice(index, index + MAX_CALL_LINES).join('\n');
+  // Include the qualifier (`backoff.NewExpBackoff`, `c.retry`)
+  let start = match.index;
+  while (start > 0 && /[\w.]/.test(text[start - 1])) start--;
+
+  const open = match.index + match[0].length - 1;
+  const args: string[] = [];
+  let argStart = open + 1;
+  let depth = 0;
+  let quote = '';
+
+  for (let i = open; i < text.length; i++) {
+    const ch = text[i];
+    if (quote) {
+      if (ch === '\\') i++;
+      else if (ch === quote) quote = '';
+      continue;
+    }
+
+    if (ch === '"' || ch === "'" || ch === '`') {
+      quote = ch;
+    } else if ('([{'.includes(ch)) {
+      depth++;
+    } else if (')]}'.includes(ch)) {
+      depth--;
+      if (depth === 0) {
+        // A trailing comma leaves an empty final argument
+        const last = text.slice(argStart, i).trim();
+        if (last) args.push(last);
+        return {
+          text: text.slice(start, i + 1).replace(/\s*\n\s*/g, ' '),
+          args,
+          endIndex: index + (text.slice(0, i).match(/\n/g)?.length ?? 0),
+        };
+      }
+    } else if (ch === ',' && depth === 1) {
+      args.push(text.slice(argStart, i).trim());
+      argStart = i + 1;
+    }
+  }
+
+  return null;
+}
+
+/**
+ * Classify an argument expression for diversity ranking
+ */
+function argumentKind(arg: string): string {
+  if (arg.endsWith('...')) return `${argumentKind(arg.slice(0, -3))}...`;
+  if (arg.startsWith('...')) return `${argumentKind(arg.slice(3))}...`;
+  if (/^(nil|null|undefined)$/.test(arg)) return 'nil';
+  if (/^(true|false)$/.test(arg)) return 'bool';
+  if (/^-?\.?\d[\w.]*$/.test(arg)) return 'number';
+  if (/^["'`]/.test(arg)) return 'string';
+  if (/^(func\b|function\b|async\b|\([^)]*\)\s*=>|\w+\s*=>)/.test(arg)) return 'func';
+  if (/^&[\w.]+(\[[^\]]*\])?\s*\{/.test(arg)) return '&composite{}';
+  if (/^([\w.]+(\[[^\]]*\])?|\[\][\w.*]+|map\[[^\]]*\][\w.*]+)\s*\{/.test(arg)) {
+    return 'composite{}';
+  }
+  if (arg.startsWith('{')) return 'object';
+  if (arg.startsWith('[')) return 'array';
+  if (/^new\s/.test(arg)) return 'new';
+  if (/^[\w.]+\s*(\[[^\]]*\])?\s*\(/.test(arg)) return 'call';
+  if (/^&[\w.]+$/.test(arg)) return '&ident';
+  if (/^[\w.]+$/.test(arg)) return arg.includes('.') ? 'selector' : 'ident';
+  return 'expr';
+}
+
+/**
+ * Interleave examples by argument shape
+ *
+ * The most common shape comes first as the canonical usage, then one example
+ * of every other shape, before any shape repeats.
+ */
+function rankByDiversity(examples: UsageExample[], limit: number): UsageExample[] {
+  const byShape = new Map<string, UsageExample[]>();
+  for (const example of examples) {
+    const group = byShape.get(example.shape);
+    if (group) {
+      group.push(example);
+    } else {
+      byShape.set(example.shape, [example]);
+    }
+  }
+
+  // Stable sort keeps first-seen order between equally common shapes
+  const groups = Array.from(byShape.values()).sort((a, b) => b.length - a.length);
+  const ranked: UsageExample[] = [];
+  for (let round = 0; ranked.length < limit; round++) {
+    const picks = groups.filter((group) => round < group.length).map((group) => group[round]);
+    if (picks.length === 0) break;
+    ranked.push(...picks.slice(0, limit - ranked.length));
+  }
+  return ranked;
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
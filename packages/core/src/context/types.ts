@@ -0,0 +1,969 @@
+/**
+ * Symbol Context Types
+ * Types for assembling the code around a symbol for LLM prompts
+ */
+
+import type { GoDependency } from '../scanner/go-modules';
+import type { GoTypeSet, SqlQuery } from '../scanner/types';
+import type { SearchResult } from '../vector/types';
+import type { InternalViolation } from './symbol-graph';
+
+/**
+ * How a context entry relates to the symbol it was reached from
+ */
+export type ContextRelation = 'target' | 'callee' | 'caller' | 'type';
+
+/**
+ * A symbol included in the assembled context
+ */
+export interface ContextEntry {
+  /** The indexed symbol */
+  symbol: SearchResult;
+  /** Relationship to the symbol it was reached from */
+  relation: ContextRelation;
+  /** Number of hops from the target (0 for the target itself) */
+  hop: number;
+  /** Name of the symbol this entry was reached from (unset for the target) */
+  via?: string;
+  /** Source included in the context: the full snippet, or the signature when trimmed */
+  source: string;
+  /** True when only the signature fit in the token budget */
+  signatureOnly: boolean;
+}
+
+/**
+ * Code needed to understand a symbol, ordered by hop and relationship
+ */
+export interface SymbolContext {
+  /** The symbol the context was built for */
+  target: ContextEntry;
+  /** Related symbols, closest hops first */
+  entries: ContextEntry[];
+  /** Related symbols left out because the token budget ran out */
+  omitted: number;
+  /** Estimated tokens of all included source */
+  tokens: number;
+}
+
+/**
+ * Options for assembling symbol context
+ */
+export interface SymbolContextOptions {
+  /** Path prefix to disambiguate symbols with the same name */
+  path?: string;
+  /** Number of caller/callee/type hops to follow (default: 1) */
+  depth?: number;
+  /** Token budget for all included source (default: 4000) */
+  maxTokens?: number;
+  /** Include types referenced by each symbol (default: true) */
+  includeTypes?: boolean;
+}
+
+/**
+ * A call to a symbol, with the source around it
+ */
+export interface UsageExample {
+  /** The symbol containing the call */
+  caller: SearchResult;
+  /** Line of the call */
+  line: number;
+  /** The call as written, arguments included (empty if outside the indexed snippet) */
+  call: string;
+  /** Kinds of the call's arguments, e.g. "(ident, &composite{}, string)" */
+  shape: string;
+  /** Source around the call */
+  source: string;
+  /** True when the call is in a test file */
+  isTest: boolean;
+}
+
+/**
+ * Call sites of a symbol, most varied argument shapes first
+ */
+export interface SymbolUsages {
+  /** The symbol whose usages were collected */
+  target: SearchResult;
+  /** Runnable Go examples documenting the symbol (`ExampleNewServer`), shown first */
+  documentedExamples: SearchResult[];
+  /** Examples from non-test code */
+  examples: UsageExample[];
+  /** Examples from tests, which often show canonical usage */
+  testExamples: UsageExample[];
+  /** Call sites in non-test code, including ones not shown */
+  totalCalls: number;
+  /** Call sites in tests, including ones not shown */
+  totalTestCalls: number;
+  /** Caller files that changed since indexing; their examples come from the index */
+  staleFiles?: string[];
+}
+
+/**
+ * Options for collecting symbol usages
+ */
+export interface SymbolUsageOptions {
+  /** Path prefix to disambiguate symbols with the same name */
+  path?: string;
+  /** Maximum examples per group (default: 5) */
+  limit?: number;
+  /** Include examples from tests (default: true) */
+  includeTests?: boolean;
+  /** Lines of source shown before and after each call (default: 2) */
+  contextLines?: number;
+}
+
+/**
+ * A symbol curated for reading: doc and signature, a few usages, direct callees
+ */
+export interface SymbolExplanation {
+  /** The explained symbol */
+  target: SearchResult;
+  /** Package directory of the symbol */
+  package: string;
+  /** Go Example functions documenting the symbol, which take the first example slots */
+  documentedExamples: SearchResult[];
+  /** Call sites filling the remaining slots, most varied first, non-test before test */
+  examples: UsageExample[];
+  /** Symbols it calls directly */
+  callees: SearchResult[];
+  /** Callees left out by the callee limit */
+  omittedCallees: number;
+  /** Call sites in the index, tests included */
+  totalCalls: number;
+  /** Caller files that changed since indexing; their examples come from the index */
+  staleFiles?: string[];
+}
+
+/**
+ * Options for explaining a symbol
+ */
+export interface SymbolExplanationOptions {
+  /** Path prefix to disambiguate symbols with the same name */
+  path?: string;
+  /** Usage examples, Go Example functions first (default: 2) */
+  examples?: number;
+  /** Maximum callees listed (default: 10) */
+  maxCallees?: number;
+  /** Lines of source shown before and after each call (default: 2) */
+  contextLines?: number;
+}
+
+/**
+ * A test reaching a symbol through the call graph
+ */
+export interface TestReach {
+  /** The test function */
+  test: SearchResult;
+  /** Calls from the test to the symbol (1 when the test calls it directly) */
+  depth: number;
+  /** Symbols the test reaches it through, test side first */
+  via: SearchResult[];
+}
+
+/**
+ * Tests exercising a symbol, closest first
+ */
+export interface SymbolTests {
+  /** The symbol the tests were collected for */
+  target: SearchResult;
+  /** Tests that call the symbol themselves */
+  direct: TestReach[];
+  /** Tests that reach it through other calls */
+  transitive: TestReach[];
+  /** Call hops searched */
+  depth: number;
+}
+
+/**
+ * Options for finding the tests of a symbol
+ */
+export interface SymbolTestOptions {
+  /** Path prefix to disambiguate symbols with the same name */
+  path?: string;
+  /** Call hops followed from the symbol toward tests (default: 5) */
+  depth?: number;
+}
+
+/**
+ * A type in a package outline with its relationships
+ */
+export interface OutlineType {
+  /** The type's declaration */
+  symbol: SearchResult;
+  /** Declaration kind */
+  kind: 'struct' | 'interface' | 'type';
+  /** Embedded types (struct fields or interfaces) */
+  embeds: string[];
+  /** Package interfaces whose methods the type has */
+  implements: string[];
+  /** Package types implementing this interface */
+  implementedBy: string[];
+  /** Functions the scanner flagged as constructing the type */
+  constructors: string[];
+  /** Method names, promoted ones included */
+  methods: string[];
+}
+
+/**
+ * Structural map of a package, most connected types kept when capped
+ */
+export interface PackageOutline {
+  /** Package directory, relative to the repository root */
+  package: string;
+  /** Types, by name */
+  types: OutlineType[];
+  /** Functions that aren't constructors, by name */
+  functions: SearchResult[];
+  /** Types left out by the limit */
+  omittedTypes: number;
+  /** Functions left out by the limit */
+  omittedFunctions: number;
+}
+
+/**
+ * Options for outlining a package
+ */
+export interface PackageOutlineOptions {
+  /** Include unexported types, functions, and methods (default: false) */
+  includeUnexported?: boolean;
+  /** Maximum types, and separately functions, in the outline (default: 30) */
+  limit?: number;
+}
+
+/**
+ * A method of a type that satisfies one the interface requires
+ */
+export interface SatisfyingMethod {
+  /** Method name */
+  name: string;
+  /** Declaring document: the method, possibly promoted from an embedded type */
+  symbol: SearchResult;
+  /**
+   * Receiver of the declaring method, or `interface` when an embedded interface
+   * supplies the method (it is then in the method sets of both `T` and `*T`)
+   */
+  receiver: 'value' | 'pointer' | 'interface';
+  /**
+   * True when the method is only in `*T`'s method set: a pointer receiver not
+   * promoted through an embedded pointer
+   */
+  pointerOnly: boolean;
+  /**
+   * `exact` when the signature was compared with the interface's; `name` when
+   * either is unknown (well-known library interfaces, signatures cut short)
+   */
+  match: 'exact' | 'name';
+}
+
+/**
+ * How sure an implementation is
+ * - high: a compile-time assertion, or every method's signature matches
+ * - medium: some methods matched by name only
+ * - low: every method matched by name only
+ */
+export type ImplementationConfidence = 'high' | 'medium' | 'low';
+
+/**
+ * A type that implements an interface, by assertion, by method set, or both
+ */
+export interface Implementation {
+  /** Type name */
+  type: string;
+  /** Package directory, relative to the repository root */
+  package: string;
+  /** The type's declaration, when indexed */
+  symbol?: SearchResult;
+  /**
+   * True when only `*T` implements the interface: a satisfying method has a pointer
+   * receiver and isn't promoted through an embedded pointer, or the assertion is a pointer
+   */
+  pointer: boolean;
+  /** Explicit `var _ I = T` assertion, when there is one */
+  assertion?: SearchResult;
+  /** True when the type's method set covers the interface, with matching signatures */
+  structural: boolean;
+  /** Methods satisfying the interface, in the interface's order (set when structural) */
+  methods: SatisfyingMethod[];
+  /** How sure the match is (see ImplementationConfidence) */
+  confidence: ImplementationConfidence;
+  /** True when the type or its assertion is in a test file */
+  isTest: boolean;
+}
+
+/**
+ * A method whose signature differs from the one the interface requires
+ */
+export interface SignatureMismatch {
+  /** The type's method */
+  symbol: SearchResult;
+  /** Required parameter and result types, normalized (e.g. `(string) ([]byte, error)`) */
+  expected: string;
+  /** The method's parameter and result types, normalized the same way */
+  actual: string;
+}
+
+/**
+ * A type one method short of implementing an interface
+ */
+export interface PartialImplementation {
+  /** Type name */
+  type: string;
+  /** Package directory, relative to the repository root */
+  package: string;
+  /** The type's declaration */
+  symbol: SearchResult;
+  /** True when only `*T` has the methods it does satisfy */
+  pointer: boolean;
+  /** Methods that satisfy the interface, in the interface's order */
+  methods: SatisfyingMethod[];
+  /** The interface method the type lacks */
+  missing: string;
+  /** The type's method of that name, when it has one with a different signature */
+  mismatch?: SignatureMismatch;
+  /** True when the type is in a test file */
+  isTest: boolean;
+}
+
+/**
+ * Types implementing an interface
+ */
+export interface InterfaceImplementations {
+  /** Interface as resolved (e.g. `io.Reader`, `store.Store`) */
+  interface: string;
+  /** The interface's declaration, when it is in the repository */
+  declaration?: SearchResult;
+  /** Other repository interfaces the name matched, as `package.Name` */
+  alternatives: string[];
+  /** Methods the interface requires; empty when they are unknown */
+  methods: string[];
+  /** Whether `methods` is known (repository or well-known standard library interface) */
+  methodsKnown: boolean;
+  /** Implementations, by package then type name */
+  implementations: Implementation[];
+  /** Implementations left out by the limit */
+  omitted: number;
+  /** Types missing exactly one method (or with one mismatched signature), by package */
+  partial: PartialImplementation[];
+}
+
+/**
+ * Options for listing implementations
+ */
+export interface ImplementationOptions {
+  /** Include types and assertions in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum implementations returned (default: 50) */
+  limit?: number;
+}
+
+/**
+ * Where a symbol is defined
+ */
+export interface Definition {
+  /** The defining document: location, signature, and kind */
+  symbol: SearchResult;
+  /** Package directory relative to the repository root ('' for the root) */
+  package: string;
+  isTest: boolean;
+}
+
+/**
+ * Every definition of an exact symbol name
+ */
+export interface Definitions {
+  /** Name as looked up, possibly package-qualified */
+  name: string;
+  /** Definitions, by path then line */
+  definitions: Definition[];
+  /** Definitions in test files left out */
+  omittedTests: number;
+}
+
+/**
+ * Options for looking up definitions
+ */
+export interface DefinitionOptions {
+  /** Include definitions in test files (default: false) */
+  includeTests?: boolean;
+}
+
+/**
+ * Symbols defined near a symbol or line
+ */
+export interface SymbolNeighbors {
+  /** File the neighborhood is in */
+  file: string;
+  /** Package directory of the file */
+  package: string;
+  /** Go: first paragraph of the package doc comment */
+  packageDoc?: string;
+  /** The symbol asked about, or the innermost one enclosing the line; null between symbols */
+  target: SearchResult | null;
+  /** Line asked about, when looked up by line */
+  line?: number;
+  /** Symbols ending before the target (or line), nearest last */
+  before: SearchResult[];
+  /** Symbols starting after the target (or line), nearest first */
+  after: SearchResult[];
+  /** Type the target belongs to: a method's receiver, or the target type itself */
+  group?: string;
+  /** The group's type and methods across the package, the target left out */
+  groupMembers: SearchResult[];
+  /** Group members left out by the limit */
+  omittedGroupMembers: number;
+}
+
+/**
+ * Options for finding a symbol's neighbors; a symbol, or a file and line, is required
+ */
+export interface SymbolNeighborOptions {
+  /** Symbol to start from (e.g. "Cache.Get") */
+  symbol?: string;
+  /** File to start from, with `line` */
+  file?: string;
+  /** Line in `file` */
+  line?: number;
+  /** Path prefix to pick between symbols with the same name */
+  path?: string;
+  /** Symbols listed on each side (default: 3) */
+  count?: number;
+  /** Maximum group members listed (default: 30) */
+  groupLimit?: number;
+}
+
+/**
+ * Sections a symbol inspection can include
+ */
+export type InspectionSection = 'definition' | 'callers' | 'callees' | 'implements' | 'git';
+
+/**
+ * An implements edge between a type and an interface
+ */
+export interface ImplementsEdge {
+  /** Package-qualified name of the other end (e.g. "store.Store", "io.Reader") */
+  name: string;
+  /** Its declaration, when indexed (well-known library interfaces have none) */
+  symbol?: SearchResult;
+  /** True when only the pointer type implements the interface */
+  pointer: boolean;
+}
+
+/**
+ * Everything known about a symbol, gathered in one pass
+ */
+export interface SymbolInspection {
+  /** The inspected symbol */
+  target: SearchResult;
+  /** Package directory of the symbol */
+  package: string;
+  /** Sections included, in output order */
+  sections: InspectionSection[];
+  /** Indexed source of the symbol (empty when it didn't fit the token budget) */
+  source: string;
+  /** Symbols calling it */
+  callers: SearchResult[];
+  /** Symbols it calls */
+  callees: SearchResult[];
+  /** Calls left unresolved because they cross a Go `internal` boundary (with callees) */
+  internalViolations: InternalViolation[];
+  /** Interfaces a type implements */
+  implements: ImplementsEdge[];
+  /** Types implementing an interface */
+  implementedBy: ImplementsEdge[];
+  /** Last commit touching the symbol's lines (set when the index was built with blame) */
+  git?: { lastModified: string; lastAuthor?: string };
+  /** Callers, callees, and edges left out because the token budget ran out */
+  omitted: number;
+  /** Estimated tokens of the included content */
+  tokens: number;
+}
+
+/**
+ * Options for inspecting a symbol
+ */
+export interface SymbolInspectionOptions {
+  /** Path prefix to disambiguate symbols with the same name */
+  path?: string;
+  /** Sections to include (default: all) */
+  sections?: InspectionSection[];
+  /** Token budget for source and listed symbols (default: 2000) */
+  maxTokens?: number;
+  /** Include callers and implementations in test files (default: false) */
+  includeTests?: boolean;
+}
+
+/**
+ * An HTTP route with the symbols handling it
+ */
+export interface RouteEntry {
+  /** HTTP method; absent when the route matches any method */
+  method?: string;
+  /** Path pattern with router group prefixes applied */
+  path: string;
+  /** Router the registration was recognized as (`net/http`, `chi`, `gin`, `echo`) */
+  framework: string;
+  /** Function or method registering the route */
+  registeredBy: SearchResult;
+  /** Line of the registration */
+  line: number;
+  /** Handler as written (`h.CreateUser`); absent for an inline func literal */
+  handler?: string;
+  /** Indexed handler symbol, when the handler resolves to one */
+  handlerSymbol?: SearchResult;
+  /** Symbols the handler calls, to follow the request flow */
+  callees: SearchResult[];
+}
+
+/**
+ * Routes registered in one package
+ */
+export interface RouteGroup {
+  /** Package directory relative to the repository root ('' for the root) */
+  package: string;
+  /** Owning Go module, in multi-module repositories */
+  module?: string;
+  /** Routes, by path then method */
+  routes: RouteEntry[];
+}
+
+/**
+ * The HTTP API surface of the indexed repository
+ */
+export interface RouteMap {
+  /** Routes grouped by registering package, in package order */
+  groups: RouteGroup[];
+  /** Routes matching the filters, including any past the limit */
+  total: number;
+  /** Matching routes left out by the limit */
+  omitted: number;
+}
+
+/**
+ * Options for listing routes
+ */
+export interface RouteOptions {
+  /** Only routes whose path starts with this prefix */
+  pathPrefix?: string;
+  /** Only routes serving this HTTP method (routes matching any method are kept) */
+  method?: string;
+  /** Include routes registered in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum routes returned (default: 200) */
+  limit?: number;
+}
+
+/**
+ * How sure a spec-to-handler link is: `high` when method and path match a
+ * route, `medium` when one of them matches loosely, `low` on weaker evidence
+ */
+export type OpenApiMatchConfidence = 'high' | 'medium' | 'low';
+
+/**
+ * A route that likely implements a spec operation
+ */
+export interface OpenApiHandlerMatch {
+  /** The route, with its handler symbol when indexed */
+  route: RouteEntry;
+  confidence: OpenApiMatchConfidence;
+  /** Evidence for the match (`method and path match`) */
+  reason: string;
+}
+
+/**
+ * An operation from an OpenAPI spec with the routes likely implementing it
+ */
+export interface OpenApiOperationEntry {
+  /** Indexed operation document */
+  spec: SearchResult;
+  /** HTTP method (`POST`) */
+  method: string;
+  /** Path template as written in the spec */
+  path: string;
+  /** Path with the spec's base path applied (`/api/v1/users`) */
+  fullPath: string;
+  operationId?: string;
+  summary?: string;
+  /** Best matching routes; empty when no route matches */
+  matches: OpenApiHandlerMatch[];
+}
+
+/**
+ * Operations declared in one spec file
+ */
+export interface OpenApiSpecGroup {
+  /** Spec file relative to the repository root */
+  file: string;
+  /** Operations, in spec order */
+  operations: OpenApiOperationEntry[];
+}
+
+/**
+ * Spec operations linked to the handlers implementing them
+ */
+export interface OpenApiMap {
+  /** Operations grouped by spec file */
+  specs: OpenApiSpecGroup[];
+  /** Operations matching the filters, including any past the limit */
+  total: number;
+  /** Matching operations linked to at least one route */
+  linked: number;
+  /** Matching operations left out by the limit */
+  omitted: number;
+  /** Routes no spec operation matches, when any spec is indexed */
+  unspecifiedRoutes: RouteEntry[];
+}
+
+/**
+ * Options for listing spec operations
+ */
+export interface OpenApiOptions {
+  /** Only operations whose path (with or without the base path) starts with this prefix */
+  pathPrefix?: string;
+  /** Only operations with this HTTP method */
+  method?: string;
+  /** Only operations with this tag */
+  tag?: string;
+  /** Only operations no route matches (default: false) */
+  unlinkedOnly?: boolean;
+  /** Maximum operations returned (default: 200) */
+  limit?: number;
+}
+
+/**
+ * A SQL statement with the symbol running it
+ */
+export interface SqlQueryEntry {
+  /** Statement kind */
+  operation: SqlQuery['operation'];
+  /** Query text with whitespace collapsed */
+  query: string;
+  /** Tables the query names, when trivially parseable */
+  tables: string[];
+  /** Function or method containing the query */
+  runBy: SearchResult;
+  /** Line of the query */
+  line: number;
+}
+
+/**
+ * SQL statements found in one package
+ */
+export interface SqlQueryGroup {
+  /** Package directory relative to the repository root ('' for the root) */
+  package: string;
+  /** Owning Go module, in multi-module repositories */
+  module?: string;
+  /** Queries, by symbol then line */
+  queries: SqlQueryEntry[];
+}
+
+/**
+ * The SQL the indexed repository runs, and the tables it touches
+ */
+export interface SqlQueryMap {
+  /** Queries grouped by package, in package order */
+  groups: SqlQueryGroup[];
+  /** Tables named by the matching queries, most queried first */
+  tables: Array<{ name: string; queries: number }>;
+  /** Queries matching the filters, including any past the limit */
+  total: number;
+  /** Matching queries left out by the limit */
+  omitted: number;
+}
+
+/**
+ * Options for listing SQL queries
+ */
+export interface SqlQueryOptions {
+  /** Only queries naming this table (`users` also matches `public.users`) */
+  table?: string;
+  /** Only queries of this kind */
+  operation?: SqlQuery['operation'];
+  /** Only queries in files under this path prefix */
+  pathPrefix?: string;
+  /** Include queries in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum queries returned (default: 200) */
+  limit?: number;
+}
+
+/**
+ * A call from one function in a recursive cycle to another (or itself)
+ */
+export interface RecursiveCall {
+  caller: SearchResult;
+  callee: SearchResult;
+  /** Line of the call in the caller's file */
+  line: number;
+}
+
+/**
+ * Functions that can reach themselves through calls
+ */
+export interface RecursiveCycle {
+  /** `self` for a function calling itself directly; `mutual` for two or more */
+  kind: 'self' | 'mutual';
+  /** The functions, in index order */
+  symbols: SearchResult[];
+  /** Shortest cycle through the first function, as package-qualified names; ends where it starts */
+  path: string[];
+  /** Every call between the functions */
+  calls: RecursiveCall[];
+}
+
+/**
+ * Recursive cycles across the repository or touching a package
+ */
+export interface RecursionReport {
+  /** Package directory searched, or "." for the whole repository */
+  scope: string;
+  /** Cycles, in index order of their first function */
+  cycles: RecursiveCycle[];
+  /** Cycles left out by the limit */
+  omitted: number;
+}
+
+/**
+ * Options for finding recursion
+ */
+export interface RecursionOptions {
+  /** Only cycles with a function in this package directory (e.g. "internal/parser") */
+  package?: string;
+  /** Include functions in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum cycles returned (default: 50) */
+  limit?: number;
+}
+
+/**
+ * One call on a call path
+ */
+export interface CallPathStep {
+  caller: SearchResult;
+  callee: SearchResult;
+  /** Line of the call in the caller's file (the first, when it calls the callee more than once) */
+  line: number;
+  /** The call's source line, trimmed; empty when it's past a truncated indexed snippet */
+  snippet: string;
+}
+
+/**
+ * Call paths from one symbol to another, shortest first
+ */
+export interface CallPaths {
+  /** Starting symbol, or null if it isn't indexed */
+  from: SearchResult | null;
+  /** Symbol to reach, or null if it isn't indexed */
+  to: SearchResult | null;
+  /** Simple paths (no symbol twice), each a list of calls; empty when none is within maxDepth */
+  paths: CallPathStep[][];
+  /** Calls on the shortest path at any depth, or null when `to` can't be reached at all */
+  shortest: number | null;
+  /** Longest path searched, in calls */
+  maxDepth: number;
+  /** True when the search budget ran out before `limit` paths were found */
+  truncated: boolean;
+  /** Caller files that changed since indexing, whose call lines come from the index */
+  staleFiles: string[];
+}
+
+/**
+ * Options for finding call paths
+ */
+export interface CallPathOptions {
+  /** Path prefix to disambiguate the starting symbol */
+  fromPath?: string;
+  /** Path prefix to disambiguate the symbol to reach */
+  toPath?: string;
+  /** Maximum paths returned (default: 1, the shortest) */
+  limit?: number;
+  /** Longest path searched, in calls (default: 6) */
+  maxDepth?: number;
+  /** Follow calls made from test files (default: false) */
+  includeTests?: boolean;
+}
+
+/**
+ * How one call graph node relates to another
+ */
+export type CallGraphEdgeKind = 'call' | 'implements';
+
+/**
+ * A symbol in an exported call graph
+ */
+export interface CallGraphNode {
+  /** Document ID, or `external:<name>` for a symbol outside the index */
+  id: string;
+  /** Package-qualified name (e.g. "internal/users.Server.CreateUser", "fmt.Println") */
+  label: string;
+  /** Document type (function, method, interface, ...) */
+  kind: string;
+  /** File, for indexed symbols */
+  file?: string;
+  /** Start line, for indexed symbols */
+  line?: number;
+  /** True for standard library, third-party, and unindexed symbols */
+  external: boolean;
+}
+
+/**
+ * A directed edge: `from` calls `to`, or `from` implements interface `to`
+ */
+export interface CallGraphEdge {
+  from: string;
+  to: string;
+  kind: CallGraphEdgeKind;
+  /** Go: every call behind the edge depends on a type switch case or comma-ok assertion */
+  guarded?: boolean;
+}
+
+/**
+ * The call graph around a symbol or across a package
+ */
+export interface CallGraph {
+  /** Symbol label or package directory the graph was built from */
+  scope: string;
+  /** Hops followed from the starting symbols */
+  depth: number;
+  /** Nodes, starting symbols first, then in the order they were reached */
+  nodes: CallGraphNode[];
+  /** Edges between included nodes */
+  edges: CallGraphEdge[];
+  /** Reachable nodes left out by the node limit */
+  omitted: number;
+}
+
+/**
+ * Direction in which a call graph is followed from its starting symbols
+ */
+export type CallGraphDirection = 'callees' | 'callers' | 'both';
+
+/**
+ * Options for exporting a call graph; one of symbol or package is required
+ */
+export interface CallGraphOptions {
+  /** Start from this symbol (e.g. "Server.CreateUser") */
+  symbol?: string;
+  /** Start from every symbol in this package directory, or a trailing part of it */
+  package?: string;
+  /** Path prefix to disambiguate a symbol name */
+  path?: string;
+  /** Hops to follow from the starting symbols (default: 1) */
+  depth?: number;
+  /** Follow callees, callers, or both (default: both) */
+  direction?: CallGraphDirection;
+  /** Include calls to symbols outside the index, such as the standard library (default: false) */
+  includeExternal?: boolean;
+  /** Include implements edges between types and interfaces (default: true) */
+  includeImplements?: boolean;
+  /** Include symbols in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum nodes (default: 200) */
+  limit?: number;
+}
+
+/**
+ * Output format of an exported call graph
+ */
+export type CallGraphFormat = 'dot' | 'json';
+
+/**
+ * How a type-parameter constraint is defined
+ *
+ * - constraint: an indexed interface with a type set, usable only as a constraint
+ * - interface: an indexed method interface that is also used as a constraint
+ * - external: not indexed (e.g. `constraints.Ordered`, or a missing package file)
+ */
+export type ConstraintKind = 'constraint' | 'interface' | 'external';
+
+/**
+ * A generic constraint and the generic functions and types using it
+ */
+export interface ConstraintEntry {
+  /** Constraint name as defined, or as referenced when not indexed */
+  name: string;
+  kind: ConstraintKind;
+  /** Indexed interface defining the constraint */
+  definition?: SearchResult;
+  /** Type set as written in the definition */
+  typeSet?: GoTypeSet;
+  /** Union terms with embedded indexed constraints expanded (e.g. Ordered to ~int, ~string) */
+  underlying?: string[];
+  /** Generic functions and types with a type parameter constrained by it, in path order */
+  users: SearchResult[];
+}
+
+/**
+ * Generic constraints in the indexed repository
+ */
+export interface ConstraintMap {
+  /** Constraints, most used first */
+  constraints: ConstraintEntry[];
+  /** Constraints matching the filters, including any past the limit */
+  total: number;
+  /** Matching constraints left out by the limit */
+  omitted: number;
+}
+
+/**
+ * Options for listing generic constraints
+ */
+export interface ConstraintOptions {
+  /** Only this constraint (`Ordered` also matches `constraints.Ordered`) */
+  name?: string;
+  /** Include constraints and users in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum constraints returned (default: 100) */
+  limit?: number;
+}
+
+/**
+ * Indexed package importing a dependency
+ */
+export interface DependencyImporter {
+  /** Package directory relative to the repository root ("" for the root) */
+  dir: string;
+  /** Its files importing the dependency, sorted */
+  files: string[];
+}
+
+/**
+ * A module dependency of one of the repository's Go modules, with the code importing it
+ */
+export interface DependencyEntry {
+  /** The dependency, at its effective version */
+  dependency: GoDependency;
+  /** Path of the repository module requiring it */
+  requiredBy: string;
+  /** Directory of that module's go.mod ("." for the root) */
+  moduleDir: string;
+  /** Indexed packages importing it, in path order; empty for transitive-only dependencies */
+  importers: DependencyImporter[];
+}
+
+/**
+ * Go module dependencies of the repository
+ */
+export interface DependencyMap {
+  /** Dependencies, sorted by path then requiring module */
+  dependencies: DependencyEntry[];
+  /** Dependencies matching the filters, including any past the limit */
+  total: number;
+  /** Matching dependencies left out by the limit */
+  omitted: number;
+  /** Go modules found in the repository */
+  modules: number;
+}
+
+/**
+ * Options for listing Go module dependencies
+ */
+export interface DependencyOptions {
+  /** Only dependencies whose module path (or replacement) contains this, case-insensitively */
+  module?: string;
+  /** Include indirect dependencies (default: true) */
+  includeIndirect?: boolean;
+  /** Count importers in test files (default: false) */
+  includeTests?: boolean;
+  /** Maximum dependencies returned (default: 200) */
+  limit?: number;
+}
@@ -0,0 +1,240 @@
+import { describe, expect, it } from 'vitest';
+import { buildChangelog, formatChangelog } from '../changelog';
+import type { RevisionDiff, SymbolSnapshot } from '../types';
+
+function symbol(
+  name: string,
+  file: string,
+  overrides: Partial<SymbolSnapshot> = {}
+): SymbolSnapshot {
+  return {
+    name,
+    type: 'function',
+    file,
+    startLine: 1,
+    signature: `func ${name}()`,
+    exported: true,
+    ...overrides,
+  };
+}
+
+function diff(overrides: Partial<RevisionDiff> = {}): RevisionDiff {
+  return {
+    base: 'v1.0.0',
+    head: 'v1.1.0',
+    filesChanged: [],
+    added: [],
+    removed: [],
+    changed: [],
+    renamed: [],
+    deprecated: [],
+    tagsChanged: [],
+    ...overrides,
+  };
+}
+
+describe('buildChangelog', () => {
+  it('should group exported changes by package', () => {
+    const changelog = buildChangelog(
+      diff({
+        added: [symbol('NewServer', 'server/server.go'), symbol('Parse', 'config/parse.go')],
+        removed: [symbol('Legacy', 'server/legacy.go')],
+      })
+    );
+
+    expect(changelog.packages.map((p) => p.package)).toEqual(['config', 'server']);
+    expect(changelog.packages[1].added.map((s) => s.name)).toEqual(['NewServer']);
+    expect(changelog.packages[1].removed.map((s) => s.name)).toEqual(['Legacy']);
+  });
+
+  it('should count removals, renames, and signature changes as breaking', () => {
+    const changelog = buildChangelog(
+      diff({
+        added: [symbol('Fresh', 'a.go')],
+        removed: [symbol('Gone', 'a.go')],
+        changed: [
+          {
+            before: symbol('Load', 'a.go'),
+            after: symbol('Load', 'a.go', { signature: 'func Load(ctx context.Context)' }),
+          },
+        ],
+        renamed: [{ before: symbol('Fetch', 'a.go'), after: symbol('Get', 'a.go') }],
+        deprecated: [symbol('Dial', 'a.go', { deprecated: 'Use DialContext.' })],
+      })
+    );
+
+    expect(changelog.breakingChanges).toBe(3);
+    expect(changelog.packages).toHaveLength(1);
+    expect(changelog.packages[0].package).toBe('.');
+    expect(changelog.packages[0].deprecated.map((s) => s.name)).toEqual(['Dial']);
+  });
+
+  it('should leave out test files', () => {
+    const changelog = buildChangelog(
+      diff({
+        added: [
+          symbol('TestServer', 'server/server_test.go'),
+          symbol('render', 'src/__tests__/render.ts'),
+        ],
+        removed: [symbol('TestOld', 'server/old_test.go', { exported: false })],
+      })
+    );
+
+    expect(changelog.packages).toEqual([]);
+    expect(changelog.breakingChanges).toBe(0);
+  });
+
+  it('should demote unexported churn to internal changes', () => {
+    const changelog = buildChangelog(
+      diff({
+        added: [symbol('helper', 'a.go', { exported: false })],
+        removed: [symbol('old', 'a.go', { exported: false })],
+        changed: [
+          {
+            before: symbol('parse', 'a.go', { exported: false }),
+            after: symbol('parse', 'a.go', { exported: false, signature: 'func parse(s string)' }),
+          },
+        ],
+        renamed: [
+          {
+            before: symbol('load', 'a.go', { exported: false }),
+            after: symbol('read', 'a.go', { exported: false }),
+          },
+        ],
+      })
+    );
+
+    const [pkg] = changelog.packages;
+    expect(pkg.internal.map((c) => [c.change, c.symbol.name])).toEqual([
+      ['added', 'helper'],
+      ['removed', 'old'],
+      ['changed', 'parse'],
+      ['renamed', 'read'],
+    ]);
+    expect(pkg.internal[3].previousName).toBe('load');
+    expect(pkg.added).toEqual([]);
+    expect(pkg.removed).toEqual([]);
+    expect(changelog.breakingChanges).toBe(0);
+  });
+
+  it('should count tag changes on exported fields of exported structs as breaking', () => {
+    const user = symbol('User', 'model/user.go', { type: 'class' });
+    const field = (name: string, tag: string) => ({
+      name,
+      type: 'string',
+      exported: name[0] === name[0].toUpperCase(),
+      embedded: false,
+      tag,
+    });
+    const changelog = buildChangelog(
+      diff({
+        tagsChanged: [
+          {
+            struct: user,
+            field: field('Email', 'json:"email_address"'),
+            before: 'json:"email"',
+            after: 'json:"email_address"',
+          },
+          { struct: user, field: field('token', 'json:"t"'), before: undefined, after: 'json:"t"' },
+          {
+            struct: symbol('row', 'model/row.go', { type: 'class', exported: false }),
+            field: field('ID', 'db:"row_id"'),
+            before: 'db:"id"',
+            after: 'db:"row_id"',
+          },
+        ],
+      })
+    );
+
+    expect(changelog.packages[0].tagsChanged.map((t) => t.field.name)).toEqual(['Email']);
+    expect(changelog.breakingChanges).toBe(1);
+  });
+
+  it('should treat unexporting as a removal and exporting as an addition', () => {
+    const changelog = buildChangelog(
+      diff({
+        changed: [
+          {
+            before: symbol('Parse', 'a.go'),
+            after: symbol('parse', 'a.go', { exported: false, signature: 'func parse(s string)' }),
+          },
+        ],
+        renamed: [
+          { before: symbol('dial', 'a.go', { exported: false }), after: symbol('Dial', 'a.go') },
+        ],
+      })
+    );
+
+    const [pkg] = changelog.packages;
+    expect(pkg.removed.map((s) => s.name)).toEqual(['Parse']);
+    expect(pkg.added.map((s) => s.name)).toEqual(['Dial']);
+    expect(pkg.changed).toEqual([]);
+    expect(pkg.renamed).toEqual([]);
+    expect(changelog.breakingChanges).toBe(1);
+  });
+});
+
+describe('formatChangelog', () => {
+  it('should render grouped markdown with breaking changes marked', () => {
+    const output = formatChangelog(
+      buildChangelog(
+        diff({
+          added: [symbol('NewServer', 'server/server.go')],
+          removed: [symbol('Legacy', 'server/legacy.go')],
+          changed: [
+            {
+              before: symbol('Load', 'config/load.go'),
+              after: symbol('Load', 'config/load.go', { signature: 'func Load(path string)' }),
+            },
+          ],
+          deprecated: [symbol('Dial', 'server/dial.go', { deprecated: 'Use DialContext.' })],
+        })
+      )
+    );
+
+    expect(output).toContain('# API Changes: v1.0.0..v1.1.0');
+    expect(output).toContain('**2 breaking change(s)**');
+    expect(output.indexOf('## config')).toBeLessThan(output.indexOf('## server'));
+    expect(output).toContain('### Changed Signatures');
+    expect(output).toContain('- **BREAKING:** `Load` (function)');
+    expect(output).toContain('  - after: `func Load(path string)`');
+    expect(output).toContain('- **BREAKING:** `Legacy` (function): `func Legacy()`');
+    expect(output).toContain('### Added\n\n- `NewServer` (function): `func NewServer()`');
+    expect(output).toContain('### Deprecated\n\n- `Dial`: Use DialContext.');
+  });
+
+  it('should render struct tag changes and internal changes', () => {
+    const output = formatChangelog(
+      buildChangelog(
+        diff({
+          added: [symbol('helper', 'model/util.go', { exported: false })],
+          tagsChanged: [
+            {
+              struct: symbol('User', 'model/user.go', { type: 'class' }),
+              field: { name: 'Email', type: 'string', exported: true, embedded: false },
+              before: 'json:"email"',
+              after: undefined,
+            },
+          ],
+        })
+      )
+    );
+
+    expect(output).toContain('**1 breaking change(s)**');
+    expect(output).toContain(
+      '### Changed Struct Tags\n\n- **BREAKING:** `User.Email` serialization: ' +
+        '`json:"email"` → (untagged)'
+    );
+    expect(output).toContain('### Internal Changes\n\n- `helper` (function) added');
+  });
+
+  it('should say when there are no API changes', () => {
+    expect(formatChangelog(buildChangelog(diff()))).toContain('*No exported API changes*');
+
+    const internalOnly = formatChangelog(
+      buildChangelog(diff({ added: [symbol('helper', 'a.go', { exported: false })] }))
+    );
+    expect(internalOnly).toContain('*No exported API changes*');
+    expect(internalOnly).toContain('## (root)\n\n### Internal Changes');
+  });
+});
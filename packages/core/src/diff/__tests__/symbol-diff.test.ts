@@ -0,0 +1,229 @@
+import { describe, expect, it } from 'vitest';
+import type { Document, StructField } from '../../scanner/types';
+import { type RevisionReader, diffRevisions } from '../revision-diff';
+import { diffSymbols, toSymbolSnapshot } from '../symbol-diff';
+
+function doc(
+  name: string,
+  signature: string,
+  overrides: Partial<Document['metadata']> & { type?: Document['type'] } = {}
+): Document {
+  const { type = 'function', ...metadata } = overrides;
+  return {
+    id: `src/a.ts:${name}:1`,
+    text: signature,
+    type,
+    language: 'typescript',
+    metadata: {
+      file: 'src/a.ts',
+      startLine: 1,
+      endLine: 5,
+      name,
+      signature,
+      exported: true,
+      ...metadata,
+    },
+  };
+}
+
+describe('diffSymbols', () => {
+  it('should report added and removed symbols', () => {
+    const before = [doc('keep', 'function keep(): void'), doc('gone', 'function gone(a: number)')];
+    const after = [doc('keep', 'function keep(): void'), doc('fresh', 'function fresh(): string')];
+
+    const diff = diffSymbols(before, after);
+
+    expect(diff.added.map((s) => s.name)).toEqual(['fresh']);
+    expect(diff.removed.map((s) => s.name)).toEqual(['gone']);
+    expect(diff.changed).toEqual([]);
+    expect(diff.renamed).toEqual([]);
+  });
+
+  it('should report signature changes', () => {
+    const before = [doc('load', 'function load(path: string): Data')];
+    const after = [doc('load', 'function load(path: string, opts?: Options): Data')];
+
+    const diff = diffSymbols(before, after);
+
+    expect(diff.changed).toHaveLength(1);
+    expect(diff.changed[0].before.signature).toBe('function load(path: string): Data');
+    expect(diff.changed[0].after.signature).toBe(
+      'function load(path: string, opts?: Options): Data'
+    );
+  });
+
+  it('should ignore whitespace-only signature changes', () => {
+    const before = [doc('load', 'function load(path: string): Data')];
+    const after = [doc('load', 'function load(path:  string):\n  Data')];
+
+    expect(diffSymbols(before, after).changed).toEqual([]);
+  });
+
+  it('should detect renames with the same signature shape', () => {
+    const before = [doc('fetchUser', 'function fetchUser(id: string): Promise<User>')];
+    const after = [doc('getUser', 'function getUser(id: string): Promise<User>')];
+
+    const diff = diffSymbols(before, after);
+
+    expect(diff.renamed).toHaveLength(1);
+    expect(diff.renamed[0].before.name).toBe('fetchUser');
+    expect(diff.renamed[0].after.name).toBe('getUser');
+    expect(diff.added).toEqual([]);
+    expect(diff.removed).toEqual([]);
+  });
+
+  it('should detect renamed Go methods by their short name', () => {
+    const before = [
+      doc('Server.Start', 'func (s *Server) Start(ctx context.Context) error', {
+        type: 'method',
+        file: 'server.go',
+      }),
+    ];
+    const after = [
+      doc('Server.Run', 'func (s *Server) Run(ctx context.Context) error', {
+        type: 'method',
+        file: 'server.go',
+      }),
+    ];
+
+    expect(diffSymbols(before, after).renamed).toHaveLength(1);
+  });
+
+  it('should not treat different files or types as renames', () => {
+    const before = [doc('a', 'function a(): void')];
+    const after = [
+      doc('b', 'function b(): void', { file: 'src/other.ts' }),
+      doc('c', 'c(): void', { type: 'method' }),
+    ];
+
+    const diff = diffSymbols(before, after);
+
+    expect(diff.renamed).toEqual([]);
+    expect(diff.removed).toHaveLength(1);
+    expect(diff.added).toHaveLength(2);
+  });
+
+  it('should pair the nearest candidate when several match', () => {
+    const before = [doc('old', 'function old(): void', { startLine: 40 })];
+    const after = [
+      doc('far', 'function far(): void', { startLine: 1 }),
+      doc('near', 'function near(): void', { startLine: 42 }),
+    ];
+
+    const diff = diffSymbols(before, after);
+
+    expect(diff.renamed[0].after.name).toBe('near');
+    expect(diff.added.map((s) => s.name)).toEqual(['far']);
+  });
+
+  it('should report symbols newly marked deprecated', () => {
+    const before = [doc('Dial', 'func Dial(addr string) (*Conn, error)'), doc('Old', 'func Old()')];
+    const after = [
+      doc('Dial', 'func Dial(addr string) (*Conn, error)', {
+        docstring:
+          'Dial connects to addr.\n\nDeprecated: Use DialContext instead.\nIt ignores\ntimeouts.',
+      }),
+      doc('Old', 'func Old()', { docstring: 'Old is old.' }),
+    ];
+
+    const diff = diffSymbols(before, after);
+
+    expect(diff.deprecated.map((s) => s.name)).toEqual(['Dial']);
+    expect(diff.deprecated[0].deprecated).toBe('Use DialContext instead. It ignores timeouts.');
+    expect(diff.changed).toEqual([]);
+  });
+
+  it('should not report symbols that were already deprecated', () => {
+    const notice = { docstring: 'Helper does things.\n@deprecated use other()' };
+    const before = [doc('helper', 'function helper(): void', notice)];
+    const after = [doc('helper', 'function helper(): void', notice)];
+
+    expect(toSymbolSnapshot(after[0])?.deprecated).toBe('use other()');
+    expect(diffSymbols(before, after).deprecated).toEqual([]);
+  });
+
+  it('should report struct fields whose tag changed', () => {
+    const struct = (fields: StructField[]) =>
+      doc('User', 'type User struct', { custom: { fields } });
+    const field = (name: string, tag?: string): StructField => ({
+      name,
+      type: 'string',
+      exported: true,
+      embedded: false,
+      ...(tag ? { tag } : {}),
+    });
+
+    const diff = diffSymbols(
+      [struct([field('ID', 'json:"id"'), field('Email', 'json:"email"'), field('Name')])],
+      [
+        struct([
+          field('ID', 'json:"id"'),
+          field('Email', 'json:"email_address"'),
+          field('Name', 'json:"name"'),
+          field('Phone', 'json:"phone"'),
+        ]),
+      ]
+    );
+
+    expect(diff.tagsChanged.map((t) => [t.field.name, t.before, t.after])).toEqual([
+      ['Email', 'json:"email"', 'json:"email_address"'],
+      ['Name', undefined, 'json:"name"'],
+    ]);
+    expect(diff.tagsChanged[0].struct.name).toBe('User');
+    expect(diff.changed).toEqual([]);
+  });
+
+  it('should skip documentation and unnamed documents', () => {
+    expect(toSymbolSnapshot(doc('README', '', { type: 'documentation' }))).toBeNull();
+    expect(toSymbolSnapshot(doc('', 'function (): void'))).toBeNull();
+  });
+});
+
+describe('diffRevisions', () => {
+  function createReader(files: Record<string, Record<string, string>>): RevisionReader {
+    return {
+      resolveRevision: async (revision) => revision,
+      getChangedFiles: async () => Object.keys({ ...files.base, ...files.head }).sort(),
+      getFileAtRevision: async (revision, file) => files[revision]?.[file] ?? null,
+    };
+  }
+
+  it('should scan both sides of the changed files', async () => {
+    const reader = createReader({
+      base: {
+        'src/users.ts': 'export function fetchUser(id: string): string {\n  return id;\n}\n',
+        'src/legacy.ts': 'export function legacy(): void {}\n',
+      },
+      head: {
+        'src/users.ts': 'export function getUser(id: string): string {\n  return id;\n}\n',
+        'src/new.ts': 'export function created(): number {\n  return 1;\n}\n',
+      },
+    });
+
+    const diff = await diffRevisions(
+      { repositoryPath: '/nonexistent', base: 'base', head: 'head' },
+      reader
+    );
+
+    expect(diff.filesChanged).toEqual(['src/legacy.ts', 'src/new.ts', 'src/users.ts']);
+    expect(diff.added.map((s) => s.name)).toEqual(['created']);
+    expect(diff.removed.map((s) => s.name)).toEqual(['legacy']);
+    expect(diff.renamed.map((r) => [r.before.name, r.after.name])).toEqual([
+      ['fetchUser', 'getUser'],
+    ]);
+  });
+
+  it('should filter changed files by path prefix', async () => {
+    const reader = createReader({
+      base: {},
+      head: { 'src/a.ts': 'export const a = 1;\n', 'docs/b.ts': 'export const b = 2;\n' },
+    });
+
+    const diff = await diffRevisions(
+      { repositoryPath: '/nonexistent', base: 'base', head: 'head', pathPrefix: 'src/' },
+      reader
+    );
+
+    expect(diff.filesChanged).toEqual(['src/a.ts']);
+  });
+});
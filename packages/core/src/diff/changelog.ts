@@ -0,0 +1,206 @@
+/**
+ * API Changelog
+ *
+ * Turns a revision diff into release notes for the exported API: changes are
+ * grouped by package, test files are left out, and removals, renames,
+ * signature changes, and field tag changes of exported symbols are marked
+ * breaking. Unexported churn is listed separately as internal changes.
+ */
+
+import * as path from 'node:path';
+import { isTestFile } from '../utils/test-utils';
+import { diffRevisions, type RevisionReader } from './revision-diff';
+import type {
+  Changelog,
+  ChangelogPackage,
+  InternalChange,
+  RevisionDiff,
+  RevisionDiffOptions,
+  SymbolSnapshot,
+} from './types';
+
+/**
+ * Diff two revisions and build the changelog of their exported API
+ *
+ * @param options - Repository and revisions to compare (typically two tags)
+ * @param reader - Git reader (default: LocalGitExtractor for the repository)
+ */
+export async function generateChangelog(
+  options: RevisionDiffOptions,
+  reader?: RevisionReader
+): Promise<Changelog> {
+  return buildChangelog(await diffRevisions(options, reader));
+}
+
+/**
+ * Group a revision diff's exported-symbol changes by package
+ *
+ * A symbol that became unexported counts as removed, and one that became
+ * exported as added, whether or not its signature or name changed too.
+ * Changes to symbols unexported at both revisions are internal, never breaking.
+ */
+export function buildChangelog(diff: RevisionDiff): Changelog {
+  const packages = new Map<string, ChangelogPackage>();
+  const entry = (symbol: SymbolSnapshot): ChangelogPackage => {
+    const name = path.posix.dirname(symbol.file);
+    let pkg = packages.get(name);
+    if (!pkg) {
+      pkg = {
+        package: name,
+        added: [],
+        removed: [],
+        changed: [],
+        renamed: [],
+        deprecated: [],
+        tagsChanged: [],
+        internal: [],
+      };
+      packages.set(name, pkg);
+    }
+    return pkg;
+  };
+  const isApi = (symbol: SymbolSnapshot) => symbol.exported && !isTestPath(symbol.file);
+  const isInternal = (symbol: SymbolSnapshot) => !symbol.exported && !isTestPath(symbol.file);
+  const internal = (change: InternalChange) => entry(change.symbol).internal.push(change);
+
+  for (const symbol of diff.added) {
+    if (isApi(symbol)) entry(symbol).added.push(symbol);
+    else if (isInternal(symbol)) internal({ change: 'added', symbol });
+  }
+  for (const symbol of diff.removed) {
+    if (isApi(symbol)) entry(symbol).removed.push(symbol);
+    else if (isInternal(symbol)) internal({ change: 'removed', symbol });
+  }
+  for (const pair of [...diff.changed, ...diff.renamed]) {
+    const { before, after } = pair;
+    const renamed = before.name !== after.name;
+    if (isApi(before) && isApi(after)) {
+      const pkg = entry(after);
+      if (renamed) pkg.renamed.push(pair);
+      else pkg.changed.push(pair);
+    } else if (isApi(before)) {
+      entry(before).removed.push(before);
+    } else if (isApi(after)) {
+      entry(after).added.push(after);
+    } else if (isInternal(before) && isInternal(after)) {
+      internal(
+        renamed
+          ? { change: 'renamed', symbol: after, previousName: before.name }
+          : { change: 'changed', symbol: after }
+      );
+    }
+  }
+  for (const symbol of diff.deprecated.filter(isApi)) entry(symbol).deprecated.push(symbol);
+  for (const change of diff.tagsChanged) {
+    if (isApi(change.struct) && change.field.exported) {
+      entry(change.struct).tagsChanged.push(change);
+    }
+  }
+
+  const sorted = Array.from(packages.values()).sort((a, b) =>
+    a.package < b.package ? -1 : a.package > b.package ? 1 : 0
+  );
+  return {
+    base: diff.base,
+    head: diff.head,
+    packages: sorted,
+    breakingChanges: sorted.reduce(
+      (sum, pkg) =>
+        sum +
+        pkg.removed.length +
+        pkg.changed.length +
+        pkg.renamed.length +
+        pkg.tagsChanged.length,
+      0
+    ),
+  };
+}
+
+/**
+ * Format a changelog as markdown release notes
+ */
+export function formatChangelog(changelog: Changelog): string {
+  const lines: string[] = [`# API Changes: ${changelog.base}..${changelog.head}`, ''];
+  if (!changelog.packages.some(hasApiChanges)) {
+    lines.push('*No exported API changes*');
+    if (changelog.packages.length === 0) return lines.join('\n');
+  } else {
+    lines.push(
+      changelog.breakingChanges > 0
+        ? `**${changelog.breakingChanges} breaking change(s)**`
+        : 'No breaking changes'
+    );
+  }
+
+  for (const pkg of changelog.packages) {
+    lines.push('', `## ${pkg.package === '.' ? '(root)' : pkg.package}`);
+    pushSection(lines, 'Removed', pkg.removed.map((s) => `- **BREAKING:** ${describeSymbol(s)}`));
+    pushSection(
+      lines,
+      'Renamed',
+      pkg.renamed.map((r) => `- **BREAKING:** \`${r.before.name}\` → ${describeSymbol(r.after)}`)
+    );
+    pushSection(
+      lines,
+      'Changed Signatures',
+      pkg.changed.map(
+        (c) =>
+          `- **BREAKING:** \`${c.after.name}\` (${c.after.type})\n` +
+          `  - before: \`${c.before.signature ?? ''}\`\n` +
+          `  - after: \`${c.after.signature ?? ''}\``
+      )
+    );
+    pushSection(
+      lines,
+      'Changed Struct Tags',
+      pkg.tagsChanged.map(
+        (t) =>
+          `- **BREAKING:** \`${t.struct.name}.${t.field.name}\` serialization: ` +
+          `${describeTag(t.before)} → ${describeTag(t.after)}`
+      )
+    );
+    pushSection(lines, 'Added', pkg.added.map((s) => `- ${describeSymbol(s)}`));
+    pushSection(
+      lines,
+      'Deprecated',
+      pkg.deprecated.map((s) => `- \`${s.name}\`${s.deprecated ? `: ${s.deprecated}` : ''}`)
+    );
+    pushSection(lines, 'Internal Changes', pkg.internal.map(describeInternal));
+  }
+
+  return lines.join('\n');
+}
+
+function pushSection(lines: string[], title: string, entries: string[]): void {
+  if (entries.length === 0) return;
+  lines.push('', `### ${title}`, '', ...entries);
+}
+
+function hasApiChanges(pkg: ChangelogPackage): boolean {
+  const { added, removed, changed, renamed, deprecated, tagsChanged } = pkg;
+  return [added, removed, changed, renamed, deprecated, tagsChanged].some((list) => list.length);
+}
+
+function describeInternal({ change, symbol, previousName }: InternalChange): string {
+  const what =
+    change === 'renamed'
+      ? `renamed from \`${previousName}\``
+      : change === 'changed'
+        ? 'signature changed'
+        : change;
+  return `- \`${symbol.name}\` (${symbol.type}) ${what}`;
+}
+
+function describeTag(tag: string | undefined): string {
+  return tag ? `\`${tag}\`` : '(untagged)';
+}
+
+function describeSymbol(symbol: SymbolSnapshot): string {
+  return symbol.signature
+    ? `\`${symbol.name}\` (${symbol.type}): \`${symbol.signature}\``
+    : `\`${symbol.name}\` (${symbol.type})`;
+}
+
+function isTestPath(file: string): boolean {
+  return isTestFile(file) || file.endsWith('_test.go') || file.includes('/__tests__/');
+}
@@ -0,0 +1,21 @@
+/**
+ * Diff Module
+ *
+ * Symbol-level comparison of two git revisions, and API changelogs built from it.
+ */
+
+export { buildChangelog, formatChangelog, generateChangelog } from './changelog';
+export { diffRevisions, type RevisionReader } from './revision-diff';
+export { diffSymbols, toSymbolSnapshot } from './symbol-diff';
+export type {
+  Changelog,
+  ChangelogPackage,
+  FieldTagChange,
+  InternalChange,
+  RevisionDiff,
+  RevisionDiffOptions,
+  SymbolChange,
+  SymbolDiff,
+  SymbolRename,
+  SymbolSnapshot,
+} from './types';
@@ -0,0 +1,112 @@
+/**
+ * Revision Diff
+ *
+ * Materializes the changed files of two git revisions into temporary
+ * directories, scans each side, and compares the results symbol by symbol.
+ */
+
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { LocalGitExtractor } from '../git/extractor';
+import { loadIgnoreFile, scanRepository } from '../scanner';
+import type { Document } from '../scanner/types';
+import { diffSymbols } from './symbol-diff';
+import type { RevisionDiff, RevisionDiffOptions } from './types';
+
+/**
+ * Git operations needed to diff revisions
+ */
+export interface RevisionReader {
+  resolveRevision(revision: string): Promise<string>;
+  getChangedFiles(base: string, head: string): Promise<string[]>;
+  getFileAtRevision(revision: string, file: string): Promise<string | null>;
+}
+
+/**
+ * Compute a symbol-level diff between two revisions
+ *
+ * @param options - Repository and revisions to compare
+ * @param reader - Git reader (default: LocalGitExtractor for the repository)
+ * @throws If either revision cannot be resolved
+ */
+export async function diffRevisions(
+  options: RevisionDiffOptions,
+  reader: RevisionReader = new LocalGitExtractor(options.repositoryPath)
+): Promise<RevisionDiff> {
+  const head = options.head ?? 'HEAD';
+  const baseCommit = await reader.resolveRevision(options.base);
+  const headCommit = await reader.resolveRevision(head);
+
+  let files = await reader.getChangedFiles(baseCommit, headCommit);
+  if (options.pathPrefix) {
+    const prefix = options.pathPrefix.replace(/^\.\//, '');
+    files = files.filter((file) => file.startsWith(prefix));
+  }
+
+  if (files.length === 0) {
+    return {
+      base: options.base,
+      head,
+      filesChanged: [],
+      added: [],
+      removed: [],
+      changed: [],
+      renamed: [],
+      deprecated: [],
+      tagsChanged: [],
+    };
+  }
+
+  const ignore = await loadIgnoreFile(options.repositoryPath);
+  const [before, after] = await Promise.all([
+    scanRevision(reader, baseCommit, files, ignore),
+    scanRevision(reader, headCommit, files, ignore),
+  ]);
+
+  return {
+    base: options.base,
+    head,
+    filesChanged: files,
+    ...diffSymbols(before, after),
+  };
+}
+
+/**
+ * Write the given files at a revision to a temp directory and scan them
+ */
+async function scanRevision(
+  reader: RevisionReader,
+  revision: string,
+  files: string[],
+  ignore: string[]
+): Promise<Document[]> {
+  const tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), 'dev-agent-diff-'));
+
+  try {
+    const present: string[] = [];
+    for (const file of files) {
+      const content = await reader.getFileAtRevision(revision, file);
+      if (content === null) continue;
+
+      const target = path.join(tmpDir, file);
+      await fs.mkdir(path.dirname(target), { recursive: true });
+      await fs.writeFile(target, content, 'utf-8');
+      present.push(file);
+    }
+
+    if (present.length === 0) {
+      return [];
+    }
+
+    const result = await scanRepository({
+      repoRoot: tmpDir,
+      include: present,
+      ignore,
+      respectGitignore: false,
+    });
+    return result.documents;
+  } finally {
+    await fs.rm(tmpDir, { recursive: true, force: true });
+  }
+}
@@ -0,0 +1,192 @@
+/**
+ * Symbol Diff
+ *
+ * Pure comparison of two sets of scanned documents at the symbol level.
+ * Symbols are matched by file + type + name. Unmatched pairs in the same
+ * file with the same type and an identical signature shape (name aside)
+ * are reported as renames.
+ */
+
+import type { Document, StructField } from '../scanner/types';
+import type {
+  FieldTagChange,
+  SymbolChange,
+  SymbolDiff,
+  SymbolRename,
+  SymbolSnapshot,
+} from './types';
+
+/** A Go `Deprecated:` paragraph or JSDoc `@deprecated` tag, capturing the notice */
+const DEPRECATION_PATTERN =
+  /(?:^|\n)[ \t*]*(?:Deprecated:|@deprecated\b)([\s\S]*?)(?=\n\s*\n|\n[ \t*]*@|$)/;
+
+/**
+ * Convert a scanned document into a snapshot, skipping non-symbol documents
+ */
+export function toSymbolSnapshot(doc: Document): SymbolSnapshot | null {
+  if (doc.type === 'documentation' || !doc.metadata.name) {
+    return null;
+  }
+
+  const fields = doc.metadata.custom?.fields as StructField[] | undefined;
+  return {
+    name: doc.metadata.name,
+    type: doc.type,
+    file: doc.metadata.file,
+    startLine: doc.metadata.startLine,
+    signature: doc.metadata.signature,
+    exported: doc.metadata.exported,
+    ...deprecationOf(doc.metadata.docstring),
+    ...(fields ? { fields } : {}),
+  };
+}
+
+/**
+ * Deprecation notice in a doc comment: a Go `Deprecated:` paragraph or a
+ * JSDoc `@deprecated` tag, up to the next blank line or tag
+ */
+function deprecationOf(docstring: string | undefined): { deprecated?: string } {
+  const match = docstring?.match(DEPRECATION_PATTERN);
+  if (!match) return {};
+  return { deprecated: normalizeWhitespace(match[1]) };
+}
+
+/**
+ * Compare two sets of documents and report symbol-level changes
+ *
+ * @param before - Documents scanned at the base revision
+ * @param after - Documents scanned at the head revision
+ */
+export function diffSymbols(before: Document[], after: Document[]): SymbolDiff {
+  const beforeMap = indexSymbols(before);
+  const afterMap = indexSymbols(after);
+
+  const removed: SymbolSnapshot[] = [];
+  const added: SymbolSnapshot[] = [];
+  const changed: SymbolChange[] = [];
+  const deprecated: SymbolSnapshot[] = [];
+  const tagsChanged: FieldTagChange[] = [];
+
+  for (const [key, oldSymbol] of beforeMap) {
+    const newSymbol = afterMap.get(key);
+    if (!newSymbol) {
+      removed.push(oldSymbol);
+      continue;
+    }
+    if (normalizeWhitespace(oldSymbol.signature) !== normalizeWhitespace(newSymbol.signature)) {
+      changed.push({ before: oldSymbol, after: newSymbol });
+    }
+    if (newSymbol.deprecated !== undefined && oldSymbol.deprecated === undefined) {
+      deprecated.push(newSymbol);
+    }
+    tagsChanged.push(...diffFieldTags(oldSymbol, newSymbol));
+  }
+
+  for (const [key, newSymbol] of afterMap) {
+    if (!beforeMap.has(key)) {
+      added.push(newSymbol);
+    }
+  }
+
+  const renamed = detectRenames(removed, added);
+  const renamedBefore = new Set(renamed.map((r) => r.before));
+  const renamedAfter = new Set(renamed.map((r) => r.after));
+
+  return {
+    added: added.filter((s) => !renamedAfter.has(s)).sort(compareSymbols),
+    removed: removed.filter((s) => !renamedBefore.has(s)).sort(compareSymbols),
+    changed: changed.sort((a, b) => compareSymbols(a.after, b.after)),
+    renamed: renamed.sort((a, b) => compareSymbols(a.after, b.after)),
+    deprecated: deprecated.sort(compareSymbols),
+    tagsChanged: tagsChanged.sort((a, b) => compareSymbols(a.struct, b.struct)),
+  };
+}
+
+/**
+ * Fields present in both versions of a struct whose tag differs
+ */
+function diffFieldTags(before: SymbolSnapshot, after: SymbolSnapshot): FieldTagChange[] {
+  if (!before.fields || !after.fields) return [];
+
+  const oldFields = new Map(before.fields.map((field) => [field.name, field]));
+  const changes: FieldTagChange[] = [];
+  for (const field of after.fields) {
+    const oldField = oldFields.get(field.name);
+    if (oldField && normalizeWhitespace(oldField.tag) !== normalizeWhitespace(field.tag)) {
+      changes.push({ struct: after, field, before: oldField.tag, after: field.tag });
+    }
+  }
+  return changes;
+}
+
+/**
+ * Pair removed and added symbols that look like renames
+ *
+ * A pair qualifies when both are in the same file, have the same type,
+ * and their signatures match once the symbol's own name is masked out.
+ * When several candidates qualify, the one closest in position wins.
+ */
+function detectRenames(removed: SymbolSnapshot[], added: SymbolSnapshot[]): SymbolRename[] {
+  const renames: SymbolRename[] = [];
+  const available = new Set(added);
+
+  for (const oldSymbol of removed) {
+    const oldShape = signatureShape(oldSymbol);
+    if (!oldShape) continue;
+
+    let best: SymbolSnapshot | null = null;
+    for (const candidate of available) {
+      if (candidate.file !== oldSymbol.file || candidate.type !== oldSymbol.type) continue;
+      if (signatureShape(candidate) !== oldShape) continue;
+      if (
+        !best ||
+        Math.abs(candidate.startLine - oldSymbol.startLine) <
+          Math.abs(best.startLine - oldSymbol.startLine)
+      ) {
+        best = candidate;
+      }
+    }
+
+    if (best) {
+      available.delete(best);
+      renames.push({ before: oldSymbol, after: best });
+    }
+  }
+
+  return renames;
+}
+
+/**
+ * Signature with the symbol's short name replaced by a placeholder
+ *
+ * Returns null when there is no signature to compare.
+ */
+function signatureShape(symbol: SymbolSnapshot): string | null {
+  if (!symbol.signature) return null;
+
+  const shortName = symbol.name.split('.').pop() ?? symbol.name;
+  const escaped = shortName.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+  return normalizeWhitespace(symbol.signature).replace(new RegExp(`\\b${escaped}\\b`, 'g'), '_');
+}
+
+function indexSymbols(docs: Document[]): Map<string, SymbolSnapshot> {
+  const map = new Map<string, SymbolSnapshot>();
+  for (const doc of docs) {
+    const symbol = toSymbolSnapshot(doc);
+    if (!symbol) continue;
+    const key = `${symbol.file}:${symbol.type}:${symbol.name}`;
+    // Keep the first occurrence (e.g. overloads, build-tagged duplicates)
+    if (!map.has(key)) {
+      map.set(key, symbol);
+    }
+  }
+  return map;
+}
+
+function normalizeWhitespace(value: string | undefined): string {
+  return (value ?? '').replace(/\s+/g, ' ').trim();
+}
+
+function compareSymbols(a: SymbolSnapshot, b: SymbolSnapshot): number {
+  return a.file.localeCompare(b.file) || a.startLine - b.startLine;
+}
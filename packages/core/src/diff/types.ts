@@ -0,0 +1,143 @@
+/**
+ * Symbol Diff Types
+ */
+
+import type { DocumentType, StructField } from '../scanner/types';
+
+/**
+ * A symbol as seen at one revision
+ */
+export interface SymbolSnapshot {
+  /** Symbol name (methods are Type.method) */
+  name: string;
+  /** Document type */
+  type: DocumentType;
+  /** File path (relative to repo root) */
+  file: string;
+  /** Line where the symbol starts */
+  startLine: number;
+  /** Signature, if available */
+  signature?: string;
+  /** Whether the symbol is part of the public API */
+  exported: boolean;
+  /** Deprecation notice from the doc comment (`Deprecated:` or `@deprecated`), when marked */
+  deprecated?: string;
+  /** Go struct fields, with their tags */
+  fields?: StructField[];
+}
+
+/**
+ * A symbol present at both revisions whose signature changed
+ */
+export interface SymbolChange {
+  before: SymbolSnapshot;
+  after: SymbolSnapshot;
+}
+
+/**
+ * A symbol that was renamed (same signature shape, different name)
+ */
+export interface SymbolRename {
+  before: SymbolSnapshot;
+  after: SymbolSnapshot;
+}
+
+/**
+ * A struct field present at both revisions whose tag changed, which changes
+ * how the struct is serialized (e.g. its JSON name)
+ */
+export interface FieldTagChange {
+  /** The struct at the head revision */
+  struct: SymbolSnapshot;
+  /** The field at the head revision */
+  field: StructField;
+  /** Tag at the base revision (undefined when untagged) */
+  before?: string;
+  /** Tag at the head revision (undefined when untagged) */
+  after?: string;
+}
+
+/**
+ * Symbol-level difference between two revisions
+ */
+export interface SymbolDiff {
+  added: SymbolSnapshot[];
+  removed: SymbolSnapshot[];
+  changed: SymbolChange[];
+  renamed: SymbolRename[];
+  /** Symbols at both revisions that are marked deprecated only at the head */
+  deprecated: SymbolSnapshot[];
+  /** Struct fields whose tag changed */
+  tagsChanged: FieldTagChange[];
+}
+
+/**
+ * Result of diffing two git revisions
+ */
+export interface RevisionDiff extends SymbolDiff {
+  /** Base revision as requested */
+  base: string;
+  /** Head revision as requested */
+  head: string;
+  /** Files that differ between the revisions */
+  filesChanged: string[];
+}
+
+/**
+ * Options for diffing two revisions
+ */
+export interface RevisionDiffOptions {
+  /** Repository root */
+  repositoryPath: string;
+  /** Base revision (e.g. "main", "v1.2.0", "HEAD~3") */
+  base: string;
+  /** Head revision (default: HEAD) */
+  head?: string;
+  /** Only include files under this path prefix */
+  pathPrefix?: string;
+}
+
+/**
+ * An unexported symbol that changed: churn outside the public API
+ */
+export interface InternalChange {
+  change: 'added' | 'removed' | 'changed' | 'renamed';
+  /** The symbol at the head revision (at the base revision when removed) */
+  symbol: SymbolSnapshot;
+  /** Name at the base revision, for renames */
+  previousName?: string;
+}
+
+/**
+ * Exported API changes within one package (directory)
+ */
+export interface ChangelogPackage {
+  /** Package directory relative to the repository root ("." for the root) */
+  package: string;
+  /** New exported symbols, including ones that became exported */
+  added: SymbolSnapshot[];
+  /** Exported symbols that were removed or unexported (breaking) */
+  removed: SymbolSnapshot[];
+  /** Exported symbols whose signature changed (breaking) */
+  changed: SymbolChange[];
+  /** Exported symbols renamed without a signature change (breaking) */
+  renamed: SymbolRename[];
+  /** Exported symbols newly marked deprecated */
+  deprecated: SymbolSnapshot[];
+  /** Tags changed on exported fields of exported structs (breaking serialization) */
+  tagsChanged: FieldTagChange[];
+  /** Unexported symbols added, removed, changed, or renamed (not breaking) */
+  internal: InternalChange[];
+}
+
+/**
+ * API-level release notes between two revisions
+ */
+export interface Changelog {
+  base: string;
+  head: string;
+  /** Packages with API changes, sorted by path */
+  packages: ChangelogPackage[];
+  /** Removals, renames, signature changes, and field tag changes across all packages */
+  breakingChanges: number;
+}
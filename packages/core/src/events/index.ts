@@ -47,4 +47,6 @@ export type {
   SystemShuttingDownEvent,
   SystemStartedEvent,
   Unsubscribe,
+  WatchReadyEvent,
+  WatchReindexedEvent,
 } from './types';
@@ -158,6 +158,24 @@ export interface IndexErrorEvent {
   recoverable: boolean;
 }
 
+/**
+ * Watch mode events
+ */
+export interface WatchReadyEvent {
+  path: string;
+  /** Files re-indexed by the initial catch-up update */
+  filesReindexed: number;
+  duration: number;
+}
+
+export interface WatchReindexedEvent {
+  path: string;
+  /** Repository-relative paths in this batch */
+  files: string[];
+  documentsIndexed: number;
+  duration: number;
+}
+
 /**
  * Health-related events
  */
@@ -230,6 +248,10 @@ export interface SystemEventMap {
   'index.updated': IndexUpdatedEvent;
   'index.error': IndexErrorEvent;
 
+  // Watch events
+  'watch.ready': WatchReadyEvent;
+  'watch.reindexed': WatchReindexedEvent;
+
   // Health events
   'health.changed': HealthChangedEvent;
 
@@ -0,0 +1,107 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import type { SearchService } from '../../services/search-service';
+import type { SearchResult } from '../../vector/types';
+import { FederatedSearch } from '../federated-search';
+import { RepositoryRegistry } from '../registry';
+
+function result(id: string, score: number, name?: string, snippet = 'func x() {}'): SearchResult {
+  return {
+    id,
+    score,
+    metadata: { name, type: 'function', path: id.split(':')[0], snippet },
+  };
+}
+
+describe('FederatedSearch', () => {
+  let tempDir: string;
+  let registry: RepositoryRegistry;
+  let searches: Record<string, ReturnType<typeof vi.fn>>;
+  let federated: FederatedSearch;
+
+  beforeEach(async () => {
+    tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'federated-search-test-'));
+    registry = new RepositoryRegistry(path.join(tempDir, 'repositories.json'));
+    for (const name of ['billing', 'orders']) {
+      await fs.mkdir(path.join(tempDir, name));
+      await registry.register(path.join(tempDir, name), { name });
+    }
+
+    searches = {
+      billing: vi.fn().mockResolvedValue([
+        result('retry.go:Retry:3', 0.9, 'Retry'),
+        result('charge.go:Charge:8', 0.7, 'Charge'),
+      ]),
+      orders: vi.fn().mockResolvedValue([
+        result('retry.go:Retry:3', 0.8, 'Retry'),
+        result('order.go:Place:5', 0.85, 'Place'),
+      ]),
+    };
+
+    federated = new FederatedSearch({
+      registry,
+      createSearchService: (repository) =>
+        ({
+          search: searches[repository.name],
+          lookupSymbol: searches[repository.name],
+        }) as unknown as SearchService,
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(tempDir, { recursive: true, force: true });
+  });
+
+  it('should merge results from all repositories by score', async () => {
+    const results = await federated.search('retry', { dedupe: false });
+
+    expect(results.map((r) => [r.id, r.metadata.repository])).toEqual([
+      ['billing:retry.go:Retry:3', 'billing'],
+      ['orders:order.go:Place:5', 'orders'],
+      ['orders:retry.go:Retry:3', 'orders'],
+      ['billing:charge.go:Charge:8', 'billing'],
+    ]);
+  });
+
+  it('should collapse identical symbols across repositories', async () => {
+    const results = await federated.search('retry');
+
+    const retry = results.filter((r) => r.metadata.name === 'Retry');
+    expect(retry).toHaveLength(1);
+    expect(retry[0].metadata.repository).toBe('billing');
+    expect(retry[0].metadata.alsoIn).toEqual(['orders']);
+    expect(results).toHaveLength(3);
+  });
+
+  it('should scope to one repository', async () => {
+    const results = await federated.search('retry', { repository: 'orders', limit: 5 });
+
+    expect(searches.billing).not.toHaveBeenCalled();
+    expect(searches.orders).toHaveBeenCalledWith('retry', { limit: 5 });
+    expect(results.every((r) => r.metadata.repository === 'orders')).toBe(true);
+  });
+
+  it('should reject unregistered repositories', async () => {
+    await expect(federated.search('retry', { repository: 'missing' })).rejects.toThrow(
+      'not registered'
+    );
+  });
+
+  it('should skip failing repositories when spanning all', async () => {
+    searches.orders.mockRejectedValue(new Error('not indexed'));
+
+    const results = await federated.lookupSymbol('Retry');
+
+    expect(results.map((r) => r.metadata.repository)).toEqual(['billing', 'billing']);
+  });
+
+  it('should report failures for a scoped query', async () => {
+    searches.orders.mockRejectedValue(new Error('not indexed'));
+
+    await expect(federated.lookupSymbol('Retry', { repository: 'orders' })).rejects.toThrow(
+      'not indexed'
+    );
+  });
+});
@@ -0,0 +1,92 @@
+import { execSync } from 'node:child_process';
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it } from 'vitest';
+import { RepositoryRegistry } from '../registry';
+
+describe('RepositoryRegistry', () => {
+  let tempDir: string;
+  let registry: RepositoryRegistry;
+
+  beforeEach(async () => {
+    tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'registry-test-'));
+    registry = new RepositoryRegistry(path.join(tempDir, 'repositories.json'));
+  });
+
+  afterEach(async () => {
+    await fs.rm(tempDir, { recursive: true, force: true });
+  });
+
+  async function makeRepo(name: string, remote?: string): Promise<string> {
+    const repoPath = path.join(tempDir, name);
+    await fs.mkdir(repoPath);
+    if (remote) {
+      execSync('git init', { cwd: repoPath, stdio: 'pipe' });
+      execSync(`git remote add origin ${remote}`, { cwd: repoPath, stdio: 'pipe' });
+    }
+    return repoPath;
+  }
+
+  it('should start empty', async () => {
+    expect(await registry.list()).toEqual([]);
+  });
+
+  it('should name repositories after their git remote', async () => {
+    const repoPath = await makeRepo('billing', 'git@github.com:acme/billing.git');
+
+    const repository = await registry.register(repoPath);
+
+    expect(repository).toMatchObject({
+      name: 'acme/billing',
+      path: repoPath,
+      remote: 'acme/billing',
+    });
+    expect(await registry.get('acme/billing')).toEqual(repository);
+  });
+
+  it('should fall back to the directory name and accept explicit names', async () => {
+    const ordersPath = await makeRepo('orders');
+    const usersPath = await makeRepo('users');
+
+    await registry.register(ordersPath);
+    await registry.register(usersPath, { name: 'accounts' });
+
+    expect((await registry.list()).map((r) => r.name)).toEqual(['accounts', 'orders']);
+  });
+
+  it('should rename a repository registered again under a new name', async () => {
+    const repoPath = await makeRepo('orders');
+    const first = await registry.register(repoPath);
+
+    const renamed = await registry.register(repoPath, { name: 'order-service' });
+
+    expect(await registry.list()).toEqual([renamed]);
+    expect(renamed.registeredAt).toBe(first.registeredAt);
+  });
+
+  it('should reject a name used by another repository', async () => {
+    await registry.register(await makeRepo('a'), { name: 'shared' });
+
+    await expect(registry.register(await makeRepo('b'), { name: 'shared' })).rejects.toThrow(
+      'already registered'
+    );
+  });
+
+  it('should unregister by name or path', async () => {
+    const ordersPath = await makeRepo('orders');
+    await registry.register(ordersPath);
+    await registry.register(await makeRepo('users'));
+
+    expect(await registry.unregister('users')).toBe(true);
+    expect(await registry.unregister(ordersPath)).toBe(true);
+    expect(await registry.unregister('missing')).toBe(false);
+    expect(await registry.list()).toEqual([]);
+  });
+
+  it('should reject a corrupt registry file', async () => {
+    await fs.writeFile(path.join(tempDir, 'repositories.json'), '{ not json');
+
+    await expect(registry.list()).rejects.toThrow('Invalid repository registry');
+  });
+});
@@ -0,0 +1,166 @@
+/**
+ * Federated Search
+ * Searches the indexes of several registered repositories as one
+ *
+ * Each repository is queried through its own SearchService and results are
+ * merged by score. Every result carries `metadata.repository`, and its id is
+ * prefixed with the repository name so ids stay unique across repositories.
+ * Identical symbols found in several repositories (vendored or copied code)
+ * are collapsed into one result listing the others in `metadata.alsoIn`.
+ *
+ * Call graphs are not resolved across repositories.
+ */
+
+import type { Logger } from '@lytics/kero';
+import { SearchService } from '../services/search-service';
+import type { SearchResult } from '../vector/types';
+import type { RepositoryRegistry } from './registry';
+import type { FederatedLookupOptions, FederatedRepository, FederatedSearchOptions } from './types';
+
+/**
+ * Federated search configuration
+ */
+export interface FederatedSearchConfig {
+  /** Registered repositories to search */
+  registry: RepositoryRegistry;
+  logger?: Logger;
+  /** Create the search service for one repository (default: a SearchService for its path) */
+  createSearchService?: (repository: FederatedRepository) => SearchService;
+}
+
+/**
+ * Search across all registered repositories, or scope to one
+ */
+export class FederatedSearch {
+  private readonly registry: RepositoryRegistry;
+  private readonly logger?: Logger;
+  private readonly createSearchService: (repository: FederatedRepository) => SearchService;
+
+  constructor(config: FederatedSearchConfig) {
+    this.registry = config.registry;
+    this.logger = config.logger;
+    this.createSearchService =
+      config.createSearchService ??
+      ((repository) => new SearchService({ repositoryPath: repository.path, logger: this.logger }));
+  }
+
+  /**
+   * Semantic search across repositories
+   *
+   * @param query - Search query string
+   * @param options - Search options plus repository scope and dedupe
+   * @returns Merged results, highest score first
+   */
+  async search(query: string, options: FederatedSearchOptions = {}): Promise<SearchResult[]> {
+    const { repository, dedupe = true, ...searchOptions } = options;
+    return this.fanOut(repository, options.limit ?? 10, dedupe, (service) =>
+      service.search(query, searchOptions)
+    );
+  }
+
+  /**
+   * Fuzzy symbol-name lookup across repositories
+   *
+   * @param query - Partial or misspelled symbol name
+   * @param options - Lookup options plus repository scope and dedupe
+   * @returns Merged matches, best match first
+   */
+  async lookupSymbol(query: string, options: FederatedLookupOptions = {}): Promise<SearchResult[]> {
+    const { repository, dedupe = true, ...lookupOptions } = options;
+    return this.fanOut(repository, options.limit ?? 10, dedupe, (service) =>
+      service.lookupSymbol(query, lookupOptions)
+    );
+  }
+
+  /**
+   * Run a query against each repository in scope and merge the results
+   *
+   * When spanning all repositories, one that fails (e.g. not indexed yet) is
+   * logged and skipped; a query scoped to one repository reports its error.
+   */
+  private async fanOut(
+    scope: string | undefined,
+    limit: number,
+    dedupe: boolean,
+    query: (service: SearchService) => Promise<SearchResult[]>
+  ): Promise<SearchResult[]> {
+    const repositories = await this.resolveScope(scope);
+
+    const settled = await Promise.allSettled(
+      repositories.map(async (repository) => {
+        const results = await query(this.createSearchService(repository));
+        return results.map((result) => tagResult(result, repository.name));
+      })
+    );
+
+    const merged: SearchResult[] = [];
+    settled.forEach((outcome, i) => {
+      if (outcome.status === 'fulfilled') {
+        merged.push(...outcome.value);
+      } else if (scope) {
+        throw outcome.reason;
+      } else {
+        this.logger?.warn('Skipping repository in federated search', {
+          repository: repositories[i].name,
+          error: outcome.reason instanceof Error ? outcome.reason.message : String(outcome.reason),
+        });
+      }
+    });
+
+    merged.sort((a, b) => b.score - a.score);
+    return (dedupe ? dedupeAcrossRepositories(merged) : merged).slice(0, limit);
+  }
+
+  private async resolveScope(scope: string | undefined): Promise<FederatedRepository[]> {
+    if (!scope) {
+      return this.registry.list();
+    }
+
+    const repository = await this.registry.get(scope);
+    if (!repository) {
+      throw new Error(`Repository "${scope}" is not registered`);
+    }
+    return [repository];
+  }
+}
+
+function tagResult(result: SearchResult, repository: string): SearchResult {
+  return {
+    ...result,
+    id: `${repository}:${result.id}`,
+    metadata: { ...result.metadata, repository },
+  };
+}
+
+/**
+ * Keep the best-scoring copy of each symbol that appears in several repositories
+ *
+ * Results must be sorted by score. Symbols match when their kind, name, and
+ * code are identical; results without a name are never collapsed.
+ */
+function dedupeAcrossRepositories(results: SearchResult[]): SearchResult[] {
+  const kept = new Map<string, SearchResult>();
+  const output: SearchResult[] = [];
+
+  for (const result of results) {
+    const { type, name, signature, snippet } = result.metadata;
+    if (!name) {
+      output.push(result);
+      continue;
+    }
+
+    const key = JSON.stringify([type, name, signature ?? '', snippet ?? '']);
+    const existing = kept.get(key);
+    if (!existing) {
+      kept.set(key, result);
+      output.push(result);
+    } else if (result.metadata.repository !== existing.metadata.repository) {
+      const alsoIn = existing.metadata.alsoIn ?? [];
+      if (!alsoIn.includes(String(result.metadata.repository))) {
+        existing.metadata.alsoIn = [...alsoIn, String(result.metadata.repository)];
+      }
+    }
+  }
+
+  return output;
+}
@@ -0,0 +1,8 @@
+/**
+ * Index Federation
+ * Registers repositories and searches their indexes as one
+ */
+
+export * from './federated-search';
+export * from './registry';
+export * from './types';
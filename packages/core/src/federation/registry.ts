@@ -0,0 +1,146 @@
+/**
+ * Repository Registry
+ * Persists the set of repositories searched by federated queries
+ *
+ * Each registered repository keeps its own index (see getStoragePath); the
+ * registry only records names and paths, in ~/.dev-agent/repositories.json.
+ */
+
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { z } from 'zod';
+import { getGitRemote, normalizeGitRemote } from '../storage/path';
+import type { FederatedRepository, RegisterRepositoryOptions } from './types';
+
+const REGISTRY_VERSION = 1;
+
+const RegistryFileSchema = z.object({
+  version: z.number().int(),
+  repositories: z.array(
+    z.object({
+      name: z.string().min(1),
+      path: z.string().min(1),
+      remote: z.string().optional(),
+      registeredAt: z.string(),
+    })
+  ),
+});
+
+/**
+ * Default registry location, shared by all repositories on this machine
+ */
+export function getDefaultRegistryPath(): string {
+  return path.join(os.homedir(), '.dev-agent', 'repositories.json');
+}
+
+/**
+ * Registry of repositories for federated search
+ */
+export class RepositoryRegistry {
+  private readonly registryPath: string;
+
+  constructor(registryPath: string = getDefaultRegistryPath()) {
+    this.registryPath = registryPath;
+  }
+
+  /**
+   * All registered repositories, sorted by name
+   */
+  async list(): Promise<FederatedRepository[]> {
+    const repositories = await this.load();
+    return repositories.sort((a, b) => a.name.localeCompare(b.name));
+  }
+
+  /**
+   * A registered repository by name, or null
+   */
+  async get(name: string): Promise<FederatedRepository | null> {
+    const repositories = await this.load();
+    return repositories.find((r) => r.name === name) ?? null;
+  }
+
+  /**
+   * Register a repository, or rename it if its path is already registered
+   *
+   * @throws If the name is already used by a different repository
+   */
+  async register(
+    repositoryPath: string,
+    options: RegisterRepositoryOptions = {}
+  ): Promise<FederatedRepository> {
+    const resolvedPath = path.resolve(repositoryPath);
+    const gitRemote = getGitRemote(resolvedPath);
+    const remote = gitRemote ? normalizeGitRemote(gitRemote) : undefined;
+    const name = options.name ?? remote ?? path.basename(resolvedPath);
+
+    const repositories = await this.load();
+    const conflict = repositories.find((r) => r.name === name && r.path !== resolvedPath);
+    if (conflict) {
+      throw new Error(
+        `Repository name "${name}" is already registered for ${conflict.path}; pass a different name`
+      );
+    }
+
+    const existing = repositories.find((r) => r.path === resolvedPath);
+    const repository: FederatedRepository = {
+      name,
+      path: resolvedPath,
+      remote,
+      registeredAt: existing?.registeredAt ?? new Date().toISOString(),
+    };
+
+    await this.save([...repositories.filter((r) => r.path !== resolvedPath), repository]);
+    return repository;
+  }
+
+  /**
+   * Unregister a repository by name or path
+   *
+   * The repository's index is left in place.
+   *
+   * @returns True if a repository was removed
+   */
+  async unregister(nameOrPath: string): Promise<boolean> {
+    const resolvedPath = path.resolve(nameOrPath);
+    const repositories = await this.load();
+    const remaining = repositories.filter((r) => r.name !== nameOrPath && r.path !== resolvedPath);
+
+    if (remaining.length === repositories.length) {
+      return false;
+    }
+    await this.save(remaining);
+    return true;
+  }
+
+  private async load(): Promise<FederatedRepository[]> {
+    let content: string;
+    try {
+      content = await fs.readFile(this.registryPath, 'utf-8');
+    } catch {
+      return [];
+    }
+
+    let data: unknown;
+    try {
+      data = JSON.parse(content);
+    } catch {
+      data = null;
+    }
+
+    const result = RegistryFileSchema.safeParse(data);
+    if (!result.success) {
+      throw new Error(`Invalid repository registry at ${this.registryPath}`);
+    }
+    return result.data.repositories;
+  }
+
+  private async save(repositories: FederatedRepository[]): Promise<void> {
+    await fs.mkdir(path.dirname(this.registryPath), { recursive: true });
+    await fs.writeFile(
+      this.registryPath,
+      JSON.stringify({ version: REGISTRY_VERSION, repositories }, null, 2),
+      'utf-8'
+    );
+  }
+}
@@ -0,0 +1,48 @@
+/**
+ * Federation Types
+ * Types for searching several repository indexes as one
+ */
+
+import type { LookupOptions, SearchOptions } from '../services/search-service';
+
+/**
+ * A repository registered for federated search
+ */
+export interface FederatedRepository {
+  /** Unique name used to scope queries (default: normalized git remote, e.g. "acme/billing") */
+  name: string;
+  /** Absolute path to the repository */
+  path: string;
+  /** Normalized git remote, if the repository has one */
+  remote?: string;
+  /** When the repository was registered (ISO 8601) */
+  registeredAt: string;
+}
+
+/**
+ * Options for registering a repository
+ */
+export interface RegisterRepositoryOptions {
+  /** Name to register under (default: normalized git remote, then directory name) */
+  name?: string;
+}
+
+/**
+ * Options for federated semantic search
+ */
+export interface FederatedSearchOptions extends SearchOptions {
+  /** Only search this registered repository (default: all) */
+  repository?: string;
+  /** Collapse identical symbols found in several repositories (default: true) */
+  dedupe?: boolean;
+}
+
+/**
+ * Options for federated symbol lookup
+ */
+export interface FederatedLookupOptions extends LookupOptions {
+  /** Only search this registered repository (default: all) */
+  repository?: string;
+  /** Collapse identical symbols found in several repositories (default: true) */
+  dedupe?: boolean;
+}
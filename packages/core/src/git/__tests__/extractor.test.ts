@@ -185,6 +185,43 @@ describe('LocalGitExtractor', () => {
     });
   });
 
+  describe('revisions', () => {
+    async function hashFor(subject: string): Promise<string> {
+      const commits = await extractor.getCommits();
+      const commit = commits.find((c) => c.subject === subject);
+      if (!commit) throw new Error(`commit not found: ${subject}`);
+      return commit.hash;
+    }
+
+    it('should resolve revisions to full hashes', async () => {
+      const head = await extractor.resolveRevision('HEAD');
+      expect(head).toMatch(/^[0-9a-f]{40}$/);
+    });
+
+    it('should reject unsafe revisions', async () => {
+      await expect(extractor.resolveRevision('--all')).rejects.toThrow('Invalid git revision');
+      await expect(extractor.resolveRevision('HEAD; rm -rf /')).rejects.toThrow(
+        'Invalid git revision'
+      );
+    });
+
+    it('should list files changed between revisions', async () => {
+      const base = await hashFor('feat: add file1 #123');
+      const head = await hashFor('refactor: update file1');
+
+      const files = await extractor.getChangedFiles(base, head);
+
+      expect(files.sort()).toEqual(['file1.ts', 'file2.ts']);
+    });
+
+    it('should read a file at a revision', async () => {
+      const base = await hashFor('feat: add file1 #123');
+
+      expect(await extractor.getFileAtRevision(base, 'file1.ts')).toBe('export const x = 1;\n');
+      expect(await extractor.getFileAtRevision(base, 'file2.ts')).toBeNull();
+    });
+  });
+
   describe('reference extraction', () => {
     it('should extract multiple issue references', async () => {
       // Create commit with multiple refs
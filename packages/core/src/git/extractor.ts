@@ -5,7 +5,8 @@
  * Designed as an interface for future pluggability (GitHub API, etc.)
  */
 
-import { execSync } from 'node:child_process';
+import { execFile, execSync } from 'node:child_process';
+import { promisify } from 'node:util';
 import type {
   BlameOptions,
   GetCommitsOptions,
@@ -36,6 +37,8 @@ export interface GitExtractor {
   getRepositoryInfo(): Promise<GitRepositoryInfo>;
 }
 
+const execFileAsync = promisify(execFile);
+
 /** Field separator for git log parsing */
 const FIELD_SEP = '␞'; // ASCII Record Separator
 /** Record separator for git log parsing */
@@ -142,7 +145,8 @@ export class LocalGitExtractor implements GitExtractor {
 
     args.push('--', file);
 
-    const output = this.execGit(args);
+    // Blame is slow on long histories; run it without blocking so files can be blamed in parallel
+    const output = await this.execGitAsync(args);
     return this.parseBlameOutput(file, output);
   }
 
@@ -193,6 +197,62 @@ export class LocalGitExtractor implements GitExtractor {
     return { name, remote, owner, branch, head, dirty };
   }
 
+  /**
+   * Resolve a revision (branch, tag, hash, HEAD~n) to a full commit hash
+   *
+   * @throws If the revision is malformed or does not exist
+   */
+  async resolveRevision(revision: string): Promise<string> {
+    this.assertSafeRevision(revision);
+    return this.execGit(['rev-parse', '--verify', '--quiet', `${revision}^{commit}`]).trim();
+  }
+
+  /**
+   * List files that differ between two revisions
+   *
+   * Renames are reported as a delete plus an add so each side can be
+   * read independently.
+   */
+  async getChangedFiles(base: string, head: string): Promise<string[]> {
+    this.assertSafeRevision(base);
+    this.assertSafeRevision(head);
+    const output = this.execGit(['diff', '--name-only', '--no-renames', base, head]);
+    return output
+      .split('\n')
+      .map((line) => line.trim())
+      .filter(Boolean);
+  }
+
+  /**
+   * Read a file's content at a revision
+   *
+   * @returns File content, or null if the file does not exist at that revision
+   */
+  async getFileAtRevision(revision: string, file: string): Promise<string | null> {
+    this.assertSafeRevision(revision);
+    try {
+      return this.execGit(['show', this.quoteArg(`${revision}:${file}`)]);
+    } catch {
+      return null;
+    }
+  }
+
+  /**
+   * Reject revisions that could be interpreted as options or shell syntax
+   */
+  private assertSafeRevision(revision: string): void {
+    if (!/^[\w./~^@{}-]+$/.test(revision) || revision.startsWith('-')) {
+      throw new Error(`Invalid git revision: ${revision}`);
+    }
+  }
+
+  /**
+   * Quote an argument for the shell
+   */
+  private quoteArg(arg: string): string {
+    return `'${arg.replace(/'/g, "'\\''")}'`;
+  }
+
   /**
    * Execute a git command and return stdout
    */
@@ -215,6 +275,26 @@ export class LocalGitExtractor implements GitExtractor {
     }
   }
 
+  /**
+   * Execute a git command without a shell or blocking, and return stdout
+   */
+  private async execGitAsync(args: string[]): Promise<string> {
+    try {
+      const { stdout } = await execFileAsync('git', args, {
+        cwd: this.repositoryPath,
+        encoding: 'utf-8',
+        maxBuffer: 50 * 1024 * 1024, // 50MB for large repos
+      });
+      return stdout;
+    } catch (error) {
+      const message = error instanceof Error ? error.message : String(error);
+      if (message.includes('does not have any commits yet')) {
+        return '';
+      }
+      throw error;
+    }
+  }
+
   /**
    * Parse git log output into commits
    */
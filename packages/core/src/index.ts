@@ -2,7 +2,9 @@
 
 export * from './api';
 export * from './context';
+export * from './diff';
 export * from './events';
+export * from './federation';
 export * from './git';
 export * from './github';
 export * from './indexer';
@@ -10,10 +12,14 @@ export * from './map';
 export * from './metrics';
 export * from './observability';
 export * from './scanner';
+export * from './search';
 export * from './services';
+export * from './similarity';
 export * from './storage';
+export * from './surface';
 export * from './utils';
 export * from './vector';
+export * from './watcher';
 
 export interface CoreConfig {
   apiKey: string;
@@ -4,5 +4,11 @@
 
 export { RepositoryIndexer } from './indexer/index';
 export { StatsAggregator } from './indexer/stats-aggregator';
+export {
+  type ScanPathOptions,
+  scanPath,
+  TransientIndex,
+  type TransientIndexOptions,
+} from './indexer/transient-index';
 export * from './indexer/types';
 export * from './indexer/utils';
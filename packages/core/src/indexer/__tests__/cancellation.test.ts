@@ -0,0 +1,164 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { VectorStorage } from '../../vector';
+import type { StorageTiming } from '../../vector/types';
+import { RepositoryIndexer } from '../index';
+
+describe('RepositoryIndexer - cancellation', () => {
+  let repoDir: string;
+  let statePath: string;
+  let indexer: RepositoryIndexer;
+  let addDocuments: ReturnType<typeof vi.fn>;
+  let deleteDocuments: ReturnType<typeof vi.fn>;
+  let clear: ReturnType<typeof vi.fn>;
+  let acceptsEmbeddings: ReturnType<typeof vi.fn>;
+
+  beforeEach(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'indexer-cancel-'));
+    statePath = path.join(repoDir, '.state.json');
+    await fs.writeFile(
+      path.join(repoDir, 'shop.go'),
+      'package shop\n\n// Total sums prices.\nfunc Total(prices []int) int {\n\treturn 0\n}\n'
+    );
+    await fs.writeFile(
+      path.join(repoDir, 'other.go'),
+      'package shop\n\n// Other is untouched.\nfunc Other() {}\n'
+    );
+
+    addDocuments = vi.fn().mockResolvedValue(undefined);
+    deleteDocuments = vi.fn().mockResolvedValue(undefined);
+    clear = vi.fn().mockResolvedValue(undefined);
+    acceptsEmbeddings = vi.fn().mockResolvedValue(true);
+    vi.spyOn(VectorStorage.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'ensureEmbedder').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'addDocuments').mockImplementation(addDocuments);
+    vi.spyOn(VectorStorage.prototype, 'deleteDocuments').mockImplementation(deleteDocuments);
+    vi.spyOn(VectorStorage.prototype, 'clear').mockImplementation(clear);
+    vi.spyOn(VectorStorage.prototype, 'acceptsEmbeddings').mockImplementation(acceptsEmbeddings);
+    vi.spyOn(VectorStorage.prototype, 'getDocumentIds').mockResolvedValue([
+      'shop.go:function:shop.Total',
+      'other.go:function:shop.Other',
+    ]);
+    vi.spyOn(VectorStorage.prototype, 'close').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'getStats').mockResolvedValue({
+      totalDocuments: 0,
+      storageSize: 0,
+      dimension: 384,
+      modelName: 'test',
+    });
+
+    indexer = new RepositoryIndexer({
+      repositoryPath: repoDir,
+      vectorStorePath: path.join(repoDir, '.vectors'),
+      statePath,
+    });
+    await indexer.initialize();
+    await indexer.index();
+    addDocuments.mockClear();
+  });
+
+  afterEach(async () => {
+    await indexer.close();
+    vi.restoreAllMocks();
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  /** Make the next embedding call cancel the operation, as a disconnecting client would */
+  function abortOnEmbed(controller: AbortController): void {
+    addDocuments.mockImplementation(
+      async (_docs: unknown, _timing?: StorageTiming, signal?: AbortSignal) => {
+        controller.abort();
+        signal?.throwIfAborted();
+      }
+    );
+  }
+
+  it('should leave the persisted index untouched when cancelled mid-scan', async () => {
+    const stateBefore = await fs.readFile(statePath, 'utf-8');
+    await fs.writeFile(path.join(repoDir, 'extra.go'), 'package shop\n\nfunc Extra() {}\n');
+
+    const controller = new AbortController();
+    const indexing = indexer.index({
+      force: true,
+      signal: controller.signal,
+      onProgress: (progress) => {
+        // Files have been discovered and scanning has started
+        if (progress.phase === 'scanning' && progress.totalFiles > 0) {
+          controller.abort();
+        }
+      },
+    });
+
+    await expect(indexing).rejects.toMatchObject({ name: 'AbortError' });
+    expect(clear).not.toHaveBeenCalled();
+    expect(addDocuments).not.toHaveBeenCalled();
+    expect(deleteDocuments).not.toHaveBeenCalled();
+    expect(await fs.readFile(statePath, 'utf-8')).toBe(stateBefore);
+  });
+
+  it('should keep old documents when an update is cancelled during embedding', async () => {
+    const stateBefore = await fs.readFile(statePath, 'utf-8');
+    await fs.writeFile(
+      path.join(repoDir, 'shop.go'),
+      'package shop\n\n// Sum sums prices.\nfunc Sum(prices []int) int {\n\treturn 0\n}\n'
+    );
+    await fs.rm(path.join(repoDir, 'other.go'));
+
+    const controller = new AbortController();
+    abortOnEmbed(controller);
+
+    await expect(indexer.update({ signal: controller.signal })).rejects.toMatchObject({
+      name: 'AbortError',
+    });
+    expect(addDocuments).toHaveBeenCalledTimes(1);
+    expect(deleteDocuments).not.toHaveBeenCalled();
+    expect(await fs.readFile(statePath, 'utf-8')).toBe(stateBefore);
+
+    // The next update picks up the same changes and removes the old documents
+    addDocuments.mockResolvedValue(undefined);
+    await indexer.update();
+
+    const removed = deleteDocuments.mock.calls.flatMap(([ids]) => ids as string[]);
+    expect(removed).toEqual(
+      expect.arrayContaining(['other.go:function:shop.Other', 'shop.go:function:shop.Total'])
+    );
+  });
+
+  it('should keep the old index when a forced re-index is cancelled during embedding', async () => {
+    const stateBefore = await fs.readFile(statePath, 'utf-8');
+    const controller = new AbortController();
+    abortOnEmbed(controller);
+
+    await expect(indexer.index({ force: true, signal: controller.signal })).rejects.toMatchObject({
+      name: 'AbortError',
+    });
+    expect(clear).not.toHaveBeenCalled();
+    expect(deleteDocuments).not.toHaveBeenCalled();
+    expect(await fs.readFile(statePath, 'utf-8')).toBe(stateBefore);
+  });
+
+  it('should remove documents a forced re-index no longer finds once it completes', async () => {
+    await fs.rm(path.join(repoDir, 'other.go'));
+
+    await indexer.index({ force: true });
+
+    expect(clear).not.toHaveBeenCalled();
+    expect(deleteDocuments).toHaveBeenCalledWith(['other.go:function:shop.Other']);
+    expect(indexer.getTrackedFiles()).toEqual(['shop.go']);
+  });
+
+  it('should discard saved state when a forced re-index is cancelled after clearing', async () => {
+    // Vectors written under other settings can't be replaced in place
+    acceptsEmbeddings.mockResolvedValue(false);
+    const controller = new AbortController();
+    abortOnEmbed(controller);
+
+    await expect(indexer.index({ force: true, signal: controller.signal })).rejects.toMatchObject({
+      name: 'AbortError',
+    });
+    expect(clear).toHaveBeenCalled();
+    await expect(fs.access(statePath)).rejects.toThrow();
+  });
+});
@@ -0,0 +1,106 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { VectorStorage } from '../../vector';
+import type { EmbeddingDocument } from '../../vector/types';
+import { RepositoryIndexer } from '../index';
+import type { IndexerConfig } from '../types';
+
+describe('RepositoryIndexer - embedding profiles', () => {
+  let repoDir: string;
+  let addDocuments: ReturnType<typeof vi.fn>;
+
+  beforeEach(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'indexer-profiles-'));
+    await fs.mkdir(path.join(repoDir, 'docs'));
+    await fs.mkdir(path.join(repoDir, 'src'));
+    await fs.writeFile(path.join(repoDir, 'docs/guide.md'), '# Guide\n\nHow to run it.\n');
+    await fs.writeFile(
+      path.join(repoDir, 'src/server.ts'),
+      'export function serve(): number {\n  return 1;\n}\n'
+    );
+
+    addDocuments = vi.fn().mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'ensureEmbedder').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'addDocuments').mockImplementation(addDocuments);
+    vi.spyOn(VectorStorage.prototype, 'deleteDocuments').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'close').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'getStats').mockResolvedValue({
+      totalDocuments: 0,
+      storageSize: 0,
+      dimension: 384,
+      modelName: 'test',
+    });
+  });
+
+  afterEach(async () => {
+    vi.restoreAllMocks();
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  function createIndexer(config: Partial<IndexerConfig> = {}): RepositoryIndexer {
+    return new RepositoryIndexer({
+      repositoryPath: repoDir,
+      vectorStorePath: path.join(repoDir, '.vectors'),
+      statePath: path.join(repoDir, '.state.json'),
+      embeddingProfiles: { docs: { embeddingMaxTokens: 128 } },
+      embeddingProfileBindings: [{ paths: ['docs/**'], profile: 'docs' }],
+      ...config,
+    });
+  }
+
+  function embedded(): EmbeddingDocument[] {
+    return addDocuments.mock.calls.flatMap(([docs]) => docs as EmbeddingDocument[]);
+  }
+
+  it('should record the profile each document was embedded with', async () => {
+    const indexer = createIndexer();
+    await indexer.initialize();
+    await indexer.index();
+
+    const profiles = new Map(embedded().map((doc) => [doc.metadata.path, doc.metadata]));
+    expect(profiles.get('docs/guide.md')?.embeddingProfile).toMatch(/^docs@/);
+    expect(profiles.get('src/server.ts')?.embeddingProfile).toMatch(/^default@/);
+
+    await indexer.close();
+  });
+
+  it('should re-embed only the files whose profile changed', async () => {
+    const first = createIndexer();
+    await first.initialize();
+    await first.index();
+    await first.close();
+    addDocuments.mockClear();
+
+    const indexer = createIndexer({ embeddingProfiles: { docs: { embeddingMaxTokens: 64 } } });
+    await indexer.initialize();
+    expect((await indexer.getUpdatePlan())?.changed).toEqual(['docs/guide.md']);
+
+    await indexer.update();
+    expect(new Set(embedded().map((doc) => doc.metadata.path))).toEqual(
+      new Set(['docs/guide.md'])
+    );
+    await indexer.close();
+
+    // Recorded again, so the next update has nothing to do
+    const next = createIndexer({ embeddingProfiles: { docs: { embeddingMaxTokens: 64 } } });
+    await next.initialize();
+    expect((await next.getUpdatePlan())?.total).toBe(0);
+    await next.close();
+  });
+
+  it('should re-embed files moved to another profile', async () => {
+    const first = createIndexer();
+    await first.initialize();
+    await first.index();
+    await first.close();
+
+    const indexer = createIndexer({ embeddingProfileBindings: [] });
+    await indexer.initialize();
+
+    expect((await indexer.getUpdatePlan())?.changed).toEqual(['docs/guide.md']);
+    await indexer.close();
+  });
+});
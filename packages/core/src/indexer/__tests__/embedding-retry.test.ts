@@ -0,0 +1,108 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { VectorStorage } from '../../vector';
+import type { EmbeddingDocument } from '../../vector/types';
+import { RepositoryIndexer } from '../index';
+
+describe('RepositoryIndexer - embedding retries', () => {
+  let repoDir: string;
+  let failingFile: string | null;
+  let addDocuments: ReturnType<typeof vi.fn>;
+
+  beforeEach(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'indexer-retry-'));
+    await fs.writeFile(
+      path.join(repoDir, 'good.ts'),
+      'export function good(): number {\n  return 1;\n}\n'
+    );
+    await fs.writeFile(
+      path.join(repoDir, 'bad.ts'),
+      'export function bad(): number {\n  return 2;\n}\n'
+    );
+
+    failingFile = 'bad.ts';
+    addDocuments = vi.fn(async (docs: EmbeddingDocument[]) => {
+      if (docs.some((doc) => doc.metadata.path === failingFile)) {
+        throw Object.assign(new Error('Service unavailable'), { status: 503 });
+      }
+    });
+
+    vi.spyOn(VectorStorage.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'ensureEmbedder').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'addDocuments').mockImplementation(addDocuments);
+    vi.spyOn(VectorStorage.prototype, 'deleteDocuments').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'close').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'getStats').mockResolvedValue({
+      totalDocuments: 0,
+      storageSize: 0,
+      dimension: 384,
+      modelName: 'test',
+    });
+  });
+
+  afterEach(async () => {
+    vi.restoreAllMocks();
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  function createIndexer(): RepositoryIndexer {
+    return new RepositoryIndexer({
+      repositoryPath: repoDir,
+      vectorStorePath: path.join(repoDir, '.vectors'),
+      statePath: path.join(repoDir, '.state.json'),
+      batchSize: 10,
+      embeddingRetry: { maxRetries: 2, initialDelay: 1, maxDelay: 1 },
+    });
+  }
+
+  it('should retry transient failures and keep the rest of the batch', async () => {
+    const indexer = createIndexer();
+    await indexer.initialize();
+
+    const stats = await indexer.index();
+
+    // 1 attempt + 2 retries for the batch, then one call per document
+    expect(addDocuments.mock.calls.length).toBe(3 + stats.documentsExtracted);
+    expect(stats.documentsIndexed).toBe(stats.documentsExtracted - 1);
+    expect(stats.failedDocuments).toEqual([
+      expect.objectContaining({ file: 'bad.ts', error: 'Service unavailable' }),
+    ]);
+    expect(stats.errors[0].type).toBe('embedder');
+
+    await indexer.close();
+  });
+
+  it('should re-embed files with failed documents on the next update', async () => {
+    const indexer = createIndexer();
+    await indexer.initialize();
+    await indexer.index();
+
+    failingFile = null;
+    addDocuments.mockClear();
+
+    const stats = await indexer.update();
+
+    expect(stats.filesScanned).toBe(1);
+    expect(stats.failedDocuments).toEqual([]);
+    const embedded = addDocuments.mock.calls.flatMap(([docs]) => docs as EmbeddingDocument[]);
+    expect(embedded.map((doc) => doc.metadata.path)).toEqual(['bad.ts']);
+
+    await indexer.close();
+  });
+
+  it('should not retry non-transient failures', async () => {
+    addDocuments.mockRejectedValue(new Error('Invalid input'));
+    const indexer = createIndexer();
+    await indexer.initialize();
+
+    const stats = await indexer.index();
+
+    // One batch attempt, then one call per document
+    expect(addDocuments.mock.calls.length).toBe(1 + stats.documentsExtracted);
+    expect(stats.documentsIndexed).toBe(0);
+
+    await indexer.close();
+  });
+});
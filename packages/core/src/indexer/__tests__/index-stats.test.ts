@@ -0,0 +1,69 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { VectorStorage } from '../../vector';
+import type { EmbeddingDocument, StorageTiming } from '../../vector/types';
+import { RepositoryIndexer } from '../index';
+
+describe('RepositoryIndexer - index stats', () => {
+  let repoDir: string;
+
+  beforeEach(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'indexer-stats-'));
+    await fs.writeFile(
+      path.join(repoDir, 'math.ts'),
+      'export function add(a: number, b: number): number {\n  return a + b;\n}\n\n' +
+        'export class Calculator {\n  total = 0;\n}\n'
+    );
+    await fs.writeFile(path.join(repoDir, 'README.md'), '# Math\n\nAdds numbers.\n');
+
+    vi.spyOn(VectorStorage.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'ensureEmbedder').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'addDocuments').mockImplementation(
+      async (_docs: EmbeddingDocument[], timing?: StorageTiming) => {
+        if (timing) {
+          timing.embed += 5;
+          timing.store += 2;
+        }
+      }
+    );
+    vi.spyOn(VectorStorage.prototype, 'close').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'getStats').mockResolvedValue({
+      totalDocuments: 0,
+      storageSize: 0,
+      dimension: 384,
+      modelName: 'test',
+    });
+  });
+
+  afterEach(async () => {
+    vi.restoreAllMocks();
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  it('should report per-language counts, bytes embedded, and phase timings', async () => {
+    const indexer = new RepositoryIndexer({
+      repositoryPath: repoDir,
+      vectorStorePath: path.join(repoDir, '.vectors'),
+      statePath: path.join(repoDir, '.state.json'),
+    });
+    await indexer.initialize();
+
+    const stats = await indexer.index();
+
+    const byLanguage = stats.scanStats?.byLanguage;
+    expect(byLanguage?.typescript).toMatchObject({ files: 1, parseFailures: 0 });
+    expect(byLanguage?.typescript.byKind.function).toBe(1);
+    expect(byLanguage?.typescript.byKind.class).toBe(1);
+    expect(byLanguage?.markdown.documents).toBeGreaterThan(0);
+    expect(stats.scanStats?.byKind?.documentation).toBe(byLanguage?.markdown.documents);
+
+    expect(stats.bytesEmbedded).toBeGreaterThan(0);
+    expect(stats.timings?.embed).toBeGreaterThan(0);
+    expect(stats.timings?.store).toBeGreaterThan(0);
+    expect(stats.timings?.scan).toBe(stats.scanStats?.duration);
+
+    await indexer.close();
+  });
+});
@@ -163,6 +163,47 @@ describe('RepositoryIndexer - Edge Case Coverage', () => {
     await indexer.close();
   });
 
+  it('should report how stale the index is without re-indexing', async () => {
+    const repoDir = path.join(testDir, 'freshness');
+    await fs.mkdir(repoDir, { recursive: true });
+    for (const name of ['a', 'b', 'c']) {
+      await fs.writeFile(
+        path.join(repoDir, `${name}.ts`),
+        `export function ${name}() { return 1; }`,
+        'utf-8'
+      );
+    }
+
+    const indexer = new RepositoryIndexer({
+      repositoryPath: repoDir,
+      vectorStorePath: path.join(testDir, 'freshness.lance'),
+    });
+
+    expect(await indexer.checkFreshness()).toBeNull();
+
+    await indexer.initialize();
+    await indexer.index();
+    expect((await indexer.checkFreshness())?.staleFiles).toBe(0);
+
+    await fs.writeFile(path.join(repoDir, 'a.ts'), 'export function a() { return 2; }', 'utf-8');
+    await fs.writeFile(path.join(repoDir, 'd.ts'), 'export function d() { return 1; }', 'utf-8');
+
+    const freshness = await indexer.checkFreshness();
+    expect(freshness).toMatchObject({
+      trackedFiles: 3,
+      changed: ['a.ts'],
+      added: ['d.ts'],
+      deleted: [],
+      staleFiles: 2,
+      stalePercent: 50,
+    });
+
+    // Checking doesn't update the index
+    expect((await indexer.checkFreshness())?.staleFiles).toBe(2);
+
+    await indexer.close();
+  });
+
   it('should handle since date filtering in detectChangedFiles', async () => {
     const repoDir = path.join(testDir, 'since-filter');
     await fs.mkdir(repoDir, { recursive: true });
@@ -0,0 +1,84 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { VectorStorage } from '../../vector';
+import { RepositoryIndexer } from '../index';
+import type { IndexerState } from '../types';
+
+const GOOD = 'package shop\n\n// Total sums prices.\nfunc Total(prices []int) int {\n\treturn 0\n}\n';
+const BROKEN = 'package shop\n\n// Total sums prices.\nfunc Total(prices []int) int {\n\tfor {\n';
+
+describe('RepositoryIndexer - unparseable files', () => {
+  let repoDir: string;
+  let statePath: string;
+  let deleteDocuments: ReturnType<typeof vi.fn>;
+
+  beforeEach(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'indexer-parse-'));
+    statePath = path.join(repoDir, '.state.json');
+    await fs.writeFile(path.join(repoDir, 'shop.go'), GOOD);
+    await fs.writeFile(
+      path.join(repoDir, 'other.go'),
+      'package shop\n\n// Other is untouched.\nfunc Other() {}\n'
+    );
+
+    deleteDocuments = vi.fn().mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'ensureEmbedder').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'addDocuments').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'deleteDocuments').mockImplementation(deleteDocuments);
+    vi.spyOn(VectorStorage.prototype, 'close').mockResolvedValue(undefined);
+    vi.spyOn(VectorStorage.prototype, 'getStats').mockResolvedValue({
+      totalDocuments: 0,
+      storageSize: 0,
+      dimension: 384,
+      modelName: 'test',
+    });
+  });
+
+  afterEach(async () => {
+    vi.restoreAllMocks();
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  async function readState(): Promise<IndexerState> {
+    return JSON.parse(await fs.readFile(statePath, 'utf-8')) as IndexerState;
+  }
+
+  it('should keep previous documents while a file has syntax errors', async () => {
+    const indexer = new RepositoryIndexer({
+      repositoryPath: repoDir,
+      vectorStorePath: path.join(repoDir, '.vectors'),
+      statePath,
+    });
+    await indexer.initialize();
+    await indexer.index();
+    const indexedIds = (await readState()).files['shop.go'].documentIds;
+
+    // Break the file mid-refactor
+    await fs.writeFile(path.join(repoDir, 'shop.go'), BROKEN);
+    const brokenStats = await indexer.update();
+
+    expect(deleteDocuments).not.toHaveBeenCalled();
+    expect(brokenStats.errors).toEqual([
+      expect.objectContaining({ type: 'scanner', file: 'shop.go' }),
+    ]);
+    let state = await readState();
+    expect(state.files['shop.go'].documentIds).toEqual(indexedIds);
+    expect(state.files['shop.go'].parseError).toMatch(/^Syntax error/);
+    expect(state.files['other.go']).toBeDefined();
+
+    // Fixing the file re-indexes it, drops documents it no longer has, and clears
+    // the parse-failed state
+    await fs.writeFile(path.join(repoDir, 'shop.go'), GOOD.replace(/Total/g, 'Sum'));
+    const fixedStats = await indexer.update();
+
+    expect(deleteDocuments).toHaveBeenCalledWith(indexedIds);
+    expect(fixedStats.errors).toEqual([]);
+    state = await readState();
+    expect(state.files['shop.go'].parseError).toBeUndefined();
+
+    await indexer.close();
+  });
+});
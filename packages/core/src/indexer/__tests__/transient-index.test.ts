@@ -0,0 +1,78 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterAll, beforeAll, describe, expect, it } from 'vitest';
+import { buildDefinitions } from '../../context/definitions';
+import { scanPath, TransientIndex } from '../transient-index';
+
+describe('TransientIndex', () => {
+  let repoDir: string;
+
+  beforeAll(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'transient-index-'));
+    await fs.mkdir(path.join(repoDir, 'internal', 'store'), { recursive: true });
+    await fs.mkdir(path.join(repoDir, 'cmd'), { recursive: true });
+    await fs.writeFile(
+      path.join(repoDir, 'internal', 'store', 'store.go'),
+      'package store\n\n// Open opens the store.\nfunc Open(path string) (*Store, error) {\n' +
+        '\treturn &Store{}, nil\n}\n\n// Store holds records.\ntype Store struct{}\n'
+    );
+    await fs.writeFile(path.join(repoDir, 'internal', 'store', 'notes.bin'), '\u0000\u0001');
+    await fs.writeFile(path.join(repoDir, 'cmd', 'main.go'), 'package main\n\nfunc main() {}\n');
+    await fs.writeFile(path.join(repoDir, '.gitignore'), 'generated.go\n');
+    await fs.writeFile(path.join(repoDir, 'generated.go'), 'package main\n\nfunc Generated() {}\n');
+  });
+
+  afterAll(async () => {
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  describe('scanPath', () => {
+    it('should scan only the files under a directory', async () => {
+      const result = await scanPath({ repositoryPath: repoDir, path: 'internal/store' });
+
+      const files = new Set(result.documents.map((d) => d.metadata.file));
+      expect(files).toEqual(new Set(['internal/store/store.go']));
+      expect(result.documents.map((d) => d.metadata.name)).toEqual(
+        expect.arrayContaining(['Open', 'Store'])
+      );
+    });
+
+    it('should scan a single file, even one .gitignore excludes', async () => {
+      const result = await scanPath({
+        repositoryPath: repoDir,
+        path: path.join(repoDir, 'generated.go'),
+      });
+
+      expect(result.documents.map((d) => d.metadata.name)).toEqual(['Generated']);
+    });
+
+    it('should reject paths outside the repository or missing', async () => {
+      await expect(scanPath({ repositoryPath: repoDir, path: '../elsewhere' })).rejects.toThrow(
+        'outside the repository'
+      );
+      await expect(scanPath({ repositoryPath: repoDir, path: 'missing.go' })).rejects.toThrow();
+    });
+  });
+
+  it('should answer structural queries without embeddings', async () => {
+    const index = await TransientIndex.create({ repositoryPath: repoDir, path: 'internal/store' });
+
+    try {
+      expect(index.path).toBe('internal/store');
+      const docs = await index.getAll();
+      expect(docs.every((d) => d.metadata.path?.startsWith('internal/store/'))).toBe(true);
+
+      const definitions = buildDefinitions(docs, 'Open');
+      expect(definitions?.definitions[0].symbol.metadata).toMatchObject({
+        path: 'internal/store/store.go',
+        startLine: 4,
+      });
+    } finally {
+      await index.dispose();
+    }
+
+    await expect(index.getAll()).rejects.toThrow('disposed');
+    await index.dispose();
+  });
+});
@@ -7,11 +7,26 @@ import * as fs from 'node:fs/promises';
 import * as path from 'node:path';
 import type { Logger } from '@lytics/kero';
 import type { EventBus } from '../events/types.js';
+import { LocalGitExtractor } from '../git/extractor';
 import { buildCodeMetadata } from '../metrics/collector.js';
 import type { CodeMetadata } from '../metrics/types.js';
-import { scanRepository } from '../scanner';
-import type { Document } from '../scanner/types';
-import { getCurrentSystemResources, getOptimalConcurrency } from '../utils/concurrency';
+import { DEFAULT_MAX_DOCUMENT_BYTES, resolveGoPlatform, scanRepository } from '../scanner';
+import { annotateIdentifiers } from '../search/identifiers';
+import type {
+  Document,
+  ScanError,
+  ScanOptions,
+  ScanResult,
+  ScanStats,
+} from '../scanner/types';
+import {
+  ConcurrencyLimiter,
+  getCurrentSystemResources,
+  getEmbeddingConcurrency,
+  getOptimalConcurrency,
+} from '../utils/concurrency';
+import { ThroughputEstimator } from '../utils/progress';
+import { RetryPredicates, withRetry } from '../utils/retry';
 import { VectorStorage } from '../vector';
 import type { EmbeddingDocument, SearchOptions, SearchResult } from '../vector/types';
 import { validateDetailedIndexStats, validateIndexerState } from './schemas/validation.js';
@@ -19,30 +34,62 @@ import { StatsAggregator } from './stats-aggregator';
 import { mergeStats } from './stats-merger';
 import type {
   DetailedIndexStats,
+  DocumentEnricher,
+  FailedDocument,
   FileMetadata,
   IndexError,
   IndexerConfig,
   IndexerState,
+  IndexFreshness,
   IndexOptions,
+  IndexPhaseTimings,
   IndexStats,
   LanguageStats,
   PackageStats,
   SupportedLanguage,
   UpdateOptions,
 } from './types';
-import { getExtensionForLanguage, prepareDocumentsForEmbedding } from './utils';
+import {
+  annotateGoDiagnostics,
+  annotateLastModified,
+  applyEnrichers,
+  applySymbolFilters,
+  DEFAULT_BOILERPLATE_DOC_THRESHOLD,
+  docCommentKeys,
+  DEFAULT_EMBEDDING_PROFILE,
+  EmbeddingProfiles,
+  foldedVariantFiles,
+  getExtensionForLanguage,
+  loadEnrichers,
+  markBoilerplateDocs,
+  prepareDocumentsForEmbedding,
+  selectPlatformVariants,
+} from './utils';
 import { aggregateChangeFrequency, calculateChangeFrequency } from './utils/change-frequency.js';
 
-const INDEXER_VERSION = '1.0.0';
+const INDEXER_VERSION = '1.1.0';
 const DEFAULT_STATE_PATH = '.dev-agent/indexer-state.json';
+const DEFAULT_EMBEDDING_MAX_TOKENS = 256;
 
 /**
  * Repository Indexer
  * Orchestrates repository scanning, embedding generation, and vector storage
  */
 export class RepositoryIndexer {
-  private readonly config: Required<Omit<IndexerConfig, 'logger'>> & Pick<IndexerConfig, 'logger'>;
+  private readonly config: Required<
+    Omit<
+      IndexerConfig,
+      | 'logger'
+      | 'similarityMetric'
+      | 'quantization'
+      | 'truncateDimensions'
+      | 'maxConcurrentEmbeddings'
+    >
+  > &
+    Pick<IndexerConfig, 'logger' | 'similarityMetric' | 'quantization' | 'truncateDimensions'>;
   private vectorStorage: VectorStorage;
+  private readonly profiles: EmbeddingProfiles;
+  private readonly embeddingLimiter: ConcurrencyLimiter;
   private state: IndexerState | null = null;
   private eventBus?: EventBus;
   private logger?: Logger;
@@ -52,18 +99,54 @@ export class RepositoryIndexer {
       statePath: path.join(config.repositoryPath, DEFAULT_STATE_PATH),
       embeddingModel: 'Xenova/all-MiniLM-L6-v2',
       embeddingDimension: 384,
+      embeddingMaxTokens: DEFAULT_EMBEDDING_MAX_TOKENS,
+      embeddingText: 'source',
+      embeddingProfiles: {},
+      embeddingProfile: DEFAULT_EMBEDDING_PROFILE,
+      embeddingProfileBindings: [],
+      embeddingCache: true,
       batchSize: 32,
+      embeddingRetry: {},
       excludePatterns: [],
+      ignorePatterns: [],
       languages: [],
+      symbolFilters: {},
+      blame: false,
+      goDiagnostics: false,
+      boilerplateDocThreshold: DEFAULT_BOILERPLATE_DOC_THRESHOLD,
+      targetPlatform: {},
       ...config,
+      // Copied so registerEnricher() never touches the caller's array
+      enrichers: [...(config.enrichers ?? [])],
     };
 
     this.vectorStorage = new VectorStorage({
       storePath: this.config.vectorStorePath,
       embeddingModel: this.config.embeddingModel,
       dimension: this.config.embeddingDimension,
+      metric: this.config.similarityMetric,
+      quantization: this.config.quantization,
+      truncateDimensions: this.config.truncateDimensions,
+      embeddingCacheDir: this.config.embeddingCache
+        ? path.join(path.dirname(this.config.vectorStorePath), 'embedding-cache')
+        : undefined,
     });
 
+    this.profiles = new EmbeddingProfiles({
+      defaults: {
+        embeddingText: this.config.embeddingText,
+        embeddingMaxTokens: this.config.embeddingMaxTokens ?? DEFAULT_EMBEDDING_MAX_TOKENS,
+        maxDocumentBytes: this.config.maxDocumentBytes ?? DEFAULT_MAX_DOCUMENT_BYTES,
+      },
+      profiles: this.config.embeddingProfiles,
+      profile: this.config.embeddingProfile,
+      bindings: this.config.embeddingProfileBindings,
+    });
+
+    this.embeddingLimiter = new ConcurrencyLimiter(
+      getEmbeddingConcurrency(config.maxConcurrentEmbeddings, process.env)
+    );
+
     this.eventBus = eventBus;
     this.logger = config.logger;
   }
@@ -90,14 +173,14 @@ export class RepositoryIndexer {
     let filesScanned = 0;
     let documentsExtracted = 0;
     const _documentsIndexed = 0;
+    const timings: IndexPhaseTimings = { scan: 0, embed: 0, store: 0 };
+    let bytesEmbedded = 0;
+    this.vectorStorage.resetEmbeddingCacheStats();
+    const signal = options.signal;
+    let cleared = false;
 
     try {
-      // Clear vector store if force re-index requested
-      if (options.force) {
-        options.logger?.info('Force re-index requested, clearing existing vectors');
-        await this.vectorStorage.clear();
-        this.state = null; // Reset state to force fresh scan
-      }
+      signal?.throwIfAborted();
 
       // Phase 1: Scan repository
       const onProgress = options.onProgress;
@@ -109,29 +192,33 @@ export class RepositoryIndexer {
         percentComplete: 0,
       });
 
-      const scanResult = await scanRepository({
+      const scanResult = await this.scan({
         repoRoot: this.config.repositoryPath,
         include: options.languages?.map((lang) => `**/*.${getExtensionForLanguage(lang)}`),
-        exclude: [...this.config.excludePatterns, ...(options.excludePatterns || [])],
+        exclude: this.resolveExcludes(options.excludePatterns),
+        ignore: this.config.ignorePatterns,
+        maxDocumentBytes: (file) => this.profiles.forFile(file).maxDocumentBytes,
         languages: options.languages,
         logger: options.logger,
+        signal,
         onProgress: (scanProgress) => {
-          // Forward scanner progress to indexer progress callback
+          // Forward scanner progress (already throttled) as the first third of the run
           onProgress?.({
             phase: 'scanning',
             filesProcessed: scanProgress.filesScanned,
             totalFiles: scanProgress.filesTotal,
             documentsIndexed: scanProgress.documentsExtracted,
-            percentComplete:
-              scanProgress.filesTotal > 0
-                ? Math.round((scanProgress.filesScanned / scanProgress.filesTotal) * 100)
-                : 0,
+            percentComplete: Math.round(scanProgress.percentComplete * 0.33),
+            etaSeconds: scanProgress.etaSeconds,
           });
         },
       });
 
       filesScanned = scanResult.stats.filesScanned;
       documentsExtracted = scanResult.documents.length;
+      timings.scan = scanResult.stats.duration;
+      const parseFailures = collectParseFailures(scanResult.stats.errors);
+      errors.push(...toParseErrors(parseFailures));
 
       // Aggregate detailed statistics
       const statsAggregator = new StatsAggregator();
@@ -151,13 +238,37 @@ export class RepositoryIndexer {
         percentComplete: 33,
       });
 
-      const embeddingDocuments = prepareDocumentsForEmbedding(scanResult.documents);
+      await this.enrich(scanResult.documents, logger);
+      await this.annotateBlame(scanResult.documents, logger);
+      await this.annotateDiagnostics(scanResult.documents, logger, options.force);
+      this.markBoilerplate(scanResult.documents, logger);
+      // Embedding text is sized with the model's tokenizer, so load it first
+      await this.vectorStorage.ensureEmbedder();
+      const embeddingDocuments = this.prepareForEmbedding(scanResult.documents);
+
+      // A forced re-index replaces documents in place and removes the ones the
+      // scan no longer found only after every batch is stored, so cancelling
+      // leaves the existing index searchable. A store written under other
+      // settings can't take the new vectors and is cleared first instead.
+      let replacedIds: string[] | undefined;
+      if (options.force) {
+        signal?.throwIfAborted();
+        if (await this.vectorStorage.acceptsEmbeddings()) {
+          replacedIds = await this.vectorStorage.getDocumentIds();
+        } else {
+          options.logger?.info('Force re-index requested, clearing existing vectors');
+          await this.vectorStorage.clear();
+          this.state = null; // Reset state to force fresh scan
+          cleared = true;
+        }
+      }
 
       // Phase 3: Batch embed and store
       logger?.info(
         {
           documents: embeddingDocuments.length,
           batchSize: options.batchSize || this.config.batchSize,
+          maxConcurrentEmbeddings: this.embeddingLimiter.limit,
         },
         'Starting embedding and storage'
       );
@@ -186,35 +297,43 @@ export class RepositoryIndexer {
 
       // Process batches in parallel groups
       let documentsIndexed = 0;
+      const throughput = new ThroughputEstimator();
+      throughput.record(0);
+      const failedDocuments: FailedDocument[] = [];
       const batchGroups: EmbeddingDocument[][][] = [];
       for (let i = 0; i < batches.length; i += CONCURRENCY) {
         batchGroups.push(batches.slice(i, i + CONCURRENCY));
       }
 
       for (let groupIndex = 0; groupIndex < batchGroups.length; groupIndex++) {
+        signal?.throwIfAborted();
         const batchGroup = batchGroups[groupIndex];
 
         // Process all batches in this group concurrently
         const results = await Promise.allSettled(
           batchGroup.map(async (batch, batchIndexInGroup) => {
             const batchNum = groupIndex * CONCURRENCY + batchIndexInGroup + 1;
-            try {
-              await this.vectorStorage.addDocuments(batch);
-              return { success: true, count: batch.length, batchNum };
-            } catch (error) {
-              const errorMessage = error instanceof Error ? error.message : String(error);
+            const { stored, failed, bytes } = await this.storeBatch(batch, timings, logger, signal);
+            bytesEmbedded += bytes;
+            if (failed.length > 0) {
+              failedDocuments.push(...failed);
               errors.push({
-                type: 'storage',
-                message: `Failed to store batch ${batchNum}: ${errorMessage}`,
-                error: error instanceof Error ? error : undefined,
+                type: 'embedder',
+                message: `Failed to embed ${failed.length} document(s) in batch ${batchNum}: ${failed[0].error}`,
                 timestamp: new Date(),
               });
-              logger?.error({ batch: batchNum, error: errorMessage }, 'Batch embedding failed');
-              return { success: false, count: 0, batchNum };
+              logger?.error(
+                { batch: batchNum, failed: failed.length, error: failed[0].error },
+                'Batch embedding failed'
+              );
             }
+            return { success: stored > 0, count: stored, batchNum };
           })
         );
 
+        // A cancelled batch writes nothing; stop before starting the next group
+        signal?.throwIfAborted();
+
         // Update progress after each group
         for (const result of results) {
           if (result.status === 'fulfilled' && result.value.success) {
@@ -249,6 +368,7 @@ export class RepositoryIndexer {
         }
 
         // Update progress callback
+        throughput.record(documentsIndexed);
         onProgress?.({
           phase: 'storing',
           filesProcessed: filesScanned,
@@ -256,10 +376,35 @@ export class RepositoryIndexer {
           documentsIndexed,
           totalDocuments: embeddingDocuments.length,
           percentComplete: 66 + (documentsIndexed / embeddingDocuments.length) * 33,
+          etaSeconds: throughput.eta(embeddingDocuments.length),
         });
       }
 
-      logger?.info({ documentsIndexed, errors: errors.length }, 'Embedding complete');
+      logger?.info(
+        { documentsIndexed, failed: failedDocuments.length, errors: errors.length },
+        'Embedding complete'
+      );
+
+      if (replacedIds) {
+        const current = new Set(embeddingDocuments.map((doc) => doc.id));
+        const removed = replacedIds.filter((id) => !current.has(id));
+        if (removed.length > 0) {
+          await this.vectorStorage.deleteDocuments(removed);
+        }
+        this.state = null; // Rebuilt from this scan below
+      }
+      logger?.info(
+        {
+          filesScanned,
+          documentsIndexed,
+          bytesEmbedded,
+          timings,
+          byKind: scanResult.stats.byKind,
+          byLanguage: scanResult.stats.byLanguage,
+          parseFailures: scanResult.stats.errors.length,
+        },
+        'Index stats'
+      );
 
       // Phase 4: Complete
       const endTime = new Date();
@@ -274,6 +419,7 @@ export class RepositoryIndexer {
       // Get detailed stats from aggregator
       const detailedStats = statsAggregator.getDetailedStats();
 
+      await this.vectorStorage.flushEmbeddingCache();
       const stats: DetailedIndexStats = {
         filesScanned,
         documentsExtracted,
@@ -291,13 +437,24 @@ export class RepositoryIndexer {
           lastUpdate: endTime,
           incrementalUpdatesSince: 0,
         },
+        failedDocuments,
+        timings,
+        bytesEmbedded,
+        embeddingCache: this.vectorStorage.getEmbeddingCacheStats() ?? undefined,
+        scanStats: scanResult.stats,
       };
 
-      // Update state with file metadata and detailed stats
-      await this.updateState(scanResult.documents, detailedStats);
+      // Update state with file metadata and detailed stats. Files with failed
+      // documents are left out so the next update re-embeds them.
+      await this.updateState(
+        withoutFailedFiles(scanResult.documents, failedDocuments),
+        detailedStats,
+        parseFailures
+      );
 
       // Reset incremental update counter after full index
       if (this.state) {
+        this.state.embeddingText = this.config.embeddingText;
         this.state.incrementalUpdatesSince = 0;
         this.state.lastUpdate = endTime;
       }
@@ -332,6 +489,13 @@ export class RepositoryIndexer {
 
       return stats;
     } catch (error) {
+      if (cleared && signal?.aborted) {
+        // The store was cleared, so the persisted state no longer describes it.
+        // Without state, the next update falls back to a full index.
+        this.state = null;
+        await fs.rm(this.config.statePath, { force: true });
+      }
+
       errors.push({
         type: 'scanner',
         message: `Indexing failed: ${error instanceof Error ? error.message : String(error)}`,
@@ -356,7 +520,13 @@ export class RepositoryIndexer {
     const errors: IndexError[] = [];
 
     // Determine which files need reindexing
-    const { changed, added, deleted } = await this.detectChangedFiles(options.since);
+    const { changed, added, deleted } = this.withGoPackages(
+      this.withPlatformVariants(
+        options.files
+          ? await this.classifyFiles(options.files)
+          : await this.detectChangedFiles(options.since)
+      )
+    );
     const filesToReindex = [...changed, ...added];
 
     if (filesToReindex.length === 0 && deleted.length === 0) {
@@ -374,6 +544,99 @@ export class RepositoryIndexer {
       };
     }
 
+    // New documents are stored before old ones are removed, so a cancelled update
+    // leaves the persisted index intact
+    const signal = options.signal;
+    signal?.throwIfAborted();
+
+    // Scan and index changed + added files
+    let documentsExtracted = 0;
+    let documentsIndexed = 0;
+    const failedDocuments: FailedDocument[] = [];
+    let incrementalStats: ReturnType<StatsAggregator['getDetailedStats']> | null = null;
+    const affectedLanguages = new Set<string>();
+    let scannedDocuments: Document[] = [];
+    const timings: IndexPhaseTimings = { scan: 0, embed: 0, store: 0 };
+    let bytesEmbedded = 0;
+    this.vectorStorage.resetEmbeddingCacheStats();
+    let scanStats: ScanStats | undefined;
+    let parseFailures = new Map<string, string>();
+    const keptFiles = new Set<string>();
+
+    if (filesToReindex.length > 0) {
+      const scanResult = await this.scan({
+        repoRoot: this.config.repositoryPath,
+        include: filesToReindex,
+        exclude: this.resolveExcludes(),
+        ignore: this.config.ignorePatterns,
+        maxDocumentBytes: (file) => this.profiles.forFile(file).maxDocumentBytes,
+        logger: options.logger,
+        signal,
+      });
+
+      scanStats = scanResult.stats;
+      timings.scan = scanResult.stats.duration;
+
+      // A changed file that no longer parses keeps its previous documents until it is fixed,
+      // so a temporary syntax error doesn't remove the file from the index
+      parseFailures = collectParseFailures(scanResult.stats.errors);
+      errors.push(...toParseErrors(parseFailures));
+      for (const file of changed) {
+        if (parseFailures.has(file) && this.state.files[file]?.documentIds.length) {
+          keptFiles.add(file);
+        }
+      }
+      if (keptFiles.size > 0) {
+        options.logger?.warn(
+          { files: Array.from(keptFiles) },
+          `Kept previous documents for ${keptFiles.size} file(s) with syntax errors`
+        );
+      }
+
+      scannedDocuments = scanResult.documents.filter((doc) => !keptFiles.has(doc.metadata.file));
+      documentsExtracted = scannedDocuments.length;
+
+      // Calculate stats for incremental changes
+      const statsAggregator = new StatsAggregator();
+      for (const doc of scannedDocuments) {
+        statsAggregator.addDocument(doc);
+        affectedLanguages.add(doc.language);
+      }
+      incrementalStats = statsAggregator.getDetailedStats();
+
+      // Index new documents
+      await this.enrich(scannedDocuments, options.logger);
+      await this.annotateBlame(scannedDocuments, options.logger);
+      await this.annotateDiagnostics(scannedDocuments, options.logger);
+      const replaced = [...filesToReindex.filter((file) => !keptFiles.has(file)), ...deleted];
+      this.markBoilerplate(scannedDocuments, options.logger, new Set(replaced));
+      await this.vectorStorage.ensureEmbedder();
+      const embeddingDocuments = this.prepareForEmbedding(scannedDocuments);
+      const batchSize = options.batchSize || this.config.batchSize;
+      for (let i = 0; i < embeddingDocuments.length; i += batchSize) {
+        signal?.throwIfAborted();
+        const { stored, failed, bytes } = await this.storeBatch(
+          embeddingDocuments.slice(i, i + batchSize),
+          timings,
+          options.logger,
+          signal
+        );
+        documentsIndexed += stored;
+        bytesEmbedded += bytes;
+        failedDocuments.push(...failed);
+      }
+      if (failedDocuments.length > 0) {
+        errors.push({
+          type: 'embedder',
+          message: `Failed to embed ${failedDocuments.length} document(s): ${failedDocuments[0].error}`,
+          timestamp: new Date(),
+        });
+      }
+    }
+
+    // Past this point the update is committed and runs to completion
+    signal?.throwIfAborted();
+
     // Delete documents for deleted files
     for (const file of deleted) {
       const oldMetadata = this.state.files[file];
@@ -394,60 +657,46 @@ export class RepositoryIndexer {
       delete this.state.files[file];
     }
 
-    // Delete old documents for changed files (not added - they have no old docs)
+    // Delete old documents of changed files that weren't replaced by the new scan
+    // (re-extracted documents with the same ID were already overwritten)
+    const storedIds = new Set(scannedDocuments.map((doc) => doc.id));
     for (const file of changed) {
       const oldMetadata = this.state.files[file];
-      if (oldMetadata?.documentIds) {
-        try {
-          await this.vectorStorage.deleteDocuments(oldMetadata.documentIds);
-        } catch (error) {
-          errors.push({
-            type: 'storage',
-            message: `Failed to delete old documents for ${file}`,
-            file,
-            error: error instanceof Error ? error : undefined,
-            timestamp: new Date(),
-          });
-        }
+      if (!oldMetadata) continue;
+      if (keptFiles.has(file)) {
+        oldMetadata.parseError = parseFailures.get(file);
+        continue;
       }
-    }
-
-    // Scan and index changed + added files
-    let documentsExtracted = 0;
-    let documentsIndexed = 0;
-    let incrementalStats: ReturnType<StatsAggregator['getDetailedStats']> | null = null;
-    const affectedLanguages = new Set<string>();
-    let scannedDocuments: Document[] = [];
 
-    if (filesToReindex.length > 0) {
-      const scanResult = await scanRepository({
-        repoRoot: this.config.repositoryPath,
-        include: filesToReindex,
-        exclude: this.config.excludePatterns,
-        logger: options.logger,
-      });
-
-      scannedDocuments = scanResult.documents;
-      documentsExtracted = scanResult.documents.length;
-
-      // Calculate stats for incremental changes
-      const statsAggregator = new StatsAggregator();
-      for (const doc of scanResult.documents) {
-        statsAggregator.addDocument(doc);
-        affectedLanguages.add(doc.language);
+      const staleIds = oldMetadata.documentIds.filter((id) => !storedIds.has(id));
+      if (staleIds.length === 0) continue;
+      try {
+        await this.vectorStorage.deleteDocuments(staleIds);
+      } catch (error) {
+        errors.push({
+          type: 'storage',
+          message: `Failed to delete old documents for ${file}`,
+          file,
+          error: error instanceof Error ? error : undefined,
+          timestamp: new Date(),
+        });
       }
-      incrementalStats = statsAggregator.getDetailedStats();
-
-      // Index new documents
-      const embeddingDocuments = prepareDocumentsForEmbedding(scanResult.documents);
-      await this.vectorStorage.addDocuments(embeddingDocuments);
-      documentsIndexed = embeddingDocuments.length;
+    }
 
+    if (filesToReindex.length > 0) {
       // Merge incremental stats into state (updates the full repository stats)
-      this.applyStatsMerge(deleted, changed, incrementalStats);
+      this.applyStatsMerge(
+        deleted,
+        changed.filter((file) => !keptFiles.has(file)),
+        incrementalStats
+      );
 
-      // Update state with new documents
-      await this.updateState(scanResult.documents);
+      // Update state with new documents (files with failed documents are retried next time)
+      await this.updateState(
+        withoutFailedFiles(scannedDocuments, failedDocuments),
+        undefined,
+        parseFailures
+      );
     } else {
       // Only deletions - need to update stats by removing deleted file contributions
       if (deleted.length > 0) {
@@ -470,6 +719,8 @@ export class RepositoryIndexer {
     const lastFullIndex = this.state?.lastIndexTime || endTime;
     const warning = this.getStatsWarning(incrementalUpdatesSince);
 
+    await this.vectorStorage.flushEmbeddingCache();
+
     // Return incremental stats (what changed) with metadata
     const stats: DetailedIndexStats = {
       filesScanned: filesToReindex.length,
@@ -491,6 +742,11 @@ export class RepositoryIndexer {
         affectedLanguages: Array.from(affectedLanguages) as SupportedLanguage[],
         warning,
       },
+      failedDocuments,
+      timings,
+      bytesEmbedded,
+      embeddingCache: this.vectorStorage.getEmbeddingCacheStats() ?? undefined,
+      scanStats,
     };
 
     // Build code metadata for metrics storage (only for updated files)
@@ -526,11 +782,215 @@ export class RepositoryIndexer {
     return stats;
   }
 
+  /**
+   * Build embedding text with each file's profile, recording the profile on its documents
+   *
+   * Token budgets are measured with the embedder's tokenizer.
+   */
+  private prepareForEmbedding(documents: Document[]): EmbeddingDocument[] {
+    const countTokens = (text: string) => this.vectorStorage.countTokens(text);
+    return documents.flatMap((doc) => {
+      const profile = this.profiles.forFile(doc.metadata.file);
+      doc.metadata.embeddingProfile = profile.key;
+      const budget = { maxTokens: profile.embeddingMaxTokens, countTokens };
+      return prepareDocumentsForEmbedding([doc], budget, profile.embeddingText);
+    });
+  }
+
+  /**
+   * Whether a file's documents were embedded with a different profile than it has now
+   *
+   * Files indexed before profiles were recorded only know the index's embedding
+   * text mode, so only a change of mode re-embeds them.
+   */
+  private profileChanged(metadata: FileMetadata): boolean {
+    const profile = this.profiles.forFile(metadata.path);
+    if (metadata.embeddingProfile !== undefined) {
+      return metadata.embeddingProfile !== profile.key;
+    }
+    return profile.embeddingText !== (this.state?.embeddingText ?? 'source');
+  }
+
+  /**
+   * Embed and store one batch, retrying transient failures with backoff
+   *
+   * Every embedding request waits for a slot under maxConcurrentEmbeddings,
+   * and a throttled request gives up its slot while it backs off, so bursts
+   * queue instead of piling onto a rate-limited provider. If the batch still
+   * fails, its documents are tried one at a time so a single bad document
+   * doesn't discard the rest of the batch.
+   */
+  private async storeBatch(
+    batch: EmbeddingDocument[],
+    timings: IndexPhaseTimings,
+    logger?: Logger,
+    signal?: AbortSignal
+  ): Promise<{ stored: number; failed: FailedDocument[]; bytes: number }> {
+    const { maxRetries = 5, initialDelay = 500, maxDelay = 30000 } = this.config.embeddingRetry;
+
+    try {
+      const embed = () => this.vectorStorage.addDocuments(batch, timings, signal);
+      await withRetry(() => this.embeddingLimiter.run(embed), {
+        maxRetries,
+        initialDelay,
+        maxDelay,
+        isRetriable: (error) => !signal?.aborted && RetryPredicates.transientHttp(error),
+        onRetry: (attempt, delay, error) => {
+          logger?.warn(
+            { attempt, maxRetries, delayMs: Math.round(delay), error: errorMessage(error) },
+            'Embedding batch failed, retrying'
+          );
+        },
+      });
+      return { stored: batch.length, failed: [], bytes: textBytes(batch) };
+    } catch (error) {
+      signal?.throwIfAborted();
+      if (batch.length === 1) {
+        return { stored: 0, failed: [toFailedDocument(batch[0], error)], bytes: 0 };
+      }
+      logger?.warn(
+        { documents: batch.length, error: errorMessage(error) },
+        'Embedding batch failed, falling back to single documents'
+      );
+    }
+
+    let stored = 0;
+    let bytes = 0;
+    const failed: FailedDocument[] = [];
+    for (const doc of batch) {
+      try {
+        await this.embeddingLimiter.run(() =>
+          this.vectorStorage.addDocuments([doc], timings, signal)
+        );
+        stored++;
+        bytes += textBytes([doc]);
+      } catch (error) {
+        signal?.throwIfAborted();
+        failed.push(toFailedDocument(doc, error));
+      }
+    }
+    return { stored, failed, bytes };
+  }
+
+  /**
+   * Export the index (documents, vectors, and incremental-update state) to an NDJSON dump
+   *
+   * @param filePath - Dump file to write
+   * @returns Number of documents exported
+   */
+  async exportIndex(filePath: string): Promise<number> {
+    return this.vectorStorage.exportDump(filePath, this.state ? { state: this.state } : {});
+  }
+
+  /**
+   * Replace the index with an NDJSON dump from exportIndex()
+   *
+   * The dump's state is adopted for this repository path, so `update` only
+   * re-indexes files that differ from the exported index.
+   *
+   * @returns Number of documents imported
+   */
+  async importIndex(filePath: string): Promise<number> {
+    const header = await this.vectorStorage.importDump(filePath);
+
+    const validation = header.state ? validateIndexerState(header.state) : undefined;
+    if (validation?.success) {
+      this.state = {
+        ...validation.data,
+        repositoryPath: this.config.repositoryPath,
+        lastUpdate: new Date(),
+      };
+      await this.saveState();
+    } else {
+      // Without state every file looks changed, so the next update re-embeds the repository
+      this.state = null;
+      await fs.rm(this.config.statePath, { force: true });
+    }
+    return header.documents;
+  }
+
+  /**
+   * Add a post-scan hook whose fields are stored with every document indexed from now on
+   *
+   * @example
+   * ```typescript
+   * indexer.registerEnricher((metadata) => ({ team: owners.teamFor(metadata.file) }));
+   * ```
+   */
+  registerEnricher(enricher: DocumentEnricher): void {
+    this.config.enrichers.push(enricher);
+  }
+
+  /**
+   * Run the configured and registered enrichers over scanned documents
+   */
+  private async enrich(documents: Document[], logger?: Logger): Promise<void> {
+    if (this.config.enrichers.length === 0 || documents.length === 0) return;
+    const enrichers = await loadEnrichers(this.config.enrichers, this.config.repositoryPath);
+    const enriched = await applyEnrichers(documents, enrichers, logger);
+    logger?.debug({ enrichers: enrichers.length, enriched }, 'Enriched document metadata');
+  }
+
+  /**
+   * Record each document's last commit from git blame, when enabled
+   */
+  private async annotateBlame(documents: Document[], logger?: Logger): Promise<void> {
+    if (!this.config.blame || documents.length === 0) return;
+    const start = Date.now();
+    const git = new LocalGitExtractor(this.config.repositoryPath);
+    const files = await annotateLastModified(documents, git);
+    logger?.debug({ files, duration: Date.now() - start }, 'Attributed symbols with git blame');
+  }
+
+  /**
+   * Attach go build and go vet findings to Go symbols, when enabled
+   *
+   * @param refresh - Run every package again instead of reusing cached results
+   */
+  private async annotateDiagnostics(
+    documents: Document[],
+    logger?: Logger,
+    refresh = false
+  ): Promise<void> {
+    if (!this.config.goDiagnostics || documents.length === 0) return;
+    const start = Date.now();
+    const stats = await annotateGoDiagnostics(documents, {
+      repositoryPath: this.config.repositoryPath,
+      cachePath: path.join(path.dirname(this.config.statePath), 'go-diagnostics.json'),
+      refresh,
+      logger,
+    });
+    logger?.debug({ ...stats, duration: Date.now() - start }, 'Attached Go diagnostics');
+  }
+
+  /**
+   * Mark documents whose doc comment is repeated across many symbols, so it isn't embedded
+   *
+   * Comments are counted over the documents being indexed plus, on an update, the
+   * files the index keeps.
+   *
+   * @param replaced - Files whose indexed documents are being replaced or removed
+   */
+  private markBoilerplate(documents: Document[], logger?: Logger, replaced?: Set<string>): void {
+    const keptFiles =
+      replaced && this.state
+        ? Object.values(this.state.files).filter((file) => !replaced.has(file.path))
+        : [];
+    const others = keptFiles.flatMap((file) => file.docComments ?? []);
+    const marked = markBoilerplateDocs(documents, others, this.config.boilerplateDocThreshold);
+    if (marked > 0) {
+      logger?.debug({ marked }, 'Left boilerplate doc comments out of embedding text');
+    }
+  }
+
   /**
    * Search the indexed repository
+   *
+   * Identifiers in the query are followed by their words, matching how
+   * document names are embedded.
    */
   async search(query: string, options?: SearchOptions): Promise<SearchResult[]> {
-    return this.vectorStorage.search(query, options);
+    return this.vectorStorage.search(annotateIdentifiers(query), options);
   }
 
   /**
@@ -541,6 +1001,27 @@ export class RepositoryIndexer {
     return this.vectorStorage.searchByDocumentId(documentId, options);
   }
 
+  /**
+   * Embed a query for searchByVector, with identifiers annotated as in search()
+   */
+  async embedQuery(query: string): Promise<number[]> {
+    return this.vectorStorage.embedQuery(annotateIdentifiers(query));
+  }
+
+  /**
+   * Search with a precomputed query vector (see embedQuery and getDocumentVector)
+   */
+  async searchByVector(vector: number[], options?: SearchOptions): Promise<SearchResult[]> {
+    return this.vectorStorage.searchByVector(vector, options);
+  }
+
+  /**
+   * Stored embedding of an indexed document, or null if there is none
+   */
+  async getDocumentVector(documentId: string): Promise<number[] | null> {
+    return this.vectorStorage.getDocumentVector(documentId);
+  }
+
   /**
    * Get all indexed documents without semantic search (fast scan)
    * Use this when you need all documents and don't need relevance ranking
@@ -567,6 +1048,41 @@ export class RepositoryIndexer {
     };
   }
 
+  /**
+   * Identify the current index contents
+   *
+   * Changes whenever the index is written (full index or incremental update),
+   * so anything derived from search results, like pagination cursors, can
+   * tell when it has gone stale.
+   *
+   * @returns An opaque version string, or null if nothing is indexed
+   */
+  getIndexVersion(): string | null {
+    if (!this.state) {
+      return null;
+    }
+    const lastWrite = new Date(this.state.lastUpdate ?? this.state.lastIndexTime).getTime();
+    const lastIndex = new Date(this.state.lastIndexTime).getTime();
+    return `${Math.max(lastWrite, lastIndex)}:${this.state.stats.totalDocuments}`;
+  }
+
+  /**
+   * Repository-relative paths of every indexed file
+   */
+  getTrackedFiles(): string[] {
+    return Object.keys(this.state?.files ?? {});
+  }
+
+  /**
+   * Get the content hash a file had when it was last indexed
+   *
+   * @param filePath - Path relative to the repository root
+   * @returns SHA-256 of the indexed content, or undefined if the file isn't tracked
+   */
+  getFileHash(filePath: string): string | undefined {
+    return this.state?.files[filePath]?.hash;
+  }
+
   async getStats(): Promise<DetailedIndexStats | null> {
     if (!this.state) {
       return null;
@@ -641,6 +1157,34 @@ export class RepositoryIndexer {
     };
   }
 
+  /**
+   * Compare stored file hashes against the repository to measure staleness
+   *
+   * Reads every tracked file and scans for new ones, so it costs about as much
+   * as planning an update; nothing is re-indexed.
+   *
+   * @returns Freshness, or null if the repository hasn't been indexed
+   */
+  async checkFreshness(): Promise<IndexFreshness | null> {
+    if (!this.state) {
+      return null;
+    }
+
+    const { changed, added, deleted } = await this.detectChangedFiles();
+    const trackedFiles = Object.keys(this.state.files).length;
+    const staleFiles = changed.length + added.length + deleted.length;
+    const total = trackedFiles + added.length;
+    return {
+      trackedFiles,
+      changed,
+      added,
+      deleted,
+      staleFiles,
+      stalePercent: total > 0 ? Math.round((staleFiles / total) * 1000) / 10 : 0,
+      checkedAt: new Date().toISOString(),
+    };
+  }
+
   /**
    * Enrich language stats with change frequency data
    * Non-blocking: returns original stats if git analysis fails
@@ -888,7 +1432,8 @@ export class RepositoryIndexer {
           languages: Partial<Record<string, number>>;
         }
       >;
-    }
+    },
+    parseFailures?: Map<string, string>
   ): Promise<void> {
     if (!this.state) {
       this.state = {
@@ -915,6 +1460,13 @@ export class RepositoryIndexer {
       fileMap.get(doc.metadata.file)?.push(doc);
     }
 
+    // Files whose symbols were all folded into another platform's variants are tracked too,
+    // so changes to them are still detected
+    const foldedFiles = foldedVariantFiles(documents);
+    for (const file of foldedFiles.keys()) {
+      fileMap.set(file, []);
+    }
+
     // Update file metadata
     for (const [filePath, docs] of fileMap) {
       const fullPath = path.join(this.config.repositoryPath, filePath);
@@ -937,7 +1489,12 @@ export class RepositoryIndexer {
         lastIndexed: new Date(),
         documentIds: docs.map((d) => d.id),
         size: stat.size,
-        language: docs[0]?.language || 'unknown',
+        language: docs[0]?.language || (foldedFiles.has(filePath) ? 'go' : 'unknown'),
+        parseError: parseFailures?.get(filePath),
+        usesCgo: docs.some((d) => d.metadata.usesCgo) || undefined,
+        buildConstraint: docs[0]?.metadata.buildConstraint ?? foldedFiles.get(filePath),
+        embeddingProfile: docs[0]?.metadata.embeddingProfile ?? this.profiles.forFile(filePath).key,
+        docComments: docs.length > 0 ? docCommentKeys(docs) : undefined,
       };
 
       this.state.files[filePath] = metadata;
@@ -991,6 +1548,12 @@ export class RepositoryIndexer {
       try {
         const stat = await fs.stat(fullPath);
 
+        // Embedded with a profile since edited or rebound, whatever its content
+        if (this.profileChanged(metadata)) {
+          changed.push(filePath);
+          continue;
+        }
+
         // Check if modified after 'since' date
         if (since && stat.mtime <= since) {
           continue;
@@ -1010,9 +1573,10 @@ export class RepositoryIndexer {
     }
 
     // Scan for new files not in state
-    const scanResult = await scanRepository({
+    const scanResult = await this.scan({
       repoRoot: this.config.repositoryPath,
-      exclude: this.config.excludePatterns,
+      exclude: this.resolveExcludes(),
+      ignore: this.config.ignorePatterns,
     });
 
     const trackedFiles = new Set(Object.keys(this.state.files));
@@ -1031,6 +1595,136 @@ export class RepositoryIndexer {
     return { changed, added: uniqueAdded, deleted };
   }
 
+  /**
+   * Scan the repository, leaving out the symbols the configured filters exclude
+   *
+   * Every scan goes through here so a fully filtered file looks the same to
+   * indexing, updates, and change detection: a file with no documents.
+   */
+  private async scan(options: ScanOptions): Promise<ScanResult> {
+    const result = await scanRepository(options);
+    const filtered = applySymbolFilters(result.documents, this.config.symbolFilters);
+    if (filtered.skipped > 0) {
+      options.logger?.debug(
+        { skipped: filtered.skipped },
+        `Skipped ${filtered.skipped} document(s) by symbol filters`
+      );
+    }
+    const platform = resolveGoPlatform(this.config.targetPlatform);
+    const { documents, folded } = selectPlatformVariants(filtered.documents, platform);
+    if (folded > 0) {
+      options.logger?.debug(
+        { folded, goos: platform.goos, goarch: platform.goarch },
+        `Folded ${folded} platform variant(s) into the ${platform.goos}/${platform.goarch} ones`
+      );
+    }
+    return { ...result, documents };
+  }
+
+  /**
+   * Add the platform variants of changed Go files to the files to re-index
+   *
+   * Variants are folded together at scan time, so a change to one can change
+   * which variant is indexed or what it records about the others. Every
+   * constrained Go file in the directory of a changed Go file is rescanned.
+   */
+  private withPlatformVariants(files: { changed: string[]; added: string[]; deleted: string[] }): {
+    changed: string[];
+    added: string[];
+    deleted: string[];
+  } {
+    const touched = new Set([...files.changed, ...files.added, ...files.deleted]);
+    const dirs = new Set(
+      [...touched].filter((file) => file.endsWith('.go')).map((file) => path.posix.dirname(file))
+    );
+    if (dirs.size === 0) return files;
+
+    const variants = Object.values(this.state?.files ?? {})
+      .filter((metadata) => metadata.buildConstraint && !touched.has(metadata.path))
+      .filter((metadata) => dirs.has(path.posix.dirname(metadata.path)))
+      .map((metadata) => metadata.path);
+    return variants.length > 0 ? { ...files, changed: [...files.changed, ...variants] } : files;
+  }
+
+  /**
+   * Add the rest of each changed Go package to the files to re-index, when Go
+   * diagnostics are enabled
+   *
+   * An edit can fix or break code elsewhere in its package (an unused
+   * variable, a type error in a caller), so every tracked Go file in the
+   * directory of a changed Go file is rescanned with fresh diagnostics.
+   */
+  private withGoPackages(files: { changed: string[]; added: string[]; deleted: string[] }): {
+    changed: string[];
+    added: string[];
+    deleted: string[];
+  } {
+    if (!this.config.goDiagnostics) return files;
+    const touched = new Set([...files.changed, ...files.added, ...files.deleted]);
+    const dirs = new Set(
+      [...touched].filter((file) => file.endsWith('.go')).map((file) => path.posix.dirname(file))
+    );
+    if (dirs.size === 0) return files;
+
+    const mates = Object.keys(this.state?.files ?? {})
+      .filter((file) => file.endsWith('.go') && !touched.has(file))
+      .filter((file) => dirs.has(path.posix.dirname(file)));
+    return mates.length > 0 ? { ...files, changed: [...files.changed, ...mates] } : files;
+  }
+
+  /**
+   * Combine configured and per-call exclusions
+   *
+   * Returns undefined when none are set so the scanner keeps its defaults.
+   */
+  private resolveExcludes(extra: string[] = []): string[] | undefined {
+    const excludes = [...this.config.excludePatterns, ...extra];
+    return excludes.length > 0 ? excludes : undefined;
+  }
+
+  /**
+   * Classify specific files as changed, added, or deleted
+   *
+   * Decisions are based on the file's current content, not on how it was
+   * modified, so a temp-file-plus-rename save resolves to a single change.
+   */
+  private async classifyFiles(files: string[]): Promise<{
+    changed: string[];
+    added: string[];
+    deleted: string[];
+  }> {
+    const changed: string[] = [];
+    const added: string[] = [];
+    const deleted: string[] = [];
+
+    for (const filePath of new Set(files)) {
+      const metadata = this.state?.files[filePath];
+      let content: string;
+
+      try {
+        content = await fs.readFile(path.join(this.config.repositoryPath, filePath), 'utf-8');
+      } catch {
+        // Missing now: only tracked files need removal
+        if (metadata) {
+          deleted.push(filePath);
+        }
+        continue;
+      }
+
+      if (!metadata) {
+        added.push(filePath);
+        continue;
+      }
+
+      const currentHash = crypto.createHash('sha256').update(content).digest('hex');
+      if (currentHash !== metadata.hash || this.profileChanged(metadata)) {
+        changed.push(filePath);
+      }
+    }
+
+    return { changed, added, deleted };
+  }
+
   /**
    * Get optimal concurrency level based on system resources and environment variables
    */
@@ -1047,4 +1741,54 @@ export class RepositoryIndexer {
    */
 }
 
+function errorMessage(error: unknown): string {
+  return error instanceof Error ? error.message : String(error);
+}
+
+function toFailedDocument(doc: EmbeddingDocument, error: unknown): FailedDocument {
+  return { id: doc.id, file: String(doc.metadata.path ?? ''), error: errorMessage(error) };
+}
+
+/**
+ * Map files that failed to parse to their error message
+ *
+ * Scanner-level failures (reported as `[language]`) and files rejected
+ * before parsing (too large, binary) are not parse failures.
+ */
+function collectParseFailures(errors: ScanError[]): Map<string, string> {
+  const failures = new Map<string, string>();
+  for (const error of errors) {
+    const rejected = error.phase === 'fileValidation' || error.phase === 'content';
+    if (rejected || error.file.startsWith('[')) {
+      continue;
+    }
+    if (!failures.has(error.file)) {
+      failures.set(error.file, error.error);
+    }
+  }
+  return failures;
+}
+
+function toParseErrors(parseFailures: Map<string, string>): IndexError[] {
+  return Array.from(parseFailures, ([file, message]) => ({
+    type: 'scanner' as const,
+    file,
+    message,
+    timestamp: new Date(),
+  }));
+}
+
+function textBytes(documents: EmbeddingDocument[]): number {
+  return documents.reduce((sum, doc) => sum + Buffer.byteLength(doc.text, 'utf-8'), 0);
+}
+
+/**
+ * Drop documents from files that had any failed document
+ */
+function withoutFailedFiles(documents: Document[], failed: FailedDocument[]): Document[] {
+  if (failed.length === 0) return documents;
+  const failedFiles = new Set(failed.map((doc) => doc.file));
+  return documents.filter((doc) => !failedFiles.has(doc.metadata.file));
+}
+
 export * from './types';
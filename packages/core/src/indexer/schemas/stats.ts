@@ -169,6 +169,21 @@ export const FileMetadataSchema = z.object({
 
   /** Language detected */
   language: z.string().min(1),
+
+  /** Parse error from the last scan, if the file had syntax errors */
+  parseError: z.string().optional(),
+
+  /** Go file that imports "C" (cgo) */
+  usesCgo: z.boolean().optional(),
+
+  /** Go build constraint of the file */
+  buildConstraint: z.string().optional(),
+
+  /** Embedding profile its documents were embedded with */
+  embeddingProfile: z.string().optional(),
+
+  /** Keys of its symbols' doc comments */
+  docComments: z.array(z.string()).optional(),
 });
 
 /**
@@ -184,6 +199,9 @@ export const IndexerStateSchema = z.object({
   /** Embedding dimension */
   embeddingDimension: z.number().int().positive(),
 
+  /** What embedding text was built from */
+  embeddingText: z.enum(['source', 'card']).optional(),
+
   /** Repository path */
   repositoryPath: z.string().min(1),
 
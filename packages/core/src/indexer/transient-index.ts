@@ -0,0 +1,198 @@
+/**
+ * Transient Index
+ *
+ * Scans one file or directory on demand without touching the persisted
+ * index. Documents stay in memory, so structural queries (the `build*`
+ * functions in context/) run without an embedding model; semantic search
+ * embeds into a temporary vector store on first use, removed by dispose().
+ */
+
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { createDefaultRegistry } from '../scanner';
+import type { Document, ScanOptions, ScanResult, ScanStats } from '../scanner/types';
+import { annotateIdentifiers } from '../search/identifiers';
+import { VectorStorage } from '../vector';
+import type { SearchOptions, SearchResult, SearchResultMetadata } from '../vector/types';
+import { prepareDocumentsForEmbedding } from './utils/documents';
+
+/**
+ * Options for scanning part of a repository
+ */
+export interface ScanPathOptions {
+  /** Repository root; document paths are relative to it */
+  repositoryPath: string;
+  /** File or directory to scan, relative to the repository root or absolute within it */
+  path: string;
+  /** Index-only ignore patterns, layered as for the persisted index */
+  ignore?: string[];
+  /** Larger documents are chunked or summarized (default: 64 KiB) */
+  maxDocumentBytes?: number;
+  logger?: ScanOptions['logger'];
+  signal?: AbortSignal;
+}
+
+/**
+ * Options for a transient index
+ */
+export interface TransientIndexOptions extends ScanPathOptions {
+  /** Embedding model for search (default: the vector store's default) */
+  embeddingModel?: string;
+  /** Embedding dimension of the model */
+  dimension?: number;
+}
+
+/**
+ * Scan a single file or directory of a repository
+ *
+ * A file is scanned even if .gitignore excludes it, since it was asked for
+ * by name; a directory is scanned like the repository, limited to it.
+ *
+ * @throws If the path is outside the repository or does not exist
+ */
+export async function scanPath(options: ScanPathOptions): Promise<ScanResult> {
+  const repoRoot = path.resolve(options.repositoryPath);
+  const relative = relativeScanPath(options);
+  const isFile = (await fs.stat(path.join(repoRoot, relative))).isFile();
+  const registry = createDefaultRegistry();
+  let include: string[] | undefined;
+  if (isFile) {
+    include = [relative];
+  } else if (relative) {
+    include = Array.from(registry.getSupportedExtensions(), (ext) => `${relative}/**/*${ext}`);
+  }
+
+  return registry.scanRepository({
+    repoRoot,
+    include,
+    ignore: options.ignore,
+    respectGitignore: !isFile,
+    maxDocumentBytes: options.maxDocumentBytes,
+    logger: options.logger,
+    signal: options.signal,
+  });
+}
+
+/**
+ * Scanned path relative to the repository root, with forward slashes
+ *
+ * @throws If the path is outside the repository
+ */
+function relativeScanPath(options: ScanPathOptions): string {
+  const repoRoot = path.resolve(options.repositoryPath);
+  const relative = path
+    .relative(repoRoot, path.resolve(repoRoot, options.path))
+    .split(path.sep)
+    .join('/');
+  if (relative === '..' || relative.startsWith('../') || path.isAbsolute(relative)) {
+    throw new Error(`${options.path} is outside the repository ${repoRoot}`);
+  }
+  return relative;
+}
+
+/**
+ * Transient Index
+ * The documents of one file or directory, queryable without persisting them
+ *
+ * @example
+ * ```typescript
+ * const index = await TransientIndex.create({ repositoryPath, path: 'internal/store' });
+ * try {
+ *   const outline = buildPackageOutline(await index.getAll(), 'internal/store');
+ * } finally {
+ *   await index.dispose();
+ * }
+ * ```
+ */
+export class TransientIndex {
+  private results: SearchResult[];
+  private storage?: Promise<{ vectors: VectorStorage; dir: string }>;
+  private disposed = false;
+
+  private constructor(
+    /** Scanned path, relative to the repository root ("" for the root) */
+    readonly path: string,
+    /** Scanned documents, with scanner metadata */
+    readonly documents: Document[],
+    readonly stats: ScanStats,
+    private readonly options: TransientIndexOptions
+  ) {
+    this.results = prepareDocumentsForEmbedding(documents).map((doc) => ({
+      id: doc.id,
+      score: 1,
+      metadata: doc.metadata as SearchResultMetadata,
+    }));
+  }
+
+  /**
+   * Scan a file or directory into a new transient index
+   *
+   * @throws If the path is outside the repository or does not exist
+   */
+  static async create(options: TransientIndexOptions): Promise<TransientIndex> {
+    const result = await scanPath(options);
+    return new TransientIndex(relativeScanPath(options), result.documents, result.stats, options);
+  }
+
+  /**
+   * Documents as search results with index metadata, as RepositoryIndexer.getAll
+   * returns them; no embeddings are needed
+   */
+  async getAll(options: { limit?: number } = {}): Promise<SearchResult[]> {
+    this.assertUsable();
+    return this.results.slice(0, options.limit ?? this.results.length);
+  }
+
+  /**
+   * Semantic search over the scanned documents
+   *
+   * The first search loads the embedding model and embeds every document
+   * into a temporary vector store.
+   */
+  async search(query: string, options?: SearchOptions): Promise<SearchResult[]> {
+    this.assertUsable();
+    this.storage ??= this.embed();
+    const { vectors } = await this.storage;
+    return vectors.search(annotateIdentifiers(query), options);
+  }
+
+  /**
+   * Remove the temporary vector store, if search created one
+   *
+   * The index can't be queried afterwards; disposing twice is harmless.
+   */
+  async dispose(): Promise<void> {
+    if (this.disposed) return;
+    this.disposed = true;
+    const storage = await this.storage?.catch(() => undefined);
+    if (!storage) return;
+    await storage.vectors.close();
+    await fs.rm(storage.dir, { recursive: true, force: true });
+  }
+
+  private async embed(): Promise<{ vectors: VectorStorage; dir: string }> {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'dev-agent-transient-'));
+    const vectors = new VectorStorage({
+      storePath: path.join(dir, 'vectors.lance'),
+      embeddingModel: this.options.embeddingModel,
+      dimension: this.options.dimension,
+    });
+    try {
+      await vectors.initialize();
+      await vectors.addDocuments(prepareDocumentsForEmbedding(this.documents));
+    } catch (error) {
+      await vectors.close().catch(() => undefined);
+      await fs.rm(dir, { recursive: true, force: true });
+      this.storage = undefined;
+      throw error;
+    }
+    return { vectors, dir };
+  }
+
+  private assertUsable(): void {
+    if (this.disposed) {
+      throw new Error('Transient index has been disposed');
+    }
+  }
+}
@@ -3,6 +3,98 @@
  */
 
 import type { Logger } from '@lytics/kero';
+import type {
+  Document,
+  DocumentMetadata,
+  DocumentType,
+  GoPlatform,
+  ScanStats,
+} from '../scanner/types';
+import type {
+  EmbeddingCacheStats,
+  QuantizationConfig,
+  SimilarityMetric,
+} from '../vector/types';
+
+/**
+ * What each symbol's embedding text is built from
+ * - `source`: kind and name, doc comment, signature, and body
+ * - `card`: kind and name, doc comment, signature, and a few metadata lines instead of the
+ *   body: owning type, package and its doc, routes, calls, fields, errors (see formatSymbolCard)
+ */
+export type EmbeddingTextMode = 'source' | 'card';
+
+/**
+ * Named embedding settings for part of a repository (see utils/profiles.ts)
+ *
+ * Unset fields fall back to the indexer's own settings.
+ */
+export interface EmbeddingProfile {
+  /** What each symbol's embedding text is built from */
+  embeddingText?: EmbeddingTextMode;
+  /** Token budget per document's embedding text; bodies are trimmed to fit */
+  embeddingMaxTokens?: number;
+  /** Largest document in bytes; larger documentation is chunked and code summarized */
+  maxDocumentBytes?: number;
+}
+
+/**
+ * Paths an embedding profile applies to
+ */
+export interface EmbeddingProfileBinding {
+  /** Globs relative to the repository root (e.g. `docs/**`, `api/*.proto`) */
+  paths: string[];
+  /** Name of a profile in `embeddingProfiles` */
+  profile: string;
+}
+
+/**
+ * Which symbols of a language to index (see utils/symbol-filter.ts)
+ *
+ * Everything is indexed by default; each field only removes symbols.
+ */
+export interface SymbolFilterRule {
+  /** Only index these kinds (default: every kind) */
+  includeKinds?: DocumentType[];
+  /** Never index these kinds (default: none) */
+  excludeKinds?: DocumentType[];
+  /**
+   * Skip unexported symbols: `true` for every kind, or only the listed kinds,
+   * e.g. `['function', 'method']` to keep unexported types (default: false)
+   */
+  skipUnexported?: boolean | DocumentType[];
+  /** Skip symbols in test files (default: false) */
+  skipTests?: boolean;
+}
+
+/**
+ * Symbol filter rules by language (`go`, `typescript`, ...); a `*` rule
+ * applies to languages without their own
+ */
+export type SymbolFilters = Partial<Record<string, SymbolFilterRule>>;
+
+/**
+ * Custom fields an enricher attaches to a document; scalars, so search
+ * filters can match them exactly
+ */
+export type EnrichmentFields = Record<string, string | number | boolean>;
+
+/**
+ * Post-scan hook adding custom metadata to a document before it is embedded
+ * (see utils/enrichment.ts)
+ *
+ * Returns the fields to add, or nothing to leave the document as scanned.
+ *
+ * @example
+ * ```typescript
+ * const team: DocumentEnricher = (metadata) =>
+ *   metadata.file.startsWith('services/billing/') ? { team: 'payments' } : undefined;
+ * ```
+ */
+export type DocumentEnricher = (
+  metadata: DocumentMetadata,
+  document: Document
+) => EnrichmentFields | undefined | Promise<EnrichmentFields | undefined>;
 
 /**
  * Options for indexing a repository
@@ -25,6 +117,12 @@ export interface IndexOptions {
 
   /** Logger for progress and debug output */
   logger?: Logger;
+
+  /**
+   * Cancels indexing; the call rejects with the signal's reason. Nothing is
+   * written to the state file, so the previously persisted index stays valid.
+   */
+  signal?: AbortSignal;
 }
 
 /**
@@ -33,6 +131,13 @@ export interface IndexOptions {
 export interface UpdateOptions extends IndexOptions {
   /** Only reindex files modified after this timestamp */
   since?: Date;
+
+  /**
+   * Only consider these repository-relative paths (e.g. from a file watcher).
+   * Each path is classified as changed, added, or deleted from its current
+   * state on disk, so the full repository is not rescanned.
+   */
+  files?: string[];
 }
 
 /**
@@ -57,8 +162,11 @@ export interface IndexProgress {
   /** Current file being processed */
   currentFile?: string;
 
-  /** Percentage complete (0-100) */
+  /** Percentage complete over the whole run (0-100): scanning is the first third */
   percentComplete: number;
+
+  /** Seconds left in the current phase at its recent rate (scanning and storing) */
+  etaSeconds?: number;
 }
 
 /**
@@ -117,6 +225,53 @@ export interface IndexStats {
 
   /** Metadata about stats freshness and source */
   statsMetadata?: StatsMetadata;
+
+  /**
+   * Documents that could not be embedded after retries.
+   * Their files are left out of the indexer state, so the next update retries them.
+   */
+  failedDocuments?: FailedDocument[];
+
+  /** Time spent in each indexing phase */
+  timings?: IndexPhaseTimings;
+
+  /** Bytes of document text sent to the embedder */
+  bytesEmbedded?: number;
+
+  /** Embedding cache hits and misses during this run (absent when the cache is disabled) */
+  embeddingCache?: EmbeddingCacheStats;
+
+  /** Scanner statistics, including documents by kind and the per-language breakdown */
+  scanStats?: ScanStats;
+}
+
+/**
+ * Time spent in each indexing phase (milliseconds)
+ *
+ * Batches are embedded concurrently, so `embed` and `store` are summed
+ * across batches and can exceed the wall-clock duration.
+ */
+export interface IndexPhaseTimings {
+  /** File discovery and parsing */
+  scan: number;
+  /** Embedding generation */
+  embed: number;
+  /** Vector store writes */
+  store: number;
+}
+
+/**
+ * A document that could not be embedded or stored
+ */
+export interface FailedDocument {
+  /** Document ID */
+  id: string;
+
+  /** File the document came from */
+  file: string;
+
+  /** Last error message */
+  error: string;
 }
 
 /**
@@ -205,6 +360,26 @@ export interface DetailedIndexStats extends IndexStats {
   byPackage?: Record<string, PackageStats>;
 }
 
+/**
+ * How far the stored index has drifted from the repository on disk
+ */
+export interface IndexFreshness {
+  /** Files the index tracks */
+  trackedFiles: number;
+  /** Tracked files whose content hash no longer matches */
+  changed: string[];
+  /** Indexable files the index doesn't track yet */
+  added: string[];
+  /** Tracked files that no longer exist */
+  deleted: string[];
+  /** changed + added + deleted */
+  staleFiles: number;
+  /** Stale files as a percentage of tracked plus added files (0-100, one decimal) */
+  stalePercent: number;
+  /** When the check ran (ISO) */
+  checkedAt: string;
+}
+
 /**
  * Metadata tracked for each indexed file
  */
@@ -229,6 +404,24 @@ export interface FileMetadata {
 
   /** Language detected */
   language: string;
+
+  /**
+   * Parse error from the last scan, if the file had syntax errors.
+   * Cleared by the next successful parse.
+   */
+  parseError?: string;
+
+  /** Go file that imports "C" (cgo) */
+  usesCgo?: boolean;
+
+  /** Go build constraint (`//go:build` and name suffix); its package's variants rescan with it */
+  buildConstraint?: string;
+
+  /** Embedding profile its documents were embedded with, as `name@fingerprint` */
+  embeddingProfile?: string;
+
+  /** Keys of its symbols' doc comments, counted across files to find boilerplate */
+  docComments?: string[];
 }
 
 /**
@@ -244,6 +437,9 @@ export interface IndexerState {
   /** Embedding dimension */
   embeddingDimension: number;
 
+  /** What the default profile's embedding text was built from (absent in older indexes: source) */
+  embeddingText?: EmbeddingTextMode;
+
   /** Repository path */
   repositoryPath: string;
 
@@ -270,6 +466,20 @@ export interface IndexerState {
   };
 }
 
+/**
+ * Retry policy for embedding batches
+ */
+export interface EmbeddingRetryOptions {
+  /** Retries per batch before falling back to one document at a time (default: 5) */
+  maxRetries?: number;
+
+  /** Delay before the first retry in milliseconds (default: 500) */
+  initialDelay?: number;
+
+  /** Maximum delay between retries in milliseconds (default: 30000) */
+  maxDelay?: number;
+}
+
 /**
  * Configuration for the Repository Indexer
  */
@@ -292,12 +502,122 @@ export interface IndexerConfig {
   /** Batch size for embedding generation (default: 32) */
   batchSize?: number;
 
-  /** Glob patterns to exclude */
+  /**
+   * Token budget per document's embedding text (default: 256, the model's sequence length).
+   * Doc comments and signatures are kept; bodies are trimmed to fit.
+   */
+  embeddingMaxTokens?: number;
+
+  /**
+   * What embedding text is built from (default: source). Changing it re-embeds the files
+   * using it on the next update.
+   */
+  embeddingText?: EmbeddingTextMode;
+
+  /**
+   * Maximum size of one document in bytes (default: 64 KiB). Larger documentation sections
+   * are chunked and larger code symbols summarized, with a warning logged for each.
+   */
+  maxDocumentBytes?: number;
+
+  /**
+   * Named embedding settings that paths can be bound to (default: none). The built-in
+   * `default` profile is the embedding settings above.
+   */
+  embeddingProfiles?: Record<string, EmbeddingProfile>;
+
+  /** Profile for files no binding matches (default: `default`) */
+  embeddingProfile?: string;
+
+  /**
+   * Profiles by path, first match wins (default: none). Each document records the profile it
+   * was embedded with, and an update re-embeds the files whose profile changed.
+   */
+  embeddingProfileBindings?: EmbeddingProfileBinding[];
+
+  /** Retry policy for failed embedding batches (rate limits, 5xx, network errors) */
+  embeddingRetry?: EmbeddingRetryOptions;
+
+  /**
+   * Maximum embedding requests in flight at once, separate from file-parse concurrency
+   * (default: DEV_AGENT_EMBEDDING_CONCURRENCY, else 4). Requests beyond the cap queue.
+   */
+  maxConcurrentEmbeddings?: number;
+
+  /**
+   * Reuse vectors for unchanged embedding text across runs, even full rebuilds (default: true).
+   * Cached next to the vector store, per embedding model.
+   */
+  embeddingCache?: boolean;
+
+  /**
+   * Similarity metric for a new index (default: the existing index's metric, or 'cosine').
+   * Stored with the index; a different metric is rejected until a forced re-index.
+   */
+  similarityMetric?: SimilarityMetric;
+
+  /**
+   * Vector quantization for a new index (default: the existing index's, or none).
+   * int8 cuts vector memory about 4x for a small recall cost; recorded like the metric.
+   */
+  quantization?: QuantizationConfig;
+
+  /**
+   * Keep only the first K dimensions of each vector, for Matryoshka-trained models
+   * (default: the existing index's, or all). Recorded like the metric.
+   */
+  truncateDimensions?: number;
+
+  /**
+   * Record each symbol's last commit date and author from git blame (default: false).
+   * Each file is blamed once per (re-)index; incremental updates only blame changed files.
+   */
+  blame?: boolean;
+
+  /**
+   * Run `go build` and `go vet` and attach their findings to the symbols they fall in
+   * (default: false). Needs a buildable checkout. Results are cached per package; updates
+   * re-run and re-index only the packages of changed Go files.
+   */
+  goDiagnostics?: boolean;
+
+  /**
+   * Symbols that must share a doc comment before it counts as boilerplate and is left out
+   * of their embedding text (default: 10; 0 disables). Comments are compared ignoring the
+   * symbol's own name, numbers, whitespace, and case. A change takes effect as files
+   * re-index; a forced index applies it everywhere.
+   */
+  boilerplateDocThreshold?: number;
+
+  /** Glob patterns to exclude (replaces the scanner's default exclusions) */
   excludePatterns?: string[];
 
+  /** Index-only ignore patterns layered on top of .gitignore and .devagentignore */
+  ignorePatterns?: string[];
+
   /** Logger for warnings and errors */
   logger?: Logger;
 
   /** Languages to index (default: all supported) */
   languages?: string[];
+
+  /**
+   * Symbols to leave out of the index, by language (default: none; everything is indexed).
+   * Changing them takes effect for files as they're re-indexed; use a forced re-index.
+   */
+  symbolFilters?: SymbolFilters;
+
+  /**
+   * Post-scan hooks adding custom metadata to every document (default: none). A string is a
+   * module path, relative to the repository, exporting the enricher as default. Changing them
+   * takes effect for files as they're re-indexed; use a forced re-index.
+   */
+  enrichers?: Array<DocumentEnricher | string>;
+
+  /**
+   * Platform that picks which variant of a Go symbol defined per platform (`work_linux.go`,
+   * `work_windows.go`) is indexed; the others are recorded on it. Missing values come from
+   * GOOS and GOARCH, then the host. Changing it takes effect on a forced re-index.
+   */
+  targetPlatform?: Partial<GoPlatform>;
 }
@@ -0,0 +1,57 @@
+import { describe, expect, it } from 'vitest';
+import type { Document } from '../../../scanner/types';
+import { docCommentKey, docCommentKeys, markBoilerplateDocs } from '../doc-boilerplate';
+
+function doc(name: string, docstring?: string): Document {
+  return {
+    id: `gen/api.pb.go:${name}`,
+    type: 'method',
+    language: 'go',
+    text: '',
+    metadata: { file: 'gen/api.pb.go', name, startLine: 1, endLine: 3, docstring },
+  };
+}
+
+describe('docCommentKey', () => {
+  it('should ignore the symbol name, numbers, whitespace, and case', () => {
+    const user = 'Deprecated: Use User.ProtoReflect.Descriptor instead.';
+    const order = 'Deprecated:  use Order.ProtoReflect.Descriptor\ninstead.';
+    expect(docCommentKey(user, 'User.Reset')).toBe(docCommentKey(order, 'Order.Reset'));
+    expect(docCommentKey('Copyright 2021 Acme')).toBe(docCommentKey('Copyright 2024 Acme'));
+  });
+
+  it('should keep comments that say different things apart', () => {
+    expect(docCommentKey('Reset clears the cache.', 'Reset')).not.toBe(
+      docCommentKey('Reset closes the connection.', 'Reset')
+    );
+  });
+});
+
+describe('markBoilerplateDocs', () => {
+  const repeated = (count: number) =>
+    Array.from({ length: count }, (_, i) => doc(`Msg${i}.String`, `Msg${i} is a message.`));
+
+  it('should mark comments found on at least the threshold number of symbols', () => {
+    const docs = [...repeated(3), doc('Parse', 'Parse reads a config file.')];
+
+    expect(markBoilerplateDocs(docs, [], 3)).toBe(3);
+    expect(docs.map((d) => d.metadata.boilerplateDoc)).toEqual([true, true, true, undefined]);
+    expect(docs[0].metadata.docstring).toBe('Msg0 is a message.');
+  });
+
+  it('should count keys from elsewhere in the index', () => {
+    const docs = repeated(1);
+    const others = docCommentKeys(repeated(2));
+
+    expect(markBoilerplateDocs(docs, others, 4)).toBe(0);
+    expect(markBoilerplateDocs(docs, others, 3)).toBe(1);
+  });
+
+  it('should skip undocumented symbols and mark nothing when disabled', () => {
+    const docs = [...repeated(5), doc('Init'), doc('Init2')];
+
+    expect(docCommentKeys(docs)).toHaveLength(5);
+    expect(markBoilerplateDocs(docs, [], 0)).toBe(0);
+    expect(docs.some((d) => d.metadata.boilerplateDoc)).toBe(false);
+  });
+});
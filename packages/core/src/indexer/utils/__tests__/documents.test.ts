@@ -122,6 +122,52 @@ describe('Document Preparation Utilities', () => {
       expect(result[1].id).toBe('doc2');
       expect(result[2].id).toBe('doc3');
     });
+
+    it('should embed trimmed bodies within a token budget', () => {
+      const doc: Document = {
+        ...mockDocuments[0],
+        metadata: {
+          ...mockDocuments[0].metadata,
+          snippet: `calculateTotal(items: Item[]): number {\n${'  total += item.price;\n'.repeat(200)}}`,
+        },
+      };
+
+      const [result] = prepareDocumentsForEmbedding([doc], { maxTokens: 64 });
+
+      expect(result.text).toContain('Calculate total price from items');
+      expect(result.text).toContain('calculateTotal(items: Item[]): number');
+      expect(result.text).toContain('total += item.price;');
+      expect(result.text.endsWith('...')).toBe(true);
+      expect(result.text.length).toBeLessThanOrEqual(64 * 4);
+    });
+
+    it('should embed symbol cards instead of source in card mode', () => {
+      const [card, unsigned] = prepareDocumentsForEmbedding(
+        mockDocuments.slice(0, 2),
+        undefined,
+        'card'
+      );
+
+      expect(card.text).toContain('calculateTotal(items: Item[]): number');
+      expect(card.text).toContain('package /src');
+      expect(card.text).not.toContain('items.reduce');
+      // No signature, so no card
+      expect(unsigned.text).toContain('class User { constructor');
+    });
+
+    it('should leave boilerplate doc comments out of the text but keep them in metadata', () => {
+      const doc: Document = {
+        ...mockDocuments[0],
+        metadata: { ...mockDocuments[0].metadata, boilerplateDoc: true },
+      };
+
+      const [result] = prepareDocumentsForEmbedding([doc], { maxTokens: 256 });
+
+      expect(result.text).not.toContain('Calculate total price from items');
+      expect(result.text).toContain('calculateTotal(items: Item[]): number');
+      expect(result.metadata.docstring).toBe('Calculate total price from items');
+      expect(result.metadata.boilerplateDoc).toBe(true);
+    });
   });
 
   describe('prepareDocumentForEmbedding', () => {
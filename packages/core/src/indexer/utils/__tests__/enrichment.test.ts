@@ -0,0 +1,113 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { describe, expect, it, vi } from 'vitest';
+import type { Document } from '../../../scanner/types';
+import type { DocumentEnricher } from '../../types';
+import { prepareDocumentsForEmbedding } from '../documents';
+import { applyEnrichers, loadEnrichers } from '../enrichment';
+
+function doc(file: string, name: string): Document {
+  return {
+    id: `${file}:${name}:1`,
+    text: name,
+    type: 'function',
+    language: 'go',
+    metadata: { file, name, startLine: 1, endLine: 5, exported: true },
+  };
+}
+
+const team: DocumentEnricher = (metadata) =>
+  metadata.file.startsWith('billing/') ? { team: 'payments' } : undefined;
+
+describe('applyEnrichers', () => {
+  it('should merge returned fields into each document', async () => {
+    const docs = [doc('billing/charge.go', 'Charge'), doc('auth/login.go', 'Login')];
+    const service: DocumentEnricher = async (metadata) => ({
+      service: metadata.file.split('/')[0],
+    });
+
+    const enriched = await applyEnrichers(docs, [team, service]);
+
+    expect(enriched).toBe(2);
+    expect(docs[0].metadata.enrichment).toEqual({ team: 'payments', service: 'billing' });
+    expect(docs[1].metadata.enrichment).toEqual({ service: 'auth' });
+  });
+
+  it('should let later enrichers override earlier fields and drop non-scalar values', async () => {
+    const docs = [doc('billing/charge.go', 'Charge')];
+    const override = (() => ({
+      team: 'billing',
+      owners: ['@ada'],
+      score: Number.NaN,
+    })) as unknown as DocumentEnricher;
+
+    await applyEnrichers(docs, [team, override]);
+
+    expect(docs[0].metadata.enrichment).toEqual({ team: 'billing' });
+  });
+
+  it('should keep indexing when an enricher throws, with one warning', async () => {
+    const docs = [doc('billing/charge.go', 'Charge'), doc('auth/login.go', 'Login')];
+    const logger = { warn: vi.fn() };
+    const broken: DocumentEnricher = () => {
+      throw new Error('CODEOWNERS missing');
+    };
+
+    const enriched = await applyEnrichers(docs, [broken, team], logger as never);
+
+    expect(enriched).toBe(1);
+    expect(docs[0].metadata.enrichment).toEqual({ team: 'payments' });
+    expect(logger.warn).toHaveBeenCalledTimes(1);
+    expect(logger.warn.mock.calls[0][0]).toMatchObject({
+      enricher: 'broken',
+      failures: 2,
+      error: 'CODEOWNERS missing',
+    });
+  });
+});
+
+describe('loadEnrichers', () => {
+  it('should import module paths relative to the repository', async () => {
+    const root = await fs.mkdtemp(path.join(os.tmpdir(), 'enrichers-'));
+    await fs.writeFile(
+      path.join(root, 'owners.mjs'),
+      "export default (metadata) => ({ team: metadata.file.split('/')[0] });\n"
+    );
+
+    try {
+      const [fromModule, inline] = await loadEnrichers(['owners.mjs', team], root);
+
+      expect(await fromModule(doc('search/rank.go', 'Rank').metadata, doc('x', 'y'))).toEqual({
+        team: 'search',
+      });
+      expect(inline).toBe(team);
+    } finally {
+      await fs.rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it('should reject a module without an enricher function', async () => {
+    const root = await fs.mkdtemp(path.join(os.tmpdir(), 'enrichers-'));
+    await fs.writeFile(path.join(root, 'empty.mjs'), 'export const name = "none";\n');
+
+    try {
+      await expect(loadEnrichers(['empty.mjs'], root)).rejects.toThrow(
+        'Enricher module empty.mjs must export a function'
+      );
+    } finally {
+      await fs.rm(root, { recursive: true, force: true });
+    }
+  });
+});
+
+describe('enrichment in embedding metadata', () => {
+  it('should store fields at the top level without replacing built-in ones', () => {
+    const enriched = doc('billing/charge.go', 'Charge');
+    enriched.metadata.enrichment = { team: 'payments', path: 'elsewhere' };
+
+    const [prepared] = prepareDocumentsForEmbedding([enriched]);
+
+    expect(prepared.metadata).toMatchObject({ team: 'payments', path: 'billing/charge.go' });
+  });
+});
@@ -8,6 +8,7 @@ import {
   cleanDocumentText,
   formatDocumentText,
   formatDocumentTextWithSignature,
+  formatSymbolCard,
   truncateText,
 } from '../formatting';
 
@@ -30,7 +31,8 @@ describe('Formatting Utilities', () => {
 
       const result = formatDocumentText(doc);
       expect(result).toBe(
-        'function: calculateTotal\n\nfunction calculateTotal(items) { return items.reduce(...); }'
+        'function: calculateTotal (calculate total)\n\n' +
+          'function calculateTotal(items) { return items.reduce(...); }'
       );
     });
 
@@ -175,6 +177,94 @@ describe('Formatting Utilities', () => {
     });
   });
 
+  describe('formatSymbolCard', () => {
+    const method: Document = {
+      id: 'users/handlers.go:Server.CreateUser:20',
+      type: 'method',
+      language: 'go',
+      text: 'method Server.CreateUser',
+      metadata: {
+        file: 'users/handlers.go',
+        name: 'Server.CreateUser',
+        startLine: 20,
+        endLine: 40,
+        exported: true,
+        signature: 'func (s *Server) CreateUser(w http.ResponseWriter, r *http.Request)',
+        docstring: 'CreateUser stores a new account.',
+        snippet: 'func (s *Server) CreateUser(...) {\n\tif err := validate(r); err != nil {',
+        packageName: 'users',
+        packageDoc: 'Package users manages accounts.',
+        callees: [
+          { name: 'validate', line: 21 },
+          { name: 's.store.Insert', line: 24 },
+          { name: 'validate', line: 30 },
+        ],
+        errorsReturned: [{ kind: 'sentinel', error: 'ErrExists', wrapped: false }],
+      },
+    };
+
+    it('should describe a symbol by its metadata instead of its body', () => {
+      expect(formatSymbolCard(method)).toBe(
+        [
+          'method: Server.CreateUser (server create user)',
+          'CreateUser stores a new account.',
+          'func (s *Server) CreateUser(w http.ResponseWriter, r *http.Request)',
+          'method of Server',
+          'package users: Package users manages accounts.',
+          'calls: validate, s.store.Insert',
+          'returns errors: ErrExists',
+        ].join('\n')
+      );
+    });
+
+    it('should list struct fields and fall back to the directory as package', () => {
+      const struct: Document = {
+        id: 'internal/config/config.go:Config:1',
+        type: 'struct',
+        language: 'go',
+        text: 'struct Config',
+        metadata: {
+          file: 'internal/config/config.go',
+          name: 'Config',
+          startLine: 1,
+          endLine: 5,
+          exported: true,
+          signature: 'type Config struct',
+          custom: {
+            fields: [
+              { name: 'Timeout', type: 'time.Duration', exported: true, embedded: false },
+              { name: 'Logger', type: '*Logger', exported: true, embedded: true },
+            ],
+          },
+        },
+      };
+
+      const card = formatSymbolCard(struct);
+      expect(card).toContain('package internal/config');
+      expect(card).toContain('fields: Timeout time.Duration, *Logger');
+    });
+
+    it('should fit the card into a token budget', () => {
+      const card = formatSymbolCard(method, { maxTokens: 45 });
+
+      expect(card).toContain('method of Server');
+      expect(card).not.toContain('returns errors');
+      expect(card.endsWith('...')).toBe(true);
+    });
+
+    it('should format documents without a signature as source', () => {
+      const section: Document = {
+        id: 'README.md:Usage:1',
+        type: 'documentation',
+        language: 'markdown',
+        text: 'Run dev index.',
+        metadata: { file: 'README.md', name: 'Usage', startLine: 1, endLine: 3, exported: true },
+      };
+
+      expect(formatSymbolCard(section)).toBe(formatDocumentText(section));
+    });
+  });
+
   describe('truncateText', () => {
     it('should not truncate text shorter than maxLength', () => {
       const text = 'Short text';
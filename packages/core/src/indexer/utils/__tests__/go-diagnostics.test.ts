@@ -0,0 +1,137 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import type { Document } from '../../../scanner/types';
+import { annotateGoDiagnostics, type GoCommandRunner, parseGoDiagnostics } from '../go-diagnostics';
+
+function doc(file: string, name: string, startLine: number, endLine: number): Document {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    text: name,
+    type: 'function',
+    language: 'go',
+    metadata: { file, name, startLine, endLine, exported: true },
+  };
+}
+
+describe('parseGoDiagnostics', () => {
+  it('should parse positions relative to the working directory', () => {
+    const output = [
+      '# example.com/m/store',
+      'store/cache.go:12:2: declared and not used: x',
+      './store/cache.go:20: unreachable code',
+      'vet: store/get.go:3:9: undefined: missing',
+      'note: module requires Go 1.22',
+    ].join('\n');
+
+    expect(parseGoDiagnostics(output, '/repo/svc', '/repo', 'vet')).toEqual([
+      {
+        file: 'svc/store/cache.go',
+        tool: 'vet',
+        line: 12,
+        column: 2,
+        message: 'declared and not used: x',
+      },
+      { file: 'svc/store/cache.go', tool: 'vet', line: 20, message: 'unreachable code' },
+      { file: 'svc/store/get.go', tool: 'vet', line: 3, column: 9, message: 'undefined: missing' },
+    ]);
+  });
+
+  it('should join continuation lines and drop files outside the repository', () => {
+    const output = [
+      'cache.go:8:9: not enough return values',
+      '\thave ()',
+      '\twant (string, error)',
+      '/root/go/pkg/mod/example.com/dep@v1.0.0/dep.go:4:1: syntax error',
+    ].join('\n');
+
+    const diagnostics = parseGoDiagnostics(output, '/repo/store', '/repo', 'build');
+    expect(diagnostics).toHaveLength(1);
+    expect(diagnostics[0].message).toBe('not enough return values have () want (string, error)');
+  });
+});
+
+describe('annotateGoDiagnostics', () => {
+  let repoDir: string;
+  let cachePath: string;
+
+  beforeEach(async () => {
+    repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'go-diagnostics-'));
+    cachePath = path.join(repoDir, '.dev-agent', 'go-diagnostics.json');
+    await fs.mkdir(path.join(repoDir, 'store'));
+    await fs.mkdir(path.join(repoDir, 'api'));
+    await fs.writeFile(path.join(repoDir, 'go.mod'), 'module example.com/m\n\ngo 1.22\n');
+    await fs.writeFile(path.join(repoDir, 'store/cache.go'), 'package store\n');
+    await fs.writeFile(path.join(repoDir, 'api/handler.go'), 'package api\n');
+  });
+
+  afterEach(async () => {
+    await fs.rm(repoDir, { recursive: true, force: true });
+  });
+
+  const documents = () => [
+    doc('store/cache.go', 'Get', 10, 15),
+    doc('store/cache.go', 'Set', 17, 25),
+    doc('api/handler.go', 'Handle', 3, 9),
+  ];
+
+  function fakeGo(outputs: { build?: string; vet?: string }) {
+    return vi.fn<GoCommandRunner>(async (args) => {
+      const output = outputs[args[0] as 'build' | 'vet'] ?? '';
+      return { exitCode: output ? 1 : 0, output };
+    });
+  }
+
+  it('should attach findings to the symbols whose lines contain them', async () => {
+    const run = fakeGo({
+      build: '# example.com/m/api\napi/handler.go:5:2: declared and not used: id\n',
+      vet: '# example.com/m/store\nstore/cache.go:20:3: unreachable code\n',
+    });
+    const docs = documents();
+
+    const stats = await annotateGoDiagnostics(docs, { repositoryPath: repoDir, cachePath, run });
+
+    expect(stats).toEqual({ packagesRun: 2, packagesCached: 0, attached: 2 });
+    expect(docs[0].metadata.diagnostics).toBeUndefined();
+    expect(docs[1].metadata.diagnostics).toEqual([
+      { tool: 'vet', line: 20, column: 3, message: 'unreachable code' },
+    ]);
+    expect(docs[2].metadata.diagnostics?.[0]).toMatchObject({ tool: 'build', line: 5 });
+    // The package that failed to build isn't vetted
+    expect(run).toHaveBeenCalledWith(['build', '-o', os.devNull, './store', './api'], repoDir);
+    expect(run).toHaveBeenCalledWith(['vet', './store'], repoDir);
+  });
+
+  it('should re-run only packages whose files changed', async () => {
+    const vet = 'store/cache.go:20:3: unreachable code\n';
+    await annotateGoDiagnostics(documents(), {
+      repositoryPath: repoDir,
+      cachePath,
+      run: fakeGo({ vet }),
+    });
+
+    await fs.writeFile(path.join(repoDir, 'api/handler.go'), 'package api\n\n// Edited\n');
+    const run = fakeGo({});
+    const docs = documents();
+    const stats = await annotateGoDiagnostics(docs, { repositoryPath: repoDir, cachePath, run });
+
+    expect(stats).toMatchObject({ packagesRun: 1, packagesCached: 1 });
+    expect(run).toHaveBeenCalledWith(['build', '-o', os.devNull, './api'], repoDir);
+    // Cached results are still attached
+    expect(docs[1].metadata.diagnostics?.[0].message).toBe('unreachable code');
+  });
+
+  it('should skip the pass when Go is not installed', async () => {
+    const run = vi.fn<GoCommandRunner>().mockRejectedValue(
+      Object.assign(new Error('spawn go ENOENT'), { code: 'ENOENT' })
+    );
+    const docs = documents();
+
+    const stats = await annotateGoDiagnostics(docs, { repositoryPath: repoDir, cachePath, run });
+
+    expect(stats.packagesRun).toBe(0);
+    expect(docs.every((d) => d.metadata.diagnostics === undefined)).toBe(true);
+    await expect(fs.access(cachePath)).rejects.toThrow();
+  });
+});
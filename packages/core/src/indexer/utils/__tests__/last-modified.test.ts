@@ -0,0 +1,71 @@
+import { describe, expect, it, vi } from 'vitest';
+import type { GitBlame, GitBlameLine, GitExtractor } from '../../../git';
+import type { Document } from '../../../scanner/types';
+import { annotateLastModified, lastModifiedInRange } from '../last-modified';
+
+function line(lineNumber: number, date: string, author: string, hash = 'a'.repeat(40)) {
+  return {
+    lineNumber,
+    content: '',
+    commit: { hash, shortHash: hash.slice(0, 7), subject: '', author, date },
+  } satisfies GitBlameLine;
+}
+
+function doc(file: string, name: string, startLine: number, endLine: number): Document {
+  return {
+    id: `${file}:${name}:${startLine}`,
+    text: name,
+    type: 'function',
+    language: 'go',
+    metadata: { file, name, startLine, endLine, exported: true },
+  };
+}
+
+const blame: GitBlame = {
+  file: 'retry.go',
+  lines: [
+    line(1, '2024-01-01T00:00:00.000Z', 'Ada'),
+    line(2, '2024-03-01T00:00:00.000Z', 'Grace'),
+    line(3, '2024-02-01T00:00:00.000Z', 'Ada'),
+    line(4, '2025-01-01T00:00:00.000Z', 'Not Committed Yet', '0'.repeat(40)),
+    line(5, '2024-05-01T00:00:00.000Z', 'Linus'),
+  ],
+};
+
+describe('lastModifiedInRange', () => {
+  it('should pick the newest commit within the range', () => {
+    expect(lastModifiedInRange(blame, 1, 3)).toEqual({
+      lastModified: '2024-03-01T00:00:00.000Z',
+      lastAuthor: 'Grace',
+    });
+    expect(lastModifiedInRange(blame, 5, 5)?.lastAuthor).toBe('Linus');
+  });
+
+  it('should ignore uncommitted lines', () => {
+    expect(lastModifiedInRange(blame, 3, 4)?.lastAuthor).toBe('Ada');
+    expect(lastModifiedInRange(blame, 4, 4)).toBeUndefined();
+  });
+});
+
+describe('annotateLastModified', () => {
+  it('should blame each file once and annotate its symbols', async () => {
+    const getBlame = vi.fn(async (file: string) => {
+      if (file === 'untracked.go') throw new Error('no such path in HEAD');
+      return { ...blame, file };
+    });
+    const git = { getBlame } as unknown as GitExtractor;
+    const docs = [
+      doc('retry.go', 'Retry', 1, 3),
+      doc('retry.go', 'backoff', 5, 5),
+      doc('untracked.go', 'New', 1, 2),
+    ];
+
+    const blamed = await annotateLastModified(docs, git, 1);
+
+    expect(blamed).toBe(1);
+    expect(getBlame).toHaveBeenCalledTimes(2);
+    expect(docs[0].metadata.lastAuthor).toBe('Grace');
+    expect(docs[1].metadata.lastModified).toBe('2024-05-01T00:00:00.000Z');
+    expect(docs[2].metadata.lastModified).toBeUndefined();
+  });
+});
@@ -0,0 +1,100 @@
+import { describe, expect, it } from 'vitest';
+import type { Document } from '../../../scanner/types';
+import { foldedVariantFiles, selectPlatformVariants } from '../platform-variants';
+
+function doc(name: string, file: string, buildConstraint?: string, startLine = 3): Document {
+  return {
+    id: `${file}:function:${name}`,
+    text: name,
+    type: 'function',
+    language: 'go',
+    metadata: { file, name, startLine, endLine: startLine + 2, exported: false, buildConstraint },
+  };
+}
+
+const linux = { goos: 'linux', goarch: 'amd64' };
+
+describe('selectPlatformVariants', () => {
+  const documents = () => [
+    doc('doWork', 'worker/work_linux.go', 'linux'),
+    doc('pinCPU', 'worker/work_linux.go', 'linux', 9),
+    doc('doWork', 'worker/work_windows.go', 'windows', 4),
+    doc('doWork', 'worker/work_bsd.go', 'darwin || freebsd', 7),
+    doc('Run', 'worker/worker.go'),
+  ];
+
+  it('should index the variant for the target platform and record the others on it', () => {
+    const result = selectPlatformVariants(documents(), linux);
+
+    expect(result.folded).toBe(2);
+    expect(result.documents.map((d) => `${d.metadata.file} ${d.metadata.name}`)).toEqual([
+      'worker/work_linux.go doWork',
+      'worker/work_linux.go pinCPU',
+      'worker/worker.go Run',
+    ]);
+    expect(result.documents[0].metadata.platformVariants).toEqual([
+      { buildConstraint: 'darwin || freebsd', file: 'worker/work_bsd.go', startLine: 7 },
+      { buildConstraint: 'windows', file: 'worker/work_windows.go', startLine: 4 },
+    ]);
+    expect(result.documents[1].metadata.platformVariants).toBeUndefined();
+  });
+
+  it('should follow the configured platform', () => {
+    const { documents: kept } = selectPlatformVariants(documents(), {
+      goos: 'darwin',
+      goarch: 'arm64',
+    });
+    const doWork = kept.filter((d) => d.metadata.name === 'doWork');
+
+    expect(doWork.map((d) => d.metadata.file)).toEqual(['worker/work_bsd.go']);
+    expect(doWork[0].metadata.platformVariants?.map((v) => v.file)).toEqual([
+      'worker/work_linux.go',
+      'worker/work_windows.go',
+    ]);
+    // Symbols of a single platform are indexed whatever the target
+    expect(kept.some((d) => d.metadata.name === 'pinCPU')).toBe(true);
+  });
+
+  it('should keep the first variant by path when none matches', () => {
+    const { documents: kept } = selectPlatformVariants(documents(), {
+      goos: 'plan9',
+      goarch: '386',
+    });
+
+    expect(kept.filter((d) => d.metadata.name === 'doWork').map((d) => d.metadata.file)).toEqual(
+      ['worker/work_bsd.go']
+    );
+  });
+
+  it('should not group across packages, init functions, or unconstrained files', () => {
+    const unrelated = [
+      doc('doWork', 'worker/work_linux.go', 'linux'),
+      doc('doWork', 'other/work_windows.go', 'windows'),
+      doc('init', 'worker/work_linux.go', 'linux'),
+      doc('init', 'worker/work_windows.go', 'windows'),
+      doc('Run', 'worker/worker.go'),
+      doc('Run', 'worker/run_windows.go', 'windows'),
+    ];
+
+    const result = selectPlatformVariants(unrelated, linux);
+
+    expect(result.folded).toBe(0);
+    expect(result.documents).toBe(unrelated);
+  });
+});
+
+describe('foldedVariantFiles', () => {
+  it('should list files left without documents, with their constraints', () => {
+    const { documents } = selectPlatformVariants(
+      [
+        doc('doWork', 'worker/work_linux.go', 'linux'),
+        doc('doWork', 'worker/work_windows.go', 'windows'),
+        doc('doWork', 'worker/work_darwin.go', 'darwin'),
+        doc('notify', 'worker/work_darwin.go', 'darwin', 12),
+      ],
+      linux
+    );
+
+    expect(foldedVariantFiles(documents)).toEqual(new Map([['worker/work_windows.go', 'windows']]));
+  });
+});
@@ -0,0 +1,65 @@
+import { describe, expect, it } from 'vitest';
+import type { EmbeddingProfile } from '../../types';
+import { DEFAULT_EMBEDDING_PROFILE, EmbeddingProfiles } from '../profiles';
+
+describe('EmbeddingProfiles', () => {
+  const defaults = {
+    embeddingText: 'source' as const,
+    embeddingMaxTokens: 256,
+    maxDocumentBytes: 65536,
+  };
+
+  it('should use the default profile without bindings', () => {
+    const profiles = new EmbeddingProfiles({ defaults });
+    const profile = profiles.forFile('src/server.go');
+
+    expect(profile.name).toBe(DEFAULT_EMBEDDING_PROFILE);
+    expect(profile.key).toMatch(/^default@[0-9a-f]{8}$/);
+    expect(profile).toMatchObject(defaults);
+  });
+
+  it('should bind paths to profiles, first match wins', () => {
+    const profiles = new EmbeddingProfiles({
+      defaults,
+      profiles: { docs: { maxDocumentBytes: 8192 }, code: { embeddingText: 'card' } },
+      profile: 'code',
+      bindings: [
+        { paths: ['docs/**', '*.md'], profile: 'docs' },
+        { paths: ['docs/api/**'], profile: 'code' },
+      ],
+    });
+
+    expect(profiles.forFile('docs/api/users.md').name).toBe('docs');
+    expect(profiles.forFile('README.md').name).toBe('docs');
+    expect(profiles.forFile('internal/server.go').name).toBe('code');
+    // Unset settings come from the defaults
+    expect(profiles.forFile('docs/guide.md')).toMatchObject({
+      embeddingText: 'source',
+      embeddingMaxTokens: 256,
+      maxDocumentBytes: 8192,
+    });
+    expect(profiles.forFile('main.go').embeddingText).toBe('card');
+  });
+
+  it('should change the key only when the resolved settings change', () => {
+    const key = (docs: EmbeddingProfile) => {
+      const profiles = new EmbeddingProfiles({ defaults, profiles: { docs }, profile: 'docs' });
+      return profiles.forFile('guide.md').key;
+    };
+
+    expect(key({})).toBe(key({ embeddingMaxTokens: undefined }));
+    expect(key({})).toBe(key({ embeddingMaxTokens: 256 }));
+    expect(key({})).not.toBe(key({ embeddingMaxTokens: 128 }));
+    expect(key({})).toMatch(/^docs@/);
+  });
+
+  it('should reject undefined profile names', () => {
+    expect(() => new EmbeddingProfiles({ defaults, profile: 'missing' })).toThrow(
+      'Unknown embedding profile "missing"'
+    );
+    const bindings = [{ paths: ['docs/**'], profile: 'docs' }];
+    expect(() => new EmbeddingProfiles({ defaults, bindings })).toThrow(
+      'Unknown embedding profile "docs"'
+    );
+  });
+});
@@ -0,0 +1,92 @@
+import { describe, expect, it } from 'vitest';
+import type { Document, DocumentType } from '../../../scanner/types';
+import { applySymbolFilters, isIndexed } from '../symbol-filter';
+
+function doc(
+  name: string,
+  type: DocumentType,
+  options: { exported?: boolean; file?: string; language?: string } = {}
+): Document {
+  const { exported = true, file = 'server.go', language = 'go' } = options;
+  return {
+    id: `${file}:${name}:1`,
+    text: name,
+    type,
+    language,
+    metadata: { file, name, startLine: 1, endLine: 5, exported },
+  };
+}
+
+describe('isIndexed', () => {
+  it('should index everything without a rule for the language', () => {
+    expect(isIndexed(doc('helper', 'function', { exported: false }), {})).toBe(true);
+    expect(
+      isIndexed(doc('helper', 'function', { language: 'typescript' }), {
+        go: { excludeKinds: ['function'] },
+      })
+    ).toBe(true);
+  });
+
+  it('should apply include and exclude kinds', () => {
+    const filters = { go: { includeKinds: ['function', 'method'] as DocumentType[] } };
+
+    expect(isIndexed(doc('Serve', 'function'), filters)).toBe(true);
+    expect(isIndexed(doc('Config', 'type'), filters)).toBe(false);
+    expect(isIndexed(doc('Timeout', 'variable'), { go: { excludeKinds: ['variable'] } })).toBe(
+      false
+    );
+  });
+
+  it('should skip unexported symbols of the listed kinds only', () => {
+    const filters = { go: { skipUnexported: ['function', 'method'] as DocumentType[] } };
+
+    expect(isIndexed(doc('helper', 'function', { exported: false }), filters)).toBe(false);
+    expect(isIndexed(doc('Serve', 'function'), filters)).toBe(true);
+    expect(isIndexed(doc('config', 'type', { exported: false }), filters)).toBe(true);
+  });
+
+  it('should never apply visibility rules to documentation', () => {
+    const readme = doc('README', 'documentation', { exported: false, language: 'markdown' });
+
+    expect(isIndexed(readme, { '*': { skipUnexported: true } })).toBe(true);
+  });
+
+  it('should skip symbols in test files', () => {
+    const filters = { '*': { skipTests: true } };
+
+    expect(isIndexed(doc('TestServe', 'function', { file: 'server_test.go' }), filters)).toBe(
+      false
+    );
+    expect(
+      isIndexed(doc('it', 'function', { file: 'src/a.test.ts', language: 'typescript' }), filters)
+    ).toBe(false);
+    expect(isIndexed(doc('Serve', 'function'), filters)).toBe(true);
+  });
+
+  it('should prefer the language rule over the fallback', () => {
+    const filters = { go: {}, '*': { skipUnexported: true } };
+
+    expect(isIndexed(doc('helper', 'function', { exported: false }), filters)).toBe(true);
+  });
+});
+
+describe('applySymbolFilters', () => {
+  it('should count the documents left out', () => {
+    const documents = [
+      doc('Serve', 'function'),
+      doc('helper', 'function', { exported: false }),
+      doc('conn', 'type', { exported: false }),
+    ];
+
+    const result = applySymbolFilters(documents, { go: { skipUnexported: ['function'] } });
+
+    expect(result.documents.map((d) => d.metadata.name)).toEqual(['Serve', 'conn']);
+    expect(result.skipped).toBe(1);
+  });
+
+  it('should return the documents untouched without filters', () => {
+    const documents = [doc('Serve', 'function')];
+
+    expect(applySymbolFilters(documents, {}).documents).toBe(documents);
+  });
+});
@@ -0,0 +1,105 @@
+/**
+ * Tests for token-aware truncation
+ */
+
+import { describe, expect, it } from 'vitest';
+import {
+  bodyWithoutSignature,
+  compactSignature,
+  estimateTokenCount,
+  fitEmbeddingText,
+  TRUNCATION_MARKER,
+} from '../truncation';
+
+// One token per whitespace-separated word keeps the arithmetic readable
+const countWords = (text: string) => text.split(/\s+/).filter(Boolean).length;
+
+describe('Token-aware truncation', () => {
+  describe('compactSignature', () => {
+    it('should keep the receiver and return types of methods', () => {
+      expect(
+        compactSignature(
+          'func (s *Server) Handle(w http.ResponseWriter, r *http.Request) (int, error)',
+          'Server.Handle'
+        )
+      ).toBe('func (s *Server) Handle(...) (int, error)');
+    });
+
+    it('should not confuse the method name with the receiver type', () => {
+      expect(compactSignature('func (h *Handler) Handle(ctx context.Context) error', 'Handle')).toBe(
+        'func (h *Handler) Handle(...) error'
+      );
+    });
+
+    it('should skip type parameters', () => {
+      expect(compactSignature('func Map[T, U any](xs []T, f func(T) U) []U', 'Map')).toBe(
+        'func Map[T, U any](...) []U'
+      );
+      expect(compactSignature('function pick<T>(items: T[], n: number): T[]', 'pick')).toBe(
+        'function pick<T>(...): T[]'
+      );
+    });
+
+    it('should leave signatures without parameters alone', () => {
+      expect(compactSignature('type Config struct', 'Config')).toBe('type Config struct');
+    });
+  });
+
+  describe('fitEmbeddingText', () => {
+    const parts = {
+      header: 'function: load',
+      docstring: 'load reads the config',
+      signature: 'func load(path string) error',
+      name: 'load',
+      body: 'a b\nc d\ne f\ng h',
+    };
+
+    it('should return everything when it fits', () => {
+      expect(fitEmbeddingText(parts, { maxTokens: 100, countTokens: countWords })).toBe(
+        'function: load\nload reads the config\nfunc load(path string) error\na b\nc d\ne f\ng h'
+      );
+    });
+
+    it('should trim the body and keep doc and signature intact', () => {
+      // head = 2 + 4 + 4 = 10 words, marker = 1, so two body lines fit in 15
+      const text = fitEmbeddingText(parts, { maxTokens: 15, countTokens: countWords });
+
+      expect(text).toBe(
+        `function: load\nload reads the config\nfunc load(path string) error\na b\nc d\n${TRUNCATION_MARKER}`
+      );
+      expect(countWords(text)).toBeLessThanOrEqual(15);
+    });
+
+    it('should compact the signature when doc and signature alone are over budget', () => {
+      const text = fitEmbeddingText(parts, { maxTokens: 5, countTokens: countWords });
+
+      expect(text).toBe('function: load\nload reads the config\nfunc load(...) error');
+    });
+
+    it('should keep the words of a long line that fit', () => {
+      const text = fitEmbeddingText(
+        { header: 'document: Setup', body: 'one two three four five six' },
+        { maxTokens: 6, countTokens: countWords }
+      );
+
+      expect(text).toBe(`document: Setup\none two three\n${TRUNCATION_MARKER}`);
+    });
+
+    it('should estimate tokens without a tokenizer', () => {
+      const body = 'x'.repeat(4000);
+      const text = fitEmbeddingText({ header: 'document: README', body }, { maxTokens: 100 });
+
+      expect(estimateTokenCount(text)).toBeLessThanOrEqual(100);
+    });
+  });
+
+  describe('bodyWithoutSignature', () => {
+    it('should strip a leading signature', () => {
+      expect(bodyWithoutSignature('func a() {\n\treturn\n}', 'func a() {')).toBe('\treturn\n}');
+    });
+
+    it('should keep snippets that do not start with the signature', () => {
+      expect(bodyWithoutSignature('x := 1', 'func a()')).toBe('x := 1');
+    });
+  });
+});
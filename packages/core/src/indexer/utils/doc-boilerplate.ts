@@ -0,0 +1,87 @@
+/**
+ * Doc Comment Boilerplate
+ *
+ * Finds doc comments repeated across many symbols, like the license text or
+ * "Deprecated: Use X.ProtoReflect.Descriptor instead." that generated code
+ * stamps everywhere. Embedded, they make every such symbol look alike, so
+ * search ranks by the shared text instead of what sets a symbol apart.
+ *
+ * Comments are compared normalized: the parts of the symbol's own name are
+ * replaced, digits (years, versions) folded, whitespace collapsed, and case
+ * ignored. A comment found on at least the threshold number of symbols is
+ * boilerplate: it stays in `docstring` for display but is left out of the
+ * embedding text and doesn't count toward doc quality.
+ */
+
+import * as crypto from 'node:crypto';
+import type { Document } from '../../scanner/types';
+
+/** Symbols sharing a doc comment before it counts as boilerplate (default) */
+export const DEFAULT_BOILERPLATE_DOC_THRESHOLD = 10;
+
+/**
+ * Key of a doc comment, equal for comments that differ only in the symbol's
+ * own name, numbers, whitespace, or case
+ *
+ * @param docstring - The doc comment
+ * @param name - Name of the symbol it documents (e.g. `Server.Close`)
+ */
+export function docCommentKey(docstring: string, name?: string): string {
+  let text = docstring;
+  for (const part of (name ?? '').split('.')) {
+    if (part.length < 2) continue;
+    text = text.replace(new RegExp(`\\b${escapeRegExp(part)}\\b`, 'g'), '_');
+  }
+  const normalized = text.replace(/\d+/g, '0').replace(/\s+/g, ' ').trim().toLowerCase();
+  return crypto.createHash('sha256').update(normalized).digest('hex').slice(0, 12);
+}
+
+/**
+ * Doc comment keys of documents, one per documented symbol
+ */
+export function docCommentKeys(documents: Document[]): string[] {
+  return documents
+    .filter((doc) => doc.metadata.docstring?.trim())
+    .map((doc) => docCommentKey(doc.metadata.docstring ?? '', doc.metadata.name));
+}
+
+/**
+ * Set `boilerplateDoc` on documents whose doc comment is boilerplate, in place
+ *
+ * @param documents - Documents being embedded; their comments are counted
+ * @param otherKeys - Keys of documents elsewhere in the index, counted too
+ * @param threshold - Symbols sharing a comment before it's boilerplate; 0 disables
+ * @returns Number of documents marked
+ */
+export function markBoilerplateDocs(
+  documents: Document[],
+  otherKeys: Iterable<string>,
+  threshold = DEFAULT_BOILERPLATE_DOC_THRESHOLD
+): number {
+  if (threshold <= 0) return 0;
+
+  const counts = new Map<string, number>();
+  for (const key of otherKeys) {
+    counts.set(key, (counts.get(key) ?? 0) + 1);
+  }
+  const keys = new Map<Document, string>();
+  for (const doc of documents) {
+    if (!doc.metadata.docstring?.trim()) continue;
+    const key = docCommentKey(doc.metadata.docstring, doc.metadata.name);
+    keys.set(doc, key);
+    counts.set(key, (counts.get(key) ?? 0) + 1);
+  }
+
+  let marked = 0;
+  for (const [doc, key] of keys) {
+    if ((counts.get(key) ?? 0) >= threshold) {
+      doc.metadata.boilerplateDoc = true;
+      marked++;
+    }
+  }
+  return marked;
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
@@ -5,7 +5,73 @@
 
 import type { Document } from '../../scanner/types';
 import type { EmbeddingDocument } from '../../vector/types';
-import { formatDocumentText } from './formatting';
+import type { EmbeddingTextMode } from '../types';
+import { formatDocumentText, formatDocumentTextWithBudget, formatSymbolCard } from './formatting';
+import type { EmbeddingTextBudget } from './truncation';
+
+/**
+ * Map scanner metadata to vector store metadata
+ *
+ * Renames `file` to `path` and adds document-level fields (type, language).
+ * Enrichment fields are stored at the top level, where search filters match them.
+ */
+function buildEmbeddingMetadata(doc: Document): Record<string, unknown> {
+  return {
+    // Custom fields first, so they can't replace built-in ones
+    ...doc.metadata.enrichment,
+    path: doc.metadata.file,
+    type: doc.type,
+    language: doc.language,
+    name: doc.metadata.name,
+    startLine: doc.metadata.startLine,
+    endLine: doc.metadata.endLine,
+    exported: doc.metadata.exported,
+    signature: doc.metadata.signature,
+    docstring: doc.metadata.docstring,
+    snippet: doc.metadata.snippet,
+    imports: doc.metadata.imports,
+    module: doc.metadata.module,
+    goVersion: doc.metadata.goVersion,
+    dependencies: doc.metadata.dependencies,
+    packageDoc: doc.metadata.packageDoc,
+    callees: doc.metadata.callees,
+    referencesTypes: doc.metadata.referencesTypes,
+    complexity: doc.metadata.complexity,
+    parseError: doc.metadata.parseError,
+    overflow: doc.metadata.overflow,
+    usesCgo: doc.metadata.usesCgo,
+    crashes: doc.metadata.crashes,
+    crashContext: doc.metadata.crashContext,
+    recovers: doc.metadata.recovers,
+    defers: doc.metadata.defers,
+    errorsReturned: doc.metadata.errorsReturned,
+    constructs: doc.metadata.constructs,
+    constructorConfidence: doc.metadata.constructorConfidence,
+    iterator: doc.metadata.iterator,
+    example: doc.metadata.example,
+    lastModified: doc.metadata.lastModified,
+    lastAuthor: doc.metadata.lastAuthor,
+    diagnostics: doc.metadata.diagnostics,
+    asserts: doc.metadata.asserts,
+    routes: doc.metadata.routes,
+    sqlQueries: doc.metadata.sqlQueries,
+    funcLiteral: doc.metadata.funcLiteral,
+    openApiOperation: doc.metadata.openApiOperation,
+    typeSet: doc.metadata.typeSet,
+    typeConstraints: doc.metadata.typeConstraints,
+    contextFindings: doc.metadata.contextFindings,
+    buildConstraint: doc.metadata.buildConstraint,
+    platformVariants: doc.metadata.platformVariants,
+    generated: doc.metadata.generated,
+    generator: doc.metadata.generator,
+    boilerplateDoc: doc.metadata.boilerplateDoc,
+    embeddingProfile: doc.metadata.embeddingProfile,
+    constantType: doc.metadata.constantType,
+    constantValue: doc.metadata.constantValue,
+    constantExpression: doc.metadata.constantExpression,
+    fields: doc.metadata.custom?.fields,
+  };
+}
 
 /**
  * Prepare documents for embedding generation
@@ -15,6 +81,8 @@ import { formatDocumentText } from './formatting';
  * metadata transformation.
  *
  * @param documents - Array of documents from repository scanner
+ * @param budget - Optional token budget; when set, bodies are embedded and trimmed to fit
+ * @param mode - Embed source (default) or a symbol card built from metadata
  * @returns Array of documents ready for embedding generation
  *
  * @example
@@ -25,27 +93,31 @@ import { formatDocumentText } from './formatting';
  * // Now ready for: await vectorStore.addDocuments(prepared)
  * ```
  */
-export function prepareDocumentsForEmbedding(documents: Document[]): EmbeddingDocument[] {
+export function prepareDocumentsForEmbedding(
+  documents: Document[],
+  budget?: EmbeddingTextBudget,
+  mode: EmbeddingTextMode = 'source'
+): EmbeddingDocument[] {
   return documents.map((doc) => ({
     id: doc.id,
-    text: formatDocumentText(doc),
-    metadata: {
-      path: doc.metadata.file,
-      type: doc.type,
-      language: doc.language,
-      name: doc.metadata.name,
-      startLine: doc.metadata.startLine,
-      endLine: doc.metadata.endLine,
-      exported: doc.metadata.exported,
-      signature: doc.metadata.signature,
-      docstring: doc.metadata.docstring,
-      snippet: doc.metadata.snippet,
-      imports: doc.metadata.imports,
-      callees: doc.metadata.callees,
-    },
+    text: embeddingText(doc, budget, mode),
+    metadata: buildEmbeddingMetadata(doc),
   }));
 }
 
+function embeddingText(
+  source: Document,
+  budget: EmbeddingTextBudget | undefined,
+  mode: EmbeddingTextMode
+): string {
+  // Boilerplate doc comments stay in metadata for display but aren't embedded
+  const doc = source.metadata.boilerplateDoc
+    ? { ...source, metadata: { ...source.metadata, docstring: undefined } }
+    : source;
+  if (mode === 'card') return formatSymbolCard(doc, budget);
+  return budget ? formatDocumentTextWithBudget(doc, budget) : formatDocumentText(doc);
+}
+
 /**
  * Prepare single document for embedding
  *
@@ -65,20 +137,7 @@ export function prepareDocumentForEmbedding(doc: Document): EmbeddingDocument {
   return {
     id: doc.id,
     text: formatDocumentText(doc),
-    metadata: {
-      path: doc.metadata.file,
-      type: doc.type,
-      language: doc.language,
-      name: doc.metadata.name,
-      startLine: doc.metadata.startLine,
-      endLine: doc.metadata.endLine,
-      exported: doc.metadata.exported,
-      signature: doc.metadata.signature,
-      docstring: doc.metadata.docstring,
-      snippet: doc.metadata.snippet,
-      imports: doc.metadata.imports,
-      callees: doc.metadata.callees,
-    },
+    metadata: buildEmbeddingMetadata(doc),
   };
 }
 
@@ -0,0 +1,106 @@
+/**
+ * Metadata Enrichment
+ *
+ * Post-scan hooks attaching repository-specific fields to documents, such as
+ * an owning team from CODEOWNERS or a service name from the directory layout.
+ * Enrichers run after symbol filters and before embedding text is built; the
+ * fields they return are stored with the document's metadata, where search
+ * filters match them exactly (`{ team: 'payments' }`).
+ *
+ * Built-in metadata always wins: a field named like one (`path`, `type`, ...)
+ * is stored but can't be filtered on.
+ */
+
+import * as path from 'node:path';
+import { pathToFileURL } from 'node:url';
+import type { Logger } from '@lytics/kero';
+import type { Document } from '../../scanner/types';
+import type { DocumentEnricher, EnrichmentFields } from '../types';
+
+/**
+ * Resolve configured enrichers, importing the ones given as module paths
+ *
+ * A module path is resolved against the repository; its default export (or
+ * a named `enrich` export) must be the enricher function.
+ *
+ * @param enrichers - Enricher functions and module paths
+ * @param repositoryPath - Root that relative module paths are resolved against
+ */
+export async function loadEnrichers(
+  enrichers: Array<DocumentEnricher | string>,
+  repositoryPath: string
+): Promise<DocumentEnricher[]> {
+  return Promise.all(
+    enrichers.map(async (enricher) => {
+      if (typeof enricher === 'function') return enricher;
+      const url = pathToFileURL(path.resolve(repositoryPath, enricher)).href;
+      const module = (await import(url)) as { default?: unknown; enrich?: unknown };
+      const loaded = module.default ?? module.enrich;
+      if (typeof loaded !== 'function') {
+        throw new Error(`Enricher module ${enricher} must export a function as default or enrich`);
+      }
+      return loaded as DocumentEnricher;
+    })
+  );
+}
+
+/**
+ * Run enrichers over documents, merging their fields into `enrichment` in place
+ *
+ * Enrichers run in order, so a later one overrides an earlier one's field.
+ * An enricher that throws leaves that document alone; failures are logged
+ * once per enricher rather than failing the index.
+ *
+ * @param documents - Scanned documents
+ * @param enrichers - Hooks to run
+ * @param logger - Receives a warning per failing enricher
+ * @returns Number of documents that gained at least one field
+ */
+export async function applyEnrichers(
+  documents: Document[],
+  enrichers: DocumentEnricher[],
+  logger?: Logger
+): Promise<number> {
+  const enriched = new Set<string>();
+  for (const [index, enricher] of enrichers.entries()) {
+    let failures = 0;
+    let firstError: unknown;
+    for (const doc of documents) {
+      try {
+        const fields = scalarFields(await enricher(doc.metadata, doc));
+        if (Object.keys(fields).length === 0) continue;
+        doc.metadata.enrichment = { ...doc.metadata.enrichment, ...fields };
+        enriched.add(doc.id);
+      } catch (error) {
+        failures++;
+        firstError ??= error;
+      }
+    }
+    if (failures > 0) {
+      logger?.warn(
+        {
+          enricher: enricher.name || index,
+          failures,
+          error: firstError instanceof Error ? firstError.message : String(firstError),
+        },
+        `Enricher failed on ${failures} document(s); they were indexed without its fields`
+      );
+    }
+  }
+  return enriched.size;
+}
+
+/**
+ * Fields usable as exact-match filters: strings, finite numbers, and booleans
+ */
+function scalarFields(fields: EnrichmentFields | undefined | null): EnrichmentFields {
+  const scalars: EnrichmentFields = {};
+  for (const [key, value] of Object.entries(fields ?? {})) {
+    if (typeof value === 'string' || typeof value === 'boolean') {
+      scalars[key] = value;
+    } else if (typeof value === 'number' && Number.isFinite(value)) {
+      scalars[key] = value;
+    }
+  }
+  return scalars;
+}
@@ -3,7 +3,11 @@
  * Functions for document text formatting and optimization
  */
 
-import type { Document } from '../../scanner/types';
+import * as path from 'node:path';
+import { describeSqlQueries } from '../../scanner/sql-queries';
+import type { Document, StructField } from '../../scanner/types';
+import { identifierWords } from '../../search/identifiers';
+import { bodyWithoutSignature, type EmbeddingTextBudget, fitEmbeddingText } from './truncation';
 
 /**
  * Format document text for better embedding quality
@@ -24,7 +28,7 @@ import type { Document } from '../../scanner/types';
  * };
  *
  * formatDocumentText(doc);
- * // "function: calculateTotal\n\nfunction calculateTotal(items) { ... }"
+ * // "function: calculateTotal (calculate total)\n\nfunction calculateTotal(items) { ... }"
  * ```
  */
 export function formatDocumentText(doc: Document): string {
@@ -32,7 +36,7 @@ export function formatDocumentText(doc: Document): string {
 
   // Add type and name for context
   if (doc.metadata.name) {
-    parts.push(`${doc.type}: ${doc.metadata.name}`);
+    parts.push(documentHeader(doc));
   }
 
   // Add actual content
@@ -88,6 +92,145 @@ export function formatDocumentTextWithSignature(doc: Document): string {
   return parts.join('\n');
 }
 
+/**
+ * Format document text within a token budget
+ *
+ * Embeds the doc comment, signature, and body. When the text is over budget
+ * the body is trimmed and the doc comment and signature are kept intact.
+ * Documents without a code snippet (e.g. markdown) use their text as the body.
+ *
+ * @param doc - Document to format
+ * @param budget - Token budget and tokenizer
+ * @returns Formatted text that fits the budget
+ *
+ * @example
+ * ```typescript
+ * formatDocumentTextWithBudget(doc, { maxTokens: 256, countTokens: embedder.countTokens });
+ * // "function: parseConfig (parse config)\n// parseConfig reads...\nfunc parseConfig(path string) (*Config, error)\n..."
+ * ```
+ */
+export function formatDocumentTextWithBudget(doc: Document, budget: EmbeddingTextBudget): string {
+  const { name, signature, docstring, snippet } = doc.metadata;
+
+  return fitEmbeddingText(
+    {
+      header: documentHeader(doc),
+      docstring,
+      signature,
+      name,
+      body: snippet ? bodyWithoutSignature(snippet, signature) : doc.text,
+    },
+    budget
+  );
+}
+
+/** Callees listed on a symbol card */
+const MAX_CARD_CALLEES = 12;
+
+/** Struct fields listed on a symbol card */
+const MAX_CARD_FIELDS = 16;
+
+/**
+ * Format a symbol as a "card" built from its metadata instead of its source
+ *
+ * The card has the kind and name, doc comment, and signature, then one line
+ * each for the owning type of a method, the package and its doc, and the
+ * identifiers that say what the symbol does: routes it registers, what it
+ * calls, its fields, what it constructs, and the errors it returns. The body
+ * is left out. Documents without a signature, such as markdown sections,
+ * have no card and are formatted as source.
+ *
+ * @param doc - Document to format
+ * @param budget - Optional token budget; card lines are trimmed to fit
+ * @returns Card text for embedding
+ *
+ * @example
+ * ```typescript
+ * formatSymbolCard(doc);
+ * // method: Server.CreateUser (server create user)
+ * // CreateUser stores a new account.
+ * // func (s *Server) CreateUser(w http.ResponseWriter, r *http.Request)
+ * // method of Server
+ * // package users: Package users manages accounts.
+ * // calls: validate, s.store.Insert
+ * ```
+ */
+export function formatSymbolCard(doc: Document, budget?: EmbeddingTextBudget): string {
+  const { name, signature, docstring } = doc.metadata;
+  if (!signature) {
+    return budget ? formatDocumentTextWithBudget(doc, budget) : formatDocumentText(doc);
+  }
+
+  const parts = { header: documentHeader(doc), docstring, signature, name, body: cardLines(doc) };
+  if (budget) {
+    return fitEmbeddingText(parts, budget);
+  }
+  return [parts.header, docstring, signature, parts.body].filter(Boolean).join('\n');
+}
+
+/**
+ * The metadata lines of a symbol card, most useful first
+ */
+function cardLines(doc: Document): string {
+  const { metadata } = doc;
+  const lines: string[] = [];
+
+  if (doc.type === 'method' && metadata.name?.includes('.')) {
+    lines.push(`method of ${metadata.name.slice(0, metadata.name.lastIndexOf('.'))}`);
+  }
+
+  const pkg = metadata.packageName ?? path.posix.dirname(metadata.file);
+  if (metadata.packageDoc) {
+    lines.push(`package ${pkg}: ${metadata.packageDoc}`);
+  } else if (pkg !== '.') {
+    lines.push(`package ${pkg}`);
+  }
+
+  if (metadata.routes?.length) {
+    const routes = metadata.routes.map((r) => `${r.method ?? 'ANY'} ${r.path}`);
+    lines.push(`routes: ${routes.join(', ')}`);
+  }
+  if (metadata.sqlQueries?.length) {
+    lines.push(`sql: ${describeSqlQueries(metadata.sqlQueries)}`);
+  }
+  if (metadata.callees?.length) {
+    const callees = [...new Set(metadata.callees.map((callee) => callee.name))];
+    lines.push(`calls: ${callees.slice(0, MAX_CARD_CALLEES).join(', ')}`);
+  }
+  const fields = metadata.custom?.fields as StructField[] | undefined;
+  if (fields?.length) {
+    const described = fields.map((field) =>
+      field.embedded ? field.type : `${field.name} ${field.type}`
+    );
+    lines.push(`fields: ${described.slice(0, MAX_CARD_FIELDS).join(', ')}`);
+  }
+  if (metadata.constructs) {
+    lines.push(`constructs: ${metadata.constructs}`);
+  }
+  if (metadata.funcLiteral) {
+    const { enclosing, captures } = metadata.funcLiteral;
+    const captured = captures.length > 0 ? `, captures ${captures.join(', ')}` : '';
+    lines.push(`func literal in ${enclosing}${captured}`);
+  }
+  if (metadata.errorsReturned?.length) {
+    const errors = [...new Set(metadata.errorsReturned.map((returned) => returned.error))];
+    lines.push(`returns errors: ${errors.join(', ')}`);
+  }
+
+  return lines.join('\n');
+}
+
+/**
+ * Type and name of a document, with the words of the name so natural-language
+ * queries match identifiers: "function: MarkFailAndGetWait (mark fail and get wait)"
+ */
+function documentHeader(doc: Document): string {
+  const { name } = doc.metadata;
+  if (!name) return doc.type;
+  const words = identifierWords(name);
+  return words ? `${doc.type}: ${name} (${words})` : `${doc.type}: ${name}`;
+}
+
 /**
  * Truncate document text to maximum length
  *
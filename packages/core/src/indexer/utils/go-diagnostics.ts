@@ -0,0 +1,375 @@
+/**
+ * Go Diagnostics
+ *
+ * Runs `go build` and `go vet` over the packages of scanned Go files and
+ * attaches what they report to the symbols whose line ranges contain it, so
+ * a finding reads "this function has a vet warning" instead of a bare line
+ * number. Both commands need a buildable checkout (a Go toolchain, modules
+ * downloaded), so the pass is opt-in.
+ *
+ * Results are cached per package directory under a fingerprint of its Go
+ * files and its module's go.mod and go.sum. Only packages whose fingerprint
+ * changed are run again; a change in an imported package doesn't invalidate
+ * its importers until they change too or the cache is refreshed.
+ */
+
+import { execFile } from 'node:child_process';
+import * as crypto from 'node:crypto';
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { promisify } from 'node:util';
+import type { Logger } from '@lytics/kero';
+import type { Document, GoDiagnostic } from '../../scanner/types';
+
+const execFileAsync = promisify(execFile);
+
+/** Bump when the cache layout or how results are computed changes */
+const CACHE_VERSION = 1;
+
+/** `file.go:line[:column]: message`, optionally prefixed by `vet: ` for type errors */
+const DIAGNOSTIC_LINE = /^(?:vet: )?(.+?\.go):(\d+)(?::(\d+))?: (.+)$/;
+
+/** Directories the go command never treats as packages */
+const SKIPPED_DIRS = new Set(['testdata', 'vendor']);
+
+/**
+ * A diagnostic with the repository-relative file it was reported in
+ */
+export interface FileDiagnostic extends GoDiagnostic {
+  file: string;
+}
+
+/**
+ * Result of running a go command
+ */
+export interface GoCommandResult {
+  exitCode: number;
+  /** stdout and stderr combined; diagnostics are printed to stderr */
+  output: string;
+}
+
+/**
+ * Runs `go <args>` in a directory. Rejects only when the command can't be
+ * started (e.g. ENOENT when Go isn't installed), not on a non-zero exit.
+ */
+export type GoCommandRunner = (args: string[], cwd: string) => Promise<GoCommandResult>;
+
+/**
+ * Options for annotating documents with Go diagnostics
+ */
+export interface GoDiagnosticsOptions {
+  /** Repository root; reported paths are made relative to it */
+  repositoryPath: string;
+  /** JSON file caching results per package */
+  cachePath: string;
+  /** Runs the go command (default: `go` from PATH) */
+  run?: GoCommandRunner;
+  /** Ignore cached results and run every package again (default: false) */
+  refresh?: boolean;
+  logger?: Logger;
+}
+
+/**
+ * What a diagnostics pass did
+ */
+export interface GoDiagnosticsStats {
+  /** Packages go build and go vet ran over */
+  packagesRun: number;
+  /** Packages whose cached results were reused */
+  packagesCached: number;
+  /** Diagnostics attached to documents */
+  attached: number;
+}
+
+interface PackageEntry {
+  fingerprint: string;
+  diagnostics: FileDiagnostic[];
+}
+
+interface DiagnosticsCache {
+  version: number;
+  packages: Record<string, PackageEntry>;
+}
+
+/**
+ * Parse diagnostics from go build or go vet output
+ *
+ * Package headers (`# example.com/m/store`) and other lines that don't
+ * name a Go file are skipped; tab-indented continuation lines (`have (...)`,
+ * `want (...)`) are appended to the message before them. Files outside the
+ * repository (the module cache, GOROOT) are dropped.
+ *
+ * @param output - Combined command output
+ * @param cwd - Directory the command ran in; reported paths are relative to it
+ * @param repositoryPath - Repository root
+ * @param tool - Command that produced the output
+ */
+export function parseGoDiagnostics(
+  output: string,
+  cwd: string,
+  repositoryPath: string,
+  tool: GoDiagnostic['tool']
+): FileDiagnostic[] {
+  const diagnostics: FileDiagnostic[] = [];
+  let previous: FileDiagnostic | undefined;
+
+  for (const line of output.split('\n')) {
+    if (line.startsWith('\t') && previous) {
+      previous.message += ` ${line.trim()}`;
+      continue;
+    }
+    previous = undefined;
+
+    const match = DIAGNOSTIC_LINE.exec(line.trimEnd());
+    if (!match) continue;
+    const file = path.relative(repositoryPath, path.resolve(cwd, match[1]));
+    if (file.startsWith('..') || path.isAbsolute(file)) continue;
+
+    previous = {
+      file: file.split(path.sep).join('/'),
+      tool,
+      line: Number(match[2]),
+      ...(match[3] ? { column: Number(match[3]) } : {}),
+      message: match[4],
+    };
+    diagnostics.push(previous);
+  }
+  return diagnostics;
+}
+
+/**
+ * Run go build and go vet over the packages of the given documents' Go
+ * files and set `diagnostics` on the documents they fall within, in place
+ *
+ * Packages are grouped by their nearest go.mod and each module is run once.
+ * Packages that fail to build aren't vetted, since vet only repeats the
+ * errors. When Go isn't installed, or a module can't be loaded at all, a
+ * warning is logged and its documents are left without diagnostics.
+ *
+ * @param documents - Scanned documents; non-Go files are ignored
+ * @param options - Repository, cache location, and command runner
+ */
+export async function annotateGoDiagnostics(
+  documents: Document[],
+  options: GoDiagnosticsOptions
+): Promise<GoDiagnosticsStats> {
+  const { repositoryPath, cachePath, run = runGo, refresh = false, logger } = options;
+  const stats: GoDiagnosticsStats = { packagesRun: 0, packagesCached: 0, attached: 0 };
+
+  const byDir = new Map<string, Document[]>();
+  for (const doc of documents) {
+    const file = doc.metadata.file;
+    if (!file.endsWith('.go')) continue;
+    const dir = path.posix.dirname(file);
+    if (dir.split('/').some((segment) => SKIPPED_DIRS.has(segment))) continue;
+    const docs = byDir.get(dir) ?? [];
+    docs.push(doc);
+    byDir.set(dir, docs);
+  }
+  if (byDir.size === 0) return stats;
+
+  const cache = refresh ? emptyCache() : await readCache(cachePath);
+  const modules = new Map<string, Map<string, string>>();
+  const moduleRoots = new Map<string, string | null>();
+  // Packages whose cached results match their files, after the runs below
+  const current = new Set<string>();
+
+  for (const dir of byDir.keys()) {
+    const moduleRoot = await findModuleRoot(repositoryPath, dir, moduleRoots);
+    if (moduleRoot === null) {
+      logger?.debug({ dir }, 'No go.mod above package; skipping Go diagnostics');
+      continue;
+    }
+    const fingerprint = await fingerprintPackage(repositoryPath, dir, moduleRoot);
+    if (cache.packages[dir]?.fingerprint === fingerprint) {
+      stats.packagesCached++;
+      current.add(dir);
+      continue;
+    }
+    const pending = modules.get(moduleRoot) ?? new Map<string, string>();
+    pending.set(dir, fingerprint);
+    modules.set(moduleRoot, pending);
+  }
+
+  for (const [moduleRoot, pending] of modules) {
+    let results: Map<string, FileDiagnostic[]> | null;
+    try {
+      results = await diagnoseModule(repositoryPath, moduleRoot, [...pending.keys()], run);
+    } catch (error) {
+      if ((error as NodeJS.ErrnoException).code === 'ENOENT') {
+        logger?.warn('Go toolchain not found on PATH; skipping Go diagnostics');
+        break;
+      }
+      throw error;
+    }
+    if (!results) {
+      logger?.warn({ module: moduleRoot || '.' }, 'go build failed without diagnostics; skipped');
+      continue;
+    }
+    for (const [dir, fingerprint] of pending) {
+      cache.packages[dir] = { fingerprint, diagnostics: results.get(dir) ?? [] };
+      stats.packagesRun++;
+      current.add(dir);
+    }
+  }
+
+  if (stats.packagesRun > 0) {
+    await fs.mkdir(path.dirname(cachePath), { recursive: true });
+    await fs.writeFile(cachePath, JSON.stringify(cache), 'utf-8');
+  }
+
+  for (const [dir, docs] of byDir) {
+    if (!current.has(dir)) continue;
+    const diagnostics = cache.packages[dir].diagnostics;
+    for (const doc of docs) {
+      const { file: docFile, startLine, endLine } = doc.metadata;
+      const found = diagnostics
+        .filter(({ file, line }) => file === docFile && line >= startLine && line <= endLine)
+        .map(({ file: _file, ...diagnostic }) => diagnostic);
+      if (found.length > 0) {
+        doc.metadata.diagnostics = found;
+        stats.attached += found.length;
+      }
+    }
+  }
+  return stats;
+}
+
+/**
+ * Build, then vet, packages of one module
+ *
+ * @returns Diagnostics by package directory, or null when the module failed
+ *   to build without reporting anything (e.g. go.mod needs updates)
+ */
+async function diagnoseModule(
+  repositoryPath: string,
+  moduleRoot: string,
+  dirs: string[],
+  run: GoCommandRunner
+): Promise<Map<string, FileDiagnostic[]> | null> {
+  const cwd = path.join(repositoryPath, moduleRoot);
+  const patterns = (list: string[]) =>
+    list.map((dir) => {
+      const relative = path.posix.relative(moduleRoot || '.', dir);
+      return relative ? `./${relative}` : '.';
+    });
+  const requested = new Set(dirs);
+  const byDir = new Map<string, FileDiagnostic[]>();
+  const collect = (diagnostics: FileDiagnostic[], from: Set<string>) => {
+    for (const diagnostic of diagnostics) {
+      const dir = path.posix.dirname(diagnostic.file);
+      // Errors in imported packages belong to those packages' own results
+      if (!from.has(dir)) continue;
+      byDir.set(dir, [...(byDir.get(dir) ?? []), diagnostic]);
+    }
+  };
+
+  const build = await run(['build', '-o', os.devNull, ...patterns(dirs)], cwd);
+  const buildErrors = parseGoDiagnostics(build.output, cwd, repositoryPath, 'build');
+  if (build.exitCode !== 0 && buildErrors.length === 0) return null;
+  collect(buildErrors, requested);
+
+  const buildable = new Set(dirs.filter((dir) => !byDir.has(dir)));
+  if (buildable.size > 0) {
+    const vet = await run(['vet', ...patterns([...buildable])], cwd);
+    collect(parseGoDiagnostics(vet.output, cwd, repositoryPath, 'vet'), buildable);
+  }
+  return byDir;
+}
+
+/**
+ * Repository-relative directory of the nearest go.mod at or above a package, or null
+ */
+async function findModuleRoot(
+  repositoryPath: string,
+  dir: string,
+  known: Map<string, string | null>
+): Promise<string | null> {
+  const visited: string[] = [];
+  let current = dir === '.' ? '' : dir;
+  let root: string | null = null;
+  while (true) {
+    const cached = known.get(current);
+    if (cached !== undefined) {
+      root = cached;
+      break;
+    }
+    visited.push(current);
+    if (await exists(path.join(repositoryPath, current, 'go.mod'))) {
+      root = current;
+      break;
+    }
+    if (current === '') break;
+    const parent = path.posix.dirname(current);
+    current = parent === '.' ? '' : parent;
+  }
+  for (const visitedDir of visited) {
+    known.set(visitedDir, root);
+  }
+  return root;
+}
+
+/**
+ * Hash of a package's Go files and its module's go.mod and go.sum
+ */
+async function fingerprintPackage(
+  repositoryPath: string,
+  dir: string,
+  moduleRoot: string
+): Promise<string> {
+  const hash = crypto.createHash('sha256');
+  const absDir = path.join(repositoryPath, dir);
+  const files = (await fs.readdir(absDir)).filter((name) => name.endsWith('.go')).sort();
+  const moduleFiles = ['go.mod', 'go.sum'].map((name) =>
+    path.join(repositoryPath, moduleRoot, name)
+  );
+
+  for (const file of [...files.map((name) => path.join(absDir, name)), ...moduleFiles]) {
+    hash.update(path.relative(repositoryPath, file));
+    hash.update('\0');
+    hash.update(await fs.readFile(file).catch(() => Buffer.alloc(0)));
+    hash.update('\0');
+  }
+  return hash.digest('hex');
+}
+
+async function readCache(cachePath: string): Promise<DiagnosticsCache> {
+  try {
+    const cache = JSON.parse(await fs.readFile(cachePath, 'utf-8')) as DiagnosticsCache;
+    return cache.version === CACHE_VERSION && cache.packages ? cache : emptyCache();
+  } catch {
+    return emptyCache();
+  }
+}
+
+function emptyCache(): DiagnosticsCache {
+  return { version: CACHE_VERSION, packages: {} };
+}
+
+async function exists(file: string): Promise<boolean> {
+  try {
+    await fs.access(file);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Run `go` from PATH, resolving with the output on a non-zero exit
+ */
+async function runGo(args: string[], cwd: string): Promise<GoCommandResult> {
+  try {
+    const { stdout, stderr } = await execFileAsync('go', args, {
+      cwd,
+      encoding: 'utf-8',
+      maxBuffer: 50 * 1024 * 1024,
+    });
+    return { exitCode: 0, output: `${stdout}${stderr}` };
+  } catch (error) {
+    const failed = error as { code?: number | string; stdout?: string; stderr?: string };
+    if (typeof failed.code !== 'number') throw error;
+    return { exitCode: failed.code, output: `${failed.stdout ?? ''}${failed.stderr ?? ''}` };
+  }
+}
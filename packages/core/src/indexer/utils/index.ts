@@ -23,6 +23,13 @@ export {
   type PackageDiff,
   type StatsDiff,
 } from './comparison';
+// Boilerplate doc comments
+export {
+  DEFAULT_BOILERPLATE_DOC_THRESHOLD,
+  docCommentKey,
+  docCommentKeys,
+  markBoilerplateDocs,
+} from './doc-boilerplate';
 // Document preparation
 export {
   filterDocumentsByExport,
@@ -31,6 +38,20 @@ export {
   prepareDocumentForEmbedding,
   prepareDocumentsForEmbedding,
 } from './documents';
+// Post-scan metadata enrichment
+export { applyEnrichers, loadEnrichers } from './enrichment';
+// Git blame attribution
+export { annotateLastModified, type LastModified, lastModifiedInRange } from './last-modified';
+// Go build and vet diagnostics
+export {
+  annotateGoDiagnostics,
+  type FileDiagnostic,
+  type GoCommandResult,
+  type GoCommandRunner,
+  type GoDiagnosticsOptions,
+  type GoDiagnosticsStats,
+  parseGoDiagnostics,
+} from './go-diagnostics';
 // Stats export
 export {
   type ExportOptions,
@@ -39,13 +60,36 @@ export {
   exportStatsAsCsv,
   exportStatsAsJson,
 } from './export';
+// Per-path embedding profiles
+export {
+  DEFAULT_EMBEDDING_PROFILE,
+  type EmbeddingProfileConfig,
+  EmbeddingProfiles,
+  type ResolvedEmbeddingProfile,
+} from './profiles';
+// Platform variants of Go symbols
+export { foldedVariantFiles, selectPlatformVariants } from './platform-variants';
+// Index-time symbol filters
+export { ANY_LANGUAGE, applySymbolFilters, isIndexed } from './symbol-filter';
 // Text formatting
 export {
   cleanDocumentText,
   formatDocumentText,
+  formatDocumentTextWithBudget,
   formatDocumentTextWithSignature,
+  formatSymbolCard,
   truncateText,
 } from './formatting';
+// Token-aware truncation
+export {
+  bodyWithoutSignature,
+  compactSignature,
+  type EmbeddingTextBudget,
+  type EmbeddingTextParts,
+  estimateTokenCount,
+  fitEmbeddingText,
+  type TokenCounter,
+} from './truncation';
 // Language mapping
 export {
   getExtensionForLanguage,
@@ -0,0 +1,90 @@
+/**
+ * Symbol Last-Modified
+ *
+ * Attributes each symbol's most recent commit by intersecting git blame with
+ * its line range. Blame is expensive, so each file is blamed once for all of
+ * its symbols, a few files at a time, and only when enabled.
+ */
+
+import type { GitBlame, GitExtractor } from '../../git';
+import type { Document } from '../../scanner/types';
+
+/** Files blamed in parallel (default) */
+const DEFAULT_BLAME_CONCURRENCY = 4;
+
+/** Hash git blame reports for lines not committed yet */
+const UNCOMMITTED = /^0{40}$/;
+
+/**
+ * Most recent commit touching a line range
+ */
+export interface LastModified {
+  /** Author date of the commit (ISO) */
+  lastModified: string;
+  /** Author of the commit */
+  lastAuthor: string;
+}
+
+/**
+ * Most recent committed change within lines [startLine, endLine] of a blame
+ *
+ * Uncommitted lines are ignored, so a symbol with only local edits keeps its
+ * last committed date.
+ */
+export function lastModifiedInRange(
+  blame: GitBlame,
+  startLine: number,
+  endLine: number
+): LastModified | undefined {
+  let latest: LastModified | undefined;
+  for (const line of blame.lines) {
+    if (line.lineNumber < startLine || line.lineNumber > endLine) continue;
+    if (UNCOMMITTED.test(line.commit.hash) || !line.commit.date) continue;
+    if (!latest || line.commit.date > latest.lastModified) {
+      latest = { lastModified: line.commit.date, lastAuthor: line.commit.author };
+    }
+  }
+  return latest;
+}
+
+/**
+ * Set `lastModified` and `lastAuthor` on documents from git blame, in place
+ *
+ * Files that can't be blamed (untracked, or outside a git repository) are
+ * left without the fields.
+ *
+ * @param documents - Scanned documents, grouped by file internally
+ * @param git - Git extractor for the repository
+ * @param concurrency - Files blamed in parallel (default: 4)
+ * @returns Number of files blamed successfully
+ */
+export async function annotateLastModified(
+  documents: Document[],
+  git: GitExtractor,
+  concurrency = DEFAULT_BLAME_CONCURRENCY
+): Promise<number> {
+  const byFile = new Map<string, Document[]>();
+  for (const doc of documents) {
+    const docs = byFile.get(doc.metadata.file) ?? [];
+    docs.push(doc);
+    byFile.set(doc.metadata.file, docs);
+  }
+
+  const files = [...byFile.keys()];
+  let blamed = 0;
+  for (let i = 0; i < files.length; i += concurrency) {
+    const batch = files.slice(i, i + concurrency);
+    const blames = await Promise.all(batch.map((file) => git.getBlame(file).catch(() => null)));
+
+    batch.forEach((file, index) => {
+      const blame = blames[index];
+      if (!blame) return;
+      blamed++;
+      for (const doc of byFile.get(file) ?? []) {
+        const modified = lastModifiedInRange(blame, doc.metadata.startLine, doc.metadata.endLine);
+        if (modified) Object.assign(doc.metadata, modified);
+      }
+    });
+  }
+  return blamed;
+}
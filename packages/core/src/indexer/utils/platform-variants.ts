@@ -0,0 +1,80 @@
+/**
+ * Platform Variants
+ * One indexed copy of a Go symbol defined per platform
+ *
+ * Packages often define the same symbol in `work_linux.go`, `work_darwin.go`,
+ * and `work_windows.go`. Indexed as-is, a search returns each copy. Instead,
+ * same-named symbols of one package from files with different build
+ * constraints are grouped; the variant built for the target platform is
+ * indexed and the others are recorded on it as `platformVariants`. When no
+ * variant matches, the first by path stands in so the symbol stays findable.
+ *
+ * Symbols only one platform defines aren't variants and are always indexed;
+ * every document from a constrained file carries its `buildConstraint`.
+ */
+
+import * as path from 'node:path';
+import { matchesGoPlatform } from '../../scanner/go-build';
+import type { Document, GoPlatform } from '../../scanner/types';
+
+/** Names a package may declare once per file: every copy is built, none is a variant */
+const REPEATABLE_NAMES = new Set(['init', '_']);
+
+/**
+ * Fold platform variants into the variant for the target platform
+ *
+ * @param documents - Scanned documents; kept documents gain `platformVariants` in place
+ * @param platform - Target platform
+ * @returns Documents to index, and how many variants were folded
+ */
+export function selectPlatformVariants(
+  documents: Document[],
+  platform: GoPlatform
+): { documents: Document[]; folded: number } {
+  const groups = new Map<string, Document[]>();
+  for (const doc of documents) {
+    const { buildConstraint, name } = doc.metadata;
+    if (doc.language !== 'go' || doc.type === 'documentation') continue;
+    if (!buildConstraint || !name || REPEATABLE_NAMES.has(name)) continue;
+    const key = `${path.posix.dirname(doc.metadata.file)}\0${name}`;
+    groups.set(key, [...(groups.get(key) ?? []), doc]);
+  }
+
+  const dropped = new Set<Document>();
+  for (const group of groups.values()) {
+    if (new Set(group.map((doc) => doc.metadata.file)).size < 2) continue;
+
+    const sorted = [...group].sort((a, b) => a.metadata.file.localeCompare(b.metadata.file));
+    const kept =
+      sorted.find((doc) => matchesGoPlatform(doc.metadata.buildConstraint as string, platform)) ??
+      sorted[0];
+    const variants = sorted.filter((doc) => doc !== kept);
+    kept.metadata.platformVariants = variants.map((doc) => ({
+      buildConstraint: doc.metadata.buildConstraint as string,
+      file: doc.metadata.file,
+      startLine: doc.metadata.startLine,
+    }));
+    for (const doc of variants) dropped.add(doc);
+  }
+
+  if (dropped.size === 0) return { documents, folded: 0 };
+  return { documents: documents.filter((doc) => !dropped.has(doc)), folded: dropped.size };
+}
+
+/**
+ * Files whose documents were all folded into variants in other files, with
+ * their build constraints
+ *
+ * They have no documents of their own, yet a change to one still changes
+ * what the index says about its platform.
+ */
+export function foldedVariantFiles(documents: Document[]): Map<string, string> {
+  const withDocuments = new Set(documents.map((doc) => doc.metadata.file));
+  const files = new Map<string, string>();
+  for (const doc of documents) {
+    for (const variant of doc.metadata.platformVariants ?? []) {
+      if (!withDocuments.has(variant.file)) files.set(variant.file, variant.buildConstraint);
+    }
+  }
+  return files;
+}
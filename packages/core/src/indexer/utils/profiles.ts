@@ -0,0 +1,123 @@
+/**
+ * Embedding Profiles
+ * Named embedding settings bound to paths, so differently shaped code and
+ * docs can share one server
+ *
+ * A profile sets how a file's documents are embedded: the embedding text
+ * mode, its token budget, and the document size limit that chunks large
+ * documentation. Bindings map path globs to profiles, first match wins;
+ * other files use the repository's profile, by default the indexer's own
+ * settings (`default`).
+ *
+ * Each document records the profile it was embedded with as
+ * `name@fingerprint`, the fingerprint hashing the resolved settings. Editing
+ * a profile or rebinding a path changes the key of exactly the files it
+ * affects, so an update re-embeds those and nothing else.
+ */
+
+import * as crypto from 'node:crypto';
+import { matchesGlob } from '../../utils/glob';
+import type { EmbeddingProfile, EmbeddingProfileBinding, EmbeddingTextMode } from '../types';
+
+/** Profile made of the indexer's own embedding settings */
+export const DEFAULT_EMBEDDING_PROFILE = 'default';
+
+/**
+ * A profile with every setting filled in
+ */
+export interface ResolvedEmbeddingProfile {
+  /** Profile name */
+  name: string;
+  /** `name@fingerprint`, recorded on each document embedded with it */
+  key: string;
+  embeddingText: EmbeddingTextMode;
+  embeddingMaxTokens: number;
+  maxDocumentBytes: number;
+}
+
+/**
+ * Profiles and bindings to resolve files against
+ */
+export interface EmbeddingProfileConfig {
+  /** The indexer's own settings: the `default` profile, and fallbacks for the others */
+  defaults: Required<EmbeddingProfile>;
+  /** Named profiles */
+  profiles?: Record<string, EmbeddingProfile>;
+  /** Profile for files no binding matches (default: `default`) */
+  profile?: string;
+  /** Profiles by path, first match wins */
+  bindings?: EmbeddingProfileBinding[];
+}
+
+/**
+ * Resolves the embedding profile in effect for each file
+ */
+export class EmbeddingProfiles {
+  private readonly profiles = new Map<string, ResolvedEmbeddingProfile>();
+  private readonly bindings: EmbeddingProfileBinding[];
+  private readonly fallback: ResolvedEmbeddingProfile;
+
+  /**
+   * @throws When the repository's profile or a binding names an undefined profile
+   */
+  constructor(config: EmbeddingProfileConfig) {
+    const { defaults, profiles = {}, bindings = [] } = config;
+    const base = resolveProfile(DEFAULT_EMBEDDING_PROFILE, defaults);
+    this.profiles.set(base.name, base);
+    for (const [name, profile] of Object.entries(profiles)) {
+      this.profiles.set(name, resolveProfile(name, { ...defaults, ...definedSettings(profile) }));
+    }
+
+    this.fallback = this.named(config.profile ?? DEFAULT_EMBEDDING_PROFILE);
+    for (const binding of bindings) {
+      this.named(binding.profile);
+    }
+    this.bindings = bindings;
+  }
+
+  /**
+   * Profile in effect for a repository-relative file
+   */
+  forFile(file: string): ResolvedEmbeddingProfile {
+    const binding = this.bindings.find((b) =>
+      b.paths.some((pattern) => matchesGlob(file, pattern))
+    );
+    return binding ? this.named(binding.profile) : this.fallback;
+  }
+
+  private named(name: string): ResolvedEmbeddingProfile {
+    const profile = this.profiles.get(name);
+    if (!profile) {
+      throw new Error(`Unknown embedding profile "${name}"; define it in embeddingProfiles`);
+    }
+    return profile;
+  }
+}
+
+function resolveProfile(
+  name: string,
+  settings: Required<EmbeddingProfile>
+): ResolvedEmbeddingProfile {
+  const { embeddingText, embeddingMaxTokens, maxDocumentBytes } = settings;
+  const fingerprint = crypto
+    .createHash('sha256')
+    .update(JSON.stringify([embeddingText, embeddingMaxTokens, maxDocumentBytes]))
+    .digest('hex')
+    .slice(0, 8);
+  return {
+    name,
+    key: `${name}@${fingerprint}`,
+    embeddingText,
+    embeddingMaxTokens,
+    maxDocumentBytes,
+  };
+}
+
+/**
+ * A profile's settings without unset fields, so they don't hide the defaults
+ */
+function definedSettings(profile: EmbeddingProfile): EmbeddingProfile {
+  return Object.fromEntries(
+    Object.entries(profile).filter(([, value]) => value !== undefined)
+  ) as EmbeddingProfile;
+}
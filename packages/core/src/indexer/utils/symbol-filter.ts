@@ -0,0 +1,60 @@
+/**
+ * Symbol Filters
+ * Index-time rules for which symbols of a language are indexed at all
+ *
+ * Unlike query-time filters, a filtered symbol is never embedded or stored.
+ * Everything is indexed by default; a rule only removes symbols. Visibility
+ * rules don't apply to documentation, which has no notion of export.
+ */
+
+import type { Document } from '../../scanner/types';
+import { isTestFile } from '../../utils/test-utils';
+import type { SymbolFilterRule, SymbolFilters } from '../types';
+
+/** Rule key applying to languages without a rule of their own */
+export const ANY_LANGUAGE = '*';
+
+/**
+ * Drop the documents the filters exclude
+ *
+ * @param documents - Scanned documents
+ * @param filters - Rules by language
+ * @returns Documents to index, and how many were left out
+ */
+export function applySymbolFilters(
+  documents: Document[],
+  filters: SymbolFilters
+): { documents: Document[]; skipped: number } {
+  if (Object.keys(filters).length === 0) return { documents, skipped: 0 };
+  const kept = documents.filter((doc) => isIndexed(doc, filters));
+  return { documents: kept, skipped: documents.length - kept.length };
+}
+
+/**
+ * Whether a document passes the rule for its language
+ */
+export function isIndexed(doc: Document, filters: SymbolFilters): boolean {
+  const rule: SymbolFilterRule | undefined = filters[doc.language] ?? filters[ANY_LANGUAGE];
+  if (!rule) return true;
+
+  if (rule.includeKinds && !rule.includeKinds.includes(doc.type)) return false;
+  if (rule.excludeKinds?.includes(doc.type)) return false;
+  if (rule.skipTests && inTestCode(doc)) return false;
+
+  const { skipUnexported } = rule;
+  const unexported = doc.type !== 'documentation' && !doc.metadata.exported;
+  if (unexported && (skipUnexported === true || skipUnexported?.includes(doc.type))) {
+    return false;
+  }
+  return true;
+}
+
+function inTestCode(doc: Document): boolean {
+  const file = doc.metadata.file;
+  return (
+    doc.metadata.custom?.isTest === true ||
+    isTestFile(file) ||
+    file.endsWith('_test.go') ||
+    file.includes('/__tests__/')
+  );
+}
@@ -0,0 +1,163 @@
+/**
+ * Token-Aware Truncation
+ * Fit symbol text into an embedding token budget without losing search signal
+ *
+ * The doc comment and signature carry most of the signal, so they are kept
+ * intact and the body is trimmed. Budgets are measured with the embedding
+ * provider's tokenizer when one is available.
+ */
+
+/**
+ * Count the tokens in a piece of text
+ */
+export type TokenCounter = (text: string) => number;
+
+/**
+ * Token budget for embedding text
+ */
+export interface EmbeddingTextBudget {
+  /** Maximum tokens for the whole embedding text */
+  maxTokens: number;
+  /** Tokenizer-backed counter (default: ~4 characters per token estimate) */
+  countTokens?: TokenCounter;
+}
+
+/**
+ * The parts of a symbol's embedding text, in priority order
+ */
+export interface EmbeddingTextParts {
+  /** Kind and name, e.g. "function: ParseConfig" */
+  header: string;
+  /** Doc comment */
+  docstring?: string;
+  /** Declaration signature */
+  signature?: string;
+  /** Symbol name, used to locate the parameter list when compacting the signature */
+  name?: string;
+  /** Body or content; trimmed first */
+  body?: string;
+}
+
+/** Marker appended when the body is trimmed */
+export const TRUNCATION_MARKER = '...';
+
+/**
+ * Rough token estimate (~4 characters per token) for when no tokenizer is available
+ */
+export function estimateTokenCount(text: string): number {
+  return Math.ceil(text.length / 4);
+}
+
+/**
+ * Collapse a signature's parameter list, keeping the receiver and return types
+ *
+ * Used only when the signature alone exceeds the budget.
+ *
+ * @example
+ * compactSignature('func (s *Server) Handle(w http.ResponseWriter, r *http.Request) error', 'Handle')
+ * // "func (s *Server) Handle(...) error"
+ */
+export function compactSignature(signature: string, name?: string): string {
+  // Methods are indexed as Type.method; the signature only spells the method name
+  const shortName = name?.slice(name.lastIndexOf('.') + 1);
+  const match = shortName
+    ? new RegExp(`\\b${escapeRegExp(shortName)}\\s*(?=[[(<])`).exec(signature)
+    : null;
+  let index = match ? match.index + match[0].length : 0;
+
+  // Skip generic type parameters: Map[K comparable, V any](...)
+  if (signature[index] === '[' || signature[index] === '<') {
+    const close = findClosing(signature, index);
+    if (close < 0) return signature;
+    index = close + 1;
+  }
+
+  const open = signature.indexOf('(', index);
+  if (open < 0) return signature;
+  const close = findClosing(signature, open);
+  if (close < 0) return signature;
+
+  return `${signature.slice(0, open + 1)}...${signature.slice(close)}`;
+}
+
+/**
+ * Build embedding text that fits the token budget
+ *
+ * The header, doc comment, and signature are kept whole; the body is
+ * trimmed line by line. If the kept parts alone are over budget, the
+ * signature's parameter list is collapsed and the body is dropped.
+ */
+export function fitEmbeddingText(parts: EmbeddingTextParts, budget: EmbeddingTextBudget): string {
+  const count = budget.countTokens ?? estimateTokenCount;
+  const head = [parts.header, parts.docstring, parts.signature].filter(Boolean) as string[];
+  const full = [...head, parts.body].filter(Boolean).join('\n');
+
+  if (count(full) <= budget.maxTokens) {
+    return full;
+  }
+
+  let text = head.join('\n');
+  if (count(text) > budget.maxTokens) {
+    if (!parts.signature) return text;
+    const compact = compactSignature(parts.signature, parts.name);
+    return [parts.header, parts.docstring, compact].filter(Boolean).join('\n');
+  }
+
+  // Add body lines while they fit, leaving room for the marker
+  const markerTokens = count(TRUNCATION_MARKER);
+  let used = count(text);
+  for (const line of (parts.body ?? '').split('\n')) {
+    const lineTokens = count(`\n${line}`);
+    const remaining = budget.maxTokens - used - markerTokens;
+    if (lineTokens > remaining) {
+      // Keep the part of a long line (e.g. a markdown paragraph) that still fits
+      const prefix = fitPrefix(line, remaining, count);
+      if (prefix) text += `\n${prefix}`;
+      break;
+    }
+    text += `\n${line}`;
+    used += lineTokens;
+  }
+
+  return `${text}\n${TRUNCATION_MARKER}`;
+}
+
+/**
+ * Strip a leading signature from a code snippet so it isn't counted twice
+ */
+export function bodyWithoutSignature(snippet: string, signature?: string): string {
+  if (signature && snippet.startsWith(signature)) {
+    return snippet.slice(signature.length).replace(/^\s*\n/, '');
+  }
+  return snippet;
+}
+
+/**
+ * Longest word-boundary prefix of a line that fits in the remaining tokens
+ */
+function fitPrefix(line: string, maxTokens: number, count: TokenCounter): string {
+  const words = line.split(' ');
+  let low = 0;
+  let high = words.length;
+  while (low < high) {
+    const mid = Math.ceil((low + high) / 2);
+    if (count(`\n${words.slice(0, mid).join(' ')}`) <= maxTokens) low = mid;
+    else high = mid - 1;
+  }
+  return words.slice(0, low).join(' ').trim();
+}
+
+function escapeRegExp(value: string): string {
+  return value.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
+
+function findClosing(text: string, openIndex: number): number {
+  const open = text[openIndex];
+  const close = open === '(' ? ')' : open === '[' ? ']' : '>';
+  let depth = 0;
+  for (let i = openIndex; i < text.length; i++) {
+    if (text[i] === open) depth++;
+    else if (text[i] === close && --depth === 0) return i;
+  }
+  return -1;
+}
@@ -0,0 +1,123 @@
+import { Project, SyntaxKind } from 'ts-morph';
+import { describe, expect, it } from 'vitest';
+import { computeGoComplexity, computeTypeScriptComplexity } from '../complexity';
+import { parseCode } from '../tree-sitter';
+
+async function goComplexity(source: string): Promise<number> {
+  const tree = await parseCode(`package main\n\n${source}`, 'go');
+  const fn = tree.rootNode.namedChildren.find(
+    (n) => n.type === 'function_declaration' || n.type === 'method_declaration'
+  );
+  if (!fn) throw new Error('no function in source');
+  return computeGoComplexity(fn);
+}
+
+function tsComplexity(source: string): number {
+  const project = new Project({ useInMemoryFileSystem: true });
+  const file = project.createSourceFile('test.ts', source);
+  const fn = file.getFirstDescendantByKindOrThrow(SyntaxKind.FunctionDeclaration);
+  return computeTypeScriptComplexity(fn);
+}
+
+describe('Cyclomatic complexity', () => {
+  describe('Go', () => {
+    it('should be 1 for straight-line code', async () => {
+      expect(await goComplexity('func f() int { return 1 }')).toBe(1);
+    });
+
+    it('should count if / else if chains', async () => {
+      const src = `func f(x int) int {
+  if x > 0 {
+    return 1
+  } else if x < 0 {
+    return -1
+  }
+  return 0
+}`;
+      expect(await goComplexity(src)).toBe(3);
+    });
+
+    it('should count loops and boolean operators', async () => {
+      const src = `func f(xs []int) int {
+  n := 0
+  for _, x := range xs {
+    if x > 0 && x < 10 || x == 42 {
+      n++
+    }
+  }
+  return n
+}`;
+      // 1 + for + if + && + ||
+      expect(await goComplexity(src)).toBe(5);
+    });
+
+    it('should count case clauses but not default', async () => {
+      const src = `func f(x int) string {
+  switch x {
+  case 1:
+    return "one"
+  case 2, 3:
+    return "few"
+  default:
+    return "many"
+  }
+}`;
+      expect(await goComplexity(src)).toBe(3);
+    });
+
+    it('should count select and type switch cases', async () => {
+      const src = `func f(v interface{}, c chan int) {
+  switch v.(type) {
+  case int:
+  case string:
+  }
+  select {
+  case <-c:
+  default:
+  }
+}`;
+      expect(await goComplexity(src)).toBe(4);
+    });
+  });
+
+  describe('TypeScript', () => {
+    it('should be 1 for straight-line code', () => {
+      expect(tsComplexity('function f() { return 1; }')).toBe(1);
+    });
+
+    it('should count branches, loops, and operators', () => {
+      const src = `function f(xs: number[], y?: number) {
+  let n = 0;
+  for (const x of xs) {
+    if (x > 0 && x < 10) n++;
+    else if (x === 42) n += 2;
+  }
+  while (n > 100) n--;
+  const z = y ?? 0;
+  return n > 5 ? n : z;
+}`;
+      // 1 + for-of + if + && + else-if + while + ?? + ternary
+      expect(tsComplexity(src)).toBe(8);
+    });
+
+    it('should count case and catch clauses', () => {
+      const src = `function f(x: string) {
+  try {
+    switch (x) {
+      case 'a': return 1;
+      case 'b': return 2;
+      default: return 0;
+    }
+  } catch {
+    return -1;
+  }
+}`;
+      expect(tsComplexity(src)).toBe(4);
+    });
+
+    it('should be deterministic', () => {
+      const src = 'function f(a: boolean, b: boolean) { return a || b; }';
+      expect(tsComplexity(src)).toBe(tsComplexity(src));
+    });
+  });
+});
@@ -0,0 +1,160 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterAll, beforeAll, describe, expect, it, vi } from 'vitest';
+import { DEFAULT_MAX_DOCUMENT_BYTES, documentSize, limitDocumentSize } from '../document-size';
+import { scanRepository } from '../index';
+import type { Document } from '../types';
+
+function makeDoc(overrides: Partial<Document> = {}, metadata: Partial<Document['metadata']> = {}) {
+  return {
+    id: 'gen/table.go:Table:1',
+    text: 'function Table\nfunc Table() []int',
+    type: 'function',
+    language: 'go',
+    ...overrides,
+    metadata: {
+      file: 'gen/table.go',
+      startLine: 1,
+      endLine: 1,
+      name: 'Table',
+      signature: 'func Table() []int',
+      exported: true,
+      ...metadata,
+    },
+  } as Document;
+}
+
+describe('Document size limits', () => {
+  it('should default to a finite limit', () => {
+    expect(DEFAULT_MAX_DOCUMENT_BYTES).toBe(64 * 1024);
+  });
+
+  it('should count text, snippet, and doc comment in UTF-8 bytes', () => {
+    const doc = makeDoc({ text: 'héllo' }, { snippet: 'abc', docstring: '日本' });
+    expect(documentSize(doc)).toBe(6 + 3 + 6);
+  });
+
+  it('should leave documents within the limit untouched', () => {
+    const doc = makeDoc({}, { snippet: 'func Table() []int { return nil }' });
+    const [limited] = limitDocumentSize([doc], 1024);
+
+    expect(limited).toBe(doc);
+    expect(limited.metadata.overflow).toBeUndefined();
+  });
+
+  describe('code symbols', () => {
+    const snippet = `func Table() []int {\n${'\t1, 2, 3, 4, 5, 6, 7, 8,\n'.repeat(400)}}`;
+    const doc = makeDoc({}, { snippet, docstring: 'Table is generated.' });
+
+    it('should summarize in place within the limit', () => {
+      const limited = limitDocumentSize([doc], 2048);
+
+      expect(limited).toHaveLength(1);
+      expect(limited[0].id).toBe(doc.id);
+      expect(documentSize(limited[0])).toBeLessThanOrEqual(2048);
+      expect(limited[0].metadata.signature).toBe('func Table() []int');
+      expect(limited[0].metadata.docstring).toBe('Table is generated.');
+      expect(limited[0].metadata.snippet).toMatch(/^func Table\(\) \[\]int \{\n/);
+      expect(limited[0].metadata.snippet).toMatch(/\.\.\. \[truncated\]$/);
+      expect(limited[0].metadata.overflow).toEqual({
+        policy: 'summarized',
+        originalBytes: documentSize(doc),
+      });
+    });
+
+    it('should not split multi-byte characters', () => {
+      const wide = makeDoc({}, { snippet: '世界'.repeat(2000) });
+      const [limited] = limitDocumentSize([wide], 1000);
+
+      expect(limited.metadata.snippet).not.toContain('\uFFFD');
+    });
+
+    it('should log a warning naming the document and policy', () => {
+      const logger = { warn: vi.fn() };
+      limitDocumentSize([doc], 2048, logger as never);
+
+      expect(logger.warn).toHaveBeenCalledWith(
+        expect.objectContaining({ file: 'gen/table.go', maxBytes: 2048, policy: 'summarized' }),
+        expect.stringContaining(doc.id)
+      );
+    });
+  });
+
+  describe('documentation', () => {
+    const lines = Array.from({ length: 300 }, (_, i) => `row ${i}: ${'x'.repeat(40)}`);
+    const doc = makeDoc(
+      {
+        id: 'data/rows.txt:rows.txt:1',
+        text: `rows.txt\n\n${lines.join('\n')}`,
+        type: 'documentation',
+        language: 'text',
+      },
+      { file: 'data/rows.txt', name: 'rows.txt', endLine: 300, snippet: lines.join('\n') }
+    );
+
+    it('should chunk on line boundaries into parts within the limit', () => {
+      const parts = limitDocumentSize([doc], 4096);
+
+      expect(parts.length).toBeGreaterThan(1);
+      for (const part of parts) {
+        expect(documentSize(part)).toBeLessThanOrEqual(4096);
+        expect(part.metadata.snippet?.split('\n')[0]).toMatch(/^row \d+: x+$/);
+      }
+      expect(parts.flatMap((part) => part.metadata.snippet?.split('\n'))).toEqual(lines);
+    });
+
+    it('should number the parts and keep their line ranges', () => {
+      const parts = limitDocumentSize([doc], 4096);
+      const second = parts[1];
+
+      expect(second.metadata.name).toBe(`rows.txt (part 2 of ${parts.length})`);
+      expect(second.text.startsWith(`rows.txt (part 2 of ${parts.length})\n\n`)).toBe(true);
+      expect(second.metadata.startLine).toBe(parts[0].metadata.endLine + 1);
+      expect(second.id).toBe(`data/rows.txt:${second.metadata.name}:${second.metadata.startLine}`);
+      expect(second.metadata.overflow).toMatchObject({
+        policy: 'chunked',
+        part: 2,
+        parts: parts.length,
+      });
+      expect(parts.at(-1)?.metadata.endLine).toBe(300);
+    });
+  });
+
+  describe('scanRepository', () => {
+    let repoDir: string;
+
+    beforeAll(async () => {
+      repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'dev-agent-doc-size-'));
+      const rows = Array.from({ length: 200 }, (_, i) => `- item ${i}`).join('\n');
+      await fs.writeFile(path.join(repoDir, 'LIST.md'), `# List\n\n${rows}\n`);
+    });
+
+    afterAll(async () => {
+      await fs.rm(repoDir, { recursive: true, force: true });
+    });
+
+    it('should apply maxDocumentBytes to scanned documents', async () => {
+      const result = await scanRepository({ repoRoot: repoDir, maxDocumentBytes: 1024 });
+
+      const parts = result.documents.filter((d) => d.metadata.overflow?.policy === 'chunked');
+      expect(parts.length).toBeGreaterThan(1);
+      expect(result.documents.every((d) => documentSize(d) <= 1024)).toBe(true);
+      expect(result.stats.documentsExtracted).toBe(result.documents.length);
+    });
+
+    it('should take the limit per file from a function', async () => {
+      const limits: string[] = [];
+      const result = await scanRepository({
+        repoRoot: repoDir,
+        maxDocumentBytes: (file) => {
+          limits.push(file);
+          return 1_000_000;
+        },
+      });
+
+      expect(limits).toContain('LIST.md');
+      expect(result.documents.some((d) => d.metadata.overflow)).toBe(false);
+    });
+  });
+});
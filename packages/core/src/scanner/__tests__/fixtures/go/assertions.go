@@ -0,0 +1,35 @@
+package assertions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// File is an in-memory file.
+type File struct {
+	data []byte
+}
+
+func (f *File) Read(p []byte) (int, error) { return copy(p, f.data), nil }
+
+func (f *File) Close() error { return nil }
+
+// Name is a printable name.
+type Name string
+
+func (n Name) String() string { return string(n) }
+
+// Compile-time checks that the types satisfy their interfaces.
+var (
+	_ io.ReadCloser = (*File)(nil)
+	_ fmt.Stringer  = Name("")
+	_ http.Handler  = http.HandlerFunc(nil)
+)
+
+var _ io.Reader = &File{}
+
+var _ fmt.Stringer = new(Name)
+
+// Not an assertion: a named variable.
+var DefaultName fmt.Stringer = Name("default")
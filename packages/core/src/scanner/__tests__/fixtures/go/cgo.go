@@ -0,0 +1,25 @@
+package native
+
+/*
+#include <stdlib.h>
+
+static int add(int a, int b) { return a + b; }
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Add sums two ints in C.
+func Add(a, b int) int {
+	return int(C.add(C.int(a), C.int(b)))
+}
+
+// Describe formats a sum.
+func Describe(a, b int) string {
+	p := C.CString("x")
+	defer C.free(unsafe.Pointer(p))
+	return fmt.Sprintf("%d", Add(a, b))
+}
@@ -0,0 +1,47 @@
+package closures
+
+import (
+	"slices"
+	"sync"
+)
+
+// Process squares each item in its own goroutine.
+func Process(items []int, scale int) []int {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make([]int, 0, len(items))
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			results = append(results, item*item*scale)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Sorted sorts names by length, counting comparisons.
+func Sorted(names []string) ([]string, int) {
+	compared := 0
+	slices.SortFunc(names, func(a, b string) int {
+		compared++
+		return len(a) - len(b)
+	})
+	return names, compared
+}
+
+type Counter struct {
+	n int
+}
+
+// Incrementer returns a function that adds step to the counter.
+func (c *Counter) Incrementer(step int) func() int {
+	return func() int {
+		c.n += step
+		each := func(n int) int { return n + step }
+		return each(c.n)
+	}
+}
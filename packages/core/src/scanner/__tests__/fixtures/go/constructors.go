@@ -0,0 +1,41 @@
+package constructors
+
+import (
+	"net/http"
+	"os"
+)
+
+// Client talks to the API.
+type Client struct {
+	base string
+}
+
+// New returns a client for the default endpoint.
+func New() Client {
+	return Client{base: "https://api.example.com"}
+}
+
+// NewFromEnv builds a client from environment variables.
+func NewFromEnv() (*Client, error) {
+	return &Client{base: os.Getenv("API_BASE")}, nil
+}
+
+// Open returns a client, with named results.
+func Open(base string) (c *Client, err error) {
+	return &Client{base: base}, nil
+}
+
+// DefaultTransport returns another package's type.
+func DefaultTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport)
+}
+
+// Clients returns a slice, not a constructed value.
+func Clients(bases ...string) []Client {
+	return nil
+}
+
+// Version returns a predeclared type.
+func Version() string {
+	return "1"
+}
@@ -0,0 +1,74 @@
+package fetch
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"os/exec"
+)
+
+// Client fetches pages and stores them.
+type Client struct {
+	db   *sql.DB
+	http *http.Client
+}
+
+// Fetch passes its context to every call that takes one.
+func (c *Client) Fetch(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.save(ctx, url); err != nil {
+		return nil, err
+	}
+	return c.http.Do(req)
+}
+
+// Refresh has a context but starts a fresh one for the save.
+func (c *Client) Refresh(ctx context.Context, url string) error {
+	if err := c.save(context.Background(), url); err != nil {
+		return err
+	}
+	return c.save(nil, url)
+}
+
+// Count has a context but runs the context-less query.
+func (c *Client) Count(ctx context.Context) (int, error) {
+	var n int
+	err := c.db.QueryRow("SELECT count(*) FROM pages").Scan(&n)
+	return n, err
+}
+
+// Ping takes a context and never uses it.
+func Ping(ctx context.Context, url string) error {
+	return nil
+}
+
+// Discard takes a context it means to ignore.
+func Discard(_ context.Context) {}
+
+// Download makes requests without taking a context.
+func Download(url string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return exec.Command("touch", "done").Run()
+}
+
+// Background starts its own context for a save.
+func Background(c *Client) error {
+	return c.save(context.TODO(), "")
+}
+
+func (c *Client) save(ctx context.Context, url string) error {
+	_, err := c.db.ExecContext(ctx, "INSERT INTO pages (url) VALUES ($1)", url)
+	return err
+}
+
+func main() {
+	c := &Client{}
+	_ = c.save(context.Background(), "")
+}
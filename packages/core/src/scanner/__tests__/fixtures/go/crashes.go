@@ -0,0 +1,44 @@
+// Package store has functions that can crash the process.
+package store
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+var registry = map[string]string{}
+
+func init() {
+	if os.Getenv("STORE_DISABLED") != "" {
+		panic("store disabled")
+	}
+}
+
+// MustOpen opens a store or panics.
+func MustOpen(path string) string {
+	if path == "" {
+		panic(fmt.Sprintf("empty path"))
+	}
+	return path
+}
+
+// Load reads the registry and exits on failure.
+func Load() {
+	if len(registry) == 0 {
+		log.Fatalf("empty registry")
+	}
+	go func() {
+		os.Exit(1)
+	}()
+}
+
+// Safe runs fn and recovers from panics.
+func Safe(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("recovered: %v", r)
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: tool <file>")
+	}
+	os.Exit(run(os.Args[1]))
+}
+
+func run(file string) int {
+	return len(file)
+}
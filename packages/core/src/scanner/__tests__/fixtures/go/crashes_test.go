@@ -0,0 +1,13 @@
+package store
+
+import "testing"
+
+func mustLoad(t *testing.T) {
+	if len(registry) == 0 {
+		panic("no fixtures")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	mustLoad(t)
+}
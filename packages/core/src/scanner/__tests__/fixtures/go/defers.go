@@ -0,0 +1,58 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"sync"
+)
+
+type Store struct {
+	mu sync.Mutex
+	db *sql.DB
+}
+
+// Connect opens the database.
+func Connect(dsn string) (*Store, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close releases the database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Count locks the store for the duration of the query.
+func (s *Store) Count() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.db.Query("SELECT count(*) FROM items")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	return 0, nil
+}
+
+// ReadConfig reads a file, recovering from parser panics.
+func ReadConfig(path string) (data []byte, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	defer func() {
+		if r := recover(); r != nil {
+			os.Remove(path)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+	}()
+	return nil, nil
+}
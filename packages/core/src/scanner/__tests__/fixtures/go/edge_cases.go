@@ -117,7 +117,39 @@ type unexportedType struct {
 	field string
 }
 
+// Value is exported but its receiver type is not.
+func (u *unexportedType) Value() string {
+	return u.field
+}
+
 // unexportedFunc should still be detected
 func unexportedFunc() string {
 	return "unexported"
 }
+
+// Packet mixes grouped, embedded, and tagged fields.
+type Packet struct {
+	flag bool
+	*Base
+	io.Reader
+	size, count int64
+	Payload     []byte `json:"payload"`
+}
+
+// ByteSize counts bytes.
+type ByteSize int64
+
+// Sizes shift by iota.
+const (
+	_           = iota
+	KB ByteSize = 1 << (10 * iota)
+	MB
+)
+
+// Delays reference other constants.
+const (
+	BaseDelay    = 250
+	MaxDelay     = BaseDelay * 8
+	Ratio        = -1.5
+	Label string = `raw`
+)
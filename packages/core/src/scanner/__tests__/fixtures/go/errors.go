@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	ErrInvalidEmail  = errors.New("invalid email address")
+	ErrEmptyName     = errors.New("name cannot be empty")
+	ErrShortPassword = errors.New("password must be at least 8 characters")
+)
+
+type User struct {
+	Email string
+	Name  string
+}
+
+type ValidationError struct {
+	Field string
+}
+
+func (e *ValidationError) Error() string { return "invalid " + e.Field }
+
+// ValidateEmail checks if an email address is valid
+func ValidateEmail(email string) error {
+	if email == "" {
+		return ErrInvalidEmail
+	}
+	if !strings.Contains(email, "@") {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// ValidatePassword checks if a password meets requirements
+func ValidatePassword(password string) error {
+	if len(password) < 8 {
+		return ErrShortPassword
+	}
+	return nil
+}
+
+// CreateUser creates a new user with validation
+func CreateUser(email, name, password string) (*User, error) {
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	if err := ValidateEmail(email); err != nil {
+		return nil, fmt.Errorf("email validation failed: %w", err)
+	}
+	if err := ValidatePassword(password); err != nil {
+		return nil, fmt.Errorf("password validation failed: %w", err)
+	}
+	return &User{Email: email, Name: name}, nil
+}
+
+// ReadHeader reads the first line of a file.
+func ReadHeader(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 64)
+	n, err := f.Read(buf)
+	if n == 0 {
+		return "", io.EOF
+	}
+	if err != nil {
+		return "", fmt.Errorf("read %s (%v): %w", path, n, err)
+	}
+	return string(buf[:n]), nil
+}
+
+// Check validates a user's fields.
+func (u *User) Check() error {
+	if u.Name == "" {
+		return &ValidationError{Field: "name"}
+	}
+	walk := func() error {
+		return ErrEmptyName
+	}
+	if err := walk(); err != nil {
+		return errors.Join(ErrInvalidEmail, err)
+	}
+	return nil
+}
+
+// Describe formats a user.
+func Describe(u *User) string {
+	return u.Name
+}
@@ -0,0 +1,49 @@
+package server_test
+
+import (
+	"fmt"
+	"sort"
+
+	"example.com/server"
+)
+
+// Example shows a package-level walkthrough.
+func Example() {
+	fmt.Println("hello")
+	// Output: hello
+}
+
+func ExampleNewServer() {
+	srv := server.NewServer(":8080")
+	fmt.Println(srv.Addr())
+	// Output:
+	// :8080
+}
+
+func ExampleServer_Handle() {
+	srv := server.NewServer(":8080")
+	srv.Handle("/health", nil)
+}
+
+func ExampleServer_Handle_prefix() {
+	srv := server.NewServer(":8080")
+	for _, route := range srv.Routes("/api") {
+		fmt.Println(route)
+	}
+	// Unordered output:
+	// /api/users
+	// /api/orders
+}
+
+func Example_sorting() {
+	names := []string{"b", "a"}
+	sort.Strings(names)
+	fmt.Println(names)
+	// Output: [a b]
+}
+
+// Examplefoo is not an example: the name continues in lower case.
+func Examplefoo() {}
+
+// ExampleWithArgs takes parameters, so go test ignores it.
+func ExampleWithArgs(name string) {}
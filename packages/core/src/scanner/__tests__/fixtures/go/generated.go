@@ -3,8 +3,8 @@
 
 package example
 
-// This file should be skipped by the scanner.
-// Generated files add noise to the index.
+// Generated files are indexed, tagged with their generator, and left out
+// of search unless asked for.
 
 type GeneratedMessage struct {
 	Field1 string
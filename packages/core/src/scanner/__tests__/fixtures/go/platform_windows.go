@@ -0,0 +1,6 @@
+package worker
+
+// doWork runs a job on an I/O completion port.
+func doWork(job string) error {
+	return nil
+}
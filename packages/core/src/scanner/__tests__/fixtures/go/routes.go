@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+)
+
+// Server serves the users API.
+type Server struct {
+	mux *http.ServeMux
+}
+
+// Routes registers the users API.
+func (s *Server) Routes() {
+	s.mux.HandleFunc("/users", s.listUsers)
+	s.mux.HandleFunc("POST /users", s.createUser)
+	s.mux.Handle("GET /users/{id}", http.HandlerFunc(s.getUser))
+	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// NewMux builds the default mux.
+func NewMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	http.Handle("/metrics", metricsHandler())
+	mux.HandleFunc(prefix+"/ignored", ignored)
+	return mux
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request)  {}
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {}
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request)    {}
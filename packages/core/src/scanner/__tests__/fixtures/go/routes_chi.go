@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Router builds the orders API.
+func Router(h *OrderHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", h.Index)
+	r.Route("/orders", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Delete("/", h.Delete)
+		})
+	})
+	r.Method(http.MethodPut, "/orders/{id}", h.Update)
+	return r
+}
@@ -0,0 +1,13 @@
+package api
+
+import "github.com/gin-gonic/gin"
+
+// Register mounts the catalog API.
+func Register(engine *gin.Engine, h *CatalogHandler) {
+	v1 := engine.Group("/api/v1")
+	items := v1.Group("/items")
+	items.GET("/:id", auth, h.GetItem)
+	items.POST("", h.CreateItem)
+	engine.Any("/ping", ping)
+	engine.Handle("PATCH", "/items/:id", h.PatchItem)
+}
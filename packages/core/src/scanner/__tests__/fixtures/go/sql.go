@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// UserStore reads and writes users.
+type UserStore struct {
+	db *sql.DB
+}
+
+// Get loads one user by ID.
+func (s *UserStore) Get(ctx context.Context, id int64) (*User, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT id, email FROM users WHERE id = $1", id)
+	u := &User{}
+	return u, row.Scan(&u.ID, &u.Email)
+}
+
+// ListWithOrders joins users to their orders.
+func (s *UserStore) ListWithOrders(ctx context.Context) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, `
+		SELECT u.id, o.total
+		FROM users u
+		JOIN orders o ON o.user_id = u.id
+		WHERE o.total > $1`, 0)
+}
+
+// Rename updates a user and records it in the audit log.
+func (s *UserStore) Rename(ctx context.Context, id int64, email string) error {
+	if _, err := s.db.ExecContext(ctx, "update users set email = ? where id = ?", email, id); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, "INSERT INTO audit_log (user_id, action) "+
+		"VALUES ($1, 'rename')", id)
+	return err
+}
+
+// Purge deletes rows from a table chosen at runtime.
+func Purge(db *sql.DB, table string) error {
+	_, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE deleted", table))
+	return err
+}
+
+// Describe only mentions SQL keywords in prose.
+func Describe() string {
+	return "select one from the list, then update your settings"
+}
+
+// User is a row of the users table.
+type User struct {
+	ID    int64
+	Email string
+}
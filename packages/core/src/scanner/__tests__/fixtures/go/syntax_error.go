@@ -0,0 +1,15 @@
+package example
+
+// Healthy is declared before the syntax error.
+func Healthy() string {
+	return "ok"
+}
+
+// Broken is missing its closing brace mid-refactor.
+func Broken(items []string) int {
+	for _, item := range items {
+		if item == "" {
+			return 0
+	}
+	return len(items)
+}
@@ -0,0 +1,89 @@
+import { describe, expect, it } from 'vitest';
+import { matchesGoPlatform, parseGoBuildConstraint, resolveGoPlatform } from '../go-build';
+
+describe('Go build constraints', () => {
+  describe('parseGoBuildConstraint', () => {
+    it('should read the //go:build line of the header', () => {
+      const source = [
+        '// Copyright 2024 The Authors.',
+        '',
+        '//go:build (linux || darwin) && !arm',
+        '',
+        'package poll',
+      ].join('\n');
+
+      expect(parseGoBuildConstraint(source, 'poll/fd.go')).toBe('(linux || darwin) && !arm');
+    });
+
+    it('should rewrite // +build lines', () => {
+      const source = '// +build linux,amd64 darwin\n// +build cgo\n\npackage poll\n';
+
+      expect(parseGoBuildConstraint(source, 'poll/fd.go')).toBe(
+        '(linux && amd64 || darwin) && cgo'
+      );
+    });
+
+    it('should add the GOOS and GOARCH of the file name', () => {
+      const source = 'package poll\n';
+
+      expect(parseGoBuildConstraint(source, 'poll/fd_windows.go')).toBe('windows');
+      expect(parseGoBuildConstraint(source, 'poll/asm_linux_amd64.go')).toBe('linux && amd64');
+      expect(parseGoBuildConstraint(source, 'poll/asm_arm64.go')).toBe('arm64');
+      expect(parseGoBuildConstraint(source, 'poll/fd_windows_test.go')).toBe('windows');
+      const withBuildLine = '//go:build cgo || race\n\npackage poll\n';
+      expect(parseGoBuildConstraint(withBuildLine, 'fd_linux.go')).toBe('(cgo || race) && linux');
+    });
+
+    it('should leave files built everywhere unconstrained', () => {
+      expect(parseGoBuildConstraint('package poll\n', 'poll/fd.go')).toBeUndefined();
+      expect(parseGoBuildConstraint('package poll\n', 'poll/linux.go')).toBeUndefined();
+      expect(parseGoBuildConstraint('package poll\n', 'poll/fd_unix.go')).toBeUndefined();
+      // Build lines after the package clause are only comments
+      expect(parseGoBuildConstraint('package poll\n\n//go:build linux\n', 'fd.go')).toBeUndefined();
+    });
+  });
+
+  describe('matchesGoPlatform', () => {
+    const linux = { goos: 'linux', goarch: 'amd64' };
+
+    it('should evaluate GOOS, GOARCH, and operators', () => {
+      expect(matchesGoPlatform('linux', linux)).toBe(true);
+      expect(matchesGoPlatform('windows', linux)).toBe(false);
+      expect(matchesGoPlatform('(linux || darwin) && !arm', linux)).toBe(true);
+      expect(matchesGoPlatform('linux && arm64', linux)).toBe(false);
+      expect(matchesGoPlatform('!windows && !plan9', linux)).toBe(true);
+    });
+
+    it('should satisfy unix, release, and configured tags', () => {
+      expect(matchesGoPlatform('unix', linux)).toBe(true);
+      expect(matchesGoPlatform('unix', { goos: 'windows', goarch: 'amd64' })).toBe(false);
+      expect(matchesGoPlatform('go1.21 && cgo', linux)).toBe(true);
+      expect(matchesGoPlatform('linux', { goos: 'android', goarch: 'arm64' })).toBe(true);
+      expect(matchesGoPlatform('integration', linux)).toBe(false);
+      expect(matchesGoPlatform('integration', { ...linux, tags: ['integration'] })).toBe(true);
+    });
+
+    it('should treat a malformed constraint as satisfied', () => {
+      expect(matchesGoPlatform('linux &&', { goos: 'windows', goarch: 'amd64' })).toBe(true);
+      expect(matchesGoPlatform('(linux', { goos: 'windows', goarch: 'amd64' })).toBe(true);
+    });
+  });
+
+  describe('resolveGoPlatform', () => {
+    it('should prefer configured values, then GOOS and GOARCH', () => {
+      expect(resolveGoPlatform({ goos: 'windows' }, { GOOS: 'darwin', GOARCH: 'arm64' })).toEqual({
+        goos: 'windows',
+        goarch: 'arm64',
+        tags: [],
+      });
+    });
+
+    it('should fall back to the host with Go names', () => {
+      const host = resolveGoPlatform({}, {});
+
+      expect(host.goos).not.toBe('win32');
+      expect(host.goarch).not.toBe('x64');
+      expect(host.goos.length).toBeGreaterThan(0);
+    });
+  });
+});
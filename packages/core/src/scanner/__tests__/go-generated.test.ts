@@ -0,0 +1,39 @@
+import { describe, expect, it } from 'vitest';
+import { parseGeneratedHeader } from '../go-generated';
+
+describe('parseGeneratedHeader', () => {
+  it('should read the generator from the header', () => {
+    const header = (text: string) => parseGeneratedHeader(`${text}\n\npackage store\n`);
+
+    expect(header('// Code generated by MockGen. DO NOT EDIT.')).toEqual({ generator: 'MockGen' });
+    expect(header('// Code generated by "stringer -type=Pill"; DO NOT EDIT.')).toEqual({
+      generator: 'stringer -type=Pill',
+    });
+    expect(
+      parseGeneratedHeader('// Code generated by github.com/golang/mock/mockgen. DO NOT EDIT.\n')
+    ).toEqual({ generator: 'github.com/golang/mock/mockgen' });
+  });
+
+  it('should accept headers without a generator, after other comments', () => {
+    const source = [
+      '// Copyright 2024 The Authors.',
+      '',
+      '//go:build linux',
+      '',
+      '// Code generated DO NOT EDIT.',
+      '',
+      'package store',
+    ].join('\n');
+
+    expect(parseGeneratedHeader(source)).toEqual({});
+  });
+
+  it('should ignore hand-written files and headers after the package clause', () => {
+    expect(parseGeneratedHeader('package store\n\nfunc Get() {}\n')).toBeNull();
+    expect(
+      parseGeneratedHeader('package store\n\n// Code generated by mockery. DO NOT EDIT.\n')
+    ).toBeNull();
+    // Only the exact form counts
+    expect(parseGeneratedHeader('// Code generated by hand, edit freely.\npackage p\n')).toBeNull();
+  });
+});
@@ -0,0 +1,309 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterAll, beforeAll, describe, expect, it } from 'vitest';
+import { GoScanner } from '../go';
+import {
+  canImportInternal,
+  compareModuleVersions,
+  findGoModules,
+  findOwningModule,
+  type GoModule,
+  goVersionAtLeast,
+  parseGoDependencies,
+  parseGoModulePath,
+  parseGoVersion,
+  resolveGoDependency,
+  resolveGoImport,
+} from '../go-modules';
+
+describe('Go modules', () => {
+  const modules: GoModule[] = [
+    { path: 'github.com/acme/mono', dir: '.' },
+    { path: 'github.com/acme/mono/api', dir: 'api' },
+    { path: 'github.com/acme/tools', dir: 'tools' },
+  ];
+
+  describe('parseGoModulePath', () => {
+    it('should parse the module directive', () => {
+      expect(parseGoModulePath('module github.com/acme/api\n\ngo 1.22\n')).toBe(
+        'github.com/acme/api'
+      );
+    });
+
+    it('should handle quoted paths and trailing comments', () => {
+      expect(parseGoModulePath('module "example.com/m" // main module\n')).toBe('example.com/m');
+    });
+
+    it('should return null without a module directive', () => {
+      expect(parseGoModulePath('go 1.22\n')).toBeNull();
+    });
+  });
+
+  describe('parseGoVersion', () => {
+    it('should parse minor and patch-level versions', () => {
+      expect(parseGoVersion('module example.com/m\n\ngo 1.21\n')).toBe('1.21');
+      expect(parseGoVersion('module example.com/m\n\ngo 1.22.3 // pinned\n')).toBe('1.22.3');
+      expect(parseGoVersion('go 1.21rc1\n')).toBe('1.21rc1');
+    });
+
+    it('should ignore the toolchain directive and malformed versions', () => {
+      expect(parseGoVersion('module m\n\ntoolchain go1.22.3\n')).toBeNull();
+      expect(parseGoVersion('module m\n\ngo latest\n')).toBeNull();
+    });
+  });
+
+  describe('goVersionAtLeast', () => {
+    it('should compare major, minor, and patch', () => {
+      expect(goVersionAtLeast('1.22.3', '1.22')).toBe(true);
+      expect(goVersionAtLeast('1.21.9', '1.22')).toBe(false);
+      expect(goVersionAtLeast('1.22', '1.22.1')).toBe(false);
+      expect(goVersionAtLeast('1.22rc1', '1.22')).toBe(true);
+      expect(goVersionAtLeast('go1.23', '1.22')).toBe(true);
+    });
+  });
+
+  describe('parseGoDependencies', () => {
+    const goMod = [
+      'module github.com/acme/api',
+      '',
+      'go 1.22',
+      '',
+      'require (',
+      '\tgithub.com/gorilla/mux v1.8.1',
+      '\t"github.com/pkg/errors" v0.9.1',
+      '\tgolang.org/x/net v0.17.0 // indirect',
+      ')',
+      '',
+      'require github.com/acme/lib v1.2.0',
+      '',
+      'replace golang.org/x/net => golang.org/x/net v0.23.0',
+      '',
+      'replace (',
+      '\tgithub.com/acme/lib => ../lib',
+      '\tgithub.com/pkg/errors v0.8.0 => github.com/fork/errors v0.8.1',
+      ')',
+    ].join('\n');
+
+    it('should read direct and indirect requirements in block and line form', () => {
+      const dependencies = parseGoDependencies(goMod);
+
+      expect(dependencies.map((d) => [d.path, d.indirect])).toEqual([
+        ['github.com/acme/lib', false],
+        ['github.com/gorilla/mux', false],
+        ['github.com/pkg/errors', false],
+        ['golang.org/x/net', true],
+      ]);
+      expect(dependencies[1]).toEqual({
+        path: 'github.com/gorilla/mux',
+        version: 'v1.8.1',
+        indirect: false,
+      });
+    });
+
+    it('should report the effective version of replaced modules', () => {
+      const dependencies = parseGoDependencies(goMod);
+
+      expect(dependencies.find((d) => d.path === 'golang.org/x/net')).toEqual({
+        path: 'golang.org/x/net',
+        version: 'v0.23.0',
+        indirect: true,
+        replacedBy: { path: 'golang.org/x/net', version: 'v0.23.0' },
+        requiredVersion: 'v0.17.0',
+      });
+      // A directory replacement keeps the required version
+      expect(dependencies.find((d) => d.path === 'github.com/acme/lib')).toEqual({
+        path: 'github.com/acme/lib',
+        version: 'v1.2.0',
+        indirect: false,
+        replacedBy: { path: '../lib' },
+      });
+      // A replace for another version doesn't apply
+      expect(dependencies.find((d) => d.path === 'github.com/pkg/errors')?.replacedBy).toBe(
+        undefined
+      );
+    });
+
+    it('should add modules only in go.sum before Go 1.17', () => {
+      const goSum = [
+        'github.com/a/b v1.0.0 h1:aaa=',
+        'github.com/c/d v1.2.0 h1:bbb=',
+        'github.com/c/d v1.10.0 h1:ccc=',
+        'github.com/c/d v1.11.0/go.mod h1:ddd=',
+        'github.com/e/f v0.1.0/go.mod h1:eee=',
+      ].join('\n');
+      const legacy = 'module m\n\ngo 1.16\n\nrequire github.com/a/b v1.0.0\n';
+
+      expect(parseGoDependencies(legacy, goSum)).toEqual([
+        { path: 'github.com/a/b', version: 'v1.0.0', indirect: false },
+        { path: 'github.com/c/d', version: 'v1.10.0', indirect: true },
+      ]);
+      expect(parseGoDependencies(legacy.replace('1.16', '1.21'), goSum)).toHaveLength(1);
+    });
+  });
+
+  describe('compareModuleVersions', () => {
+    it('should order by semver precedence', () => {
+      expect(compareModuleVersions('v1.10.0', 'v1.9.2')).toBeGreaterThan(0);
+      expect(compareModuleVersions('v1.2.0-rc.1', 'v1.2.0')).toBeLessThan(0);
+      expect(
+        compareModuleVersions('v0.0.0-20230101000000-abcdef', 'v0.0.0-20240101000000-123456')
+      ).toBeLessThan(0);
+      expect(compareModuleVersions('v2.0.0+incompatible', 'v1.9.0')).toBeGreaterThan(0);
+      expect(compareModuleVersions('v1.2.3', 'v1.2.3')).toBe(0);
+    });
+  });
+
+  describe('resolveGoDependency', () => {
+    it('should resolve against the longest matching dependency path', () => {
+      const module: GoModule = {
+        path: 'github.com/acme/api',
+        dir: '.',
+        dependencies: [
+          { path: 'github.com/acme/kit', version: 'v1.0.0', indirect: false },
+          { path: 'github.com/acme/kit/v2', version: 'v2.1.0', indirect: false },
+        ],
+      };
+
+      expect(resolveGoDependency('github.com/acme/kit/v2/log', module)?.version).toBe('v2.1.0');
+      expect(resolveGoDependency('github.com/acme/kit/log', module)?.version).toBe('v1.0.0');
+      expect(resolveGoDependency('github.com/acme/kitchen', module)).toBeNull();
+      expect(resolveGoDependency('fmt', module)).toBeNull();
+    });
+  });
+
+  describe('findOwningModule', () => {
+    it('should pick the nearest enclosing module', () => {
+      expect(findOwningModule('api/handlers/user.go', modules)?.path).toBe(
+        'github.com/acme/mono/api'
+      );
+      expect(findOwningModule('internal/db/db.go', modules)?.path).toBe('github.com/acme/mono');
+      expect(findOwningModule('tools/gen/main.go', modules)?.path).toBe('github.com/acme/tools');
+    });
+
+    it('should not match directories that only share a prefix', () => {
+      expect(findOwningModule('apis/x.go', modules)?.path).toBe('github.com/acme/mono');
+    });
+
+    it('should return null outside any module', () => {
+      expect(findOwningModule('x.go', [{ path: 'a', dir: 'sub' }])).toBeNull();
+    });
+  });
+
+  describe('resolveGoImport', () => {
+    it('should resolve against the longest matching module path', () => {
+      const resolved = resolveGoImport('github.com/acme/mono/api/handlers', modules);
+      expect(resolved?.module.dir).toBe('api');
+      expect(resolved?.dir).toBe('api/handlers');
+    });
+
+    it('should resolve root module packages', () => {
+      expect(resolveGoImport('github.com/acme/mono/internal/db', modules)?.dir).toBe(
+        'internal/db'
+      );
+      expect(resolveGoImport('github.com/acme/tools', modules)?.dir).toBe('tools');
+    });
+
+    it('should return null for stdlib and external imports', () => {
+      expect(resolveGoImport('fmt', modules)).toBeNull();
+      expect(resolveGoImport('github.com/acme/monolith', modules)).toBeNull();
+    });
+  });
+
+  describe('canImportInternal', () => {
+    it('should allow importers within the parent of internal', () => {
+      expect(canImportInternal('svc/api', 'svc/internal/db')).toBe(true);
+      expect(canImportInternal('svc', 'svc/internal/db')).toBe(true);
+      expect(canImportInternal('svc/internal/cache', 'svc/internal/db')).toBe(true);
+    });
+
+    it('should reject importers outside that tree', () => {
+      expect(canImportInternal('web', 'svc/internal/db')).toBe(false);
+      expect(canImportInternal('svcx', 'svc/internal/db')).toBe(false);
+      expect(canImportInternal('.', 'svc/internal/db')).toBe(false);
+    });
+
+    it('should use the last internal element when nested', () => {
+      expect(canImportInternal('a/internal/b', 'a/internal/b/internal/c')).toBe(true);
+      expect(canImportInternal('a', 'a/internal/b/internal/c')).toBe(false);
+    });
+
+    it('should make top-level internal packages visible repository-wide', () => {
+      expect(canImportInternal('cmd/server', 'internal/db')).toBe(true);
+      expect(canImportInternal('api', 'pkg/store')).toBe(true);
+    });
+  });
+
+  describe('with a multi-module repository', () => {
+    let repoDir: string;
+
+    beforeAll(async () => {
+      repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'dev-agent-gomod-'));
+      await fs.mkdir(path.join(repoDir, 'api'), { recursive: true });
+      await fs.mkdir(path.join(repoDir, 'internal', 'db'), { recursive: true });
+      await fs.writeFile(path.join(repoDir, 'go.mod'), 'module github.com/acme/mono\n\ngo 1.22\n');
+      await fs.writeFile(
+        path.join(repoDir, 'api', 'go.mod'),
+        'module github.com/acme/mono/api\n\ngo 1.22.3\n\n' +
+          'require github.com/gorilla/mux v1.8.1\n\n' +
+          'replace github.com/gorilla/mux => github.com/acme/mux v1.8.2\n'
+      );
+      await fs.writeFile(
+        path.join(repoDir, 'internal', 'db', 'db.go'),
+        'package db\n\n// Open opens the database.\nfunc Open() error { return nil }\n'
+      );
+      await fs.writeFile(
+        path.join(repoDir, 'api', 'server.go'),
+        'package api\n\nimport (\n\t"fmt"\n\tdb "github.com/acme/mono/internal/db"\n' +
+          '\t"github.com/gorilla/mux"\n)\n\n' +
+          'func Serve() { fmt.Println(db.Open(), mux.NewRouter()) }\n'
+      );
+    });
+
+    afterAll(async () => {
+      await fs.rm(repoDir, { recursive: true, force: true });
+    });
+
+    it('should find every go.mod', async () => {
+      expect(await findGoModules(repoDir)).toEqual([
+        { path: 'github.com/acme/mono', dir: '.', goVersion: '1.22' },
+        {
+          path: 'github.com/acme/mono/api',
+          dir: 'api',
+          goVersion: '1.22.3',
+          dependencies: [
+            {
+              path: 'github.com/gorilla/mux',
+              version: 'v1.8.2',
+              indirect: false,
+              replacedBy: { path: 'github.com/acme/mux', version: 'v1.8.2' },
+              requiredVersion: 'v1.8.1',
+            },
+          ],
+        },
+      ]);
+    });
+
+    it('should record the owning module and imports on documents', async () => {
+      const scanner = new GoScanner();
+      const docs = await scanner.scan(['api/server.go', 'internal/db/db.go'], repoDir);
+
+      const serve = docs.find((d) => d.metadata.name === 'Serve');
+      expect(serve?.metadata.module).toBe('github.com/acme/mono/api');
+      expect(serve?.metadata.goVersion).toBe('1.22.3');
+      expect(serve?.metadata.imports).toEqual([
+        'fmt',
+        'github.com/acme/mono/internal/db',
+        'github.com/gorilla/mux',
+      ]);
+      expect(serve?.metadata.dependencies).toEqual([
+        { module: 'github.com/gorilla/mux', version: 'v1.8.2', replaced: true },
+      ]);
+
+      const open = docs.find((d) => d.metadata.name === 'Open');
+      expect(open?.metadata.module).toBe('github.com/acme/mono');
+      expect(open?.metadata.imports).toBeUndefined();
+      expect(open?.metadata.dependencies).toBeUndefined();
+    });
+  });
+});
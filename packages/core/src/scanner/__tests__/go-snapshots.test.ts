@@ -0,0 +1,49 @@
+import * as fs from 'node:fs';
+import * as path from 'node:path';
+import { describe, expect, it } from 'vitest';
+import { GoScanner } from '../go';
+import type { Document } from '../types';
+
+/**
+ * Golden-file snapshots of everything the Go scanner extracts from each
+ * fixture, so a change to any metadata field shows up as a diff.
+ *
+ * After an intended change, rewrite them with `pnpm test:update-snapshots`
+ * and review the diff of `__snapshots__/go/` like any other code change.
+ */
+describe('GoScanner snapshots', () => {
+  const scanner = new GoScanner();
+  const fixturesDir = path.join(__dirname, 'fixtures', 'go');
+  const fixtures = fs
+    .readdirSync(fixturesDir)
+    .filter((file) => file.endsWith('.go'))
+    .sort();
+
+  it.each(fixtures)('should match the snapshot for %s', async (fixture) => {
+    const documents = await scanner.scan([fixture], fixturesDir);
+
+    await expect(serialize(documents, fixturesDir)).toMatchFileSnapshot(
+      path.join(__dirname, '__snapshots__', 'go', `${fixture}.json`)
+    );
+  });
+});
+
+/** ISO timestamps, e.g. blame dates */
+const TIMESTAMP = /\b\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})\b/g;
+
+/**
+ * Documents as stable JSON: ordered by ID, with machine-specific paths and
+ * timestamps replaced by placeholders
+ */
+function serialize(documents: Document[], root: string): string {
+  const sorted = [...documents].sort((a, b) => a.id.localeCompare(b.id));
+  const json = JSON.stringify(
+    sorted,
+    (_key, value) =>
+      typeof value === 'string'
+        ? value.split(root).join('<fixtures>').replace(TIMESTAMP, '<timestamp>')
+        : value,
+    2
+  );
+  return `${json}\n`;
+}
@@ -1,7 +1,8 @@
 import * as path from 'node:path';
 import { beforeAll, describe, expect, it } from 'vitest';
+import { InMemoryFileSystem, NodeFileSystemValidator } from '../../utils/file-validator';
 import { GoScanner } from '../go';
-import type { Document } from '../types';
+import type { Document, ScanError } from '../types';
 
 describe('GoScanner', () => {
   const scanner = new GoScanner();
@@ -88,6 +89,14 @@ describe('GoScanner', () => {
         expect(start?.metadata.signature).toContain('ctx context.Context');
         expect(start?.metadata.signature).toContain('error');
       });
+
+      it('should include cyclomatic complexity', () => {
+        const functions = simpleDocuments.filter((d) => d.type === 'function');
+        expect(functions.length).toBeGreaterThan(0);
+        for (const fn of functions) {
+          expect(fn.metadata.complexity).toBeGreaterThanOrEqual(1);
+        }
+      });
     });
 
     describe('structs', () => {
@@ -115,6 +124,22 @@ describe('GoScanner', () => {
         expect(config?.metadata.snippet).toContain('Host');
         expect(config?.metadata.snippet).toContain('Port');
       });
+
+      it('should record struct fields with visibility', () => {
+        const server = simpleDocuments.find(
+          (d) => d.metadata.name === 'Server' && d.type === 'class'
+        );
+        expect(server?.metadata.custom?.fields).toEqual([
+          { name: 'config', type: '*Config', exported: false, embedded: false },
+          { name: 'running', type: 'bool', exported: false, embedded: false },
+        ]);
+
+        const config = simpleDocuments.find(
+          (d) => d.metadata.name === 'Config' && d.type === 'class'
+        );
+        const fields = config?.metadata.custom?.fields as Array<{ exported: boolean }>;
+        expect(fields.every((f) => f.exported)).toBe(true);
+      });
     });
 
     describe('interfaces', () => {
@@ -168,6 +193,8 @@ describe('GoScanner', () => {
         expect(maxRetries).toBeDefined();
         expect(maxRetries?.metadata.exported).toBe(true);
         expect(maxRetries?.metadata.custom?.isConstant).toBe(true);
+        expect(maxRetries?.metadata.constantType).toBe('untyped int');
+        expect(maxRetries?.metadata.constantValue).toBe('3');
       });
 
       it('should not extract unexported constants', () => {
@@ -218,9 +245,20 @@ describe('GoScanner', () => {
     });
 
     describe('generated files', () => {
-      it('should skip generated files', async () => {
+      it('should tag documents of generated files with their generator', async () => {
         const generatedDocs = await scanner.scan(['generated.go'], fixturesDir);
-        expect(generatedDocs).toHaveLength(0);
+
+        expect(generatedDocs.map((d) => d.metadata.name)).toEqual(
+          expect.arrayContaining(['GeneratedMessage', 'NewGeneratedMessage'])
+        );
+        for (const doc of generatedDocs) {
+          expect(doc.metadata.generated).toBe(true);
+          expect(doc.metadata.generator).toBe('protoc-gen-go');
+        }
+      });
+
+      it('should leave hand-written files untagged', () => {
+        expect(simpleDocuments.some((d) => d.metadata.generated)).toBe(false);
       });
     });
 
@@ -353,6 +391,34 @@ describe('GoScanner', () => {
         expect(ordered?.metadata.custom?.isGeneric).toBeUndefined();
       });
     });
+
+    describe('constraints', () => {
+      const find = (name: string) => genericsDocuments.find((d) => d.metadata.name === name);
+
+      it('should capture the type set of constraint interfaces', () => {
+        const ordered = find('Ordered');
+        expect(ordered?.metadata.typeSet?.terms).toHaveLength(13);
+        expect(ordered?.metadata.typeSet).toMatchObject({ comparable: false });
+        expect(ordered?.metadata.typeSet?.terms.slice(0, 2)).toEqual(['~int', '~int8']);
+        expect(ordered?.text).toContain('constraint interface Ordered');
+        expect(find('Integer')?.metadata.typeSet).toEqual({
+          terms: ['~int', '~int64'],
+          comparable: false,
+        });
+        expect(find('Key')?.metadata.typeSet).toEqual({ terms: [], comparable: true });
+        expect(find('Comparable')?.metadata.typeSet).toBeUndefined();
+      });
+
+      it('should record the named constraints of generic functions and types', () => {
+        expect(find('Min')?.metadata.typeConstraints).toEqual(['Ordered']);
+        expect(find('Tree')?.metadata.typeConstraints).toEqual(['Ordered']);
+        expect(find('MaxBy')?.metadata.typeConstraints).toEqual(['Comparable']);
+        expect(find('Index')?.metadata.typeConstraints).toEqual(['Key', 'Integer']);
+        expect(find('Min')?.text).toContain('constrained by Ordered');
+        expect(find('Map')?.metadata.typeConstraints).toBeUndefined();
+        expect(find('Pair')?.metadata.typeConstraints).toBeUndefined();
+      });
+    });
   });
 
   describe('edge cases', () => {
@@ -387,6 +453,26 @@ describe('GoScanner', () => {
         expect(extended).toBeDefined();
         expect(extended?.metadata.snippet).toContain('Base');
       });
+
+      it('should record fields in declaration order with embeds and tags', () => {
+        const packet = edgeCaseDocuments.find(
+          (d) => d.metadata.name === 'Packet' && d.type === 'class'
+        );
+        expect(packet?.metadata.custom?.fields).toEqual([
+          { name: 'flag', type: 'bool', exported: false, embedded: false },
+          { name: 'Base', type: '*Base', exported: true, embedded: true },
+          { name: 'Reader', type: 'io.Reader', exported: true, embedded: true },
+          { name: 'size', type: 'int64', exported: false, embedded: false },
+          { name: 'count', type: 'int64', exported: false, embedded: false },
+          {
+            name: 'Payload',
+            type: '[]byte',
+            exported: true,
+            embedded: false,
+            tag: 'json:"payload"',
+          },
+        ]);
+      });
     });
 
     describe('multiple declarations', () => {
@@ -409,6 +495,49 @@ describe('GoScanner', () => {
         );
         expect(dayConsts.length).toBeGreaterThanOrEqual(1);
       });
+
+      it('should record types and values of grouped constants', () => {
+        const constant = (name: string) =>
+          edgeCaseDocuments.find((d) => d.metadata.name === name && d.metadata.custom?.isConstant);
+
+        expect(constant('StatusRunning')?.metadata).toMatchObject({
+          startLine: 66,
+          signature: 'const StatusRunning  = "running"',
+          constantType: 'untyped string',
+          constantValue: '"running"',
+        });
+        expect(constant('Wednesday')?.metadata).toMatchObject({
+          constantType: 'untyped int',
+          constantValue: '3',
+        });
+        expect(constant('Ratio')?.metadata).toMatchObject({
+          constantType: 'untyped float',
+          constantValue: '-1.5',
+        });
+        expect(constant('Label')?.metadata).toMatchObject({
+          constantType: 'string',
+          constantValue: '`raw`',
+        });
+      });
+
+      it('should keep expressions that are not plain literals', () => {
+        const constant = (name: string) =>
+          edgeCaseDocuments.find((d) => d.metadata.name === name && d.metadata.custom?.isConstant);
+
+        expect(constant('MaxDelay')?.metadata).toMatchObject({
+          constantType: 'untyped',
+          constantExpression: 'BaseDelay * 8',
+        });
+        expect(constant('MaxDelay')?.metadata.constantValue).toBeUndefined();
+
+        // MB repeats KB's type and expression with the next iota
+        expect(constant('MB')?.metadata).toMatchObject({
+          constantType: 'ByteSize',
+          constantExpression: '1 << (10 * iota)',
+          custom: { isConstant: true, iota: 2 },
+        });
+        expect(constant('_')).toBeUndefined();
+      });
     });
 
     describe('function variations', () => {
@@ -461,6 +590,14 @@ describe('GoScanner', () => {
         expect(unexported).toBeDefined();
         expect(unexported?.metadata.exported).toBe(false);
       });
+
+      it('should mark methods on unexported types as unexported', () => {
+        const value = edgeCaseDocuments.find(
+          (d) => d.metadata.name === 'unexportedType.Value' && d.type === 'method'
+        );
+        expect(value).toBeDefined();
+        expect(value?.metadata.exported).toBe(false);
+      });
     });
 
     describe('interface implementations', () => {
@@ -477,4 +614,792 @@ describe('GoScanner', () => {
       });
     });
   });
+
+  describe('syntax errors', () => {
+    it('should report the error and keep symbols it could recover', async () => {
+      const errors: ScanError[] = [];
+      const docs = await scanner.scan(
+        ['syntax_error.go', 'simple.go'],
+        fixturesDir,
+        undefined,
+        undefined,
+        (error) => errors.push(error)
+      );
+
+      expect(errors).toEqual([
+        expect.objectContaining({ file: 'syntax_error.go', phase: 'parse' }),
+      ]);
+      expect(errors[0].error).toMatch(/^Syntax error/);
+      expect(errors[0].line).toBeGreaterThan(0);
+
+      const healthy = docs.find((d) => d.metadata.name === 'Healthy');
+      expect(healthy).toBeDefined();
+      expect(healthy?.metadata.parseError).toBe(errors[0].error);
+
+      // Other files are unaffected
+      const simple = docs.filter((d) => d.metadata.file === 'simple.go');
+      expect(simple.length).toBeGreaterThan(0);
+      expect(simple.every((d) => d.metadata.parseError === undefined)).toBe(true);
+    });
+  });
+
+  describe('in-memory files', () => {
+    it('should scan content that is not on disk', async () => {
+      const fs = new InMemoryFileSystem(
+        {
+          'go.mod': 'module example.com/shop\n\ngo 1.22\n',
+          'cart/cart.go': 'package cart\n\n// Total sums the cart.\nfunc Total() {}\n',
+        },
+        '/virtual'
+      );
+      const documents = await new GoScanner(fs).scan(['cart/cart.go'], '/virtual');
+
+      expect(documents).toHaveLength(1);
+      expect(documents[0].metadata).toMatchObject({
+        file: 'cart/cart.go',
+        name: 'Total',
+        docstring: 'Total sums the cart.',
+        module: 'example.com/shop',
+        goVersion: '1.22',
+      });
+    });
+
+    it('should prefer unsaved buffers over the files on disk', async () => {
+      const fs = new InMemoryFileSystem(
+        { 'simple.go': 'package main\n\nfunc Unsaved() {}\n' },
+        fixturesDir,
+        new NodeFileSystemValidator()
+      );
+      const documents = await new GoScanner(fs).scan(['simple.go', 'methods.go'], fixturesDir);
+      const names = documents.map((d) => d.metadata.name);
+
+      expect(names).toContain('Unsaved');
+      expect(names.some((name) => name?.includes('.'))).toBe(true);
+    });
+  });
+
+  describe('package docs', () => {
+    it('should share the package doc with every file of the package', async () => {
+      const fs = new InMemoryFileSystem(
+        {
+          'cart/doc.go':
+            '// Copyright 2024 Shop Authors.\n\n// Package cart totals carts.\n//\n' +
+            '// Details follow.\npackage cart\n\nfunc Documented() {}\n',
+          'cart/cart.go': 'package cart\n\nfunc Total() {}\n',
+          'other/other.go': 'package other\n\nfunc Other() {}\n',
+        },
+        '/virtual'
+      );
+      const documents = await new GoScanner(fs).scan(
+        ['cart/doc.go', 'cart/cart.go', 'other/other.go'],
+        '/virtual'
+      );
+      const byName = (name: string) => documents.find((d) => d.metadata.name === name)?.metadata;
+
+      expect(byName('Documented')?.packageDoc).toBe('Package cart totals carts.');
+      expect(byName('Total')).toMatchObject({
+        packageName: 'cart',
+        packageDoc: 'Package cart totals carts.',
+      });
+      expect(byName('Other')?.packageName).toBe('other');
+      expect(byName('Other')?.packageDoc).toBeUndefined();
+    });
+
+    it('should ignore comments that are not package docs', async () => {
+      const documents = await scanner.scan(['generics.go', 'edge_cases.go'], fixturesDir);
+      const inPackage = (name: string) =>
+        documents.find((d) => d.metadata.packageName === name)?.metadata;
+
+      expect(inPackage('generics')?.packageDoc).toBeUndefined();
+      expect(inPackage('edgecases')?.packageDoc).toBe(
+        'Package edgecases tests various Go edge cases for the scanner.'
+      );
+    });
+  });
+
+  describe('cgo', () => {
+    let cgoDocuments: Document[];
+    let pureDocuments: Document[];
+
+    beforeAll(async () => {
+      cgoDocuments = await scanner.scan(['cgo.go'], fixturesDir);
+      pureDocuments = await scanner.scan(['simple.go'], fixturesDir);
+    });
+
+    it('should flag every document from a cgo file', () => {
+      expect(cgoDocuments.length).toBeGreaterThan(0);
+      expect(cgoDocuments.every((d) => d.metadata.usesCgo === true)).toBe(true);
+      expect(pureDocuments.every((d) => d.metadata.usesCgo === false)).toBe(true);
+    });
+
+    it('should leave the C pseudo-package out of imports', () => {
+      const add = cgoDocuments.find((d) => d.metadata.name === 'Add');
+      expect(add?.metadata.imports).toEqual(['fmt', 'unsafe']);
+    });
+
+    it('should skip calls into C', () => {
+      const describeFn = cgoDocuments.find((d) => d.metadata.name === 'Describe');
+      const names = describeFn?.metadata.callees?.map((c) => c.name) ?? [];
+
+      expect(names).toEqual(expect.arrayContaining(['unsafe.Pointer', 'fmt.Sprintf', 'Add']));
+      expect(names.some((name) => name.startsWith('C.'))).toBe(false);
+    });
+
+    it('should capture the C preamble as text', () => {
+      const preamble = cgoDocuments.find((d) => d.metadata.name === 'C');
+
+      expect(preamble?.type).toBe('documentation');
+      expect(preamble?.metadata.startLine).toBe(3);
+      expect(preamble?.metadata.endLine).toBe(7);
+      expect(preamble?.metadata.snippet).toContain('#include <stdlib.h>');
+      expect(preamble?.metadata.snippet).toContain('static int add(int a, int b)');
+    });
+  });
+
+  describe('crash calls', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(
+        ['crashes.go', 'crashes_main.go', 'crashes_test.go'],
+        fixturesDir
+      );
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record panic, log.Fatal, and os.Exit calls with their lines', () => {
+      expect(find('MustOpen')?.metadata.crashes).toEqual([
+        { kind: 'panic', call: 'panic', line: 21 },
+      ]);
+      // The goroutine's os.Exit counts toward the enclosing function
+      expect(find('Load')?.metadata.crashes).toEqual([
+        { kind: 'fatal', call: 'log.Fatalf', line: 29 },
+        { kind: 'exit', call: 'os.Exit', line: 32 },
+      ]);
+    });
+
+    it('should report the enclosing context', () => {
+      expect(find('MustOpen')?.metadata.crashContext).toBe('library');
+      expect(find('init')?.metadata.crashContext).toBe('init');
+      expect(find('main')?.metadata.crashContext).toBe('main');
+      expect(find('mustLoad')?.metadata.crashContext).toBe('test');
+    });
+
+    it('should leave functions without crash calls unmarked', () => {
+      const run = find('run');
+
+      expect(run?.metadata.crashes).toBeUndefined();
+      expect(run?.metadata.crashContext).toBeUndefined();
+    });
+
+    it('should flag functions that recover', () => {
+      expect(find('Safe')?.metadata.recovers).toBe(true);
+      expect(find('Safe')?.metadata.crashes).toBeUndefined();
+      expect(find('MustOpen')?.metadata.recovers).toBeUndefined();
+    });
+  });
+
+  describe('constructors', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['constructors.go', 'generics.go', 'simple.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should detect value, pointer, and (T, error) returns', () => {
+      expect(find('New')?.metadata).toMatchObject({
+        constructs: 'Client',
+        constructorConfidence: 'high',
+      });
+      expect(find('NewFromEnv')?.metadata).toMatchObject({
+        constructs: 'Client',
+        constructorConfidence: 'medium',
+      });
+      expect(find('NewServer')?.metadata).toMatchObject({
+        constructs: 'Server',
+        constructorConfidence: 'high',
+      });
+    });
+
+    it('should detect constructors without the New prefix at low confidence', () => {
+      expect(find('Open')?.metadata).toMatchObject({
+        constructs: 'Client',
+        constructorConfidence: 'low',
+      });
+    });
+
+    it('should detect generic constructors but not type parameter returns', () => {
+      expect(find('NewPair')?.metadata.constructs).toBe('Pair');
+      expect(find('Min')?.metadata.constructs).toBeUndefined();
+      expect(find('Map')?.metadata.constructs).toBeUndefined();
+    });
+
+    it('should skip predeclared, slice, and other packages\' types', () => {
+      expect(find('Version')?.metadata.constructs).toBeUndefined();
+      expect(find('Clients')?.metadata.constructs).toBeUndefined();
+      expect(find('DefaultTransport')?.metadata.constructs).toBeUndefined();
+    });
+
+    it('should mention the constructed type in the embedding text', () => {
+      expect(find('NewServer')?.text).toContain('constructor of Server');
+    });
+  });
+
+  describe('type references', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['constructors.go', 'generics.go', 'simple.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record the types in signatures and composite literals', () => {
+      expect(find('NewServer')?.metadata.referencesTypes).toEqual(['Config', 'Server']);
+      expect(find('processRequest')?.metadata.referencesTypes).toEqual(['Request', 'Response']);
+    });
+
+    it("should qualify other packages' types as written", () => {
+      expect(find('Start')?.metadata.referencesTypes).toEqual(['context.Context']);
+      expect(find('DefaultTransport')?.metadata.referencesTypes).toEqual(['http.Transport']);
+    });
+
+    it('should leave out predeclared types, type parameters, and the receiver', () => {
+      expect(find('Version')?.metadata.referencesTypes).toBeUndefined();
+      expect(find('NewPair')?.metadata.referencesTypes).toEqual(['Pair']);
+      expect(find('Min')?.metadata.referencesTypes).toEqual(['Ordered']);
+      expect(find('Stack.Push')?.metadata.referencesTypes).toBeUndefined();
+    });
+  });
+
+  describe('iterators', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['iterators.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should detect iter.Seq and iter.Seq2 returns with their element types', () => {
+      expect(find('List.All')?.metadata.iterator).toEqual({ kind: 'Seq', elementTypes: ['T'] });
+      expect(find('List.Enumerate')?.metadata.iterator).toEqual({
+        kind: 'Seq2',
+        elementTypes: ['int', 'T'],
+      });
+      expect(find('Keys')?.metadata.iterator).toEqual({ kind: 'Seq', elementTypes: ['K'] });
+    });
+
+    it('should detect the underlying func(yield ...) form', () => {
+      expect(find('Pairs')?.metadata.iterator).toEqual({
+        kind: 'Seq2',
+        elementTypes: ['string', '[]int'],
+      });
+    });
+
+    it('should not tag functions that consume iterators or take callbacks', () => {
+      expect(find('Collect')?.metadata.iterator).toBeUndefined();
+      expect(find('Callback')?.metadata.iterator).toBeUndefined();
+    });
+
+    it('should mention the element types in the embedding text', () => {
+      expect(find('List.Enumerate')?.text).toContain('iterator over int, T');
+    });
+  });
+
+  describe('examples', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['example_test.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should resolve the documented symbol from the example name', () => {
+      expect(find('Example')?.metadata.example?.target).toBeUndefined();
+      expect(find('Example_sorting')?.metadata.example).toMatchObject({ suffix: 'sorting' });
+      expect(find('ExampleNewServer')?.metadata.example?.target).toBe('NewServer');
+      expect(find('ExampleServer_Handle')?.metadata.example?.target).toBe('Server.Handle');
+      expect(find('ExampleServer_Handle_prefix')?.metadata.example).toMatchObject({
+        target: 'Server.Handle',
+        suffix: 'prefix',
+      });
+    });
+
+    it('should capture the body and expected output', () => {
+      expect(find('ExampleNewServer')?.metadata.example).toEqual({
+        target: 'NewServer',
+        code: 'srv := server.NewServer(":8080")\nfmt.Println(srv.Addr())',
+        output: ':8080',
+      });
+      expect(find('Example')?.metadata.example?.output).toBe('hello');
+      expect(find('ExampleServer_Handle_prefix')?.metadata.example).toMatchObject({
+        output: '/api/users\n/api/orders',
+        unordered: true,
+      });
+    });
+
+    it('should leave examples without an output comment unrun', () => {
+      const example = find('ExampleServer_Handle')?.metadata.example;
+      expect(example?.output).toBeUndefined();
+      expect(example?.code).toContain('srv.Handle("/health", nil)');
+    });
+
+    it('should ignore functions that only look like examples', () => {
+      expect(find('Examplefoo')?.metadata.example).toBeUndefined();
+      expect(find('ExampleWithArgs')?.metadata.example).toBeUndefined();
+    });
+
+    it('should mention the documented symbol in the embedding text', () => {
+      expect(find('ExampleNewServer')?.text).toContain('example of NewServer');
+    });
+  });
+
+  describe('defers', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['defers.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record deferred calls in source order', () => {
+      expect(find('Store.Count')?.metadata.defers).toEqual([
+        { call: 's.mu.Unlock()', line: 31 },
+        { call: 'rows.Close()', line: 37 },
+      ]);
+    });
+
+    it('should summarize deferred func literals with the calls they make', () => {
+      expect(find('ReadConfig')?.metadata.defers).toEqual([
+        { call: 'f.Close()', line: 47 },
+        { call: 'func() {...}()', line: 48, calls: ['recover', 'os.Remove'] },
+      ]);
+    });
+
+    it('should leave out defers belonging to nested func literals', () => {
+      const calls = find('ReadConfig')?.metadata.defers?.map((d) => d.call);
+      expect(calls).not.toContain('wg.Done()');
+    });
+
+    it('should not set defers on functions without defer statements', () => {
+      expect(find('Connect')?.metadata.defers).toBeUndefined();
+      expect(find('Store.Close')?.metadata.defers).toBeUndefined();
+    });
+  });
+
+  describe('type narrowing', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['narrowing.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record guarded narrowings from comma-ok assertions and switch cases', () => {
+      expect(find('Drain')?.metadata.callees).toEqual([
+        { name: 'f.Read', line: 18, receiverType: 'FileReader', guarded: true },
+        { name: 'v.Read', line: 22, receiverType: 'BufferReader', guarded: true },
+        { name: 'v.Read', line: 24 },
+        { name: 'r.Read', line: 26 },
+      ]);
+    });
+
+    it('should record unchecked assertions as unconditional', () => {
+      expect(find('MustClose')?.metadata.callees).toEqual([
+        { name: 'f.Close', line: 32, receiverType: 'FileReader' },
+        { name: 'c.(*FileReader).Close', line: 33, receiverType: 'FileReader' },
+      ]);
+    });
+  });
+
+  describe('func literals', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['closures.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should index a goroutine literal as a child of its function', () => {
+      const literal = find('Process.func1');
+
+      expect(literal?.type).toBe('function');
+      expect(literal?.metadata).toMatchObject({
+        startLine: 15,
+        endLine: 20,
+        signature: 'func()',
+        exported: false,
+        funcLiteral: {
+          enclosing: 'Process',
+          captures: ['wg', 'mu', 'results', 'item', 'scale'],
+          usage: 'go',
+        },
+      });
+      expect(literal?.metadata.callees).toBeUndefined();
+      expect(find('Process')?.metadata.callees?.map((c) => c.name)).toContain('wg.Done');
+    });
+
+    it('should record the function a callback is passed to', () => {
+      expect(find('Sorted.func1')?.metadata).toMatchObject({
+        signature: 'func(a, b string) int',
+        funcLiteral: {
+          enclosing: 'Sorted',
+          captures: ['compared'],
+          usage: 'argument',
+          passedTo: 'slices.SortFunc',
+        },
+      });
+      expect(find('Sorted.func1')?.text).toContain(
+        'func literal in Sorted, passed to slices.SortFunc; captures compared'
+      );
+    });
+
+    it('should name nested literals after their parent literal', () => {
+      expect(find('Counter.Incrementer.func1')?.metadata.funcLiteral).toEqual({
+        enclosing: 'Counter.Incrementer',
+        captures: ['c', 'step'],
+        usage: 'value',
+      });
+      expect(find('Counter.Incrementer.func1.1')?.metadata.funcLiteral).toEqual({
+        enclosing: 'Counter.Incrementer.func1',
+        captures: ['step'],
+        usage: 'value',
+      });
+    });
+  });
+
+  describe('errors returned', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['errors.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record sentinel errors once each', () => {
+      expect(find('ValidateEmail')?.metadata.errorsReturned).toEqual([
+        { kind: 'sentinel', error: 'ErrInvalidEmail', wrapped: false, line: 31 },
+      ]);
+    });
+
+    it('should resolve wrapped errors through functions in the same file', () => {
+      expect(find('CreateUser')?.metadata.errorsReturned).toEqual([
+        { kind: 'sentinel', error: 'ErrEmptyName', wrapped: false, line: 50 },
+        {
+          kind: 'sentinel',
+          error: 'ErrInvalidEmail',
+          wrapped: true,
+          via: 'ValidateEmail',
+          line: 53,
+        },
+        {
+          kind: 'sentinel',
+          error: 'ErrShortPassword',
+          wrapped: true,
+          via: 'ValidatePassword',
+          line: 56,
+        },
+      ]);
+    });
+
+    it('should follow variables to their latest assignment and pair %w arguments', () => {
+      expect(find('ReadHeader')?.metadata.errorsReturned).toEqual([
+        { kind: 'call', error: 'os.Open', wrapped: false, line: 65 },
+        { kind: 'sentinel', error: 'io.EOF', wrapped: false, line: 72 },
+        { kind: 'call', error: 'f.Read', wrapped: true, line: 75 },
+      ]);
+    });
+
+    it('should record error types and errors.Join, skipping func literal returns', () => {
+      expect(find('User.Check')?.metadata.errorsReturned).toEqual([
+        { kind: 'type', error: '*ValidationError', wrapped: false, line: 83 },
+        { kind: 'sentinel', error: 'ErrInvalidEmail', wrapped: true, line: 89 },
+        { kind: 'call', error: 'walk', wrapped: true, line: 89 },
+      ]);
+    });
+
+    it('should mention returned errors in the embedding text', () => {
+      expect(find('CreateUser')?.text).toContain(
+        'returns errors ErrEmptyName, ErrInvalidEmail, ErrShortPassword'
+      );
+    });
+
+    it('should not set errorsReturned on functions without error results', () => {
+      expect(find('Describe')?.metadata.errorsReturned).toBeUndefined();
+      expect(find('ValidationError.Error')?.metadata.errorsReturned).toBeUndefined();
+    });
+  });
+
+  describe('interface assertions', () => {
+    let assertions: Document[];
+
+    beforeAll(async () => {
+      const documents = await scanner.scan(['assertions.go'], fixturesDir);
+      assertions = documents.filter((d) => d.metadata.asserts);
+    });
+
+    const find = (iface: string, type: string) =>
+      assertions.find(
+        (d) => d.metadata.asserts?.interface === iface && d.metadata.asserts.type === type
+      );
+
+    it('should record grouped and single assertions', () => {
+      expect(assertions).toHaveLength(5);
+      expect(find('io.ReadCloser', 'File')?.metadata.asserts?.pointer).toBe(true);
+      expect(find('fmt.Stringer', 'Name')?.metadata.asserts?.pointer).toBe(false);
+      expect(find('http.Handler', 'http.HandlerFunc')).toBeDefined();
+    });
+
+    it('should treat &T{} and new(T) as pointer assertions', () => {
+      expect(find('io.Reader', 'File')?.metadata.asserts?.pointer).toBe(true);
+      expect(
+        assertions.filter((d) => d.metadata.asserts?.interface === 'fmt.Stringer')
+      ).toHaveLength(2);
+    });
+
+    it('should emit blank, unexported variable documents', () => {
+      const doc = find('io.Reader', 'File');
+      expect(doc?.type).toBe('variable');
+      expect(doc?.metadata).toMatchObject({ name: '_', exported: false, startLine: 30 });
+      expect(doc?.metadata.signature).toBe('var _ io.Reader = &File{}');
+    });
+
+    it('should skip named variables', () => {
+      expect(assertions.some((d) => d.metadata.signature?.includes('DefaultName'))).toBe(false);
+    });
+  });
+
+  describe('HTTP routes', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['routes.go', 'routes_chi.go', 'routes_gin.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record net/http registrations with Go 1.22 method patterns', () => {
+      expect(find('Server.Routes')?.metadata.routes).toEqual([
+        { path: '/users', handler: 's.listUsers', framework: 'net/http', line: 14 },
+        {
+          method: 'POST',
+          path: '/users',
+          handler: 's.createUser',
+          framework: 'net/http',
+          line: 15,
+        },
+        {
+          method: 'GET',
+          path: '/users/{id}',
+          handler: 's.getUser',
+          framework: 'net/http',
+          line: 16,
+        },
+        { path: '/healthz', framework: 'net/http', line: 17 },
+      ]);
+    });
+
+    it('should skip paths that are not string literals', () => {
+      expect(find('NewMux')?.metadata.routes).toEqual([
+        { path: '/metrics', handler: 'metricsHandler()', framework: 'net/http', line: 25 },
+      ]);
+    });
+
+    it('should apply chi sub-router prefixes', () => {
+      const routes = find('Router')?.metadata.routes ?? [];
+      expect(routes.map((r) => `${r.method} ${r.path} ${r.handler}`)).toEqual([
+        'GET / h.Index',
+        'GET /orders h.List',
+        'POST /orders h.Create',
+        'DELETE /orders/{id} h.Delete',
+        'PUT /orders/{id} h.Update',
+      ]);
+      expect(routes.every((r) => r.framework === 'chi')).toBe(true);
+    });
+
+    it('should apply gin group prefixes and take the last handler', () => {
+      const routes = find('Register')?.metadata.routes ?? [];
+      expect(routes.map((r) => `${r.method ?? 'ANY'} ${r.path} ${r.handler}`)).toEqual([
+        'GET /api/v1/items/:id h.GetItem',
+        'POST /api/v1/items h.CreateItem',
+        'ANY /ping ping',
+        'PATCH /items/:id h.PatchItem',
+      ]);
+      expect(routes.every((r) => r.framework === 'gin')).toBe(true);
+    });
+
+    it('should mention routes in the embedding text', () => {
+      expect(find('Server.Routes')?.text).toContain(
+        'registers routes ANY /users, POST /users, GET /users/{id}, ANY /healthz'
+      );
+    });
+
+    it('should not set routes on functions that register none', () => {
+      expect(find('Server.listUsers')?.metadata.routes).toBeUndefined();
+    });
+
+    it('should accept custom route patterns', async () => {
+      const custom = new GoScanner(undefined, [
+        { framework: 'custom', calls: ['HandleFunc'], method: 'path' },
+      ]);
+      const docs = await custom.scan(['routes.go'], fixturesDir);
+      const routes = docs.find((d) => d.metadata.name === 'Server.Routes')?.metadata.routes;
+      expect(routes?.map((r) => r.framework)).toEqual(['custom', 'custom', 'custom']);
+    });
+  });
+
+  describe('SQL queries', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['sql.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+
+    it('should record a query with its tables', () => {
+      expect(find('UserStore.Get')?.metadata.sqlQueries).toEqual([
+        {
+          operation: 'select',
+          query: 'SELECT id, email FROM users WHERE id = $1',
+          tables: ['users'],
+          line: 16,
+        },
+      ]);
+    });
+
+    it('should collapse whitespace in raw strings and record joined tables', () => {
+      expect(find('UserStore.ListWithOrders')?.metadata.sqlQueries).toEqual([
+        {
+          operation: 'select',
+          query:
+            'SELECT u.id, o.total FROM users u JOIN orders o ON o.user_id = u.id ' +
+            'WHERE o.total > $1',
+          tables: ['users', 'orders'],
+          line: 23,
+        },
+      ]);
+    });
+
+    it('should read lowercase SQL and concatenated literals', () => {
+      const queries = find('UserStore.Rename')?.metadata.sqlQueries ?? [];
+      expect(queries.map((q) => `${q.operation} ${q.tables.join(',')} ${q.line}`)).toEqual([
+        'update users 32',
+        'insert audit_log 35',
+      ]);
+      expect(queries[1].query).toBe(
+        "INSERT INTO audit_log (user_id, action) VALUES ($1, 'rename')"
+      );
+    });
+
+    it('should leave out table names built at runtime', () => {
+      expect(find('Purge')?.metadata.sqlQueries).toMatchObject([
+        { operation: 'delete', tables: [] },
+      ]);
+    });
+
+    it('should not mistake prose for SQL', () => {
+      expect(find('Describe')?.metadata.sqlQueries).toBeUndefined();
+    });
+
+    it('should mention queries in the embedding text', () => {
+      expect(find('UserStore.ListWithOrders')?.text).toContain('runs SQL select users orders');
+    });
+  });
+
+  describe('context propagation', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(['contexts.go', 'edge_cases.go', 'simple.go'], fixturesDir);
+    });
+
+    const find = (name: string) => documents.find((d) => d.metadata.name === name);
+    const findings = (name: string) =>
+      find(name)?.metadata.contextFindings?.map((f) => `${f.kind} ${f.call ?? '-'} ${f.line}`);
+
+    it('should not flag functions that pass their ctx on', () => {
+      expect(find('Client.Fetch')?.metadata.contextFindings).toBeUndefined();
+      expect(find('Client.save')?.metadata.contextFindings).toBeUndefined();
+      expect(find('DoWork')?.metadata.contextFindings).toBeUndefined();
+    });
+
+    it('should flag root contexts and nil passed where ctx is in scope', () => {
+      expect(findings('Client.Refresh')).toEqual([
+        'not-propagated c.save 30',
+        'not-propagated c.save 33',
+      ]);
+      expect(find('Client.Refresh')?.metadata.contextFindings?.[0].suggestion).toBe(
+        'passes context.Background() although ctx is in scope; pass ctx'
+      );
+    });
+
+    it('should suggest the context-taking variant of a call', () => {
+      expect(find('Client.Count')?.metadata.contextFindings).toEqual([
+        {
+          kind: 'not-propagated',
+          line: 39,
+          call: 'c.db.QueryRow',
+          suggestion: 'ignores ctx; use c.db.QueryRowContext with it',
+        },
+      ]);
+    });
+
+    it('should flag a named ctx that is never used', () => {
+      expect(findings('Ping')).toEqual(['ignored - 44']);
+      expect(findings('Start')).toEqual(['ignored - 78']);
+      expect(find('Discard')?.metadata.contextFindings).toBeUndefined();
+    });
+
+    it('should flag I/O and root contexts in functions without a ctx', () => {
+      expect(findings('Download')).toEqual(['missing http.Get 53', 'missing exec.Command 58']);
+      expect(findings('Background')).toEqual(['missing c.save 63']);
+    });
+
+    it('should let main start root contexts', () => {
+      expect(find('main')?.metadata.contextFindings).toBeUndefined();
+    });
+  });
+
+  describe('build constraints', () => {
+    let documents: Document[];
+
+    beforeAll(async () => {
+      documents = await scanner.scan(
+        ['platform_linux.go', 'platform_windows.go', 'platform_bsd.go', 'simple.go'],
+        fixturesDir
+      );
+    });
+
+    const constraintOf = (file: string) =>
+      documents.find((d) => d.metadata.file === file)?.metadata.buildConstraint;
+
+    it('should record the platforms of file name suffixes and build lines', () => {
+      expect(constraintOf('platform_linux.go')).toBe('linux');
+      expect(constraintOf('platform_windows.go')).toBe('windows');
+      expect(constraintOf('platform_bsd.go')).toBe('darwin || freebsd');
+    });
+
+    it('should keep every variant in the scan', () => {
+      const doWork = documents.filter((d) => d.metadata.name === 'doWork');
+      expect(doWork.map((d) => d.metadata.file).sort()).toEqual([
+        'platform_bsd.go',
+        'platform_linux.go',
+        'platform_windows.go',
+      ]);
+    });
+
+    it('should leave files built everywhere unconstrained', () => {
+      expect(constraintOf('simple.go')).toBeUndefined();
+    });
+  });
 });
@@ -0,0 +1,112 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterAll, beforeAll, describe, expect, it } from 'vitest';
+import {
+  DEFAULT_IGNORE_PATTERNS,
+  IGNORE_FILE_NAME,
+  loadIgnoreFile,
+  normalizeIgnorePattern,
+  parseIgnoreFile,
+  resolveIgnorePatterns,
+} from '../ignore';
+import { scanRepository } from '../index';
+
+describe('Ignore patterns', () => {
+  describe('normalizeIgnorePattern', () => {
+    it('should match directories at any depth', () => {
+      expect(normalizeIgnorePattern('vendor/')).toBe('**/vendor/**');
+    });
+
+    it('should match bare file globs at any depth', () => {
+      expect(normalizeIgnorePattern('*_generated.go')).toBe('**/*_generated.go');
+    });
+
+    it('should anchor leading-slash patterns to the root', () => {
+      expect(normalizeIgnorePattern('/scripts/')).toBe('scripts/**');
+    });
+
+    it('should keep patterns with an inner slash relative to the root', () => {
+      expect(normalizeIgnorePattern('api/gen/')).toBe('api/gen/**');
+    });
+
+    it('should leave globstar patterns untouched', () => {
+      expect(normalizeIgnorePattern('**/*.pb.go')).toBe('**/*.pb.go');
+    });
+  });
+
+  describe('parseIgnoreFile', () => {
+    it('should skip comments and blank lines', () => {
+      const content = '# generated code\n*.pb.go\n\n  vendor/  \n# end\n';
+      expect(parseIgnoreFile(content)).toEqual(['*.pb.go', 'vendor/']);
+    });
+  });
+
+  describe('resolveIgnorePatterns', () => {
+    it('should append user patterns to the base', () => {
+      expect(resolveIgnorePatterns(['**/dist/**'], ['*.pb.go'])).toEqual([
+        '**/dist/**',
+        '**/*.pb.go',
+      ]);
+    });
+
+    it('should remove base patterns with negation', () => {
+      const result = resolveIgnorePatterns(DEFAULT_IGNORE_PATTERNS, ['!vendor/']);
+      expect(result).not.toContain('**/vendor/**');
+      expect(result).toContain('**/node_modules/**');
+    });
+
+    it('should dedupe patterns', () => {
+      expect(resolveIgnorePatterns(['**/vendor/**'], ['vendor/'])).toEqual(['**/vendor/**']);
+    });
+  });
+
+  describe('with a repository', () => {
+    let repoDir: string;
+
+    beforeAll(async () => {
+      repoDir = await fs.mkdtemp(path.join(os.tmpdir(), 'dev-agent-ignore-'));
+      await fs.mkdir(path.join(repoDir, 'gen'), { recursive: true });
+      await fs.mkdir(path.join(repoDir, 'vendor', 'lib'), { recursive: true });
+      await fs.writeFile(path.join(repoDir, 'main.ts'), 'export function main() {}\n');
+      await fs.writeFile(path.join(repoDir, 'gen', 'client.ts'), 'export function gen() {}\n');
+      await fs.writeFile(
+        path.join(repoDir, 'vendor', 'lib', 'dep.ts'),
+        'export function dep() {}\n'
+      );
+      await fs.writeFile(path.join(repoDir, IGNORE_FILE_NAME), '# codegen\ngen/\n');
+    });
+
+    afterAll(async () => {
+      await fs.rm(repoDir, { recursive: true, force: true });
+    });
+
+    it('should load the ignore file', async () => {
+      expect(await loadIgnoreFile(repoDir)).toEqual(['gen/']);
+    });
+
+    it('should return no patterns without an ignore file', async () => {
+      expect(await loadIgnoreFile(path.join(repoDir, 'missing'))).toEqual([]);
+    });
+
+    it('should skip ignored and default-ignored files when scanning', async () => {
+      const result = await scanRepository({ repoRoot: repoDir, exclude: ['**/*.test.ts'] });
+      const files = new Set(result.documents.map((d) => d.metadata.file));
+
+      expect(files.has('main.ts')).toBe(true);
+      expect(files.has('gen/client.ts')).toBe(false);
+      expect(files.has('vendor/lib/dep.ts')).toBe(false);
+    });
+
+    it('should allow overriding defaults with negated patterns', async () => {
+      const result = await scanRepository({
+        repoRoot: repoDir,
+        exclude: ['**/*.test.ts'],
+        ignore: ['!vendor/'],
+      });
+      const files = new Set(result.documents.map((d) => d.metadata.file));
+
+      expect(files.has('vendor/lib/dep.ts')).toBe(true);
+    });
+  });
+});
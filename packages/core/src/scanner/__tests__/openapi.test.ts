@@ -0,0 +1,130 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeAll, beforeEach, describe, expect, it } from 'vitest';
+import { createDefaultRegistry } from '../index';
+import { OpenApiScanner } from '../openapi';
+import type { Document } from '../types';
+
+describe('OpenApiScanner', () => {
+  const scanner = new OpenApiScanner();
+  const fixturesDir = path.join(__dirname, 'fixtures', 'openapi');
+
+  describe('canHandle', () => {
+    it('should handle spec files by name or directory', () => {
+      expect(scanner.canHandle('openapi.yaml')).toBe(true);
+      expect(scanner.canHandle('api/swagger.json')).toBe(true);
+      expect(scanner.canHandle('api/users.openapi.yml')).toBe(true);
+      expect(scanner.canHandle('docs/openapi-v2.yaml')).toBe(true);
+      expect(scanner.canHandle('api/openapi/users.yaml')).toBe(true);
+    });
+
+    it('should not handle other config files', () => {
+      expect(scanner.canHandle('deploy.yaml')).toBe(false);
+      expect(scanner.canHandle('package.json')).toBe(false);
+      expect(scanner.canHandle('myopenapi.yaml')).toBe(false);
+      expect(scanner.canHandle('openapi.md')).toBe(false);
+    });
+  });
+
+  describe('OpenAPI 3 YAML', () => {
+    let docs: Document[];
+
+    beforeAll(async () => {
+      docs = await scanner.scan(['openapi.yaml'], fixturesDir);
+    });
+
+    it('should index each operation with its method, path, and operationId', () => {
+      expect(docs.map((d) => d.metadata.name)).toEqual([
+        'GET /users',
+        'POST /users',
+        'DELETE /users/{id}',
+      ]);
+      expect(docs[1]).toMatchObject({
+        type: 'documentation',
+        language: 'openapi',
+        metadata: {
+          startLine: 17,
+          endLine: 33,
+          openApiOperation: {
+            method: 'POST',
+            path: '/users',
+            basePath: '/api/v1',
+            operationId: 'createUser',
+            summary: 'Create a user',
+            tags: ['users'],
+          },
+        },
+      });
+    });
+
+    it('should make the operation searchable by endpoint, summary, and description', () => {
+      const text = docs[1].text;
+
+      expect(text.split('\n')[0]).toBe('API endpoint POST /api/v1/users (operationId createUser)');
+      expect(text).toContain('Create a user');
+      expect(text).toContain('Creates an account and sends a welcome email.\n\nEmails must be');
+      expect(docs[1].metadata.snippet).toContain('requestBody:');
+    });
+
+    it('should read flow sequences, quoted paths, and folded summaries', () => {
+      expect(docs[0].metadata.openApiOperation?.tags).toEqual(['users']);
+      expect(docs[2].metadata.openApiOperation).toMatchObject({
+        path: '/users/{id}',
+        summary: 'Delete a user and their sessions',
+      });
+      expect(docs[2].metadata).toMatchObject({ startLine: 39, endLine: 46 });
+    });
+  });
+
+  describe('Swagger 2 JSON', () => {
+    it('should index operations with the base path and lines of their keys', async () => {
+      const docs = await scanner.scan(['swagger.json'], fixturesDir);
+
+      expect(docs.map((d) => [d.metadata.name, d.metadata.startLine, d.metadata.endLine])).toEqual([
+        ['GET /orders', 6, 9],
+        ['POST /orders', 10, 12],
+      ]);
+      expect(docs[0].metadata.openApiOperation).toEqual({
+        method: 'GET',
+        path: '/orders',
+        basePath: '/v2',
+        operationId: 'listOrders',
+        summary: 'List orders',
+      });
+      expect(docs[1].metadata.openApiOperation?.operationId).toBeUndefined();
+    });
+  });
+
+  describe('Dispatch', () => {
+    let tempDir: string;
+
+    beforeEach(async () => {
+      tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'scanner-openapi-'));
+    });
+
+    afterEach(async () => {
+      await fs.rm(tempDir, { recursive: true, force: true });
+    });
+
+    it('should index a spec-named file that is not a spec as text', async () => {
+      await fs.writeFile(path.join(tempDir, 'swagger-ui.json'), '{\n  "deepLinking": true\n}\n');
+
+      const docs = await scanner.scan(['swagger-ui.json'], tempDir);
+
+      expect(docs).toHaveLength(1);
+      expect(docs[0].language).toBe('json');
+      expect(docs[0].metadata.openApiOperation).toBeUndefined();
+    });
+
+    it('should claim specs ahead of the text scanner in the default registry', async () => {
+      await fs.copyFile(path.join(fixturesDir, 'openapi.yaml'), path.join(tempDir, 'openapi.yaml'));
+      await fs.writeFile(path.join(tempDir, 'deploy.yaml'), 'replicas: 2\n');
+
+      const result = await createDefaultRegistry().scanRepository({ repoRoot: tempDir });
+      const languages = result.documents.map((d) => `${d.metadata.file}:${d.language}`);
+
+      expect(new Set(languages)).toEqual(new Set(['openapi.yaml:openapi', 'deploy.yaml:yaml']));
+    });
+  });
+});
@@ -1,7 +1,11 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
 import * as path from 'node:path';
-import { describe, expect, it } from 'vitest';
+import { afterEach, beforeEach, describe, expect, it } from 'vitest';
 import { MarkdownScanner } from '../markdown';
 import { ScannerRegistry } from '../registry';
+import { TextScanner } from '../text';
+import type { ScanProgress } from '../types';
 import { TypeScriptScanner } from '../typescript';
 
 // Helper to create registry
@@ -196,9 +200,9 @@ describe('Scanner', () => {
     // All IDs should be unique
     expect(ids.length).toBe(uniqueIds.size);
 
-    // IDs should follow format: file:name:line
+    // IDs should follow format: file:kind:name, with no line numbers
     for (const id of ids) {
-      expect(id).toMatch(/^[^:]+:[^:]+:\d+(-\d+)?$/);
+      expect(id).toMatch(/^packages\/core\/src\/scanner\/registry\.ts:[a-z]+:[^:]+$/);
     }
   });
 
@@ -357,6 +361,101 @@ describe('Scanner', () => {
     expect(result.stats.filesScanned).toBe(1);
   });
 
+  it('should report per-language stats and parse failures', async () => {
+    const registry = new ScannerRegistry();
+    registry.register({
+      language: 'typescript',
+      capabilities: { syntax: true },
+      canHandle: (file: string) => file.endsWith('.ts'),
+      scan: async (files, _repoRoot, _logger, _onProgress, onError) => {
+        const broken = 'packages/core/src/index.ts';
+        if (files.includes(broken)) {
+          onError?.({ file: broken, error: 'Unexpected token', phase: 'extractFromFile' });
+        }
+        return files
+          .filter((file) => file !== broken)
+          .map((file) => ({
+            id: `${file}:fn:1`,
+            text: 'function fn() {}',
+            type: 'function' as const,
+            language: 'typescript',
+            metadata: { file, startLine: 1, endLine: 1, name: 'fn', exported: true },
+          }));
+      },
+    });
+    registry.register(new MarkdownScanner());
+
+    const result = await registry.scanRepository({
+      repoRoot,
+      include: ['packages/core/src/index.ts', 'packages/core/src/scanner/index.ts', 'README.md'],
+    });
+
+    const ts = result.stats.byLanguage?.typescript;
+    expect(ts?.files).toBe(2);
+    expect(ts?.documents).toBe(1);
+    expect(ts?.byKind).toEqual({ function: 1 });
+    expect(ts?.parseFailures).toBe(1);
+    expect(result.stats.byLanguage?.markdown?.documents).toBeGreaterThan(0);
+    expect(result.stats.byKind?.function).toBe(1);
+    expect(result.stats.byKind?.documentation).toBeGreaterThan(0);
+    expect(result.stats.errors).toEqual([
+      { file: 'packages/core/src/index.ts', error: 'Unexpected token', phase: 'extractFromFile' },
+    ]);
+  });
+
+  describe('Progress', () => {
+    // Reports progress after every file, like the real scanners
+    function perFileRegistry(): ScannerRegistry {
+      const registry = new ScannerRegistry();
+      registry.register({
+        language: 'typescript',
+        capabilities: { syntax: true },
+        canHandle: (file: string) => file.endsWith('.ts'),
+        scan: async (files, _repoRoot, _logger, onProgress) => {
+          files.forEach((_file, i) => onProgress?.(i + 1, files.length));
+          return [];
+        },
+      });
+      return registry;
+    }
+    const include = [
+      'packages/core/src/index.ts',
+      'packages/core/src/scanner/index.ts',
+      'packages/core/src/utils/index.ts',
+    ];
+
+    it('should throttle per-file reports but always report phase changes', async () => {
+      const progress: ScanProgress[] = [];
+
+      await perFileRegistry().scanRepository({
+        repoRoot,
+        include,
+        onProgress: (p) => progress.push(p),
+      });
+
+      expect(progress.map((p) => p.phase)).toEqual(['discovery', 'scanning', 'complete']);
+      expect(progress.at(-1)).toMatchObject({
+        filesScanned: 3,
+        percentComplete: 100,
+        etaSeconds: 0,
+      });
+    });
+
+    it('should report every file with a zero interval', async () => {
+      const progress: ScanProgress[] = [];
+
+      await perFileRegistry().scanRepository({
+        repoRoot,
+        include,
+        progressIntervalMs: 0,
+        onProgress: (p) => progress.push(p),
+      });
+
+      const scanning = progress.filter((p) => p.phase === 'scanning');
+      expect(scanning.map((p) => p.percentComplete)).toEqual([0, 33, 67, 100]);
+    });
+  });
+
   describe('Code Snippets', () => {
     it('should extract code snippets for classes', async () => {
       const result = await scanRepository({
@@ -978,4 +1077,178 @@ describe('Scanner', () => {
       expect(config).toBeUndefined();
     });
   });
+
+  describe('Dispatch and Fallback', () => {
+    let tempDir: string;
+
+    beforeEach(async () => {
+      tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'scanner-dispatch-'));
+      await fs.writeFile(path.join(tempDir, 'notes.txt'), 'Retry budget is shared per client.\n');
+      await fs.writeFile(path.join(tempDir, 'deploy'), '#!/usr/bin/env node\nconsole.log(1);\n');
+      await fs.writeFile(path.join(tempDir, 'logo.bin'), Buffer.from([0x89, 0x50, 0x00, 0x01]));
+      await fs.writeFile(path.join(tempDir, 'app.lua'), 'print("hi")\n');
+    });
+
+    afterEach(async () => {
+      await fs.rm(tempDir, { recursive: true, force: true });
+    });
+
+    it('should index unknown text files with the fallback scanner', async () => {
+      const registry = createDefaultRegistry();
+      registry.setFallback(new TextScanner());
+
+      const result = await registry.scanRepository({
+        repoRoot: tempDir,
+        include: ['notes.txt', 'logo.bin'],
+      });
+
+      expect(result.documents).toHaveLength(1);
+      expect(result.documents[0]).toMatchObject({
+        type: 'documentation',
+        language: 'text',
+        metadata: { file: 'notes.txt', name: 'notes.txt', startLine: 1 },
+      });
+      expect(result.documents[0].text).toContain('Retry budget');
+    });
+
+    it('should skip unknown files without a fallback', async () => {
+      const result = await createDefaultRegistry().scanRepository({
+        repoRoot: tempDir,
+        include: ['notes.txt'],
+      });
+
+      expect(result.documents).toEqual([]);
+    });
+
+    it('should dispatch by content when no scanner claims the path', async () => {
+      const registry = new ScannerRegistry();
+      const seen: string[] = [];
+      registry.register({
+        language: 'node-script',
+        capabilities: { syntax: false },
+        canHandle: (_file: string, content?: string) =>
+          content?.startsWith('#!/usr/bin/env node') ?? false,
+        scan: async (files) => {
+          seen.push(...files);
+          return [];
+        },
+      });
+
+      await registry.scanRepository({ repoRoot: tempDir, include: ['deploy', 'notes.txt'] });
+
+      expect(seen).toEqual(['deploy']);
+    });
+
+    it('should discover files by a scanner-declared extension', async () => {
+      const registry = new ScannerRegistry();
+      registry.register({
+        language: 'lua',
+        capabilities: { syntax: true },
+        extensions: ['.lua'],
+        canHandle: (file: string) => file.endsWith('.lua'),
+        scan: async (files) =>
+          files.map((file) => ({
+            id: `${file}:main:1`,
+            text: 'main',
+            type: 'function' as const,
+            language: 'lua',
+            metadata: { file, startLine: 1, endLine: 1, name: 'main', exported: true },
+          })),
+      });
+
+      expect(registry.getSupportedExtensions()).toEqual(new Set(['.lua']));
+
+      const result = await registry.scanRepository({ repoRoot: tempDir });
+      expect(result.documents.map((d) => d.metadata.file)).toEqual(['app.lua']);
+    });
+
+    it('should chunk long text files by lines', async () => {
+      const lines = Array.from({ length: 90 }, (_, i) => `line ${i + 1}`);
+      await fs.writeFile(path.join(tempDir, 'long.txt'), lines.join('\n'));
+
+      const docs = await new TextScanner().scan(['long.txt'], tempDir);
+
+      expect(docs.map((d) => [d.metadata.startLine, d.metadata.endLine])).toEqual([
+        [1, 40],
+        [41, 80],
+        [81, 90],
+      ]);
+      expect(docs[1].metadata.name).toBe('long.txt:41-80');
+    });
+  });
+
+  describe('Docs and Config Files', () => {
+    let tempDir: string;
+
+    beforeEach(async () => {
+      tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'scanner-docs-'));
+    });
+
+    afterEach(async () => {
+      await fs.rm(tempDir, { recursive: true, force: true });
+    });
+
+    it('should split YAML at top-level keys, keeping comments with their key', async () => {
+      const services = Array.from({ length: 40 }, (_, i) => `  svc${i}:\n    image: app`);
+      const yaml = ['version: 2', '# Worker pool', 'workers:', '  count: 4', 'services:'];
+      yaml.push(...services);
+      await fs.writeFile(path.join(tempDir, 'deploy.yaml'), yaml.join('\n'));
+
+      const docs = await new TextScanner().scan(['deploy.yaml'], tempDir);
+
+      expect(docs[0]).toMatchObject({
+        language: 'yaml',
+        metadata: { name: 'version, workers', startLine: 1, endLine: 4 },
+      });
+      expect(docs[0].text.startsWith('deploy.yaml: version, workers')).toBe(true);
+      expect(docs[0].metadata.snippet).toContain('# Worker pool');
+      expect(docs.slice(1).every((d) => d.metadata.name === 'services')).toBe(true);
+      expect(docs[1].metadata.startLine).toBe(5);
+    });
+
+    it('should split JSON at the keys of the top-level object', async () => {
+      const deps = Array.from({ length: 45 }, (_, i) => `    "dep${i}": "^1.0.0"`).join(',\n');
+      const pkg = `{\n  "name": "api",\n  "dependencies": {\n${deps}\n  }\n}\n`;
+      await fs.writeFile(path.join(tempDir, 'package.json'), pkg);
+
+      const docs = await new TextScanner().scan(['package.json'], tempDir);
+
+      expect(docs.map((d) => d.language)).toEqual(['json', 'json', 'json']);
+      expect(docs.map((d) => d.metadata.name)).toEqual(['name', 'dependencies', 'dependencies']);
+    });
+
+    it('should discover config files in default scans', async () => {
+      await fs.writeFile(path.join(tempDir, 'ci.yml'), 'jobs:\n  test: {}\n');
+      const registry = new ScannerRegistry();
+      registry.register(new TextScanner());
+
+      const result = await registry.scanRepository({ repoRoot: tempDir });
+
+      expect(result.documents.map((d) => d.metadata.file)).toEqual(['ci.yml']);
+    });
+
+    it('should title Markdown sections with their heading path', async () => {
+      await fs.writeFile(
+        path.join(tempDir, 'DESIGN.md'),
+        [
+          'Overview of the indexer.',
+          '',
+          '# Indexer',
+          '',
+          '## Batching',
+          '',
+          'Documents are embedded in batches:',
+          '',
+          '- 32 per batch',
+          '- retried on failure',
+        ].join('\n')
+      );
+
+      const docs = await new MarkdownScanner().scan(['DESIGN.md'], tempDir);
+
+      expect(docs.map((d) => d.metadata.name)).toEqual(['DESIGN.md', 'Batching']);
+      expect(docs[1].text.startsWith('Indexer > Batching')).toBe(true);
+      expect(docs[1].text).toContain('- 32 per batch');
+    });
+  });
 });
@@ -0,0 +1,83 @@
+import { describe, expect, it } from 'vitest';
+import { assignStableIds, stableDocumentId } from '../stable-ids';
+import type { Document, DocumentMetadata, DocumentType } from '../types';
+
+function doc(
+  name: string,
+  type: DocumentType,
+  startLine: number,
+  metadata: Partial<DocumentMetadata> = {}
+): Document {
+  const file = metadata.file ?? 'internal/users/server.go';
+  return {
+    id: `${file}:${name}:${startLine}`,
+    text: name,
+    type,
+    language: 'go',
+    metadata: { file, name, startLine, endLine: startLine + 5, exported: true, ...metadata },
+  };
+}
+
+describe('stableDocumentId', () => {
+  it('should qualify Go symbols with their package', () => {
+    const method = doc('Server.CreateUser', 'method', 42, { packageName: 'users' });
+
+    expect(stableDocumentId(method)).toBe(
+      'internal/users/server.go:method:users.Server.CreateUser'
+    );
+  });
+
+  it('should use the scanned name when there is no package', () => {
+    const method = doc('Registry.register', 'method', 10, {
+      file: 'src/registry.ts',
+    });
+
+    expect(stableDocumentId(method)).toBe('src/registry.ts:method:Registry.register');
+  });
+
+  it('should tell interface assertions apart by what they assert', () => {
+    const assertion = doc('_', 'variable', 12, {
+      packageName: 'users',
+      asserts: { interface: 'Store', type: 'memStore', pointer: true },
+    });
+
+    expect(stableDocumentId(assertion)).toBe(
+      'internal/users/server.go:variable:users._(memStore implements Store)'
+    );
+  });
+});
+
+describe('assignStableIds', () => {
+  it('should keep IDs when code above a symbol moves it', () => {
+    const before = assignStableIds([doc('NewServer', 'function', 10, { packageName: 'users' })]);
+    const after = assignStableIds([doc('NewServer', 'function', 25, { packageName: 'users' })]);
+
+    expect(after[0].id).toBe(before[0].id);
+    expect(after[0].id).not.toMatch(/:\d+$/);
+  });
+
+  it('should number repeated names in source order', () => {
+    const documents = assignStableIds([
+      doc('init', 'function', 30, { packageName: 'users' }),
+      doc('init', 'function', 5, { packageName: 'users' }),
+      doc('init', 'function', 60, { packageName: 'users' }),
+    ]);
+
+    expect(documents.map((d) => d.id)).toEqual([
+      'internal/users/server.go:function:users.init#2',
+      'internal/users/server.go:function:users.init',
+      'internal/users/server.go:function:users.init#3',
+    ]);
+  });
+
+  it('should not number the same name across kinds or files', () => {
+    const documents = assignStableIds([
+      doc('Config', 'type', 5, { packageName: 'users' }),
+      doc('Config', 'function', 20, { packageName: 'users' }),
+      doc('Config', 'type', 5, { packageName: 'users', file: 'internal/users/config.go' }),
+    ]);
+
+    expect(documents.every((d) => !d.id.includes('#'))).toBe(true);
+    expect(new Set(documents.map((d) => d.id)).size).toBe(3);
+  });
+});
@@ -0,0 +1,27 @@
+import { describe, expect, it } from 'vitest';
+import { isGoExported, isGoMethodExported } from '../visibility';
+
+describe('Go visibility', () => {
+  it('should export identifiers starting with an upper case letter', () => {
+    expect(isGoExported('Server')).toBe(true);
+    expect(isGoExported('server')).toBe(false);
+  });
+
+  it('should follow Unicode letter categories', () => {
+    expect(isGoExported('Ñandú')).toBe(true);
+    expect(isGoExported('über')).toBe(false);
+    // Not a letter: underscore and non-cased scripts are unexported
+    expect(isGoExported('_Private')).toBe(false);
+    expect(isGoExported('日本')).toBe(false);
+  });
+
+  it('should treat empty names as unexported', () => {
+    expect(isGoExported('')).toBe(false);
+  });
+
+  it('should require both receiver and method to be exported', () => {
+    expect(isGoMethodExported('Server', 'Start')).toBe(true);
+    expect(isGoMethodExported('Server', 'start')).toBe(false);
+    expect(isGoMethodExported('server', 'Start')).toBe(false);
+  });
+});
@@ -0,0 +1,106 @@
+/**
+ * Cyclomatic Complexity
+ *
+ * Deterministic, per-function complexity estimate (McCabe style):
+ *
+ *   complexity = 1 + number of decision points
+ *
+ * Decision points:
+ * - `if` statements (each `else if` is its own `if`)
+ * - loops (`for`, `for..in`, `for..of`, `while`, `do..while`, Go `for`/`range`)
+ * - `case` clauses (`default` is not counted)
+ * - `catch` clauses and ternary expressions (TypeScript)
+ * - short-circuit boolean operators: `&&`, `||` (and `??` in TypeScript)
+ *
+ * Nested function literals/closures are counted as part of the enclosing
+ * function. The value is a review signal, not an absolute measure.
+ */
+
+import { type Node, SyntaxKind } from 'ts-morph';
+import type { TreeSitterNode } from './tree-sitter';
+
+/** Go node types that add a decision point */
+const GO_DECISION_NODES = new Set([
+  'if_statement',
+  'for_statement',
+  'expression_case',
+  'type_case',
+  'communication_case',
+]);
+
+/** Go boolean operators that add a decision point */
+const GO_BOOLEAN_OPERATORS = new Set(['&&', '||']);
+
+/**
+ * Compute cyclomatic complexity for a Go function or method node
+ *
+ * @param node - tree-sitter function_declaration or method_declaration
+ * @returns Complexity (minimum 1)
+ */
+export function computeGoComplexity(node: TreeSitterNode): number {
+  let complexity = 1;
+  const stack: TreeSitterNode[] = [node];
+
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+
+    if (GO_DECISION_NODES.has(current.type)) {
+      complexity++;
+    } else if (current.type === 'binary_expression') {
+      const operator = current.childForFieldName('operator');
+      if (operator && GO_BOOLEAN_OPERATORS.has(operator.type)) {
+        complexity++;
+      }
+    }
+
+    stack.push(...current.namedChildren);
+  }
+
+  return complexity;
+}
+
+/** TypeScript syntax kinds that add a decision point */
+const TS_DECISION_KINDS = new Set<SyntaxKind>([
+  SyntaxKind.IfStatement,
+  SyntaxKind.ForStatement,
+  SyntaxKind.ForInStatement,
+  SyntaxKind.ForOfStatement,
+  SyntaxKind.WhileStatement,
+  SyntaxKind.DoStatement,
+  SyntaxKind.CaseClause,
+  SyntaxKind.CatchClause,
+  SyntaxKind.ConditionalExpression,
+]);
+
+/** TypeScript operators that add a decision point */
+const TS_BOOLEAN_OPERATORS = new Set<SyntaxKind>([
+  SyntaxKind.AmpersandAmpersandToken,
+  SyntaxKind.BarBarToken,
+  SyntaxKind.QuestionQuestionToken,
+]);
+
+/**
+ * Compute cyclomatic complexity for a TypeScript/JavaScript function-like node
+ *
+ * @param node - Function, method, arrow function, or function expression
+ * @returns Complexity (minimum 1)
+ */
+export function computeTypeScriptComplexity(node: Node): number {
+  let complexity = 1;
+
+  node.forEachDescendant((descendant) => {
+    const kind = descendant.getKind();
+    if (TS_DECISION_KINDS.has(kind)) {
+      complexity++;
+    } else if (
+      kind === SyntaxKind.BinaryExpression &&
+      TS_BOOLEAN_OPERATORS.has(
+        descendant.asKindOrThrow(SyntaxKind.BinaryExpression).getOperatorToken().getKind()
+      )
+    ) {
+      complexity++;
+    }
+  });
+
+  return complexity;
+}
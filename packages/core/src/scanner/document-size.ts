@@ -0,0 +1,177 @@
+/**
+ * Document Size Limits
+ * Keeps a single pathological symbol (generated tables, embedded blobs,
+ * minified lines) from ballooning the index and every response that returns it
+ *
+ * Policy for a document over the limit:
+ * - `documentation` documents are chunked: the body is split on line
+ *   boundaries into parts that each fit, titled `<name> (part i of n)`
+ * - everything else is summarized: the signature and metadata are kept, and
+ *   the doc comment, embedding text, and snippet are cut to fit
+ *
+ * Either way the result records `overflow`, so nothing is dropped silently.
+ */
+
+import type { Logger } from '@lytics/kero';
+import type { Document } from './types';
+
+/**
+ * Default maximum document size in bytes, counting embedding text, snippet,
+ * and doc comment together
+ */
+export const DEFAULT_MAX_DOCUMENT_BYTES = 64 * 1024;
+
+/** Marker appended where content was cut */
+const TRUNCATION_MARKER = '\n... [truncated]';
+
+/**
+ * Size of a document in bytes (UTF-8), as held in memory and stored
+ */
+export function documentSize(doc: Document): number {
+  return (
+    Buffer.byteLength(doc.text) +
+    Buffer.byteLength(doc.metadata.snippet ?? '') +
+    Buffer.byteLength(doc.metadata.docstring ?? '')
+  );
+}
+
+/**
+ * Apply the size limit to scanned documents, logging each one that overflows
+ *
+ * @param maxBytes - Limit for every document, or a function of the document's file
+ */
+export function limitDocumentSize(
+  documents: Document[],
+  maxBytes: number | ((file: string) => number) = DEFAULT_MAX_DOCUMENT_BYTES,
+  logger?: Logger
+): Document[] {
+  const limited: Document[] = [];
+
+  for (const doc of documents) {
+    const limit = typeof maxBytes === 'function' ? maxBytes(doc.metadata.file) : maxBytes;
+    const bytes = documentSize(doc);
+    if (bytes <= limit) {
+      limited.push(doc);
+      continue;
+    }
+
+    const result =
+      doc.type === 'documentation'
+        ? chunkDocument(doc, bytes, limit)
+        : [summarizeDocument(doc, bytes, limit)];
+    logger?.warn(
+      {
+        file: doc.metadata.file,
+        name: doc.metadata.name,
+        bytes,
+        maxBytes: limit,
+        policy: result[0].metadata.overflow?.policy,
+        documents: result.length,
+      },
+      `Document ${doc.id} exceeds ${limit} bytes; ${result[0].metadata.overflow?.policy}`
+    );
+    limited.push(...result);
+  }
+
+  return limited;
+}
+
+/**
+ * Keep the document whole, cutting its doc comment to a quarter of the limit,
+ * its embedding text to another quarter, and its snippet to the rest
+ */
+function summarizeDocument(doc: Document, bytes: number, maxBytes: number): Document {
+  const quarter = Math.floor(maxBytes / 4);
+  const docstring =
+    doc.metadata.docstring === undefined ? undefined : clipBytes(doc.metadata.docstring, quarter);
+  const text = clipBytes(doc.text, quarter);
+  const used = Buffer.byteLength(text) + Buffer.byteLength(docstring ?? '');
+  const snippet =
+    doc.metadata.snippet === undefined
+      ? undefined
+      : clipBytes(doc.metadata.snippet, Math.max(0, maxBytes - used));
+
+  return {
+    ...doc,
+    text,
+    metadata: {
+      ...doc.metadata,
+      docstring,
+      snippet,
+      overflow: { policy: 'summarized', originalBytes: bytes },
+    },
+  };
+}
+
+/**
+ * Split a documentation document into parts on line boundaries
+ *
+ * The title (text before the first blank line) heads every part, and the doc
+ * comment is cut to an eighth of the limit. Each part's body gets half of
+ * what's left, since the snippet, when present, repeats it.
+ */
+function chunkDocument(doc: Document, bytes: number, maxBytes: number): Document[] {
+  const separator = doc.text.indexOf('\n\n');
+  const title = separator >= 0 ? doc.text.slice(0, separator) : (doc.metadata.name ?? '');
+  const body = doc.metadata.snippet ?? (separator >= 0 ? doc.text.slice(separator + 2) : doc.text);
+  const docstring =
+    doc.metadata.docstring === undefined
+      ? undefined
+      : clipBytes(doc.metadata.docstring, Math.floor(maxBytes / 8));
+  const room = maxBytes - Buffer.byteLength(docstring ?? '');
+  const budget = Math.max(1, Math.floor(room / 2) - Buffer.byteLength(title) - 32);
+
+  const parts: { lines: string[]; start: number; bytes: number }[] = [];
+  for (const [index, line] of body.split('\n').entries()) {
+    const clipped = clipBytes(line, budget);
+    const size = Buffer.byteLength(clipped) + 1;
+    let current = parts.at(-1);
+    if (!current || current.bytes + size > budget) {
+      current = { lines: [], start: index, bytes: 0 };
+      parts.push(current);
+    }
+    current.lines.push(clipped);
+    current.bytes += size;
+  }
+
+  const name = doc.metadata.name ?? title;
+  return parts.map((part, index) => {
+    const content = part.lines.join('\n');
+    const startLine = doc.metadata.startLine + part.start;
+    const partName = `${name} (part ${index + 1} of ${parts.length})`;
+    return {
+      ...doc,
+      id: `${doc.metadata.file}:${partName}:${startLine}`,
+      text: `${title} (part ${index + 1} of ${parts.length})\n\n${content}`,
+      metadata: {
+        ...doc.metadata,
+        name: partName,
+        startLine,
+        endLine: Math.min(doc.metadata.endLine, startLine + part.lines.length - 1),
+        docstring,
+        ...(doc.metadata.snippet === undefined ? {} : { snippet: content }),
+        overflow: {
+          policy: 'chunked',
+          originalBytes: bytes,
+          part: index + 1,
+          parts: parts.length,
+        },
+      },
+    };
+  });
+}
+
+/**
+ * Cut text to at most maxBytes (UTF-8), at a line break when one falls in the
+ * second half, and mark the cut
+ */
+function clipBytes(text: string, maxBytes: number): string {
+  if (Buffer.byteLength(text) <= maxBytes) return text;
+
+  const room = Math.max(0, maxBytes - TRUNCATION_MARKER.length);
+  // Decoding a cut multi-byte character leaves a replacement character; drop it
+  let clipped = Buffer.from(text).subarray(0, room).toString().replace(/\uFFFD+$/, '');
+  const newline = clipped.lastIndexOf('\n');
+  if (newline > clipped.length / 2) clipped = clipped.slice(0, newline);
+  return `${clipped}${TRUNCATION_MARKER}`;
+}
@@ -0,0 +1,244 @@
+/**
+ * Build Constraints
+ * Which platforms a Go file is built for, from its `//go:build` line and name
+ *
+ * A file can be limited by a build line (`//go:build linux && !arm`, or the
+ * older `// +build` lines) and by a `_GOOS`, `_GOARCH`, or `_GOOS_GOARCH`
+ * name suffix (`poll_windows.go`, `asm_linux_amd64.go`). Both are folded into
+ * one expression, so `poll_windows.go` with `//go:build cgo` reads
+ * `cgo && windows`, and evaluated against a target platform the way
+ * `go build` would.
+ */
+
+import type { GoPlatform } from './types';
+
+/** Operating systems Go recognizes in file name suffixes */
+export const KNOWN_GOOS = new Set([
+  'aix',
+  'android',
+  'darwin',
+  'dragonfly',
+  'freebsd',
+  'hurd',
+  'illumos',
+  'ios',
+  'js',
+  'linux',
+  'nacl',
+  'netbsd',
+  'openbsd',
+  'plan9',
+  'solaris',
+  'wasip1',
+  'windows',
+  'zos',
+]);
+
+/** Architectures Go recognizes in file name suffixes */
+export const KNOWN_GOARCH = new Set([
+  '386',
+  'amd64',
+  'amd64p32',
+  'arm',
+  'armbe',
+  'arm64',
+  'arm64be',
+  'loong64',
+  'mips',
+  'mipsle',
+  'mips64',
+  'mips64le',
+  'mips64p32',
+  'mips64p32le',
+  'ppc',
+  'ppc64',
+  'ppc64le',
+  'riscv',
+  'riscv64',
+  's390',
+  's390x',
+  'sparc',
+  'sparc64',
+  'wasm',
+]);
+
+/** Operating systems satisfying the `unix` tag */
+const UNIX_GOOS = new Set([
+  'aix',
+  'android',
+  'darwin',
+  'dragonfly',
+  'freebsd',
+  'hurd',
+  'illumos',
+  'ios',
+  'linux',
+  'netbsd',
+  'openbsd',
+  'solaris',
+]);
+
+/** Node's process.platform and process.arch names that differ from Go's */
+const NODE_TO_GOOS: Record<string, string> = { win32: 'windows', sunos: 'solaris' };
+const NODE_TO_GOARCH: Record<string, string> = { x64: 'amd64', ia32: '386', x32: '386' };
+
+/**
+ * Build constraint of a Go file, combining its build line and name suffix
+ *
+ * @param sourceText - File contents; only the header before the package clause is read
+ * @param file - File path or name
+ * @returns The constraint expression, or undefined for a file built everywhere
+ */
+export function parseGoBuildConstraint(sourceText: string, file: string): string | undefined {
+  const line = buildLine(sourceText);
+  const terms = fileNameTerms(file);
+  const parts = line ? [terms.length > 0 && line.includes('||') ? `(${line})` : line] : [];
+  parts.push(...terms);
+  return parts.length > 0 ? parts.join(' && ') : undefined;
+}
+
+/**
+ * Whether a build constraint holds for a platform
+ *
+ * The platform's GOOS and GOARCH, `unix` for Unix-like systems, `gc`, `cgo`,
+ * every `go1.N` release tag, and the platform's extra tags are satisfied.
+ * A constraint that doesn't parse is treated as satisfied, so a file is
+ * never hidden because of it.
+ *
+ * @param constraint - Expression from parseGoBuildConstraint
+ * @param platform - Target platform
+ */
+export function matchesGoPlatform(constraint: string, platform: GoPlatform): boolean {
+  const tags = new Set([platform.goos, platform.goarch, 'gc', 'cgo', ...(platform.tags ?? [])]);
+  if (UNIX_GOOS.has(platform.goos)) tags.add('unix');
+  // android and ios builds also satisfy linux and darwin
+  if (platform.goos === 'android') tags.add('linux');
+  if (platform.goos === 'ios') tags.add('darwin');
+
+  const satisfied = (tag: string) => tags.has(tag) || /^go1\.\d+$/.test(tag);
+  try {
+    return evaluate(constraint, satisfied);
+  } catch {
+    return true;
+  }
+}
+
+/**
+ * Fill in a target platform: explicit values first, then the GOOS and GOARCH
+ * environment variables, then the host
+ *
+ * @param platform - Configured values, any of which may be missing
+ * @param env - Environment to read GOOS and GOARCH from
+ */
+export function resolveGoPlatform(
+  platform: Partial<GoPlatform> = {},
+  env: Record<string, string | undefined> = process.env
+): GoPlatform {
+  return {
+    goos: platform.goos || env.GOOS || NODE_TO_GOOS[process.platform] || process.platform,
+    goarch: platform.goarch || env.GOARCH || NODE_TO_GOARCH[process.arch] || process.arch,
+    tags: platform.tags ?? [],
+  };
+}
+
+/**
+ * The `//go:build` expression of a file's header, or its `// +build` lines
+ * rewritten as one
+ */
+function buildLine(sourceText: string): string | undefined {
+  const plusBuild: string[] = [];
+  let inBlockComment = false;
+
+  for (const raw of sourceText.split('\n')) {
+    const line = raw.trim();
+    if (inBlockComment) {
+      inBlockComment = !line.includes('*/');
+      continue;
+    }
+    if (line.startsWith('/*')) {
+      inBlockComment = !line.includes('*/');
+      continue;
+    }
+    if (line.startsWith('//go:build ')) return line.slice('//go:build '.length).trim();
+    if (/^\/\/ *\+build /.test(line)) {
+      plusBuild.push(plusBuildExpression(line.replace(/^\/\/ *\+build /, '')));
+      continue;
+    }
+    if (line !== '' && !line.startsWith('//')) break;
+  }
+
+  if (plusBuild.length === 0) return undefined;
+  if (plusBuild.length === 1) return plusBuild[0];
+  return plusBuild.map((expr) => (expr.includes('||') ? `(${expr})` : expr)).join(' && ');
+}
+
+/**
+ * `// +build linux,amd64 darwin`: spaces separate alternatives, commas join terms
+ */
+function plusBuildExpression(options: string): string {
+  return options
+    .split(/\s+/)
+    .filter(Boolean)
+    .map((option) => option.split(',').join(' && '))
+    .join(' || ');
+}
+
+/**
+ * GOOS and GOARCH terms implied by a file name suffix
+ */
+function fileNameTerms(file: string): string[] {
+  const base = file.slice(file.lastIndexOf('/') + 1).replace(/\.go$/, '').replace(/_test$/, '');
+  const parts = base.split('_');
+  // The suffix needs something before it: linux.go is built everywhere
+  const last = parts.length > 1 ? parts[parts.length - 1] : undefined;
+  const previous = parts.length > 2 ? parts[parts.length - 2] : undefined;
+
+  if (last && KNOWN_GOARCH.has(last)) {
+    return previous && KNOWN_GOOS.has(previous) ? [previous, last] : [last];
+  }
+  return last && KNOWN_GOOS.has(last) ? [last] : [];
+}
+
+/**
+ * Evaluate a build expression: `!`, `&&`, `||`, and parentheses over tags
+ */
+function evaluate(expression: string, satisfied: (tag: string) => boolean): boolean {
+  const tokens = expression.match(/&&|\|\||[!()]|[\w.]+/g) ?? [];
+  let position = 0;
+
+  const or = (): boolean => {
+    let value = and();
+    while (tokens[position] === '||') {
+      position++;
+      const right = and();
+      value = value || right;
+    }
+    return value;
+  };
+  const and = (): boolean => {
+    let value = not();
+    while (tokens[position] === '&&') {
+      position++;
+      const right = not();
+      value = value && right;
+    }
+    return value;
+  };
+  const not = (): boolean => {
+    const token = tokens[position++];
+    if (token === '!') return !not();
+    if (token === '(') {
+      const value = or();
+      if (tokens[position++] !== ')') throw new Error(`Unbalanced build constraint: ${expression}`);
+      return value;
+    }
+    if (!token || !/^[\w.]+$/.test(token)) {
+      throw new Error(`Invalid build constraint: ${expression}`);
+    }
+    return satisfied(token);
+  };
+
+  const value = or();
+  if (position !== tokens.length) throw new Error(`Invalid build constraint: ${expression}`);
+  return value;
+}
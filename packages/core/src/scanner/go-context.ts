@@ -0,0 +1,185 @@
+/**
+ * Context Propagation
+ * How Go functions thread `context.Context`, as lint-style findings
+ *
+ * Idiomatic Go takes a ctx as the first parameter and passes it to every call
+ * that can block. Three departures are flagged:
+ *
+ * - `ignored`: a named ctx parameter the body never uses, when no call is
+ *   flagged for it already
+ * - `not-propagated`: a ctx is in scope, but a call gets `context.Background()`,
+ *   `context.TODO()`, or `nil` instead, or uses the context-less variant of an
+ *   API (`db.Query` rather than `db.QueryContext`)
+ * - `missing`: no ctx parameter, yet the function starts a root context for a
+ *   call or uses one of those context-less APIs; `main` and `init` are exempt
+ *
+ * Context-taking callees are known from the same file (functions and methods
+ * whose first parameter is a `context.Context`) and from a list of standard
+ * library APIs, so calls into other files are only checked for root contexts.
+ */
+
+import type { TreeSitterNode } from './tree-sitter';
+import type { ContextFinding } from './types';
+
+/** Calls that start a new root context */
+const ROOT_CONTEXTS = new Set(['context.Background()', 'context.TODO()']);
+
+/** database/sql methods with a context-taking variant, when called with arguments */
+const CONTEXT_METHOD_VARIANTS: Record<string, string> = {
+  Query: 'QueryContext',
+  QueryRow: 'QueryRowContext',
+  Exec: 'ExecContext',
+  Prepare: 'PrepareContext',
+};
+
+const NEW_REQUEST_WITH_CONTEXT = 'http.NewRequestWithContext and Client.Do';
+
+/** Package functions with a context-taking variant */
+const CONTEXT_FUNCTION_VARIANTS: Record<string, string> = {
+  'http.Get': NEW_REQUEST_WITH_CONTEXT,
+  'http.Head': NEW_REQUEST_WITH_CONTEXT,
+  'http.Post': NEW_REQUEST_WITH_CONTEXT,
+  'http.PostForm': NEW_REQUEST_WITH_CONTEXT,
+  'http.NewRequest': 'http.NewRequestWithContext',
+  'exec.Command': 'exec.CommandContext',
+  'net.Dial': 'net.Dialer.DialContext',
+  'net.DialTimeout': 'net.Dialer.DialContext',
+};
+
+/** Functions that may start root contexts: nothing calls them with one */
+const ROOT_FUNCTIONS = new Set(['main', 'init']);
+
+/**
+ * Names of the functions and methods in a file that take a context.Context first
+ *
+ * @param root - Root node of the file
+ */
+export function goContextFunctions(root: TreeSitterNode): Set<string> {
+  const names = new Set<string>();
+  for (const declaration of root.namedChildren) {
+    if (declaration.type !== 'function_declaration' && declaration.type !== 'method_declaration') {
+      continue;
+    }
+    const first = declaration.childForFieldName('parameters')?.namedChildren[0];
+    const name = declaration.childForFieldName('name')?.text;
+    if (name && first && isContextType(first)) names.add(name);
+  }
+  return names;
+}
+
+/**
+ * Find the context propagation findings of a function or method, in line order
+ *
+ * @param declaration - Function or method declaration
+ * @param contextFunctions - Same-file callees taking a context first (see goContextFunctions)
+ */
+export function extractGoContextFindings(
+  declaration: TreeSitterNode,
+  contextFunctions: Set<string> = new Set()
+): ContextFinding[] {
+  const body = declaration.childForFieldName('body');
+  if (!body) return [];
+  const ctx = contextParameter(declaration);
+  const name = declaration.childForFieldName('name')?.text ?? '';
+  const findings: ContextFinding[] = [];
+
+  const flag = (call: string, line: number, withCtx: string, withoutCtx: string) => {
+    if (ctx) {
+      findings.push({ kind: 'not-propagated', line, call, suggestion: withCtx });
+    } else if (!ROOT_FUNCTIONS.has(name)) {
+      findings.push({ kind: 'missing', line, call, suggestion: withoutCtx });
+    }
+  };
+
+  for (const callNode of calls(body)) {
+    const fn = callNode.childForFieldName('function');
+    if (!fn || (fn.type !== 'identifier' && fn.type !== 'selector_expression')) continue;
+    const call = fn.text.replace(/\s+/g, '');
+    const args = callNode.childForFieldName('arguments')?.namedChildren ?? [];
+    const line = callNode.startPosition.row + 1;
+    const callName = call.slice(call.lastIndexOf('.') + 1);
+
+    const root = args.map((arg) => arg.text.replace(/\s+/g, '')).find((a) => ROOT_CONTEXTS.has(a));
+    const variant =
+      CONTEXT_FUNCTION_VARIANTS[call] ??
+      (fn.type === 'selector_expression' && args.length > 0
+        ? qualifiedVariant(call, CONTEXT_METHOD_VARIANTS[callName])
+        : undefined);
+
+    if (root) {
+      flag(
+        call,
+        line,
+        `passes ${root} although ${ctx} is in scope; pass ${ctx}`,
+        `starts a root context with ${root}; take a ctx parameter and pass it`
+      );
+    } else if (ctx && contextFunctions.has(callName) && args[0]?.text === 'nil') {
+      findings.push({
+        kind: 'not-propagated',
+        line,
+        call,
+        suggestion: `passes nil for the context; pass ${ctx}`,
+      });
+    } else if (variant) {
+      flag(
+        call,
+        line,
+        `ignores ${ctx}; use ${variant} with it`,
+        `can block without a context; take a ctx parameter and use ${variant}`
+      );
+    }
+  }
+
+  // A call flagged above already says where ctx should have gone
+  if (findings.length === 0 && ctx && ctx !== '_' && !usesIdentifier(body, ctx)) {
+    findings.push({
+      kind: 'ignored',
+      line: declaration.startPosition.row + 1,
+      suggestion: `${ctx} is never used; pass it to the calls that can block, or name it _`,
+    });
+  }
+
+  return findings.sort((a, b) => a.line - b.line);
+}
+
+/**
+ * Name of a declaration's context.Context parameter: `_` when blank or
+ * unnamed, undefined when there is none
+ */
+function contextParameter(declaration: TreeSitterNode): string | undefined {
+  const parameters = declaration.childForFieldName('parameters')?.namedChildren ?? [];
+  const param = parameters.find(isContextType);
+  if (!param) return undefined;
+  return param.namedChildren.find((child) => child.type === 'identifier')?.text ?? '_';
+}
+
+function isContextType(param: TreeSitterNode): boolean {
+  return param.childForFieldName('type')?.text.replace(/\s+/g, '') === 'context.Context';
+}
+
+/**
+ * Variant named on the same receiver: `db.Query` becomes `db.QueryContext`
+ */
+function qualifiedVariant(call: string, variant: string | undefined): string | undefined {
+  return variant && `${call.slice(0, call.lastIndexOf('.') + 1)}${variant}`;
+}
+
+function usesIdentifier(node: TreeSitterNode, name: string): boolean {
+  if (node.type === 'identifier' && node.text === name) return true;
+  return node.namedChildren.some((child) => usesIdentifier(child, name));
+}
+
+/**
+ * Call expressions under a node, func literals included: they run with the
+ * enclosing function's ctx in scope
+ */
+function calls(node: TreeSitterNode): TreeSitterNode[] {
+  const found: TreeSitterNode[] = [];
+  const stack = [node];
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'call_expression') found.push(current);
+    stack.push(...current.namedChildren);
+  }
+  return found;
+}
@@ -0,0 +1,386 @@
+/**
+ * Go Modules
+ * Discovery of go.mod files and module-aware import resolution
+ *
+ * A repository can contain several Go modules. Every Go file belongs to the
+ * module whose go.mod is in the nearest enclosing directory, and an import
+ * path is local when it starts with one of the repository's module paths.
+ *
+ * Each module's dependencies come from its require and replace directives,
+ * so a replaced module reports the version actually built. Before Go 1.17,
+ * go.mod lists only some indirect dependencies; the rest are read from go.sum.
+ */
+
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+import { globby } from 'globby';
+
+/**
+ * A Go module declared by a go.mod file
+ */
+export interface GoModule {
+  /** Module path from the `module` directive (e.g. github.com/acme/api) */
+  path: string;
+  /** Directory containing go.mod, relative to the repository root ("." for the root) */
+  dir: string;
+  /** Language version from the `go` directive (e.g. "1.22" or "1.22.3"), when present */
+  goVersion?: string;
+  /** Modules it requires, sorted by path (see parseGoDependencies) */
+  dependencies?: GoDependency[];
+}
+
+/**
+ * A module another module depends on
+ */
+export interface GoDependency {
+  /** Module path as required (e.g. golang.org/x/net) */
+  path: string;
+  /** Version in effect: a replacement's version when a replace directive gives one */
+  version: string;
+  /** Marked `// indirect` in go.mod, or known only from go.sum */
+  indirect: boolean;
+  /** Target of a replace directive */
+  replacedBy?: GoReplacement;
+  /** Version the require directive asks for, when a replacement changed it */
+  requiredVersion?: string;
+}
+
+/**
+ * Target of a replace directive: another module version, or a local
+ * directory (a path starting with `./` or `../`) without a version
+ */
+export interface GoReplacement {
+  path: string;
+  version?: string;
+}
+
+/**
+ * A Go import path resolved to a package inside the repository
+ */
+export interface ResolvedGoImport {
+  /** Module that provides the package */
+  module: GoModule;
+  /** Package directory relative to the repository root */
+  dir: string;
+}
+
+/**
+ * Parse the module path from go.mod content
+ *
+ * @returns Module path, or null if there is no module directive
+ */
+export function parseGoModulePath(content: string): string | null {
+  for (const rawLine of content.split('\n')) {
+    const line = rawLine.replace(/\/\/.*$/, '').trim();
+    const match = line.match(/^module\s+("?)([^\s"]+)\1$/);
+    if (match) {
+      return match[2];
+    }
+  }
+  return null;
+}
+
+/**
+ * Parse the language version from go.mod content (the `go` directive)
+ *
+ * Accepts release versions with and without a patch level (`go 1.21`,
+ * `go 1.22.3`) and pre-releases (`go 1.21rc1`).
+ *
+ * @returns Version as written, or null if there is no valid go directive
+ */
+export function parseGoVersion(content: string): string | null {
+  for (const rawLine of content.split('\n')) {
+    const line = rawLine.replace(/\/\/.*$/, '').trim();
+    const match = line.match(/^go\s+(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta)\d+)?)$/);
+    if (match) {
+      return match[1];
+    }
+  }
+  return null;
+}
+
+/**
+ * Parse the dependencies from go.mod content, with replace directives applied
+ *
+ * A replacement with a version (`replace a => b v1.2.0`) makes that the
+ * effective version; one pointing at a directory (`replace a => ../a`) keeps
+ * the required version, since the directory's code is built whatever it is.
+ * A replace naming a version on its left side only applies to that version.
+ *
+ * @param sumContent - go.sum content; for modules before Go 1.17, modules it
+ * lists that go.mod doesn't are added as indirect, at their highest version
+ * @returns Dependencies sorted by path
+ */
+export function parseGoDependencies(content: string, sumContent?: string): GoDependency[] {
+  const requires = new Map<string, { version: string; indirect: boolean }>();
+  const replaces: { path: string; version?: string; to: GoReplacement }[] = [];
+
+  let block: string | null = null;
+  for (const rawLine of content.split('\n')) {
+    const indirect = /\/\/\s*indirect\b/.test(rawLine);
+    const line = rawLine.replace(/\/\/.*$/, '').trim();
+    if (!line) continue;
+    if (block) {
+      if (line === ')') {
+        block = null;
+        continue;
+      }
+    } else {
+      const opened = line.match(/^(\w+)\s*\($/);
+      if (opened) {
+        block = opened[1];
+        continue;
+      }
+    }
+    const directive = block ?? line.split(/\s+/)[0];
+    const rest = block ? line : line.slice(directive.length).trim();
+    const fields = rest.split(/\s+/).map(unquote);
+    if (directive === 'require' && fields.length >= 2) {
+      requires.set(fields[0], { version: fields[1], indirect });
+    } else if (directive === 'replace') {
+      const arrow = fields.indexOf('=>');
+      if (arrow < 1 || arrow === fields.length - 1) continue;
+      const [to, toVersion] = fields.slice(arrow + 1);
+      replaces.push({
+        path: fields[0],
+        ...(arrow === 2 ? { version: fields[1] } : {}),
+        to: { path: to, ...(toVersion ? { version: toVersion } : {}) },
+      });
+    }
+  }
+
+  const goVersion = parseGoVersion(content);
+  if (sumContent && (!goVersion || !goVersionAtLeast(goVersion, '1.17'))) {
+    for (const [modulePath, version] of parseGoSum(sumContent)) {
+      if (!requires.has(modulePath)) requires.set(modulePath, { version, indirect: true });
+    }
+  }
+
+  const dependencies: GoDependency[] = [];
+  for (const [modulePath, required] of requires) {
+    // A replace for the exact version wins over one for every version
+    const replace =
+      replaces.find((r) => r.path === modulePath && r.version === required.version) ??
+      replaces.find((r) => r.path === modulePath && r.version === undefined);
+    const version = replace?.to.version ?? required.version;
+    dependencies.push({
+      path: modulePath,
+      version,
+      indirect: required.indirect,
+      ...(replace ? { replacedBy: replace.to } : {}),
+      ...(version !== required.version ? { requiredVersion: required.version } : {}),
+    });
+  }
+  return dependencies.sort((a, b) => (a.path < b.path ? -1 : a.path > b.path ? 1 : 0));
+}
+
+/**
+ * Highest version of each module whose code go.sum has a hash for
+ *
+ * Lines for a module's go.mod alone (`v1.2.0/go.mod`) are skipped: those
+ * modules were only consulted for the build list, not built.
+ */
+export function parseGoSum(content: string): Map<string, string> {
+  const versions = new Map<string, string>();
+  for (const line of content.split('\n')) {
+    const [modulePath, version] = line.trim().split(/\s+/);
+    if (!modulePath || !version || version.endsWith('/go.mod')) continue;
+    const current = versions.get(modulePath);
+    if (!current || compareModuleVersions(version, current) > 0) {
+      versions.set(modulePath, version);
+    }
+  }
+  return versions;
+}
+
+/**
+ * Compare two module versions by semver precedence (`v1.10.0` > `v1.9.2`,
+ * pre-releases and pseudo-versions before their release)
+ */
+export function compareModuleVersions(a: string, b: string): number {
+  const parse = (version: string) => {
+    const [release, ...pre] = version.replace(/^v/, '').replace(/\+.*$/, '').split('-');
+    return { parts: release.split('.').map((part) => Number.parseInt(part, 10) || 0), pre };
+  };
+  const left = parse(a);
+  const right = parse(b);
+  for (let i = 0; i < Math.max(left.parts.length, right.parts.length); i++) {
+    const diff = (left.parts[i] ?? 0) - (right.parts[i] ?? 0);
+    if (diff !== 0) return diff;
+  }
+  if (left.pre.length === 0 || right.pre.length === 0) {
+    return right.pre.length - left.pre.length;
+  }
+  const leftPre = left.pre.join('-');
+  const rightPre = right.pre.join('-');
+  return leftPre < rightPre ? -1 : leftPre > rightPre ? 1 : 0;
+}
+
+/**
+ * Dependency of a module providing an import path; the longest module path
+ * wins, so github.com/acme/api/v2 isn't mistaken for github.com/acme/api
+ *
+ * @returns The dependency, or null for stdlib, local, and unlisted imports
+ */
+export function resolveGoDependency(importPath: string, module: GoModule): GoDependency | null {
+  let best: GoDependency | null = null;
+  for (const dependency of module.dependencies ?? []) {
+    if (importPath !== dependency.path && !importPath.startsWith(`${dependency.path}/`)) continue;
+    if (!best || dependency.path.length > best.path.length) {
+      best = dependency;
+    }
+  }
+  return best;
+}
+
+/**
+ * Whether a Go version is at least a minimum, comparing major, minor, and patch
+ *
+ * Pre-releases count as their release (`1.22rc1` is at least `1.22`), which
+ * matches how the go directive selects language semantics.
+ *
+ * @example goVersionAtLeast('1.22.3', '1.22') // true
+ */
+export function goVersionAtLeast(version: string, minimum: string): boolean {
+  const parse = (v: string) =>
+    v
+      .replace(/^go/, '')
+      .replace(/(rc|beta)\d+$/, '')
+      .split('.')
+      .map((part) => Number.parseInt(part, 10) || 0);
+  const actual = parse(version);
+  const wanted = parse(minimum);
+  for (let i = 0; i < Math.max(actual.length, wanted.length); i++) {
+    const diff = (actual[i] ?? 0) - (wanted[i] ?? 0);
+    if (diff !== 0) return diff > 0;
+  }
+  return true;
+}
+
+/**
+ * Find all Go modules in a repository
+ *
+ * Vendored and testdata modules are skipped. Results are ordered by
+ * directory so output is deterministic.
+ */
+export async function findGoModules(repoRoot: string): Promise<GoModule[]> {
+  const files = await globby('**/go.mod', {
+    cwd: repoRoot,
+    ignore: ['**/node_modules/**', '**/vendor/**', '**/testdata/**', '**/.git/**'],
+    gitignore: true,
+  });
+
+  const modules: GoModule[] = [];
+  for (const file of files) {
+    try {
+      const content = await fs.readFile(path.join(repoRoot, file), 'utf-8');
+      const sumFile = path.join(repoRoot, path.dirname(file), 'go.sum');
+      const sumContent = await fs.readFile(sumFile, 'utf-8').catch(() => undefined);
+      const module = parseGoModule(file, content, sumContent);
+      if (module) {
+        modules.push(module);
+      }
+    } catch {
+      // Unreadable go.mod: treat the directory as outside any module
+    }
+  }
+
+  return sortGoModules(modules);
+}
+
+/**
+ * Read a module from go.mod content
+ *
+ * @param file - Path of the go.mod file relative to the repository root
+ * @param sumContent - The module's go.sum content, when there is one
+ * @returns The module, or null if there's no module directive
+ */
+export function parseGoModule(
+  file: string,
+  content: string,
+  sumContent?: string
+): GoModule | null {
+  const modulePath = parseGoModulePath(content);
+  if (!modulePath) return null;
+  const goVersion = parseGoVersion(content);
+  const dependencies = parseGoDependencies(content, sumContent);
+  return {
+    path: modulePath,
+    dir: path.posix.dirname(file),
+    ...(goVersion ? { goVersion } : {}),
+    ...(dependencies.length > 0 ? { dependencies } : {}),
+  };
+}
+
+/**
+ * Order modules by directory so output is deterministic
+ */
+export function sortGoModules(modules: GoModule[]): GoModule[] {
+  return modules.sort((a, b) => (a.dir < b.dir ? -1 : a.dir > b.dir ? 1 : 0));
+}
+
+/**
+ * Find the module that owns a file (nearest enclosing go.mod)
+ *
+ * @param file - File path relative to the repository root
+ */
+export function findOwningModule(file: string, modules: GoModule[]): GoModule | null {
+  let best: GoModule | null = null;
+  for (const module of modules) {
+    if (!isWithin(file, module.dir)) continue;
+    if (!best || module.dir.length > best.dir.length) {
+      best = module;
+    }
+  }
+  return best;
+}
+
+/**
+ * Resolve an import path to a package directory in the repository
+ *
+ * The module with the longest matching path wins, so nested modules
+ * (e.g. github.com/acme/api/v2 inside github.com/acme/api) resolve correctly.
+ *
+ * @returns The resolved package, or null for stdlib and external imports
+ */
+export function resolveGoImport(importPath: string, modules: GoModule[]): ResolvedGoImport | null {
+  let best: GoModule | null = null;
+  for (const module of modules) {
+    if (importPath !== module.path && !importPath.startsWith(`${module.path}/`)) continue;
+    if (!best || module.path.length > best.path.length) {
+      best = module;
+    }
+  }
+  if (!best) return null;
+
+  const rest = importPath.slice(best.path.length).replace(/^\//, '');
+  const dir = rest ? path.posix.join(best.dir, rest) : best.dir;
+  return { module: best, dir };
+}
+
+/**
+ * Whether code in one package directory may import another under Go's
+ * `internal` rule
+ *
+ * A package with an `internal` element in its path is only importable from the
+ * tree rooted at the parent of that element (the last one, when nested):
+ * `a/b/internal/c` is visible to `a/b` and below, not to `a/x`.
+ *
+ * @param importerDir - Importing package directory, relative to the repository root
+ * @param packageDir - Imported package directory, relative to the repository root
+ */
+export function canImportInternal(importerDir: string, packageDir: string): boolean {
+  const parts = packageDir.split('/');
+  const internal = parts.lastIndexOf('internal');
+  if (internal === -1) return true;
+  const root = parts.slice(0, internal).join('/');
+  return root === '' || root === '.' || importerDir === root || importerDir.startsWith(`${root}/`);
+}
+
+function unquote(field: string): string {
+  return field.replace(/^"(.*)"$/, '$1');
+}
+
+function isWithin(file: string, dir: string): boolean {
+  return dir === '.' || file.startsWith(`${dir}/`);
+}
@@ -9,17 +9,60 @@ import * as path from 'node:path';
 import type { Logger } from '@lytics/kero';
 import {
   type FileSystemValidator,
+  InMemoryFileSystem,
   NodeFileSystemValidator,
   validateFile,
 } from '../utils/file-validator';
+import { computeGoComplexity } from './complexity';
+import { parseGoBuildConstraint } from './go-build';
+import { extractGoContextFindings, goContextFunctions } from './go-context';
+import { parseGeneratedHeader } from './go-generated';
+import {
+  findGoModules,
+  findOwningModule,
+  type GoModule,
+  parseGoModule,
+  resolveGoDependency,
+  sortGoModules,
+} from './go-modules';
+import {
+  describeRoutes,
+  extractGoRoutes,
+  GO_ROUTE_PATTERNS,
+  type RoutePattern,
+  routePatternsFor,
+} from './http-routes';
+import { describeSqlQueries, extractGoSqlQueries } from './sql-queries';
 import {
   extractGoDocComment,
+  findSyntaxError,
   initTreeSitter,
   loadLanguage,
   type ParsedTree,
   parseCode,
+  type SyntaxErrorInfo,
+  type TreeSitterNode,
 } from './tree-sitter';
-import type { Document, Scanner, ScannerCapabilities } from './types';
+import type {
+  CalleeInfo,
+  ConstructorConfidence,
+  CrashKind,
+  CrashSite,
+  DeferredCall,
+  Document,
+  DocumentMetadata,
+  GoDependencyUse,
+  GoExample,
+  GoFuncLiteral,
+  GoIterator,
+  GoTypeSet,
+  ReturnedError,
+  ScanError,
+  Scanner,
+  ScannerCapabilities,
+  StructField,
+} from './types';
+import { isGoExported, isGoMethodExported } from './visibility';
 
 /**
  * Tree-sitter queries for Go code extraction
@@ -79,12 +122,9 @@ const GO_QUERIES = {
         ] @alias_type)) @definition
   `,
 
-  // Const declarations
+  // Const declarations (specs are walked individually for iota and implicit repetition)
   constants: `
-    (const_declaration
-      (const_spec
-        name: (identifier) @name
-        value: (_)? @value)) @definition
+    (const_declaration) @definition
   `,
 
   // Var declarations (package-level)
@@ -95,6 +135,20 @@ const GO_QUERIES = {
         value: (_)? @value)) @definition
   `,
 
+  // Blank var specs with a type: compile-time interface assertions like var _ I = (*T)(nil)
+  assertions: `
+    (var_spec
+      name: (identifier) @name
+      type: (_) @interface
+      value: (expression_list . (_) @value)) @definition
+  `,
+
+  // Import specs (single and grouped)
+  imports: `
+    (import_spec
+      path: (_) @path)
+  `,
+
   // Package declaration
   package: `
     (package_clause
@@ -102,6 +156,21 @@ const GO_QUERIES = {
   `,
 };
 
+/** Import path of cgo's pseudo-package */
+const CGO_PSEUDO_PACKAGE = 'C';
+
+/** Calls that can hard-crash the process, by kind */
+const GO_CRASH_CALLS: Record<string, CrashKind> = {
+  panic: 'panic',
+  'log.Panic': 'panic',
+  'log.Panicf': 'panic',
+  'log.Panicln': 'panic',
+  'log.Fatal': 'fatal',
+  'log.Fatalf': 'fatal',
+  'log.Fatalln': 'fatal',
+  'os.Exit': 'exit',
+};
+
 /**
  * Go scanner using tree-sitter for parsing
  */
@@ -119,8 +188,15 @@ export class GoScanner implements Scanner {
   /** File validator (injected for testability) */
   private fileValidator: FileSystemValidator;
 
-  constructor(fileValidator: FileSystemValidator = new NodeFileSystemValidator()) {
+  /** Route registration patterns (see http-routes.ts) */
+  private routePatterns: RoutePattern[];
+
+  constructor(
+    fileValidator: FileSystemValidator = new NodeFileSystemValidator(),
+    routePatterns: RoutePattern[] = GO_ROUTE_PATTERNS
+  ) {
     this.fileValidator = fileValidator;
+    this.routePatterns = routePatterns;
   }
 
   canHandle(filePath: string): boolean {
@@ -152,7 +228,9 @@ export class GoScanner implements Scanner {
     files: string[],
     repoRoot: string,
     logger?: Logger,
-    onProgress?: (filesProcessed: number, totalFiles: number) => void
+    onProgress?: (filesProcessed: number, totalFiles: number) => void,
+    onError?: (error: ScanError) => void,
+    signal?: AbortSignal
   ): Promise<Document[]> {
     const documents: Document[] = [];
     const total = files.length;
@@ -178,21 +256,28 @@ export class GoScanner implements Scanner {
       );
     }
 
+    // Module boundaries for multi-module repositories
+    const modules = await this.findModules(repoRoot);
+    if (modules.length > 1) {
+      logger?.debug({ modules: modules.map((m) => m.path) }, 'Found multiple Go modules');
+    }
+
     const startTime = Date.now();
     let lastLogTime = startTime;
 
     for (let i = 0; i < total; i++) {
+      signal?.throwIfAborted();
       const file = files[i];
       const fileStartTime = Date.now();
 
-      // Report progress via callback every 50 files OR every 10 seconds
-      const now = Date.now();
-      const timeSinceLastLog = now - lastLogTime;
-
-      if (onProgress && i > 0 && (i % 50 === 0 || timeSinceLastLog > 10000)) {
+      // Report progress before every file; the registry throttles what reaches listeners
+      if (onProgress && i > 0) {
         onProgress(i, total);
       }
 
+      const now = Date.now();
+      const timeSinceLastLog = now - lastLogTime;
+
       // Log progress every 50 files OR every 10 seconds
       if (logger && i > 0 && (i % 50 === 0 || timeSinceLastLog > 10000)) {
         lastLogTime = now;
@@ -226,25 +311,32 @@ export class GoScanner implements Scanner {
         // Validate file using testable utility
         const validation = validateFile(file, absolutePath, this.fileValidator);
         if (!validation.isValid) {
-          errors.push({
-            file,
-            absolutePath,
-            error: validation.error || 'Unknown validation error',
-            phase: validation.phase || 'fileValidation',
-          });
+          const error = validation.error || 'Unknown validation error';
+          const phase = validation.phase || 'fileValidation';
+          errors.push({ file, absolutePath, error, phase });
+          onError?.({ file, error, phase });
           continue;
         }
 
         const sourceText = this.fileValidator.readText(absolutePath);
 
-        // Skip generated files
-        if (this.isGeneratedFile(sourceText)) {
-          continue;
-        }
-
-        const fileDocs = await this.extractFromFile(sourceText, file);
+        const { documents: fileDocs, parseError } = await this.extractFromFile(
+          sourceText,
+          file,
+          modules
+        );
         documents.push(...fileDocs);
 
+        // Syntax errors don't stop extraction: tree-sitter recovers and we keep what it found
+        if (parseError) {
+          errors.push({ file, absolutePath, error: parseError.message, phase: 'parse' });
+          onError?.({ file, error: parseError.message, line: parseError.line, phase: 'parse' });
+          logger?.info(
+            { file, line: parseError.line, documents: fileDocs.length },
+            `Go file has syntax errors, indexed partially: ${file}`
+          );
+        }
+
         // Flag slow files (>5s)
         const fileDuration = Date.now() - fileStartTime;
         if (logger && fileDuration > 5000) {
@@ -265,6 +357,7 @@ export class GoScanner implements Scanner {
           phase: 'extractFromFile',
           stack: errorStack,
         });
+        onError?.({ file, error: errorMessage, phase: 'extractFromFile' });
 
         // Log first 10 errors at INFO level, rest at DEBUG
         if (errors.length <= 10) {
@@ -287,6 +380,8 @@ export class GoScanner implements Scanner {
       }
     }
 
+    shareGoPackageDocs(documents);
+
     // Log final summary
     const successCount = documents.length;
     const failureCount = errors.length;
@@ -317,26 +412,60 @@ export class GoScanner implements Scanner {
   }
 
   /**
-   * Check if a file is generated (should be skipped)
+   * Find the Go modules under the repository root
+   *
+   * go.mod files held in memory take precedence over the ones on disk, so an
+   * unsaved go.mod is honored and a purely virtual tree still has modules.
    */
-  private isGeneratedFile(sourceText: string): boolean {
-    const firstLine = sourceText.split('\n')[0] || '';
-    return firstLine.includes('Code generated') || firstLine.includes('DO NOT EDIT');
+  private async findModules(repoRoot: string): Promise<GoModule[]> {
+    const fs = this.fileValidator;
+    if (!(fs instanceof InMemoryFileSystem)) {
+      return findGoModules(repoRoot);
+    }
+
+    const inMemory = fs
+      .files()
+      .filter((file) => path.posix.basename(file) === 'go.mod')
+      .map((file) => {
+        const sumFile = path.posix.join(path.posix.dirname(file), 'go.sum');
+        const sumContent = fs.exists(sumFile) ? fs.readText(sumFile) : undefined;
+        return parseGoModule(file, fs.readText(file), sumContent);
+      })
+      .filter((module): module is GoModule => module !== null);
+    const onDisk = await findGoModules(repoRoot).catch((): GoModule[] => []);
+    const dirs = new Set(inMemory.map((module) => module.dir));
+    return sortGoModules([...inMemory, ...onDisk.filter((module) => !dirs.has(module.dir))]);
   }
 
   /**
    * Extract documents from a single Go file
    */
-  private async extractFromFile(sourceText: string, relativeFile: string): Promise<Document[]> {
+  private async extractFromFile(
+    sourceText: string,
+    relativeFile: string,
+    modules: GoModule[] = []
+  ): Promise<{ documents: Document[]; parseError: SyntaxErrorInfo | null }> {
     const documents: Document[] = [];
     const tree = await parseCode(sourceText, 'go');
     const isTestFile = relativeFile.endsWith('_test.go');
+    const parseError = findSyntaxError(tree.rootNode);
+    const imports = this.extractImports(tree);
+    const usesCgo = imports.includes(CGO_PSEUDO_PACKAGE);
+    const packageName = this.extractPackageName(tree);
+    const packageDoc = this.extractPackageDoc(tree, sourceText);
+    const routePatterns = routePatternsFor(imports, this.routePatterns);
+    const buildConstraint = parseGoBuildConstraint(sourceText, relativeFile);
+    const generated = parseGeneratedHeader(sourceText);
 
     // Extract functions
-    documents.push(...this.extractFunctions(tree, sourceText, relativeFile, isTestFile));
+    documents.push(
+      ...this.extractFunctions(tree, sourceText, relativeFile, isTestFile, usesCgo, routePatterns)
+    );
 
     // Extract methods
-    documents.push(...this.extractMethods(tree, sourceText, relativeFile, isTestFile));
+    documents.push(
+      ...this.extractMethods(tree, sourceText, relativeFile, isTestFile, usesCgo, routePatterns)
+    );
 
     // Extract structs
     documents.push(...this.extractStructs(tree, sourceText, relativeFile, isTestFile));
@@ -350,7 +479,202 @@ export class GoScanner implements Scanner {
     // Extract constants
     documents.push(...this.extractConstants(tree, sourceText, relativeFile, isTestFile));
 
-    return documents;
+    // Extract interface assertions
+    documents.push(...this.extractAssertions(tree, sourceText, relativeFile, isTestFile));
+
+    // Capture the C preamble as text; it is C, not Go
+    const preamble = usesCgo ? this.extractCgoPreamble(tree, relativeFile) : null;
+    if (preamble) {
+      documents.push(preamble);
+    }
+
+    // Attach file-level context: imports, package, owning module, its Go version, and the
+    // dependencies imported, cgo usage, build constraint, and generator. "C" isn't a real
+    // package, so it's left out of the imports.
+    const goImports = imports.filter((imp) => imp !== CGO_PSEUDO_PACKAGE);
+    const module = findOwningModule(relativeFile, modules);
+    const dependencies = module ? importedDependencies(goImports, module) : [];
+    for (const doc of documents) {
+      if (packageName) {
+        doc.metadata.packageName = packageName;
+      }
+      if (packageDoc) {
+        doc.metadata.packageDoc = packageDoc;
+      }
+      if (goImports.length > 0) {
+        doc.metadata.imports = goImports;
+      }
+      if (module) {
+        doc.metadata.module = module.path;
+      }
+      if (module?.goVersion) {
+        doc.metadata.goVersion = module.goVersion;
+      }
+      if (dependencies.length > 0) {
+        doc.metadata.dependencies = dependencies;
+      }
+      doc.metadata.usesCgo = usesCgo;
+      if (buildConstraint) {
+        doc.metadata.buildConstraint = buildConstraint;
+      }
+      if (generated) {
+        doc.metadata.generated = true;
+        if (generated.generator) {
+          doc.metadata.generator = generated.generator;
+        }
+      }
+      if (parseError) {
+        doc.metadata.parseError = parseError.message;
+      }
+      if (doc.type === 'function' || doc.type === 'method') {
+        annotateCrashes(doc, isTestFile, packageName);
+      }
+    }
+    resolveErrorReturns(documents);
+
+    return { documents, parseError };
+  }
+
+  /**
+   * Extract import paths
+   */
+  private extractImports(tree: ParsedTree): string[] {
+    const imports: string[] = [];
+    for (const match of tree.query(GO_QUERIES.imports)) {
+      const pathCapture = match.captures.find((c) => c.name === 'path');
+      if (pathCapture) {
+        imports.push(pathCapture.node.text.slice(1, -1));
+      }
+    }
+    return imports;
+  }
+
+  /**
+   * Extract the package name from the package clause
+   */
+  private extractPackageName(tree: ParsedTree): string | undefined {
+    const [match] = tree.query(GO_QUERIES.package);
+    return match?.captures.find((c) => c.name === 'name')?.node.text;
+  }
+
+  /**
+   * Extract the first paragraph of the package doc comment (`// Package users ...`)
+   *
+   * Comments above the package clause that don't follow the convention, such
+   * as license headers, are ignored.
+   */
+  private extractPackageDoc(tree: ParsedTree, sourceText: string): string | undefined {
+    const [match] = tree.query(GO_QUERIES.package);
+    const clause = match?.captures.find((c) => c.name === 'definition')?.node;
+    if (!clause) return undefined;
+    const comment = extractGoDocComment(sourceText, clause.startPosition.row + 1);
+    if (!comment?.startsWith('Package ')) return undefined;
+    return comment.split(/\n\s*\n/)[0].replace(/\s+/g, ' ');
+  }
+
+  /**
+   * Extract the cgo preamble: the comment block directly above `import "C"`
+   *
+   * The preamble holds C declarations, so it is indexed as plain text.
+   */
+  private extractCgoPreamble(tree: ParsedTree, file: string): Document | null {
+    for (const match of tree.query(GO_QUERIES.imports)) {
+      const pathNode = match.captures.find((c) => c.name === 'path')?.node;
+      if (pathNode?.text !== `"${CGO_PSEUDO_PACKAGE}"`) continue;
+
+      let declaration = pathNode.parent;
+      while (declaration && declaration.type !== 'import_declaration') {
+        declaration = declaration.parent;
+      }
+      if (!declaration?.parent) return null;
+
+      const { row, column } = declaration.startPosition;
+      const siblings = declaration.parent.children;
+      const index = siblings.findIndex(
+        (s) => s.startPosition.row === row && s.startPosition.column === column
+      );
+
+      // Only comments with no blank line before the import belong to the preamble
+      const comments: TreeSitterNode[] = [];
+      let nextRow = row;
+      for (let i = index - 1; i >= 0 && siblings[i].type === 'comment'; i--) {
+        if (siblings[i].endPosition.row !== nextRow - 1) break;
+        comments.unshift(siblings[i]);
+        nextRow = siblings[i].startPosition.row;
+      }
+      if (comments.length === 0) return null;
+
+      const preamble = comments.map((c) => stripCommentMarkers(c.text)).join('\n');
+      const startLine = comments[0].startPosition.row + 1;
+      const endLine = comments[comments.length - 1].endPosition.row + 1;
+
+      return {
+        id: `${file}:${CGO_PSEUDO_PACKAGE}:${startLine}`,
+        text: `cgo preamble\n${preamble}`,
+        type: 'documentation',
+        language: 'go',
+        metadata: {
+          file,
+          startLine,
+          endLine,
+          name: CGO_PSEUDO_PACKAGE,
+          signature: `import "${CGO_PSEUDO_PACKAGE}"`,
+          exported: false,
+          snippet: this.truncateSnippet(preamble),
+          custom: { cgoPreamble: true },
+        },
+      };
+    }
+    return null;
+  }
+
+  /**
+   * Extract calls made in a function or method body
+   *
+   * Calls into cgo's pseudo-package are skipped: they go to C code, which
+   * isn't indexed and can't be resolved.
+   */
+  private extractCallees(node: TreeSitterNode, usesCgo: boolean): CalleeInfo[] {
+    const callees: CalleeInfo[] = [];
+    const seen = new Set<string>();
+    const stack: TreeSitterNode[] = [node];
+    const narrowings = goTypeNarrowings(node);
+
+    while (stack.length > 0) {
+      const current = stack.pop() as TreeSitterNode;
+      stack.push(...current.namedChildren);
+      if (current.type !== 'call_expression') continue;
+
+      // Plain calls (foo()) and selector calls (pkg.Foo(), s.store.Get(),
+      // x.(*T).Get()); other calls on call results and func literals are skipped
+      const fn = current.childForFieldName('function');
+      if (!fn || (fn.type !== 'identifier' && fn.type !== 'selector_expression')) continue;
+      const operand = fn.type === 'selector_expression' ? fn.childForFieldName('operand') : null;
+      const asserted = operand?.type === 'type_assertion_expression';
+      if (fn.text.includes('(') && !asserted) continue;
+      if (usesCgo && fn.text.startsWith(`${CGO_PSEUDO_PACKAGE}.`)) continue;
+
+      // A receiver narrowed to a concrete type lets the call resolve to its method
+      let narrowing: { receiverType: string; guarded?: boolean } | undefined;
+      if (asserted) {
+        const receiverType = goNarrowedTypeName(operand?.childForFieldName('type') ?? null);
+        if (receiverType) narrowing = { receiverType };
+      } else if (operand?.type === 'identifier') {
+        const found = goNarrowingAt(narrowings, operand.text, current.startPosition);
+        if (found) {
+          narrowing = { receiverType: found.type, ...(found.guarded ? { guarded: true } : {}) };
+        }
+      }
+
+      const line = current.startPosition.row + 1;
+      const key = `${fn.text}:${line}`;
+      if (!seen.has(key)) {
+        seen.add(key);
+        callees.push({ name: fn.text, line, ...narrowing });
+      }
+    }
+
+    return callees.sort((a, b) => a.line - b.line);
   }
 
   /**
@@ -360,10 +684,13 @@ export class GoScanner implements Scanner {
     tree: ParsedTree,
     sourceText: string,
     file: string,
-    isTestFile: boolean
+    isTestFile: boolean,
+    usesCgo = false,
+    routePatterns: RoutePattern[] = []
   ): Document[] {
     const documents: Document[] = [];
     const matches = tree.query(GO_QUERIES.functions);
+    const contextFunctions = goContextFunctions(tree.rootNode);
 
     for (const match of matches) {
       const nameCapture = match.captures.find((c) => c.name === 'name');
@@ -377,15 +704,37 @@ export class GoScanner implements Scanner {
       const fullText = defCapture.node.text;
       const signature = this.extractSignature(fullText);
       const docstring = extractGoDocComment(sourceText, startLine);
-      const exported = this.isExported(name);
+      const exported = isGoExported(name);
       const snippet = this.truncateSnippet(fullText);
+      const callees = this.extractCallees(defCapture.node, usesCgo);
+      const referencesTypes = extractGoTypeReferences(defCapture.node, usesCgo);
 
       // Check for generics
       const { isGeneric, typeParameters } = this.extractTypeParameters(signature);
+      const constructor = detectGoConstructor(defCapture.node, name);
+      const iterator = detectGoIterator(defCapture.node);
+      const example = isTestFile ? detectGoExample(defCapture.node, name) : undefined;
+      const defers = extractGoDefers(defCapture.node);
+      const errorsReturned = extractGoErrorReturns(defCapture.node);
+      const routes = extractGoRoutes(defCapture.node, routePatterns);
+      const sqlQueries = extractGoSqlQueries(defCapture.node);
+      // Tests build their own contexts; flagging them would only be noise
+      const contextFindings = isTestFile
+        ? []
+        : extractGoContextFindings(defCapture.node, contextFunctions);
+      const typeConstraints = goTypeConstraints(signature, name);
+      let text = this.buildEmbeddingText('function', name, signature, docstring);
+      // Mentioning the type helps "how do I create a X" queries find its constructors
+      if (constructor) text += `\nconstructor of ${constructor.constructs}`;
+      if (iterator) text += `\n${describeIterator(iterator)}`;
+      if (example) text += `\nexample of ${example.target ?? 'the package'}\n${example.code}`;
+      if (routes.length > 0) text += `\nregisters routes ${describeRoutes(routes)}`;
+      if (sqlQueries.length > 0) text += `\nruns SQL ${describeSqlQueries(sqlQueries)}`;
+      if (typeConstraints.length > 0) text += `\nconstrained by ${typeConstraints.join(', ')}`;
 
       documents.push({
         id: `${file}:${name}:${startLine}`,
-        text: this.buildEmbeddingText('function', name, signature, docstring),
+        text,
         type: 'function',
         language: 'go',
         metadata: {
@@ -397,12 +746,25 @@ export class GoScanner implements Scanner {
           exported,
           docstring,
           snippet,
+          complexity: computeGoComplexity(defCapture.node),
+          callees: callees.length > 0 ? callees : undefined,
+          ...(referencesTypes.length > 0 ? { referencesTypes } : {}),
+          ...constructor,
+          ...(iterator ? { iterator } : {}),
+          ...(example ? { example } : {}),
+          ...(defers.length > 0 ? { defers } : {}),
+          ...(errorsReturned.length > 0 ? { errorsReturned } : {}),
+          ...(routes.length > 0 ? { routes } : {}),
+          ...(sqlQueries.length > 0 ? { sqlQueries } : {}),
+          ...(typeConstraints.length > 0 ? { typeConstraints } : {}),
+          ...(contextFindings.length > 0 ? { contextFindings } : {}),
           custom: {
             ...(isTestFile ? { isTest: true } : {}),
             ...(isGeneric ? { isGeneric, typeParameters } : {}),
           },
         },
       });
+      documents.push(...this.extractFuncLiterals(defCapture.node, name, file, isTestFile));
     }
 
     return documents;
@@ -415,10 +777,13 @@ export class GoScanner implements Scanner {
     tree: ParsedTree,
     sourceText: string,
     file: string,
-    isTestFile: boolean
+    isTestFile: boolean,
+    usesCgo = false,
+    routePatterns: RoutePattern[] = []
   ): Document[] {
     const documents: Document[] = [];
     const matches = tree.query(GO_QUERIES.methods);
+    const contextFunctions = goContextFunctions(tree.rootNode);
 
     for (const match of matches) {
       const nameCapture = match.captures.find((c) => c.name === 'name');
@@ -438,8 +803,11 @@ export class GoScanner implements Scanner {
       const fullText = defCapture.node.text;
       const signature = this.extractSignature(fullText);
       const docstring = extractGoDocComment(sourceText, startLine);
-      const exported = this.isExported(methodName);
+      // Methods on unexported types are not part of the public API
+      const exported = isGoMethodExported(baseReceiverType, methodName);
       const snippet = this.truncateSnippet(fullText);
+      const callees = this.extractCallees(defCapture.node, usesCgo);
+      const referencesTypes = extractGoTypeReferences(defCapture.node, usesCgo);
 
       // Check if receiver is a pointer
       const receiverText = receiverCapture?.node.text || '';
@@ -450,10 +818,22 @@ export class GoScanner implements Scanner {
       const { isGeneric: signatureHasGenerics, typeParameters } =
         this.extractTypeParameters(signature);
       const isGeneric = receiverHasGenerics || signatureHasGenerics;
+      const iterator = detectGoIterator(defCapture.node);
+      const defers = extractGoDefers(defCapture.node);
+      const errorsReturned = extractGoErrorReturns(defCapture.node);
+      const routes = extractGoRoutes(defCapture.node, routePatterns);
+      const sqlQueries = extractGoSqlQueries(defCapture.node);
+      const contextFindings = isTestFile
+        ? []
+        : extractGoContextFindings(defCapture.node, contextFunctions);
+      let text = this.buildEmbeddingText('method', name, signature, docstring);
+      if (iterator) text += `\n${describeIterator(iterator)}`;
+      if (routes.length > 0) text += `\nregisters routes ${describeRoutes(routes)}`;
+      if (sqlQueries.length > 0) text += `\nruns SQL ${describeSqlQueries(sqlQueries)}`;
 
       documents.push({
         id: `${file}:${name}:${startLine}`,
-        text: this.buildEmbeddingText('method', name, signature, docstring),
+        text,
         type: 'method',
         language: 'go',
         metadata: {
@@ -465,6 +845,15 @@ export class GoScanner implements Scanner {
           exported,
           docstring,
           snippet,
+          complexity: computeGoComplexity(defCapture.node),
+          callees: callees.length > 0 ? callees : undefined,
+          ...(referencesTypes.length > 0 ? { referencesTypes } : {}),
+          ...(iterator ? { iterator } : {}),
+          ...(defers.length > 0 ? { defers } : {}),
+          ...(errorsReturned.length > 0 ? { errorsReturned } : {}),
+          ...(routes.length > 0 ? { routes } : {}),
+          ...(sqlQueries.length > 0 ? { sqlQueries } : {}),
+          ...(contextFindings.length > 0 ? { contextFindings } : {}),
           custom: {
             receiver: baseReceiverType,
             receiverPointer,
@@ -473,6 +862,58 @@ export class GoScanner implements Scanner {
           },
         },
       });
+      documents.push(...this.extractFuncLiterals(defCapture.node, name, file, isTestFile));
+    }
+
+    return documents;
+  }
+
+  /**
+   * Extract the func literals in a function or method as anonymous child symbols
+   *
+   * Calls inside a literal stay with the enclosing function's callees, so
+   * literals carry none of their own and the call graph isn't doubled.
+   */
+  private extractFuncLiterals(
+    declaration: TreeSitterNode,
+    enclosing: string,
+    file: string,
+    isTestFile: boolean
+  ): Document[] {
+    const documents: Document[] = [];
+
+    for (const site of goFuncLiterals(declaration, enclosing)) {
+      const { node, name } = site;
+      const startLine = node.startPosition.row + 1;
+      const endLine = node.endPosition.row + 1;
+      const signature = this.extractSignature(node.text);
+      const funcLiteral: GoFuncLiteral = {
+        enclosing: site.enclosing,
+        captures: goCaptures(node, site.scopes),
+        ...goFuncLiteralUsage(node),
+      };
+      const text =
+        this.buildEmbeddingText('function', name, signature) +
+        `\n${describeFuncLiteral(funcLiteral)}`;
+
+      documents.push({
+        id: `${file}:${name}:${startLine}`,
+        text,
+        type: 'function',
+        language: 'go',
+        metadata: {
+          file,
+          startLine,
+          endLine,
+          name,
+          signature,
+          exported: false,
+          snippet: this.truncateSnippet(node.text),
+          complexity: computeGoComplexity(node),
+          funcLiteral,
+          custom: isTestFile ? { isTest: true } : {},
+        },
+      });
     }
 
     return documents;
@@ -508,12 +949,17 @@ export class GoScanner implements Scanner {
         : `type ${name} struct`;
 
       const docstring = extractGoDocComment(sourceText, startLine);
-      const exported = this.isExported(name);
+      const exported = isGoExported(name);
       const snippet = this.truncateSnippet(fullText);
+      const bodyCapture = match.captures.find((c) => c.name === 'struct_body');
+      const fields = bodyCapture ? this.extractStructFields(bodyCapture.node) : [];
+      const typeConstraints = goTypeConstraints(fullText, name);
+      let text = this.buildEmbeddingText('struct', name, signature, docstring);
+      if (typeConstraints.length > 0) text += `\nconstrained by ${typeConstraints.join(', ')}`;
 
       documents.push({
         id: `${file}:${name}:${startLine}`,
-        text: this.buildEmbeddingText('struct', name, signature, docstring),
+        text,
         type: 'class', // Map struct to 'class' for consistency with other scanners
         language: 'go',
         metadata: {
@@ -525,7 +971,9 @@ export class GoScanner implements Scanner {
           exported,
           docstring,
           snippet,
+          ...(typeConstraints.length > 0 ? { typeConstraints } : {}),
           custom: {
+            fields,
             ...(isTestFile ? { isTest: true } : {}),
             ...(isGeneric ? { isGeneric, typeParameters } : {}),
           },
@@ -566,12 +1014,24 @@ export class GoScanner implements Scanner {
         : `type ${name} interface`;
 
       const docstring = extractGoDocComment(sourceText, startLine);
-      const exported = this.isExported(name);
+      const exported = isGoExported(name);
       const snippet = this.truncateSnippet(fullText);
+      const bodyCapture = match.captures.find((c) => c.name === 'interface_body');
+      const typeSet = bodyCapture ? goInterfaceTypeSet(bodyCapture.node.text) : undefined;
+      const typeConstraints = goTypeConstraints(fullText, name);
+      // Constraints only constrain type parameters; say so for "what constraint allows ints"
+      let text = this.buildEmbeddingText(
+        typeSet ? 'constraint interface' : 'interface',
+        name,
+        signature,
+        docstring
+      );
+      if (typeSet) text += `\ntype set: ${describeTypeSet(typeSet)}`;
+      if (typeConstraints.length > 0) text += `\nconstrained by ${typeConstraints.join(', ')}`;
 
       documents.push({
         id: `${file}:${name}:${startLine}`,
-        text: this.buildEmbeddingText('interface', name, signature, docstring),
+        text,
         type: 'interface',
         language: 'go',
         metadata: {
@@ -583,6 +1043,8 @@ export class GoScanner implements Scanner {
           exported,
           docstring,
           snippet,
+          ...(typeSet ? { typeSet } : {}),
+          ...(typeConstraints.length > 0 ? { typeConstraints } : {}),
           custom: {
             ...(isTestFile ? { isTest: true } : {}),
             ...(isGeneric ? { isGeneric, typeParameters } : {}),
@@ -618,12 +1080,15 @@ export class GoScanner implements Scanner {
       const fullText = defCapture.node.text;
       const signature = fullText.trim();
       const docstring = extractGoDocComment(sourceText, startLine);
-      const exported = this.isExported(name);
+      const exported = isGoExported(name);
       const snippet = this.truncateSnippet(fullText);
+      const typeConstraints = goTypeConstraints(fullText, name);
+      let text = this.buildEmbeddingText('type', name, signature, docstring);
+      if (typeConstraints.length > 0) text += `\nconstrained by ${typeConstraints.join(', ')}`;
 
       documents.push({
         id: `${file}:${name}:${startLine}`,
-        text: this.buildEmbeddingText('type', name, signature, docstring),
+        text,
         type: 'type',
         language: 'go',
         metadata: {
@@ -635,6 +1100,7 @@ export class GoScanner implements Scanner {
           exported,
           docstring,
           snippet,
+          ...(typeConstraints.length > 0 ? { typeConstraints } : {}),
           custom: isTestFile ? { isTest: true } : undefined,
         },
       });
@@ -644,7 +1110,11 @@ export class GoScanner implements Scanner {
   }
 
   /**
-   * Extract constant declarations
+   * Extract exported constants with their type and value
+   *
+   * Each spec in a grouped block is its own document. Specs without a value
+   * repeat the previous spec's type and expressions, as in Go, with `iota`
+   * counting specs from zero.
    */
   private extractConstants(
     tree: ParsedTree,
@@ -656,40 +1126,111 @@ export class GoScanner implements Scanner {
     const matches = tree.query(GO_QUERIES.constants);
 
     for (const match of matches) {
-      const nameCapture = match.captures.find((c) => c.name === 'name');
-      const defCapture = match.captures.find((c) => c.name === 'definition');
+      const declaration = match.captures.find((c) => c.name === 'definition')?.node;
+      if (!declaration) continue;
+
+      const blockDocstring = extractGoDocComment(sourceText, declaration.startPosition.row + 1);
+      const snippet = this.truncateSnippet(declaration.text);
+      const specs = declaration.namedChildren.filter((n) => n.type === 'const_spec');
+      let typeNode: TreeSitterNode | null = null;
+      let values: TreeSitterNode[] = [];
+
+      for (const [iota, spec] of specs.entries()) {
+        const valueList = spec.childForFieldName('value');
+        if (valueList) {
+          typeNode = spec.childForFieldName('type');
+          values = valueList.namedChildren.filter((n) => n.type !== 'comment');
+        }
 
-      if (!nameCapture || !defCapture) continue;
+        const startLine = spec.startPosition.row + 1;
+        const endLine = spec.endPosition.row + 1;
+        const names = spec.namedChildren.filter((n) => n.type === 'identifier');
 
-      const name = nameCapture.node.text;
-      // Only extract exported constants
-      if (!this.isExported(name)) continue;
+        for (const [i, nameNode] of names.entries()) {
+          const name = nameNode.text;
+          if (!isGoExported(name)) continue;
 
-      const startLine = defCapture.node.startPosition.row + 1;
-      const endLine = defCapture.node.endPosition.row + 1;
-      const fullText = defCapture.node.text;
-      const signature = fullText.trim();
-      const docstring = extractGoDocComment(sourceText, startLine);
-      const snippet = this.truncateSnippet(fullText);
+          const constant = describeGoConstant(values[i], typeNode, iota);
+          const signature = `const ${spec.text.trim()}`;
+          const docstring = extractGoDocComment(sourceText, startLine) ?? blockDocstring;
+
+          documents.push({
+            id: `${file}:${name}:${startLine}`,
+            text: this.buildEmbeddingText('constant', name, signature, docstring),
+            type: 'variable',
+            language: 'go',
+            metadata: {
+              file,
+              startLine,
+              endLine,
+              name,
+              signature,
+              exported: true,
+              docstring,
+              snippet,
+              ...constant,
+              custom: {
+                isConstant: true,
+                ...(values[i]?.text.includes('iota') ? { iota } : {}),
+                ...(isTestFile ? { isTest: true } : {}),
+              },
+            },
+          });
+        }
+      }
+    }
 
+    return documents;
+  }
+
+  /**
+   * Extract package-level interface assertions (`var _ io.Reader = (*File)(nil)`)
+   *
+   * Each becomes a `_` variable document recording the interface and the
+   * asserted type, so implementations can be listed without type checking.
+   */
+  private extractAssertions(
+    tree: ParsedTree,
+    sourceText: string,
+    file: string,
+    isTestFile: boolean
+  ): Document[] {
+    const documents: Document[] = [];
+
+    for (const match of tree.query(GO_QUERIES.assertions)) {
+      const spec = match.captures.find((c) => c.name === 'definition')?.node;
+      const name = match.captures.find((c) => c.name === 'name')?.node.text;
+      const iface = match.captures.find((c) => c.name === 'interface')?.node.text;
+      const value = match.captures.find((c) => c.name === 'value')?.node.text;
+      if (!spec || name !== '_' || !iface || !value || !isPackageLevel(spec)) continue;
+
+      const asserted = assertedType(value);
+      if (!asserted) continue;
+
+      const startLine = spec.startPosition.row + 1;
+      const signature = `var ${spec.text.trim()}`;
+      const docstring = extractGoDocComment(sourceText, startLine);
       documents.push({
-        id: `${file}:${name}:${startLine}`,
-        text: this.buildEmbeddingText('constant', name, signature, docstring),
+        id: `${file}:_:${startLine}`,
+        text: this.buildEmbeddingText(
+          'assertion',
+          `${asserted.type} implements ${iface}`,
+          signature,
+          docstring
+        ),
         type: 'variable',
         language: 'go',
         metadata: {
           file,
           startLine,
-          endLine,
-          name,
+          endLine: spec.endPosition.row + 1,
+          name: '_',
           signature,
-          exported: true,
+          exported: false,
           docstring,
-          snippet,
-          custom: {
-            isConstant: true,
-            ...(isTestFile ? { isTest: true } : {}),
-          },
+          snippet: spec.text,
+          asserts: { interface: iface, ...asserted },
+          custom: isTestFile ? { isTest: true } : undefined,
         },
       });
     }
@@ -698,12 +1239,47 @@ export class GoScanner implements Scanner {
   }
 
   /**
-   * Check if a Go identifier is exported (starts with uppercase)
+   * Extract struct fields in declaration order, including embedded fields
    */
-  private isExported(name: string): boolean {
-    if (!name || name.length === 0) return false;
-    const firstChar = name.charAt(0);
-    return firstChar === firstChar.toUpperCase() && firstChar !== firstChar.toLowerCase();
+  private extractStructFields(structBody: TreeSitterNode): StructField[] {
+    const fields: StructField[] = [];
+    const fieldList = structBody.namedChildren.find((n) => n.type === 'field_declaration_list');
+    if (!fieldList) return fields;
+
+    for (const declaration of fieldList.namedChildren) {
+      if (declaration.type !== 'field_declaration') continue;
+      const typeNode = declaration.childForFieldName('type');
+      if (!typeNode) continue;
+
+      const names = declaration.namedChildren.filter((c) => c.type === 'field_identifier');
+      const tag = goStructTag(declaration.childForFieldName('tag')?.text);
+      if (names.length === 0) {
+        // Embedded: `*Base` keeps the pointer outside the type node; the field is named `Base`
+        const pointer = declaration.children.some((c) => c.type === '*');
+        const name = typeNode.text.replace(/\[.*$/, '').split('.').pop() ?? typeNode.text;
+        fields.push({
+          name,
+          type: pointer ? `*${typeNode.text}` : typeNode.text,
+          exported: isGoExported(name),
+          embedded: true,
+          ...(tag ? { tag } : {}),
+        });
+        continue;
+      }
+
+      // `a, b int` declares several fields of the same type
+      for (const name of names) {
+        fields.push({
+          name: name.text,
+          type: typeNode.text,
+          exported: isGoExported(name.text),
+          embedded: false,
+          ...(tag ? { tag } : {}),
+        });
+      }
+    }
+
+    return fields;
   }
 
   /**
@@ -772,3 +1348,1026 @@ export class GoScanner implements Scanner {
     return `${truncated}\n// ... ${remaining} more lines`;
   }
 }
+
+/**
+ * External modules a file's imports come from, at the owning module's effective versions
+ */
+function importedDependencies(imports: string[], module: GoModule): GoDependencyUse[] {
+  const used = new Map<string, GoDependencyUse>();
+  for (const importPath of imports) {
+    const dependency = resolveGoDependency(importPath, module);
+    if (!dependency || used.has(dependency.path)) continue;
+    used.set(dependency.path, {
+      module: dependency.path,
+      version: dependency.version,
+      ...(dependency.indirect ? { indirect: true } : {}),
+      ...(dependency.replacedBy ? { replaced: true } : {}),
+    });
+  }
+  return [...used.values()];
+}
+
+/**
+ * Give every document in a package the package doc found in one of its files
+ *
+ * The doc usually lives in a single file (often doc.go), so documents only
+ * get it when that file is part of the same scan.
+ */
+function shareGoPackageDocs(documents: Document[]): void {
+  const docs = new Map<string, string>();
+  for (const doc of documents) {
+    if (doc.metadata.packageDoc) {
+      docs.set(packageKey(doc), doc.metadata.packageDoc);
+    }
+  }
+  if (docs.size === 0) return;
+
+  for (const doc of documents) {
+    const packageDoc = docs.get(packageKey(doc));
+    if (packageDoc && !doc.metadata.packageDoc) {
+      doc.metadata.packageDoc = packageDoc;
+    }
+  }
+}
+
+/**
+ * Directory and package name: a directory can hold an external `_test`
+ * package next to the one it tests
+ */
+function packageKey(doc: Document): string {
+  return `${path.posix.dirname(doc.metadata.file)}\n${doc.metadata.packageName ?? ''}`;
+}
+
+/**
+ * Value forms that name the asserted type, and whether they assert through a pointer
+ */
+const ASSERTED_TYPE_PATTERNS: [RegExp, boolean][] = [
+  [/^\(\s*\*\s*([\w.]+)(?:\[.*\])?\s*\)\s*\(\s*nil\s*\)$/s, true], // (*T)(nil)
+  [/^&\s*([\w.]+)(?:\[.*\])?\s*\{/s, true], // &T{}
+  [/^new\(\s*([\w.]+)(?:\[.*\])?\s*\)$/s, true], // new(T)
+  [/^([\w.]+)(?:\[.*\])?\s*\{/s, false], // T{}
+  [/^([\w.]+)(?:\[.*\])?\s*\((?:\s*nil\s*|\s*""\s*|\s*0\s*)\)$/s, false], // T(nil), T(""), T(0)
+];
+
+/**
+ * Type named by an interface assertion's value, without type arguments
+ */
+function assertedType(value: string): { type: string; pointer: boolean } | null {
+  for (const [pattern, pointer] of ASSERTED_TYPE_PATTERNS) {
+    const match = value.trim().match(pattern);
+    if (match) return { type: match[1], pointer };
+  }
+  return null;
+}
+
+/**
+ * Struct tag contents from its raw (`json:"id"`) or interpreted ("json:\"id\"") literal
+ */
+function goStructTag(literal: string | undefined): string | undefined {
+  if (!literal) return undefined;
+  if (literal.startsWith('`')) return literal.slice(1, -1) || undefined;
+  try {
+    return JSON.parse(literal) || undefined;
+  } catch {
+    return literal.slice(1, -1) || undefined;
+  }
+}
+
+/**
+ * Whether a var spec is declared at package level rather than in a function
+ */
+function isPackageLevel(spec: TreeSitterNode): boolean {
+  let node = spec.parent;
+  while (node && node.type !== 'var_declaration') node = node.parent;
+  return node?.parent?.type === 'source_file';
+}
+
+/**
+ * Record the calls in a function or method that can hard-crash the process,
+ * and whether it recovers from panics
+ *
+ * Works from the extracted callees, so calls inside closures count toward the
+ * enclosing function. Calls through a renamed import of log or os are missed.
+ */
+function annotateCrashes(doc: Document, isTestFile: boolean, packageName?: string): void {
+  const callees = doc.metadata.callees ?? [];
+  const crashes: CrashSite[] = [];
+  for (const callee of callees) {
+    const kind = GO_CRASH_CALLS[callee.name];
+    if (kind) {
+      crashes.push({ kind, call: callee.name, line: callee.line });
+    }
+  }
+
+  if (crashes.length > 0) {
+    doc.metadata.crashes = crashes;
+    doc.metadata.crashContext = isTestFile
+      ? 'test'
+      : doc.type === 'function' && doc.metadata.name === 'init'
+        ? 'init'
+        : packageName === 'main'
+          ? 'main'
+          : 'library';
+  }
+  if (callees.some((callee) => callee.name === 'recover')) {
+    doc.metadata.recovers = true;
+  }
+}
+
+/**
+ * Collect the calls a function or method defers
+ *
+ * Defers inside nested func literals run when the literal returns, not the
+ * enclosing function, so they're left out. A deferred literal is summarized
+ * as `func() {...}()` with the calls it makes.
+ */
+function extractGoDefers(declaration: TreeSitterNode): DeferredCall[] {
+  const defers: DeferredCall[] = [];
+  const body = declaration.childForFieldName('body');
+  const stack: TreeSitterNode[] = body ? [body] : [];
+
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'func_literal') continue;
+    if (current.type !== 'defer_statement') {
+      stack.push(...current.namedChildren);
+      continue;
+    }
+
+    const expression = current.namedChildren[0];
+    if (!expression) continue;
+    const line = current.startPosition.row + 1;
+    const fn = expression.childForFieldName('function');
+    if (fn?.type === 'func_literal') {
+      const calls = goCallNames(fn);
+      defers.push({ call: 'func() {...}()', line, ...(calls.length > 0 ? { calls } : {}) });
+    } else {
+      defers.push({ call: expression.text.replace(/\s+/g, ' '), line });
+    }
+  }
+
+  return defers.sort((a, b) => a.line - b.line);
+}
+
+/** Sentinel error names: `ErrNotFound`, `errClosed`, `io.EOF` */
+const GO_SENTINEL_ERROR = /^(?:[Ee]rr[A-Z0-9]\w*|EOF)$/;
+
+/** Calls that wrap the error given as their first argument (github.com/pkg/errors) */
+const GO_WRAP_CALLS = new Set([
+  'errors.Wrap',
+  'errors.Wrapf',
+  'errors.WithMessage',
+  'errors.WithMessagef',
+  'errors.WithStack',
+]);
+
+type SourcePosition = { row: number; column: number };
+
+function isBefore(a: SourcePosition, b: SourcePosition): boolean {
+  return a.row < b.row || (a.row === b.row && a.column <= b.column);
+}
+
+/**
+ * A variable narrowed to a named type by a type switch case or a type
+ * assertion, over the source range where the narrowing holds
+ */
+interface GoTypeNarrowing {
+  variable: string;
+  type: string;
+  /** Only holds on a checked branch: a switch case or comma-ok assertion */
+  guarded: boolean;
+  start: SourcePosition;
+  end: SourcePosition;
+}
+
+/**
+ * Find the type narrowings in a function or method
+ *
+ * `switch v := x.(type)` narrows v in each single-type case;
+ * `r, ok := x.(*T)` narrows r (guarded) and `r := x.(*T)` narrows it
+ * unconditionally, from the declaration to the end of its block or `if`.
+ * Reassignments aren't tracked.
+ */
+function goTypeNarrowings(declaration: TreeSitterNode): GoTypeNarrowing[] {
+  const narrowings: GoTypeNarrowing[] = [];
+  const stack: TreeSitterNode[] = [declaration];
+
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    stack.push(...current.namedChildren);
+
+    if (current.type === 'type_switch_statement') {
+      const alias = current.childForFieldName('alias')?.namedChildren[0];
+      if (alias?.type !== 'identifier' || alias.text === '_') continue;
+      for (const clause of current.namedChildren) {
+        if (clause.type !== 'type_case') continue;
+        // In `case A, B:` the variable keeps the switched expression's type
+        const types = goCaseTypes(clause);
+        const type = types.length === 1 ? goNarrowedTypeName(types[0]) : null;
+        if (type) {
+          narrowings.push({
+            variable: alias.text,
+            type,
+            guarded: true,
+            start: clause.startPosition,
+            end: clause.endPosition,
+          });
+        }
+      }
+    } else if (current.type === 'short_var_declaration') {
+      const left = current.childForFieldName('left')?.namedChildren ?? [];
+      const right = current.childForFieldName('right')?.namedChildren ?? [];
+      const [variable] = left;
+      if (right.length !== 1 || right[0].type !== 'type_assertion_expression') continue;
+      if (variable?.type !== 'identifier' || variable.text === '_') continue;
+      const type = goNarrowedTypeName(right[0].childForFieldName('type'));
+      if (type && current.parent) {
+        narrowings.push({
+          variable: variable.text,
+          type,
+          guarded: left.length === 2,
+          start: current.startPosition,
+          end: current.parent.endPosition,
+        });
+      }
+    }
+  }
+
+  return narrowings;
+}
+
+/**
+ * Types listed by a type switch case, before its colon
+ */
+function goCaseTypes(clause: TreeSitterNode): TreeSitterNode[] {
+  const types: TreeSitterNode[] = [];
+  for (const child of clause.children) {
+    if (child.type === ':') break;
+    if (child.type !== 'case' && child.type !== ',' && child.type !== 'comment') types.push(child);
+  }
+  return types;
+}
+
+/**
+ * The named type a narrowing targets (`*MyReader` -> `MyReader`, `io.Reader`),
+ * or null for nil and unnamed types
+ */
+function goNarrowedTypeName(node: TreeSitterNode | null): string | null {
+  if (!node) return null;
+  const name = node.text.replace(/^\*+/, '').replace(/\[.*\]$/, '');
+  if (name === 'nil') return null;
+  return /^[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?$/.test(name) ? name : null;
+}
+
+/**
+ * The innermost narrowing of a variable at a position
+ */
+function goNarrowingAt(
+  narrowings: GoTypeNarrowing[],
+  variable: string,
+  position: SourcePosition
+): GoTypeNarrowing | undefined {
+  let innermost: GoTypeNarrowing | undefined;
+  for (const narrowing of narrowings) {
+    if (narrowing.variable !== variable) continue;
+    if (!isBefore(narrowing.start, position) || !isBefore(position, narrowing.end)) continue;
+    if (!innermost || isBefore(innermost.start, narrowing.start)) innermost = narrowing;
+  }
+  return innermost;
+}
+
+/**
+ * A func literal found in a function or method, with the scopes around it
+ */
+interface GoFuncLiteralSite {
+  node: TreeSitterNode;
+  /** `Outer.func1`, `Outer.func1.1`, ... as the Go toolchain numbers them */
+  name: string;
+  /** Name of the declaration or literal it is written in */
+  enclosing: string;
+  /** The declaration and any enclosing literals, outermost first */
+  scopes: TreeSitterNode[];
+}
+
+/**
+ * Nodes whose direct identifier children are declared names
+ */
+const GO_DECLARING_NODES = new Set([
+  'parameter_declaration',
+  'variadic_parameter_declaration',
+  'var_spec',
+  'const_spec',
+]);
+
+/**
+ * Nodes declaring the identifiers on their left-hand side
+ */
+const GO_DECLARING_LEFT_NODES = new Set([
+  'short_var_declaration',
+  'range_clause',
+  'receive_statement',
+]);
+
+/**
+ * Find the func literals in a declaration, in source order, each followed by
+ * the literals nested in it
+ */
+function goFuncLiterals(declaration: TreeSitterNode, enclosing: string): GoFuncLiteralSite[] {
+  const sites: GoFuncLiteralSite[] = [];
+  const visit = (scope: TreeSitterNode, scopeName: string, scopes: TreeSitterNode[]) => {
+    // Top-level literals are funcN; nested ones are numbered under their parent
+    const prefix = scope === declaration ? 'func' : '';
+    for (const [i, node] of goDirectFuncLiterals(scope).entries()) {
+      const name = `${scopeName}.${prefix}${i + 1}`;
+      sites.push({ node, name, enclosing: scopeName, scopes });
+      visit(node, name, [...scopes, node]);
+    }
+  };
+  visit(declaration, enclosing, [declaration]);
+  return sites;
+}
+
+/**
+ * Func literals under a node that aren't nested in another literal, in source order
+ */
+function goDirectFuncLiterals(scope: TreeSitterNode): TreeSitterNode[] {
+  const literals: TreeSitterNode[] = [];
+  const stack = [...scope.namedChildren].reverse();
+
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'func_literal') {
+      literals.push(current);
+    } else {
+      stack.push(...[...current.namedChildren].reverse());
+    }
+  }
+
+  return literals;
+}
+
+/**
+ * Names declared under a node: parameters, receivers, named results, and
+ * local variables and constants. Nested func literals are skipped unless
+ * `intoLiterals` is set.
+ */
+function goDeclaredNames(scope: TreeSitterNode, intoLiterals: boolean): Set<string> {
+  const names = new Set<string>();
+  const add = (nodes: TreeSitterNode[]) => {
+    for (const node of nodes) {
+      if (node.type === 'identifier' && node.text !== '_') names.add(node.text);
+    }
+  };
+  const stack = [...scope.namedChildren];
+
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'func_literal' && !intoLiterals) continue;
+    stack.push(...current.namedChildren);
+
+    if (GO_DECLARING_NODES.has(current.type)) {
+      add(current.namedChildren);
+    } else if (GO_DECLARING_LEFT_NODES.has(current.type)) {
+      add(current.childForFieldName('left')?.namedChildren ?? []);
+    } else if (current.type === 'type_switch_statement') {
+      add(current.childForFieldName('alias')?.namedChildren ?? []);
+    }
+  }
+
+  return names;
+}
+
+/**
+ * Variables of the enclosing scopes a func literal refers to, in order of first use
+ *
+ * Works by name: a variable the literal declares itself hides any outer one
+ * of the same name throughout the literal, and package-level variables aren't
+ * counted as captures.
+ */
+function goCaptures(literal: TreeSitterNode, scopes: TreeSitterNode[]): string[] {
+  const outer = new Set<string>();
+  for (const scope of scopes) {
+    for (const name of goDeclaredNames(scope, false)) outer.add(name);
+  }
+  const local = goDeclaredNames(literal, true);
+
+  const captures: string[] = [];
+  const stack = [...literal.namedChildren].reverse();
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    stack.push(...[...current.namedChildren].reverse());
+    const name = current.text;
+    if (current.type !== 'identifier' || !outer.has(name) || local.has(name)) continue;
+    if (!captures.includes(name)) captures.push(name);
+  }
+
+  return captures;
+}
+
+/**
+ * How a func literal is used, from where it sits
+ */
+function goFuncLiteralUsage(literal: TreeSitterNode): Pick<GoFuncLiteral, 'usage' | 'passedTo'> {
+  const parent = literal.parent;
+  // A literal directly under a call is the function being called
+  if (parent?.type === 'call_expression') {
+    const statement = parent.parent?.type;
+    if (statement === 'go_statement') return { usage: 'go' };
+    if (statement === 'defer_statement') return { usage: 'defer' };
+    return { usage: 'call' };
+  }
+  if (parent?.type === 'argument_list' && parent.parent?.type === 'call_expression') {
+    const fn = parent.parent.childForFieldName('function');
+    if (!fn) return { usage: 'argument' };
+    return { usage: 'argument', passedTo: fn.text.replace(/\s+/g, '') };
+  }
+  return { usage: 'value' };
+}
+
+/**
+ * Embedding text line for a func literal ("func literal in Serve, started as a goroutine; ...")
+ */
+function describeFuncLiteral(literal: GoFuncLiteral): string {
+  const usage = {
+    go: 'started as a goroutine',
+    defer: 'deferred',
+    call: 'called in place',
+    argument: `passed to ${literal.passedTo ?? 'a call'}`,
+    value: 'stored as a value',
+  }[literal.usage];
+  const captures = literal.captures.length > 0 ? `; captures ${literal.captures.join(', ')}` : '';
+  return `func literal in ${literal.enclosing}, ${usage}${captures}`;
+}
+
+/**
+ * Trace the errors a function or method returns through its own return
+ * statements, for functions whose results end in `error`
+ *
+ * Shallow and intra-function: a returned variable is followed back to its
+ * latest assignment, and a call whose error is passed on is recorded as a
+ * `call` entry (resolved later against the same file, see resolveErrorReturns).
+ * Ad hoc errors (`errors.New`, `fmt.Errorf` without `%w`) and returns inside
+ * func literals are left out.
+ */
+function extractGoErrorReturns(declaration: TreeSitterNode): ReturnedError[] {
+  const result = declaration.childForFieldName('result');
+  const body = declaration.childForFieldName('body');
+  if (!body || !result || !/\berror\s*\)?$/.test(result.text)) return [];
+
+  const returns: TreeSitterNode[] = [];
+  const assignments: { name: string; value: TreeSitterNode; end: SourcePosition }[] = [];
+  const stack: TreeSitterNode[] = [body];
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'func_literal') continue;
+    stack.push(...current.namedChildren);
+    if (current.type === 'return_statement') returns.push(current);
+    if (current.type !== 'short_var_declaration' && current.type !== 'assignment_statement') {
+      continue;
+    }
+    const left = current.childForFieldName('left')?.namedChildren ?? [];
+    const right = current.childForFieldName('right')?.namedChildren ?? [];
+    for (const [index, target] of left.entries()) {
+      // `v, err := f()` assigns every target from the one call
+      const value = right.length === left.length ? right[index] : right[0];
+      if (target.type === 'identifier' && value) {
+        assignments.push({ name: target.text, value, end: current.endPosition });
+      }
+    }
+  }
+
+  const errors: ReturnedError[] = [];
+  const trace = (expr: TreeSitterNode, wrapped: boolean, line: number): void => {
+    switch (expr.type) {
+      case 'parenthesized_expression': {
+        const inner = expr.namedChildren[0];
+        if (inner) trace(inner, wrapped, line);
+        return;
+      }
+      case 'identifier': {
+        if (expr.text === 'nil') return;
+        const assignment = assignments
+          .filter((a) => a.name === expr.text && isBefore(a.end, expr.startPosition))
+          .sort((a, b) => a.end.row - b.end.row || a.end.column - b.end.column)
+          .at(-1);
+        if (assignment) {
+          trace(assignment.value, wrapped, line);
+        } else if (GO_SENTINEL_ERROR.test(expr.text)) {
+          errors.push({ kind: 'sentinel', error: expr.text, wrapped, line });
+        }
+        return;
+      }
+      case 'selector_expression': {
+        const field = expr.childForFieldName('field')?.text ?? '';
+        if (GO_SENTINEL_ERROR.test(field)) {
+          errors.push({ kind: 'sentinel', error: expr.text, wrapped, line });
+        }
+        return;
+      }
+      case 'unary_expression': {
+        const operand = expr.childForFieldName('operand');
+        const type = operand?.type === 'composite_literal' && operand.childForFieldName('type');
+        if (type && expr.text.startsWith('&')) {
+          errors.push({ kind: 'type', error: `*${type.text}`, wrapped, line });
+        }
+        return;
+      }
+      case 'composite_literal': {
+        const type = expr.childForFieldName('type');
+        if (type) errors.push({ kind: 'type', error: type.text, wrapped, line });
+        return;
+      }
+      case 'call_expression': {
+        const fn = expr.childForFieldName('function');
+        const args = expr.childForFieldName('arguments')?.namedChildren ?? [];
+        if (!fn || (fn.type !== 'identifier' && fn.type !== 'selector_expression')) return;
+        if (fn.text === 'fmt.Errorf') {
+          // Pair the format verbs with the arguments after the format string
+          const verbs = [...(args[0]?.text ?? '').matchAll(/%[-+# 0-9.*]*([a-zA-Z%])/g)]
+            .map((match) => match[1])
+            .filter((verb) => verb !== '%');
+          for (const [index, verb] of verbs.entries()) {
+            const arg = args[index + 1];
+            if (verb === 'w' && arg) trace(arg, true, line);
+          }
+        } else if (fn.text === 'errors.Join') {
+          for (const arg of args) trace(arg, true, line);
+        } else if (GO_WRAP_CALLS.has(fn.text)) {
+          if (args[0]) trace(args[0], true, line);
+        } else if (fn.text !== 'errors.New') {
+          errors.push({ kind: 'call', error: fn.text, wrapped, line });
+        }
+        return;
+      }
+    }
+  };
+
+  returns.sort((a, b) => (isBefore(a.startPosition, b.startPosition) ? -1 : 1));
+  for (const statement of returns) {
+    // The error is the last result
+    const value = statement.namedChildren[0]?.namedChildren.at(-1);
+    if (value) trace(value, false, statement.startPosition.row + 1);
+  }
+
+  return uniqueReturnedErrors(errors);
+}
+
+/**
+ * Replace `call` entries naming a function in the same file with the errors
+ * that function returns (one level deep), and mention the sentinel and typed
+ * errors in the embedding text so searching for an error finds its sources
+ */
+function resolveErrorReturns(documents: Document[]): void {
+  const byFunction = new Map<string, ReturnedError[]>();
+  for (const doc of documents) {
+    if (doc.type === 'function' && doc.metadata.errorsReturned) {
+      byFunction.set(doc.metadata.name as string, doc.metadata.errorsReturned);
+    }
+  }
+
+  for (const doc of documents) {
+    const returned = doc.metadata.errorsReturned;
+    if (!returned) continue;
+
+    const resolved = returned.flatMap((entry) => {
+      const callee = entry.kind === 'call' ? byFunction.get(entry.error) : undefined;
+      if (!callee || entry.error === doc.metadata.name) return [entry];
+      return callee.map((inner) => ({
+        ...inner,
+        wrapped: entry.wrapped || inner.wrapped,
+        via: entry.error,
+        line: entry.line,
+      }));
+    });
+    doc.metadata.errorsReturned = uniqueReturnedErrors(resolved);
+
+    const named = doc.metadata.errorsReturned.filter((entry) => entry.kind !== 'call');
+    if (named.length > 0) {
+      const names = [...new Set(named.map((entry) => entry.error))];
+      doc.text += `\nreturns errors ${names.join(', ')}`;
+    }
+  }
+}
+
+/**
+ * First occurrence of each error, by kind, name, and whether it's wrapped
+ */
+function uniqueReturnedErrors(errors: ReturnedError[]): ReturnedError[] {
+  const seen = new Set<string>();
+  return errors.filter((entry) => {
+    const key = `${entry.kind}:${entry.error}:${entry.wrapped}`;
+    if (seen.has(key)) return false;
+    seen.add(key);
+    return true;
+  });
+}
+
+/**
+ * Names of the plain and selector calls under a node, in source order
+ */
+function goCallNames(node: TreeSitterNode): string[] {
+  const calls: { name: string; row: number; column: number }[] = [];
+  const stack: TreeSitterNode[] = [node];
+
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    stack.push(...current.namedChildren);
+    if (current.type !== 'call_expression') continue;
+    const fn = current.childForFieldName('function');
+    if (fn && (fn.type === 'identifier' || fn.type === 'selector_expression')) {
+      calls.push({ name: fn.text, ...current.startPosition });
+    }
+  }
+
+  return calls
+    .sort((a, b) => a.row - b.row || a.column - b.column)
+    .map((call) => call.name);
+}
+
+/** Predeclared types, which no package function constructs */
+const GO_PREDECLARED_TYPES = new Set([
+  'any',
+  'bool',
+  'byte',
+  'comparable',
+  'complex64',
+  'complex128',
+  'error',
+  'float32',
+  'float64',
+  'int',
+  'int8',
+  'int16',
+  'int32',
+  'int64',
+  'rune',
+  'string',
+  'uint',
+  'uint8',
+  'uint16',
+  'uint32',
+  'uint64',
+  'uintptr',
+]);
+
+/**
+ * Types a function or method refers to in its signature and body
+ *
+ * Parameters, results, composite literals, variable declarations, and type
+ * assertions and switches all count; field accesses count through
+ * the declared types of the values they're made on. The package's own types
+ * are recorded by name and other packages' qualified as written
+ * (`http.Request`). Predeclared types, type parameters, types declared inside
+ * the function, and a method's receiver type are left out, as are cgo's `C.*`.
+ */
+function extractGoTypeReferences(declaration: TreeSitterNode, usesCgo: boolean): string[] {
+  const excluded = new Set(GO_PREDECLARED_TYPES);
+  for (const param of declaration.childForFieldName('type_parameters')?.namedChildren ?? []) {
+    for (const name of param.namedChildren.filter((n) => n.type === 'identifier')) {
+      excluded.add(name.text);
+    }
+  }
+  // The receiver's type identifiers are the type itself and its type parameters
+  const receiver = declaration.childForFieldName('receiver');
+  for (const name of receiver ? goTypeIdentifiers(receiver) : []) excluded.add(name);
+  const isReceiver = (node: TreeSitterNode) =>
+    receiver !== null &&
+    node.startPosition.row === receiver.startPosition.row &&
+    node.startPosition.column === receiver.startPosition.column;
+
+  const references = new Set<string>();
+  const stack = declaration.namedChildren.filter((child) => !isReceiver(child));
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'qualified_type') {
+      const type = current.text.replace(/\s+/g, '');
+      if (!(usesCgo && type.startsWith(`${CGO_PSEUDO_PACKAGE}.`))) references.add(type);
+      continue;
+    }
+    if (current.type === 'type_identifier') {
+      references.add(current.text);
+      continue;
+    }
+    if (current.type === 'type_spec' || current.type === 'type_alias') {
+      const name = current.childForFieldName('name');
+      if (name) excluded.add(name.text);
+    }
+    stack.push(...current.namedChildren);
+  }
+
+  return [...references].filter((type) => !excluded.has(type)).sort();
+}
+
+/** Type identifiers under a node */
+function goTypeIdentifiers(node: TreeSitterNode): string[] {
+  const names: string[] = [];
+  const stack = [node];
+  while (stack.length > 0) {
+    const current = stack.pop() as TreeSitterNode;
+    if (current.type === 'type_identifier') names.push(current.text);
+    stack.push(...current.namedChildren);
+  }
+  return names;
+}
+
+/**
+ * Detect a function constructing a package type from its primary (first) result
+ *
+ * `T`, `*T`, and `T[...]` count when T is an unqualified, non-predeclared type
+ * that isn't one of the function's type parameters, so `(T, error)` returns
+ * and generic constructors are covered. Slices, maps, and other packages'
+ * types are not.
+ */
+function detectGoConstructor(
+  declaration: TreeSitterNode,
+  name: string
+): Pick<DocumentMetadata, 'constructs' | 'constructorConfidence'> | undefined {
+  let result = declaration.childForFieldName('result');
+  if (result?.type === 'parameter_list') {
+    const first = result.namedChildren.find((n) => n.type === 'parameter_declaration');
+    result = first?.childForFieldName('type') ?? null;
+  }
+  if (result?.type === 'pointer_type') {
+    result = result.namedChildren[0] ?? null;
+  }
+  if (result?.type === 'generic_type') {
+    result = result.childForFieldName('type');
+  }
+  if (result?.type !== 'type_identifier') return undefined;
+
+  const type = result.text;
+  const typeParameters = new Set(
+    (declaration.childForFieldName('type_parameters')?.namedChildren ?? []).flatMap((param) =>
+      param.namedChildren.filter((n) => n.type === 'identifier').map((n) => n.text)
+    )
+  );
+  if (GO_PREDECLARED_TYPES.has(type) || typeParameters.has(type)) return undefined;
+
+  const constructorConfidence: ConstructorConfidence =
+    name === 'New' || name.startsWith(`New${type}`)
+      ? 'high'
+      : name.startsWith('New')
+        ? 'medium'
+        : 'low';
+  return { constructs: type, constructorConfidence };
+}
+
+/**
+ * Detect a function or method returning a range-over-func iterator
+ *
+ * Recognizes `iter.Seq[V]`, `iter.Seq2[K, V]`, and the underlying
+ * `func(yield func(V) bool)` / `func(yield func(K, V) bool)` forms as the
+ * only result (a named result counts too).
+ */
+function detectGoIterator(declaration: TreeSitterNode): GoIterator | undefined {
+  let result = declaration.childForFieldName('result');
+  if (result?.type === 'parameter_list') {
+    const params = result.namedChildren.filter((n) => n.type === 'parameter_declaration');
+    if (params.length !== 1) return undefined;
+    result = params[0].childForFieldName('type');
+  }
+  if (!result) return undefined;
+
+  const text = result.text.replace(/\s+/g, ' ').trim();
+  const seq = text.match(/^iter\.(Seq2?)\s*\[(.+)\]$/);
+  const yieldFunc = text.match(/^func\s*\(\s*(?:\w+\s+)?func\s*\((.*)\)\s*bool\s*\)$/);
+  const elementTypes = splitTopLevel(seq ? seq[2] : (yieldFunc?.[1] ?? '')).map((part) =>
+    // Drop yield parameter names: func(k K, v V) bool
+    part.replace(/^(?!(?:func|map|chan|struct|interface)\b)\w+\s+(?=\S)/, '')
+  );
+  const kind = elementTypes.length === 1 ? 'Seq' : elementTypes.length === 2 ? 'Seq2' : undefined;
+  if (!kind || (seq && seq[1] !== kind) || (!seq && !yieldFunc)) return undefined;
+
+  return { kind, elementTypes };
+}
+
+function describeIterator(iterator: GoIterator): string {
+  return `iterator over ${iterator.elementTypes.join(', ')}`;
+}
+
+/**
+ * Parse a runnable example: an `Example...` function without parameters or
+ * results, named for what it documents, whose last comment may hold the
+ * expected output
+ */
+function detectGoExample(declaration: TreeSitterNode, name: string): GoExample | undefined {
+  const naming = parseGoExampleName(name);
+  const params = declaration.childForFieldName('parameters');
+  const body = declaration.childForFieldName('body');
+  if (!naming || declaration.childForFieldName('result') || !body) return undefined;
+  if (params && params.namedChildren.length > 0) return undefined;
+
+  const lines = body.text.slice(body.text.indexOf('{') + 1, body.text.lastIndexOf('}')).split('\n');
+  let outputAt = -1;
+  for (let i = lines.length - 1; i >= 0 && outputAt === -1; i--) {
+    if (/^\s*\/\/\s*(?:unordered )?output:/i.test(lines[i])) outputAt = i;
+    // Only the last comment in the body counts
+    else if (/^\s*\S/.test(lines[i]) && !/^\s*\/\//.test(lines[i])) break;
+  }
+
+  const example: GoExample = {
+    ...naming,
+    code: dedent(outputAt === -1 ? lines : lines.slice(0, outputAt)),
+  };
+  if (outputAt !== -1) {
+    const [first, ...rest] = lines.slice(outputAt).map((line) => line.trim());
+    const header = first.match(/^\/\/\s*(unordered )?output:(.*)$/i) as RegExpMatchArray;
+    const output = [header[2], ...rest.map((line) => line.replace(/^\/\/ ?/, ''))];
+    example.output = output.join('\n').trim();
+    if (header[1]) example.unordered = true;
+  }
+  return example;
+}
+
+/**
+ * Target and suffix from an example name, following `go test`:
+ * `Example_suffix`, `ExampleF_suffix`, `ExampleT_M_suffix`
+ */
+function parseGoExampleName(name: string): Pick<GoExample, 'target' | 'suffix'> | undefined {
+  if (!name.startsWith('Example')) return undefined;
+  const rest = name.slice('Example'.length);
+  if (rest === '') return {};
+  if (rest.startsWith('_')) return { suffix: rest.slice(1) };
+  if (!/^[A-Z]/.test(rest)) return undefined; // Examplefoo is an ordinary function
+
+  const parts = rest.split('_');
+  let target = parts.shift() as string;
+  if (parts.length > 0 && /^[A-Z]/.test(parts[0])) {
+    target += `.${parts.shift()}`;
+  }
+  const suffix = parts.join('_');
+  return suffix ? { target, suffix } : { target };
+}
+
+/**
+ * Trim blank edge lines and the indentation common to all lines
+ */
+function dedent(lines: string[]): string {
+  const kept = [...lines];
+  while (kept.length > 0 && kept[0].trim() === '') kept.shift();
+  while (kept.length > 0 && kept[kept.length - 1].trim() === '') kept.pop();
+  const indents = kept
+    .filter((line) => line.trim() !== '')
+    .map((line) => (line.match(/^\s*/) as RegExpMatchArray)[0].length);
+  const common = indents.length > 0 ? Math.min(...indents) : 0;
+  return kept.map((line) => line.slice(common).trimEnd()).join('\n');
+}
+
+/**
+ * Split a type list on commas (or another separator) outside brackets and parentheses
+ */
+function splitTopLevel(list: string, separator = ','): string[] {
+  const parts: string[] = [];
+  let depth = 0;
+  let current = '';
+  for (const char of list) {
+    if (char === '[' || char === '(' || char === '{') depth++;
+    if (char === ']' || char === ')' || char === '}') depth--;
+    if (char === separator && depth === 0) {
+      parts.push(current.trim());
+      current = '';
+    } else {
+      current += char;
+    }
+  }
+  if (current.trim()) parts.push(current.trim());
+  return parts;
+}
+
+/**
+ * Bracketed type-parameter list following a declared name (`Max[T Ordered]`),
+ * without the brackets
+ */
+function goTypeParameterList(declaration: string, name: string): string | undefined {
+  const open = declaration.indexOf(`${name}[`) + name.length;
+  if (open < name.length) return undefined;
+  let depth = 0;
+  for (let i = open; i < declaration.length; i++) {
+    if (declaration[i] === '[') depth++;
+    if (declaration[i] === ']' && --depth === 0) return declaration.slice(open + 1, i);
+  }
+  return undefined;
+}
+
+/**
+ * Named constraints a declaration's type parameters use: `Ordered`,
+ * `constraints.Integer`, or `Comparable` for `Comparable[T]`, union terms
+ * included. `any`, `comparable`, and inline `interface{...}` constraints name nothing.
+ */
+function goTypeConstraints(declaration: string, name: string): string[] {
+  const list = goTypeParameterList(declaration, name);
+  if (!list) return [];
+  const constraints = new Set<string>();
+  for (const param of splitTopLevel(list)) {
+    // In `K, V Ordered` only the last parameter carries the constraint
+    const constraint = param.replace(/^[A-Za-z_]\w*\s*/, '');
+    for (const term of splitTopLevel(constraint, '|')) {
+      const named = term.replace(/^~/, '').replace(/\[[\s\S]*\]$/, '').trim();
+      if (/^[A-Za-z_]\w*(\.[A-Za-z_]\w*)?$/.test(named) && !GO_PREDECLARED_TYPES.has(named)) {
+        constraints.add(named);
+      }
+    }
+  }
+  return [...constraints];
+}
+
+/**
+ * Type set of an interface body, when the interface is a constraint: union
+ * and `~` terms (`~int | ~int64`), lone non-interface types, and an embedded
+ * `comparable`. Undefined for interfaces of methods and embedded interfaces.
+ */
+function goInterfaceTypeSet(body: string): GoTypeSet | undefined {
+  const elements = body
+    .slice(body.indexOf('{') + 1, body.lastIndexOf('}'))
+    .replace(/\/\*[\s\S]*?\*\//g, '')
+    .replace(/\/\/.*$/gm, '')
+    // A union may continue on the next line after a trailing `|`
+    .replace(/\|\s*\n\s*/g, '| ')
+    .split(/[\n;]/)
+    .map((element) => element.trim())
+    .filter(Boolean);
+
+  const terms: string[] = [];
+  let comparable = false;
+  for (const element of elements) {
+    if (element === 'comparable') {
+      comparable = true;
+    } else if (
+      element.includes('|') ||
+      /^(~|\*|\[|map\[|chan\b|func\s*\()/.test(element) ||
+      (GO_PREDECLARED_TYPES.has(element) && element !== 'any' && element !== 'error')
+    ) {
+      terms.push(...splitTopLevel(element, '|'));
+    }
+  }
+
+  return terms.length > 0 || comparable ? { terms, comparable } : undefined;
+}
+
+/**
+ * Type set as embedding text: `~int | ~int64`, `comparable`, or both
+ */
+function describeTypeSet(typeSet: GoTypeSet): string {
+  const parts = typeSet.terms.length > 0 ? [typeSet.terms.join(' | ')] : [];
+  if (typeSet.comparable) parts.push('comparable');
+  return parts.join(', ');
+}
+
+/**
+ * Strip the markers from a line or block comment
+ */
+function stripCommentMarkers(comment: string): string {
+  if (comment.startsWith('/*')) {
+    return comment.slice(2, -2).trim();
+  }
+  return comment.replace(/^\/\/ ?/, '');
+}
+
+/** Untyped constant kinds by literal node type */
+const UNTYPED_LITERAL_KINDS: Record<string, string> = {
+  int_literal: 'untyped int',
+  float_literal: 'untyped float',
+  imaginary_literal: 'untyped complex',
+  rune_literal: 'untyped rune',
+  interpreted_string_literal: 'untyped string',
+  raw_string_literal: 'untyped string',
+  true: 'untyped bool',
+  false: 'untyped bool',
+};
+
+/**
+ * Type and value of one Go constant
+ *
+ * Literals (optionally negated) and a bare `iota` are evaluated; anything
+ * else keeps its expression text. Untyped constants get their untyped kind
+ * when it follows from the literal, or just "untyped".
+ */
+function describeGoConstant(
+  value: TreeSitterNode | undefined,
+  typeNode: TreeSitterNode | null,
+  iota: number
+): Pick<DocumentMetadata, 'constantType' | 'constantValue' | 'constantExpression'> {
+  let literal: TreeSitterNode | undefined = value;
+  let negated = false;
+  if (value?.type === 'unary_expression' && value.text.startsWith('-')) {
+    literal = value.childForFieldName('operand') ?? undefined;
+    negated = true;
+  }
+
+  let kind: string | undefined;
+  let constantValue: string | undefined;
+  // Newer Go grammars give `iota` its own node type; older ones parse an identifier
+  if ((literal?.type === 'iota' || literal?.type === 'identifier') && literal.text === 'iota') {
+    kind = 'untyped int';
+    constantValue = String(negated ? -iota : iota);
+  } else if (literal && UNTYPED_LITERAL_KINDS[literal.type]) {
+    kind = UNTYPED_LITERAL_KINDS[literal.type];
+    constantValue = negated ? `-${literal.text}` : literal.text;
+  }
+
+  return {
+    constantType: typeNode?.text ?? kind ?? 'untyped',
+    constantValue,
+    constantExpression: constantValue === undefined ? value?.text : undefined,
+  };
+}
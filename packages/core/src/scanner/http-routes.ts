@@ -0,0 +1,292 @@
+/**
+ * HTTP Routes
+ * Route registrations in Go function bodies: `mux.HandleFunc("/users", h)`,
+ * `r.Get("/users/{id}", h.Get)`, `api.POST("/orders", createOrder)`
+ *
+ * Recognition is syntactic and driven by a list of patterns, one per router
+ * call shape, so another router is supported by adding a pattern (the Go
+ * scanner takes the list as a constructor argument). Only string-literal
+ * paths are recorded. Prefixes from router groups are applied when the group
+ * is a local variable (`v1 := r.Group("/v1")`) or a sub-router literal
+ * (`r.Route("/api", func(r chi.Router) {...})`).
+ */
+
+import type { TreeSitterNode } from './tree-sitter';
+import type { HttpRoute } from './types';
+
+/**
+ * A way of registering routes, e.g. net/http's `HandleFunc`
+ */
+export interface RoutePattern {
+  /** Router the pattern belongs to, recorded on each route */
+  framework: string;
+  /** Only applies in files importing one of these packages or a subpackage (any file if unset) */
+  imports?: string[];
+  /** Method names that register a route (`HandleFunc`, `GET`) */
+  calls: string[];
+  /**
+   * Where the HTTP method comes from:
+   * - `path`: an optional `"METHOD "` prefix on the path (`"GET /users"`, net/http since Go 1.22)
+   * - `call`: the call name (`Get`, `POST`); `Any` matches every method
+   * - `argument`: the argument before the path (`r.Handle("GET", "/users", h)`)
+   */
+  method: 'path' | 'call' | 'argument';
+  /** Calls that make a router for a path prefix (`Group`, `Route`) */
+  groups?: string[];
+}
+
+const HTTP_METHODS = [
+  'Get',
+  'Head',
+  'Post',
+  'Put',
+  'Patch',
+  'Delete',
+  'Options',
+  'Connect',
+  'Trace',
+];
+
+/**
+ * Built-in patterns, tried in order; the first that matches a call wins
+ */
+export const GO_ROUTE_PATTERNS: RoutePattern[] = [
+  {
+    framework: 'chi',
+    imports: ['github.com/go-chi/chi'],
+    calls: HTTP_METHODS,
+    method: 'call',
+    groups: ['Route'],
+  },
+  {
+    framework: 'chi',
+    imports: ['github.com/go-chi/chi'],
+    calls: ['Method', 'MethodFunc'],
+    method: 'argument',
+  },
+  {
+    framework: 'chi',
+    imports: ['github.com/go-chi/chi'],
+    calls: ['Handle', 'HandleFunc'],
+    method: 'path',
+  },
+  {
+    framework: 'gin',
+    imports: ['github.com/gin-gonic/gin'],
+    calls: [...HTTP_METHODS.map((m) => m.toUpperCase()), 'Any'],
+    method: 'call',
+    groups: ['Group'],
+  },
+  {
+    framework: 'gin',
+    imports: ['github.com/gin-gonic/gin'],
+    calls: ['Handle'],
+    method: 'argument',
+  },
+  {
+    framework: 'echo',
+    imports: ['github.com/labstack/echo'],
+    calls: [...HTTP_METHODS.map((m) => m.toUpperCase()), 'Any'],
+    method: 'call',
+    groups: ['Group'],
+  },
+  {
+    framework: 'echo',
+    imports: ['github.com/labstack/echo'],
+    calls: ['Add'],
+    method: 'argument',
+  },
+  {
+    framework: 'net/http',
+    calls: ['Handle', 'HandleFunc'],
+    method: 'path',
+  },
+];
+
+/**
+ * Patterns that apply to a file with these imports
+ */
+export function routePatternsFor(
+  imports: string[],
+  patterns: RoutePattern[] = GO_ROUTE_PATTERNS
+): RoutePattern[] {
+  return patterns.filter(
+    (pattern) =>
+      !pattern.imports ||
+      pattern.imports.some((prefix) =>
+        imports.some((imp) => imp === prefix || imp.startsWith(`${prefix}/`))
+      )
+  );
+}
+
+/**
+ * Find the routes a function or method registers, in source order
+ *
+ * @param declaration - Function or method declaration
+ * @param patterns - Patterns that apply to the file (see routePatternsFor)
+ */
+export function extractGoRoutes(
+  declaration: TreeSitterNode,
+  patterns: RoutePattern[]
+): HttpRoute[] {
+  const routes: HttpRoute[] = [];
+  const body = declaration.childForFieldName('body');
+  if (!body || patterns.length === 0) return routes;
+  const groups = new Set(patterns.flatMap((pattern) => pattern.groups ?? []));
+
+  const visit = (node: TreeSitterNode, prefixes: Map<string, string>): void => {
+    if (node.type === 'call_expression') {
+      const route = matchRoute(node, patterns, prefixes);
+      if (route) routes.push(route);
+
+      // A sub-router literal sees its parameter under the group's prefix
+      const group = matchGroup(node, groups, prefixes);
+      const literal = lastArgument(node);
+      if (group && literal?.type === 'func_literal') {
+        const param = literal
+          .childForFieldName('parameters')
+          ?.namedChildren[0]?.childForFieldName('name')?.text;
+        const scoped = new Map(prefixes);
+        if (param) scoped.set(param, group.prefix);
+        visit(literal, scoped);
+        return;
+      }
+    }
+
+    for (const child of node.namedChildren) visit(child, prefixes);
+
+    // `v1 := r.Group("/v1")` puts later uses of v1 under the prefix
+    if (node.type === 'short_var_declaration' || node.type === 'assignment_statement') {
+      const left = node.childForFieldName('left')?.namedChildren ?? [];
+      const right = node.childForFieldName('right')?.namedChildren ?? [];
+      if (left.length === 1 && right.length === 1 && left[0].type === 'identifier') {
+        const group = right[0].type === 'call_expression' && matchGroup(right[0], groups, prefixes);
+        if (group) prefixes.set(left[0].text, group.prefix);
+      }
+    }
+  };
+  visit(body, new Map());
+
+  return routes;
+}
+
+/**
+ * Describe routes for embedding text, e.g. `GET /users, POST /users`
+ */
+export function describeRoutes(routes: HttpRoute[]): string {
+  return routes.map((route) => `${route.method ?? 'ANY'} ${route.path}`).join(', ');
+}
+
+function matchRoute(
+  call: TreeSitterNode,
+  patterns: RoutePattern[],
+  prefixes: Map<string, string>
+): HttpRoute | null {
+  const selector = callSelector(call);
+  if (!selector) return null;
+  const args = call.childForFieldName('arguments')?.namedChildren ?? [];
+
+  for (const pattern of patterns) {
+    if (!pattern.calls.includes(selector.name)) continue;
+
+    const pathIndex = pattern.method === 'argument' ? 1 : 0;
+    // At least one handler after the path; gin and echo allow middleware before the handler
+    if (args.length < pathIndex + 2) continue;
+    let path = stringLiteral(args[pathIndex]);
+    if (path === null) continue;
+
+    let method: string | undefined;
+    if (pattern.method === 'argument') {
+      method = methodArgument(args[0]);
+    } else if (pattern.method === 'call') {
+      method = selector.name === 'Any' ? undefined : selector.name.toUpperCase();
+    } else {
+      const prefixed = path.match(/^([A-Z]+)\s+(\S+)$/);
+      if (prefixed) {
+        method = prefixed[1];
+        path = prefixed[2];
+      }
+    }
+    // Inside a group, an empty path is the group itself (`v1.GET("", h)`)
+    const prefix = prefixes.get(selector.receiver) ?? '';
+    if (!path.startsWith('/') && !(prefix && path === '')) continue;
+
+    const handler = handlerName(args[args.length - 1]);
+    return {
+      ...(method ? { method } : {}),
+      path: joinPath(prefix, path),
+      ...(handler ? { handler } : {}),
+      framework: pattern.framework,
+      line: call.startPosition.row + 1,
+    };
+  }
+  return null;
+}
+
+/**
+ * The prefix of a group call like `r.Group("/v1")` or `r.Route("/api", ...)`
+ */
+function matchGroup(
+  call: TreeSitterNode,
+  groups: Set<string>,
+  prefixes: Map<string, string>
+): { prefix: string } | null {
+  const selector = callSelector(call);
+  if (!selector || !groups.has(selector.name)) return null;
+  const first = call.childForFieldName('arguments')?.namedChildren[0];
+  const path = first ? stringLiteral(first) : null;
+  if (path === null || !path.startsWith('/')) return null;
+  return { prefix: joinPath(prefixes.get(selector.receiver) ?? '', path) };
+}
+
+/**
+ * Receiver and method name of a `x.Method(...)` call
+ */
+function callSelector(call: TreeSitterNode): { receiver: string; name: string } | null {
+  const fn = call.childForFieldName('function');
+  if (fn?.type !== 'selector_expression') return null;
+  const operand = fn.childForFieldName('operand');
+  const field = fn.childForFieldName('field');
+  if (!operand || !field) return null;
+  return { receiver: operand.text, name: field.text };
+}
+
+function lastArgument(call: TreeSitterNode): TreeSitterNode | undefined {
+  const args = call.childForFieldName('arguments')?.namedChildren ?? [];
+  return args[args.length - 1];
+}
+
+/**
+ * Handler as written, without an `http.HandlerFunc(...)` conversion;
+ * undefined for an inline func literal
+ */
+function handlerName(arg: TreeSitterNode): string | undefined {
+  if (arg.type === 'func_literal') return undefined;
+  const fn = arg.type === 'call_expression' ? arg.childForFieldName('function') : null;
+  if (fn?.text === 'http.HandlerFunc') {
+    const inner = arg.childForFieldName('arguments')?.namedChildren[0];
+    if (inner) return handlerName(inner);
+  }
+  return arg.text.replace(/\s+/g, ' ');
+}
+
+/**
+ * HTTP method passed as an argument: `"GET"` or `http.MethodGet`
+ */
+function methodArgument(arg: TreeSitterNode): string {
+  const constant = arg.text.match(/^http\.Method(\w+)$/);
+  return (stringLiteral(arg) ?? constant?.[1] ?? arg.text).toUpperCase();
+}
+
+function stringLiteral(node: TreeSitterNode): string | null {
+  if (node.type !== 'interpreted_string_literal' && node.type !== 'raw_string_literal') {
+    return null;
+  }
+  return node.text.slice(1, -1);
+}
+
+function joinPath(prefix: string, path: string): string {
+  if (!prefix) return path;
+  const joined = `${prefix.replace(/\/+$/, '')}${path === '/' ? '' : path}`;
+  return joined || '/';
+}
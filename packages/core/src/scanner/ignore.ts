@@ -0,0 +1,119 @@
+/**
+ * Ignore Patterns
+ * Index-specific ignore rules layered on top of .gitignore
+ *
+ * Patterns come from the `.devagentignore` file at the repository root and
+ * from the `ignore` scan option. They only affect the semantic index, so
+ * files tracked by git can still be excluded from search results.
+ */
+
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+
+/** Name of the ignore file at the repository root */
+export const IGNORE_FILE_NAME = '.devagentignore';
+
+/**
+ * Default index ignore patterns
+ *
+ * Applied in addition to the scanner's default exclusions. Any of these
+ * can be re-enabled with a negated pattern (e.g. `!vendor/`).
+ */
+export const DEFAULT_IGNORE_PATTERNS: string[] = [
+  '**/vendor/**',
+  '**/node_modules/**',
+  '**/*_generated.go',
+  '**/*.pb.go',
+];
+
+/**
+ * Normalize a user-facing ignore pattern into a globby pattern
+ *
+ * Follows .gitignore conventions:
+ * - `dir/` matches the directory anywhere in the tree
+ * - `/path` is anchored to the repository root
+ * - patterns without a slash match at any depth
+ *
+ * For example `vendor/` becomes a recursive match on any vendor directory,
+ * and `*_generated.go` matches generated Go files in every package.
+ */
+export function normalizeIgnorePattern(pattern: string): string {
+  let result = pattern.trim();
+  if (result.startsWith('**/') || result === '**') {
+    return result;
+  }
+
+  const anchored = result.startsWith('/');
+  if (anchored) {
+    result = result.slice(1);
+  }
+
+  const isDirectory = result.endsWith('/');
+  if (isDirectory) {
+    result = `${result.slice(0, -1)}/**`;
+  }
+
+  // Unanchored patterns without an inner slash match at any depth
+  const hasInnerSlash = result.replace(/\/\*\*$/, '').includes('/');
+  if (!anchored && !hasInnerSlash) {
+    result = `**/${result}`;
+  }
+
+  return result;
+}
+
+/**
+ * Parse ignore file content into raw patterns
+ *
+ * Blank lines and `#` comments are skipped.
+ */
+export function parseIgnoreFile(content: string): string[] {
+  return content
+    .split(/\r?\n/)
+    .map((line) => line.trim())
+    .filter((line) => line.length > 0 && !line.startsWith('#'));
+}
+
+/**
+ * Combine base patterns with user ignore patterns
+ *
+ * User patterns are appended in order. A negated pattern (`!pattern`)
+ * removes a matching base or earlier pattern, which is how defaults are
+ * overridden.
+ *
+ * @param base - Starting patterns (e.g. defaults)
+ * @param patterns - User patterns, possibly negated
+ * @returns Deduped globby ignore patterns
+ */
+export function resolveIgnorePatterns(base: string[], patterns: string[]): string[] {
+  const result = base.map(normalizeIgnorePattern);
+
+  for (const raw of patterns) {
+    if (raw.startsWith('!')) {
+      const negated = normalizeIgnorePattern(raw.slice(1));
+      for (let i = result.length - 1; i >= 0; i--) {
+        if (result[i] === negated) {
+          result.splice(i, 1);
+        }
+      }
+    } else {
+      result.push(normalizeIgnorePattern(raw));
+    }
+  }
+
+  return [...new Set(result)];
+}
+
+/**
+ * Load patterns from the repository's ignore file
+ *
+ * @returns Raw patterns, or an empty array if the file does not exist
+ */
+export async function loadIgnoreFile(repoRoot: string): Promise<string[]> {
+  try {
+    const content = await fs.readFile(path.join(repoRoot, IGNORE_FILE_NAME), 'utf-8');
+    return parseIgnoreFile(content);
+  } catch {
+    return [];
+  }
+}
@@ -1,14 +1,86 @@
 // Export types
 
+export { DEFAULT_MAX_DOCUMENT_BYTES, documentSize, limitDocumentSize } from './document-size';
 export { GoScanner } from './go';
+export {
+  KNOWN_GOARCH,
+  KNOWN_GOOS,
+  matchesGoPlatform,
+  parseGoBuildConstraint,
+  resolveGoPlatform,
+} from './go-build';
+export { extractGoContextFindings, goContextFunctions } from './go-context';
+export { type GeneratedHeader, parseGeneratedHeader } from './go-generated';
+export {
+  canImportInternal,
+  compareModuleVersions,
+  findGoModules,
+  findOwningModule,
+  type GoDependency,
+  type GoModule,
+  type GoReplacement,
+  goVersionAtLeast,
+  parseGoDependencies,
+  parseGoModule,
+  parseGoModulePath,
+  parseGoSum,
+  parseGoVersion,
+  type ResolvedGoImport,
+  resolveGoDependency,
+  resolveGoImport,
+  sortGoModules,
+} from './go-modules';
+export {
+  describeRoutes,
+  extractGoRoutes,
+  GO_ROUTE_PATTERNS,
+  type RoutePattern,
+  routePatternsFor,
+} from './http-routes';
+export {
+  DEFAULT_IGNORE_PATTERNS,
+  IGNORE_FILE_NAME,
+  loadIgnoreFile,
+  normalizeIgnorePattern,
+  parseIgnoreFile,
+  resolveIgnorePatterns,
+} from './ignore';
 export { MarkdownScanner } from './markdown';
+export { OpenApiScanner } from './openapi';
 export { ScannerRegistry } from './registry';
+export {
+  describeSqlQueries,
+  extractGoSqlQueries,
+  parseSqlQuery,
+  queriesTable,
+} from './sql-queries';
+export { assignStableIds, stableDocumentId } from './stable-ids';
+export { looksBinary, TextScanner } from './text';
 export type {
   CalleeInfo,
   CallerInfo,
+  ConstructorConfidence,
+  ContextFinding,
+  CrashContext,
+  CrashKind,
+  CrashSite,
+  DeferredCall,
   Document,
   DocumentMetadata,
+  DocumentOverflow,
   DocumentType,
+  GoDependencyUse,
+  GoDiagnostic,
+  GoExample,
+  GoFuncLiteral,
+  GoIterator,
+  GoPlatform,
+  GoTypeSet,
+  HttpRoute,
+  InterfaceAssertion,
+  OpenApiOperation,
+  PlatformVariant,
+  ReturnedError,
   ScanError,
   Scanner,
   ScannerCapabilities,
@@ -16,14 +88,19 @@ export type {
   ScanProgress,
   ScanResult,
   ScanStats,
+  SqlQuery,
+  StructField,
 } from './types';
 // Export scanner implementations
 export { TypeScriptScanner } from './typescript';
+export { isGoExported, isGoMethodExported } from './visibility';
 
 import { GoScanner } from './go';
 import { MarkdownScanner } from './markdown';
-// Create default scanner registry with TypeScript, Markdown, and Go
+import { OpenApiScanner } from './openapi';
+// Create default scanner registry with TypeScript, Markdown, Go, OpenAPI, and text/config
 import { ScannerRegistry } from './registry';
+import { TextScanner } from './text';
 import type { ScanOptions } from './types';
 import { TypeScriptScanner } from './typescript';
 
@@ -42,6 +119,14 @@ export function createDefaultRegistry(): ScannerRegistry {
   // Register Go scanner
   registry.register(new GoScanner());
 
+  // Register OpenAPI scanner ahead of the text scanner, which would otherwise claim specs
+  registry.register(new OpenApiScanner());
+
+  // Register text/config scanner, also the fallback for anything included explicitly
+  const textScanner = new TextScanner();
+  registry.register(textScanner);
+  registry.setFallback(textScanner);
+
   return registry;
 }
 
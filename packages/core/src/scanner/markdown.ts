@@ -4,11 +4,13 @@ import type { Logger } from '@lytics/kero';
 import type { Code, Heading, Paragraph, Root } from 'mdast';
 import remarkParse from 'remark-parse';
 import { unified } from 'unified';
-import type { Document, Scanner, ScannerCapabilities } from './types';
+import type { Document, ScanError, Scanner, ScannerCapabilities } from './types';
 
 /**
  * Markdown scanner using remark
- * Extracts documentation sections and code blocks
+ * Extracts heading-delimited documentation sections, titled by their heading
+ *
+ * Text before the first heading becomes a section titled with the file name.
  */
 export class MarkdownScanner implements Scanner {
   readonly language = 'markdown';
@@ -26,11 +28,14 @@ export class MarkdownScanner implements Scanner {
     files: string[],
     repoRoot: string,
     _logger?: Logger,
-    _onProgress?: (filesProcessed: number, totalFiles: number) => void
+    _onProgress?: (filesProcessed: number, totalFiles: number) => void,
+    _onError?: (error: ScanError) => void,
+    signal?: AbortSignal
   ): Promise<Document[]> {
     const documents: Document[] = [];
 
     for (const file of files) {
+      signal?.throwIfAborted();
       const absolutePath = path.join(repoRoot, file);
       const content = await fs.readFile(absolutePath, 'utf-8');
 
@@ -48,55 +53,61 @@ export class MarkdownScanner implements Scanner {
     const processor = unified().use(remarkParse);
     const tree = processor.parse(content) as Root;
 
-    let currentHeading: string | null = null;
-    let _currentLevel = 0;
-    let currentContent: string[] = [];
-    let currentStartLine = 1;
+    // Enclosing headings by depth, so each section is titled with its full path
+    const headingPath: string[] = [];
+    let current: { heading: string; path: string[]; startLine: number; blocks: string[] } = {
+      heading: path.basename(file),
+      path: [],
+      startLine: 1,
+      blocks: [],
+    };
+
+    const flush = (endLine: number) => {
+      if (current.blocks.length > 0) {
+        documents.push(
+          this.createDocument({
+            file,
+            heading: current.heading,
+            headingPath: current.path,
+            content: current.blocks.join('\n\n'),
+            startLine: current.startLine,
+            endLine,
+          })
+        );
+      }
+    };
 
     // Walk the AST
     for (const node of tree.children) {
       if (node.type === 'heading') {
         // Save previous section if exists
-        if (currentHeading && currentContent.length > 0) {
-          documents.push(
-            this.createDocument({
-              file,
-              heading: currentHeading,
-              content: currentContent.join('\n\n'),
-              startLine: currentStartLine,
-              endLine: node.position?.start.line || currentStartLine,
-            })
-          );
-        }
+        flush(node.position?.start.line || current.startLine);
 
         // Start new section
         const headingNode = node as Heading;
-        currentHeading = this.extractTextFromNode(headingNode);
-        _currentLevel = headingNode.depth;
-        currentContent = [];
-        currentStartLine = node.position?.start.line || 1;
+        const heading = this.extractTextFromNode(headingNode);
+        headingPath.length = Math.min(headingPath.length, headingNode.depth - 1);
+        headingPath[headingNode.depth - 1] = heading;
+        current = {
+          heading,
+          path: headingPath.filter(Boolean),
+          startLine: node.position?.start.line || 1,
+          blocks: [],
+        };
       } else if (node.type === 'paragraph') {
-        const paragraphNode = node as Paragraph;
-        const text = this.extractTextFromNode(paragraphNode);
-        currentContent.push(text);
+        current.blocks.push(this.extractTextFromNode(node as Paragraph));
       } else if (node.type === 'code') {
         const codeNode = node as Code;
-        currentContent.push(`\`\`\`${codeNode.lang || ''}\n${codeNode.value}\n\`\`\``);
+        current.blocks.push(`\`\`\`${codeNode.lang || ''}\n${codeNode.value}\n\`\`\``);
+      } else if (node.position?.start.offset !== undefined) {
+        // Lists, tables, quotes, and HTML keep their source text
+        const source = content.slice(node.position.start.offset, node.position.end.offset);
+        if (source.trim()) current.blocks.push(source);
       }
     }
 
     // Save last section
-    if (currentHeading && currentContent.length > 0) {
-      documents.push(
-        this.createDocument({
-          file,
-          heading: currentHeading,
-          content: currentContent.join('\n\n'),
-          startLine: currentStartLine,
-          endLine: content.split('\n').length,
-        })
-      );
-    }
+    flush(content.split('\n').length);
 
     return documents;
   }
@@ -117,14 +128,16 @@ export class MarkdownScanner implements Scanner {
   private createDocument(params: {
     file: string;
     heading: string;
+    headingPath: string[];
     content: string;
     startLine: number;
     endLine: number;
   }): Document {
-    const { file, heading, content, startLine, endLine } = params;
+    const { file, heading, headingPath, content, startLine, endLine } = params;
 
-    // Build text for embedding
-    const text = `${heading}\n\n${content}`;
+    // Build text for embedding; the heading path ranks sections by their place in the doc
+    const title = headingPath.length > 1 ? headingPath.join(' > ') : heading;
+    const text = `${title}\n\n${content}`;
 
     // Create clean ID
     const id = `${file}:${this.slugify(heading)}:${startLine}`;
@@ -0,0 +1,403 @@
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+import type { Logger } from '@lytics/kero';
+import { TextScanner } from './text';
+import type { Document, OpenApiOperation, ScanError, Scanner, ScannerCapabilities } from './types';
+
+/** Spec files by name: `openapi.yaml`, `swagger.json`, `users.openapi.yml`, `openapi-v2.yaml` */
+const SPEC_FILE_NAME = /(?:^|[/._-])(?:openapi|swagger)(?:[._-][^/]*)?\.(?:ya?ml|json)$/i;
+
+/** Any YAML or JSON file directly in an `openapi/` or `swagger/` directory */
+const SPEC_DIRECTORY_FILE = /(?:^|\/)(?:openapi|swagger)\/[^/]+\.(?:ya?ml|json)$/i;
+
+/** Specs larger than this are skipped */
+const MAX_SPEC_BYTES = 10 * 1024 * 1024;
+
+/** Keys of a path item that declare operations */
+const HTTP_METHODS = ['get', 'put', 'post', 'delete', 'options', 'head', 'patch', 'trace'];
+
+/** `key: value` or `key:`, with a plain or quoted key */
+const MAPPING_ENTRY = /^("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s"'#[{][^#]*?)\s*:(?:\s+(.*))?$/;
+
+/**
+ * OpenAPI and Swagger spec scanner
+ *
+ * Indexes each operation of a spec (OpenAPI 3 or Swagger 2, YAML or JSON) as
+ * a document with its method, path, operationId, summary, and description, so
+ * a search for "create user endpoint" finds the spec alongside the Go handler.
+ * The operation is recorded in `openApiOperation` metadata, which dev_openapi
+ * uses to link it to the handler of the matching route.
+ *
+ * Specs are recognized by file name (see SPEC_FILE_NAME and
+ * SPEC_DIRECTORY_FILE). A matching file that turns out not to be a spec is
+ * indexed by the text scanner instead.
+ */
+export class OpenApiScanner implements Scanner {
+  readonly language = 'openapi';
+  readonly capabilities: ScannerCapabilities = {
+    syntax: false,
+    documentation: true,
+  };
+  readonly extensions = ['.yaml', '.yml', '.json'];
+
+  private readonly text = new TextScanner();
+
+  canHandle(filePath: string): boolean {
+    return SPEC_FILE_NAME.test(filePath) || SPEC_DIRECTORY_FILE.test(filePath);
+  }
+
+  async scan(
+    files: string[],
+    repoRoot: string,
+    logger?: Logger,
+    onProgress?: (filesProcessed: number, totalFiles: number) => void,
+    onError?: (error: ScanError) => void,
+    signal?: AbortSignal
+  ): Promise<Document[]> {
+    const documents: Document[] = [];
+
+    for (const [i, file] of files.entries()) {
+      signal?.throwIfAborted();
+      try {
+        const absolutePath = path.join(repoRoot, file);
+        const stat = await fs.stat(absolutePath);
+        if (stat.size > MAX_SPEC_BYTES) {
+          logger?.debug({ file, bytes: stat.size }, 'Skipping large OpenAPI spec');
+          continue;
+        }
+
+        const content = await fs.readFile(absolutePath, 'utf-8');
+        const operations = this.extractFromSpec(content, file);
+        if (operations) {
+          documents.push(...operations);
+        } else {
+          documents.push(...(await this.text.scan([file], repoRoot, logger, undefined, onError)));
+        }
+      } catch (error) {
+        onError?.({
+          file,
+          error: error instanceof Error ? error.message : String(error),
+          phase: 'extractFromFile',
+        });
+      }
+      onProgress?.(i + 1, files.length);
+    }
+
+    return documents;
+  }
+
+  /**
+   * One document per operation; null if the file isn't an OpenAPI or Swagger spec
+   */
+  private extractFromSpec(content: string, file: string): Document[] | null {
+    const lines = content.split('\n');
+    const json = path.extname(file).toLowerCase() === '.json';
+    const keyLines = new WeakMap<object, number>();
+
+    let spec: unknown;
+    try {
+      spec = json ? JSON.parse(content) : new YamlReader([...lines], keyLines).read();
+    } catch {
+      return null;
+    }
+    if (!isObject(spec) || !(spec.openapi || spec.swagger) || !isObject(spec.paths)) {
+      return null;
+    }
+
+    const basePath = specBasePath(spec);
+    const documents: Document[] = [];
+    let cursor = json ? jsonKeyLine(lines, 'paths', 0) : 0;
+
+    for (const [specPath, pathItem] of Object.entries(spec.paths)) {
+      if (!isObject(pathItem)) continue;
+      const pathLine = json
+        ? jsonKeyLine(lines, specPath, cursor)
+        : (keyLines.get(pathItem) ?? 1);
+      cursor = Math.max(cursor, pathLine);
+
+      for (const method of HTTP_METHODS) {
+        const operation = pathItem[method];
+        if (!isObject(operation)) continue;
+
+        const startLine = json
+          ? jsonKeyLine(lines, method, pathLine)
+          : (keyLines.get(operation) ?? pathLine);
+        const endLine = blockEnd(lines, startLine);
+        documents.push(
+          operationDocument(
+            {
+              method: method.toUpperCase(),
+              path: specPath,
+              ...(basePath ? { basePath } : {}),
+              ...stringField(operation, 'operationId'),
+              ...stringField(operation, 'summary'),
+              ...(Array.isArray(operation.tags) ? { tags: operation.tags.map(String) } : {}),
+            },
+            typeof operation.description === 'string' ? operation.description : undefined,
+            file,
+            lines.slice(startLine - 1, endLine).join('\n'),
+            startLine,
+            endLine
+          )
+        );
+      }
+    }
+
+    return documents;
+  }
+}
+
+function operationDocument(
+  operation: OpenApiOperation,
+  description: string | undefined,
+  file: string,
+  snippet: string,
+  startLine: number,
+  endLine: number
+): Document {
+  const name = `${operation.method} ${operation.path}`;
+  const fullPath = `${operation.basePath ?? ''}${operation.path}`;
+  const operationId = operation.operationId ? ` (operationId ${operation.operationId})` : '';
+  const text = [
+    `API endpoint ${operation.method} ${fullPath}${operationId}`,
+    operation.summary,
+    description?.trim(),
+    operation.tags?.length ? `tags: ${operation.tags.join(', ')}` : undefined,
+  ]
+    .filter(Boolean)
+    .join('\n\n');
+
+  return {
+    id: `${file}:${name}:${startLine}`,
+    text,
+    type: 'documentation',
+    language: 'openapi',
+    metadata: {
+      file,
+      startLine,
+      endLine,
+      name,
+      exported: true,
+      snippet,
+      ...(operation.summary ? { docstring: operation.summary } : {}),
+      openApiOperation: operation,
+    },
+  };
+}
+
+/**
+ * Path every operation is served under: the path of the first server URL
+ * (OpenAPI 3) or `basePath` (Swagger 2)
+ */
+function specBasePath(spec: Record<string, unknown>): string | undefined {
+  let base: unknown = spec.basePath;
+  if (Array.isArray(spec.servers) && isObject(spec.servers[0])) {
+    // Drop the scheme and host, which may be templated (`{scheme}://{host}/v1`)
+    base = String(spec.servers[0].url ?? '').replace(/^[^/]*\/\/[^/]*/, '');
+  }
+  const trimmed = typeof base === 'string' ? base.replace(/\/+$/, '') : '';
+  return trimmed.startsWith('/') ? trimmed : undefined;
+}
+
+function stringField(
+  object: Record<string, unknown>,
+  key: 'operationId' | 'summary'
+): Partial<OpenApiOperation> {
+  const value = object[key];
+  return typeof value === 'string' && value.trim() ? { [key]: value.trim() } : {};
+}
+
+function isObject(value: unknown): value is Record<string, unknown> {
+  return typeof value === 'object' && value !== null && !Array.isArray(value);
+}
+
+function indentOf(line: string): number {
+  return line.length - line.trimStart().length;
+}
+
+/**
+ * Line (1-based) of the first `"key":` at or after a line of pretty-printed JSON
+ */
+function jsonKeyLine(lines: string[], key: string, fromLine: number): number {
+  const quoted = JSON.stringify(key);
+  for (let i = Math.max(fromLine - 1, 0); i < lines.length; i++) {
+    const at = lines[i].indexOf(quoted);
+    if (at >= 0 && /^\s*:/.test(lines[i].slice(at + quoted.length))) return i + 1;
+  }
+  return Math.max(fromLine, 1);
+}
+
+/**
+ * Last line (1-based) of the block a key opens: the lines indented under it,
+ * plus a closing bracket at the key's own indent
+ */
+function blockEnd(lines: string[], startLine: number): number {
+  const indent = indentOf(lines[startLine - 1] ?? '');
+  let end = startLine;
+  for (let i = startLine; i < lines.length; i++) {
+    const line = lines[i];
+    if (!line.trim()) continue;
+    if (indentOf(line) > indent) {
+      end = i + 1;
+      continue;
+    }
+    if (indentOf(line) === indent && /^[}\]]/.test(line.trim())) end = i + 1;
+    break;
+  }
+  return end;
+}
+
+function isSequenceItem(text: string): boolean {
+  return text === '-' || text.startsWith('- ');
+}
+
+/**
+ * Minimal YAML reader, enough for OpenAPI specs
+ *
+ * Reads block mappings and sequences, plain scalars (continued on
+ * more-indented lines), quoted scalars, block scalars (`|`, `>`), and flow
+ * sequences. Scalars stay strings. Anchors, aliases, and flow mappings other
+ * than `{}` are kept as plain text. The line of the key holding each mapping
+ * or sequence is recorded in `keyLines`.
+ */
+class YamlReader {
+  private index = 0;
+
+  /**
+   * @param lines - Lines of the document; rewritten in place while reading
+   * @param keyLines - Receives the 1-based line of each nested value's key
+   */
+  constructor(
+    private readonly lines: string[],
+    private readonly keyLines: WeakMap<object, number>
+  ) {}
+
+  read(): unknown {
+    const next = this.peek();
+    return next ? this.readNode(next.indent) : null;
+  }
+
+  /**
+   * Next content line, past blank lines, comments, and document markers
+   */
+  private peek(): { indent: number; text: string } | null {
+    for (; this.index < this.lines.length; this.index++) {
+      const line = this.lines[this.index];
+      const text = line.trim();
+      if (text && !text.startsWith('#') && text !== '---' && text !== '...') {
+        return { indent: indentOf(line), text };
+      }
+    }
+    return null;
+  }
+
+  private readNode(indent: number): unknown {
+    const next = this.peek();
+    if (!next) return null;
+    return isSequenceItem(next.text) ? this.readSequence(indent) : this.readMapping(indent);
+  }
+
+  private readMapping(indent: number): Record<string, unknown> {
+    const mapping: Record<string, unknown> = {};
+    for (let next = this.peek(); next && next.indent >= indent; next = this.peek()) {
+      const entry = next.indent === indent ? MAPPING_ENTRY.exec(next.text) : null;
+      if (!entry) {
+        if (next.indent === indent && isSequenceItem(next.text)) break;
+        // Not something this reader understands; skip it rather than stop
+        this.index++;
+        continue;
+      }
+
+      const keyLine = this.index + 1;
+      this.index++;
+      const value = this.readValue(entry[2] ?? '', indent);
+      if (typeof value === 'object' && value !== null) this.keyLines.set(value, keyLine);
+      mapping[unquote(entry[1])] = value;
+    }
+    return mapping;
+  }
+
+  private readSequence(indent: number): unknown[] {
+    const items: unknown[] = [];
+    for (let next = this.peek(); next?.indent === indent; next = this.peek()) {
+      if (!isSequenceItem(next.text)) break;
+      const rest = next.text.slice(1).trimStart();
+      const itemLine = this.index + 1;
+
+      let item: unknown;
+      if (MAPPING_ENTRY.test(rest)) {
+        // `- key: value` starts a mapping indented to where its first key is
+        const offset = indent + next.text.length - rest.length;
+        this.lines[this.index] = `${' '.repeat(offset)}${rest}`;
+        item = this.readMapping(offset);
+      } else {
+        this.index++;
+        item = this.readValue(rest, indent);
+      }
+      if (typeof item === 'object' && item !== null) this.keyLines.set(item, itemLine);
+      items.push(item);
+    }
+    return items;
+  }
+
+  /**
+   * Value following a key or sequence dash on a line indented by `indent`
+   */
+  private readValue(raw: string, indent: number): unknown {
+    const text = raw.trim();
+    if (text === '' || text.startsWith('#')) {
+      const next = this.peek();
+      if (next && next.indent > indent) return this.readNode(next.indent);
+      if (next?.indent === indent && isSequenceItem(next.text)) return this.readSequence(indent);
+      return null;
+    }
+    if (/^[|>][-+0-9]*(\s+#.*)?$/.test(text)) {
+      return this.readBlockScalar(indent, text.startsWith('|'));
+    }
+    if (text.startsWith('"') || text.startsWith("'")) {
+      return unquote(text.match(/^("(?:[^"\\]|\\.)*"|'(?:[^']|'')*')/)?.[1] ?? text);
+    }
+    if (text === '{}') return {};
+    if (text.startsWith('[') && text.endsWith(']')) {
+      const inner = text.slice(1, -1).trim();
+      return inner ? inner.split(',').map((item) => unquote(item.trim())) : [];
+    }
+
+    // Plain scalars may continue on more-indented lines
+    let value = text.replace(/\s+#.*$/, '');
+    for (let next = this.peek(); next && next.indent > indent; next = this.peek()) {
+      value += ` ${next.text}`;
+      this.index++;
+    }
+    return value;
+  }
+
+  private readBlockScalar(indent: number, literal: boolean): string {
+    const body: string[] = [];
+    for (; this.index < this.lines.length; this.index++) {
+      const line = this.lines[this.index];
+      if (line.trim() && indentOf(line) <= indent) break;
+      body.push(line);
+    }
+    while (body.length > 0 && !body[body.length - 1].trim()) body.pop();
+
+    const margin = Math.min(...body.filter((line) => line.trim()).map(indentOf));
+    const text = body.map((line) => line.slice(margin)).join('\n');
+    // Folded scalars join lines with spaces; blank lines stay line breaks
+    return literal ? text : text.replace(/([^\n])\n(?=[^\n])/g, '$1 ');
+  }
+}
+
+function unquote(text: string): string {
+  if (text.length >= 2 && text.startsWith('"') && text.endsWith('"')) {
+    try {
+      return JSON.parse(text);
+    } catch {
+      return text.slice(1, -1);
+    }
+  }
+  if (text.length >= 2 && text.startsWith("'") && text.endsWith("'")) {
+    return text.slice(1, -1).replace(/''/g, "'");
+  }
+  return text;
+}
@@ -1,11 +1,32 @@
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
 import { globby } from 'globby';
-import type { Document, Scanner, ScanOptions, ScanProgress, ScanResult } from './types';
+import { ThroughputEstimator, throttleProgress } from '../utils/progress';
+import { limitDocumentSize } from './document-size';
+import { DEFAULT_IGNORE_PATTERNS, loadIgnoreFile, resolveIgnorePatterns } from './ignore';
+import { assignStableIds } from './stable-ids';
+import { TEXT_SNIFF_BYTES } from './text';
+import type {
+  Document,
+  DocumentType,
+  LanguageScanStats,
+  ScanError,
+  Scanner,
+  ScanOptions,
+  ScanProgress,
+  ScanResult,
+} from './types';
 
 /**
  * Scanner registry manages multiple language scanners
+ *
+ * Files are dispatched by path first. A file no scanner claims by path is
+ * offered to each scanner again with the start of its content (shebangs,
+ * modelines), then to the fallback scanner if one is set.
  */
 export class ScannerRegistry {
   private scanners: Map<string, Scanner> = new Map();
+  private fallback?: Scanner;
 
   /**
    * Register a scanner for a specific language
@@ -14,6 +35,16 @@ export class ScannerRegistry {
     this.scanners.set(scanner.language, scanner);
   }
 
+  /**
+   * Set the scanner for files no registered scanner handles
+   *
+   * The fallback only sees files that discovery finds, so it applies to files
+   * matched by `include` patterns rather than widening a default scan.
+   */
+  setFallback(scanner: Scanner): void {
+    this.fallback = scanner;
+  }
+
   /**
    * Get scanner for a specific language
    */
@@ -30,23 +61,45 @@ export class ScannerRegistry {
 
   /**
    * Find appropriate scanner for a file
+   *
+   * @param filePath - File path (relative)
+   * @param content - Start of the file, to match by content rather than path
    */
-  getScannerForFile(filePath: string): Scanner | undefined {
+  getScannerForFile(filePath: string, content?: string): Scanner | undefined {
     for (const scanner of this.scanners.values()) {
-      if (scanner.canHandle(filePath)) {
+      if (scanner.canHandle(filePath, content)) {
         return scanner;
       }
     }
     return undefined;
   }
 
+  /**
+   * Find the scanner for a discovered file: by path, then content, then the fallback
+   */
+  private async resolveScanner(file: string, repoRoot: string): Promise<Scanner | undefined> {
+    const byPath = this.getScannerForFile(file);
+    if (byPath) return byPath;
+
+    const head = await readHead(path.join(repoRoot, file));
+    if (head === null) return undefined;
+
+    // The fallback may also be registered; it only gets files no other scanner wants
+    for (const scanner of this.scanners.values()) {
+      if (scanner !== this.fallback && scanner.canHandle(file, head)) {
+        return scanner;
+      }
+    }
+    return this.fallback?.canHandle(file, head) ? this.fallback : undefined;
+  }
+
   /**
    * Get all supported file extensions
    */
   getSupportedExtensions(): Set<string> {
     const extensions = new Set<string>();
     for (const scanner of this.scanners.values()) {
-      const langExtensions = this.getExtensionsForLanguage(scanner.language);
+      const langExtensions = this.getExtensions(scanner);
       for (const ext of langExtensions) {
         extensions.add(ext);
       }
@@ -59,25 +112,37 @@ export class ScannerRegistry {
    */
   async scanRepository(options: ScanOptions): Promise<ScanResult> {
     const startTime = Date.now();
-    const errors: Array<{ file: string; error: string }> = [];
+    const errors: ScanError[] = [];
     const logger = options.logger?.child({ component: 'scanner' });
-    const onProgress = options.onProgress;
-
-    // Helper to emit progress
-    const emitProgress = (progress: Partial<ScanProgress>) => {
-      onProgress?.({
-        phase: 'discovery',
-        filesTotal: 0,
-        filesScanned: 0,
-        documentsExtracted: 0,
-        errors: errors.length,
-        ...progress,
-      });
+    const onProgress = options.onProgress
+      ? throttleProgress(options.onProgress, options.progressIntervalMs)
+      : undefined;
+    const throughput = new ThroughputEstimator();
+
+    // Helper to emit progress; scanners report per file, so only phase changes are forced
+    const emitProgress = (progress: Partial<ScanProgress>, force = false) => {
+      if (!onProgress) return;
+      const filesTotal = progress.filesTotal ?? 0;
+      const filesScanned = progress.filesScanned ?? 0;
+      throughput.record(filesScanned);
+      onProgress(
+        {
+          phase: 'discovery',
+          filesTotal,
+          filesScanned,
+          documentsExtracted: 0,
+          errors: errors.length,
+          percentComplete: filesTotal > 0 ? Math.round((filesScanned / filesTotal) * 100) : 0,
+          etaSeconds: progress.phase === 'complete' ? 0 : throughput.eta(filesTotal),
+          ...progress,
+        },
+        force
+      );
     };
 
     // Phase 1: Discovery
     logger?.info({ repoRoot: options.repoRoot }, 'Starting repository scan');
-    emitProgress({ phase: 'discovery' });
+    emitProgress({ phase: 'discovery' }, true);
 
     // Build glob patterns
     const patterns = this.buildGlobPatterns(options);
@@ -85,7 +150,8 @@ export class ScannerRegistry {
     // Find all files
     const files = await globby(patterns, {
       cwd: options.repoRoot,
-      ignore: options.exclude || this.getDefaultExclusions(),
+      ignore: await this.resolveIgnore(options),
+      gitignore: options.respectGitignore ?? true,
       absolute: false,
     });
 
@@ -95,7 +161,7 @@ export class ScannerRegistry {
     const filesByScanner = new Map<Scanner, string[]>();
 
     for (const file of files) {
-      const scanner = this.getScannerForFile(file);
+      const scanner = await this.resolveScanner(file, options.repoRoot);
       if (scanner) {
         const existing = filesByScanner.get(scanner) || [];
         existing.push(file);
@@ -115,24 +181,39 @@ export class ScannerRegistry {
 
     // Phase 2: Scanning
     const allDocuments: Document[] = [];
+    const byLanguage: Record<string, LanguageScanStats> = {};
     let totalFilesScanned = 0;
 
     for (const [scanner, scannerFiles] of filesByScanner.entries()) {
+      options.signal?.throwIfAborted();
       logger?.debug(
         { language: scanner.language, fileCount: scannerFiles.length },
         `Scanning ${scanner.language}...`
       );
 
-      emitProgress({
-        phase: 'scanning',
-        language: scanner.language,
-        filesTotal: files.length,
-        filesScanned: totalFilesScanned,
-        documentsExtracted: allDocuments.length,
-      });
+      emitProgress(
+        {
+          phase: 'scanning',
+          language: scanner.language,
+          filesTotal: files.length,
+          filesScanned: totalFilesScanned,
+          documentsExtracted: allDocuments.length,
+        },
+        true
+      );
+
+      const languageStats: LanguageScanStats = {
+        files: scannerFiles.length,
+        documents: 0,
+        byKind: {},
+        parseFailures: 0,
+        duration: 0,
+      };
+      byLanguage[scanner.language] = languageStats;
+      const scanStart = Date.now();
 
       try {
-        const documents = await scanner.scan(
+        const scanned = await scanner.scan(
           scannerFiles,
           options.repoRoot,
           logger,
@@ -145,16 +226,40 @@ export class ScannerRegistry {
               filesScanned: totalFilesScanned + filesProcessed,
               documentsExtracted: allDocuments.length,
             });
-          }
+          },
+          (error) => {
+            errors.push(error);
+            languageStats.parseFailures++;
+          },
+          options.signal
+        );
+        const documents = assignStableIds(
+          limitDocumentSize(scanned, options.maxDocumentBytes, logger)
         );
         allDocuments.push(...documents);
         totalFilesScanned += scannerFiles.length;
+        languageStats.documents = documents.length;
+        languageStats.byKind = countByKind(documents);
+        languageStats.duration = Date.now() - scanStart;
 
         logger?.info(
-          { language: scanner.language, files: scannerFiles.length, documents: documents.length },
+          {
+            language: scanner.language,
+            files: scannerFiles.length,
+            documents: documents.length,
+            parseFailures: languageStats.parseFailures,
+            duration: languageStats.duration,
+          },
           `${scanner.language} scan complete`
         );
 
+        if (documents.length === 0 && scannerFiles.length > 0) {
+          logger?.warn(
+            { language: scanner.language, files: scannerFiles.length },
+            `${scanner.language} scanner extracted no documents from ${scannerFiles.length} files`
+          );
+        }
+
         emitProgress({
           phase: 'scanning',
           language: scanner.language,
@@ -163,6 +268,9 @@ export class ScannerRegistry {
           documentsExtracted: allDocuments.length,
         });
       } catch (error) {
+        // Cancellation isn't a scanner failure; stop the whole scan
+        options.signal?.throwIfAborted();
+        languageStats.duration = Date.now() - scanStart;
         const errorMessage = error instanceof Error ? error.message : String(error);
         errors.push({
           file: `[${scanner.language}]`,
@@ -177,6 +285,7 @@ export class ScannerRegistry {
 
     // Phase 3: Complete
     const duration = Date.now() - startTime;
+    const byKind = countByKind(allDocuments);
 
     logger?.info(
       {
@@ -184,17 +293,21 @@ export class ScannerRegistry {
         totalDocuments: allDocuments.length,
         duration: `${duration}ms`,
         byLanguage: languageBreakdown,
+        byKind,
         errors: errors.length,
       },
       'Repository scan complete'
     );
 
-    emitProgress({
-      phase: 'complete',
-      filesTotal: files.length,
-      filesScanned: totalFilesScanned,
-      documentsExtracted: allDocuments.length,
-    });
+    emitProgress(
+      {
+        phase: 'complete',
+        filesTotal: files.length,
+        filesScanned: totalFilesScanned,
+        documentsExtracted: allDocuments.length,
+      },
+      true
+    );
 
     return {
       documents: allDocuments,
@@ -203,6 +316,8 @@ export class ScannerRegistry {
         documentsExtracted: allDocuments.length,
         duration,
         errors,
+        byKind,
+        byLanguage,
       },
     };
   }
@@ -218,7 +333,7 @@ export class ScannerRegistry {
 
     for (const scanner of this.scanners.values()) {
       // Get common extensions for each language
-      const langExtensions = this.getExtensionsForLanguage(scanner.language);
+      const langExtensions = this.getExtensions(scanner);
       for (const ext of langExtensions) {
         extensions.add(ext);
       }
@@ -227,11 +342,26 @@ export class ScannerRegistry {
     return Array.from(extensions).map((ext) => `**/*${ext}`);
   }
 
+  /**
+   * Resolve the full ignore list for a scan
+   *
+   * Layers: exclusions (defaults unless overridden), index ignore defaults,
+   * then `.devagentignore` and `options.ignore`, where `!pattern` lifts a
+   * pattern from an earlier layer. .gitignore is applied separately by globby.
+   */
+  async resolveIgnore(options: ScanOptions): Promise<string[]> {
+    const fileIgnores = await loadIgnoreFile(options.repoRoot);
+    return resolveIgnorePatterns(
+      [...(options.exclude || this.getDefaultExclusions()), ...DEFAULT_IGNORE_PATTERNS],
+      [...fileIgnores, ...(options.ignore ?? [])]
+    );
+  }
+
   /**
    * Get default exclusion patterns based on industry best practices
    * Excludes dependencies, build artifacts, caches, IDE files, and other non-source files
    */
-  private getDefaultExclusions(): string[] {
+  getDefaultExclusions(): string[] {
     return [
       // Dependencies
       '**/node_modules/**',
@@ -253,8 +383,6 @@ export class ScannerRegistry {
       '**/*.gen.go', // Code generators
       '**/*_gen.go', // Alternative generator pattern
       '**/*.pb.gw.go', // gRPC gateway
-      '**/mock_*.go', // Mockgen files
-      '**/mocks/**', // Mock directories
       '**/testdata/**', // Test fixtures
 
       // Version control
@@ -308,6 +436,10 @@ export class ScannerRegistry {
     ];
   }
 
+  private getExtensions(scanner: Scanner): readonly string[] {
+    return scanner.extensions ?? this.getExtensionsForLanguage(scanner.language);
+  }
+
   private getExtensionsForLanguage(language: string): string[] {
     const extensionMap: Record<string, string[]> = {
       typescript: ['.ts', '.tsx', '.js', '.jsx', '.mjs', '.cjs'], // TypeScript scanner handles JS too
@@ -321,3 +453,28 @@ export class ScannerRegistry {
     return extensionMap[language] || [];
   }
 }
+
+/**
+ * Read the start of a file for content-based dispatch, or null if unreadable
+ */
+async function readHead(filePath: string): Promise<string | null> {
+  let handle: fs.FileHandle | undefined;
+  try {
+    handle = await fs.open(filePath, 'r');
+    const buffer = Buffer.alloc(TEXT_SNIFF_BYTES);
+    const { bytesRead } = await handle.read(buffer, 0, TEXT_SNIFF_BYTES, 0);
+    return buffer.subarray(0, bytesRead).toString('utf-8');
+  } catch {
+    return null;
+  } finally {
+    await handle?.close();
+  }
+}
+
+function countByKind(documents: Document[]): Partial<Record<DocumentType, number>> {
+  const counts: Partial<Record<DocumentType, number>> = {};
+  for (const doc of documents) {
+    counts[doc.type] = (counts[doc.type] ?? 0) + 1;
+  }
+  return counts;
+}
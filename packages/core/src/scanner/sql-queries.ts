@@ -0,0 +1,146 @@
+/**
+ * SQL Queries
+ * SQL statements written as string literals in Go function bodies:
+ * `db.QueryContext(ctx, "SELECT id FROM users WHERE email = $1", email)`
+ *
+ * Recognition is deliberately conservative. A literal (or a `+` concatenation
+ * of literals) counts as a query only when it starts with SELECT, INSERT,
+ * UPDATE, or DELETE and has the clause that statement needs (FROM, INTO,
+ * SET). Lowercase statements must also contain SQL punctuation, so prose like
+ * "select one from the list" isn't mistaken for a query. Table names are
+ * recorded only when written as plain identifiers after FROM, JOIN, INTO, or
+ * UPDATE; names built at runtime (`"FROM " + table`, `FROM %s`) are left out.
+ */
+
+import type { TreeSitterNode } from './tree-sitter';
+import type { SqlQuery } from './types';
+
+/** Longest query text kept, in characters */
+const MAX_QUERY_LENGTH = 500;
+
+const STATEMENTS: Array<{ operation: SqlQuery['operation']; pattern: RegExp }> = [
+  { operation: 'select', pattern: /^select\s+[\s\S]+?\sfrom\s/i },
+  { operation: 'insert', pattern: /^insert\s+(?:or\s+\w+\s+)?into\s/i },
+  { operation: 'update', pattern: /^update\s+\S+\s+set\s/i },
+  { operation: 'delete', pattern: /^delete\s+from\s/i },
+];
+
+/** Characters that show a lowercase statement is SQL rather than prose */
+const SQL_PUNCTUATION = /[*=?$(]/;
+
+/** A table name, optionally schema-qualified or quoted, after a keyword that introduces one */
+const TABLE_REFERENCE =
+  /\b(?:from|join|into|update)\s+([`"]?[A-Za-z_][\w.]*[`"]?)(?=\s|\(|,|;|$)/gi;
+
+/** Words that can follow FROM or JOIN without naming a table */
+const NOT_TABLES = new Set([
+  'select',
+  'lateral',
+  'unnest',
+  'only',
+  'where',
+  'set',
+  'values',
+  'nowait',
+  'skip',
+]);
+
+/**
+ * Find the SQL queries a function or method runs, in source order
+ *
+ * @param declaration - Function or method declaration
+ */
+export function extractGoSqlQueries(declaration: TreeSitterNode): SqlQuery[] {
+  const queries: SqlQuery[] = [];
+  const body = declaration.childForFieldName('body');
+  if (!body) return queries;
+
+  const visit = (node: TreeSitterNode): void => {
+    const text = literalText(node);
+    const query = text !== null ? parseSqlQuery(text, node.startPosition.row + 1) : null;
+    if (query) {
+      // A concatenation is read as a whole; its parts aren't queries on their own
+      queries.push(query);
+      return;
+    }
+    for (const child of node.namedChildren) visit(child);
+  };
+  visit(body);
+
+  return queries;
+}
+
+/**
+ * Recognize a SQL statement and the tables it names
+ *
+ * @returns The query, or null if the text doesn't look like SQL
+ */
+export function parseSqlQuery(text: string, line: number): SqlQuery | null {
+  const query = text.replace(/\s+/g, ' ').trim();
+  const statement = STATEMENTS.find(({ pattern }) => pattern.test(query));
+  if (!statement) return null;
+
+  const keyword = query.slice(0, statement.operation.length);
+  if (keyword !== keyword.toUpperCase() && !SQL_PUNCTUATION.test(query)) return null;
+
+  const tables: string[] = [];
+  for (const match of query.matchAll(TABLE_REFERENCE)) {
+    // `EXTRACT(YEAR FROM created_at)` names a column, not a table
+    if (/\(\s*\w+\s+$/.test(query.slice(0, match.index))) continue;
+    const table = match[1].replace(/[`"]/g, '');
+    if (NOT_TABLES.has(table.toLowerCase()) || tables.includes(table)) continue;
+    tables.push(table);
+  }
+
+  return {
+    operation: statement.operation,
+    query: query.length > MAX_QUERY_LENGTH ? `${query.slice(0, MAX_QUERY_LENGTH)}…` : query,
+    tables,
+    line,
+  };
+}
+
+/**
+ * Describe queries for embedding text, e.g. `select users, insert audit_log`
+ */
+export function describeSqlQueries(queries: SqlQuery[]): string {
+  const described = queries.map((query) =>
+    query.tables.length > 0 ? `${query.operation} ${query.tables.join(' ')}` : query.operation
+  );
+  return [...new Set(described)].join(', ');
+}
+
+/**
+ * Whether a query touches a table; `users` also matches a schema-qualified `public.users`
+ */
+export function queriesTable(query: SqlQuery, table: string): boolean {
+  const wanted = table.toLowerCase();
+  return query.tables.some((name) => {
+    const lower = name.toLowerCase();
+    return lower === wanted || lower.endsWith(`.${wanted}`);
+  });
+}
+
+/**
+ * Text of a string literal, or of a `+` concatenation with at least one literal
+ * (non-literal operands become `?`); null for any other node
+ */
+function literalText(node: TreeSitterNode): string | null {
+  if (node.type === 'interpreted_string_literal') {
+    return node.text.slice(1, -1).replace(/\\[nrt]/g, ' ');
+  }
+  if (node.type === 'raw_string_literal') {
+    return node.text.slice(1, -1);
+  }
+  if (node.type !== 'binary_expression' || node.childForFieldName('operator')?.text !== '+') {
+    return null;
+  }
+
+  const left = node.childForFieldName('left');
+  const right = node.childForFieldName('right');
+  if (!left || !right) return null;
+  const leftText = literalText(left);
+  const rightText = literalText(right);
+  if (leftText === null && rightText === null) return null;
+  return `${leftText ?? ' ? '}${rightText ?? ' ? '}`;
+}
@@ -0,0 +1,60 @@
+/**
+ * Stable IDs
+ * Document IDs that survive re-indexing, so external systems can bookmark a
+ * symbol and caches can key on it
+ *
+ * An ID is built from the file, kind, and qualified name, never the line, so
+ * editing code above a symbol leaves its ID alone:
+ * `internal/users/server.go:method:users.Server.CreateUser`. Go names are
+ * qualified with the package name; other languages use the name as scanned
+ * (TypeScript methods are already `Class.method`).
+ *
+ * Go has no overloading, so a qualified name is unique per file, with a few
+ * exceptions: several `init` functions, and duplicate declarations in code
+ * that doesn't compile. TypeScript overloads and repeated markdown headings
+ * repeat too. Repeats get `#2`, `#3`, ... in source order, so their IDs hold
+ * as long as they keep their order.
+ */
+
+import type { Document } from './types';
+
+/**
+ * The ID of a document before duplicates are numbered
+ */
+export function stableDocumentId(doc: Document): string {
+  return `${doc.metadata.file}:${doc.type}:${qualifiedName(doc)}`;
+}
+
+/**
+ * Give each document its stable ID, numbering repeats within a file
+ *
+ * @param documents - Scanned documents; line-based IDs are replaced
+ * @returns The same documents with stable IDs, in the same order
+ */
+export function assignStableIds(documents: Document[]): Document[] {
+  const ids = documents.map(stableDocumentId);
+
+  const byId = new Map<string, number[]>();
+  for (const [index, id] of ids.entries()) {
+    const indexes = byId.get(id);
+    if (indexes) indexes.push(index);
+    else byId.set(id, [index]);
+  }
+
+  for (const [id, indexes] of byId) {
+    if (indexes.length < 2) continue;
+    indexes.sort((a, b) => documents[a].metadata.startLine - documents[b].metadata.startLine);
+    for (const [n, index] of indexes.entries()) {
+      if (n > 0) ids[index] = `${id}#${n + 1}`;
+    }
+  }
+
+  return documents.map((doc, index) => (doc.id === ids[index] ? doc : { ...doc, id: ids[index] }));
+}
+
+function qualifiedName(doc: Document): string {
+  const { name = '', packageName, asserts } = doc.metadata;
+  // Blank assertions (`var _ Store = (*memStore)(nil)`) are told apart by what they assert
+  const local = asserts ? `_(${asserts.type} implements ${asserts.interface})` : name;
+  return packageName ? `${packageName}.${local}` : local;
+}
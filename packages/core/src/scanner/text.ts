@@ -0,0 +1,309 @@
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+import type { Logger } from '@lytics/kero';
+import type { Document, ScanError, Scanner, ScannerCapabilities } from './types';
+
+/** Lines per document when chunking a text file */
+const CHUNK_LINES = 40;
+
+/** Files larger than this are skipped rather than chunked */
+const MAX_TEXT_FILE_BYTES = 1024 * 1024;
+
+/** Bytes inspected when deciding whether a file is text */
+export const TEXT_SNIFF_BYTES = 512;
+
+/**
+ * Whether the start of a file looks like binary content
+ */
+export function looksBinary(head: string): boolean {
+  return head.includes('\u0000');
+}
+
+/** Extensions indexed as text or config by default scans */
+const TEXT_EXTENSIONS = ['.txt', '.yaml', '.yml', '.json'];
+
+const LANGUAGE_BY_EXTENSION: Record<string, string> = {
+  '.yaml': 'yaml',
+  '.yml': 'yaml',
+  '.json': 'json',
+};
+
+/** Top-level YAML mapping key, e.g. `services:` or `"on":` */
+const YAML_TOP_LEVEL_KEY = /^(["']?)([^\s#'"-][^:#]*?)\1\s*:(\s|$)/;
+
+/**
+ * A run of lines, titled by the key that starts it
+ */
+interface Section {
+  title?: string;
+  /** First line (0-based) */
+  start: number;
+  /** Line after the last (0-based, exclusive) */
+  end: number;
+}
+
+/**
+ * A document-sized group of consecutive sections
+ */
+interface Chunk {
+  titles: string[];
+  start: number;
+  end: number;
+}
+
+/**
+ * Generic text and config scanner
+ *
+ * Indexes plain text, YAML, and JSON without symbol structure. YAML and JSON
+ * are split at top-level keys and plain text at paragraphs; small sections are
+ * packed together and long ones split, so each document stays around
+ * CHUNK_LINES lines. Section keys become document titles.
+ *
+ * Also the registry's fallback for files no language scanner handles.
+ * Binary and very large files are skipped.
+ */
+export class TextScanner implements Scanner {
+  readonly language = 'text';
+  readonly capabilities: ScannerCapabilities = {
+    syntax: false,
+    documentation: true,
+  };
+  readonly extensions = TEXT_EXTENSIONS;
+
+  /**
+   * Handles text and config extensions, and any other file whose content looks like text
+   */
+  canHandle(filePath: string, content?: string): boolean {
+    if (TEXT_EXTENSIONS.includes(path.extname(filePath).toLowerCase())) {
+      return true;
+    }
+    return content !== undefined && !looksBinary(content);
+  }
+
+  async scan(
+    files: string[],
+    repoRoot: string,
+    logger?: Logger,
+    onProgress?: (filesProcessed: number, totalFiles: number) => void,
+    onError?: (error: ScanError) => void,
+    signal?: AbortSignal
+  ): Promise<Document[]> {
+    const documents: Document[] = [];
+
+    for (const [i, file] of files.entries()) {
+      signal?.throwIfAborted();
+      try {
+        const absolutePath = path.join(repoRoot, file);
+        const stat = await fs.stat(absolutePath);
+        if (stat.size > MAX_TEXT_FILE_BYTES) {
+          logger?.debug({ file, bytes: stat.size }, 'Skipping large text file');
+          continue;
+        }
+
+        const content = await fs.readFile(absolutePath, 'utf-8');
+        if (!looksBinary(content.slice(0, TEXT_SNIFF_BYTES))) {
+          documents.push(...this.extractFromText(content, file));
+        }
+      } catch (error) {
+        onError?.({
+          file,
+          error: error instanceof Error ? error.message : String(error),
+          phase: 'extractFromFile',
+        });
+      }
+      onProgress?.(i + 1, files.length);
+    }
+
+    return documents;
+  }
+
+  private extractFromText(content: string, file: string): Document[] {
+    const lines = content.split('\n');
+    const language = LANGUAGE_BY_EXTENSION[path.extname(file).toLowerCase()] ?? this.language;
+    const sections =
+      language === 'yaml'
+        ? yamlSections(lines)
+        : language === 'json'
+          ? jsonSections(lines)
+          : paragraphSections(lines);
+
+    const basename = path.basename(file);
+    const documents: Document[] = [];
+
+    for (const chunk of packSections(sections, lines.length)) {
+      const text = lines.slice(chunk.start, chunk.end).join('\n');
+      if (!text.trim()) continue;
+
+      const startLine = chunk.start + 1;
+      const endLine = chunk.end;
+      const name = chunkName(chunk, basename, startLine, endLine, lines.length);
+      const title = name === basename ? basename : `${basename}: ${name}`;
+      documents.push({
+        id: `${file}:${name}:${startLine}`,
+        text: `${title}\n\n${text}`,
+        type: 'documentation',
+        language,
+        metadata: {
+          file,
+          startLine,
+          endLine,
+          name,
+          exported: true,
+          snippet: text,
+        },
+      });
+    }
+
+    return documents;
+  }
+}
+
+/**
+ * Split YAML at top-level keys and `---` document markers
+ *
+ * Comments directly above a key belong to that key's section.
+ */
+function yamlSections(lines: string[]): Section[] {
+  const starts: Array<{ line: number; title?: string }> = [];
+  for (const [i, line] of lines.entries()) {
+    const key = YAML_TOP_LEVEL_KEY.exec(line);
+    if (key) {
+      let start = i;
+      while (start > 0 && lines[start - 1].startsWith('#')) start--;
+      starts.push({ line: start, title: key[2].trim() });
+    } else if (line.startsWith('---')) {
+      starts.push({ line: i });
+    }
+  }
+  return sectionsFromStarts(starts, lines.length);
+}
+
+/**
+ * Split pretty-printed JSON at the keys of the top-level object
+ *
+ * Minified or non-object JSON stays one section.
+ */
+function jsonSections(lines: string[]): Section[] {
+  const starts: Array<{ line: number; title?: string }> = [];
+  let depth = 0;
+  let inString = false;
+
+  for (const [i, line] of lines.entries()) {
+    const key = depth === 1 && !inString ? /^\s*"((?:[^"\\]|\\.)*)"\s*:/.exec(line) : null;
+    if (key) {
+      starts.push({ line: i, title: key[1] });
+    }
+
+    for (let j = 0; j < line.length; j++) {
+      const ch = line[j];
+      if (inString) {
+        if (ch === '\\') j++;
+        else if (ch === '"') inString = false;
+      } else if (ch === '"') {
+        inString = true;
+      } else if (ch === '{' || ch === '[') {
+        depth++;
+      } else if (ch === '}' || ch === ']') {
+        depth--;
+      }
+    }
+  }
+  return sectionsFromStarts(starts, lines.length);
+}
+
+/**
+ * Split plain text at blank lines
+ */
+function paragraphSections(lines: string[]): Section[] {
+  const sections: Section[] = [];
+  let start = 0;
+  for (const [i, line] of lines.entries()) {
+    if (!line.trim() && i > start) {
+      sections.push({ start, end: i + 1 });
+      start = i + 1;
+    }
+  }
+  if (start < lines.length) {
+    sections.push({ start, end: lines.length });
+  }
+  return sections;
+}
+
+/**
+ * Turn section start lines into contiguous sections covering the whole file
+ */
+function sectionsFromStarts(
+  starts: Array<{ line: number; title?: string }>,
+  lineCount: number
+): Section[] {
+  const sections: Section[] = [];
+  let previous = 0;
+  let title: string | undefined;
+  for (const start of starts) {
+    if (start.line > previous) {
+      sections.push({ title, start: previous, end: start.line });
+    }
+    if (start.line >= previous) {
+      previous = start.line;
+      title = start.title;
+    }
+  }
+  if (previous < lineCount) {
+    sections.push({ title, start: previous, end: lineCount });
+  }
+  return sections;
+}
+
+/**
+ * Pack consecutive sections into chunks of at most CHUNK_LINES lines,
+ * splitting any section longer than that into windows
+ */
+function packSections(sections: Section[], lineCount: number): Chunk[] {
+  const chunks: Chunk[] = [];
+  let current: Chunk | null = null;
+
+  for (const section of sections) {
+    const length = section.end - section.start;
+    if (current && section.end - current.start <= CHUNK_LINES) {
+      current.end = section.end;
+      if (section.title) current.titles.push(section.title);
+      continue;
+    }
+
+    if (current) chunks.push(current);
+    current = null;
+
+    if (length <= CHUNK_LINES) {
+      current = {
+        titles: section.title ? [section.title] : [],
+        start: section.start,
+        end: section.end,
+      };
+      continue;
+    }
+    for (let start = section.start; start < section.end; start += CHUNK_LINES) {
+      chunks.push({
+        titles: section.title ? [section.title] : [],
+        start,
+        end: Math.min(section.end, start + CHUNK_LINES),
+      });
+    }
+  }
+
+  if (current) chunks.push(current);
+  return chunks.length > 0 ? chunks : [{ titles: [], start: 0, end: lineCount }];
+}
+
+function chunkName(
+  chunk: Chunk,
+  basename: string,
+  startLine: number,
+  endLine: number,
+  lineCount: number
+): string {
+  if (chunk.titles.length > 0) {
+    const shown = chunk.titles.slice(0, 3).join(', ');
+    return chunk.titles.length > 3 ? `${shown}, ...` : shown;
+  }
+  return startLine === 1 && endLine >= lineCount ? basename : `${basename}:${startLine}-${endLine}`;
+}
@@ -257,6 +257,10 @@ export interface TreeSitterNode {
   namedChildren: TreeSitterNode[];
   childForFieldName(name: string): TreeSitterNode | null;
   parent: TreeSitterNode | null;
+  /** True if this node or any descendant is a syntax error */
+  hasError: boolean;
+  /** True if the parser inserted this node to recover from an error */
+  isMissing: boolean;
 }
 
 /**
@@ -335,6 +339,42 @@ export async function parseCode(
   }
 }
 
+/**
+ * Location and description of a syntax error
+ */
+export interface SyntaxErrorInfo {
+  message: string;
+  line: number; // 1-based
+}
+
+/**
+ * Find the first syntax error in a tree
+ *
+ * Tree-sitter recovers from errors by inserting ERROR and missing nodes,
+ * so a broken file still parses; this reports where the first one is.
+ *
+ * @returns The first error, or null if the tree parsed cleanly
+ */
+export function findSyntaxError(node: TreeSitterNode): SyntaxErrorInfo | null {
+  if (!node.hasError && !node.isMissing) {
+    return null;
+  }
+
+  const line = node.startPosition.row + 1;
+  if (node.isMissing) {
+    return { message: `Syntax error: missing ${node.type} at line ${line}`, line };
+  }
+  if (node.type === 'ERROR') {
+    return { message: `Syntax error at line ${line}`, line };
+  }
+
+  for (const child of node.children) {
+    const error = findSyntaxError(child);
+    if (error) return error;
+  }
+  return { message: `Syntax error at line ${line}`, line };
+}
+
 /**
  * Helper to get text from source by line numbers (1-based)
  */
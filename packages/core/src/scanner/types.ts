@@ -34,10 +34,300 @@ export interface CalleeInfo {
   file?: string;
   /** Line number of the call within this component */
   line: number;
+  /**
+   * Go: type the receiver was narrowed to by a type switch case or type
+   * assertion (`MyReader` for `r.Read` after `r, ok := x.(*MyReader)`)
+   */
+  receiverType?: string;
+  /** Go: the narrowing only holds on a checked branch (comma-ok assertion or switch case) */
+  guarded?: boolean;
+}
+
+/**
+ * How a call can terminate the process: `panic` (including log.Panic*),
+ * `fatal` (log.Fatal*), or `exit` (os.Exit)
+ */
+export type CrashKind = 'panic' | 'fatal' | 'exit';
+
+/**
+ * Code a crash call sits in. Crashing in `init`, a `main` package, or a test
+ * is often intended; in `library` code it takes down every caller's process.
+ */
+export type CrashContext = 'library' | 'init' | 'main' | 'test';
+
+/**
+ * A call that can hard-crash the process
+ */
+export interface CrashSite {
+  kind: CrashKind;
+  /** Called function as written (e.g. `panic`, `log.Fatalf`) */
+  call: string;
+  /** Line number of the call */
+  line: number;
+}
+
+/**
+ * How sure constructor detection is: `high` when the name is `New` or
+ * `New<Type>...`, `medium` for other `New*` names, `low` when only the
+ * return type suggests it
+ */
+export type ConstructorConfidence = 'high' | 'medium' | 'low';
+
+/**
+ * A struct field, in declaration order
+ */
+export interface StructField {
+  /** Field name; embedded fields are named after their type, as in Go */
+  name: string;
+  /** Declared type as written (e.g. `*Config`, `io.Reader`) */
+  type: string;
+  /** Is the field visible outside its package? */
+  exported: boolean;
+  /** True for embedded fields */
+  embedded: boolean;
+  /** Struct tag without its quotes (e.g. `json:"id,omitempty"`), when tagged */
+  tag?: string;
+}
+
+/**
+ * A range-over-func iterator a function returns (Go 1.23): `iter.Seq[V]`,
+ * `iter.Seq2[K, V]`, or the equivalent `func(yield func(...) bool)`
+ */
+export interface GoIterator {
+  /** `Seq` yields one value per iteration, `Seq2` two */
+  kind: 'Seq' | 'Seq2';
+  /** Yielded types as written: `[V]` or `[K, V]` */
+  elementTypes: string[];
+}
+
+/**
+ * A runnable Go example (`func ExampleT_Method()` in a _test.go file)
+ *
+ * Targets follow the `go test` naming convention: `Example` and
+ * `Example_suffix` document the package, `ExampleF` a function or type,
+ * and `ExampleT_M` a method. Suffixes start with a lower-case letter.
+ */
+export interface GoExample {
+  /** Documented symbol (`NewServer`, `Server.Handle`); absent for package examples */
+  target?: string;
+  /** Lower-case suffix distinguishing several examples of one target */
+  suffix?: string;
+  /** Example body without the output comment */
+  code: string;
+  /** Expected output from the `// Output:` comment; absent when the example isn't run */
+  output?: string;
+  /** True for `// Unordered output:`, where lines may print in any order */
+  unordered?: boolean;
+}
+
+/**
+ * A `defer` statement in a Go function or method
+ */
+export interface DeferredCall {
+  /** Deferred call as written, whitespace collapsed (`c.Close()`, `mu.Unlock()`) */
+  call: string;
+  /** Line number of the defer statement */
+  line: number;
+  /** For a deferred func literal (`call` is `func() {...}()`), the calls made inside it */
+  calls?: string[];
+}
+
+/**
+ * An anonymous function literal in a Go function or method, indexed as a
+ * child symbol named the way the Go toolchain names it: `Serve.func1` for the
+ * first literal in Serve, `Serve.func1.1` for a literal nested in that one
+ */
+export interface GoFuncLiteral {
+  /** Function, method, or literal it is written in (`Server.Start`, `Serve.func1`) */
+  enclosing: string;
+  /** Variables of enclosing scopes it refers to, in order of first use */
+  captures: string[];
+  /**
+   * How it is used: `go` (started as a goroutine), `defer`, `call` (invoked
+   * in place), `argument` (passed to another function), or `value` (assigned,
+   * returned, or stored)
+   */
+  usage: 'go' | 'defer' | 'call' | 'argument' | 'value';
+  /** For `argument`, the called function as written (`slices.SortFunc`, `g.Go`) */
+  passedTo?: string;
+}
+
+/**
+ * An error a Go function or method can return, traced from its return
+ * statements (see README, "Go Scanner Features")
+ */
+export interface ReturnedError {
+  /**
+   * - `sentinel`: a package-level error value (`ErrNotFound`, `io.EOF`)
+   * - `type`: an error type constructed in place (`*ValidationError`)
+   * - `call`: an error passed on from a call that couldn't be resolved further
+   */
+  kind: 'sentinel' | 'type' | 'call';
+  /** The sentinel or type name, or for `call`, the callee (`os.Open`) */
+  error: string;
+  /** Wrapped (`fmt.Errorf` with `%w`, `errors.Join`) rather than returned as is */
+  wrapped: boolean;
+  /** Function in the same file whose returns this error came through */
+  via?: string;
+  /** Line of the first return statement producing it */
+  line: number;
+}
+
+/**
+ * An HTTP route registered in a Go function (see http-routes.ts)
+ */
+export interface HttpRoute {
+  /** HTTP method (`GET`); absent when the route matches any method */
+  method?: string;
+  /** Path pattern with router group prefixes applied (`/api/users/{id}`) */
+  path: string;
+  /** Handler as written (`h.CreateUser`, `listUsers`); absent for an inline func literal */
+  handler?: string;
+  /** Router the registration was recognized as (`net/http`, `chi`, `gin`, `echo`) */
+  framework: string;
+  /** Line of the registration */
+  line: number;
+}
+
+/**
+ * An operation declared in an OpenAPI or Swagger spec (see openapi.ts)
+ */
+export interface OpenApiOperation {
+  /** HTTP method (`POST`) */
+  method: string;
+  /** Path template as written in the spec (`/users/{id}`) */
+  path: string;
+  /** Path of the first server URL, or Swagger's `basePath` (`/api/v1`) */
+  basePath?: string;
+  /** The operation's `operationId` (`createUser`) */
+  operationId?: string;
+  /** One-line summary */
+  summary?: string;
+  /** Tags grouping the operation */
+  tags?: string[];
+}
+
+/**
+ * A SQL statement written as a string literal in a Go function (see sql-queries.ts)
+ */
+export interface SqlQuery {
+  /** Statement kind */
+  operation: 'select' | 'insert' | 'update' | 'delete';
+  /** Query text with whitespace collapsed (truncated when long) */
+  query: string;
+  /** Tables named after FROM, JOIN, INTO, or UPDATE, when written as plain identifiers */
+  tables: string[];
+  /** Line of the string literal */
+  line: number;
+}
+
+/**
+ * A lint-style finding about how a Go function threads context.Context (see go-context.ts)
+ */
+export interface ContextFinding {
+  /** `ignored` ctx parameter, ctx `not-propagated` to a call, or ctx `missing` entirely */
+  kind: 'ignored' | 'not-propagated' | 'missing';
+  /** Line of the call, or of the declaration for an ignored ctx */
+  line: number;
+  /** Callee as written (`db.Query`); absent for an ignored ctx */
+  call?: string;
+  /** What to change */
+  suggestion: string;
+}
+
+/**
+ * A `go build` or `go vet` diagnostic on a symbol's lines (see indexer/utils/go-diagnostics.ts)
+ */
+export interface GoDiagnostic {
+  /** Command that reported it */
+  tool: 'build' | 'vet';
+  /** Line in the file */
+  line: number;
+  /** Column, when reported */
+  column?: number;
+  /** Message as printed (e.g. `fmt.Sprintf format %d has arg name of wrong type string`) */
+  message: string;
+}
+
+/**
+ * External module a Go file imports, at the version its go.mod puts in effect
+ * (see go-modules.ts)
+ */
+export interface GoDependencyUse {
+  /** Module path (e.g. golang.org/x/net) */
+  module: string;
+  /** Effective version, after replace directives */
+  version: string;
+  /** Required only indirectly by the owning module */
+  indirect?: boolean;
+  /** Replaced by a replace directive; `version` is the replacement's when it has one */
+  replaced?: boolean;
+}
+
+/**
+ * Platform a Go build is for; build constraints are evaluated against it (see go-build.ts)
+ */
+export interface GoPlatform {
+  /** Target operating system (`linux`, `darwin`, `windows`, ...) */
+  goos: string;
+  /** Target architecture (`amd64`, `arm64`, ...) */
+  goarch: string;
+  /** Extra build tags to treat as set (`-tags`) */
+  tags?: string[];
+}
+
+/**
+ * A platform variant of a Go symbol left out of the index in favor of the
+ * variant built for the target platform
+ */
+export interface PlatformVariant {
+  /** Build constraint of the variant's file (e.g. `windows`) */
+  buildConstraint: string;
+  /** File defining the variant */
+  file: string;
+  /** Line of its declaration */
+  startLine: number;
+}
+
+/**
+ * How a document over the maximum document size was cut down (see document-size.ts)
+ */
+export interface DocumentOverflow {
+  /** `chunked` into parts (documentation) or `summarized` in place (code symbols) */
+  policy: 'chunked' | 'summarized';
+  /** Size of the original document in bytes */
+  originalBytes: number;
+  /** 1-based index of this part, when chunked */
+  part?: number;
+  /** Number of parts the original was split into, when chunked */
+  parts?: number;
+}
+
+/**
+ * A compile-time interface assertion, e.g. `var _ io.Reader = (*File)(nil)`
+ */
+export interface InterfaceAssertion {
+  /** Interface as written (e.g. `io.Reader`, `Store`) */
+  interface: string;
+  /** Asserted type, without pointer marker or type arguments */
+  type: string;
+  /** True when the assertion is through a pointer (`(*T)(nil)`, `&T{}`, `new(T)`) */
+  pointer: boolean;
+}
+
+/**
+ * Type set of a Go constraint interface: the interface has type elements
+ * (`~int | ~int64`) or embeds `comparable`, so it can only constrain type parameters
+ */
+export interface GoTypeSet {
+  /** Union terms as written, `~` kept (e.g. `~int`, `~string`, `Integer`) */
+  terms: string[];
+  /** True when the interface embeds `comparable` */
+  comparable: boolean;
 }
 
 export interface Document {
-  id: string; // Unique identifier: file:name:line
+  id: string; // Unique identifier; file:kind:qualified name once scanned (see stable-ids.ts)
   text: string; // Text to embed (for vector search)
   type: DocumentType; // Type of code element
   language: string; // typescript, go, python, rust, markdown
@@ -55,9 +345,45 @@ export interface DocumentMetadata {
   docstring?: string; // Documentation comment
   snippet?: string; // Actual code content (truncated if large)
   imports?: string[]; // File-level imports (module specifiers)
+  module?: string; // Owning module (Go: module path from the nearest go.mod)
+  packageName?: string; // Go: name in the package clause
+  packageDoc?: string; // Go: first paragraph of the package doc comment, when in the same scan
+  goVersion?: string; // Go: language version from that go.mod's go directive (e.g. "1.22.3")
+  dependencies?: GoDependencyUse[]; // Go: external modules the file imports, with versions
+  parseError?: string; // Set when the file had syntax errors and was only partially extracted
+  overflow?: DocumentOverflow; // Set when the document exceeded the maximum document size
+  usesCgo?: boolean; // Go: the file imports "C" (cgo)
+  crashes?: CrashSite[]; // Go: calls to panic, log.Fatal*, log.Panic*, or os.Exit
+  crashContext?: CrashContext; // Go: where those calls sit (set with crashes)
+  recovers?: boolean; // Go: calls recover(), usually in a deferred func
+  defers?: DeferredCall[]; // Go: calls deferred by the function's own defer statements
+  errorsReturned?: ReturnedError[]; // Go: sentinel, typed, and wrapped errors it can return
+  constructs?: string; // Go: package type this function returns first (T, *T, or T[...])
+  constructorConfidence?: ConstructorConfidence; // Go: set with constructs
+  iterator?: GoIterator; // Go: the range-over-func iterator this function or method returns
+  example?: GoExample; // Go: runnable example this Example function is
+  lastModified?: string; // Last commit touching the symbol's lines (ISO; when blame is enabled)
+  lastAuthor?: string; // Author of that commit
+  diagnostics?: GoDiagnostic[]; // Go: build errors and vet warnings on its lines (when enabled)
+  asserts?: InterfaceAssertion; // Go: `var _ I = T` assertion this blank variable makes
+  routes?: HttpRoute[]; // Go: HTTP routes this function or method registers
+  sqlQueries?: SqlQuery[]; // Go: SQL statements in its string literals, with the tables named
+  funcLiteral?: GoFuncLiteral; // Go: where this anonymous function sits and what it captures
+  openApiOperation?: OpenApiOperation; // OpenAPI: the spec operation this document describes
+  typeSet?: GoTypeSet; // Go interfaces: the type set, when the interface is a constraint
+  typeConstraints?: string[]; // Go: named constraints in its type parameters (not any/comparable)
+  contextFindings?: ContextFinding[]; // Go: ctx parameters ignored, not passed on, or missing
+  buildConstraint?: string; // Go: platforms the file is built for (`//go:build` and name suffix)
+  platformVariants?: PlatformVariant[]; // Go: same symbol for other platforms, not indexed
+  generated?: boolean; // Go: file has a "Code generated ... DO NOT EDIT." header
+  generator?: string; // Go: generator named by that header (e.g. `MockGen`)
+  boilerplateDoc?: boolean; // Doc comment repeated across many symbols; not embedded
+  enrichment?: Record<string, string | number | boolean>; // Custom fields from indexer enrichers
+  embeddingProfile?: string; // Embedding profile it was embedded with (`name@fingerprint`)
 
   // Relationship data (call graph)
   callees?: CalleeInfo[]; // Functions/methods this component calls
+  referencesTypes?: string[]; // Go: types in its signature and body (`Config`, `http.Request`)
   // Note: callers are computed at query time via reverse lookup
 
   // Variable/function metadata
@@ -66,6 +392,12 @@ export interface DocumentMetadata {
   isAsync?: boolean; // True if async function/arrow function
   isConstant?: boolean; // True if exported constant (object/array/call expression)
   constantKind?: 'object' | 'array' | 'value'; // Kind of constant initializer
+  constantType?: string; // Go: declared type, or the untyped kind (e.g. "untyped int", "untyped")
+  constantValue?: string; // Go: literal value as written (iota resolved), when trivially evaluable
+  constantExpression?: string; // Go: initializer text when the value isn't a plain literal
+
+  // Code quality signals
+  complexity?: number; // Cyclomatic complexity for functions/methods (see complexity.ts)
 
   // Extensible for future use
   custom?: Record<string, unknown>;
@@ -81,6 +413,8 @@ export interface ScannerCapabilities {
 export interface Scanner {
   readonly language: string;
   readonly capabilities: ScannerCapabilities;
+  /** File extensions to discover for this scanner (default: built-in list for its language) */
+  readonly extensions?: readonly string[];
 
   /**
    * Scan files and extract documents
@@ -88,18 +422,24 @@ export interface Scanner {
    * @param repoRoot - Repository root path
    * @param logger - Optional logger for progress output
    * @param onProgress - Optional callback for progress updates
+   * @param onError - Optional callback for files that could not be parsed
+   * @param signal - Optional signal to stop scanning; the scan rejects with an AbortError
    */
   scan(
     files: string[],
     repoRoot: string,
     logger?: Logger,
-    onProgress?: (filesProcessed: number, totalFiles: number) => void
+    onProgress?: (filesProcessed: number, totalFiles: number) => void,
+    onError?: (error: ScanError) => void,
+    signal?: AbortSignal
   ): Promise<Document[]>;
 
   /**
    * Check if this scanner can handle a file
+   * @param filePath - File path (relative)
+   * @param content - Start of the file, passed only when no scanner claimed it by path
    */
-  canHandle(filePath: string): boolean;
+  canHandle(filePath: string, content?: string): boolean;
 }
 
 export interface ScanResult {
@@ -112,12 +452,32 @@ export interface ScanStats {
   documentsExtracted: number;
   duration: number; // milliseconds
   errors: ScanError[];
+  /** Documents extracted, by kind */
+  byKind?: Partial<Record<DocumentType, number>>;
+  /** Per-language breakdown, keyed by scanner language */
+  byLanguage?: Record<string, LanguageScanStats>;
+}
+
+/**
+ * Scan statistics for a single language
+ *
+ * A language with files but no documents usually means the scanner is broken.
+ */
+export interface LanguageScanStats {
+  files: number;
+  documents: number;
+  byKind: Partial<Record<DocumentType, number>>;
+  /** Files the scanner could not parse */
+  parseFailures: number;
+  duration: number; // milliseconds
 }
 
 export interface ScanError {
   file: string;
   error: string;
   line?: number;
+  /** Scanner phase that failed (e.g. fileValidation, extractFromFile) */
+  phase?: string;
 }
 
 /**
@@ -138,6 +498,10 @@ export interface ScanProgress {
   currentFile?: string;
   /** Number of errors encountered */
   errors: number;
+  /** Share of files scanned (0-100) */
+  percentComplete: number;
+  /** Seconds left at the recent scan rate; absent until there is a rate to go by */
+  etaSeconds?: number;
 }
 
 export interface ScanOptions {
@@ -145,8 +509,21 @@ export interface ScanOptions {
   exclude?: string[]; // Glob patterns to exclude (default: see getDefaultExclusions() - deps, build, cache, IDE, etc.)
   include?: string[]; // Glob patterns to include (default: all supported extensions)
   languages?: string[]; // Limit to specific languages (default: all registered scanners)
+  /** Index-only ignore patterns, layered over exclusions and .devagentignore (`!` negates) */
+  ignore?: string[];
+  /** Skip files ignored by .gitignore (default: true) */
+  respectGitignore?: boolean;
   /** Logger instance for progress and debug output */
   logger?: Logger;
-  /** Callback for progress updates during scanning */
+  /** Callback for progress updates during scanning, throttled (see progressIntervalMs) */
   onProgress?: (progress: ScanProgress) => void;
+  /** Minimum time between progress updates; phase changes always go out (default: 250ms) */
+  progressIntervalMs?: number;
+  /** Cancels the scan between files; the scan rejects with the signal's reason */
+  signal?: AbortSignal;
+  /**
+   * Larger documents are chunked or summarized (default: DEFAULT_MAX_DOCUMENT_BYTES, 64 KiB);
+   * a function sets the limit per file
+   */
+  maxDocumentBytes?: number | ((file: string) => number);
 }
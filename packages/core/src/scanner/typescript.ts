@@ -17,7 +17,8 @@ import {
   type VariableStatement,
 } from 'ts-morph';
 import { getCurrentSystemResources, getOptimalConcurrency } from '../utils/concurrency';
-import type { CalleeInfo, Document, Scanner, ScannerCapabilities } from './types';
+import { computeTypeScriptComplexity } from './complexity';
+import type { CalleeInfo, Document, ScanError, Scanner, ScannerCapabilities } from './types';
 
 /**
  * Enhanced TypeScript scanner using ts-morph
@@ -74,7 +75,9 @@ export class TypeScriptScanner implements Scanner {
     files: string[],
     repoRoot: string,
     logger?: Logger,
-    onProgress?: (filesProcessed: number, totalFiles: number) => void
+    onProgress?: (filesProcessed: number, totalFiles: number) => void,
+    onError?: (error: ScanError) => void,
+    signal?: AbortSignal
   ): Promise<Document[]> {
     // Initialize project with lenient type checking enabled
     // - Allows cross-file symbol resolution for better callee extraction
@@ -117,6 +120,7 @@ export class TypeScriptScanner implements Scanner {
     // Step 1: Add all files to project sequentially (required for ts-morph state management)
     const sourceFiles = new Map<string, SourceFile>();
     for (const file of files) {
+      signal?.throwIfAborted();
       const absolutePath = path.join(repoRoot, file);
       try {
         const sourceFile = this.project.addSourceFileAtPath(absolutePath);
@@ -170,6 +174,7 @@ export class TypeScriptScanner implements Scanner {
 
     // Process batches sequentially, files within batch in parallel
     for (let batchIndex = 0; batchIndex < batches.length; batchIndex++) {
+      signal?.throwIfAborted();
       const batch = batches[batchIndex];
       const batchStartTime = Date.now();
       const results = await Promise.all(
@@ -191,6 +196,11 @@ export class TypeScriptScanner implements Scanner {
         if (result.error) {
           errors.push(result.error);
           failureCount++;
+          onError?.({
+            file: result.error.file,
+            error: result.error.error,
+            phase: result.error.phase,
+          });
 
           // Log first 10 errors at INFO level, rest at DEBUG
           if (errors.length <= 10) {
@@ -219,13 +229,8 @@ export class TypeScriptScanner implements Scanner {
       const now = Date.now();
       const timeSinceLastLog = now - lastLogTime;
 
-      // Report progress via callback: every 2 batches OR every 10 seconds OR last batch
-      if (
-        onProgress &&
-        (batchIndex % 2 === 0 || timeSinceLastLog > 10000 || batchIndex === batches.length - 1)
-      ) {
-        onProgress(processedCount, total);
-      }
+      // Report progress after every batch; the registry throttles what reaches listeners
+      onProgress?.(processedCount, total);
 
       // Log progress: every 2 batches OR every 10 seconds OR last batch
       if (
@@ -274,6 +279,11 @@ export class TypeScriptScanner implements Scanner {
             error: 'Failed to add file to project',
             phase: 'addSourceFileAtPath',
           });
+          onError?.({
+            file,
+            error: 'Failed to add file to project',
+            phase: 'addSourceFileAtPath',
+          });
         }
       }
     }
@@ -509,6 +519,7 @@ export class TypeScriptScanner implements Scanner {
         snippet,
         imports,
         callees: callees.length > 0 ? callees : undefined,
+        complexity: computeTypeScriptComplexity(fn),
       },
     };
   }
@@ -604,6 +615,7 @@ export class TypeScriptScanner implements Scanner {
         snippet,
         imports,
         callees: callees.length > 0 ? callees : undefined,
+        complexity: computeTypeScriptComplexity(method),
       },
     };
   }
@@ -769,6 +781,7 @@ export class TypeScriptScanner implements Scanner {
         snippet,
         imports,
         callees: callees.length > 0 ? callees : undefined,
+        complexity: computeTypeScriptComplexity(funcNode),
         isArrowFunction,
         isHook,
         isAsync,
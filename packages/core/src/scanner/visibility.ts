@@ -0,0 +1,27 @@
+/**
+ * Symbol Visibility
+ *
+ * Go visibility rules, shared by the scanner and anything that needs to
+ * reason about the public API without re-implementing them.
+ *
+ * An identifier is exported if its first character is a Unicode upper case
+ * letter (category Lu). A method is only reachable from outside the package
+ * when both the method and its receiver type are exported.
+ */
+
+/**
+ * Check if a Go identifier is exported
+ */
+export function isGoExported(name: string): boolean {
+  return /^\p{Lu}/u.test(name);
+}
+
+/**
+ * Check if a Go method is part of the package's public API
+ *
+ * @param receiverType - Receiver type name without pointer or type parameters
+ * @param methodName - Method name
+ */
+export function isGoMethodExported(receiverType: string, methodName: string): boolean {
+  return isGoExported(receiverType) && isGoExported(methodName);
+}
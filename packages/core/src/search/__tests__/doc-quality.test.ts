@@ -0,0 +1,55 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { docQuality, rankByDocQuality } from '../doc-quality';
+
+function result(id: string, score: number, docstring?: string, exported = true): SearchResult {
+  return { id, score, metadata: { name: id, type: 'class', exported, docstring } };
+}
+
+const rich =
+  'Server handles incoming requests and manages connections. It is safe for concurrent use ' +
+  'and shuts down gracefully when its context is cancelled, draining active streams.';
+
+describe('docQuality', () => {
+  it('should grow with doc comment length', () => {
+    expect(docQuality({})).toBe(0);
+    expect(docQuality({ docstring: '  ' })).toBe(0);
+    expect(docQuality({ docstring: 'Server serves.' })).toBeCloseTo(2 / 24);
+    expect(docQuality({ docstring: rich })).toBe(1);
+  });
+
+  it('should halve the quality of unexported symbols', () => {
+    expect(docQuality({ docstring: rich, exported: false })).toBe(0.5);
+  });
+
+  it('should not count boilerplate doc comments', () => {
+    expect(docQuality({ docstring: rich, boilerplateDoc: true })).toBe(0);
+  });
+});
+
+describe('rankByDocQuality', () => {
+  it('should break near ties toward documented public API', () => {
+    const ranked = rankByDocQuality([
+      result('conn', 0.81),
+      result('serverImpl', 0.805, rich, false),
+      result('Server', 0.8, rich),
+    ]);
+
+    expect(ranked.map((r) => r.id)).toEqual(['Server', 'serverImpl', 'conn']);
+    expect(ranked[0].score).toBe(0.8);
+    expect(ranked[0].metadata.docBoost).toBe(0.02);
+    expect(ranked[2].metadata.docBoost).toBeUndefined();
+  });
+
+  it('should not override a strong semantic match', () => {
+    const ranked = rankByDocQuality([result('retry', 0.9), result('Server', 0.7, rich)]);
+
+    expect(ranked.map((r) => r.id)).toEqual(['retry', 'Server']);
+  });
+
+  it('should keep the order when disabled', () => {
+    const results = [result('conn', 0.81), result('Server', 0.8, rich)];
+
+    expect(rankByDocQuality(results, 0)).toBe(results);
+  });
+});
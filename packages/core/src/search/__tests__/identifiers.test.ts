@@ -0,0 +1,109 @@
+import { describe, expect, it } from 'vitest';
+import {
+  annotateIdentifiers,
+  containsIdentifier,
+  identifierTokens,
+  identifierWords,
+  matchesIdentifiers,
+  splitIdentifier,
+  symbolIdentifiers,
+  tokenize,
+} from '../identifiers';
+
+describe('splitIdentifier', () => {
+  it('should split camelCase and PascalCase', () => {
+    expect(splitIdentifier('MarkFailAndGetWait')).toEqual(['mark', 'fail', 'and', 'get', 'wait']);
+    expect(splitIdentifier('parseConfig')).toEqual(['parse', 'config']);
+  });
+
+  it('should split snake_case, kebab-case, and qualified names', () => {
+    expect(splitIdentifier('parse_http_request')).toEqual(['parse', 'http', 'request']);
+    expect(splitIdentifier('retry-with-backoff')).toEqual(['retry', 'with', 'backoff']);
+    expect(splitIdentifier('Client.Do')).toEqual(['client', 'do']);
+  });
+
+  it('should keep acronyms together', () => {
+    expect(splitIdentifier('HTTPServer')).toEqual(['http', 'server']);
+    expect(splitIdentifier('parseJSON')).toEqual(['parse', 'json']);
+  });
+});
+
+describe('identifierWords', () => {
+  it('should join the words of a compound identifier', () => {
+    expect(identifierWords('getUserConfig')).toBe('get user config');
+  });
+
+  it('should skip single words and prose', () => {
+    expect(identifierWords('Retry')).toBeUndefined();
+    expect(identifierWords('Getting Started')).toBeUndefined();
+  });
+});
+
+describe('identifierTokens', () => {
+  it('should keep the whole identifier alongside its words', () => {
+    expect(identifierTokens('ValidatePassword')).toEqual([
+      'validate',
+      'password',
+      'validatepassword',
+    ]);
+    expect(identifierTokens('HTTPServer')).toEqual(['http', 'server', 'httpserver']);
+    expect(identifierTokens('Retry')).toEqual(['retry']);
+  });
+
+  it('should tokenize queries the same way', () => {
+    expect(tokenize('check ValidatePassword')).toEqual([
+      'check',
+      'validate',
+      'password',
+      'validatepassword',
+    ]);
+  });
+});
+
+describe('annotateIdentifiers', () => {
+  it('should follow compound identifiers with their words', () => {
+    expect(annotateIdentifiers('where is parseHTTPRequest used')).toBe(
+      'where is parseHTTPRequest (parse http request) used'
+    );
+    expect(annotateIdentifiers('retry logic')).toBe('retry logic');
+  });
+});
+
+describe('identifier constraints', () => {
+  const checkLogin = {
+    name: 'CheckLogin',
+    signature: 'func CheckLogin(user *User, pw string) error',
+    snippet: 'if err := auth.ValidatePassword(user, pw); err != nil {\n  return err\n}',
+  };
+
+  it('should collect the identifiers of name, signature, and code', () => {
+    const identifiers = symbolIdentifiers(checkLogin);
+
+    expect(identifiers).toContain('checklogin');
+    expect(identifiers).toContain('validatepassword');
+    expect(identifiers).toContain('password');
+    expect(identifiers).not.toContain('authvalidatepassword');
+  });
+
+  it('should match whole identifiers, their components, and qualified names', () => {
+    const identifiers = symbolIdentifiers(checkLogin);
+
+    expect(containsIdentifier(identifiers, 'ValidatePassword')).toBe(true);
+    expect(containsIdentifier(identifiers, 'validate_password')).toBe(true);
+    expect(containsIdentifier(identifiers, 'Password')).toBe(true);
+    expect(containsIdentifier(identifiers, 'auth.ValidatePassword')).toBe(true);
+    expect(containsIdentifier(identifiers, 'HashPassword')).toBe(false);
+    expect(containsIdentifier(identifiers, ' ')).toBe(false);
+  });
+
+  it('should require every mustContain and no mustNotContain identifier', () => {
+    expect(matchesIdentifiers(checkLogin, { mustContain: ['ValidatePassword', 'User'] })).toBe(
+      true
+    );
+    expect(matchesIdentifiers(checkLogin, { mustContain: ['ValidatePassword', 'Token'] })).toBe(
+      false
+    );
+    expect(matchesIdentifiers(checkLogin, { mustNotContain: ['ValidatePassword'] })).toBe(false);
+    expect(matchesIdentifiers(checkLogin, {})).toBe(true);
+  });
+});
@@ -0,0 +1,109 @@
+import { describe, expect, it, vi } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import {
+  DEFAULT_CODE_STOPWORDS,
+  DEFAULT_KEYWORD_WEIGHT,
+  KeywordWeights,
+  KeywordWeightsCache,
+  keywordSettingsFromEnv,
+} from '../keyword-weights';
+
+function doc(name: string, docstring?: string): SearchResult {
+  return { id: name, score: 1, metadata: { name, type: 'function', docstring } };
+}
+
+// "handler" is in half the corpus, "limiter" in one symbol
+const corpus = [
+  doc('RateLimiter', 'RateLimiter throttles requests.'),
+  doc('UserHandler'),
+  doc('OrderHandler'),
+  doc('CartHandler'),
+  doc('AuthHandler'),
+  doc('HealthHandler'),
+  doc('Server'),
+  doc('Config'),
+  doc('Store'),
+  doc('Cache'),
+];
+
+describe('KeywordWeights', () => {
+  it('should weight rare tokens above common ones', () => {
+    const weights = KeywordWeights.fromDocuments(corpus, { maxDocumentFraction: 1 });
+
+    expect(weights.weight('limiter')).toBeGreaterThan(weights.weight('handler'));
+    expect(weights.weight('handler')).toBeGreaterThan(0);
+    expect(weights.weight('unknown')).toBeGreaterThan(weights.weight('limiter'));
+  });
+
+  it('should treat tokens in a large share of symbols as stopwords', () => {
+    const weights = KeywordWeights.fromDocuments(corpus);
+
+    expect(weights.weight('handler')).toBe(0);
+    expect(weights.weight('limiter')).toBeGreaterThan(0);
+  });
+
+  it('should give code stopwords no weight', () => {
+    const weights = KeywordWeights.fromDocuments(corpus);
+
+    expect(DEFAULT_CODE_STOPWORDS).toContain('get');
+    expect(weights.weigh('get the rate limiter')).toMatchObject({ get: 0, the: 0 });
+  });
+
+  it('should multiply weights by configured boosts', () => {
+    const plain = KeywordWeights.fromDocuments(corpus);
+    const boosted = KeywordWeights.fromDocuments(corpus, { boosts: { Limiter: 2 } });
+
+    expect(boosted.weight('limiter')).toBeCloseTo(plain.weight('limiter') * 2);
+  });
+
+  it('should score a match by the share of query weight it contains', () => {
+    const weights = KeywordWeights.fromDocuments(corpus);
+    const query = weights.weigh('get RateLimiter');
+
+    expect(weights.match(query, corpus[0].metadata)).toEqual({
+      score: 1,
+      matched: ['rate', 'limiter', 'ratelimiter'],
+    });
+    expect(weights.match(query, corpus[6].metadata)).toEqual({ score: 0, matched: [] });
+    expect(weights.match(weights.weigh('the'), corpus[0].metadata).score).toBe(0);
+  });
+});
+
+describe('KeywordWeightsCache', () => {
+  it('should reload only when the index version changes', async () => {
+    const cache = new KeywordWeightsCache();
+    const load = vi.fn().mockResolvedValue(corpus);
+
+    const first = await cache.get('v1', load);
+    expect(await cache.get('v1', load)).toBe(first);
+    expect(load).toHaveBeenCalledTimes(1);
+
+    await cache.get('v2', load);
+    await cache.get(null, load);
+    expect(load).toHaveBeenCalledTimes(3);
+  });
+});
+
+describe('keywordSettingsFromEnv', () => {
+  it('should be empty when unset or off', () => {
+    expect(keywordSettingsFromEnv({})).toEqual({});
+    expect(keywordSettingsFromEnv({ DEV_AGENT_KEYWORD_WEIGHT: 'off' })).toEqual({});
+    expect(keywordSettingsFromEnv({ DEV_AGENT_KEYWORD_WEIGHT: 'lots' })).toEqual({});
+  });
+
+  it('should read the weight, stopwords, and boosts', () => {
+    expect(keywordSettingsFromEnv({ DEV_AGENT_KEYWORD_WEIGHT: 'on' })).toEqual({
+      keywordWeight: DEFAULT_KEYWORD_WEIGHT,
+    });
+
+    const settings = keywordSettingsFromEnv({
+      DEV_AGENT_KEYWORD_WEIGHT: '0.1',
+      DEV_AGENT_KEYWORD_STOPWORDS: 'impl, util',
+      DEV_AGENT_KEYWORD_BOOSTS: 'handler=0.2,ratelimit=2,bad=x',
+    });
+
+    expect(settings.keywordWeight).toBe(0.1);
+    expect(settings.keywords?.stopwords).toEqual([...DEFAULT_CODE_STOPWORDS, 'impl', 'util']);
+    expect(settings.keywords?.boosts).toEqual({ handler: 0.2, ratelimit: 2 });
+  });
+});
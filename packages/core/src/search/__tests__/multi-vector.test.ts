@@ -0,0 +1,45 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { mergeRankings, poolVectors } from '../multi-vector';
+
+describe('poolVectors', () => {
+  it('should average unit vectors so each example counts the same', () => {
+    expect(poolVectors([[3, 0], [0, 1]], 'mean')).toEqual([0.5, 0.5]);
+  });
+
+  it('should take the largest value per dimension', () => {
+    expect(poolVectors([[0.6, -0.8], [0, 1]], 'max')).toEqual([0.6, 1]);
+  });
+
+  it('should return a lone example unchanged apart from scale', () => {
+    expect(poolVectors([[0, 2]], 'mean')).toEqual([0, 1]);
+  });
+
+  it('should reject an empty list', () => {
+    expect(() => poolVectors([], 'mean')).toThrow('At least one vector');
+  });
+});
+
+describe('mergeRankings', () => {
+  const result = (id: string, score: number): SearchResult => ({ id, score, metadata: {} });
+
+  it('should keep each result once with its best score', () => {
+    const merged = mergeRankings(
+      [
+        [result('a', 0.9), result('b', 0.7)],
+        [result('b', 0.95), result('c', 0.6)],
+      ],
+      10
+    );
+
+    expect(merged.map((r) => [r.id, r.score])).toEqual([
+      ['b', 0.95],
+      ['a', 0.9],
+      ['c', 0.6],
+    ]);
+  });
+
+  it('should cap the merged list', () => {
+    expect(mergeRankings([[result('a', 0.9), result('b', 0.7)]], 1)).toHaveLength(1);
+  });
+});
@@ -0,0 +1,46 @@
+import { describe, expect, it } from 'vitest';
+import { expandQuery } from '../query-expansion';
+
+describe('expandQuery', () => {
+  it('should replace a query word with each synonym', () => {
+    const expansion = expandQuery('auth middleware', 10);
+
+    expect(expansion.terms).toEqual([
+      { term: 'auth', synonyms: ['authentication', 'authorization', 'login', 'credentials'] },
+    ]);
+    expect(expansion.variants.map((v) => v.query)).toEqual([
+      'authentication middleware',
+      'authorization middleware',
+      'login middleware',
+      'credentials middleware',
+    ]);
+  });
+
+  it('should take turns between terms and cap the variants', () => {
+    const expansion = expandQuery('retry db', 3);
+
+    expect(expansion.variants.map((v) => [v.term, v.synonym])).toEqual([
+      ['retry', 'backoff'],
+      ['db', 'database'],
+      ['db', 'storage'],
+    ]);
+  });
+
+  it('should expand words inside identifiers', () => {
+    const expansion = expandQuery('getUserConfig');
+
+    expect(expansion.terms.map((t) => t.term)).toEqual(['get', 'user', 'config']);
+    expect(expansion.variants[1]).toEqual({
+      query: 'getUserConfig account',
+      term: 'user',
+      synonym: 'account',
+    });
+  });
+
+  it('should skip synonyms already in the query', () => {
+    const expansion = expandQuery('cache memoize');
+
+    expect(expansion.terms).toEqual([]);
+    expect(expansion.variants).toEqual([]);
+  });
+});
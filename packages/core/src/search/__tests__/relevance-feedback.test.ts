@@ -0,0 +1,59 @@
+import { describe, expect, it } from 'vitest';
+import { applyRocchio, RelevanceFeedback } from '../relevance-feedback';
+
+describe('applyRocchio', () => {
+  it('should move the query toward the relevant centroid', () => {
+    const [x, y] = applyRocchio([2, 0], [[0, 3], [0, 1]], []);
+
+    expect(x).toBeCloseTo(0.8);
+    expect(y).toBeCloseTo(0.6);
+  });
+
+  it('should move the query away from non-relevant results', () => {
+    const weights = { query: 1, relevant: 1, nonRelevant: 1 };
+
+    expect(applyRocchio([1, 0], [[0, 3]], [[5, 0]], weights)).toEqual([0, 1]);
+  });
+
+  it('should return the normalized query without feedback', () => {
+    expect(applyRocchio([0, 4], [], [])).toEqual([0, 1]);
+  });
+});
+
+describe('RelevanceFeedback', () => {
+  it('should keep selections per session, the latest per document winning', () => {
+    const feedback = new RelevanceFeedback();
+
+    feedback.record('a', 'doc1', true);
+    feedback.record('a', 'doc2', false);
+    feedback.record('a', 'doc1', false);
+    feedback.record('b', 'doc3', true);
+
+    expect(feedback.get('a')).toEqual({ relevant: [], nonRelevant: ['doc1', 'doc2'] });
+    expect(feedback.get('b')).toEqual({ relevant: ['doc3'], nonRelevant: [] });
+    expect(feedback.get('missing')).toEqual({ relevant: [], nonRelevant: [] });
+  });
+
+  it('should bound selections and forget the least recently updated sessions', () => {
+    const feedback = new RelevanceFeedback({ maxSessions: 2, maxSelections: 2 });
+
+    feedback.record('a', 'doc1', true);
+    feedback.record('b', 'doc1', true);
+    feedback.record('a', 'doc2', true);
+    feedback.record('a', 'doc3', true);
+    feedback.record('c', 'doc1', true);
+
+    expect(feedback.get('a').relevant).toEqual(['doc3', 'doc2']);
+    expect(feedback.get('b').relevant).toEqual([]);
+    expect(feedback.get('c').relevant).toEqual(['doc1']);
+  });
+
+  it('should clear a session', () => {
+    const feedback = new RelevanceFeedback();
+    feedback.record('a', 'doc1', true);
+
+    expect(feedback.clear('a')).toBe(true);
+    expect(feedback.clear('a')).toBe(false);
+    expect(feedback.get('a').relevant).toEqual([]);
+  });
+});
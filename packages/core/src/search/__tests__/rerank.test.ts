@@ -0,0 +1,73 @@
+import { describe, expect, it, vi } from 'vitest';
+import type { RerankProvider, SearchResult } from '../../vector/types';
+import { rerankResults, rerankText } from '../rerank';
+
+function result(id: string, score: number): SearchResult {
+  return { id, score, metadata: { name: id, type: 'function', snippet: `func ${id}() {}` } };
+}
+
+function reranker(scores: Record<string, number>): RerankProvider {
+  return {
+    modelName: 'test-cross-encoder',
+    initialize: vi.fn().mockResolvedValue(undefined),
+    score: vi.fn(async (_query: string, documents: string[]) =>
+      documents.map((text) => scores[text.split('\n')[0]] ?? 0)
+    ),
+  };
+}
+
+describe('rerankText', () => {
+  it('should put the name, signature, and doc comment before the source', () => {
+    expect(
+      rerankText({
+        name: 'Client.Do',
+        type: 'method',
+        signature: 'func (c *Client) Do(req *Request) (*Response, error)',
+        docstring: '  Do sends a request.  ',
+        snippet: 'func (c *Client) Do(req *Request) (*Response, error) {}',
+      })
+    ).toBe(
+      [
+        'method Client.Do',
+        'func (c *Client) Do(req *Request) (*Response, error)',
+        'Do sends a request.',
+        'func (c *Client) Do(req *Request) (*Response, error) {}',
+      ].join('\n')
+    );
+  });
+
+  it('should cap long sources', () => {
+    expect(rerankText({ snippet: 'x'.repeat(5000) })).toHaveLength(2000);
+  });
+});
+
+describe('rerankResults', () => {
+  const results = [result('a', 0.9), result('b', 0.85), result('c', 0.8), result('d', 0.75)];
+
+  it('should reorder candidates by reranker score, keeping vector scores', async () => {
+    const scorer = reranker({ 'function c': 0.9, 'function b': 0.5 });
+
+    const reranked = await rerankResults('query', results, scorer);
+
+    expect(reranked.map((r) => r.id)).toEqual(['c', 'b', 'a', 'd']);
+    expect(reranked[0]).toMatchObject({ score: 0.8, metadata: { rerankScore: 0.9 } });
+    expect(scorer.score).toHaveBeenCalledWith('query', expect.any(Array));
+  });
+
+  it('should leave results past the candidates in vector order', async () => {
+    const scorer = reranker({ 'function b': 1, 'function d': 1 });
+
+    const reranked = await rerankResults('query', results, scorer, 2);
+
+    expect(reranked.map((r) => r.id)).toEqual(['b', 'a', 'c', 'd']);
+    expect(vi.mocked(scorer.score).mock.calls[0][1]).toHaveLength(2);
+    expect(reranked[3].metadata.rerankScore).toBeUndefined();
+  });
+
+  it('should not call the reranker without results', async () => {
+    const scorer = reranker({});
+
+    expect(await rerankResults('query', [], scorer)).toEqual([]);
+    expect(scorer.score).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,52 @@
+/**
+ * Doc Quality
+ * A small ranking nudge toward documented public API
+ *
+ * The boost is kept apart from the semantic score: results keep their
+ * similarity `score`, and the boost only decides order between near ties.
+ */
+
+import type { SearchResult, SearchResultMetadata } from '../vector/types';
+
+/** Default boost for a fully documented exported symbol; a gentle tie-breaker */
+export const DEFAULT_DOC_WEIGHT = 0.02;
+
+/** Doc comment length (words) that earns the full boost */
+const FULL_DOC_WORDS = 24;
+
+/**
+ * How well a symbol is documented, from 0 (no doc comment) to 1
+ *
+ * Grows with doc comment length up to ~24 words; unexported symbols get half,
+ * so ties break toward public API. Boilerplate doc comments count as none.
+ */
+export function docQuality(metadata: SearchResultMetadata): number {
+  const doc = metadata.docstring?.trim();
+  if (!doc || metadata.boilerplateDoc) return 0;
+  const richness = Math.min(1, doc.split(/\s+/).length / FULL_DOC_WORDS);
+  return metadata.exported === false ? richness / 2 : richness;
+}
+
+/**
+ * Order results by semantic score plus a doc quality boost
+ *
+ * Scores are unchanged; boosted results carry the boost in
+ * `metadata.docBoost`. With a weight of 0 the order is unchanged.
+ *
+ * @param results - Results in score order
+ * @param weight - Boost for a fully documented exported symbol (default: 0.02)
+ */
+export function rankByDocQuality(
+  results: SearchResult[],
+  weight = DEFAULT_DOC_WEIGHT
+): SearchResult[] {
+  if (weight <= 0) return results;
+
+  const ranked = results.map((result, index) => {
+    const docBoost = weight * docQuality(result.metadata);
+    const boosted =
+      docBoost > 0 ? { ...result, metadata: { ...result.metadata, docBoost } } : result;
+    return { result: boosted, index, rank: result.score + docBoost };
+  });
+  return ranked.sort((a, b) => b.rank - a.rank || a.index - b.index).map(({ result }) => result);
+}
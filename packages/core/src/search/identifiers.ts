@@ -0,0 +1,126 @@
+/**
+ * Identifier Tokenization
+ * Splits code identifiers into the words they are made of
+ *
+ * Natural-language queries ("mark fail wait") rarely spell identifiers the
+ * way code does (`MarkFailAndGetWait`), so names are split into words at
+ * index time and the words are embedded alongside the name. Queries are
+ * tokenized the same way, for both embedding and keyword matching, and
+ * search constraints like "must contain `ValidatePassword`" are checked
+ * against a symbol's identifier tokens.
+ */
+
+import type { SearchResultMetadata } from '../vector/types';
+
+/**
+ * Split an identifier into lowercase words at camelCase, PascalCase,
+ * snake_case, kebab-case, and dot boundaries
+ *
+ * Runs of capitals are kept together as an acronym, ending before a capital
+ * that starts a word: `HTTPServer` splits into `http` and `server`.
+ *
+ * @example
+ * ```typescript
+ * splitIdentifier('MarkFailAndGetWait'); // ['mark', 'fail', 'and', 'get', 'wait']
+ * splitIdentifier('parse_http_request'); // ['parse', 'http', 'request']
+ * ```
+ */
+export function splitIdentifier(identifier: string): string[] {
+  return identifier
+    .replace(/([a-z0-9])([A-Z])/g, '$1 $2')
+    .replace(/([A-Z]+)([A-Z][a-z])/g, '$1 $2')
+    .split(/[^A-Za-z0-9]+/)
+    .filter((word) => word.length > 0)
+    .map((word) => word.toLowerCase());
+}
+
+/**
+ * Keyword tokens of an identifier: its words plus the whole identifier,
+ * lowercased and without separators
+ *
+ * @example
+ * ```typescript
+ * identifierTokens('ValidatePassword'); // ['validate', 'password', 'validatepassword']
+ * identifierTokens('HTTPServer'); // ['http', 'server', 'httpserver']
+ * ```
+ */
+export function identifierTokens(identifier: string): string[] {
+  const words = splitIdentifier(identifier);
+  return [...new Set(words.length > 1 ? [...words, words.join('')] : words)];
+}
+
+/**
+ * Keyword tokens of free text, each whitespace-separated word tokenized as an identifier
+ */
+export function tokenize(text: string): string[] {
+  return [...new Set(text.split(/\s+/).flatMap(identifierTokens))];
+}
+
+/**
+ * Follow each compound identifier in a query with its words, the way
+ * document headers are embedded, so "ValidatePassword" also matches
+ * "validate password"
+ *
+ * @example
+ * ```typescript
+ * annotateIdentifiers('find ValidatePassword'); // 'find ValidatePassword (validate password)'
+ * ```
+ */
+export function annotateIdentifiers(query: string): string {
+  return query.replace(/\S+/g, (word) => {
+    const words = identifierWords(word);
+    return words ? `${word} (${words})` : word;
+  });
+}
+
+/**
+ * Words of an identifier joined by spaces, or undefined when it is a single
+ * word or already prose (e.g. a markdown heading)
+ */
+export function identifierWords(identifier: string): string | undefined {
+  if (/\s/.test(identifier)) return undefined;
+  const words = splitIdentifier(identifier);
+  return words.length > 1 ? words.join(' ') : undefined;
+}
+
+/**
+ * Identifier tokens of a symbol: every identifier in its name, signature,
+ * and code, split at separators and punctuation, with each identifier's
+ * words and the whole identifier (see identifierTokens)
+ */
+export function symbolIdentifiers(metadata: SearchResultMetadata): Set<string> {
+  const text = [metadata.name, metadata.signature, metadata.snippet].filter(Boolean).join(' ');
+  return new Set(text.split(/[^A-Za-z0-9_]+/).flatMap(identifierTokens));
+}
+
+/**
+ * Whether a symbol contains an identifier, matched by its tokens
+ *
+ * `ValidatePassword` matches `ValidatePassword` and `validate_password`;
+ * `password` also matches them, as a component. Qualified identifiers like
+ * `auth.ValidatePassword` need every part.
+ *
+ * @param identifiers - The symbol's identifier tokens (see symbolIdentifiers)
+ */
+export function containsIdentifier(identifiers: Set<string>, identifier: string): boolean {
+  const parts = identifier
+    .split(/[^A-Za-z0-9_]+/)
+    .map((part) => splitIdentifier(part).join(''))
+    .filter((part) => part.length > 0);
+  return parts.length > 0 && parts.every((part) => identifiers.has(part));
+}
+
+/**
+ * Whether a symbol contains every `mustContain` identifier and none of the
+ * `mustNotContain` ones (see containsIdentifier)
+ */
+export function matchesIdentifiers(
+  metadata: SearchResultMetadata,
+  constraints: { mustContain?: string[]; mustNotContain?: string[] }
+): boolean {
+  const identifiers = symbolIdentifiers(metadata);
+  return (
+    (constraints.mustContain ?? []).every((id) => containsIdentifier(identifiers, id)) &&
+    !(constraints.mustNotContain ?? []).some((id) => containsIdentifier(identifiers, id))
+  );
+}
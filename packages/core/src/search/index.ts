@@ -0,0 +1,13 @@
+/**
+ * Search
+ * Identifier tokenization, query expansion, relevance feedback, reranking, keyword
+ * weighting, and ranking
+ */
+
+export * from './doc-quality';
+export * from './identifiers';
+export * from './keyword-weights';
+export * from './multi-vector';
+export * from './query-expansion';
+export * from './relevance-feedback';
+export * from './rerank';
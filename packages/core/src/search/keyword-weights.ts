@@ -0,0 +1,258 @@
+/**
+ * Keyword Weights
+ * IDF-style token weights for keyword matching in code search
+ *
+ * Words like `get`, `new`, or `handler` show up in so much code that a result
+ * containing them says little about a query. Each token is weighted by how
+ * rare it is among the indexed symbols (BM25's inverse document frequency),
+ * so a query's distinctive identifiers decide the keyword score and the
+ * ubiquitous ones barely count. Tokens in a large share of symbols count as
+ * stopwords outright, as do the words in a code stopword list; per-token
+ * boosts correct what corpus frequency gets wrong.
+ *
+ * Symbols are tokenized from their name, signature, and doc comment the same
+ * way queries are (see identifiers.ts).
+ */
+
+import type { SearchResult, SearchResultMetadata } from '../vector/types';
+import { tokenize } from './identifiers';
+
+/** Keyword boost for a result matching every weighted query token, when turned on */
+export const DEFAULT_KEYWORD_WEIGHT = 0.05;
+
+/** Share of symbols a token may appear in before it counts as a stopword */
+export const DEFAULT_MAX_DOCUMENT_FRACTION = 0.2;
+
+/** Env var turning keyword scoring on: a weight, or `on` for DEFAULT_KEYWORD_WEIGHT */
+export const KEYWORD_WEIGHT_ENV = 'DEV_AGENT_KEYWORD_WEIGHT';
+
+/** Env var adding comma-separated stopwords to the defaults */
+export const KEYWORD_STOPWORDS_ENV = 'DEV_AGENT_KEYWORD_STOPWORDS';
+
+/** Env var with comma-separated token boosts, e.g. `handler=0.2,ratelimit=2` */
+export const KEYWORD_BOOSTS_ENV = 'DEV_AGENT_KEYWORD_BOOSTS';
+
+/**
+ * Words that carry no signal in a code search query: English function words
+ * and verbs that prefix half of all identifiers
+ */
+export const DEFAULT_CODE_STOPWORDS = [
+  'a',
+  'an',
+  'and',
+  'are',
+  'as',
+  'at',
+  'be',
+  'by',
+  'code',
+  'do',
+  'does',
+  'find',
+  'for',
+  'from',
+  'func',
+  'function',
+  'get',
+  'has',
+  'how',
+  'in',
+  'is',
+  'it',
+  'make',
+  'method',
+  'new',
+  'of',
+  'on',
+  'or',
+  'set',
+  'that',
+  'the',
+  'this',
+  'to',
+  'what',
+  'when',
+  'where',
+  'which',
+  'with',
+];
+
+/**
+ * Keyword weighting settings
+ */
+export interface KeywordWeightsConfig {
+  /** Tokens that never count toward a match (default: DEFAULT_CODE_STOPWORDS) */
+  stopwords?: string[];
+  /** Multipliers on particular tokens' weights, e.g. `{ handler: 0.2, ratelimit: 2 }` */
+  boosts?: Record<string, number>;
+  /** Share of symbols a token may appear in before it's a stopword (default: 0.2) */
+  maxDocumentFraction?: number;
+}
+
+/**
+ * Keyword match of one result
+ */
+export interface KeywordMatch {
+  /** Weight of the query tokens the result contains over all query token weight (0-1) */
+  score: number;
+  /** Query tokens the result contains */
+  matched: string[];
+}
+
+/**
+ * Token weights over a corpus of indexed symbols
+ */
+export class KeywordWeights {
+  private readonly stopwords: Set<string>;
+  private readonly boosts: Map<string, number>;
+  private readonly maxDocuments: number;
+
+  /**
+   * @param documentFrequency - Symbols each token appears in
+   * @param documents - Symbols in the corpus
+   * @param config - Stopwords, boosts, and the corpus stopword cutoff
+   */
+  constructor(
+    private readonly documentFrequency: Map<string, number>,
+    private readonly documents: number,
+    config: KeywordWeightsConfig = {}
+  ) {
+    this.stopwords = new Set(
+      (config.stopwords ?? DEFAULT_CODE_STOPWORDS).map((word) => word.toLowerCase())
+    );
+    this.boosts = new Map(
+      Object.entries(config.boosts ?? {}).map(([token, boost]) => [token.toLowerCase(), boost])
+    );
+    this.maxDocuments = documents * (config.maxDocumentFraction ?? DEFAULT_MAX_DOCUMENT_FRACTION);
+  }
+
+  /**
+   * Weights built from indexed symbols
+   */
+  static fromDocuments(docs: SearchResult[], config?: KeywordWeightsConfig): KeywordWeights {
+    const frequency = new Map<string, number>();
+    for (const doc of docs) {
+      for (const token of documentTokens(doc.metadata)) {
+        frequency.set(token, (frequency.get(token) ?? 0) + 1);
+      }
+    }
+    return new KeywordWeights(frequency, docs.length, config);
+  }
+
+  /**
+   * Weight of a token: 0 for stopwords, otherwise its IDF times any boost
+   *
+   * Tokens no symbol contains get the highest IDF, since matching them is rare.
+   */
+  weight(token: string): number {
+    if (this.stopwords.has(token)) return 0;
+    const frequency = this.documentFrequency.get(token) ?? 0;
+    if (frequency > this.maxDocuments) return 0;
+    const idf = Math.log(1 + (this.documents - frequency + 0.5) / (frequency + 0.5));
+    return idf * (this.boosts.get(token) ?? 1);
+  }
+
+  /**
+   * Effective weight of each token in a query, stopwords included at 0
+   */
+  weigh(query: string): Record<string, number> {
+    return Object.fromEntries(tokenize(query).map((token) => [token, this.weight(token)]));
+  }
+
+  /**
+   * How much of a query's token weight a result matches
+   *
+   * @param weights - The query's token weights (see weigh)
+   */
+  match(weights: Record<string, number>, metadata: SearchResultMetadata): KeywordMatch {
+    const tokens = documentTokens(metadata);
+    let total = 0;
+    let found = 0;
+    const matched: string[] = [];
+    for (const [token, weight] of Object.entries(weights)) {
+      total += weight;
+      if (weight > 0 && tokens.has(token)) {
+        found += weight;
+        matched.push(token);
+      }
+    }
+    return { score: total > 0 ? found / total : 0, matched };
+  }
+}
+
+/**
+ * Keyword weights kept across searches while the index is unchanged
+ */
+export class KeywordWeightsCache {
+  private weights: KeywordWeights | null = null;
+  private version: string | null = null;
+
+  constructor(private readonly config?: KeywordWeightsConfig) {}
+
+  /**
+   * Weights for the index at `version`, loading its symbols only when it changed
+   *
+   * @param version - Index version; null disables reuse
+   * @param load - Reads every indexed symbol
+   */
+  async get(version: string | null, load: () => Promise<SearchResult[]>): Promise<KeywordWeights> {
+    if (this.weights && version !== null && version === this.version) {
+      return this.weights;
+    }
+    this.weights = KeywordWeights.fromDocuments(await load(), this.config);
+    this.version = version;
+    return this.weights;
+  }
+}
+
+/**
+ * Keyword settings from the environment (see the *_ENV constants)
+ *
+ * @returns The weight and config to pass to SearchService; empty when unset
+ */
+export function keywordSettingsFromEnv(env: Record<string, string | undefined> = process.env): {
+  keywordWeight?: number;
+  keywords?: KeywordWeightsConfig;
+} {
+  const value = env[KEYWORD_WEIGHT_ENV]?.trim().toLowerCase();
+  const parsed = Number(value);
+  const keywordWeight =
+    !value || ['0', 'false', 'off'].includes(value)
+      ? undefined
+      : ['true', 'on'].includes(value)
+        ? DEFAULT_KEYWORD_WEIGHT
+        : Number.isFinite(parsed) && parsed > 0
+          ? parsed
+          : undefined;
+
+  const list = (name: string) =>
+    (env[name] ?? '')
+      .split(',')
+      .map((item) => item.trim())
+      .filter(Boolean);
+  const stopwords = list(KEYWORD_STOPWORDS_ENV);
+  const boosts = Object.fromEntries(
+    list(KEYWORD_BOOSTS_ENV).flatMap((pair) => {
+      const [token, boost] = pair.split('=');
+      const factor = Number(boost);
+      return token && Number.isFinite(factor) ? [[token.trim(), factor]] : [];
+    })
+  );
+
+  const keywords: KeywordWeightsConfig = {
+    ...(stopwords.length > 0 ? { stopwords: [...DEFAULT_CODE_STOPWORDS, ...stopwords] } : {}),
+    ...(Object.keys(boosts).length > 0 ? { boosts } : {}),
+  };
+  return {
+    ...(keywordWeight !== undefined ? { keywordWeight } : {}),
+    ...(Object.keys(keywords).length > 0 ? { keywords } : {}),
+  };
+}
+
+/**
+ * Keyword tokens of a symbol: its name, signature, and doc comment
+ */
+function documentTokens(metadata: SearchResultMetadata): Set<string> {
+  const text = [metadata.name, metadata.signature, metadata.docstring].filter(Boolean).join(' ');
+  return new Set(tokenize(text));
+}
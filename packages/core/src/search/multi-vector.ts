@@ -0,0 +1,63 @@
+/**
+ * Multi-Vector Search
+ * Combine several examples (queries or indexed symbols) into one search
+ *
+ * - `mean` averages the examples' unit vectors, so each example counts the
+ *   same however long its text is; results sit near the examples' centroid
+ *   and favor what they have in common.
+ * - `max` takes the largest value per dimension. It keeps features strong in
+ *   any one example, so a result can match one example well without matching
+ *   all of them.
+ * - `separate` searches with each example and merges the rankings, keeping
+ *   each result's best score: the union of "more like each of these".
+ */
+
+import type { SearchResult } from '../vector/types';
+
+/**
+ * How example vectors become a search
+ */
+export type ExamplePooling = 'mean' | 'max' | 'separate';
+
+/**
+ * Pool example vectors into a single query vector
+ *
+ * @param vectors - One embedding per example, all of the same dimension
+ * @param pooling - `mean` or `max` (see module docs)
+ */
+export function poolVectors(vectors: number[][], pooling: 'mean' | 'max'): number[] {
+  if (vectors.length === 0) {
+    throw new Error('At least one vector is required');
+  }
+
+  const units = vectors.map(toUnit);
+  const pooled = [...units[0]];
+  for (const vector of units.slice(1)) {
+    for (let i = 0; i < pooled.length; i++) {
+      pooled[i] = pooling === 'max' ? Math.max(pooled[i], vector[i]) : pooled[i] + vector[i];
+    }
+  }
+  return pooling === 'max' ? pooled : pooled.map((value) => value / units.length);
+}
+
+/**
+ * Merge per-example rankings, keeping each result's best score
+ *
+ * @param rankings - Results per example, each in score order
+ * @param limit - Most results to keep
+ */
+export function mergeRankings(rankings: SearchResult[][], limit: number): SearchResult[] {
+  const best = new Map<string, SearchResult>();
+  for (const results of rankings) {
+    for (const result of results) {
+      const current = best.get(result.id);
+      if (!current || result.score > current.score) best.set(result.id, result);
+    }
+  }
+  return [...best.values()].sort((a, b) => b.score - a.score).slice(0, limit);
+}
+
+function toUnit(vector: number[]): number[] {
+  const norm = Math.sqrt(vector.reduce((sum, value) => sum + value * value, 0));
+  return norm > 0 ? vector.map((value) => value / norm) : vector;
+}
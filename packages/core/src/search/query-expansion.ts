@@ -0,0 +1,110 @@
+/**
+ * Query Expansion
+ * Rewrites search queries with code synonyms to improve recall
+ *
+ * Each variant replaces one query word with a synonym ("auth" becomes
+ * "login"), so results can be traced back to the expanded term that found
+ * them. Expansion is opt-in: variants cost an extra embedding each.
+ */
+
+import { splitIdentifier } from './identifiers';
+
+/** Default variant queries per expansion */
+export const DEFAULT_MAX_VARIANTS = 6;
+
+/**
+ * Words used interchangeably in code and in questions about it
+ */
+const SYNONYM_GROUPS: string[][] = [
+  ['auth', 'authentication', 'authorization', 'login', 'credentials'],
+  ['config', 'configuration', 'settings', 'options'],
+  ['db', 'database', 'storage', 'sql'],
+  ['err', 'error', 'failure', 'exception'],
+  ['delete', 'remove', 'destroy'],
+  ['create', 'new', 'make', 'build'],
+  ['fetch', 'get', 'retrieve', 'load'],
+  ['http', 'request', 'endpoint', 'handler'],
+  ['cache', 'memoize'],
+  ['retry', 'backoff'],
+  ['log', 'logger', 'logging'],
+  ['user', 'account'],
+  ['parse', 'decode', 'unmarshal'],
+  ['serialize', 'encode', 'marshal'],
+  ['queue', 'job', 'worker'],
+  ['msg', 'message', 'event'],
+];
+
+const SYNONYMS = new Map<string, string[]>(
+  SYNONYM_GROUPS.flatMap((group) =>
+    group.map((word) => [word, group.filter((other) => other !== word)] as [string, string[]])
+  )
+);
+
+/**
+ * A query word and the synonyms it was expanded to
+ */
+export interface ExpandedTerm {
+  term: string;
+  synonyms: string[];
+}
+
+/**
+ * The query with one word replaced by a synonym
+ */
+export interface QueryVariant {
+  query: string;
+  term: string;
+  synonym: string;
+}
+
+/**
+ * Synonyms found for a query and the variant queries built from them
+ */
+export interface QueryExpansion {
+  query: string;
+  /** Query words with synonyms, in query order */
+  terms: ExpandedTerm[];
+  /** Variant queries, alternating between terms so each gets a turn */
+  variants: QueryVariant[];
+}
+
+/**
+ * Expand a query with code synonyms
+ *
+ * Identifiers in the query are split into words first, so "getUserConfig"
+ * expands "user" and "config". Synonyms already in the query are skipped.
+ *
+ * @param query - Search query
+ * @param maxVariants - Most variant queries to build (default: 6)
+ */
+export function expandQuery(query: string, maxVariants = DEFAULT_MAX_VARIANTS): QueryExpansion {
+  const words = query.split(/\s+/).flatMap(splitIdentifier);
+  const present = new Set(words);
+
+  const terms: ExpandedTerm[] = [];
+  for (const term of new Set(words)) {
+    const synonyms = (SYNONYMS.get(term) ?? []).filter((synonym) => !present.has(synonym));
+    if (synonyms.length > 0) terms.push({ term, synonyms });
+  }
+
+  const variants: QueryVariant[] = [];
+  const rounds = Math.max(0, ...terms.map((t) => t.synonyms.length));
+  for (let round = 0; round < rounds && variants.length < maxVariants; round++) {
+    for (const { term, synonyms } of terms) {
+      const synonym = synonyms[round];
+      if (!synonym || variants.length >= maxVariants) continue;
+      variants.push({ query: replaceWord(query, term, synonym), term, synonym });
+    }
+  }
+
+  return { query, terms, variants };
+}
+
+/**
+ * Replace a word in a query, or append the synonym when the word is only part
+ * of an identifier (e.g. "user" in "getUserConfig")
+ */
+function replaceWord(query: string, term: string, synonym: string): string {
+  const pattern = new RegExp(`\\b${term}\\b`, 'gi');
+  return pattern.test(query) ? query.replace(pattern, synonym) : `${query} ${synonym}`;
+}
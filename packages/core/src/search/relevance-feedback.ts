@@ -0,0 +1,154 @@
+/**
+ * Relevance Feedback
+ * Bias a session's later searches toward results it picked (Rocchio)
+ *
+ * The query vector moves toward the centroid of results marked relevant and
+ * away from the centroid of results marked not relevant:
+ *
+ *   q' = query·q + relevant·mean(R) - nonRelevant·mean(N)
+ *
+ * All vectors are normalized first, so a long document counts no more than a
+ * short one. Feedback lives in memory, keyed by a caller-chosen session id;
+ * it never touches the index and is gone when the process exits.
+ */
+
+/**
+ * Weights of the Rocchio terms
+ */
+export interface FeedbackWeights {
+  /** Weight of the original query (alpha) */
+  query: number;
+  /** Weight of the relevant results' centroid (beta) */
+  relevant: number;
+  /** Weight of the non-relevant results' centroid (gamma) */
+  nonRelevant: number;
+}
+
+/**
+ * Classic Rocchio weights: the query dominates, negatives only nudge
+ */
+export const DEFAULT_FEEDBACK_WEIGHTS: FeedbackWeights = {
+  query: 1,
+  relevant: 0.75,
+  nonRelevant: 0.15,
+};
+
+/**
+ * A result a session marked relevant or not relevant
+ */
+export interface FeedbackSelection {
+  /** Indexed document ID */
+  documentId: string;
+  relevant: boolean;
+  at: Date;
+}
+
+/**
+ * Options for a feedback store
+ */
+export interface RelevanceFeedbackOptions {
+  /** Sessions kept; the least recently updated are forgotten first (default: 100) */
+  maxSessions?: number;
+  /** Selections kept per session, most recent first (default: 20) */
+  maxSelections?: number;
+}
+
+/**
+ * Move a query vector toward relevant and away from non-relevant vectors
+ *
+ * @param query - Query embedding
+ * @param relevant - Embeddings of results marked relevant
+ * @param nonRelevant - Embeddings of results marked not relevant
+ * @param weights - Rocchio weights (default: DEFAULT_FEEDBACK_WEIGHTS)
+ * @returns The adjusted query vector, normalized
+ */
+export function applyRocchio(
+  query: number[],
+  relevant: number[][],
+  nonRelevant: number[][],
+  weights: FeedbackWeights = DEFAULT_FEEDBACK_WEIGHTS
+): number[] {
+  const adjusted = toUnit(query).map((value) => value * weights.query);
+  for (const [vectors, weight] of [
+    [relevant, weights.relevant],
+    [nonRelevant, -weights.nonRelevant],
+  ] as const) {
+    for (const vector of vectors.map(toUnit)) {
+      for (let i = 0; i < adjusted.length; i++) {
+        adjusted[i] += (weight * vector[i]) / vectors.length;
+      }
+    }
+  }
+  return toUnit(adjusted);
+}
+
+/**
+ * Relevance Feedback
+ * Per-session selections, in memory only
+ *
+ * @example
+ * ```typescript
+ * const feedback = new RelevanceFeedback();
+ * feedback.record('auth-review', 'internal/auth/session.go:function:Validate', true);
+ * const { relevant, nonRelevant } = feedback.get('auth-review');
+ * ```
+ */
+export class RelevanceFeedback {
+  private sessions = new Map<string, FeedbackSelection[]>();
+  private maxSessions: number;
+  private maxSelections: number;
+
+  constructor(options: RelevanceFeedbackOptions = {}) {
+    this.maxSessions = options.maxSessions ?? 100;
+    this.maxSelections = options.maxSelections ?? 20;
+  }
+
+  /**
+   * Mark a result relevant or not for a session
+   *
+   * Marking the same document again replaces the earlier selection.
+   *
+   * @returns The session's selections, most recent first
+   */
+  record(session: string, documentId: string, relevant: boolean): FeedbackSelection[] {
+    const previous = this.sessions.get(session) ?? [];
+    const selections = [
+      { documentId, relevant, at: new Date() },
+      ...previous.filter((selection) => selection.documentId !== documentId),
+    ].slice(0, this.maxSelections);
+
+    // Re-inserting keeps the map ordered from least to most recently updated
+    this.sessions.delete(session);
+    this.sessions.set(session, selections);
+    for (const stale of this.sessions.keys()) {
+      if (this.sessions.size <= this.maxSessions) break;
+      this.sessions.delete(stale);
+    }
+    return selections;
+  }
+
+  /**
+   * Document IDs a session marked relevant and not relevant
+   */
+  get(session: string): { relevant: string[]; nonRelevant: string[] } {
+    const selections = this.sessions.get(session) ?? [];
+    return {
+      relevant: selections.filter((s) => s.relevant).map((s) => s.documentId),
+      nonRelevant: selections.filter((s) => !s.relevant).map((s) => s.documentId),
+    };
+  }
+
+  /**
+   * Forget a session's feedback
+   *
+   * @returns True if the session had any
+   */
+  clear(session: string): boolean {
+    return this.sessions.delete(session);
+  }
+}
+
+function toUnit(vector: number[]): number[] {
+  const norm = Math.sqrt(vector.reduce((sum, value) => sum + value * value, 0));
+  return norm > 0 ? vector.map((value) => value / norm) : vector;
+}
@@ -0,0 +1,61 @@
+/**
+ * Reranking
+ * Reorder a search's top candidates with a more expensive scoring model
+ *
+ * Vector search finds the right neighborhood but often orders it poorly for
+ * code: the right file comes back with the wrong function on top. A reranker
+ * (a cross-encoder, see vector/reranker.ts) reads the query and each
+ * candidate together and reorders the top N by its score. Candidates past N
+ * keep their vector order after the reranked ones. Scores are unchanged; the
+ * reranker's score is kept in `metadata.rerankScore`.
+ */
+
+import type { RerankProvider, SearchResult, SearchResultMetadata } from '../vector/types';
+
+/** Candidates reranked per search when not configured */
+export const DEFAULT_RERANK_CANDIDATES = 20;
+
+/** Characters of a candidate the reranker reads; cross-encoders truncate at ~512 tokens anyway */
+const MAX_RERANK_TEXT = 2000;
+
+/**
+ * Text a reranker reads for a result: name, signature, doc comment, then source
+ */
+export function rerankText(metadata: SearchResultMetadata): string {
+  const parts = [
+    metadata.name && metadata.type ? `${metadata.type} ${metadata.name}` : metadata.name,
+    metadata.signature,
+    metadata.docstring?.trim(),
+    metadata.snippet,
+  ].filter((part): part is string => Boolean(part));
+  return parts.join('\n').slice(0, MAX_RERANK_TEXT);
+}
+
+/**
+ * Reorder the top candidates by reranker score
+ *
+ * @param query - Search query as the user wrote it
+ * @param results - Candidates in vector order
+ * @param reranker - Initialized rerank provider
+ * @param candidates - How many of the top results to rerank (default: 20)
+ * @returns All results: reranked candidates with `metadata.rerankScore`, then the rest
+ */
+export async function rerankResults(
+  query: string,
+  results: SearchResult[],
+  reranker: RerankProvider,
+  candidates = DEFAULT_RERANK_CANDIDATES
+): Promise<SearchResult[]> {
+  const head = results.slice(0, candidates);
+  if (head.length === 0) return results;
+
+  const scores = await reranker.score(query, head.map((result) => rerankText(result.metadata)));
+  const reranked = head
+    .map((result, index) => ({ result, index, rerankScore: scores[index] ?? 0 }))
+    .sort((a, b) => b.rerankScore - a.rerankScore || a.index - b.index)
+    .map(({ result, rerankScore }) => ({
+      ...result,
+      metadata: { ...result.metadata, rerankScore },
+    }));
+  return [...reranked, ...results.slice(candidates)];
+}
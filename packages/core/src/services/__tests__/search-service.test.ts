@@ -2,8 +2,10 @@
  * Tests for SearchService
  */
 
+import type { Logger } from '@lytics/kero';
 import { describe, expect, it, vi } from 'vitest';
 import type { RepositoryIndexer } from '../../indexer/index.js';
+import { EmbeddingsUnavailableError } from '../../vector/embedder.js';
 import type { SearchResult } from '../../vector/types.js';
 import { SearchService } from '../search-service.js';
 
@@ -62,6 +64,7 @@ describe('SearchService', () => {
       expect(mockIndexer.search).toHaveBeenCalledWith('authentication', {
         limit: 10,
         scoreThreshold: 0.7,
+        excludeGenerated: true,
       });
       expect(mockIndexer.close).toHaveBeenCalledOnce();
       expect(results).toEqual(mockSearchResults);
@@ -82,9 +85,29 @@ describe('SearchService', () => {
       expect(mockIndexer.search).toHaveBeenCalledWith('test query', {
         limit: 10,
         scoreThreshold: 0.7,
+        excludeGenerated: true,
       });
     });
 
+    it('should search generated code when included or filtered on', async () => {
+      const mockIndexer: RepositoryIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue([]),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+
+      const mockFactory = vi.fn().mockResolvedValue(mockIndexer);
+      const service = new SearchService({ repositoryPath: '/test/repo' }, mockFactory);
+
+      await service.search('mock store', { includeGenerated: true });
+      await service.search('mock store', { filter: { generated: true } });
+
+      expect(vi.mocked(mockIndexer.search).mock.calls.map(([, options]) => options)).toEqual([
+        expect.objectContaining({ excludeGenerated: false }),
+        expect.objectContaining({ excludeGenerated: false, filter: { generated: true } }),
+      ]);
+    });
+
     it('should close indexer even on error', async () => {
       const mockIndexer: RepositoryIndexer = {
         initialize: vi.fn().mockResolvedValue(undefined),
@@ -98,6 +121,303 @@ describe('SearchService', () => {
       await expect(service.search('test')).rejects.toThrow('Search failed');
       expect(mockIndexer.close).toHaveBeenCalledOnce();
     });
+
+    it('should report unavailable embeddings and release the index', async () => {
+      const mockIndexer: RepositoryIndexer = {
+        initialize: vi
+          .fn()
+          .mockRejectedValue(new EmbeddingsUnavailableError('Xenova/all-MiniLM-L6-v2', 'offline')),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+
+      const mockFactory = vi.fn().mockResolvedValue(mockIndexer);
+      const service = new SearchService({ repositoryPath: '/test/repo' }, mockFactory);
+
+      await expect(service.search('test')).rejects.toThrow(EmbeddingsUnavailableError);
+      expect(mockIndexer.close).toHaveBeenCalledOnce();
+    });
+
+    it('should merge synonym variants and record the terms that helped', async () => {
+      const credentials: SearchResult = {
+        id: 'doc3',
+        score: 0.9,
+        metadata: { name: 'checkCredentials', type: 'function', path: 'src/auth/check.ts' },
+      };
+      const search = vi.fn().mockImplementation(async (query: string) => {
+        if (query === 'auth') return mockSearchResults;
+        if (query === 'login') return [{ ...mockSearchResults[1], score: 0.97 }];
+        if (query === 'credentials') return [credentials];
+        return [];
+      });
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search,
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const results = await service.search('auth', { limit: 3, expand: true });
+
+      expect(results.map((r) => [r.id, r.score])).toEqual([
+        ['doc2', 0.97],
+        ['doc1', 0.95],
+        ['doc3', 0.9],
+      ]);
+      expect(results[0].metadata.expandedTerms).toEqual(['login']);
+      expect(results[1].metadata.expandedTerms).toBeUndefined();
+      expect(results[2].metadata.expandedTerms).toEqual(['credentials']);
+      expect(search).toHaveBeenCalledTimes(5);
+    });
+
+    it('should break near ties toward documented symbols unless disabled', async () => {
+      const documented: SearchResult = {
+        ...mockSearchResults[1],
+        score: 0.94,
+        metadata: {
+          ...mockSearchResults[1].metadata,
+          exported: true,
+          docstring: 'login checks credentials against the user store and starts a session.',
+        },
+      };
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue([mockSearchResults[0], documented]),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const boosted = await service.search('auth', { docWeight: 0.05 });
+      expect(boosted.map((r) => r.id)).toEqual(['doc2', 'doc1']);
+      expect(boosted[0].score).toBe(0.94);
+
+      const plain = await service.search('auth', { docWeight: 0 });
+      expect(plain.map((r) => r.id)).toEqual(['doc1', 'doc2']);
+    });
+
+    it('should explain scores with debug on', async () => {
+      const documented: SearchResult = {
+        ...mockSearchResults[1],
+        score: 0.94,
+        metadata: {
+          ...mockSearchResults[1].metadata,
+          exported: true,
+          docstring: 'login checks credentials against the user store and starts a session.',
+        },
+      };
+      const search = vi.fn().mockImplementation(async (query: string) =>
+        query === 'getUser' ? [mockSearchResults[0], documented] : []
+      );
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search,
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const results = await service.search('getUser', { docWeight: 0.05, debug: true });
+
+      expect(results.map((r) => r.id)).toEqual(['doc2', 'doc1']);
+      expect(results[0].metadata.scoreDebug).toEqual({
+        vectorScore: 0.94,
+        keywordScore: null,
+        docBoost: expect.any(Number),
+        finalScore: expect.any(Number),
+        vectorRank: 2,
+        rank: 1,
+        matchedQuery: 'getUser',
+        embeddedQuery: 'getUser (get user)',
+        sort: 'relevance',
+      });
+      const debug = results[0].metadata.scoreDebug;
+      expect(debug?.finalScore).toBeCloseTo(0.94 + (debug?.docBoost ?? 0));
+      expect(results[1].metadata.scoreDebug).toMatchObject({ docBoost: 0, vectorRank: 1, rank: 2 });
+
+      const plain = await service.search('getUser', { docWeight: 0.05 });
+      expect(plain[0].metadata.scoreDebug).toBeUndefined();
+    });
+
+    it('should drop results whose final score is below minScore', async () => {
+      const weak: SearchResult = { ...mockSearchResults[1], id: 'weak', score: 0.25 };
+      const documented: SearchResult = {
+        ...weak,
+        id: 'documented',
+        metadata: {
+          ...weak.metadata,
+          exported: true,
+          docstring: 'login checks credentials against the user store and starts a session.',
+        },
+      };
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue([mockSearchResults[0], weak, documented]),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const strong = await service.search('auth', { minScore: 0.9, docWeight: 0 });
+      expect(strong.map((r) => r.id)).toEqual(['doc1']);
+
+      // The doc boost counts toward the cutoff
+      const fused = await service.search('auth', { minScore: 0.26, docWeight: 0.05 });
+      expect(fused.map((r) => r.id)).toEqual(['doc1', 'documented']);
+
+      expect(await service.search('auth', { minScore: 0.99 })).toEqual([]);
+      expect(await service.search('auth')).toHaveLength(3);
+    });
+
+    it('should report how many candidates the minScore cutoff dropped', async () => {
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue(mockSearchResults),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+      const onMinScoreCutoff = vi.fn();
+
+      await service.search('auth', { minScore: 0.99, docWeight: 0, onMinScoreCutoff });
+      expect(onMinScoreCutoff).toHaveBeenLastCalledWith(2);
+
+      // Nothing retrieved, so nothing was cut for being weak
+      vi.mocked(mockIndexer.search).mockResolvedValue([]);
+      await service.search('auth', { minScore: 0.99, onMinScoreCutoff });
+      expect(onMinScoreCutoff).toHaveBeenLastCalledWith(0);
+    });
+
+    it('should boost results containing rare query tokens with a keyword weight', async () => {
+      const close: SearchResult = { ...mockSearchResults[1], score: 0.93 };
+      const filler = ['Server', 'Config', 'Store', 'Cache', 'Queue', 'Router', 'Logger', 'Pool'];
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue([mockSearchResults[0], close]),
+        getAll: vi
+          .fn()
+          .mockResolvedValue([
+            ...mockSearchResults,
+            ...filler.map((name) => ({ id: name, score: 1, metadata: { name } })),
+          ]),
+        getIndexVersion: vi.fn().mockReturnValue('v1'),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const plain = await service.search('login', { docWeight: 0 });
+      expect(plain.map((r) => r.id)).toEqual(['doc1', 'doc2']);
+      expect(mockIndexer.getAll).not.toHaveBeenCalled();
+
+      const results = await service.search('login', {
+        docWeight: 0,
+        keywordWeight: 0.05,
+        debug: true,
+      });
+
+      expect(results.map((r) => r.id)).toEqual(['doc2', 'doc1']);
+      expect(results[0].score).toBe(0.93);
+      expect(results[0].metadata.scoreDebug).toMatchObject({
+        keywordScore: 1,
+        keywordBoost: 0.05,
+        finalScore: expect.closeTo(0.98),
+        keywordWeights: { login: expect.any(Number) },
+      });
+      expect(results[1].metadata.scoreDebug).toMatchObject({ keywordScore: 0, keywordBoost: 0 });
+    });
+
+    it('should keep only results satisfying identifier constraints', async () => {
+      const validate: SearchResult = {
+        id: 'doc3',
+        score: 0.8,
+        metadata: {
+          name: 'checkPassword',
+          type: 'function',
+          path: 'src/auth/check.ts',
+          snippet: 'return validatePassword(user, password);',
+        },
+      };
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue([...mockSearchResults, validate]),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const required = await service.search('password validation', {
+        limit: 2,
+        mustContain: ['ValidatePassword'],
+      });
+      expect(required.map((r) => r.id)).toEqual(['doc3']);
+      // Candidates are over-fetched to make up for the ones dropped
+      expect(mockIndexer.search).toHaveBeenCalledWith(
+        'password validation',
+        expect.objectContaining({ limit: 10 })
+      );
+
+      const excluded = await service.search('password validation', {
+        mustNotContain: ['credentials', 'password'],
+      });
+      expect(excluded.map((r) => r.id)).toEqual(['doc1']);
+    });
+
+    it('should order the top matches by recency on request', async () => {
+      const dated = (result: SearchResult, lastModified?: string): SearchResult => ({
+        ...result,
+        metadata: { ...result.metadata, lastModified },
+      });
+      const matches = [
+        dated(mockSearchResults[0]),
+        dated(mockSearchResults[1], '2024-01-01T00:00:00.000Z'),
+        dated({ ...mockSearchResults[1], id: 'doc3' }, '2024-06-01T00:00:00.000Z'),
+      ];
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue(matches),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService({ repositoryPath: '/test/repo' }, async () => mockIndexer);
+
+      const results = await service.search('auth', {
+        changedSince: '2023-01-01',
+        sort: 'recency',
+      });
+
+      expect(results.map((r) => r.id)).toEqual(['doc3', 'doc2', 'doc1']);
+      expect(mockIndexer.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ changedSince: '2023-01-01' })
+      );
+
+      // The limit keeps the strongest matches; recency only reorders them
+      const top = await service.search('auth', { limit: 2, sort: 'recency' });
+      expect(top.map((r) => r.id)).toEqual(['doc2', 'doc1']);
+    });
+  });
+
+  describe('searchBatch', () => {
+    it('should run every query against one indexer', async () => {
+      const mockIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi
+          .fn()
+          .mockImplementation(async (query: string) =>
+            mockSearchResults.filter((r) => r.metadata.name === query)
+          ),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const mockFactory = vi.fn().mockResolvedValue(mockIndexer);
+      const service = new SearchService({ repositoryPath: '/test/repo' }, mockFactory);
+
+      const results = await service.searchBatch(['login', 'missing', 'authenticate']);
+
+      expect(results.map((r) => r.map((result) => result.id))).toEqual([['doc2'], [], ['doc1']]);
+      expect(mockFactory).toHaveBeenCalledOnce();
+      expect(mockIndexer.close).toHaveBeenCalledOnce();
+    });
+
+    it('should not open the index for an empty batch', async () => {
+      const mockFactory = vi.fn();
+      const service = new SearchService({ repositoryPath: '/test/repo' }, mockFactory);
+
+      expect(await service.searchBatch([])).toEqual([]);
+      expect(mockFactory).not.toHaveBeenCalled();
+    });
   });
 
   describe('findSimilar', () => {
@@ -174,6 +494,366 @@ describe('SearchService', () => {
     });
   });
 
+  describe('findSimilarCode', () => {
+    const retry: SearchResult = {
+      id: 'retry.go:retryRequest:10',
+      score: 1,
+      metadata: {
+        name: 'retryRequest',
+        type: 'function',
+        path: 'client/retry.go',
+        startLine: 10,
+        snippet: 'func retryRequest() error {\n\treturn do(3)\n}',
+      },
+    };
+    const neighbors: SearchResult[] = [
+      retry,
+      {
+        id: 'copy.go:retryCall:4',
+        score: 0.88,
+        metadata: {
+          name: 'retryCall',
+          type: 'function',
+          path: 'server/copy.go',
+          snippet: 'func retryRequest() error { return do(3) } // copied',
+        },
+      },
+      {
+        id: 'README.md:Retries:1',
+        score: 0.86,
+        metadata: { name: 'Retries', type: 'documentation', path: 'README.md' },
+      },
+      {
+        id: 'backoff.go:Backoff:7',
+        score: 0.8,
+        metadata: { name: 'Backoff', type: 'function', path: 'client/backoff.go' },
+      },
+    ];
+
+    function createIndexer(): RepositoryIndexer {
+      return {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        getAll: vi.fn().mockResolvedValue([retry, neighbors[3]]),
+        searchByDocumentId: vi.fn().mockResolvedValue(neighbors),
+        search: vi.fn().mockResolvedValue(neighbors.slice(1)),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+    }
+
+    it('should reuse the stored vector for an indexed symbol', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const result = await service.findSimilarCode(
+        { symbol: 'retryRequest' },
+        { limit: 5, threshold: 0.7 }
+      );
+
+      expect(mockIndexer.initialize).toHaveBeenCalledWith({ skipEmbedder: true });
+      expect(mockIndexer.searchByDocumentId).toHaveBeenCalledWith(retry.id, {
+        limit: 11,
+        scoreThreshold: 0.7,
+      });
+      expect(mockIndexer.search).not.toHaveBeenCalled();
+      expect(result.reference?.id).toBe(retry.id);
+      expect(result.nearIdentical.map((m) => m.metadata.name)).toEqual(['retryCall']);
+      expect(result.related.map((m) => m.metadata.name)).toEqual(['Backoff']);
+    });
+
+    it('should embed a snippet', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const result = await service.findSimilarCode({ snippet: 'return do(3)' }, { limit: 1 });
+
+      expect(mockIndexer.search).toHaveBeenCalledWith('return do(3)', expect.any(Object));
+      expect(result.reference).toBeNull();
+      expect([...result.nearIdentical, ...result.related]).toHaveLength(1);
+    });
+
+    it('should return no matches for an unknown symbol', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const result = await service.findSimilarCode({ symbol: 'missing' });
+
+      expect(result).toEqual({ reference: null, nearIdentical: [], related: [] });
+      expect(mockIndexer.searchByDocumentId).not.toHaveBeenCalled();
+      expect(mockIndexer.close).toHaveBeenCalledOnce();
+    });
+  });
+
+  describe('searchExamples', () => {
+    const handler = (name: string, score = 1): SearchResult => ({
+      id: `api/${name}.go:${name}:1`,
+      score,
+      metadata: { name, type: 'function', path: `api/${name}.go` },
+    });
+    const [createUser, deleteUser, listUsers, health] = [
+      handler('CreateUser'),
+      handler('DeleteUser'),
+      handler('ListUsers', 0.9),
+      handler('Health', 0.8),
+    ];
+
+    function createIndexer(): RepositoryIndexer {
+      return {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        getAll: vi.fn().mockResolvedValue([createUser, deleteUser, listUsers, health]),
+        getDocumentVector: vi.fn().mockImplementation(async (id: string) =>
+          id === createUser.id ? [1, 0] : [0, 1]
+        ),
+        embedQuery: vi.fn().mockResolvedValue([0, 2]),
+        searchByVector: vi.fn().mockResolvedValue([createUser, deleteUser, listUsers, health]),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+    }
+
+    it('should mean-pool stored vectors and leave the examples out', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo', docWeight: 0 },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const result = await service.searchExamples({
+        symbols: ['CreateUser', deleteUser.id, 'Missing'],
+      });
+
+      expect(mockIndexer.initialize).toHaveBeenCalledWith({ skipEmbedder: true });
+      expect(mockIndexer.searchByVector).toHaveBeenCalledWith(
+        [0.5, 0.5],
+        expect.objectContaining({ limit: 12 })
+      );
+      expect(result.inputs.map((i) => i.metadata.name)).toEqual(['CreateUser', 'DeleteUser']);
+      expect(result.unresolved).toEqual(['Missing']);
+      expect(result.results.map((r) => r.metadata.name)).toEqual(['ListUsers', 'Health']);
+    });
+
+    it('should keep the examples on request', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo', docWeight: 0 },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const result = await service.searchExamples(
+        { symbols: ['CreateUser'] },
+        { excludeInputs: false, limit: 2 }
+      );
+
+      expect(result.results.map((r) => r.metadata.name)).toEqual(['CreateUser', 'DeleteUser']);
+    });
+
+    it('should embed queries and search each example separately', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      await service.searchExamples(
+        { queries: ['create a user'], symbols: ['CreateUser'] },
+        { pooling: 'separate' }
+      );
+
+      expect(mockIndexer.initialize).not.toHaveBeenCalledWith({ skipEmbedder: true });
+      expect(mockIndexer.embedQuery).toHaveBeenCalledWith('create a user');
+      expect(mockIndexer.searchByVector).toHaveBeenCalledTimes(2);
+      expect(mockIndexer.searchByVector).toHaveBeenCalledWith([0, 2], expect.any(Object));
+      expect(mockIndexer.searchByVector).toHaveBeenCalledWith([1, 0], expect.any(Object));
+    });
+
+    it('should require an example', async () => {
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(createIndexer())
+      );
+
+      await expect(service.searchExamples({})).rejects.toThrow('At least one query or symbol');
+    });
+  });
+
+  describe('relevance feedback', () => {
+    const [authenticate, login] = mockSearchResults;
+
+    function createIndexer(): RepositoryIndexer {
+      return {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        getAll: vi.fn().mockResolvedValue(mockSearchResults),
+        getDocumentVector: vi.fn().mockImplementation(async (id: string) =>
+          id === authenticate.id ? [0, 2] : [1, 0]
+        ),
+        search: vi.fn().mockResolvedValue([authenticate, login]),
+        embedQuery: vi.fn().mockResolvedValue([1, 0]),
+        searchByVector: vi.fn().mockResolvedValue([login, authenticate]),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+    }
+
+    it('should adjust the query vector of later searches in the same session', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo', docWeight: 0 },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const marked = await service.recordFeedback('explore-auth', 'authenticate');
+      expect(marked?.id).toBe(authenticate.id);
+      expect(service.getFeedback('explore-auth')).toEqual({
+        relevant: [authenticate.id],
+        nonRelevant: [],
+      });
+
+      const results = await service.search('auth', { session: 'explore-auth', debug: true });
+
+      expect(mockIndexer.search).not.toHaveBeenCalled();
+      const [vector] = vi.mocked(mockIndexer.searchByVector).mock.calls[0];
+      expect(vector[0]).toBeCloseTo(0.8);
+      expect(vector[1]).toBeCloseTo(0.6);
+      expect(results[0].metadata.scoreDebug?.feedback).toEqual({ relevant: 1, nonRelevant: 0 });
+    });
+
+    it('should search normally for other sessions and after clearing', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+      await service.recordFeedback('explore-auth', login.id, false);
+
+      await service.search('auth', { session: 'other' });
+      expect(service.clearFeedback('explore-auth')).toBe(true);
+      await service.search('auth', { session: 'explore-auth' });
+
+      expect(mockIndexer.search).toHaveBeenCalledTimes(2);
+      expect(mockIndexer.searchByVector).not.toHaveBeenCalled();
+    });
+
+    it('should return null for a symbol that is not indexed', async () => {
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(createIndexer())
+      );
+
+      expect(await service.recordFeedback('explore-auth', 'Missing')).toBeNull();
+      expect(service.getFeedback('explore-auth').relevant).toEqual([]);
+    });
+  });
+
+  describe('reranking', () => {
+    const candidates: SearchResult[] = ['a', 'b', 'c', 'd'].map((name, index) => ({
+      id: name,
+      score: 0.9 - index * 0.05,
+      metadata: { name, type: 'function', path: `src/${name}.ts`, signature: `function ${name}()` },
+    }));
+
+    function createIndexer(): RepositoryIndexer {
+      return {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        search: vi.fn().mockResolvedValue(candidates),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+    }
+
+    // Scores `c` highest, then `d`; the text starts with the kind and name
+    const rerankScores: Record<string, number> = { 'function c': 0.9, 'function d': 0.8 };
+    const reranker = {
+      modelName: 'test-cross-encoder',
+      initialize: vi.fn().mockResolvedValue(undefined),
+      score: vi.fn(async (_query: string, documents: string[]) =>
+        documents.map((text) => rerankScores[text.split('\n')[0]] ?? 0.1)
+      ),
+    };
+
+    it('should reorder the top candidates and keep the limit', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo', reranker, rerankCandidates: 4 },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const results = await service.search('auth', { limit: 2 });
+
+      expect(mockIndexer.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ limit: 4 })
+      );
+      expect(results.map((r) => r.id)).toEqual(['c', 'd']);
+      expect(results[0].score).toBeCloseTo(0.8);
+      expect(results[0].metadata.rerankScore).toBe(0.9);
+    });
+
+    it('should report ranks before and after reranking with debug on', async () => {
+      const service = new SearchService(
+        { repositoryPath: '/test/repo', reranker, rerankCandidates: 4 },
+        vi.fn().mockResolvedValue(createIndexer())
+      );
+
+      const results = await service.search('auth', { limit: 4, debug: true });
+
+      expect(results.map((r) => r.metadata.scoreDebug?.rerank)).toEqual([
+        { model: 'test-cross-encoder', score: 0.9, preRank: 3, postRank: 1 },
+        { model: 'test-cross-encoder', score: 0.8, preRank: 4, postRank: 2 },
+        { model: 'test-cross-encoder', score: 0.1, preRank: 1, postRank: 3 },
+        { model: 'test-cross-encoder', score: 0.1, preRank: 2, postRank: 4 },
+      ]);
+      expect(results[0].metadata.scoreDebug).toMatchObject({ vectorRank: 3, rank: 1, docBoost: 0 });
+    });
+
+    it('should skip reranking when turned off per search', async () => {
+      const mockIndexer = createIndexer();
+      const service = new SearchService(
+        { repositoryPath: '/test/repo', reranker, docWeight: 0 },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+
+      const results = await service.search('auth', { limit: 2, rerank: false });
+
+      expect(mockIndexer.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ limit: 2 })
+      );
+      expect(results.map((r) => r.id)).toEqual(['a', 'b']);
+    });
+
+    it('should keep the vector order when the reranker fails', async () => {
+      const warn = vi.fn();
+      const failing = {
+        modelName: 'missing-model',
+        initialize: vi.fn().mockRejectedValue(new Error('download failed')),
+        score: vi.fn(),
+      };
+      const service = new SearchService(
+        {
+          repositoryPath: '/test/repo',
+          reranker: failing,
+          docWeight: 0,
+          logger: { warn } as unknown as Logger,
+        },
+        vi.fn().mockResolvedValue(createIndexer())
+      );
+
+      const results = await service.search('auth', { limit: 2 });
+
+      expect(results.map((r) => r.id)).toEqual(['a', 'b']);
+      expect(failing.score).not.toHaveBeenCalled();
+      expect(warn).toHaveBeenCalledWith(
+        { model: 'missing-model', error: 'download failed' },
+        'Reranking failed; results keep their vector order'
+      );
+    });
+  });
+
   describe('findRelatedTests', () => {
     it('should find test files for a source file', async () => {
       const testResults: SearchResult[] = [
@@ -239,6 +919,57 @@ describe('SearchService', () => {
     });
   });
 
+  describe('lookupSymbol', () => {
+    const indexed: SearchResult[] = [
+      { id: 'a', score: 1, metadata: { name: 'ExpBackoff', type: 'class', path: 'retry/b.go' } },
+      {
+        id: 'b',
+        score: 1,
+        metadata: { name: 'ExpBackoff.Success', type: 'method', path: 'retry/b.go' },
+      },
+      { id: 'c', score: 1, metadata: { name: 'backoffDelay', type: 'function', path: 'net/d.go' } },
+      { id: 'd', score: 1, metadata: { name: 'Connection', type: 'class', path: 'net/c.go' } },
+    ];
+
+    function createService() {
+      const mockIndexer: RepositoryIndexer = {
+        initialize: vi.fn().mockResolvedValue(undefined),
+        getAll: vi.fn().mockResolvedValue(indexed),
+        close: vi.fn().mockResolvedValue(undefined),
+      } as unknown as RepositoryIndexer;
+      const service = new SearchService(
+        { repositoryPath: '/test/repo' },
+        vi.fn().mockResolvedValue(mockIndexer)
+      );
+      return { service, mockIndexer };
+    }
+
+    it('should rank fuzzy name matches without embeddings', async () => {
+      const { service, mockIndexer } = createService();
+
+      const results = await service.lookupSymbol('backof');
+
+      expect(mockIndexer.initialize).toHaveBeenCalledWith({ skipEmbedder: true });
+      expect(results.map((r) => r.metadata.name)).toEqual([
+        'backoffDelay',
+        'ExpBackoff',
+        'ExpBackoff.Success',
+      ]);
+      expect(results[0].score).toBeGreaterThan(results[1].score);
+      expect(mockIndexer.close).toHaveBeenCalledOnce();
+    });
+
+    it('should scope by kind and path', async () => {
+      const { service } = createService();
+
+      const byKind = await service.lookupSymbol('backof', { kind: 'class' });
+      expect(byKind.map((r) => r.metadata.name)).toEqual(['ExpBackoff']);
+
+      const byPath = await service.lookupSymbol('backof', { path: 'net/' });
+      expect(byPath.map((r) => r.metadata.name)).toEqual(['backoffDelay']);
+    });
+  });
+
   describe('findSymbol', () => {
     it('should find a symbol by exact name match', async () => {
       const mockIndexer: RepositoryIndexer = {
@@ -305,6 +1036,7 @@ describe('SearchService', () => {
       const result = await service.isIndexed();
 
       expect(result).toBe(true);
+      expect(mockIndexer.initialize).toHaveBeenCalledWith({ skipEmbedder: true });
     });
 
     it('should return false when repository is not indexed', async () => {
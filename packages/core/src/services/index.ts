@@ -36,8 +36,14 @@ export {
   type TypeAnnotationPattern,
 } from './pattern-analysis-service.js';
 export {
+  DEFAULT_MIN_SCORE,
+  type ExampleSearchOptions,
+  type ExampleSearchResult,
+  type LookupOptions,
+  type SearchExamples,
   SearchService,
   type SearchServiceConfig,
+  type SimilarCodeTarget,
   type SimilarityOptions,
 } from './search-service.js';
 export { StatsService, type StatsServiceConfig } from './stats-service.js';
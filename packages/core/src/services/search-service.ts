@@ -6,22 +6,221 @@
  */
 
 import type { Logger } from '@lytics/kero';
+import { collectCallGraph } from '../context/call-graph.js';
+import { collectCallPaths } from '../context/call-paths.js';
+import { collectConstraintUsages } from '../context/constraints.js';
+import { collectDefinitions } from '../context/definitions.js';
+import { collectDependencies } from '../context/dependencies.js';
+import { collectImplementations } from '../context/implementations.js';
+import { collectOpenApiOperations } from '../context/openapi.js';
+import { collectPackageOutline } from '../context/package-outline.js';
+import { collectRecursion } from '../context/recursion.js';
+import { collectRoutes } from '../context/routes.js';
+import { collectSqlQueries } from '../context/sql-queries.js';
+import { assembleSymbolContext } from '../context/symbol-context.js';
+import { collectSymbolExplanation } from '../context/symbol-explanation.js';
+import { SymbolGraphCache } from '../context/symbol-graph.js';
+import { collectSymbolInspection } from '../context/symbol-inspection.js';
+import { collectSymbolNeighbors } from '../context/symbol-neighbors.js';
+import { SymbolSourceReader } from '../context/symbol-source.js';
+import { collectSymbolTests } from '../context/symbol-tests.js';
+import { collectSymbolUsages } from '../context/symbol-usage.js';
+import type {
+  CallGraph,
+  CallGraphOptions,
+  CallPathOptions,
+  CallPaths,
+  ConstraintMap,
+  ConstraintOptions,
+  DefinitionOptions,
+  Definitions,
+  DependencyMap,
+  DependencyOptions,
+  ImplementationOptions,
+  InterfaceImplementations,
+  OpenApiMap,
+  OpenApiOptions,
+  PackageOutline,
+  PackageOutlineOptions,
+  RecursionOptions,
+  RecursionReport,
+  RouteMap,
+  RouteOptions,
+  SqlQueryMap,
+  SqlQueryOptions,
+  SymbolContext,
+  SymbolContextOptions,
+  SymbolExplanation,
+  SymbolExplanationOptions,
+  SymbolInspection,
+  SymbolInspectionOptions,
+  SymbolNeighborOptions,
+  SymbolNeighbors,
+  SymbolTestOptions,
+  SymbolTests,
+  SymbolUsageOptions,
+  SymbolUsages,
+} from '../context/types.js';
 import type { RepositoryIndexer } from '../indexer/index.js';
-import type { SearchResult, SearchOptions as VectorSearchOptions } from '../vector/types.js';
+import { DEFAULT_DOC_WEIGHT, rankByDocQuality } from '../search/doc-quality.js';
+import { annotateIdentifiers, matchesIdentifiers } from '../search/identifiers.js';
+import { type KeywordWeightsConfig, KeywordWeightsCache } from '../search/keyword-weights.js';
+import { type ExamplePooling, mergeRankings, poolVectors } from '../search/multi-vector.js';
+import { expandQuery } from '../search/query-expansion.js';
+import { applyRocchio, RelevanceFeedback } from '../search/relevance-feedback.js';
+import { DEFAULT_RERANK_CANDIDATES, rerankResults } from '../search/rerank.js';
+import { classifySimilarCode, NEAR_IDENTICAL_THRESHOLD } from '../similarity/index.js';
+import type { SimilarCodeOptions, SimilarCodeResult } from '../similarity/types.js';
+import { rankFuzzyMatches } from '../utils/fuzzy.js';
+import type {
+  RerankProvider,
+  SearchResult,
+  SearchScoreDebug,
+  SearchOptions as VectorSearchOptions,
+} from '../vector/types.js';
 
 export interface SearchServiceConfig {
   repositoryPath: string;
   logger?: Logger;
+  /** Default doc quality boost for search ranking (default: 0.02; 0 disables) */
+  docWeight?: number;
+  /**
+   * Reorders the top search candidates, e.g. a TransformersReranker cross-encoder
+   * (default: none). Slower than vector search alone, so off unless configured.
+   */
+  reranker?: RerankProvider;
+  /** Top candidates the reranker reorders per search (default: 20) */
+  rerankCandidates?: number;
+  /**
+   * Default keyword boost for a result matching all of a query's weighted tokens
+   * (default: 0, off; see search/keyword-weights.ts)
+   */
+  keywordWeight?: number;
+  /** Stopwords and per-token boosts for keyword weighting */
+  keywords?: KeywordWeightsConfig;
+}
+
+export interface SearchOptions extends VectorSearchOptions {
+  /** Also search synonym variants of the query (see expandQuery) */
+  expand?: boolean;
+  /** Order of the top matches: by score (default) or most recently modified first */
+  sort?: 'relevance' | 'recency';
+  /** Doc quality boost for this search (default: the service's docWeight) */
+  docWeight?: number;
+  /** Keyword boost for this search (default: the service's keywordWeight) */
+  keywordWeight?: number;
+  /** Attach a score breakdown to each result in `metadata.scoreDebug` */
+  debug?: boolean;
+  /** Rerank the top candidates with the service's reranker (default: true when configured) */
+  rerank?: boolean;
+  /**
+   * Include symbols from generated files, e.g. mocks (default: false). A
+   * `generated: true` filter searches only generated code.
+   */
+  includeGenerated?: boolean;
+  /**
+   * Adjust the query toward results this session marked relevant, and away
+   * from ones it marked not relevant (see recordFeedback)
+   */
+  session?: string;
+  /**
+   * Drop results whose final score (score plus doc boost) is below this
+   * (default: none). Scores run 0-1 for cosine indexes; see DEFAULT_MIN_SCORE.
+   */
+  minScore?: number;
+  /**
+   * Called with how many ranked candidates the `minScore` cutoff dropped,
+   * so callers can tell "all too weak" from "nothing matched the filters"
+   */
+  onMinScoreCutoff?: (dropped: number) => void;
+  /**
+   * Identifiers every result must contain in its name, signature, or code,
+   * matched by identifier tokens (see search/identifiers.ts)
+   */
+  mustContain?: string[];
+  /** Identifiers no result may contain */
+  mustNotContain?: string[];
+}
+
+/**
+ * Candidates fetched per result when identifier constraints drop some after retrieval
+ */
+const CONSTRAINT_OVERFETCH = 5;
+
+/**
+ * Suggested minScore for tools: below it, matches are rarely relevant
+ *
+ * Cosine scores are `exp(-(2 * (1 - similarity))^2)`, so 0.3 keeps anything
+ * with a cosine similarity above about 0.45; strong matches score 0.8 and up.
+ */
+export const DEFAULT_MIN_SCORE = 0.3;
+
+/**
+ * Examples for a multi-vector search: query texts, indexed symbols, or both
+ */
+export interface SearchExamples {
+  queries?: string[];
+  /** Symbol names (e.g. "Server.CreateUser") or document IDs */
+  symbols?: string[];
 }
 
-// Re-export SearchOptions from vector types for convenience
-export type SearchOptions = VectorSearchOptions;
+export interface ExampleSearchOptions
+  extends Pick<
+    SearchOptions,
+    | 'limit'
+    | 'scoreThreshold'
+    | 'filter'
+    | 'changedSince'
+    | 'pathFilter'
+    | 'sort'
+    | 'docWeight'
+    | 'minScore'
+    | 'onMinScoreCutoff'
+    | 'includeGenerated'
+    | 'mustContain'
+    | 'mustNotContain'
+  > {
+  /** How example vectors are combined (default: 'mean'; see search/multi-vector.ts) */
+  pooling?: ExamplePooling;
+  /** Leave the example symbols out of the results (default: true) */
+  excludeInputs?: boolean;
+}
+
+export interface ExampleSearchResult {
+  results: SearchResult[];
+  /** Indexed symbols the examples resolved to, in example order */
+  inputs: SearchResult[];
+  /** Symbol examples with no indexed match */
+  unresolved: string[];
+}
 
 export interface SimilarityOptions {
   limit?: number;
   threshold?: number;
 }
 
+export interface LookupOptions {
+  limit?: number;
+  /** Only match symbols of this type (function, method, class, ...) */
+  kind?: string;
+  /** Only match symbols under this path prefix */
+  path?: string;
+  /** Minimum fuzzy match score (0-1, default: 0.3) */
+  minScore?: number;
+}
+
+/**
+ * What to find similar code for: an indexed symbol or a raw snippet
+ */
+export interface SimilarCodeTarget {
+  /** Indexed symbol name (e.g. "retryRequest" or "Client.Do") */
+  symbol?: string;
+  /** Path prefix to disambiguate symbols with the same name */
+  path?: string;
+  /** Code snippet to embed when there is no indexed symbol */
+  snippet?: string;
+}
+
 export interface IndexerFactoryConfig {
   repositoryPath: string;
   vectorStorePath: string;
@@ -31,6 +230,14 @@ export interface IndexerFactoryConfig {
   languages?: string[];
 }
 
+/**
+ * Stored vectors of the results a session marked, for the Rocchio adjustment
+ */
+interface FeedbackVectors {
+  relevant: number[][];
+  nonRelevant: number[][];
+}
+
 /**
  * Factory function for creating RepositoryIndexer instances
  */
@@ -45,11 +252,26 @@ export type IndexerFactory = (config: IndexerFactoryConfig) => Promise<Repositor
 export class SearchService {
   private repositoryPath: string;
   private logger?: Logger;
+  private docWeight: number;
+  private reranker?: RerankProvider;
+  private rerankCandidates: number;
+  private keywordWeight: number;
+  /** IDF token weights, rebuilt when the index changes */
+  private keywordWeights: KeywordWeightsCache;
   private createIndexer: IndexerFactory;
+  /** Call graph kept across calls and patched as files are re-indexed */
+  private symbolGraphs = new SymbolGraphCache();
+  /** Relevance feedback per session, in memory only */
+  private feedback = new RelevanceFeedback();
 
   constructor(config: SearchServiceConfig, createIndexer?: IndexerFactory) {
     this.repositoryPath = config.repositoryPath;
     this.logger = config.logger;
+    this.docWeight = config.docWeight ?? DEFAULT_DOC_WEIGHT;
+    this.reranker = config.reranker;
+    this.rerankCandidates = config.rerankCandidates ?? DEFAULT_RERANK_CANDIDATES;
+    this.keywordWeight = config.keywordWeight ?? 0;
+    this.keywordWeights = new KeywordWeightsCache(config.keywords);
 
     // Use provided factory or default implementation
     this.createIndexer = createIndexer || this.defaultIndexerFactory.bind(this);
@@ -76,6 +298,7 @@ export class SearchService {
   private async getIndexer(options?: {
     excludePatterns?: string[];
     languages?: string[];
+    skipEmbedder?: boolean;
   }): Promise<RepositoryIndexer> {
     const { getStoragePath, getStorageFilePaths } = await import('../storage/path.js');
     const storagePath = await getStoragePath(this.repositoryPath);
@@ -90,30 +313,440 @@ export class SearchService {
       languages: options?.languages,
     });
 
-    await indexer.initialize();
+    try {
+      await indexer.initialize(options?.skipEmbedder ? { skipEmbedder: true } : undefined);
+    } catch (error) {
+      // e.g. EmbeddingsUnavailableError: release the store before passing it on
+      await indexer.close().catch(() => {});
+      throw error;
+    }
     return indexer;
   }
 
+  /**
+   * Reader for symbol source in the working tree, checked against indexed hashes
+   * One per request, so each file is read at most once per tool call
+   */
+  private sourceReader(indexer: RepositoryIndexer): SymbolSourceReader {
+    return new SymbolSourceReader(this.repositoryPath, {
+      indexedHash: (file) => indexer.getFileHash(file),
+      logger: this.logger,
+    });
+  }
+
   /**
    * Perform semantic code search
    *
+   * With `expand`, synonym variants of the query are searched too and each
+   * result keeps its best score. Results a variant ranked higher list the
+   * synonyms responsible in `metadata.expandedTerms`.
+   *
+   * `changedSince` and `sort: 'recency'` rely on blame data recorded at index
+   * time (`blame: true`); symbols without it are excluded or sorted last.
+   *
+   * Near ties are broken toward well-documented exported symbols by a small
+   * `docWeight` boost (see rankByDocQuality); scores stay purely semantic.
+   * With a `keywordWeight`, results matching the query's distinctive tokens
+   * get a boost too, each token weighted by its rarity in the index (see
+   * search/keyword-weights.ts).
+   *
+   * With `debug`, each result carries `metadata.scoreDebug`: the vector
+   * score, boosts, final ordering score, and the query text that matched.
+   *
+   * With `session`, the query vector is adjusted by that session's
+   * relevance feedback (see recordFeedback).
+   *
+   * Symbols from generated files (`metadata.generated`, e.g. mocks) are left
+   * out unless `includeGenerated` is set or the filter names `generated`.
+   *
+   * `mustContain`/`mustNotContain` keep semantic ranking but require (or rule
+   * out) identifiers in each result, checked after retrieval against the
+   * symbol's identifier tokens, camelCase components included.
+   *
    * @param query - Search query string
-   * @param options - Search options (limit, scoreThreshold)
+   * @param options - Search options (limit, scoreThreshold, filter, changedSince, pathFilter,
+   * expand, sort, session)
    * @returns Array of search results
    */
   async search(query: string, options?: SearchOptions): Promise<SearchResult[]> {
     const indexer = await this.getIndexer();
     try {
-      const results = await indexer.search(query, {
-        limit: options?.limit ?? 10,
-        scoreThreshold: options?.scoreThreshold ?? 0.7,
-      });
+      return await this.searchWith(indexer, query, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Run several searches against one indexer
+   *
+   * Opening the index and loading the embedding model happen once for the
+   * whole batch, which matters when a tool resolves many symbols per call.
+   *
+   * @param queries - Search queries
+   * @param options - Options applied to every query (see search)
+   * @returns Results per query, in query order
+   */
+  async searchBatch(queries: string[], options?: SearchOptions): Promise<SearchResult[][]> {
+    if (queries.length === 0) return [];
+    const indexer = await this.getIndexer();
+    try {
+      const results: SearchResult[][] = [];
+      for (const query of queries) {
+        results.push(await this.searchWith(indexer, query, options));
+      }
       return results;
     } finally {
       await indexer.close();
     }
   }
 
+  private async searchWith(
+    indexer: RepositoryIndexer,
+    query: string,
+    options?: SearchOptions
+  ): Promise<SearchResult[]> {
+    const limit = options?.limit ?? 10;
+    const reranker = options?.rerank === false ? undefined : this.reranker;
+    // The reranker picks from more candidates than are returned
+    const candidates = reranker ? Math.max(limit, this.rerankCandidates) : limit;
+    const constrained = hasIdentifierConstraints(options);
+    const fetched = constrained ? candidates * CONSTRAINT_OVERFETCH : candidates;
+    const searchOptions = {
+      limit: fetched,
+      scoreThreshold: options?.scoreThreshold ?? 0.7,
+      filter: options?.filter,
+      changedSince: options?.changedSince,
+      pathFilter: options?.pathFilter,
+      excludeGenerated: excludesGenerated(options),
+    };
+    const feedback = await this.feedbackVectors(indexer, options?.session);
+    let results = await this.searchQuery(indexer, query, searchOptions, feedback);
+    const matchedQuery = new Map(results.map((result) => [result.id, query]));
+
+    if (options?.expand) {
+      const best = new Map(results.map((result) => [result.id, result]));
+      for (const variant of expandQuery(query).variants) {
+        const variantResults = await this.searchQuery(
+          indexer,
+          variant.query,
+          searchOptions,
+          feedback
+        );
+        for (const result of variantResults) {
+          const current = best.get(result.id);
+          if (current && current.score >= result.score) continue;
+          const expandedTerms = [...(current?.metadata.expandedTerms ?? []), variant.synonym];
+          best.set(result.id, { ...result, metadata: { ...result.metadata, expandedTerms } });
+          matchedQuery.set(result.id, variant.query);
+        }
+      }
+      results = [...best.values()].sort((a, b) => b.score - a.score).slice(0, fetched);
+    }
+    if (constrained) {
+      results = results
+        .filter((result) => matchesIdentifiers(result.metadata, options ?? {}))
+        .slice(0, candidates);
+    }
+
+    const vectorRank = new Map(results.map((result, index) => [result.id, index + 1]));
+    const reranked = reranker ? await this.rerank(reranker, query, results) : undefined;
+    const rerankRank = new Map(reranked?.map((result, index) => [result.id, index + 1]));
+    // The reranker's order replaces the doc quality and keyword boosts, which only nudge
+    // the vector order
+    const keywordWeight = reranked ? 0 : (options?.keywordWeight ?? this.keywordWeight);
+    let keywords: { weights: Record<string, number>; scores: Map<string, number> } | undefined;
+    if (keywordWeight > 0) {
+      keywords = await this.keywordMatches(indexer, query, results);
+      results = results.map((result) => {
+        const keywordBoost = keywordWeight * (keywords?.scores.get(result.id) ?? 0);
+        return keywordBoost > 0
+          ? { ...result, metadata: { ...result.metadata, keywordBoost } }
+          : result;
+      });
+    }
+    results = this.rank(
+      reranked ?? results,
+      limit,
+      reranked ? { ...options, docWeight: 0 } : options
+    );
+    const sort = options?.sort ?? 'relevance';
+
+    if (!options?.debug) return results;
+    return results.map((result, index) => {
+      const docBoost = result.metadata.docBoost ?? 0;
+      const matched = matchedQuery.get(result.id) ?? query;
+      const scoreDebug: SearchScoreDebug = {
+        vectorScore: result.score,
+        keywordScore: keywords?.scores.get(result.id) ?? null,
+        docBoost,
+        ...(keywords
+          ? { keywordBoost: result.metadata.keywordBoost ?? 0, keywordWeights: keywords.weights }
+          : {}),
+        finalScore: finalScore(result),
+        vectorRank: vectorRank.get(result.id) ?? index + 1,
+        rank: index + 1,
+        matchedQuery: matched,
+        embeddedQuery: annotateIdentifiers(matched),
+        sort,
+        ...(feedback
+          ? {
+              feedback: {
+                relevant: feedback.relevant.length,
+                nonRelevant: feedback.nonRelevant.length,
+              },
+            }
+          : {}),
+        ...(reranked && reranker
+          ? {
+              rerank: {
+                model: reranker.modelName,
+                score: result.metadata.rerankScore,
+                preRank: vectorRank.get(result.id) ?? index + 1,
+                postRank: rerankRank.get(result.id) ?? index + 1,
+              },
+            }
+          : {}),
+      };
+      return { ...result, metadata: { ...result.metadata, scoreDebug } };
+    });
+  }
+
+  /**
+   * Keyword scores of results against the query's token weights over the whole index
+   */
+  private async keywordMatches(
+    indexer: RepositoryIndexer,
+    query: string,
+    results: SearchResult[]
+  ): Promise<{ weights: Record<string, number>; scores: Map<string, number> }> {
+    const keywordWeights = await this.keywordWeights.get(indexer.getIndexVersion(), () =>
+      indexer.getAll({ limit: 100000 })
+    );
+    const weights = keywordWeights.weigh(query);
+    const scores = new Map(
+      results.map((result) => [result.id, keywordWeights.match(weights, result.metadata).score])
+    );
+    return { weights, scores };
+  }
+
+  /**
+   * Rerank the top candidates; a reranker that fails leaves the vector order
+   *
+   * @returns The reordered results, or undefined when reranking failed
+   */
+  private async rerank(
+    reranker: RerankProvider,
+    query: string,
+    results: SearchResult[]
+  ): Promise<SearchResult[] | undefined> {
+    try {
+      await reranker.initialize();
+      return await rerankResults(query, results, reranker, this.rerankCandidates);
+    } catch (error) {
+      this.logger?.warn(
+        { model: reranker.modelName, error: error instanceof Error ? error.message : error },
+        'Reranking failed; results keep their vector order'
+      );
+      return undefined;
+    }
+  }
+
+  /**
+   * Search with the embedded query, adjusted by session feedback when there is any
+   */
+  private async searchQuery(
+    indexer: RepositoryIndexer,
+    query: string,
+    options: SearchOptions,
+    feedback?: FeedbackVectors
+  ): Promise<SearchResult[]> {
+    if (!feedback) return indexer.search(query, options);
+    const vector = await indexer.embedQuery(query);
+    return indexer.searchByVector(
+      applyRocchio(vector, feedback.relevant, feedback.nonRelevant),
+      options
+    );
+  }
+
+  /**
+   * Stored vectors of the results a session gave feedback on, if any are still indexed
+   */
+  private async feedbackVectors(
+    indexer: RepositoryIndexer,
+    session?: string
+  ): Promise<FeedbackVectors | undefined> {
+    if (!session) return undefined;
+    const selected = this.feedback.get(session);
+    const load = async (ids: string[]) => {
+      const vectors: number[][] = [];
+      for (const id of ids) {
+        const vector = await indexer.getDocumentVector(id);
+        if (vector) vectors.push(vector);
+      }
+      return vectors;
+    };
+    const relevant = await load(selected.relevant);
+    const nonRelevant = await load(selected.nonRelevant);
+    return relevant.length + nonRelevant.length > 0 ? { relevant, nonRelevant } : undefined;
+  }
+
+  /**
+   * Mark a result relevant or not for a session
+   *
+   * Later searches passing the same `session` move their query vector toward
+   * relevant results and away from the rest (Rocchio; see
+   * search/relevance-feedback.ts). Feedback stays in this service's memory;
+   * the index is not changed.
+   *
+   * @param session - Caller-chosen session id
+   * @param target - Document ID or symbol name (e.g. "Server.CreateUser")
+   * @param relevant - False to mark the result not relevant (default: true)
+   * @returns The indexed symbol marked, or null if none matched
+   */
+  async recordFeedback(
+    session: string,
+    target: string,
+    relevant = true
+  ): Promise<SearchResult | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      const allDocs = await indexer.getAll({ limit: 100000 });
+      const doc = allDocs.find((d) => d.id === target) ?? pickSymbol(allDocs, target);
+      if (doc) this.feedback.record(session, doc.id, relevant);
+      return doc;
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Document IDs a session marked relevant and not relevant
+   */
+  getFeedback(session: string): { relevant: string[]; nonRelevant: string[] } {
+    return this.feedback.get(session);
+  }
+
+  /**
+   * Forget a session's feedback
+   *
+   * @returns True if the session had any
+   */
+  clearFeedback(session: string): boolean {
+    return this.feedback.clear(session);
+  }
+
+  /**
+   * Apply the doc quality boost, minScore cutoff, limit, and sort order to vector results
+   *
+   * The cutoff reports how many candidates it dropped to `onMinScoreCutoff`. The
+   * recency sort only reorders the top `limit`, so it never pulls in weaker matches.
+   */
+  private rank(
+    results: SearchResult[],
+    limit: number,
+    options?: Pick<SearchOptions, 'docWeight' | 'minScore' | 'onMinScoreCutoff' | 'sort'>
+  ): SearchResult[] {
+    let ranked = rankByDocQuality(results, options?.docWeight ?? this.docWeight);
+    if (ranked.some((result) => result.metadata.keywordBoost)) {
+      ranked = [...ranked].sort((a, b) => finalScore(b) - finalScore(a));
+    }
+    const minScore = options?.minScore;
+    if (minScore !== undefined && minScore > 0) {
+      const candidates = ranked.length;
+      ranked = ranked.filter((result) => finalScore(result) >= minScore);
+      options?.onMinScoreCutoff?.(candidates - ranked.length);
+    }
+    ranked = ranked.slice(0, limit);
+    return options?.sort === 'recency' ? sortByRecency(ranked) : ranked;
+  }
+
+  /**
+   * Search with several examples at once ("more code like these")
+   *
+   * Query texts are embedded; symbols reuse their stored vectors. The vectors
+   * are pooled into one query (`mean` or `max`) or searched one by one and
+   * merged (`separate`); see search/multi-vector.ts. Example symbols are
+   * left out of the results unless `excludeInputs` is false.
+   *
+   * @param examples - Query texts and/or symbol names or document IDs
+   * @param options - Pooling, input exclusion, and the usual search filters
+   * @returns Ranked results, the resolved example symbols, and symbols not found
+   */
+  async searchExamples(
+    examples: SearchExamples,
+    options?: ExampleSearchOptions
+  ): Promise<ExampleSearchResult> {
+    const queries = examples.queries ?? [];
+    const symbols = examples.symbols ?? [];
+    if (queries.length === 0 && symbols.length === 0) {
+      throw new Error('At least one query or symbol is required');
+    }
+
+    // Symbols alone need only stored vectors, not the embedding model
+    const indexer = await this.getIndexer({ skipEmbedder: queries.length === 0 });
+    try {
+      const inputs: SearchResult[] = [];
+      const unresolved: string[] = [];
+      if (symbols.length > 0) {
+        const allDocs = await indexer.getAll({ limit: 100000 });
+        for (const symbol of symbols) {
+          const input = allDocs.find((doc) => doc.id === symbol) ?? pickSymbol(allDocs, symbol);
+          if (input) {
+            inputs.push(input);
+          } else {
+            unresolved.push(symbol);
+          }
+        }
+      }
+
+      const vectors: number[][] = [];
+      for (const query of queries) {
+        vectors.push(await indexer.embedQuery(query));
+      }
+      for (const input of inputs) {
+        const vector = await indexer.getDocumentVector(input.id);
+        if (vector) vectors.push(vector);
+      }
+      if (vectors.length === 0) {
+        return { results: [], inputs, unresolved };
+      }
+
+      const limit = options?.limit ?? 10;
+      const excluded = new Set(options?.excludeInputs === false ? [] : inputs.map((i) => i.id));
+      const searchOptions = {
+        // Over-fetch to make room for the excluded examples and identifier constraints
+        limit:
+          (hasIdentifierConstraints(options) ? limit * CONSTRAINT_OVERFETCH : limit) +
+          excluded.size,
+        scoreThreshold: options?.scoreThreshold ?? 0.7,
+        filter: options?.filter,
+        changedSince: options?.changedSince,
+        pathFilter: options?.pathFilter,
+        excludeGenerated: excludesGenerated(options),
+      };
+
+      const pooling = options?.pooling ?? 'mean';
+      let results: SearchResult[];
+      if (pooling === 'separate') {
+        const rankings: SearchResult[][] = [];
+        for (const vector of vectors) {
+          rankings.push(await indexer.searchByVector(vector, searchOptions));
+        }
+        results = mergeRankings(rankings, searchOptions.limit);
+      } else {
+        results = await indexer.searchByVector(poolVectors(vectors, pooling), searchOptions);
+      }
+
+      results = results
+        .filter((result) => !excluded.has(result.id))
+        .filter((result) => matchesIdentifiers(result.metadata, options ?? {}));
+      return { results: this.rank(results, limit, options), inputs, unresolved };
+    } finally {
+      await indexer.close();
+    }
+  }
+
   /**
    * Find similar code to a specific file
    *
@@ -122,7 +755,8 @@ export class SearchService {
    * @returns Array of similar files with scores
    */
   async findSimilar(filePath: string, options?: SimilarityOptions): Promise<SearchResult[]> {
-    const indexer = await this.getIndexer();
+    // Stored vectors only, so no embedding model is needed
+    const indexer = await this.getIndexer({ skipEmbedder: true });
     try {
       // Step 1: Get all documents from the target file
       const allDocs = await indexer.getAll({ limit: 10000 });
@@ -148,6 +782,66 @@ export class SearchService {
     }
   }
 
+  /**
+   * Find symbols semantically similar to an indexed symbol or a code snippet
+   *
+   * Indexed symbols reuse their stored vector, so no embedding is computed;
+   * snippets are embedded. The reference symbol itself is excluded, and
+   * matches are split into near-identical copies and related code.
+   *
+   * @param target - Symbol name (optionally scoped by path) or snippet
+   * @param options - Similarity options (limit, threshold, nearIdenticalThreshold)
+   * @returns The resolved reference and classified matches
+   */
+  async findSimilarCode(
+    target: SimilarCodeTarget,
+    options?: SimilarCodeOptions
+  ): Promise<SimilarCodeResult> {
+    const limit = options?.limit ?? 10;
+    const searchOptions = {
+      // Over-fetch to make room for the reference and documentation entries
+      limit: limit * 2 + 1,
+      scoreThreshold: options?.threshold ?? 0.75,
+    };
+    const nearIdenticalThreshold = options?.nearIdenticalThreshold ?? NEAR_IDENTICAL_THRESHOLD;
+
+    const indexer = await this.getIndexer({ skipEmbedder: !!target.symbol });
+    try {
+      let reference: SearchResult | null = null;
+      let results: SearchResult[];
+
+      if (target.symbol) {
+        const allDocs = await indexer.getAll({ limit: 100000 });
+        reference = pickSymbol(allDocs, target.symbol, target.path);
+
+        if (!reference) {
+          this.logger?.warn({ symbol: target.symbol }, 'Symbol not found in index');
+          return { reference: null, nearIdentical: [], related: [] };
+        }
+        results = await indexer.searchByDocumentId(reference.id, searchOptions);
+      } else if (target.snippet) {
+        results = await indexer.search(target.snippet, searchOptions);
+      } else {
+        throw new Error('Either a symbol or a snippet is required');
+      }
+
+      const matches = results
+        .filter((r) => r.id !== reference?.id && r.metadata.type !== 'documentation')
+        .slice(0, limit);
+
+      return {
+        reference,
+        ...classifySimilarCode(
+          reference ? reference.metadata.snippet : target.snippet,
+          matches,
+          nearIdenticalThreshold
+        ),
+      };
+    } finally {
+      await indexer.close();
+    }
+  }
+
   /**
    * Find related test files for a source file
    *
@@ -223,6 +917,367 @@ export class SearchService {
     }
   }
 
+  /**
+   * Find where a symbol is defined, by exact name
+   *
+   * Uses the symbol graph's name index, so no embedding is computed and
+   * nothing fuzzy matches.
+   *
+   * @param name - Exact symbol name, optionally package-qualified (e.g. "store.Get")
+   * @param options - Test inclusion
+   * @returns Every definition of the name, or null if nothing has that name
+   */
+  async getDefinitions(name: string, options?: DefinitionOptions): Promise<Definitions | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectDefinitions(indexer, name, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * List the HTTP routes registered in the indexed code, grouped by package
+   *
+   * Each route links to its handler symbol and the handler's callees.
+   *
+   * @param options - Path prefix and method filters, test inclusion, limit
+   */
+  async getRoutes(options?: RouteOptions): Promise<RouteMap> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectRoutes(indexer, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * List the operations of indexed OpenAPI specs with the routes implementing them
+   *
+   * Uses stored spec and route metadata, so no embedding is computed.
+   *
+   * @param options - Path prefix, method, and tag filters, unlinked-only, limit
+   */
+  async getOpenApiOperations(options?: OpenApiOptions): Promise<OpenApiMap> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectOpenApiOperations(indexer, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * List the SQL queries in the indexed code and the tables they touch
+   *
+   * Uses stored query metadata, so no embedding is computed.
+   *
+   * @param options - Table, operation, and path filters, test inclusion, limit
+   */
+  async getSqlQueries(options?: SqlQueryOptions): Promise<SqlQueryMap> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectSqlQueries(indexer, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * List generic constraints and the generic functions and types using them
+   *
+   * Uses stored type set and constraint metadata, so no embedding is computed.
+   *
+   * @param options - Constraint name, test inclusion, limit
+   */
+  async getConstraintUsages(options?: ConstraintOptions): Promise<ConstraintMap> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectConstraintUsages(indexer, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Export the call graph around a symbol or across a package
+   *
+   * Uses stored call graph metadata, so no embedding is computed.
+   *
+   * @param options - Starting symbol or package, depth, direction, and filters
+   * @returns The graph, or null if the symbol or package isn't indexed
+   */
+  async getCallGraph(options: CallGraphOptions): Promise<CallGraph | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectCallGraph(indexer, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Find self-recursive and mutually recursive functions
+   *
+   * Uses stored call graph metadata, so no embedding is computed.
+   *
+   * @param options - Package, test inclusion, limit
+   * @returns The cycles, or null if the package isn't indexed
+   */
+  async getRecursion(options?: RecursionOptions): Promise<RecursionReport | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectRecursion(indexer, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * List the Go module dependencies and the indexed packages importing each
+   *
+   * Versions are read from the repository's go.mod files as they are now;
+   * importers come from stored imports, so no embedding is computed.
+   *
+   * @param options - Module filter, indirect and test inclusion, limit
+   */
+  async getDependencies(options?: DependencyOptions): Promise<DependencyMap> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectDependencies(indexer, this.repositoryPath, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Find call paths from one symbol to another, shortest first
+   *
+   * Uses stored call graph metadata, so no embedding is computed. Call lines
+   * are read from the current source where the file hasn't changed.
+   *
+   * @param from - Symbol to start from
+   * @param to - Symbol to reach
+   * @param options - Path limit, depth bound, test inclusion, disambiguation
+   * @returns The paths; `from` or `to` is null when that symbol isn't indexed
+   */
+  async getCallPaths(from: string, to: string, options?: CallPathOptions): Promise<CallPaths> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      const sources = this.sourceReader(indexer);
+      return await collectCallPaths(indexer, from, to, options, this.symbolGraphs, sources);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Find what's defined near a symbol or a file line
+   *
+   * Reads indexed line ranges and names, so no embedding is computed.
+   *
+   * @param options - Symbol, or file and line; neighbor counts
+   * @returns The neighbors, or null if the symbol or file isn't indexed
+   */
+  async getSymbolNeighbors(options: SymbolNeighborOptions): Promise<SymbolNeighbors | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectSymbolNeighbors(indexer, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Look up symbols by fuzzy name match
+   *
+   * Matches names lexically (trigrams + edit distance) instead of by
+   * embedding, so it needs no model and tolerates typos and partial names.
+   *
+   * @param query - Partial or misspelled symbol name
+   * @param options - Lookup options (limit, kind, path, minScore)
+   * @returns Matching results with `score` set to the fuzzy match score
+   */
+  async lookupSymbol(query: string, options?: LookupOptions): Promise<SearchResult[]> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      const allDocs = await indexer.getAll({ limit: 100000 });
+      const candidates = allDocs.filter(
+        (doc) =>
+          doc.metadata.type !== 'documentation' &&
+          (!options?.kind || doc.metadata.type === options.kind) &&
+          (!options?.path || doc.metadata.path?.startsWith(options.path))
+      );
+
+      return rankFuzzyMatches(query, candidates, (doc) => doc.metadata.name, {
+        limit: options?.limit ?? 10,
+        minScore: options?.minScore ?? 0.3,
+      }).map(({ item, score }) => ({ ...item, score }));
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Assemble a symbol's source with its callers, callees, and referenced types
+   *
+   * Uses stored call graph metadata, so no embedding is computed.
+   *
+   * @param name - Symbol name (e.g. "retryRequest" or "Client.Do")
+   * @param options - Hop depth, token budget, and path disambiguation
+   * @returns The assembled context, or null if the symbol isn't indexed
+   */
+  async getSymbolContext(
+    name: string,
+    options?: SymbolContextOptions
+  ): Promise<SymbolContext | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await assembleSymbolContext(indexer, name, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Gather a symbol's definition, callers, callees, implements edges, and git info
+   *
+   * Uses stored metadata, so no embedding is computed and git isn't run.
+   *
+   * @param name - Symbol name (e.g. "retryRequest" or "Client.Do")
+   * @param options - Sections, token budget, and path disambiguation
+   * @returns The inspection, or null if the symbol isn't indexed
+   */
+  async getSymbolInspection(
+    name: string,
+    options?: SymbolInspectionOptions
+  ): Promise<SymbolInspection | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectSymbolInspection(indexer, name, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Explain a symbol: doc comment, signature, a few usage examples, and direct callees
+   *
+   * Uses stored call graph metadata, so no embedding is computed. Examples
+   * are read from the callers' current source, or their indexed snippets
+   * when a file changed since indexing.
+   *
+   * @param name - Symbol name (e.g. "NewExpBackoff" or "Client.Do")
+   * @param options - Example and callee limits, and path disambiguation
+   * @returns The explanation, or null if the symbol isn't indexed
+   */
+  async getSymbolExplanation(
+    name: string,
+    options?: SymbolExplanationOptions
+  ): Promise<SymbolExplanation | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      const sources = this.sourceReader(indexer);
+      return await collectSymbolExplanation(indexer, name, options, this.symbolGraphs, sources);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Collect call sites of a symbol as usage examples
+   *
+   * Uses stored call graph metadata, so no embedding is computed. Examples
+   * are read from the callers' current source, or their indexed snippets
+   * when a file changed since indexing.
+   *
+   * @param name - Symbol name (e.g. "NewExpBackoff" or "Client.Do")
+   * @param options - Example limit, test inclusion, and path disambiguation
+   * @returns The usages, or null if the symbol isn't indexed
+   */
+  async getSymbolUsages(name: string, options?: SymbolUsageOptions): Promise<SymbolUsages | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      const sources = this.sourceReader(indexer);
+      return await collectSymbolUsages(indexer, name, options, this.symbolGraphs, sources);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Find the test functions that call a symbol, directly or through other code
+   *
+   * @param name - Symbol name (e.g. "NewExpBackoff" or "Client.Do")
+   * @param options - Depth and disambiguation options
+   * @returns Direct and transitive tests, or null if the symbol isn't indexed
+   */
+  async getSymbolTests(name: string, options?: SymbolTestOptions): Promise<SymbolTests | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectSymbolTests(indexer, name, options, this.symbolGraphs);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Outline a package's types and how they relate
+   *
+   * Uses stored metadata, so no embedding is computed.
+   *
+   * @param pkg - Package directory (e.g. "internal/retry"), or a trailing part of it
+   * @param options - Visibility and size options
+   * @returns The outline, or null if no indexed file is in the package
+   */
+  async getPackageOutline(
+    pkg: string,
+    options?: PackageOutlineOptions
+  ): Promise<PackageOutline | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectPackageOutline(indexer, pkg, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * List the types implementing an interface, by assertion or method set
+   *
+   * Uses stored metadata, so no embedding is computed.
+   *
+   * @param iface - Interface name, optionally package-qualified (e.g. "io.Reader")
+   * @param options - Test inclusion and limit
+   * @returns The implementations, or null if the interface can't be resolved
+   */
+  async getImplementations(
+    iface: string,
+    options?: ImplementationOptions
+  ): Promise<InterfaceImplementations | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return await collectImplementations(indexer, iface, options);
+    } finally {
+      await indexer.close();
+    }
+  }
+
+  /**
+   * Version of the current index contents (see RepositoryIndexer.getIndexVersion)
+   *
+   * @returns An opaque version string, or null if the repository isn't indexed
+   */
+  async getIndexVersion(): Promise<string | null> {
+    const indexer = await this.getIndexer({ skipEmbedder: true });
+    try {
+      return indexer.getIndexVersion();
+    } finally {
+      await indexer.close();
+    }
+  }
+
   /**
    * Check if repository is indexed
    *
@@ -230,7 +1285,8 @@ export class SearchService {
    */
   async isIndexed(): Promise<boolean> {
     try {
-      const indexer = await this.getIndexer();
+      // An unreachable embedding model doesn't make the index missing
+      const indexer = await this.getIndexer({ skipEmbedder: true });
       try {
         const stats = await indexer.getStats();
         return stats !== null;
@@ -242,3 +1298,55 @@ export class SearchService {
     }
   }
 }
+
+/**
+ * Score results are ordered by: similarity plus doc quality and keyword boosts
+ */
+function finalScore(result: SearchResult): number {
+  return result.score + (result.metadata.docBoost ?? 0) + (result.metadata.keywordBoost ?? 0);
+}
+
+/**
+ * Whether a search requires or excludes identifiers
+ */
+function hasIdentifierConstraints(
+  options?: Pick<SearchOptions, 'mustContain' | 'mustNotContain'>
+): boolean {
+  return (options?.mustContain?.length ?? 0) + (options?.mustNotContain?.length ?? 0) > 0;
+}
+
+/**
+ * Whether a search leaves out generated code: yes, unless it is included or
+ * filtered on explicitly
+ */
+function excludesGenerated(options?: Pick<SearchOptions, 'includeGenerated' | 'filter'>): boolean {
+  return !options?.includeGenerated && options?.filter?.generated === undefined;
+}
+
+/**
+ * Pick the indexed symbol with an exact name, optionally under a path prefix.
+ * Ties resolve to the first by path and line so the choice is stable.
+ */
+function pickSymbol(docs: SearchResult[], name: string, pathPrefix?: string): SearchResult | null {
+  const candidates = docs.filter(
+    (doc) =>
+      doc.metadata.name === name &&
+      doc.metadata.type !== 'documentation' &&
+      (!pathPrefix || doc.metadata.path?.startsWith(pathPrefix))
+  );
+  candidates.sort(
+    (a, b) =>
+      (a.metadata.path ?? '').localeCompare(b.metadata.path ?? '') ||
+      (a.metadata.startLine ?? 0) - (b.metadata.startLine ?? 0)
+  );
+  return candidates[0] ?? null;
+}
+
+/**
+ * Most recently modified first; symbols without blame data keep their order at the end
+ */
+function sortByRecency(results: SearchResult[]): SearchResult[] {
+  const time = (result: SearchResult) =>
+    result.metadata.lastModified ? Date.parse(result.metadata.lastModified) : 0;
+  return [...results].sort((a, b) => time(b) - time(a));
+}
@@ -0,0 +1,156 @@
+import { describe, expect, it, vi } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import {
+  findCloneClusters,
+  formatCloneReport,
+  type NeighborSearch,
+  normalizeStructure,
+  structuralHash,
+} from '../clones';
+
+const SUM_BODY = `func sumOrders(orders []Order) int {
+	total := 0
+	for _, o := range orders {
+		total += o.Amount // cents
+	}
+	return total
+}`;
+
+const RENAMED_BODY = `func totalInvoices(invoices []Invoice) int {
+	acc := 0
+	for _, inv := range invoices {
+		acc += inv.Value
+	}
+	return acc
+}`;
+
+const LOOP_BODY = `func countOrders(orders []Order) int {
+	n := 0
+	for i := 0; i < len(orders); i++ {
+		n++
+	}
+	return n
+}`;
+
+function doc(
+  id: string,
+  path: string,
+  snippet: string,
+  extra: Partial<SearchResult['metadata']> = {}
+): SearchResult {
+  const lines = snippet.split('\n').length;
+  return {
+    id,
+    score: 1,
+    metadata: {
+      name: id,
+      type: 'function',
+      path,
+      startLine: 10,
+      endLine: 10 + lines - 1,
+      snippet,
+      ...extra,
+    },
+  };
+}
+
+describe('normalizeStructure', () => {
+  it('should normalize identifiers, literals, and comments', () => {
+    expect(normalizeStructure(SUM_BODY)).toBe(normalizeStructure(RENAMED_BODY));
+    expect(normalizeStructure('x := "a" + 1')).toBe(normalizeStructure("y := 'b' + 42"));
+  });
+
+  it('should keep keywords and structure', () => {
+    expect(structuralHash(SUM_BODY)).not.toBe(structuralHash(LOOP_BODY));
+    expect(normalizeStructure('for _, v := range xs { return v }')).toBe(
+      'for _, _ := range _ { return _ }'
+    );
+  });
+});
+
+describe('findCloneClusters', () => {
+  it('should group renamed copies structurally', async () => {
+    const docs = [
+      doc('sumOrders', 'billing/orders.go', SUM_BODY),
+      doc('totalInvoices', 'billing/invoices.go', RENAMED_BODY, {
+        docstring: 'totalInvoices sums invoice values.',
+      }),
+      doc('countOrders', 'billing/count.go', LOOP_BODY),
+    ];
+
+    const clusters = await findCloneClusters(docs, null);
+
+    expect(clusters).toHaveLength(1);
+    expect(clusters[0].kind).toBe('structural');
+    expect(clusters[0].similarity).toBe(1);
+    expect(clusters[0].members.map((m) => m.path)).toEqual([
+      'billing/invoices.go',
+      'billing/orders.go',
+    ]);
+    // Documented member represents the cluster
+    expect(clusters[0].representative.name).toBe('totalInvoices');
+  });
+
+  it('should link semantic clones through neighbor search', async () => {
+    const docs = [
+      doc('sumOrders', 'a.go', SUM_BODY),
+      doc('countOrders', 'b.go', LOOP_BODY),
+    ];
+    const searchNeighbors: NeighborSearch = vi.fn(async (id: string) =>
+      id === 'sumOrders' ? [docs[0], { ...docs[1], score: 0.96 }] : []
+    );
+
+    const clusters = await findCloneClusters(docs, searchNeighbors, { threshold: 0.95 });
+
+    expect(searchNeighbors).toHaveBeenCalledWith('sumOrders', {
+      limit: 11,
+      scoreThreshold: 0.95,
+    });
+    expect(clusters).toHaveLength(1);
+    expect(clusters[0].kind).toBe('semantic');
+    expect(clusters[0].similarity).toBe(0.96);
+  });
+
+  it('should skip short bodies, tests, and non-functions', async () => {
+    const short = 'func a() int {\n\treturn 1\n}';
+    const docs = [
+      doc('a', 'a.go', short),
+      doc('b', 'b.go', short),
+      doc('sumOrders', 'orders.go', SUM_BODY),
+      doc('sumOrdersTest', 'orders_test.go', SUM_BODY),
+      doc('Orders', 'types.go', SUM_BODY, { type: 'class' }),
+    ];
+
+    expect(await findCloneClusters(docs, null)).toEqual([]);
+    expect(await findCloneClusters(docs, null, { minLines: 3 })).toHaveLength(1);
+    expect(await findCloneClusters(docs, null, { includeTests: true })).toHaveLength(1);
+  });
+
+  it('should scope by path prefix', async () => {
+    const docs = [
+      doc('sumOrders', 'billing/orders.go', SUM_BODY),
+      doc('totalInvoices', 'reports/invoices.go', RENAMED_BODY),
+    ];
+
+    expect(await findCloneClusters(docs, null, { path: 'billing/' })).toEqual([]);
+  });
+});
+
+describe('formatCloneReport', () => {
+  it('should list each cluster with its representative and locations', async () => {
+    const clusters = await findCloneClusters(
+      [doc('sumOrders', 'a.go', SUM_BODY), doc('totalInvoices', 'b.go', RENAMED_BODY)],
+      null
+    );
+
+    expect(formatCloneReport(clusters)).toBe(
+      'Cluster 1: 2 x 7 lines (structural)\n' +
+        '  Representative: sumOrders (a.go:10-16)\n' +
+        '  - totalInvoices (b.go:10-16)\n'
+    );
+  });
+
+  it('should report when there are no clones', () => {
+    expect(formatCloneReport([])).toBe('No clones found.\n');
+  });
+});
@@ -0,0 +1,48 @@
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { classifySimilarCode, normalizeCodeBody } from '../similar';
+
+function result(id: string, score: number, snippet?: string): SearchResult {
+  return { id, score, metadata: { name: id, type: 'function', snippet } };
+}
+
+describe('normalizeCodeBody', () => {
+  it('should ignore comments and whitespace', () => {
+    const a = 'func a() {\n\t// retry\n\treturn x + 1\n}';
+    const b = 'func a() { /* retry */ return x + 1 }';
+    expect(normalizeCodeBody(a)).toBe(normalizeCodeBody(b));
+  });
+
+  it('should keep code differences', () => {
+    expect(normalizeCodeBody('return x + 1')).not.toBe(normalizeCodeBody('return x + 2'));
+  });
+});
+
+describe('classifySimilarCode', () => {
+  it('should flag matches at or above the threshold as near-identical', () => {
+    const { nearIdentical, related } = classifySimilarCode('body', [
+      result('copy', 0.97),
+      result('cousin', 0.82),
+    ]);
+
+    expect(nearIdentical.map((m) => m.id)).toEqual(['copy']);
+    expect(nearIdentical[0].nearIdentical).toBe(true);
+    expect(related.map((m) => m.id)).toEqual(['cousin']);
+    expect(related[0].nearIdentical).toBe(false);
+  });
+
+  it('should flag identical bodies regardless of score', () => {
+    const { nearIdentical } = classifySimilarCode('return x  // done', [
+      result('reformatted', 0.8, 'return   x'),
+    ]);
+
+    expect(nearIdentical.map((m) => m.id)).toEqual(['reformatted']);
+  });
+
+  it('should respect a custom threshold', () => {
+    const { nearIdentical, related } = classifySimilarCode(undefined, [result('a', 0.9)], 0.85);
+
+    expect(nearIdentical).toHaveLength(1);
+    expect(related).toHaveLength(0);
+  });
+});
@@ -0,0 +1,353 @@
+/**
+ * Clone Detection
+ *
+ * Finds clusters of duplicated function bodies across the index. Two
+ * signals are combined:
+ * - Structural hashing: bodies that are identical once identifiers,
+ *   literals, comments, and whitespace are normalized (copy-paste, renames)
+ * - Embedding similarity: bodies whose stored vectors are nearly the same
+ *   (semantically equivalent reimplementations)
+ */
+
+import * as crypto from 'node:crypto';
+import type { RepositoryIndexer } from '../indexer/index';
+import { isTestFile } from '../utils/test-utils';
+import type { SearchResult } from '../vector/types';
+import { NEAR_IDENTICAL_THRESHOLD, normalizeCodeBody } from './similar';
+
+/**
+ * How the members of a clone cluster were matched
+ */
+export type CloneKind = 'structural' | 'semantic';
+
+/**
+ * A symbol that belongs to a clone cluster
+ */
+export interface CloneLocation {
+  id: string;
+  name: string;
+  type: string;
+  path: string;
+  startLine: number;
+  endLine: number;
+}
+
+/**
+ * A group of symbols with (near-)duplicate bodies
+ */
+export interface CloneCluster {
+  /**
+   * 'structural' when every member has the same normalized body,
+   * 'semantic' when at least one member was matched by embedding only
+   */
+  kind: CloneKind;
+  /** Member chosen to represent the cluster (documented and exported first) */
+  representative: CloneLocation;
+  /** All members, including the representative, ordered by location */
+  members: CloneLocation[];
+  /** Lowest embedding score linking members (1 for purely structural clusters) */
+  similarity: number;
+  /** Lines in the representative's body */
+  lines: number;
+}
+
+/**
+ * Clone detection options
+ */
+export interface CloneDetectionOptions {
+  /** Only consider symbols under this path prefix */
+  path?: string;
+  /** Skip bodies shorter than this many lines (default: 5) */
+  minLines?: number;
+  /** Minimum embedding score for a semantic clone (default: 0.95) */
+  threshold?: number;
+  /** Compare embeddings as well as structure (default: true) */
+  semantic?: boolean;
+  /** Include test files (default: false) */
+  includeTests?: boolean;
+  /** Nearest neighbors examined per symbol (default: 10) */
+  neighbors?: number;
+}
+
+/**
+ * Finds nearest neighbors of an indexed document by its stored vector
+ */
+export type NeighborSearch = (
+  documentId: string,
+  options: { limit: number; scoreThreshold: number }
+) => Promise<SearchResult[]>;
+
+const CLONE_KINDS = new Set(['function', 'method']);
+
+/**
+ * Words that carry structure and must survive normalization: keywords of
+ * the languages the scanners support, plus common Go builtins.
+ */
+const KEYWORDS = new Set([
+  // Shared
+  'if',
+  'else',
+  'for',
+  'return',
+  'break',
+  'continue',
+  'switch',
+  'case',
+  'default',
+  'import',
+  'const',
+  'var',
+  'nil',
+  'null',
+  'undefined',
+  'true',
+  'false',
+  'new',
+  'this',
+  'try',
+  'catch',
+  'finally',
+  'throw',
+  'while',
+  'do',
+  'in',
+  'of',
+  'let',
+  'function',
+  'class',
+  'interface',
+  'type',
+  'async',
+  'await',
+  'yield',
+  'typeof',
+  'instanceof',
+  // Go
+  'func',
+  'go',
+  'defer',
+  'chan',
+  'select',
+  'range',
+  'map',
+  'struct',
+  'package',
+  'fallthrough',
+  'goto',
+  'make',
+  'len',
+  'append',
+  'panic',
+  'recover',
+]);
+
+/** String literals, number literals, and identifiers, in one pass */
+const TOKEN_PATTERN = /`[^`]*`|"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\b\d[\w.]*|[A-Za-z_$][\w$]*/g;
+
+/**
+ * Normalize a body for structural comparison
+ *
+ * Comments and whitespace are removed, string and number literals are
+ * replaced by placeholders, and every non-keyword identifier becomes `_`,
+ * so renamed copies normalize to the same text.
+ */
+export function normalizeStructure(code: string): string {
+  return normalizeCodeBody(code).replace(TOKEN_PATTERN, (token) => {
+    if (/^[`"']/.test(token)) return '"S"';
+    if (/^\d/.test(token)) return '0';
+    return KEYWORDS.has(token) ? token : '_';
+  });
+}
+
+/**
+ * Hash of a body's normalized structure
+ */
+export function structuralHash(code: string): string {
+  return crypto.createHash('sha256').update(normalizeStructure(code)).digest('hex');
+}
+
+/**
+ * Group candidate symbols into clone clusters
+ *
+ * Members are linked when their structural hashes match or, if a neighbor
+ * search is provided, when their embeddings score at or above the threshold.
+ * Linked members are merged transitively (union-find).
+ *
+ * @param docs - Indexed documents (non-function documents are ignored)
+ * @param searchNeighbors - Nearest-neighbor search over stored vectors
+ */
+export async function findCloneClusters(
+  docs: SearchResult[],
+  searchNeighbors: NeighborSearch | null,
+  options: CloneDetectionOptions = {}
+): Promise<CloneCluster[]> {
+  const {
+    minLines = 5,
+    threshold = NEAR_IDENTICAL_THRESHOLD,
+    includeTests = false,
+    neighbors = 10,
+  } = options;
+
+  const candidates = docs.filter((doc) => {
+    const { type, path, snippet } = doc.metadata;
+    if (!CLONE_KINDS.has(String(type)) || !path || !snippet) return false;
+    if (options.path && !path.startsWith(options.path)) return false;
+    if (!includeTests && isTestPath(path)) return false;
+    return bodyLines(doc) >= minLines;
+  });
+
+  const byId = new Map(candidates.map((doc) => [doc.id, doc]));
+  const parent = new Map(candidates.map((doc) => [doc.id, doc.id]));
+  const find = (id: string): string => {
+    let root = id;
+    while (parent.get(root) !== root) root = parent.get(root) as string;
+    parent.set(id, root);
+    return root;
+  };
+  const union = (a: string, b: string) => parent.set(find(a), find(b));
+
+  // Structural links
+  const hashes = new Map<string, string>();
+  const firstByHash = new Map<string, string>();
+  for (const doc of candidates) {
+    const hash = structuralHash(doc.metadata.snippet as string);
+    hashes.set(doc.id, hash);
+    const first = firstByHash.get(hash);
+    if (first) union(first, doc.id);
+    else firstByHash.set(hash, doc.id);
+  }
+
+  // Semantic links
+  const linkScores = new Map<string, number>();
+  if (searchNeighbors) {
+    for (const doc of candidates) {
+      const results = await searchNeighbors(doc.id, {
+        limit: neighbors + 1,
+        scoreThreshold: threshold,
+      });
+      for (const result of results) {
+        if (result.id === doc.id || !byId.has(result.id)) continue;
+        union(doc.id, result.id);
+        for (const id of [doc.id, result.id]) {
+          linkScores.set(id, Math.min(linkScores.get(id) ?? 1, result.score));
+        }
+      }
+    }
+  }
+
+  const groups = new Map<string, SearchResult[]>();
+  for (const doc of candidates) {
+    const root = find(doc.id);
+    groups.set(root, [...(groups.get(root) ?? []), doc]);
+  }
+
+  const clusters: CloneCluster[] = [];
+  for (const members of groups.values()) {
+    if (members.length < 2) continue;
+
+    const structural = new Set(members.map((doc) => hashes.get(doc.id))).size === 1;
+    const representative = pickRepresentative(members);
+
+    clusters.push({
+      kind: structural ? 'structural' : 'semantic',
+      representative: toLocation(representative),
+      members: members.map(toLocation).sort(compareLocation),
+      similarity: structural ? 1 : Math.min(...members.map((doc) => linkScores.get(doc.id) ?? 1)),
+      lines: bodyLines(representative),
+    });
+  }
+
+  // Biggest consolidation opportunities first
+  return clusters.sort(
+    (a, b) =>
+      b.members.length * b.lines - a.members.length * a.lines ||
+      compareLocation(a.members[0], b.members[0])
+  );
+}
+
+/**
+ * Detect clone clusters across an indexed repository
+ *
+ * Uses stored vectors for the semantic pass, so no embedding model is needed.
+ */
+export async function detectClones(
+  indexer: RepositoryIndexer,
+  options: CloneDetectionOptions = {}
+): Promise<CloneCluster[]> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  const searchNeighbors: NeighborSearch | null =
+    options.semantic === false
+      ? null
+      : (documentId, searchOptions) => indexer.searchByDocumentId(documentId, searchOptions);
+  return findCloneClusters(docs, searchNeighbors, options);
+}
+
+/**
+ * Format clone clusters as a plain-text report
+ */
+export function formatCloneReport(clusters: CloneCluster[]): string {
+  if (clusters.length === 0) {
+    return 'No clones found.\n';
+  }
+
+  const lines: string[] = [];
+  for (const [i, cluster] of clusters.entries()) {
+    const score = cluster.kind === 'semantic' ? `, ${(cluster.similarity * 100).toFixed(0)}%` : '';
+    lines.push(
+      `Cluster ${i + 1}: ${cluster.members.length} x ${cluster.lines} lines (${cluster.kind}${score})`
+    );
+    lines.push(`  Representative: ${formatLocation(cluster.representative)}`);
+    for (const member of cluster.members) {
+      if (member.id === cluster.representative.id) continue;
+      lines.push(`  - ${formatLocation(member)}`);
+    }
+    lines.push('');
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
+
+function formatLocation(location: CloneLocation): string {
+  return `${location.name} (${location.path}:${location.startLine}-${location.endLine})`;
+}
+
+function toLocation(doc: SearchResult): CloneLocation {
+  const { name, type, path, startLine, endLine } = doc.metadata;
+  return {
+    id: doc.id,
+    name: name ?? '(anonymous)',
+    type: String(type),
+    path: path ?? '',
+    startLine: startLine ?? 0,
+    endLine: endLine ?? 0,
+  };
+}
+
+/**
+ * Prefer documented, exported members, then the earliest location
+ */
+function pickRepresentative(members: SearchResult[]): SearchResult {
+  const rank = (doc: SearchResult) =>
+    (doc.metadata.docstring ? 2 : 0) + (doc.metadata.exported ? 1 : 0);
+  return members.reduce((best, doc) => {
+    const diff = rank(doc) - rank(best);
+    return diff > 0 || (diff === 0 && compareLocation(toLocation(doc), toLocation(best)) < 0)
+      ? doc
+      : best;
+  });
+}
+
+function bodyLines(doc: SearchResult): number {
+  const { startLine, endLine, snippet } = doc.metadata;
+  if (startLine !== undefined && endLine !== undefined) return endLine - startLine + 1;
+  return (snippet ?? '').split('\n').length;
+}
+
+function compareLocation(a: CloneLocation, b: CloneLocation): number {
+  if (a.path !== b.path) return a.path < b.path ? -1 : 1;
+  return a.startLine - b.startLine;
+}
+
+function isTestPath(file: string): boolean {
+  return isTestFile(file) || file.endsWith('_test.go') || file.includes('/__tests__/');
+}
@@ -0,0 +1,8 @@
+/**
+ * Code Similarity
+ * Similar-code classification and clone detection
+ */
+
+export * from './clones';
+export * from './similar';
+export * from './types';
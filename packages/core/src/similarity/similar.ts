@@ -0,0 +1,59 @@
+/**
+ * Similar Code Classification
+ *
+ * Helpers for telling near-identical code apart from merely related code.
+ * Embedding scores find related code; normalized bodies confirm copies.
+ */
+
+import type { SearchResult } from '../vector/types';
+import type { SimilarCodeMatch, SimilarCodeResult } from './types';
+
+/**
+ * Default score at or above which a match counts as near-identical
+ */
+export const NEAR_IDENTICAL_THRESHOLD = 0.95;
+
+/**
+ * Normalize a code body for comparison
+ *
+ * Strips line and block comments and collapses whitespace, so formatting
+ * and comment differences don't hide a copy.
+ */
+export function normalizeCodeBody(code: string): string {
+  return code
+    .replace(/\/\*[\s\S]*?\*\//g, ' ')
+    .replace(/\/\/[^\n]*/g, ' ')
+    .replace(/\s+/g, ' ')
+    .trim();
+}
+
+/**
+ * Split similar-code search results into near-identical and related matches
+ *
+ * A match is near-identical when its score reaches the threshold or its
+ * normalized body equals the reference body.
+ *
+ * @param referenceBody - Code of the symbol or snippet being compared
+ * @param results - Nearest-neighbor results, best first
+ */
+export function classifySimilarCode(
+  referenceBody: string | undefined,
+  results: SearchResult[],
+  nearIdenticalThreshold = NEAR_IDENTICAL_THRESHOLD
+): Omit<SimilarCodeResult, 'reference'> {
+  const reference = referenceBody ? normalizeCodeBody(referenceBody) : '';
+  const nearIdentical: SimilarCodeMatch[] = [];
+  const related: SimilarCodeMatch[] = [];
+
+  for (const result of results) {
+    const body = result.metadata.snippet;
+    const sameBody = reference !== '' && !!body && normalizeCodeBody(body) === reference;
+    if (sameBody || result.score >= nearIdenticalThreshold) {
+      nearIdentical.push({ ...result, nearIdentical: true });
+    } else {
+      related.push({ ...result, nearIdentical: false });
+    }
+  }
+
+  return { nearIdentical, related };
+}
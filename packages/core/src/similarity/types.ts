@@ -0,0 +1,38 @@
+/**
+ * Code Similarity Types
+ * Types for embedding-based "similar code" results
+ */
+
+import type { SearchResult } from '../vector/types';
+
+/**
+ * A symbol similar to the reference symbol or snippet
+ */
+export interface SimilarCodeMatch extends SearchResult {
+  /** True when the bodies are (almost) the same code, not just related */
+  nearIdentical: boolean;
+}
+
+/**
+ * Result of a similar-code query
+ */
+export interface SimilarCodeResult {
+  /** The indexed symbol that was used as the reference (null for snippets) */
+  reference: SearchResult | null;
+  /** Matches that are copies or near-copies of the reference */
+  nearIdentical: SimilarCodeMatch[];
+  /** Matches that are semantically related but not copies */
+  related: SimilarCodeMatch[];
+}
+
+/**
+ * Options for classifying similar code
+ */
+export interface SimilarCodeOptions {
+  /** Maximum number of matches (default: 10) */
+  limit?: number;
+  /** Minimum similarity score (0-1, default: 0.75) */
+  threshold?: number;
+  /** Score at or above which a match counts as near-identical (default: 0.95) */
+  nearIdenticalThreshold?: number;
+}
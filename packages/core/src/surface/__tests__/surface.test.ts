@@ -0,0 +1,170 @@
+/**
+ * Tests for API Surface Report
+ */
+
+import { describe, expect, it } from 'vitest';
+import type { SearchResult } from '../../vector/types';
+import { buildApiSurface, formatApiSurface, summarizeDoc } from '../index';
+
+function doc(
+  name: string,
+  type: string,
+  signature: string,
+  overrides: Partial<SearchResult['metadata']> = {}
+): SearchResult {
+  return {
+    id: `${overrides.path ?? 'pkg/server/server.go'}:${name}:1`,
+    score: 1,
+    metadata: {
+      path: 'pkg/server/server.go',
+      type,
+      name,
+      signature,
+      exported: true,
+      language: 'go',
+      startLine: 1,
+      endLine: 1,
+      ...overrides,
+    },
+  };
+}
+
+describe('API Surface', () => {
+  const docs: SearchResult[] = [
+    doc('Server', 'class', 'type Server struct', {
+      docstring: 'Server handles requests. It is safe for concurrent use.',
+    }),
+    doc('Server.Start', 'method', 'func (s *Server) Start() error', {
+      docstring: 'Start begins serving.',
+    }),
+    doc('Server.Stop', 'method', 'func (s *Server) Stop()'),
+    doc('Server.reset', 'method', 'func (s *Server) reset()', { exported: false }),
+    doc('NewServer', 'function', 'func NewServer(cfg Config) *Server', {
+      docstring: 'NewServer creates a server.',
+    }),
+    doc('MaxConns', 'variable', 'const MaxConns = 100'),
+    doc('DefaultClient', 'variable', 'var DefaultClient = &Client{}'),
+    doc('Handler', 'interface', 'type Handler interface', { path: 'pkg/server/handler.go' }),
+    doc('helper', 'function', 'func helper()', { exported: false }),
+    doc('TestStart', 'function', 'func TestStart(t *testing.T)', {
+      path: 'pkg/server/server_test.go',
+    }),
+    doc('Route', 'function', 'func Route()', { path: 'pkg/server/routes/route.go' }),
+    doc('Other', 'function', 'func Other()', { path: 'pkg/other/other.go' }),
+  ];
+
+  describe('buildApiSurface', () => {
+    it('should include only exported symbols in the package directory', () => {
+      const surface = buildApiSurface(docs, { path: 'pkg/server' });
+
+      expect(surface.package).toBe('pkg/server');
+      expect(surface.functions.map((s) => s.name)).toEqual(['NewServer']);
+      expect(surface.constants.map((s) => s.name)).toEqual(['MaxConns']);
+      expect(surface.variables.map((s) => s.name)).toEqual(['DefaultClient']);
+      expect(surface.types.map((t) => t.symbol.name)).toEqual(['Handler', 'Server']);
+    });
+
+    it('should group methods under their types', () => {
+      const surface = buildApiSurface(docs, { path: 'pkg/server' });
+      const server = surface.types.find((t) => t.symbol.name === 'Server');
+
+      expect(server?.methods.map((m) => m.name)).toEqual(['Server.Start', 'Server.Stop']);
+    });
+
+    it('should include subdirectories when recursive', () => {
+      const surface = buildApiSurface(docs, { path: 'pkg/server/', recursive: true });
+
+      expect(surface.package).toBe('pkg/server');
+      expect(surface.functions.map((s) => s.name)).toEqual(['NewServer', 'Route']);
+    });
+
+    it('should include test files only when requested', () => {
+      const surface = buildApiSurface(docs, { path: 'pkg/server', includeTests: true });
+
+      expect(surface.functions.map((s) => s.name)).toContain('TestStart');
+    });
+
+    it('should keep methods whose type is declared elsewhere', () => {
+      const surface = buildApiSurface(
+        [doc('Client.Do', 'method', 'func (c *Client) Do() error')],
+        { path: 'pkg/server' }
+      );
+
+      expect(surface.types).toHaveLength(1);
+      expect(surface.types[0].symbol.name).toBe('Client');
+      expect(surface.types[0].methods).toHaveLength(1);
+    });
+  });
+
+  describe('formatApiSurface', () => {
+    it('should format like go doc', () => {
+      const output = formatApiSurface(buildApiSurface(docs, { path: 'pkg/server' }));
+
+      expect(output).toBe(
+        [
+          'package pkg/server',
+          '',
+          'CONSTANTS',
+          '',
+          'const MaxConns = 100',
+          '',
+          'VARIABLES',
+          '',
+          'var DefaultClient = &Client{}',
+          '',
+          'FUNCTIONS',
+          '',
+          'func NewServer(cfg Config) *Server',
+          '    NewServer creates a server.',
+          '',
+          'TYPES',
+          '',
+          'type Handler interface',
+          '',
+          'type Server struct',
+          '    Server handles requests.',
+          '',
+          'func (s *Server) Start() error',
+          '    Start begins serving.',
+          '',
+          'func (s *Server) Stop()',
+          '',
+        ].join('\n')
+      );
+    });
+
+    it('should omit doc summaries when disabled', () => {
+      const output = formatApiSurface(buildApiSurface(docs, { path: 'pkg/server' }), {
+        includeDocs: false,
+      });
+
+      expect(output).not.toContain('NewServer creates a server.');
+    });
+
+    it('should be independent of input order', () => {
+      const forward = formatApiSurface(buildApiSurface(docs, { path: 'pkg/server' }));
+      const reversed = formatApiSurface(
+        buildApiSurface([...docs].reverse(), { path: 'pkg/server' })
+      );
+
+      expect(reversed).toBe(forward);
+    });
+  });
+
+  describe('summarizeDoc', () => {
+    it('should return the first sentence', () => {
+      expect(summarizeDoc('Parse reads a file.\nIt returns an error.')).toBe(
+        'Parse reads a file.'
+      );
+    });
+
+    it('should return the whole text without a sentence break', () => {
+      expect(summarizeDoc('Parse reads a file')).toBe('Parse reads a file');
+    });
+
+    it('should handle missing docs', () => {
+      expect(summarizeDoc(undefined)).toBeUndefined();
+      expect(summarizeDoc('   ')).toBeUndefined();
+    });
+  });
+});
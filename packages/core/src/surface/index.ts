@@ -0,0 +1,210 @@
+/**
+ * API Surface Report
+ * Builds a stable, diffable listing of a package's exported API from the index
+ *
+ * Output is formatted like `go doc -all` and contains no line numbers,
+ * timestamps, or scores, so it can be committed and compared in review.
+ */
+
+import * as path from 'node:path';
+import type { RepositoryIndexer } from '../indexer';
+import { isTestFile } from '../utils/test-utils';
+import type { SearchResult } from '../vector/types';
+import type {
+  ApiSurface,
+  ApiSurfaceOptions,
+  ApiSymbol,
+  ApiType,
+  FormatApiSurfaceOptions,
+} from './types';
+
+export * from './types';
+
+/** Document types that declare a named type */
+const TYPE_KINDS = new Set(['class', 'interface', 'type']);
+
+/**
+ * Generate the API surface of a package from indexed documents
+ *
+ * @param indexer - Repository indexer with indexed documents
+ * @param options - Package selection options
+ */
+export async function generateApiSurface(
+  indexer: RepositoryIndexer,
+  options: ApiSurfaceOptions
+): Promise<ApiSurface> {
+  const docs = await indexer.getAll({ limit: 100000 });
+  return buildApiSurface(docs, options);
+}
+
+/**
+ * Build an API surface from indexed documents
+ *
+ * Only exported symbols are included. Methods are grouped under their
+ * receiver/class when it is declared in the package; otherwise a type
+ * entry without a signature is created so the methods are not lost.
+ */
+export function buildApiSurface(docs: SearchResult[], options: ApiSurfaceOptions): ApiSurface {
+  const pkg = normalizePackagePath(options.path);
+  const symbols = docs
+    .filter((doc) => doc.metadata.exported === true && doc.metadata.name)
+    .filter((doc) => isInPackage(doc.metadata.path, pkg, options.recursive ?? false))
+    .filter((doc) => options.includeTests || !isTestPath(doc.metadata.path ?? ''))
+    .filter((doc) => doc.metadata.type !== 'documentation')
+    .map(toApiSymbol);
+
+  const surface: ApiSurface = {
+    package: pkg,
+    constants: [],
+    variables: [],
+    functions: [],
+    types: [],
+  };
+
+  const types = new Map<string, ApiType>();
+  const methods: ApiSymbol[] = [];
+
+  for (const symbol of symbols) {
+    if (symbol.kind === 'method') {
+      methods.push(symbol);
+    } else if (TYPE_KINDS.has(symbol.kind)) {
+      if (!types.has(symbol.name)) {
+        types.set(symbol.name, { symbol, methods: [] });
+      }
+    } else if (symbol.kind === 'function') {
+      surface.functions.push(symbol);
+    } else if (symbol.kind === 'variable') {
+      if (/^(export\s+)?const\b/.test(symbol.signature)) {
+        surface.constants.push(symbol);
+      } else {
+        surface.variables.push(symbol);
+      }
+    }
+  }
+
+  for (const method of methods) {
+    const dot = method.name.indexOf('.');
+    const owner = dot === -1 ? '' : method.name.slice(0, dot);
+    let type = types.get(owner);
+    if (!type) {
+      type = {
+        symbol: { name: owner, kind: 'type', signature: '', file: method.file },
+        methods: [],
+      };
+      types.set(owner, type);
+    }
+    type.methods.push(method);
+  }
+
+  surface.constants.sort(compareByName);
+  surface.variables.sort(compareByName);
+  surface.functions.sort(compareByName);
+  surface.types = [...types.values()].sort((a, b) => compareByName(a.symbol, b.symbol));
+  for (const type of surface.types) {
+    type.methods.sort(compareByName);
+  }
+
+  return surface;
+}
+
+/**
+ * Format an API surface as text
+ */
+export function formatApiSurface(
+  surface: ApiSurface,
+  options: FormatApiSurfaceOptions = {}
+): string {
+  const includeDocs = options.includeDocs ?? true;
+  const lines: string[] = [`package ${surface.package}`];
+
+  const section = (title: string, entries: ApiSymbol[]) => {
+    if (entries.length === 0) return;
+    lines.push('', title, '');
+    for (const entry of entries) {
+      lines.push(...formatSymbol(entry, includeDocs));
+    }
+  };
+
+  section('CONSTANTS', surface.constants);
+  section('VARIABLES', surface.variables);
+  section('FUNCTIONS', surface.functions);
+
+  if (surface.types.length > 0) {
+    lines.push('', 'TYPES', '');
+    for (const type of surface.types) {
+      if (type.symbol.signature) {
+        lines.push(...formatSymbol(type.symbol, includeDocs));
+      } else {
+        lines.push(`type ${type.symbol.name}`, '');
+      }
+      for (const method of type.methods) {
+        lines.push(...formatSymbol(method, includeDocs));
+      }
+    }
+  }
+
+  // Drop trailing blank lines so the output ends with exactly one newline
+  while (lines[lines.length - 1] === '') {
+    lines.pop();
+  }
+
+  return `${lines.join('\n')}\n`;
+}
+
+function formatSymbol(symbol: ApiSymbol, includeDocs: boolean): string[] {
+  const lines = symbol.signature.split('\n').map((line) => line.trimEnd());
+  if (includeDocs && symbol.summary) {
+    lines.push(`    ${symbol.summary}`);
+  }
+  lines.push('');
+  return lines;
+}
+
+function toApiSymbol(doc: SearchResult): ApiSymbol {
+  const name = doc.metadata.name as string;
+  return {
+    name,
+    kind: String(doc.metadata.type ?? 'unknown'),
+    signature: (doc.metadata.signature ?? name).trim(),
+    summary: summarizeDoc(doc.metadata.docstring),
+    file: doc.metadata.path ?? '',
+  };
+}
+
+/**
+ * First sentence of a doc comment, collapsed to one line
+ */
+export function summarizeDoc(docstring: string | undefined): string | undefined {
+  if (!docstring) return undefined;
+  const text = docstring.replace(/\s+/g, ' ').trim();
+  if (!text) return undefined;
+  const match = text.match(/^(.+?[.!?])(\s|$)/);
+  return match ? match[1] : text;
+}
+
+function normalizePackagePath(pkg: string): string {
+  const normalized = path.posix.normalize(pkg.replace(/\\/g, '/')).replace(/\/+$/, '');
+  return normalized === '' ? '.' : normalized;
+}
+
+function isInPackage(file: string | undefined, pkg: string, recursive: boolean): boolean {
+  if (!file) return false;
+  const dir = path.posix.dirname(file);
+  if (pkg === '.') {
+    return recursive || dir === '.';
+  }
+  return dir === pkg || (recursive && dir.startsWith(`${pkg}/`));
+}
+
+function isTestPath(file: string): boolean {
+  return isTestFile(file) || file.endsWith('_test.go') || file.includes('/__tests__/');
+}
+
+function compareByName(a: ApiSymbol, b: ApiSymbol): number {
+  return compareStrings(a.name, b.name) || compareStrings(a.file, b.file);
+}
+
+/** Locale-independent comparison so output is identical on every machine */
+function compareStrings(a: string, b: string): number {
+  return a < b ? -1 : a > b ? 1 : 0;
+}
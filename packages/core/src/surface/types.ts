@@ -0,0 +1,60 @@
+/**
+ * API Surface Types
+ * Types for representing the exported API of a package
+ */
+
+/**
+ * An exported symbol in the API surface
+ */
+export interface ApiSymbol {
+  /** Symbol name (methods keep their Type.method form) */
+  name: string;
+  /** Document type (function, method, class, interface, type, variable) */
+  kind: string;
+  /** Declaration signature */
+  signature: string;
+  /** First sentence of the doc comment */
+  summary?: string;
+  /** File where it's defined (relative to repository root) */
+  file: string;
+}
+
+/**
+ * An exported type with the methods declared on it
+ */
+export interface ApiType {
+  symbol: ApiSymbol;
+  methods: ApiSymbol[];
+}
+
+/**
+ * Exported API of a package, grouped like `go doc`
+ */
+export interface ApiSurface {
+  /** Package directory (relative to repository root) */
+  package: string;
+  constants: ApiSymbol[];
+  variables: ApiSymbol[];
+  functions: ApiSymbol[];
+  types: ApiType[];
+}
+
+/**
+ * Options for building an API surface
+ */
+export interface ApiSurfaceOptions {
+  /** Package directory relative to the repository root ("." for the root) */
+  path: string;
+  /** Include subdirectories (default: false, like a Go package) */
+  recursive?: boolean;
+  /** Include test files (default: false) */
+  includeTests?: boolean;
+}
+
+/**
+ * Options for formatting an API surface
+ */
+export interface FormatApiSurfaceOptions {
+  /** Include doc summaries (default: true) */
+  includeDocs?: boolean;
+}
@@ -5,7 +5,10 @@
 import { describe, expect, it } from 'vitest';
 import {
   type ConcurrencyConfig,
+  ConcurrencyLimiter,
   calculateOptimalConcurrency,
+  DEFAULT_MAX_CONCURRENT_EMBEDDINGS,
+  getEmbeddingConcurrency,
   getOptimalConcurrency,
   parseConcurrencyFromEnv,
   type SystemResources,
@@ -108,3 +111,66 @@ describe('getOptimalConcurrency', () => {
     expect(getOptimalConcurrency(config)).toBe(12);
   });
 });
+
+describe('getEmbeddingConcurrency', () => {
+  it('should prefer an explicit setting, then the environment, then the default', () => {
+    const env = { DEV_AGENT_EMBEDDING_CONCURRENCY: '8' };
+
+    expect(getEmbeddingConcurrency(2, env)).toBe(2);
+    expect(getEmbeddingConcurrency(undefined, env)).toBe(8);
+    expect(getEmbeddingConcurrency(undefined, {})).toBe(DEFAULT_MAX_CONCURRENT_EMBEDDINGS);
+  });
+
+  it('should ignore the global concurrency setting and invalid values', () => {
+    expect(getEmbeddingConcurrency(undefined, { DEV_AGENT_CONCURRENCY: '30' })).toBe(
+      DEFAULT_MAX_CONCURRENT_EMBEDDINGS
+    );
+    expect(getEmbeddingConcurrency(0, { DEV_AGENT_EMBEDDING_CONCURRENCY: 'lots' })).toBe(
+      DEFAULT_MAX_CONCURRENT_EMBEDDINGS
+    );
+  });
+});
+
+describe('ConcurrencyLimiter', () => {
+  it('should keep in-flight tasks under the limit and run queued tasks in order', async () => {
+    const limiter = new ConcurrencyLimiter(2);
+    const started: number[] = [];
+    let peak = 0;
+    const releases: Array<() => void> = [];
+
+    const tasks = [0, 1, 2, 3].map((i) =>
+      limiter.run(async () => {
+        started.push(i);
+        peak = Math.max(peak, limiter.inFlight);
+        await new Promise<void>((resolve) => releases.push(resolve));
+        return i;
+      })
+    );
+
+    await new Promise((resolve) => setTimeout(resolve, 0));
+    expect(started).toEqual([0, 1]);
+    expect(limiter.queued).toBe(2);
+
+    while (releases.length > 0 || started.length < 4) {
+      releases.shift()?.();
+      await new Promise((resolve) => setTimeout(resolve, 0));
+    }
+
+    expect(await Promise.all(tasks)).toEqual([0, 1, 2, 3]);
+    expect(started).toEqual([0, 1, 2, 3]);
+    expect(peak).toBe(2);
+    expect(limiter.inFlight).toBe(0);
+  });
+
+  it('should release the slot when a task fails', async () => {
+    const limiter = new ConcurrencyLimiter(1);
+
+    await expect(limiter.run(() => Promise.reject(new Error('429')))).rejects.toThrow('429');
+    await expect(limiter.run(async () => 'ok')).resolves.toBe('ok');
+    expect(limiter.inFlight).toBe(0);
+  });
+
+  it('should reject limits below one', () => {
+    expect(() => new ConcurrencyLimiter(0)).toThrow('positive integer');
+  });
+});
@@ -3,7 +3,12 @@
  */
 
 import { describe, expect, it } from 'vitest';
-import { type FileSystemValidator, validateFile, validateFiles } from '../file-validator';
+import {
+  type FileSystemValidator,
+  InMemoryFileSystem,
+  validateFile,
+  validateFiles,
+} from '../file-validator';
 
 // Mock filesystem validator for testing
 class MockFileSystemValidator implements FileSystemValidator {
@@ -154,3 +159,28 @@ describe('validateFiles', () => {
     expect(results).toHaveLength(0);
   });
 });
+
+describe('InMemoryFileSystem', () => {
+  it('should serve files relative to its root', () => {
+    const fs = new InMemoryFileSystem({ 'api/server.go': 'package api\n' }, '/repo');
+
+    expect(fs.exists('/repo/api/server.go')).toBe(true);
+    expect(fs.isFile('/repo/api/server.go')).toBe(true);
+    expect(fs.readText('/repo/api/server.go')).toBe('package api\n');
+    expect(fs.exists('/repo/api/missing.go')).toBe(false);
+    expect(() => fs.readText('/repo/api/missing.go')).toThrow('No such file in memory');
+    expect(fs.files()).toEqual(['api/server.go']);
+  });
+
+  it('should overlay a fallback filesystem', () => {
+    const disk = new MockFileSystemValidator()
+      .addFile('/repo/a.go', 'saved')
+      .addFile('/repo/b.go', 'b');
+    const fs = new InMemoryFileSystem(new Map([['a.go', 'unsaved']]), '/repo', disk);
+
+    expect(fs.readText('/repo/a.go')).toBe('unsaved');
+    expect(fs.readText('/repo/b.go')).toBe('b');
+    expect(validateFile('b.go', '/repo/b.go', fs).isValid).toBe(true);
+    expect(fs.files()).toEqual(['a.go']);
+  });
+});
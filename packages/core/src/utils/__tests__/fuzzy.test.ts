@@ -0,0 +1,72 @@
+import { describe, expect, it } from 'vitest';
+import { fuzzyScore, levenshtein, rankFuzzyMatches, trigramSimilarity } from '../fuzzy';
+
+describe('fuzzy matching', () => {
+  describe('levenshtein', () => {
+    it('should compute edit distance', () => {
+      expect(levenshtein('kitten', 'sitting')).toBe(3);
+      expect(levenshtein('', 'abc')).toBe(3);
+      expect(levenshtein('same', 'same')).toBe(0);
+    });
+  });
+
+  describe('trigramSimilarity', () => {
+    it('should be 1 for identical strings and 0 for disjoint ones', () => {
+      expect(trigramSimilarity('backoff', 'backoff')).toBe(1);
+      expect(trigramSimilarity('abc', 'xyz')).toBe(0);
+    });
+
+    it('should be case-insensitive', () => {
+      expect(trigramSimilarity('Backoff', 'backoff')).toBe(1);
+    });
+  });
+
+  describe('fuzzyScore', () => {
+    it('should rank exact > prefix > substring > fuzzy', () => {
+      const exact = fuzzyScore('backoff', 'Backoff');
+      const prefix = fuzzyScore('backof', 'Backoff');
+      const substring = fuzzyScore('backof', 'ExpBackoff');
+      const typo = fuzzyScore('bakoff', 'Backoff');
+
+      expect(exact).toBe(1);
+      expect(prefix).toBeLessThan(exact);
+      expect(substring).toBeLessThan(prefix);
+      expect(typo).toBeLessThan(substring);
+      expect(typo).toBeGreaterThan(0.5);
+    });
+
+    it('should match the last segment of qualified names', () => {
+      expect(fuzzyScore('Start', 'Server.Start')).toBe(1);
+    });
+
+    it('should match names containing every query word', () => {
+      const twoOfThree = fuzzyScore('validate password', 'ValidateUserPassword');
+      const allWords = fuzzyScore('validate password', 'ValidatePassword');
+
+      expect(twoOfThree).toBeGreaterThan(fuzzyScore('validate pasword', 'ValidateUserPassword'));
+      expect(allWords).toBeGreaterThan(twoOfThree);
+      expect(fuzzyScore('server http', 'HTTPServer')).toBeGreaterThan(0.66);
+      expect(fuzzyScore('validate email', 'ValidateUserPassword')).toBeLessThan(0.66);
+    });
+  });
+
+  describe('rankFuzzyMatches', () => {
+    const names = ['ExpBackoff', 'NewExpBackoff', 'Backoff', 'Connection', 'retryWithBackoff'];
+
+    it('should rank matches and drop unrelated names', () => {
+      const matches = rankFuzzyMatches('backof', names, (n) => n);
+
+      expect(matches[0].item).toBe('Backoff');
+      expect(matches.map((m) => m.item)).not.toContain('Connection');
+    });
+
+    it('should respect limit and minScore', () => {
+      expect(rankFuzzyMatches('backof', names, (n) => n, { limit: 2 })).toHaveLength(2);
+      expect(rankFuzzyMatches('backof', names, (n) => n, { minScore: 0.95 })).toEqual([]);
+    });
+
+    it('should skip items without a name', () => {
+      expect(rankFuzzyMatches('x', [undefined], (n) => n)).toEqual([]);
+    });
+  });
+});
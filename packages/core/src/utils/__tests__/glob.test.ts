@@ -0,0 +1,20 @@
+import { describe, expect, it } from 'vitest';
+import { matchesGlob } from '../glob';
+
+describe('matchesGlob', () => {
+  it('should match a single segment with *', () => {
+    expect(matchesGlob('src/a.ts', 'src/*.ts')).toBe(true);
+    expect(matchesGlob('src/lib/a.ts', 'src/*.ts')).toBe(false);
+  });
+
+  it('should match any depth with **', () => {
+    expect(matchesGlob('node_modules/pkg/index.ts', '**/node_modules/**')).toBe(true);
+    expect(matchesGlob('api/service.pb.go', '**/*.pb.go')).toBe(true);
+    expect(matchesGlob('src/index.ts', '**/node_modules/**')).toBe(false);
+  });
+
+  it('should treat regex characters literally', () => {
+    expect(matchesGlob('a+b.ts', 'a+b.ts')).toBe(true);
+    expect(matchesGlob('aab.ts', 'a+b.ts')).toBe(false);
+  });
+});
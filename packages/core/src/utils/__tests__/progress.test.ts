@@ -0,0 +1,83 @@
+import { describe, expect, it, vi } from 'vitest';
+import { ThroughputEstimator, throttleProgress } from '../progress';
+
+function clock(start = 0) {
+  let time = start;
+  return {
+    now: () => time,
+    advance: (ms: number) => {
+      time += ms;
+    },
+  };
+}
+
+describe('ThroughputEstimator', () => {
+  it('should estimate time left from the recent rate', () => {
+    const { now, advance } = clock();
+    const estimator = new ThroughputEstimator(10_000, now);
+
+    expect(estimator.eta(100)).toBeUndefined();
+    estimator.record(0);
+    advance(2000);
+    estimator.record(20);
+
+    expect(estimator.rate()).toBe(10);
+    expect(estimator.eta(100)).toBe(8);
+  });
+
+  it('should forget throughput older than the window', () => {
+    const { now, advance } = clock();
+    const estimator = new ThroughputEstimator(1000, now);
+
+    // Slow start: 1 item/s
+    estimator.record(0);
+    advance(5000);
+    estimator.record(5);
+    // Then 50 items/s
+    advance(1000);
+    estimator.record(55);
+    advance(1000);
+    estimator.record(105);
+
+    expect(estimator.rate()).toBe(50);
+    expect(estimator.eta(205)).toBe(2);
+  });
+
+  it('should not estimate before any progress', () => {
+    const { now, advance } = clock();
+    const estimator = new ThroughputEstimator(10_000, now);
+
+    estimator.record(0);
+    advance(1000);
+    estimator.record(0);
+
+    expect(estimator.eta(10)).toBeUndefined();
+  });
+});
+
+describe('throttleProgress', () => {
+  it('should report at most once per interval unless forced', () => {
+    const { now, advance } = clock(1000);
+    const emit = vi.fn();
+    const report = throttleProgress<number>(emit, 250, now);
+
+    report(1);
+    advance(100);
+    report(2);
+    report(3, true);
+    advance(250);
+    report(4);
+
+    expect(emit.mock.calls.map(([n]) => n)).toEqual([1, 3, 4]);
+  });
+
+  it('should pass every report with a zero interval', () => {
+    const emit = vi.fn();
+    const report = throttleProgress<number>(emit, 0, () => 0);
+
+    report(1);
+    report(2);
+
+    expect(emit).toHaveBeenCalledTimes(2);
+  });
+});
@@ -327,6 +327,22 @@ describe('RetryPredicates', () => {
     });
   });
 
+  describe('transientHttp', () => {
+    it('should retry 429 and 5xx status codes', () => {
+      const predicate = RetryPredicates.transientHttp;
+      expect(predicate(Object.assign(new Error('Slow down'), { status: 429 }))).toBe(true);
+      expect(predicate(Object.assign(new Error('Oops'), { statusCode: 503 }))).toBe(true);
+      expect(predicate(new Error('Request failed with status 502'))).toBe(true);
+      expect(predicate(new Error('ECONNRESET'))).toBe(true);
+    });
+
+    it('should not retry client errors', () => {
+      const predicate = RetryPredicates.transientHttp;
+      expect(predicate(Object.assign(new Error('Bad request'), { status: 400 }))).toBe(false);
+      expect(predicate(new Error('Invalid input'))).toBe(false);
+    });
+  });
+
   describe('fromCodes', () => {
     it('should match specific error codes', () => {
       const predicate = RetryPredicates.fromCodes(['ENOENT', 'EACCES']);
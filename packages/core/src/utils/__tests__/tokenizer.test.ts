@@ -0,0 +1,38 @@
+import { AutoTokenizer } from '@xenova/transformers';
+import { afterEach, describe, expect, it, vi } from 'vitest';
+import { OutputTokenizer } from '../tokenizer';
+
+vi.mock('@xenova/transformers', () => ({ AutoTokenizer: { from_pretrained: vi.fn() } }));
+
+describe('OutputTokenizer', () => {
+  afterEach(() => {
+    vi.mocked(AutoTokenizer.from_pretrained).mockReset();
+  });
+
+  it('should estimate until the tokenizer loads', () => {
+    const tokenizer = new OutputTokenizer();
+
+    expect(tokenizer.loaded).toBe(false);
+    expect(tokenizer.countTokens('12345678')).toBe(2);
+  });
+
+  it('should count with the loaded tokenizer', async () => {
+    const encode = vi.fn((text: string) => text.split(' '));
+    vi.mocked(AutoTokenizer.from_pretrained).mockResolvedValue({ encode } as never);
+    const tokenizer = new OutputTokenizer('Xenova/test');
+
+    await expect(tokenizer.initialize()).resolves.toBe(true);
+    expect(AutoTokenizer.from_pretrained).toHaveBeenCalledWith('Xenova/test');
+    expect(tokenizer.countTokens('one two three')).toBe(3);
+    expect(encode).toHaveBeenCalledWith('one two three', null, { add_special_tokens: false });
+  });
+
+  it('should keep the fallback when the tokenizer cannot load', async () => {
+    vi.mocked(AutoTokenizer.from_pretrained).mockRejectedValue(new Error('offline'));
+    const tokenizer = new OutputTokenizer(undefined, (text) => text.length);
+
+    await expect(tokenizer.initialize()).resolves.toBe(false);
+    expect(tokenizer.loaded).toBe(false);
+    expect(tokenizer.countTokens('abc')).toBe(3);
+  });
+});
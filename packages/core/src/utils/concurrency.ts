@@ -86,3 +86,83 @@ export function getCurrentSystemResources(): SystemResources {
     memoryGB: os.totalmem() / (1024 * 1024 * 1024),
   };
 }
+
+/**
+ * Default cap on in-flight embedding requests, low enough for hosted providers' rate limits
+ */
+export const DEFAULT_MAX_CONCURRENT_EMBEDDINGS = 4;
+
+/**
+ * Resolve the cap on in-flight embedding requests
+ *
+ * An explicit setting wins, then DEV_AGENT_EMBEDDING_CONCURRENCY, then the
+ * default. Unlike parse concurrency, DEV_AGENT_CONCURRENCY doesn't apply:
+ * the cap protects a remote rate limit rather than local resources.
+ */
+export function getEmbeddingConcurrency(
+  configured: number | undefined,
+  environmentVariables: Record<string, string | undefined>
+): number {
+  if (configured !== undefined && Number.isInteger(configured) && configured > 0) {
+    return configured;
+  }
+  const parsed = Number.parseInt(environmentVariables.DEV_AGENT_EMBEDDING_CONCURRENCY ?? '', 10);
+  if (!Number.isNaN(parsed) && parsed > 0 && parsed <= 100) {
+    return parsed;
+  }
+  return DEFAULT_MAX_CONCURRENT_EMBEDDINGS;
+}
+
+/**
+ * Caps how many async tasks run at once; the rest wait in FIFO order
+ */
+export class ConcurrencyLimiter {
+  private active = 0;
+  private readonly waiting: Array<() => void> = [];
+
+  constructor(readonly limit: number) {
+    if (!Number.isInteger(limit) || limit < 1) {
+      throw new Error(`Concurrency limit must be a positive integer, got ${limit}`);
+    }
+  }
+
+  /** Tasks currently running */
+  get inFlight(): number {
+    return this.active;
+  }
+
+  /** Tasks waiting for a slot */
+  get queued(): number {
+    return this.waiting.length;
+  }
+
+  /**
+   * Run a task once a slot is free, releasing the slot when it settles
+   */
+  async run<T>(task: () => Promise<T>): Promise<T> {
+    await this.acquire();
+    try {
+      return await task();
+    } finally {
+      this.release();
+    }
+  }
+
+  private acquire(): Promise<void> {
+    if (this.active < this.limit) {
+      this.active++;
+      return Promise.resolve();
+    }
+    return new Promise((resolve) => this.waiting.push(resolve));
+  }
+
+  private release(): void {
+    // Hand the slot straight to the next waiter so it can't be taken out of order
+    const next = this.waiting.shift();
+    if (next) {
+      next();
+    } else {
+      this.active--;
+    }
+  }
+}
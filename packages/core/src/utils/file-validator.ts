@@ -3,6 +3,8 @@
  * Separated for testability and consistency across scanners
  */
 
+import * as path from 'node:path';
+
 export interface FileValidationResult {
   isValid: boolean;
   error?: string;
@@ -39,6 +41,62 @@ export class NodeFileSystemValidator implements FileSystemValidator {
   }
 }
 
+/**
+ * Filesystem over in-memory file contents, e.g. an editor's unsaved buffers
+ *
+ * Paths are resolved against `root`, so a scanner reading
+ * `path.join(root, file)` finds the content as it would on disk. Paths not
+ * held in memory go to `fallback` when one is given, overlaying the buffers
+ * on the working tree; otherwise they don't exist.
+ */
+export class InMemoryFileSystem implements FileSystemValidator {
+  private contents = new Map<string, string>();
+
+  /**
+   * @param files - Content by path, relative to `root` or absolute
+   * @param root - Directory relative paths are under (default: `/`)
+   * @param fallback - Filesystem for paths not held in memory
+   */
+  constructor(
+    files: Record<string, string> | Map<string, string>,
+    private root = '/',
+    private fallback?: FileSystemValidator
+  ) {
+    const entries = files instanceof Map ? files.entries() : Object.entries(files);
+    for (const [file, content] of entries) {
+      this.contents.set(path.resolve(root, file), content);
+    }
+  }
+
+  exists(filePath: string): boolean {
+    return this.contents.has(this.resolve(filePath)) || !!this.fallback?.exists(filePath);
+  }
+
+  isFile(filePath: string): boolean {
+    return this.contents.has(this.resolve(filePath)) || !!this.fallback?.isFile(filePath);
+  }
+
+  readText(filePath: string): string {
+    const content = this.contents.get(this.resolve(filePath));
+    if (content !== undefined) return content;
+    if (this.fallback) return this.fallback.readText(filePath);
+    throw new Error(`No such file in memory: ${filePath}`);
+  }
+
+  /**
+   * Paths held in memory, relative to the root, in sorted order
+   */
+  files(): string[] {
+    return [...this.contents.keys()]
+      .map((file) => path.relative(this.root, file).split(path.sep).join('/'))
+      .sort();
+  }
+
+  private resolve(filePath: string): string {
+    return path.resolve(this.root, filePath);
+  }
+}
+
 /**
  * Validate a single file for processing
  */
@@ -0,0 +1,142 @@
+/**
+ * Fuzzy name matching
+ *
+ * Lexical (non-embedding) matching for half-remembered identifiers.
+ * Combines trigram overlap with edit distance so both typos ("backof")
+ * and partial names ("Backoff" for "ExpBackoff") rank well, and matches
+ * identifier words so "validate password" finds "ValidateUserPassword".
+ */
+
+import { identifierTokens, splitIdentifier } from '../search/identifiers';
+
+/**
+ * Split a string into lowercase trigrams, padded so short names still produce grams
+ */
+export function trigrams(value: string): Set<string> {
+  const padded = `  ${value.toLowerCase()} `;
+  const grams = new Set<string>();
+  for (let i = 0; i < padded.length - 2; i++) {
+    grams.add(padded.slice(i, i + 3));
+  }
+  return grams;
+}
+
+/**
+ * Jaccard similarity of two strings' trigram sets (0-1)
+ */
+export function trigramSimilarity(a: string, b: string): number {
+  const ga = trigrams(a);
+  const gb = trigrams(b);
+  let shared = 0;
+  for (const gram of ga) {
+    if (gb.has(gram)) shared++;
+  }
+  const union = ga.size + gb.size - shared;
+  return union === 0 ? 0 : shared / union;
+}
+
+/**
+ * Levenshtein edit distance (case-sensitive)
+ */
+export function levenshtein(a: string, b: string): number {
+  if (a === b) return 0;
+  if (a.length === 0) return b.length;
+  if (b.length === 0) return a.length;
+
+  let previous = Array.from({ length: b.length + 1 }, (_, i) => i);
+  for (let i = 1; i <= a.length; i++) {
+    const current = [i];
+    for (let j = 1; j <= b.length; j++) {
+      const cost = a[i - 1] === b[j - 1] ? 0 : 1;
+      current[j] = Math.min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost);
+    }
+    previous = current;
+  }
+  return previous[b.length];
+}
+
+/**
+ * Score how well a candidate name matches a query (0-1)
+ *
+ * Ranking tiers: exact > prefix > substring > qualifier substring > all
+ * query words in the name > fuzzy. Qualified names (Type.method) are matched
+ * on their last segment first.
+ */
+export function fuzzyScore(query: string, candidate: string): number {
+  const q = query.toLowerCase();
+  const full = candidate.toLowerCase();
+  const short = full.slice(full.lastIndexOf('.') + 1);
+
+  if (full === q || short === q) return 1;
+  if (short.startsWith(q)) return 0.9 + 0.05 * (q.length / short.length);
+  if (full.startsWith(q)) return 0.9 + 0.05 * (q.length / full.length);
+  if (short.includes(q)) return 0.8 + 0.05 * (q.length / short.length);
+  // Match only in the qualifier (e.g. the type of a method)
+  if (full.includes(q)) return 0.7 + 0.05 * (q.length / full.length);
+
+  const words = wordCoverage(query, candidate);
+  if (words > 0) return 0.66 + 0.03 * words;
+
+  const best = Math.max(similarity(q, short), similarity(q, full));
+  return Math.min(best, 0.69) * 0.95;
+}
+
+/**
+ * A ranked fuzzy match
+ */
+export interface FuzzyMatch<T> {
+  item: T;
+  score: number;
+}
+
+/**
+ * Rank items by fuzzy name match
+ *
+ * @param query - Partial or misspelled name
+ * @param items - Candidates
+ * @param getName - Extract the name to match from an item
+ * @param options - Result limit and minimum score (default: 10, 0.3)
+ */
+export function rankFuzzyMatches<T>(
+  query: string,
+  items: T[],
+  getName: (item: T) => string | undefined,
+  options: { limit?: number; minScore?: number } = {}
+): FuzzyMatch<T>[] {
+  const { limit = 10, minScore = 0.3 } = options;
+  const matches: FuzzyMatch<T>[] = [];
+
+  for (const item of items) {
+    const name = getName(item);
+    if (!name) continue;
+    const score = fuzzyScore(query, name);
+    if (score >= minScore) {
+      matches.push({ item, score });
+    }
+  }
+
+  return matches
+    .sort((a, b) => b.score - a.score || compareNames(getName(a.item), getName(b.item)))
+    .slice(0, limit);
+}
+
+/**
+ * Share of the candidate's words covered when every word of a multi-word
+ * query is one of its keyword tokens, otherwise 0
+ */
+function wordCoverage(query: string, candidate: string): number {
+  const words = [...new Set(splitIdentifier(query))];
+  if (words.length < 2) return 0;
+  const tokens = new Set(candidate.split('.').flatMap(identifierTokens));
+  if (!words.every((word) => tokens.has(word))) return 0;
+  return words.length / Math.max(words.length, splitIdentifier(candidate).length);
+}
+
+function similarity(a: string, b: string): number {
+  const edit = 1 - levenshtein(a, b) / Math.max(a.length, b.length);
+  return Math.max(edit, trigramSimilarity(a, b));
+}
+
+function compareNames(a: string | undefined, b: string | undefined): number {
+  return (a ?? '').length - (b ?? '').length || (a ?? '').localeCompare(b ?? '');
+}
@@ -0,0 +1,69 @@
+/**
+ * Glob matching
+ *
+ * A small glob-to-regex translation for repository-relative paths: `*`, `?`,
+ * `**`, `[...]` sets, and `{a,b}` alternatives. Used where node's
+ * `path.matchesGlob` can't be (it needs Node 22.5+).
+ */
+
+/** Compiled patterns, since the same few globs are checked against every file */
+const compiled = new Map<string, RegExp>();
+
+/**
+ * Check whether a repository-relative path matches a glob
+ */
+export function matchesGlob(file: string, glob: string): boolean {
+  let pattern = compiled.get(glob);
+  if (!pattern) {
+    pattern = new RegExp(`^${globToRegExpSource(glob)}$`);
+    compiled.set(glob, pattern);
+  }
+  return pattern.test(file);
+}
+
+/**
+ * Regular expression source for a glob, without anchors
+ *
+ * Wildcards stop at `/` and at `:`, so a pattern can be matched against a
+ * document ID's `path:` prefix.
+ */
+export function globToRegExpSource(glob: string): string {
+  let source = '';
+  let braces = 0;
+  for (let i = 0; i < glob.length; i++) {
+    const char = glob[i];
+    if (char === '*' && glob[i + 1] === '*') {
+      // `**/` also matches no directories at all
+      const slash = glob[i + 2] === '/';
+      source += slash ? '(?:.*/)?' : '.*';
+      i += slash ? 2 : 1;
+    } else if (char === '*') {
+      source += '[^/:]*';
+    } else if (char === '?') {
+      source += '[^/:]';
+    } else if (char === '[' && glob.indexOf(']', i + 2) !== -1) {
+      const end = glob.indexOf(']', i + 2);
+      const set = glob.slice(i + 1, end).replace(/^!/, '^').replace(/\\/g, '\\\\');
+      source += `[${set}]`;
+      i = end;
+    } else if (char === '{') {
+      source += '(?:';
+      braces++;
+    } else if (char === '}' && braces > 0) {
+      source += ')';
+      braces--;
+    } else if (char === ',' && braces > 0) {
+      source += '|';
+    } else {
+      source += escapeRegExp(char);
+    }
+  }
+  return source;
+}
+
+/**
+ * Escape text for use inside a regular expression
+ */
+export function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
@@ -4,7 +4,11 @@
 
 export * from './concurrency';
 export * from './file-validator';
+export * from './fuzzy';
+export * from './glob';
 export * from './icons';
+export * from './progress';
 export * from './retry';
 export * from './test-utils';
+export * from './tokenizer';
 export * from './wasm-resolver';
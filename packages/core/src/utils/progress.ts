@@ -0,0 +1,92 @@
+/**
+ * Progress utilities
+ * Throttling and throughput-based ETAs for progress callbacks
+ *
+ * Scanners and the indexer report after every file or batch, far more often
+ * than a progress bar or an MCP client needs. Reports are throttled to a few
+ * per second, so forwarding them costs nothing next to parsing and embedding.
+ * ETAs come from throughput over the last few seconds rather than the whole
+ * run, so they recover quickly when a slow phase or language gives way to a
+ * fast one.
+ */
+
+/** Minimum time between throttled progress reports: four per second */
+export const DEFAULT_PROGRESS_INTERVAL_MS = 250;
+
+/** How far back throughput is measured for ETAs */
+const DEFAULT_THROUGHPUT_WINDOW_MS = 10_000;
+
+/**
+ * Estimates time left from recent throughput
+ */
+export class ThroughputEstimator {
+  private samples: Array<{ time: number; done: number }> = [];
+
+  /**
+   * @param windowMs - How far back throughput is measured (default: 10s)
+   * @param now - Clock, for tests
+   */
+  constructor(
+    private readonly windowMs = DEFAULT_THROUGHPUT_WINDOW_MS,
+    private readonly now: () => number = Date.now
+  ) {}
+
+  /**
+   * Record how many items are done so far
+   */
+  record(done: number): void {
+    const time = this.now();
+    this.samples.push({ time, done });
+    // Keep the newest sample at or before the window's start, so the window stays full
+    while (this.samples.length > 2 && this.samples[1].time <= time - this.windowMs) {
+      this.samples.shift();
+    }
+  }
+
+  /**
+   * Items per second over the window, or undefined before anything was done in it
+   */
+  rate(): number | undefined {
+    const first = this.samples[0];
+    const last = this.samples.at(-1);
+    if (!first || !last) return undefined;
+    const seconds = (last.time - first.time) / 1000;
+    const done = last.done - first.done;
+    return seconds > 0 && done > 0 ? done / seconds : undefined;
+  }
+
+  /**
+   * Seconds until `total` items are done at the recent rate, or undefined when unknown
+   */
+  eta(total: number): number | undefined {
+    const last = this.samples.at(-1);
+    const rate = this.rate();
+    if (!last || rate === undefined) return undefined;
+    return Math.max(0, Math.ceil((total - last.done) / rate));
+  }
+}
+
+/**
+ * Wrap a progress callback so it runs at most once per interval
+ *
+ * Reports inside the interval are dropped, not delayed, so the caller should
+ * force the ones that must arrive: phase changes and the final report.
+ *
+ * @param emit - Callback to throttle
+ * @param intervalMs - Minimum time between reports (default: 250ms; 0 passes every report)
+ * @param now - Clock, for tests
+ * @returns The throttled callback; `force` reports regardless of the interval
+ */
+export function throttleProgress<T>(
+  emit: (progress: T) => void,
+  intervalMs = DEFAULT_PROGRESS_INTERVAL_MS,
+  now: () => number = Date.now
+): (progress: T, force?: boolean) => void {
+  let last = Number.NEGATIVE_INFINITY;
+  return (progress, force = false) => {
+    const time = now();
+    if (!force && time - last < intervalMs) return;
+    last = time;
+    emit(progress);
+  };
+}
@@ -153,6 +153,20 @@ export const RetryPredicates = {
     return message.includes('rate limit') || message.includes('too many requests');
   },
 
+  /**
+   * Retry rate limits (429), server errors (5xx), and network errors.
+   * Reads `status`/`statusCode` when the error carries one, otherwise the message.
+   */
+  transientHttp: (error: unknown) => {
+    if (!(error instanceof Error)) return false;
+    const { status, statusCode } = error as Error & { status?: number; statusCode?: number };
+    const code = status ?? statusCode;
+    if (typeof code === 'number') {
+      return code === 429 || (code >= 500 && code < 600);
+    }
+    return defaultIsRetriable(error) || /\b(429|5\d\d)\b/.test(error.message);
+  },
+
   /** Custom predicate from error codes */
   fromCodes: (codes: string[]) => (error: unknown) => {
     if (!(error instanceof Error)) return false;
@@ -0,0 +1,68 @@
+/**
+ * Output Tokenizer
+ * Counts tokens the way an LLM reading tool output does
+ *
+ * The embedding model's tokenizer undercounts code for chat models, so tool
+ * output budgets use a GPT-family BPE tokenizer instead. Only the tokenizer
+ * files are downloaded (no model weights), on first use, through
+ * transformers.js. Until it has loaded, or if it can't be loaded, counts fall
+ * back to an estimate, so callers never wait on or fail because of it.
+ */
+
+import { AutoTokenizer, type PreTrainedTokenizer } from '@xenova/transformers';
+import { estimateTokenCount, type TokenCounter } from '../indexer/utils/truncation';
+
+/** Tokenizer loaded when none is configured */
+export const DEFAULT_OUTPUT_TOKENIZER = 'Xenova/gpt-4o';
+
+/**
+ * Output Tokenizer
+ * Real token counts once loaded, estimates before
+ */
+export class OutputTokenizer {
+  private tokenizer: PreTrainedTokenizer | null = null;
+  private loading: Promise<boolean> | null = null;
+
+  /**
+   * @param modelName - Hugging Face repository with the tokenizer files
+   * @param fallback - Counter used until the tokenizer loads (default: ~4 characters per token)
+   */
+  constructor(
+    readonly modelName = DEFAULT_OUTPUT_TOKENIZER,
+    private readonly fallback: TokenCounter = estimateTokenCount
+  ) {}
+
+  /**
+   * Whether counts come from the real tokenizer
+   */
+  get loaded(): boolean {
+    return this.tokenizer !== null;
+  }
+
+  /**
+   * Load the tokenizer; concurrent calls share one load
+   *
+   * @returns Whether the tokenizer loaded (false leaves the fallback in use)
+   */
+  async initialize(): Promise<boolean> {
+    if (this.tokenizer) return true;
+    this.loading ??= AutoTokenizer.from_pretrained(this.modelName)
+      .then((tokenizer) => {
+        this.tokenizer = tokenizer;
+        return true;
+      })
+      .catch(() => false)
+      .finally(() => {
+        this.loading = null;
+      });
+    return this.loading;
+  }
+
+  /**
+   * Count tokens, special tokens excluded
+   */
+  countTokens(text: string): number {
+    if (!this.tokenizer) return this.fallback(text);
+    return this.tokenizer.encode(text, null, { add_special_tokens: false }).length;
+  }
+}
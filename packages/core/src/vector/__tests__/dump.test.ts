@@ -0,0 +1,113 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it } from 'vitest';
+import {
+  DUMP_FORMAT,
+  DUMP_VERSION,
+  type DumpHeader,
+  decodeVector,
+  encodeVector,
+  readDump,
+  writeDump,
+} from '../dump';
+import { LanceDBVectorStore } from '../store';
+import type { StoredRecord } from '../types';
+
+const header = (documents: number): DumpHeader => ({
+  format: DUMP_FORMAT,
+  version: DUMP_VERSION,
+  embeddingModel: 'test-model',
+  dimension: 3,
+  metric: 'cosine',
+  documents,
+  exportedAt: '2024-06-01T00:00:00.000Z',
+});
+
+describe('vector encoding', () => {
+  it('should round-trip float32 values exactly', () => {
+    const vector = [Math.fround(0.1), Math.fround(-0.7071067811865476), 0, Math.fround(1e-8)];
+
+    expect(decodeVector(encodeVector(vector))).toEqual(vector);
+  });
+
+  it('should reject truncated vectors', () => {
+    expect(() => decodeVector(Buffer.from([1, 2, 3]).toString('base64'))).toThrow(/float32/);
+  });
+});
+
+describe('index dumps', () => {
+  let testDir: string;
+
+  beforeEach(async () => {
+    testDir = await fs.mkdtemp(path.join(os.tmpdir(), 'index-dump-'));
+  });
+
+  afterEach(async () => {
+    await fs.rm(testDir, { recursive: true, force: true });
+  });
+
+  const records: StoredRecord[] = [
+    {
+      id: 'retry/retry.go:Retry:10',
+      text: 'function: Retry',
+      metadata: { path: 'retry/retry.go', name: 'Retry', startLine: 10 },
+      vector: [0.6, 0.8, 0].map(Math.fround),
+    },
+    {
+      id: 'retry/backoff.go:Backoff:3',
+      text: 'function: Backoff',
+      metadata: { path: 'retry/backoff.go', name: 'Backoff', startLine: 3 },
+      vector: [0, 0.6, 0.8].map(Math.fround),
+    },
+  ];
+
+  it('should write one greppable record per line after the header', async () => {
+    const file = path.join(testDir, 'index.ndjson');
+    await writeDump(file, header(2), records);
+
+    const lines = (await fs.readFile(file, 'utf-8')).trimEnd().split('\n');
+    expect(lines).toHaveLength(3);
+    expect(JSON.parse(lines[0])).toMatchObject({ format: DUMP_FORMAT, documents: 2 });
+    expect(lines.filter((line) => line.includes('retry/backoff.go'))).toHaveLength(1);
+
+    const dump = await readDump(file);
+    expect(dump.header).toEqual(header(2));
+    expect(dump.records).toEqual(records);
+  });
+
+  it('should reject truncated dumps and unknown formats', async () => {
+    const truncated = path.join(testDir, 'truncated.ndjson');
+    await writeDump(truncated, header(3), records);
+    await expect(readDump(truncated)).rejects.toThrow(/may be truncated/);
+
+    const other = path.join(testDir, 'other.ndjson');
+    await fs.writeFile(other, '{"hello":"world"}\n');
+    await expect(readDump(other)).rejects.toThrow(/not a dev-agent index dump/);
+  });
+
+  it('should preserve search results through export and import', async () => {
+    const original = new LanceDBVectorStore(path.join(testDir, 'original.lance'), 3);
+    await original.initialize();
+    await original.add(
+      records.map(({ id, text, metadata }) => ({ id, text, metadata })),
+      [
+        [3, 4, 0],
+        [0, 1, 1],
+      ]
+    );
+
+    const file = path.join(testDir, 'index.ndjson');
+    const exported = await original.getRecords();
+    await writeDump(file, { ...header(exported.length), metric: original.metric }, exported);
+
+    const { header: read, records: imported } = await readDump(file);
+    const restored = new LanceDBVectorStore(path.join(testDir, 'restored.lance'), 3);
+    await restored.initialize();
+    await restored.replaceRecords(imported, read.metric);
+
+    const query = [0.2, 0.9, 0.4];
+    expect(await restored.search(query)).toEqual(await original.search(query));
+    expect(restored.metric).toBe(original.metric);
+  });
+});
@@ -0,0 +1,49 @@
+import { pipeline } from '@xenova/transformers';
+import { afterEach, describe, expect, it, vi } from 'vitest';
+import { EmbeddingsUnavailableError, TransformersEmbedder } from '../embedder';
+
+vi.mock('@xenova/transformers', () => ({ pipeline: vi.fn() }));
+
+describe('TransformersEmbedder when the model is unavailable', () => {
+  afterEach(() => {
+    vi.mocked(pipeline).mockReset();
+    vi.useRealTimers();
+  });
+
+  it('should report a failed load as unavailable embeddings', async () => {
+    vi.mocked(pipeline).mockRejectedValue(new Error('fetch failed'));
+    const embedder = new TransformersEmbedder();
+
+    const error = await embedder.initialize().catch((e: unknown) => e);
+
+    expect(error).toBeInstanceOf(EmbeddingsUnavailableError);
+    expect((error as EmbeddingsUnavailableError).reason).toBe('fetch failed');
+    expect((error as Error).message).toMatch(/^Embeddings unavailable: could not load embedding/);
+  });
+
+  it('should give up on a load that hangs', async () => {
+    vi.useFakeTimers();
+    vi.mocked(pipeline).mockReturnValue(new Promise(() => {}));
+    const embedder = new TransformersEmbedder('Xenova/all-MiniLM-L6-v2', 384, 5000);
+
+    const loading = embedder.initialize();
+    vi.advanceTimersByTime(5000);
+
+    await expect(loading).rejects.toThrow('timed out after 5s');
+  });
+
+  it('should share one load and retry after a failure', async () => {
+    vi.mocked(pipeline).mockRejectedValueOnce(new Error('offline'));
+    const embedder = new TransformersEmbedder();
+
+    const results = await Promise.allSettled([embedder.initialize(), embedder.initialize()]);
+    expect(results.map((r) => r.status)).toEqual(['rejected', 'rejected']);
+    expect(pipeline).toHaveBeenCalledTimes(1);
+
+    vi.mocked(pipeline).mockResolvedValueOnce(
+      vi.fn() as unknown as Awaited<ReturnType<typeof pipeline>>
+    );
+    await expect(embedder.initialize()).resolves.toBeUndefined();
+    expect(pipeline).toHaveBeenCalledTimes(2);
+  });
+});
@@ -20,6 +20,12 @@ describe('TransformersEmbedder', () => {
     expect(customEmbedder.modelName).toBe('Xenova/all-MiniLM-L6-v2');
   });
 
+  it('should count tokens with the model tokenizer', () => {
+    expect(embedder.countTokens('hello world')).toBe(2);
+    expect(embedder.countTokens('')).toBe(0);
+    expect(() => new TransformersEmbedder().countTokens('x')).toThrow('not initialized');
+  });
+
   it('should generate embeddings for single text', async () => {
     const text = 'This is a test sentence';
     const embedding = await embedder.embed(text);
@@ -0,0 +1,184 @@
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { EmbeddingsUnavailableError, TransformersEmbedder } from '../embedder';
+import { EmbeddingCache } from '../embedding-cache';
+import { VectorStorage } from '../index';
+import { LanceDBVectorStore } from '../store';
+
+const vector = (seed: number, dimension = 4) =>
+  Array.from({ length: dimension }, (_, i) => seed + i / 4);
+
+describe('EmbeddingCache', () => {
+  let cacheDir: string;
+
+  beforeEach(async () => {
+    cacheDir = await fs.mkdtemp(path.join(os.tmpdir(), 'embedding-cache-'));
+  });
+
+  afterEach(async () => {
+    await fs.rm(cacheDir, { recursive: true, force: true });
+  });
+
+  const createCache = (overrides: { modelName?: string; maxEntries?: number } = {}) =>
+    new EmbeddingCache({
+      cacheDir,
+      provider: 'transformers',
+      modelName: 'test-model',
+      dimension: 4,
+      ...overrides,
+    });
+
+  it('should count hits and misses', async () => {
+    const cache = createCache();
+    await cache.load();
+
+    expect(cache.get('func Retry()')).toBeUndefined();
+    cache.set('func Retry()', vector(1));
+
+    expect(cache.get('func Retry()')).toEqual(vector(1));
+    expect(cache.getStats()).toEqual({ hits: 1, misses: 1, entries: 1 });
+
+    cache.resetStats();
+    expect(cache.getStats()).toEqual({ hits: 0, misses: 0, entries: 1 });
+  });
+
+  it('should persist vectors across instances', async () => {
+    const first = createCache();
+    await first.load();
+    first.set('func Retry()', vector(1));
+    await first.flush();
+
+    const second = createCache();
+    await second.load();
+
+    expect(second.get('func Retry()')).toEqual(vector(1));
+  });
+
+  it('should not reuse vectors from a different model', async () => {
+    const first = createCache();
+    await first.load();
+    first.set('func Retry()', vector(1));
+    await first.flush();
+
+    const other = createCache({ modelName: 'other-model' });
+    await other.load();
+
+    expect(other.get('func Retry()')).toBeUndefined();
+  });
+
+  it('should drop the least recently used entries past the limit', async () => {
+    const cache = createCache({ maxEntries: 2 });
+    await cache.load();
+    cache.set('a', vector(1));
+    cache.set('b', vector(2));
+    cache.get('a');
+    cache.set('c', vector(3));
+
+    expect(cache.get('a')).toEqual(vector(1));
+    expect(cache.get('b')).toBeUndefined();
+    expect(cache.get('c')).toEqual(vector(3));
+  });
+
+  it('should ignore vectors of the wrong dimension', async () => {
+    const cache = createCache();
+    await cache.load();
+    cache.set('a', vector(1, 3));
+
+    expect(cache.getStats().entries).toBe(0);
+  });
+
+  it('should start empty when the cache file is corrupt', async () => {
+    const first = createCache();
+    await first.load();
+    first.set('a', vector(1));
+    await first.flush();
+    const [file] = await fs.readdir(cacheDir);
+    await fs.appendFile(path.join(cacheDir, file), 'x');
+
+    const second = createCache();
+    await second.load();
+
+    expect(second.getStats().entries).toBe(0);
+  });
+});
+
+describe('VectorStorage embedding cache', () => {
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'vector-cache-'));
+    vi.spyOn(TransformersEmbedder.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(TransformersEmbedder.prototype, 'embedBatch').mockImplementation(async (texts) =>
+      texts.map((text) => vector(text.length, 384))
+    );
+    vi.spyOn(LanceDBVectorStore.prototype, 'initialize').mockResolvedValue(undefined);
+    vi.spyOn(LanceDBVectorStore.prototype, 'add').mockResolvedValue(undefined);
+    vi.spyOn(LanceDBVectorStore.prototype, 'close').mockResolvedValue(undefined);
+  });
+
+  afterEach(async () => {
+    vi.restoreAllMocks();
+    await fs.rm(tempDir, { recursive: true, force: true });
+  });
+
+  const docs = (...texts: string[]) =>
+    texts.map((text, i) => ({ id: `doc-${i}`, text, metadata: {} }));
+
+  it('should embed only texts missing from the cache, across instances', async () => {
+    const config = {
+      storePath: path.join(tempDir, 'vectors'),
+      embeddingCacheDir: path.join(tempDir, 'embedding-cache'),
+    };
+    const first = new VectorStorage(config);
+    await first.initialize();
+    await first.addDocuments(docs('func A()', 'func B()'));
+    await first.close();
+
+    vi.mocked(TransformersEmbedder.prototype.embedBatch).mockClear();
+    const second = new VectorStorage(config);
+    await second.initialize();
+    await second.addDocuments(docs('func A()', 'func Changed()'));
+
+    expect(TransformersEmbedder.prototype.embedBatch).toHaveBeenCalledWith(
+      ['func Changed()'],
+      undefined
+    );
+    expect(LanceDBVectorStore.prototype.add).toHaveBeenLastCalledWith(
+      docs('func A()', 'func Changed()'),
+      [vector(8, 384), vector(14, 384)]
+    );
+    expect(second.getEmbeddingCacheStats()).toEqual({ hits: 1, misses: 1, entries: 3 });
+    await second.close();
+  });
+
+  it('should store cached embeddings without loading the model', async () => {
+    const config = {
+      storePath: path.join(tempDir, 'vectors'),
+      embeddingCacheDir: path.join(tempDir, 'embedding-cache'),
+    };
+    const first = new VectorStorage(config);
+    await first.initialize();
+    await first.addDocuments(docs('func A()'));
+    await first.close();
+
+    vi.mocked(TransformersEmbedder.prototype.initialize).mockRejectedValue(
+      new EmbeddingsUnavailableError('Xenova/all-MiniLM-L6-v2', 'offline')
+    );
+    const second = new VectorStorage(config);
+    await second.initialize({ skipEmbedder: true });
+
+    await expect(second.addDocuments(docs('func A()'))).resolves.toBeUndefined();
+    await expect(second.addDocuments(docs('func New()'))).rejects.toThrow(
+      EmbeddingsUnavailableError
+    );
+    await second.close();
+  });
+
+  it('should report no stats without a cache', async () => {
+    const storage = new VectorStorage({ storePath: path.join(tempDir, 'vectors') });
+
+    expect(storage.getEmbeddingCacheStats()).toBeNull();
+  });
+});
@@ -0,0 +1,62 @@
+import { describe, expect, it } from 'vitest';
+import {
+  bytesPerVector,
+  dequantizeInt8,
+  quantizedDistance,
+  quantizeInt8,
+  vectorDistance,
+} from '../quantization';
+
+describe('quantizeInt8', () => {
+  it('should map the largest component onto ±127', () => {
+    const { codes, scale } = quantizeInt8([0.5, -1, 0.25]);
+
+    expect(Array.from(codes)).toEqual([64, -127, 32]);
+    expect(scale).toBeCloseTo(1 / 127);
+  });
+
+  it('should round-trip within half a step', () => {
+    const vector = [0.12, -0.58, 0.33, 0.91, -0.04];
+    const quantized = quantizeInt8(vector);
+    const restored = dequantizeInt8(quantized);
+
+    for (const [i, value] of vector.entries()) {
+      expect(Math.abs(restored[i] - value)).toBeLessThanOrEqual(quantized.scale / 2);
+    }
+  });
+
+  it('should handle zero vectors', () => {
+    const quantized = quantizeInt8([0, 0, 0]);
+
+    expect(Array.from(quantized.codes)).toEqual([0, 0, 0]);
+    expect(dequantizeInt8(quantized)).toEqual([0, 0, 0]);
+  });
+});
+
+describe('vectorDistance', () => {
+  it('should report distances in LanceDB units', () => {
+    expect(vectorDistance([1, 0], [0, 1], 'cosine')).toBeCloseTo(1);
+    expect(vectorDistance([1, 0], [2, 0], 'cosine')).toBeCloseTo(0);
+    expect(vectorDistance([1, 2], [3, 4], 'dot')).toBeCloseTo(1 - 11);
+    expect(vectorDistance([1, 2], [3, 4], 'euclidean')).toBeCloseTo(8);
+  });
+
+  it('should apply the scale to the stored vector', () => {
+    expect(vectorDistance([1, 2], [30, 40], 'dot', 0.1)).toBeCloseTo(1 - 11);
+  });
+
+  it('should stay close to the float distance for quantized vectors', () => {
+    const query = [0.3, -0.2, 0.9, 0.1];
+    const vector = [0.25, -0.1, 0.8, 0.4];
+    const exact = vectorDistance(query, vector, 'cosine');
+
+    expect(quantizedDistance(query, quantizeInt8(vector), 'cosine')).toBeCloseTo(exact, 3);
+  });
+});
+
+describe('bytesPerVector', () => {
+  it('should take about a quarter of the float32 size for int8', () => {
+    expect(bytesPerVector(384, 'none')).toBe(1536);
+    expect(bytesPerVector(384, 'int8')).toBe(388);
+  });
+});
@@ -3,7 +3,20 @@
  * Focus on testing the distance-to-similarity conversion bug fix
  */
 
-import { describe, expect, it } from 'vitest';
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import { afterEach, describe, expect, it } from 'vitest';
+import {
+  changedAfter,
+  distanceToScore,
+  LanceDBVectorStore,
+  matchesFilter,
+  matchesPathFilter,
+  normalizeForMetric,
+  pathFilterPattern,
+} from '../store';
+import type { QuantizationConfig } from '../types';
 
 describe('LanceDB Distance to Similarity Conversion', () => {
   describe('Score Calculation', () => {
@@ -176,3 +189,373 @@ describe('LanceDB Distance to Similarity Conversion', () => {
     });
   });
 });
+
+describe('matchesFilter', () => {
+  const metadata = { path: 'a.go', type: 'function', exported: true };
+
+  it('should match everything without a filter', () => {
+    expect(matchesFilter(metadata, undefined)).toBe(true);
+    expect(matchesFilter(metadata, {})).toBe(true);
+  });
+
+  it('should require every key to match', () => {
+    expect(matchesFilter(metadata, { exported: true })).toBe(true);
+    expect(matchesFilter(metadata, { exported: true, type: 'method' })).toBe(false);
+    expect(matchesFilter(metadata, { exported: false })).toBe(false);
+  });
+
+  it('should treat missing keys as non-matching', () => {
+    expect(matchesFilter({ path: 'a.md' }, { exported: true })).toBe(false);
+  });
+});
+
+describe('changedAfter', () => {
+  it('should compare last-modified dates', () => {
+    const metadata = { lastModified: '2024-06-15T10:00:00.000Z' };
+
+    expect(changedAfter(metadata, undefined)).toBe(true);
+    expect(changedAfter(metadata, '2024-06-01')).toBe(true);
+    expect(changedAfter(metadata, '2024-06-15T10:00:00Z')).toBe(true);
+    expect(changedAfter(metadata, '2024-07-01')).toBe(false);
+  });
+
+  it('should exclude symbols without blame data once a date is given', () => {
+    expect(changedAfter({ path: 'a.go' }, '2024-06-01')).toBe(false);
+  });
+});
+
+describe('matchesPathFilter', () => {
+  it('should treat plain paths as prefixes', () => {
+    expect(matchesPathFilter('packages/core/src/scanner/go.ts', 'packages/core/src/scanner')).toBe(
+      true
+    );
+    expect(matchesPathFilter('packages/core/src/index.ts', './packages/core')).toBe(true);
+    expect(matchesPathFilter('packages/cli/src/index.ts', 'packages/core')).toBe(false);
+  });
+
+  it('should match globs against the whole path', () => {
+    expect(matchesPathFilter('internal/store/store_test.go', '**/*_test.go')).toBe(true);
+    expect(matchesPathFilter('store_test.go', '**/*_test.go')).toBe(true);
+    expect(matchesPathFilter('internal/store/store.go', '**/*_test.go')).toBe(false);
+    expect(matchesPathFilter('src/a/b.ts', 'src/*.ts')).toBe(false);
+    expect(matchesPathFilter('src/b.tsx', 'src/*.{ts,tsx}')).toBe(true);
+    expect(matchesPathFilter('src/v2/api.go', 'src/v[0-9]/*.go')).toBe(true);
+  });
+
+  it('should match globs without a slash at any depth', () => {
+    expect(matchesPathFilter('a/b/c_test.go', '*_test.go')).toBe(true);
+    expect(matchesPathFilter('a/b/c.go', '*_test.go')).toBe(false);
+  });
+
+  it('should anchor globs before the symbol part of document IDs', () => {
+    const pattern = new RegExp(pathFilterPattern('**/*.go'));
+    expect(pattern.test('pkg/server.go:Serve:10')).toBe(true);
+    expect(pattern.test('pkg/server.gox:Serve:10')).toBe(false);
+  });
+});
+
+describe('normalizeForMetric', () => {
+  it('should unit-normalize cosine vectors', () => {
+    expect(normalizeForMetric([3, 4], 'cosine')).toEqual([0.6, 0.8]);
+  });
+
+  it('should keep magnitude for dot and euclidean', () => {
+    expect(normalizeForMetric([3, 4], 'dot')).toEqual([3, 4]);
+    expect(normalizeForMetric([3, 4], 'euclidean')).toEqual([3, 4]);
+  });
+
+  it('should leave zero vectors unchanged', () => {
+    expect(normalizeForMetric([0, 0], 'cosine')).toEqual([0, 0]);
+  });
+});
+
+describe('distanceToScore', () => {
+  it('should match the L2 scores of unit vectors for cosine', () => {
+    // Cosine distance 0.5 between unit vectors is a squared L2 distance of 1
+    expect(distanceToScore(0.5, 'cosine')).toBeCloseTo(distanceToScore(1, 'euclidean'));
+  });
+
+  it('should score dot products in the 0-1 range', () => {
+    expect(distanceToScore(0.25, 'dot')).toBeCloseTo(0.75);
+    expect(distanceToScore(-2, 'dot')).toBe(1);
+    expect(distanceToScore(3, 'dot')).toBe(0);
+  });
+});
+
+describe('LanceDBVectorStore similarity metric', () => {
+  let testDir: string | undefined;
+  const doc = { id: 'a', text: 'alpha', metadata: { path: 'a.go' } };
+
+  afterEach(async () => {
+    if (testDir) await fs.rm(testDir, { recursive: true, force: true });
+    testDir = undefined;
+  });
+
+  async function storeWith(metric?: 'cosine' | 'dot' | 'euclidean') {
+    testDir ??= await fs.mkdtemp(path.join(os.tmpdir(), 'store-metric-'));
+    const store = new LanceDBVectorStore(path.join(testDir, 'vectors.lance'), 2, metric);
+    await store.initialize();
+    return store;
+  }
+
+  it('should keep the metric an index was written with', async () => {
+    const writer = await storeWith('dot');
+    await writer.add([doc], [[3, 4]]);
+
+    const reader = await storeWith();
+    expect(reader.metric).toBe('dot');
+    const [result] = await reader.search([0.1, 0.2]);
+    expect(result.score).toBe(1); // 0.3 + 0.8 >= 1
+  });
+
+  it('should reject a different metric at query time', async () => {
+    const writer = await storeWith('dot');
+    await writer.add([doc], [[3, 4]]);
+
+    const reader = await storeWith('cosine');
+    await expect(reader.search([1, 0])).rejects.toThrow(/uses the 'dot' similarity metric/);
+    await expect(reader.add([doc], [[1, 0]])).rejects.toThrow(/re-index with --force/);
+  });
+
+  it('should switch metrics after the index is cleared', async () => {
+    const writer = await storeWith('dot');
+    await writer.add([doc], [[3, 4]]);
+
+    const store = await storeWith('euclidean');
+    await store.clear();
+    await store.add([doc], [[3, 4]]);
+
+    expect((await storeWith()).metric).toBe('euclidean');
+  });
+});
+
+describe('LanceDBVectorStore path filter', () => {
+  let testDir: string | undefined;
+
+  afterEach(async () => {
+    if (testDir) await fs.rm(testDir, { recursive: true, force: true });
+    testDir = undefined;
+  });
+
+  it('should only rank documents under the filtered paths', async () => {
+    testDir = await fs.mkdtemp(path.join(os.tmpdir(), 'store-path-filter-'));
+    const store = new LanceDBVectorStore(path.join(testDir, 'vectors.lance'), 2);
+    await store.initialize();
+    const docs = ['pkg/server.go', 'pkg/server_test.go', 'cmd/main.go'].map((file) => ({
+      id: `${file}:Serve:1`,
+      text: file,
+      metadata: { path: file },
+    }));
+    await store.add(docs, [
+      [1, 0],
+      [0.9, 0.1],
+      [1, 0.05],
+    ]);
+
+    const prefixed = await store.search([1, 0], { pathFilter: 'pkg/' });
+    expect(prefixed.map((r) => r.metadata.path).sort()).toEqual([
+      'pkg/server.go',
+      'pkg/server_test.go',
+    ]);
+
+    const tests = await store.search([1, 0], { pathFilter: '**/*_test.go' });
+    expect(tests.map((r) => r.metadata.path)).toEqual(['pkg/server_test.go']);
+  });
+});
+
+/** Seeded pseudo-random numbers in [-0.5, 0.5) (mulberry32) */
+function seededRandom(seed: number): () => number {
+  let state = seed;
+  return () => {
+    state = (state + 0x6d2b79f5) | 0;
+    let t = Math.imul(state ^ (state >>> 15), 1 | state);
+    t = (t + Math.imul(t ^ (t >>> 7), 61 | t)) ^ t;
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296 - 0.5;
+  };
+}
+
+/** Deterministic pseudo-random vectors, each dimension scaled by `scale` */
+function randomVectors(
+  count: number,
+  dimension: number,
+  seed: number,
+  scale: (dim: number) => number = () => 1
+): number[][] {
+  const next = seededRandom(seed);
+  return Array.from({ length: count }, () =>
+    Array.from({ length: dimension }, (_, i) => next() * scale(i))
+  );
+}
+
+/**
+ * Open stores in a temporary directory created on first use; `cleanup` removes it
+ */
+function tempStores(prefix: string, dimension: number) {
+  let testDir: string | undefined;
+  return {
+    async open(name: string, quantization?: QuantizationConfig, truncateDimensions?: number) {
+      testDir ??= await fs.mkdtemp(path.join(os.tmpdir(), prefix));
+      const store = new LanceDBVectorStore(
+        path.join(testDir, `${name}.lance`),
+        dimension,
+        undefined,
+        quantization,
+        truncateDimensions
+      );
+      await store.initialize();
+      return store;
+    },
+    async cleanup() {
+      if (testDir) await fs.rm(testDir, { recursive: true, force: true });
+      testDir = undefined;
+    },
+  };
+}
+
+/** Mean share of the baseline's top 10 that a store also returns, over the queries */
+async function topKOverlap(
+  store: LanceDBVectorStore,
+  baseline: LanceDBVectorStore,
+  queries: number[][]
+): Promise<number> {
+  let overlap = 0;
+  for (const query of queries) {
+    const expected = new Set((await baseline.search(query)).map((r) => r.id));
+    const actual = await store.search(query);
+    overlap += actual.filter((r) => expected.has(r.id)).length / expected.size;
+  }
+  return overlap / queries.length;
+}
+
+describe('LanceDBVectorStore int8 quantization', () => {
+  const DIMENSION = 64;
+  const stores = tempStores('store-quantization-', DIMENSION);
+  const storeWith = (name: string, quantization?: QuantizationConfig) =>
+    stores.open(name, quantization);
+
+  afterEach(() => stores.cleanup());
+
+  const vectors = randomVectors(300, DIMENSION, 7);
+  const queries = randomVectors(20, DIMENSION, 99);
+  const docs = vectors.map((_, i) => ({ id: `pkg/f${i}.go:F${i}:1`, text: `F${i}`, metadata: {} }));
+
+  it('should keep top-k overlap with the unquantized index high', async () => {
+    const baseline = await storeWith('float');
+    await baseline.add(docs, vectors);
+    const int8 = await storeWith('int8', { scheme: 'int8' });
+    await int8.add(docs, vectors);
+    const reranked = await storeWith('rerank', { scheme: 'int8', keepFullPrecision: true });
+    await reranked.add(docs, vectors);
+
+    expect(await topKOverlap(int8, baseline, queries)).toBeGreaterThanOrEqual(0.9);
+    expect(await topKOverlap(reranked, baseline, queries)).toBeGreaterThanOrEqual(0.99);
+  });
+
+  it('should score like the unquantized index', async () => {
+    const baseline = await storeWith('float');
+    await baseline.add(docs, vectors);
+    const int8 = await storeWith('int8', { scheme: 'int8' });
+    await int8.add(docs, vectors);
+
+    const [expected] = await baseline.search(vectors[3], { limit: 1 });
+    const [actual] = await int8.search(vectors[3], { limit: 1 });
+    expect(actual.id).toBe(expected.id);
+    expect(actual.score).toBeCloseTo(expected.score, 2);
+  });
+
+  it('should record the scheme and reject a different one on load', async () => {
+    const writer = await storeWith('int8', { scheme: 'int8' });
+    await writer.add(docs.slice(0, 2), vectors.slice(0, 2));
+
+    const reader = await storeWith('int8');
+    expect(reader.quantization).toEqual({ scheme: 'int8', keepFullPrecision: false });
+    expect(await reader.search(vectors[0], { limit: 1 })).toHaveLength(1);
+
+    const mismatched = await storeWith('int8', { scheme: 'none' });
+    await expect(mismatched.search(vectors[0])).rejects.toThrow(/uses 'int8' vector quantization/);
+  });
+
+  it('should read, filter, and delete quantized documents', async () => {
+    const store = await storeWith('int8', { scheme: 'int8' });
+    await store.add(docs.slice(0, 3), vectors.slice(0, 3));
+
+    expect((await store.get(docs[1].id))?.text).toBe('F1');
+    const [record] = (await store.getRecords()).filter((r) => r.id === docs[0].id);
+    expect(record.vector).toHaveLength(DIMENSION);
+
+    const filtered = await store.search(vectors[0], { pathFilter: 'pkg/f1.go' });
+    expect(filtered.map((r) => r.id)).toEqual([docs[1].id]);
+
+    await store.delete([docs[0].id]);
+    const remaining = await store.search(vectors[0]);
+    expect(remaining.map((r) => r.id)).not.toContain(docs[0].id);
+  });
+});
+
+describe('LanceDBVectorStore dimension truncation', () => {
+  const DIMENSION = 64;
+  const stores = tempStores('store-truncation-', DIMENSION);
+  const storeWith = (name: string, truncateDimensions?: number) =>
+    stores.open(name, undefined, truncateDimensions);
+
+  afterEach(() => stores.cleanup());
+
+  // Variance decays along the dimensions, like a Matryoshka-trained model's
+  const decay = (dim: number) => 0.93 ** dim;
+  const vectors = randomVectors(300, DIMENSION, 7, decay);
+  const queries = randomVectors(20, DIMENSION, 99, decay);
+  const docs = vectors.map((_, i) => ({ id: `pkg/f${i}.go:F${i}:1`, text: `F${i}`, metadata: {} }));
+
+  it('should trade recall for dimensions', async () => {
+    const baseline = await storeWith('full');
+    await baseline.add(docs, vectors);
+    const half = await storeWith('half', DIMENSION / 2);
+    await half.add(docs, vectors);
+    const quarter = await storeWith('quarter', DIMENSION / 4);
+    await quarter.add(docs, vectors);
+
+    const halfRecall = await topKOverlap(half, baseline, queries);
+    const quarterRecall = await topKOverlap(quarter, baseline, queries);
+    expect(halfRecall).toBeGreaterThanOrEqual(0.95);
+    expect(quarterRecall).toBeGreaterThanOrEqual(0.8);
+    expect(quarterRecall).toBeLessThan(halfRecall);
+  });
+
+  it('should store truncated vectors and cut queries to match on reopen', async () => {
+    const writer = await storeWith('truncated', 16);
+    await writer.add(docs.slice(0, 3), vectors.slice(0, 3));
+    expect(await writer.getVector(docs[0].id)).toHaveLength(16);
+
+    // Opened without a truncation, as the search tools do
+    const reader = await storeWith('truncated');
+    expect(reader.dimensions).toBe(16);
+    const [top] = await reader.search(vectors[1], { limit: 1 });
+    expect(top.id).toBe(docs[1].id);
+    expect(top.score).toBeCloseTo(1, 5);
+  });
+
+  it('should accept embeddings only under the settings the table was written with', async () => {
+    const writer = await storeWith('truncated', 16);
+    await writer.add(docs.slice(0, 2), vectors.slice(0, 2));
+    expect(await writer.getIds()).toEqual(expect.arrayContaining([docs[0].id, docs[1].id]));
+    expect(await writer.acceptsEmbeddings(DIMENSION)).toBe(true);
+    // A model whose vectors are shorter than the stored ones
+    expect(await writer.acceptsEmbeddings(8)).toBe(false);
+
+    const mismatched = await storeWith('truncated', 32);
+    expect(await mismatched.acceptsEmbeddings(DIMENSION)).toBe(false);
+  });
+
+  it('should reject a different truncation on load', async () => {
+    const writer = await storeWith('truncated', 16);
+    await writer.add(docs.slice(0, 2), vectors.slice(0, 2));
+
+    const mismatched = await storeWith('truncated', 32);
+    await expect(mismatched.search(vectors[0])).rejects.toThrow(/stores 16 dimensions/);
+
+    const full = await storeWith('full');
+    await full.add(docs.slice(0, 2), vectors.slice(0, 2));
+    const truncating = await storeWith('full', 16);
+    await expect(truncating.search(vectors[0])).rejects.toThrow(/stores 64 dimensions/);
+  });
+});
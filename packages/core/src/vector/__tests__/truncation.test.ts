@@ -0,0 +1,24 @@
+import { describe, expect, it } from 'vitest';
+import { truncateVector, truncationFor } from '../truncation';
+
+describe('truncationFor', () => {
+  it('should keep every dimension unless fewer are asked for', () => {
+    expect(truncationFor(undefined, 384)).toBeUndefined();
+    expect(truncationFor(384, 384)).toBeUndefined();
+    expect(truncationFor(512, 384)).toBeUndefined();
+    expect(truncationFor(128, 384)).toBe(128);
+  });
+
+  it('should reject anything but a positive integer', () => {
+    expect(() => truncationFor(0, 384)).toThrow(/positive integer/);
+    expect(() => truncationFor(12.5, 384)).toThrow(/positive integer/);
+  });
+});
+
+describe('truncateVector', () => {
+  it('should keep the leading dimensions', () => {
+    expect(truncateVector([1, 2, 3, 4], 2)).toEqual([1, 2]);
+    expect(truncateVector([1, 2], 4)).toEqual([1, 2]);
+    expect(truncateVector([1, 2], undefined)).toEqual([1, 2]);
+  });
+});
@@ -0,0 +1,180 @@
+/**
+ * Index Dump
+ * Portable NDJSON export of a vector store, for moving an index between
+ * machines and for inspecting what was indexed
+ *
+ * Format (one JSON value per line):
+ *
+ * - Line 1 is a {@link DumpHeader}: `{"format":"dev-agent-index","version":1,...}`
+ * - Every following line is a {@link DumpRecord}:
+ *   `{"id":"src/a.go:Retry:10","text":"...","metadata":{...},"vector":"AAB4Pw..."}`
+ *
+ * Vectors are base64-encoded little-endian float32 arrays, the precision they
+ * are stored at, so an import ranks results exactly like the exported index.
+ * Vectors are dumped as stored, i.e. already normalized for the metric.
+ * Records are one per line so the dump can be grepped by path or name.
+ */
+
+import { once } from 'node:events';
+import { createReadStream, createWriteStream } from 'node:fs';
+import * as readline from 'node:readline';
+import type { SimilarityMetric, StoredRecord } from './types';
+
+/** Format marker in the header line */
+export const DUMP_FORMAT = 'dev-agent-index';
+
+/** Current dump format version */
+export const DUMP_VERSION = 1;
+
+/**
+ * First line of a dump
+ */
+export interface DumpHeader {
+  format: typeof DUMP_FORMAT;
+  version: number;
+  /** Model the vectors were embedded with; imports require the same model */
+  embeddingModel: string;
+  dimension: number;
+  metric: SimilarityMetric;
+  /** Number of records that follow */
+  documents: number;
+  exportedAt: string;
+  /** Producer-specific state (e.g. the indexer's file hashes) */
+  [key: string]: unknown;
+}
+
+/**
+ * One stored document per line after the header
+ */
+export interface DumpRecord {
+  id: string;
+  text: string;
+  metadata: Record<string, unknown>;
+  /** Base64 of the vector as little-endian float32 */
+  vector: string;
+}
+
+/**
+ * Encode a vector as base64 little-endian float32
+ */
+export function encodeVector(vector: ArrayLike<number>): string {
+  const view = new DataView(new ArrayBuffer(vector.length * 4));
+  for (let i = 0; i < vector.length; i++) {
+    view.setFloat32(i * 4, vector[i], true);
+  }
+  return Buffer.from(view.buffer).toString('base64');
+}
+
+/**
+ * Decode a base64 little-endian float32 vector
+ */
+export function decodeVector(encoded: string): number[] {
+  const bytes = Buffer.from(encoded, 'base64');
+  if (bytes.length % 4 !== 0) {
+    throw new Error('Vector is not a whole number of float32 values');
+  }
+  const view = new DataView(bytes.buffer, bytes.byteOffset, bytes.length);
+  return Array.from({ length: bytes.length / 4 }, (_, i) => view.getFloat32(i * 4, true));
+}
+
+/**
+ * Write a header and records as NDJSON
+ */
+export async function writeDump(
+  filePath: string,
+  header: DumpHeader,
+  records: StoredRecord[]
+): Promise<void> {
+  const stream = createWriteStream(filePath, { encoding: 'utf-8' });
+  const write = async (value: unknown) => {
+    if (!stream.write(`${JSON.stringify(value)}\n`)) {
+      await once(stream, 'drain');
+    }
+  };
+
+  try {
+    await write(header);
+    for (const record of records) {
+      const line: DumpRecord = {
+        id: record.id,
+        text: record.text,
+        metadata: record.metadata,
+        vector: encodeVector(record.vector),
+      };
+      await write(line);
+    }
+  } finally {
+    stream.end();
+    await once(stream, 'finish');
+  }
+}
+
+/**
+ * Read and validate an NDJSON dump
+ *
+ * @throws When the header is missing or from an unknown format version, a
+ * line is not valid JSON, or the record count doesn't match the header
+ */
+export async function readDump(
+  filePath: string
+): Promise<{ header: DumpHeader; records: StoredRecord[] }> {
+  const lines = readline.createInterface({
+    input: createReadStream(filePath, { encoding: 'utf-8' }),
+    crlfDelay: Number.POSITIVE_INFINITY,
+  });
+
+  let header: DumpHeader | undefined;
+  const records: StoredRecord[] = [];
+  let lineNumber = 0;
+  for await (const line of lines) {
+    lineNumber++;
+    if (line.trim() === '') continue;
+
+    let value: unknown;
+    try {
+      value = JSON.parse(line);
+    } catch {
+      throw new Error(`Invalid JSON on line ${lineNumber} of ${filePath}`);
+    }
+
+    if (!header) {
+      header = parseHeader(value, filePath);
+      continue;
+    }
+
+    const record = value as Partial<DumpRecord>;
+    if (typeof record.id !== 'string' || typeof record.vector !== 'string') {
+      throw new Error(`Line ${lineNumber} of ${filePath} is not a dump record`);
+    }
+    records.push({
+      id: record.id,
+      text: record.text ?? '',
+      metadata: record.metadata ?? {},
+      vector: decodeVector(record.vector),
+    });
+  }
+
+  if (!header) {
+    throw new Error(`${filePath} is empty`);
+  }
+  if (records.length !== header.documents) {
+    throw new Error(
+      `${filePath} has ${records.length} records but its header lists ${header.documents}; ` +
+        'the dump may be truncated'
+    );
+  }
+  return { header, records };
+}
+
+function parseHeader(value: unknown, filePath: string): DumpHeader {
+  const header = value as Partial<DumpHeader>;
+  if (header?.format !== DUMP_FORMAT) {
+    throw new Error(`${filePath} is not a dev-agent index dump`);
+  }
+  if (header.version !== DUMP_VERSION) {
+    throw new Error(
+      `${filePath} is dump version ${header.version}; this version reads ${DUMP_VERSION}`
+    );
+  }
+  return header as DumpHeader;
+}
@@ -11,6 +11,24 @@ interface FeatureExtractionOptions {
   precision?: 'binary' | 'ubinary';
 }
 
+/** How long loading the embedding model may take before it counts as unavailable */
+export const DEFAULT_EMBEDDER_LOAD_TIMEOUT_MS = 120_000;
+
+/**
+ * The embedding model couldn't be loaded (download failed, timed out, or the
+ * model is broken), so semantic search and indexing can't run. Lookups by name
+ * don't need embeddings and keep working.
+ */
+export class EmbeddingsUnavailableError extends Error {
+  constructor(
+    public readonly modelName: string,
+    public readonly reason: string
+  ) {
+    super(`Embeddings unavailable: could not load embedding model ${modelName} (${reason})`);
+    this.name = 'EmbeddingsUnavailableError';
+  }
+}
+
 /**
  * Embedding provider using Transformers.js
  * Uses all-MiniLM-L6-v2 model for generating embeddings
@@ -19,9 +37,14 @@ export class TransformersEmbedder implements EmbeddingProvider {
   readonly modelName: string;
   readonly dimension: number;
   private pipeline: FeatureExtractionPipeline | null = null;
+  private loading: Promise<void> | null = null;
   private batchSize = 32;
 
-  constructor(modelName = 'Xenova/all-MiniLM-L6-v2', dimension = 384) {
+  constructor(
+    modelName = 'Xenova/all-MiniLM-L6-v2',
+    dimension = 384,
+    private readonly loadTimeoutMs = DEFAULT_EMBEDDER_LOAD_TIMEOUT_MS
+  ) {
     this.modelName = modelName;
     this.dimension = dimension;
   }
@@ -29,22 +52,43 @@ export class TransformersEmbedder implements EmbeddingProvider {
   /**
    * Initialize the embedding model
    * Downloads and caches the model on first run
+   *
+   * Concurrent calls share one load. A load that fails or takes longer than
+   * the timeout throws EmbeddingsUnavailableError; the next call tries again.
    */
   async initialize(): Promise<void> {
     if (this.pipeline) {
       return; // Already initialized
     }
 
+    this.loading ??= this.load().finally(() => {
+      this.loading = null;
+    });
+    return this.loading;
+  }
+
+  private async load(): Promise<void> {
+    let timer: ReturnType<typeof setTimeout> | undefined;
+    const timeout = new Promise<never>((_, reject) => {
+      timer = setTimeout(
+        () => reject(new Error(`timed out after ${Math.round(this.loadTimeoutMs / 1000)}s`)),
+        this.loadTimeoutMs
+      );
+    });
+
     try {
       // Create pipeline with the feature-extraction task
-      this.pipeline = (await pipeline(
-        'feature-extraction',
-        this.modelName
-      )) as FeatureExtractionPipeline;
+      this.pipeline = (await Promise.race([
+        pipeline('feature-extraction', this.modelName),
+        timeout,
+      ])) as FeatureExtractionPipeline;
     } catch (error) {
-      throw new Error(
-        `Failed to initialize embedding model ${this.modelName}: ${error instanceof Error ? error.message : String(error)}`
+      throw new EmbeddingsUnavailableError(
+        this.modelName,
+        error instanceof Error ? error.message : String(error)
       );
+    } finally {
+      clearTimeout(timer);
     }
   }
 
@@ -84,8 +128,9 @@ export class TransformersEmbedder implements EmbeddingProvider {
 
   /**
    * Generate embeddings for multiple texts (batched for efficiency)
+   * @param signal - Optional signal checked between batches
    */
-  async embedBatch(texts: string[]): Promise<number[][]> {
+  async embedBatch(texts: string[], signal?: AbortSignal): Promise<number[][]> {
     if (!this.pipeline) {
       throw new Error('Embedder not initialized. Call initialize() first.');
     }
@@ -99,6 +144,7 @@ export class TransformersEmbedder implements EmbeddingProvider {
       const embeddings: number[][] = [];
 
       for (let i = 0; i < texts.length; i += this.batchSize) {
+        signal?.throwIfAborted();
         const batch = texts.slice(i, i + this.batchSize);
 
         // Process batch in parallel
@@ -109,12 +155,25 @@ export class TransformersEmbedder implements EmbeddingProvider {
 
       return embeddings;
     } catch (error) {
+      // Cancellation passes through unwrapped so callers can recognize it
+      signal?.throwIfAborted();
       throw new Error(
         `Failed to generate batch embeddings: ${error instanceof Error ? error.message : String(error)}`
       );
     }
   }
 
+  /**
+   * Count tokens with the model's tokenizer (special tokens excluded)
+   */
+  countTokens(text: string): number {
+    if (!this.pipeline) {
+      throw new Error('Embedder not initialized. Call initialize() first.');
+    }
+
+    return this.pipeline.tokenizer.encode(text, null, { add_special_tokens: false }).length;
+  }
+
   /**
    * Set batch size for batch processing
    */
@@ -0,0 +1,159 @@
+/**
+ * Embedding Cache
+ * Reuses vectors for text that was embedded before, across runs and full rebuilds
+ *
+ * Entries are keyed by a hash of the embedding text, in one file per
+ * provider, model, and dimension, so changing the model starts a fresh cache
+ * instead of mixing vectors from different embedding spaces. The cache lives
+ * outside the vector store, so clearing the store for a rebuild keeps it.
+ */
+
+import * as crypto from 'node:crypto';
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+import type { EmbeddingCacheStats } from './types';
+
+/** Bytes of the text hash stored per entry */
+const KEY_BYTES = 16;
+
+/** Default entries kept; least recently used entries are dropped past this */
+export const DEFAULT_EMBEDDING_CACHE_ENTRIES = 200_000;
+
+/**
+ * Embedding cache configuration
+ */
+export interface EmbeddingCacheConfig {
+  /** Directory holding cache files */
+  cacheDir: string;
+  /** Embedding provider (e.g. "transformers") */
+  provider: string;
+  /** Embedding model name */
+  modelName: string;
+  /** Vector dimension */
+  dimension: number;
+  /** Maximum entries kept (default: 200,000) */
+  maxEntries?: number;
+}
+
+/**
+ * Content-hash-keyed cache of embedding vectors
+ */
+export class EmbeddingCache {
+  private readonly filePath: string;
+  private readonly dimension: number;
+  private readonly maxEntries: number;
+  private entries = new Map<string, Float32Array>();
+  private loading?: Promise<void>;
+  private dirty = false;
+  private hits = 0;
+  private misses = 0;
+
+  constructor(config: EmbeddingCacheConfig) {
+    const space = `${config.provider}\u0000${config.modelName}\u0000${config.dimension}`;
+    const fingerprint = crypto.createHash('sha1').update(space).digest('hex').slice(0, 16);
+    this.filePath = path.join(config.cacheDir, `${fingerprint}.bin`);
+    this.dimension = config.dimension;
+    this.maxEntries = config.maxEntries ?? DEFAULT_EMBEDDING_CACHE_ENTRIES;
+  }
+
+  /**
+   * Load cached vectors from disk; a missing or corrupt file starts an empty cache
+   */
+  load(): Promise<void> {
+    // Batches embed concurrently; all of them wait on the same read
+    this.loading ??= this.read();
+    return this.loading;
+  }
+
+  private async read(): Promise<void> {
+    let data: Buffer;
+    try {
+      data = await fs.readFile(this.filePath);
+    } catch {
+      return;
+    }
+
+    const recordBytes = KEY_BYTES + this.dimension * 4;
+    if (data.length % recordBytes !== 0) {
+      return;
+    }
+    for (let offset = 0; offset < data.length; offset += recordBytes) {
+      const key = data.toString('hex', offset, offset + KEY_BYTES);
+      const vector = new Float32Array(this.dimension);
+      for (let i = 0; i < this.dimension; i++) {
+        vector[i] = data.readFloatLE(offset + KEY_BYTES + i * 4);
+      }
+      this.entries.set(key, vector);
+    }
+  }
+
+  /**
+   * Cached vector for a text, or undefined; counts toward hit/miss stats
+   */
+  get(text: string): number[] | undefined {
+    const key = hashText(text);
+    const vector = this.entries.get(key);
+    if (!vector) {
+      this.misses++;
+      return undefined;
+    }
+
+    // Re-insert so the Map's order tracks recency
+    this.entries.delete(key);
+    this.entries.set(key, vector);
+    this.hits++;
+    return Array.from(vector);
+  }
+
+  set(text: string, vector: number[]): void {
+    if (vector.length !== this.dimension) return;
+
+    const key = hashText(text);
+    this.entries.delete(key);
+    this.entries.set(key, Float32Array.from(vector));
+    this.dirty = true;
+
+    for (const oldest of this.entries.keys()) {
+      if (this.entries.size <= this.maxEntries) break;
+      this.entries.delete(oldest);
+    }
+  }
+
+  /**
+   * Write the cache to disk if it changed
+   */
+  async flush(): Promise<void> {
+    if (!this.dirty) return;
+
+    const recordBytes = KEY_BYTES + this.dimension * 4;
+    const data = Buffer.alloc(this.entries.size * recordBytes);
+    let offset = 0;
+    for (const [key, vector] of this.entries) {
+      data.write(key, offset, KEY_BYTES, 'hex');
+      for (let i = 0; i < this.dimension; i++) {
+        data.writeFloatLE(vector[i], offset + KEY_BYTES + i * 4);
+      }
+      offset += recordBytes;
+    }
+
+    // Write then rename, so an interrupted flush never leaves a torn file
+    await fs.mkdir(path.dirname(this.filePath), { recursive: true });
+    const tmpPath = `${this.filePath}.${process.pid}.tmp`;
+    await fs.writeFile(tmpPath, data);
+    await fs.rename(tmpPath, this.filePath);
+    this.dirty = false;
+  }
+
+  getStats(): EmbeddingCacheStats {
+    return { hits: this.hits, misses: this.misses, entries: this.entries.size };
+  }
+
+  resetStats(): void {
+    this.hits = 0;
+    this.misses = 0;
+  }
+}
+
+function hashText(text: string): string {
+  return crypto.createHash('sha256').update(text).digest('hex').slice(0, KEY_BYTES * 2);
+}
@@ -2,17 +2,27 @@
  * Vector storage and embedding system
  */
 
+export * from './dump';
 export * from './embedder';
+export * from './embedding-cache';
+export * from './quantization';
+export * from './reranker';
 export * from './store';
+export * from './truncation';
 export * from './types';
 
 import * as fs from 'node:fs/promises';
+import { DUMP_FORMAT, DUMP_VERSION, type DumpHeader, readDump, writeDump } from './dump';
 import { TransformersEmbedder } from './embedder';
+import { EmbeddingCache } from './embedding-cache';
 import { LanceDBVectorStore } from './store';
+import { truncateVector, truncationFor } from './truncation';
 import type {
+  EmbeddingCacheStats,
   EmbeddingDocument,
   SearchOptions,
   SearchResult,
+  StorageTiming,
   VectorStats,
   VectorStorageConfig,
 } from './types';
@@ -24,13 +34,28 @@ import type {
 export class VectorStorage {
   private readonly embedder: TransformersEmbedder;
   private readonly store: LanceDBVectorStore;
+  private readonly embeddingCache?: EmbeddingCache;
   private initialized = false;
 
   constructor(config: VectorStorageConfig) {
     const { storePath, embeddingModel = 'Xenova/all-MiniLM-L6-v2', dimension = 384 } = config;
 
     this.embedder = new TransformersEmbedder(embeddingModel, dimension);
-    this.store = new LanceDBVectorStore(storePath, dimension);
+    this.store = new LanceDBVectorStore(
+      storePath,
+      dimension,
+      config.metric,
+      config.quantization,
+      truncationFor(config.truncateDimensions, dimension)
+    );
+    if (config.embeddingCacheDir) {
+      this.embeddingCache = new EmbeddingCache({
+        cacheDir: config.embeddingCacheDir,
+        provider: 'transformers',
+        modelName: embeddingModel,
+        dimension,
+      });
+    }
   }
 
   /**
@@ -58,8 +83,10 @@ export class VectorStorage {
 
   /**
    * Ensure embedder is initialized (lazy initialization for search operations)
+   *
+   * @throws EmbeddingsUnavailableError when the model can't be loaded
    */
-  private async ensureEmbedder(): Promise<void> {
+  async ensureEmbedder(): Promise<void> {
     if (!this.embedder) {
       throw new Error('Embedder not available');
     }
@@ -69,8 +96,14 @@ export class VectorStorage {
 
   /**
    * Add documents to the store (automatically generates embeddings)
+   * @param timing - Optional accumulator for time spent embedding and storing
+   * @param signal - Optional signal to cancel before anything is written
    */
-  async addDocuments(documents: EmbeddingDocument[]): Promise<void> {
+  async addDocuments(
+    documents: EmbeddingDocument[],
+    timing?: StorageTiming,
+    signal?: AbortSignal
+  ): Promise<void> {
     if (!this.initialized) {
       throw new Error('VectorStorage not initialized. Call initialize() first.');
     }
@@ -79,12 +112,79 @@ export class VectorStorage {
       return;
     }
 
-    // Generate embeddings
+    // Generate embeddings, reusing cached vectors for unchanged text
     const texts = documents.map((doc) => doc.text);
-    const embeddings = await this.embedder.embedBatch(texts);
+    const embedStart = Date.now();
+    const embeddings = await this.embedWithCache(texts, signal);
+    const storeStart = Date.now();
+
+    // Once embedded, a batch is written in full or not at all
+    signal?.throwIfAborted();
 
     // Store documents with embeddings
     await this.store.add(documents, embeddings);
+
+    if (timing) {
+      timing.embed += storeStart - embedStart;
+      timing.store += Date.now() - storeStart;
+    }
+  }
+
+  private async embedWithCache(texts: string[], signal?: AbortSignal): Promise<number[][]> {
+    const cache = this.embeddingCache;
+    if (!cache) {
+      await this.ensureEmbedder();
+      return this.embedder.embedBatch(texts, signal);
+    }
+
+    await cache.load();
+    const embeddings = texts.map((text) => cache.get(text));
+    const missing = texts.filter((_, i) => !embeddings[i]);
+    if (missing.length === 0) {
+      return embeddings as number[][];
+    }
+
+    // Only texts missing from the cache need the model
+    await this.ensureEmbedder();
+    const embedded = await this.embedder.embedBatch(missing, signal);
+    let next = 0;
+    return embeddings.map((cached, i) => {
+      if (cached) return cached;
+      const vector = embedded[next++];
+      cache.set(texts[i], vector);
+      return vector;
+    });
+  }
+
+  /**
+   * Embedding cache hits and misses since the last reset, or null without a cache
+   */
+  getEmbeddingCacheStats(): EmbeddingCacheStats | null {
+    return this.embeddingCache?.getStats() ?? null;
+  }
+
+  resetEmbeddingCacheStats(): void {
+    this.embeddingCache?.resetStats();
+  }
+
+  /**
+   * Persist newly cached embeddings
+   */
+  async flushEmbeddingCache(): Promise<void> {
+    await this.embeddingCache?.flush();
+  }
+
+  /**
+   * Count tokens with the embedding model's tokenizer
+   *
+   * Falls back to a character-based estimate when the embedder isn't loaded.
+   */
+  countTokens(text: string): number {
+    try {
+      return this.embedder.countTokens(text);
+    } catch {
+      return Math.ceil(text.length / 4);
+    }
   }
 
   /**
@@ -117,6 +217,41 @@ export class VectorStorage {
     return this.store.searchByDocumentId(documentId, options);
   }
 
+  /**
+   * Embed a query the way search() does, for callers that combine query vectors.
+   * Cut to the index's dimensions, so it combines with stored vectors.
+   */
+  async embedQuery(query: string): Promise<number[]> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    await this.ensureEmbedder();
+    return truncateVector(await this.embedder.embed(query), this.store.dimensions);
+  }
+
+  /**
+   * Search with a precomputed query vector
+   */
+  async searchByVector(vector: number[], options?: SearchOptions): Promise<SearchResult[]> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    return this.store.search(vector, options);
+  }
+
+  /**
+   * Stored embedding of a document, or null if it isn't indexed
+   */
+  async getDocumentVector(documentId: string): Promise<number[] | null> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    return this.store.getVector(documentId);
+  }
+
   /**
    * Get all documents without semantic search (fast scan)
    * Use this when you need all documents and don't need relevance ranking
@@ -152,6 +287,30 @@ export class VectorStorage {
     await this.store.delete(ids);
   }
 
+  /**
+   * IDs of every stored document
+   */
+  async getDocumentIds(): Promise<string[]> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    return this.store.getIds();
+  }
+
+  /**
+   * Whether new embeddings can replace documents in place, or the store has
+   * to be cleared first because its metric, quantization, truncation, or
+   * embedding model differ
+   */
+  async acceptsEmbeddings(): Promise<boolean> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    return this.store.acceptsEmbeddings(this.embedder.dimension);
+  }
+
   /**
    * Clear all documents from the store (destructive operation)
    * Used for force re-indexing
@@ -193,6 +352,61 @@ export class VectorStorage {
     };
   }
 
+  /**
+   * Export every document and vector to an NDJSON dump (see ./dump.ts for the format)
+   *
+   * @param filePath - Dump file to write
+   * @param extra - Additional header fields, returned again by importDump()
+   * @returns Number of documents written
+   */
+  async exportDump(filePath: string, extra: Record<string, unknown> = {}): Promise<number> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    const records = await this.store.getRecords();
+    const header: DumpHeader = {
+      ...extra,
+      format: DUMP_FORMAT,
+      version: DUMP_VERSION,
+      embeddingModel: this.embedder.modelName,
+      dimension: this.embedder.dimension,
+      metric: this.store.metric,
+      documents: records.length,
+      exportedAt: new Date().toISOString(),
+    };
+    await writeDump(filePath, header, records);
+    return records.length;
+  }
+
+  /**
+   * Replace the store's contents with an NDJSON dump
+   *
+   * The dump must come from the same embedding model and dimension, or its
+   * vectors wouldn't be comparable with query embeddings.
+   *
+   * @returns The dump's header
+   */
+  async importDump(filePath: string): Promise<DumpHeader> {
+    if (!this.initialized) {
+      throw new Error('VectorStorage not initialized. Call initialize() first.');
+    }
+
+    const { header, records } = await readDump(filePath);
+    if (
+      header.embeddingModel !== this.embedder.modelName ||
+      header.dimension !== this.embedder.dimension
+    ) {
+      throw new Error(
+        `Dump was embedded with ${header.embeddingModel} (${header.dimension} dimensions), ` +
+          `but this index uses ${this.embedder.modelName} (${this.embedder.dimension})`
+      );
+    }
+
+    await this.store.replaceRecords(records, header.metric);
+    return header;
+  }
+
   /**
    * Optimize the vector store (compact fragments, update indices)
    * Call this after bulk indexing operations for better performance
@@ -209,6 +423,7 @@ export class VectorStorage {
    * Close the storage
    */
   async close(): Promise<void> {
+    await this.embeddingCache?.flush();
     await this.store.close();
     this.initialized = false;
   }
@@ -0,0 +1,98 @@
+/**
+ * Vector Quantization
+ * Scalar quantization of stored vectors (float32 → int8) for indexes that
+ * don't fit comfortably in memory
+ *
+ * Each vector is scaled by its largest absolute component so every value maps
+ * onto -127..127, and stored as one signed byte per dimension plus the scale:
+ * about a quarter of the float32 size. Queries stay float32 and are compared
+ * with the dequantized vectors, which keeps the recall cost small.
+ */
+
+import type { QuantizationScheme, SimilarityMetric } from './types';
+
+/**
+ * A vector quantized to int8: `vector[i] ≈ codes[i] * scale`
+ */
+export interface QuantizedVector {
+  codes: Int8Array;
+  scale: number;
+}
+
+/**
+ * Quantize a vector to int8 with a per-vector scale
+ */
+export function quantizeInt8(vector: ArrayLike<number>): QuantizedVector {
+  let maxAbs = 0;
+  for (let i = 0; i < vector.length; i++) {
+    maxAbs = Math.max(maxAbs, Math.abs(vector[i]));
+  }
+  const scale = maxAbs === 0 ? 1 : maxAbs / 127;
+  const codes = new Int8Array(vector.length);
+  for (let i = 0; i < vector.length; i++) {
+    codes[i] = Math.round(vector[i] / scale);
+  }
+  return { codes, scale };
+}
+
+/**
+ * Approximate float vector a quantized vector stands for
+ */
+export function dequantizeInt8({ codes, scale }: QuantizedVector): number[] {
+  return Array.from(codes, (code) => code * scale);
+}
+
+/**
+ * Distance between a float query and a quantized vector, in the units LanceDB
+ * reports for the metric (see vectorDistance)
+ */
+export function quantizedDistance(
+  query: ArrayLike<number>,
+  { codes, scale }: QuantizedVector,
+  metric: SimilarityMetric
+): number {
+  return vectorDistance(query, codes, metric, scale);
+}
+
+/**
+ * Distance between two vectors in the units LanceDB reports for the metric
+ * (see distanceToScore): `1 - cosine` for cosine, `1 - dot` for dot, and
+ * squared L2 for euclidean. `scale` multiplies every component of `vector`.
+ */
+export function vectorDistance(
+  query: ArrayLike<number>,
+  vector: ArrayLike<number>,
+  metric: SimilarityMetric,
+  scale = 1
+): number {
+  let dot = 0;
+  let queryNorm = 0;
+  let vectorNorm = 0;
+  let l2 = 0;
+  for (let i = 0; i < vector.length; i++) {
+    const value = vector[i] * scale;
+    dot += query[i] * value;
+    queryNorm += query[i] * query[i];
+    vectorNorm += value * value;
+    l2 += (query[i] - value) * (query[i] - value);
+  }
+
+  switch (metric) {
+    case 'cosine': {
+      const norms = Math.sqrt(queryNorm) * Math.sqrt(vectorNorm);
+      return norms === 0 ? 1 : 1 - dot / norms;
+    }
+    case 'dot':
+      return 1 - dot;
+    case 'euclidean':
+      return l2;
+  }
+}
+
+/**
+ * Bytes a vector of the given dimension takes in memory under a scheme
+ */
+export function bytesPerVector(dimension: number, scheme: QuantizationScheme): number {
+  // int8: one byte per dimension plus the float32 scale
+  return scheme === 'int8' ? dimension + 4 : dimension * 4;
+}
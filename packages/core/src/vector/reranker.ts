@@ -0,0 +1,118 @@
+import {
+  AutoModelForSequenceClassification,
+  AutoTokenizer,
+  type PreTrainedModel,
+  type PreTrainedTokenizer,
+} from '@xenova/transformers';
+import { DEFAULT_EMBEDDER_LOAD_TIMEOUT_MS } from './embedder';
+import type { RerankProvider } from './types';
+
+/** Cross-encoder loaded when none is configured: small, trained on MS MARCO passage ranking */
+export const DEFAULT_RERANK_MODEL = 'Xenova/ms-marco-MiniLM-L-6-v2';
+
+/** Environment variable turning search reranking on: a model name, or `default` */
+export const RERANK_MODEL_ENV = 'DEV_AGENT_RERANK_MODEL';
+
+/**
+ * Reranker set by DEV_AGENT_RERANK_MODEL; none when it's unset, empty, or `off`
+ */
+export function rerankerFromEnv(
+  env: Record<string, string | undefined> = process.env
+): TransformersReranker | undefined {
+  const model = env[RERANK_MODEL_ENV]?.trim();
+  if (!model || ['0', 'false', 'off'].includes(model)) return undefined;
+  const useDefault = ['1', 'true', 'on', 'default'].includes(model);
+  return new TransformersReranker(useDefault ? DEFAULT_RERANK_MODEL : model);
+}
+
+/**
+ * Rerank provider using a Transformers.js cross-encoder
+ *
+ * A cross-encoder reads the query and a document together, so it weighs
+ * which function actually answers the query far better than comparing two
+ * independently embedded vectors. It is also much slower: one model pass per
+ * candidate, so it only reorders a search's top results.
+ */
+export class TransformersReranker implements RerankProvider {
+  readonly modelName: string;
+  private tokenizer: PreTrainedTokenizer | null = null;
+  private model: PreTrainedModel | null = null;
+  private loading: Promise<void> | null = null;
+  private batchSize = 8;
+
+  constructor(
+    modelName = DEFAULT_RERANK_MODEL,
+    private readonly loadTimeoutMs = DEFAULT_EMBEDDER_LOAD_TIMEOUT_MS
+  ) {
+    this.modelName = modelName;
+  }
+
+  /**
+   * Initialize the reranking model
+   * Downloads and caches the model on first run; concurrent calls share one load
+   */
+  async initialize(): Promise<void> {
+    if (this.model) {
+      return;
+    }
+
+    this.loading ??= this.load().finally(() => {
+      this.loading = null;
+    });
+    return this.loading;
+  }
+
+  private async load(): Promise<void> {
+    let timer: ReturnType<typeof setTimeout> | undefined;
+    const timeout = new Promise<never>((_, reject) => {
+      timer = setTimeout(
+        () => reject(new Error(`timed out after ${Math.round(this.loadTimeoutMs / 1000)}s`)),
+        this.loadTimeoutMs
+      );
+    });
+
+    try {
+      const [tokenizer, model] = await Promise.race([
+        Promise.all([
+          AutoTokenizer.from_pretrained(this.modelName),
+          AutoModelForSequenceClassification.from_pretrained(this.modelName),
+        ]),
+        timeout,
+      ]);
+      this.tokenizer = tokenizer;
+      this.model = model;
+    } catch (error) {
+      const reason = error instanceof Error ? error.message : String(error);
+      throw new Error(`Could not load reranking model ${this.modelName}: ${reason}`);
+    } finally {
+      clearTimeout(timer);
+    }
+  }
+
+  /**
+   * Score query-document pairs, from 0 (irrelevant) to 1
+   */
+  async score(query: string, documents: string[]): Promise<number[]> {
+    if (!this.tokenizer || !this.model) {
+      throw new Error('Reranker not initialized. Call initialize() first.');
+    }
+
+    const scores: number[] = [];
+    for (let i = 0; i < documents.length; i += this.batchSize) {
+      const batch = documents.slice(i, i + this.batchSize);
+      const inputs = this.tokenizer(new Array(batch.length).fill(query), {
+        text_pair: batch,
+        padding: true,
+        truncation: true,
+      });
+      // Note: Using any because transformers.js doesn't export specific Tensor types
+      // biome-ignore lint/suspicious/noExplicitAny: Tensor type not exported
+      const { logits } = (await this.model(inputs)) as { logits: any };
+      // One relevance logit per pair
+      for (const logit of Array.from(logits.data as Float32Array)) {
+        scores.push(1 / (1 + Math.exp(-logit)));
+      }
+    }
+    return scores;
+  }
+}
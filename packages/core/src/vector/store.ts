@@ -1,13 +1,163 @@
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
 import type { Connection, Table } from '@lancedb/lancedb';
 import * as lancedb from '@lancedb/lancedb';
+import { escapeRegExp, globToRegExpSource } from '../utils/glob';
+import {
+  dequantizeInt8,
+  type QuantizedVector,
+  quantizedDistance,
+  quantizeInt8,
+  vectorDistance,
+} from './quantization';
+import { truncateVector } from './truncation';
 import type {
   EmbeddingDocument,
+  QuantizationConfig,
   SearchOptions,
   SearchResult,
   SearchResultMetadata,
+  SimilarityMetric,
+  StoredRecord,
   VectorStore,
 } from './types';
 
+/**
+ * Over-fetch factor when a metadata filter is applied after vector search
+ */
+const FILTER_OVERFETCH = 5;
+
+/**
+ * int8 candidates reranked at full precision per requested result, by default
+ */
+const DEFAULT_RERANK_FACTOR = 4;
+
+/**
+ * Sidecar file recording how the table's vectors were written
+ */
+const STORE_METADATA_FILE = 'store-metadata.json';
+
+/**
+ * Quantization as recorded with a table
+ */
+type StoredQuantization = Required<Omit<QuantizationConfig, 'rerankFactor'>>;
+
+/**
+ * A ranked row: ID, metadata JSON, and distance in LanceDB's units
+ */
+interface RankedRow {
+  id: string;
+  metadata: string;
+  _distance?: number;
+}
+
+/**
+ * LanceDB distance type for each similarity metric
+ */
+const DISTANCE_TYPES: Record<SimilarityMetric, 'cosine' | 'dot' | 'l2'> = {
+  cosine: 'cosine',
+  dot: 'dot',
+  euclidean: 'l2',
+};
+
+/**
+ * Prepare a vector for storage or querying under a metric.
+ * Cosine vectors are unit-normalized; dot and euclidean keep their magnitude.
+ */
+export function normalizeForMetric(vector: number[], metric: SimilarityMetric): number[] {
+  if (metric !== 'cosine') return vector;
+  const norm = Math.sqrt(vector.reduce((sum, value) => sum + value * value, 0));
+  return norm === 0 ? vector : vector.map((value) => value / norm);
+}
+
+/**
+ * Convert a LanceDB distance to a similarity score (0-1 range)
+ */
+export function distanceToScore(distance: number, metric: SimilarityMetric): number {
+  switch (metric) {
+    case 'cosine': {
+      // Between unit vectors the squared L2 distance is twice the cosine distance, so
+      // scores match indexes written before the metric was configurable
+      const l2 = 2 * distance;
+      return Math.exp(-(l2 * l2));
+    }
+    case 'dot':
+      // LanceDB reports 1 - dot product
+      return Math.min(1, Math.max(0, 1 - distance));
+    case 'euclidean':
+      // Exponential decay: close to 1 for distance≈0, approaching 0 for large distances
+      return Math.exp(-(distance * distance));
+  }
+}
+
+/**
+ * Check whether metadata matches every key/value in a filter (strict equality)
+ */
+export function matchesFilter(
+  metadata: SearchResultMetadata,
+  filter: Record<string, unknown> | undefined
+): boolean {
+  if (!filter) return true;
+  return Object.entries(filter).every(([key, value]) => metadata[key] === value);
+}
+
+/**
+ * Check whether a symbol was last modified at or after a date; symbols without
+ * blame data never match a date
+ */
+export function changedAfter(metadata: SearchResultMetadata, since: string | undefined): boolean {
+  if (since === undefined) return true;
+  if (!metadata.lastModified) return false;
+  return Date.parse(metadata.lastModified) >= Date.parse(since);
+}
+
+/**
+ * Regular expression source for a path filter, matched against document IDs
+ *
+ * IDs start with the file path (`path:name:line`). A filter without glob
+ * characters is a path prefix (`packages/core/src/scanner`); anything else is
+ * a glob over the whole path: `**` crosses directories, `*` and `?` don't,
+ * `[...]` and `{a,b}` work as usual. A glob without a slash (`*_test.go`)
+ * matches file names at any depth.
+ */
+export function pathFilterPattern(pathFilter: string): string {
+  const filter = pathFilter.trim().replace(/^\.\//, '');
+  if (!/[*?[{]/.test(filter)) {
+    return `^${escapeRegExp(filter)}`;
+  }
+  const anyDepth = filter.includes('/') ? '' : '(?:.*/)?';
+  return `^${anyDepth}${globToRegExpSource(filter)}:`;
+}
+
+/**
+ * Check whether a file path matches a path filter (see pathFilterPattern)
+ */
+export function matchesPathFilter(filePath: string, pathFilter: string | undefined): boolean {
+  if (pathFilter === undefined) return true;
+  return new RegExp(pathFilterPattern(pathFilter)).test(`${filePath}:`);
+}
+
+/**
+ * SQL predicate matching rows by ID, single quotes escaped
+ */
+function idPredicate(ids: string[]): string {
+  const escapedIds = ids.map((id) => id.replace(/'/g, "''"));
+  return `id IN ('${escapedIds.join("', '")}')`;
+}
+
+/**
+ * int8 codes from a binary column
+ */
+function toCodes(bytes: Uint8Array): Int8Array {
+  return Int8Array.from(new Int8Array(bytes.buffer, bytes.byteOffset, bytes.byteLength));
+}
+
+function quantizationLabel(quantization: StoredQuantization): string {
+  return quantization.scheme === 'int8' && quantization.keepFullPrecision
+    ? 'int8 (full precision kept)'
+    : quantization.scheme;
+}
+
 /**
  * Vector store implementation using LanceDB
  */
@@ -16,12 +166,65 @@ export class LanceDBVectorStore implements VectorStore {
   private readonly tableName = 'documents';
   private connection: Connection | null = null;
   private table: Table | null = null;
+  private readonly requestedMetric?: SimilarityMetric;
+  private indexMetric: SimilarityMetric | null = null;
+  private readonly requestedQuantization?: QuantizationConfig;
+  private indexQuantization: StoredQuantization | null = null;
+  /** int8 vectors by ID, loaded on the first quantized search after a write */
+  private quantized: Map<string, QuantizedVector> | null = null;
+  private readonly requestedDimensions?: number;
+  /** Vector length recorded with the table; null for tables written before it was */
+  private indexDimensions: number | null = null;
 
-  constructor(path: string, _dimension = 384) {
-    this.path = path;
+  /**
+   * @param metric - Similarity metric for new tables; an existing table written with a
+   * different metric is rejected on add() and search(). Defaults to the table's metric.
+   * @param quantization - Vector quantization for new tables, checked against existing
+   * tables like the metric. Defaults to the table's, or none.
+   * @param truncateDimensions - Keep only this many leading dimensions of each vector
+   * (see ./truncation.ts), checked against existing tables like the metric. Defaults to
+   * the table's, or every dimension.
+   */
+  constructor(
+    storePath: string,
+    _dimension = 384,
+    metric?: SimilarityMetric,
+    quantization?: QuantizationConfig,
+    truncateDimensions?: number
+  ) {
+    this.path = storePath;
+    this.requestedMetric = metric;
+    this.requestedQuantization = quantization;
+    this.requestedDimensions = truncateDimensions;
     // Note: dimension is determined by the embeddings passed to add()
   }
 
+  /**
+   * Metric vectors are written and queried with
+   */
+  get metric(): SimilarityMetric {
+    return this.indexMetric ?? this.requestedMetric ?? 'cosine';
+  }
+
+  /**
+   * Quantization vectors are written with
+   */
+  get quantization(): StoredQuantization {
+    return (
+      this.indexQuantization ?? {
+        scheme: this.requestedQuantization?.scheme ?? 'none',
+        keepFullPrecision: this.requestedQuantization?.keepFullPrecision ?? false,
+      }
+    );
+  }
+
+  /**
+   * Dimensions vectors are cut to on write and query, or undefined to keep every one
+   */
+  get dimensions(): number | undefined {
+    return this.indexDimensions ?? this.requestedDimensions;
+  }
+
   /**
    * Initialize the vector store
    */
@@ -39,6 +242,7 @@ export class LanceDBVectorStore implements VectorStore {
 
       if (tableNames.includes(this.tableName)) {
         this.table = await this.connection.openTable(this.tableName);
+        await this.readStoreMetadata();
       }
       // Table will be created on first add() call
     } catch (error) {
@@ -64,19 +268,25 @@ export class LanceDBVectorStore implements VectorStore {
       return;
     }
 
+    this.assertCompatible();
+
     try {
-      // Prepare data for LanceDB
-      const data = documents.map((doc, i) => ({
+      // Prepare data for LanceDB: truncate first, so cosine vectors are unit length as stored
+      const metric = this.metric;
+      const records = documents.map((doc, i) => ({
         id: doc.id,
         text: doc.text,
-        vector: embeddings[i],
-        metadata: JSON.stringify(doc.metadata),
+        vector: normalizeForMetric(truncateVector(embeddings[i], this.dimensions), metric),
+        metadata: doc.metadata,
       }));
+      const data = this.toRows(records);
+      this.quantized = null;
 
       if (!this.table) {
         // Create table on first add
         try {
           this.table = await this.connection.createTable(this.tableName, data);
+          await this.writeStoreMetadata(metric, records[0].vector.length);
           // Create scalar index on 'id' column for fast upsert operations
           await this.ensureIdIndex();
         } catch (createError) {
@@ -84,6 +294,8 @@ export class LanceDBVectorStore implements VectorStore {
           if (createError instanceof Error && createError.message.includes('already exists')) {
             // Open the existing table
             this.table = await this.connection.openTable(this.tableName);
+            await this.readStoreMetadata();
+            this.assertCompatible();
             // Now add the data using mergeInsert
             await this.table
               .mergeInsert('id')
@@ -118,33 +330,44 @@ export class LanceDBVectorStore implements VectorStore {
       return []; // No documents yet
     }
 
-    const { limit = 10, scoreThreshold = 0 } = options;
+    const { limit = 10, scoreThreshold = 0, filter, changedSince, pathFilter } = options;
+    const hasFilter =
+      (filter !== undefined && Object.keys(filter).length > 0) ||
+      changedSince !== undefined ||
+      options.excludeGenerated === true;
+
+    // Rankings are only meaningful under the metric the vectors were written with
+    this.assertCompatible();
+    const metric = this.metric;
 
     try {
-      // Perform vector search
-      // LanceDB uses L2 distance by default, returning lower values for more similar vectors
-      const results = await this.table.search(queryEmbedding).limit(limit).toArray();
+      // Perform vector search, returning lower distances for more similar vectors
+      // Metadata is stored as JSON, so filters are applied after an over-fetched search
+      const fetchLimit = hasFilter ? limit * FILTER_OVERFETCH : limit;
+      const vector = normalizeForMetric(truncateVector(queryEmbedding, this.dimensions), metric);
+      const results =
+        this.quantization.scheme === 'int8'
+          ? await this.searchQuantized(vector, fetchLimit, pathFilter)
+          : await this.searchVectors(vector, fetchLimit, pathFilter);
 
       // Transform results
-      // Convert L2 distance to a similarity score (0-1 range)
-      // For normalized embeddings, L2 distance ≈ sqrt(2 * (1 - cosine_similarity))
-      // So cosine_similarity ≈ 1 - (L2_distance^2 / 2)
-      // We'll use an exponential decay to convert distance to similarity
       return results
         .map((result) => {
           const distance =
             result._distance !== undefined ? result._distance : Number.POSITIVE_INFINITY;
-          // Use exponential decay: score = e^(-distance^2)
-          // This gives scores close to 1 for distance≈0, and approaches 0 for large distances
-          const score = Math.exp(-(distance * distance));
+          const score = distanceToScore(distance, metric);
 
           return {
-            id: result.id as string,
+            id: result.id,
             score,
-            metadata: JSON.parse(result.metadata as string) as SearchResultMetadata,
+            metadata: JSON.parse(result.metadata) as SearchResultMetadata,
           };
         })
-        .filter((result) => result.score >= scoreThreshold);
+        .filter((result) => result.score >= scoreThreshold)
+        .filter((result) => matchesFilter(result.metadata, filter))
+        .filter((result) => changedAfter(result.metadata, changedSince))
+        .filter((result) => !(options.excludeGenerated && result.metadata.generated))
+        .slice(0, limit);
     } catch (error) {
       throw new Error(
         `Failed to search: ${error instanceof Error ? error.message : String(error)}`
@@ -185,6 +408,77 @@ export class LanceDBVectorStore implements VectorStore {
     }
   }
 
+  /**
+   * Every stored row with its vector exactly as written (int8 vectors are
+   * dequantized unless full precision was kept)
+   */
+  async getRecords(): Promise<StoredRecord[]> {
+    if (!this.table) {
+      return [];
+    }
+
+    try {
+      const { scheme, keepFullPrecision } = this.quantization;
+      const fromCodes = scheme === 'int8' && !keepFullPrecision;
+      const rows = await this.table
+        .query()
+        .select(['id', 'text', 'metadata', ...(fromCodes ? ['codes', 'scale'] : ['vector'])])
+        .limit(await this.table.countRows())
+        .toArray();
+      return rows.map((row) => ({
+        id: row.id as string,
+        text: row.text as string,
+        vector: fromCodes
+          ? dequantizeInt8({ codes: toCodes(row.codes), scale: row.scale as number })
+          : Array.from(row.vector as ArrayLike<number>),
+        metadata: JSON.parse(row.metadata as string) as Record<string, unknown>,
+      }));
+    } catch (error) {
+      throw new Error(
+        `Failed to read records: ${error instanceof Error ? error.message : String(error)}`
+      );
+    }
+  }
+
+  /**
+   * Replace the table with rows written verbatim under their original metric.
+   * Vectors are not re-normalized, so a restored table ranks exactly like the original.
+   */
+  async replaceRecords(records: StoredRecord[], metric: SimilarityMetric): Promise<void> {
+    if (!this.connection) {
+      throw new Error('Store not initialized. Call initialize() first.');
+    }
+    if (this.requestedMetric && this.requestedMetric !== metric) {
+      throw new Error(
+        `Records use the '${metric}' similarity metric, but '${this.requestedMetric}' was requested`
+      );
+    }
+
+    const dimensions = records[0]?.vector.length;
+    if (this.requestedDimensions && dimensions && this.requestedDimensions !== dimensions) {
+      throw new Error(
+        `Records have ${dimensions}-dimensional vectors, ` +
+          `but truncation to ${this.requestedDimensions} was requested`
+      );
+    }
+
+    await this.clear();
+    if (records.length === 0) {
+      return;
+    }
+
+    try {
+      const data = this.toRows(records);
+      this.table = await this.connection.createTable(this.tableName, data);
+      await this.writeStoreMetadata(metric, dimensions);
+      await this.ensureIdIndex();
+    } catch (error) {
+      throw new Error(
+        `Failed to write records: ${error instanceof Error ? error.message : String(error)}`
+      );
+    }
+  }
+
   /**
    * Find similar documents to a given document by ID
    * Uses the document's existing embedding for efficient similarity search
@@ -198,20 +492,11 @@ export class LanceDBVectorStore implements VectorStore {
     }
 
     try {
-      // Get the document and its embedding
-      const results = await this.table
-        .query()
-        .where(`id = '${documentId}'`)
-        .select(['id', 'vector'])
-        .limit(1)
-        .toArray();
-
-      if (results.length === 0) {
+      const documentEmbedding = await this.getVector(documentId);
+      if (!documentEmbedding) {
         return []; // Document not found
       }
 
-      const documentEmbedding = results[0].vector as number[];
-
       // Use the document's embedding to find similar documents
       return this.search(documentEmbedding, options);
     } catch (error) {
@@ -221,6 +506,31 @@ export class LanceDBVectorStore implements VectorStore {
     }
   }
 
+  /**
+   * Stored embedding of a document (dequantized for int8 tables), or null if it isn't indexed
+   */
+  async getVector(documentId: string): Promise<number[] | null> {
+    if (!this.table) {
+      return null;
+    }
+
+    const { scheme, keepFullPrecision } = this.quantization;
+    const fromCodes = scheme === 'int8' && !keepFullPrecision;
+    const [row] = await this.table
+      .query()
+      .where(idPredicate([documentId]))
+      .select(fromCodes ? ['id', 'codes', 'scale'] : ['id', 'vector'])
+      .limit(1)
+      .toArray();
+
+    if (!row) {
+      return null;
+    }
+    return fromCodes
+      ? dequantizeInt8({ codes: toCodes(row.codes), scale: row.scale as number })
+      : Array.from(row.vector as ArrayLike<number>);
+  }
+
   /**
    * Get a document by ID
    */
@@ -230,12 +540,13 @@ export class LanceDBVectorStore implements VectorStore {
     }
 
     try {
-      // Use a dummy vector for search since LanceDB requires it
-      // We'll search with a zero vector and filter results by ID
-      const dummyVector = new Array(384).fill(0); // Default dimension
-      const results = await this.table.search(dummyVector).limit(10000).toArray();
-
-      const result = results.find((r) => r.id === id);
+      // A plain query by ID; int8 tables may have no vector column to search
+      const [result] = await this.table
+        .query()
+        .where(idPredicate([id]))
+        .select(['id', 'text', 'metadata'])
+        .limit(1)
+        .toArray();
 
       if (!result) {
         return null;
@@ -262,11 +573,9 @@ export class LanceDBVectorStore implements VectorStore {
     }
 
     try {
-      // Delete using SQL IN predicate
-      // Escape single quotes in IDs to prevent SQL injection
-      const escapedIds = ids.map((id) => id.replace(/'/g, "''"));
-      const predicate = `id IN ('${escapedIds.join("', '")}')`;
-      await this.table.delete(predicate);
+      // Delete using SQL IN predicate (quotes escaped to prevent SQL injection)
+      await this.table.delete(idPredicate(ids));
+      this.quantized = null;
     } catch (error) {
       throw new Error(
         `Failed to delete documents: ${error instanceof Error ? error.message : String(error)}`
@@ -274,6 +583,52 @@ export class LanceDBVectorStore implements VectorStore {
     }
   }
 
+  /**
+   * IDs of every stored document
+   */
+  async getIds(): Promise<string[]> {
+    if (!this.table) {
+      return [];
+    }
+
+    try {
+      const rows = await this.table
+        .query()
+        .select(['id'])
+        .limit(await this.table.countRows())
+        .toArray();
+      return rows.map((row) => row.id as string);
+    } catch (error) {
+      throw new Error(
+        `Failed to list document IDs: ${error instanceof Error ? error.message : String(error)}`
+      );
+    }
+  }
+
+  /**
+   * Whether embeddings of this length can be added next to the stored ones:
+   * the table was written under the requested metric, quantization, and
+   * truncation, and its vectors have the length these would be stored at.
+   * Otherwise the store has to be cleared first.
+   */
+  async acceptsEmbeddings(length: number): Promise<boolean> {
+    if (!this.table) {
+      return true;
+    }
+    try {
+      this.assertCompatible();
+    } catch {
+      return false;
+    }
+
+    const [row] = await this.table.query().select(['id']).limit(1).toArray();
+    if (!row) {
+      return true;
+    }
+    const stored = await this.getVector(row.id as string);
+    return stored?.length === Math.min(this.dimensions ?? length, length);
+  }
+
   /**
    * Clear all documents from the store (destructive operation)
    */
@@ -288,6 +643,12 @@ export class LanceDBVectorStore implements VectorStore {
         await this.connection.dropTable('documents');
         this.table = null;
       }
+      // The next add() starts a fresh table under the requested metric
+      await fs.rm(path.join(this.path, STORE_METADATA_FILE), { force: true });
+      this.indexMetric = null;
+      this.indexQuantization = null;
+      this.indexDimensions = null;
+      this.quantized = null;
     } catch (error) {
       throw new Error(
         `Failed to clear vector store: ${error instanceof Error ? error.message : String(error)}`
@@ -329,6 +690,198 @@ export class LanceDBVectorStore implements VectorStore {
     }
   }
 
+  /**
+   * Reject reads and writes when the table was written under a different metric,
+   * quantization, or truncation
+   */
+  private assertCompatible(): void {
+    if (this.indexMetric && this.requestedMetric && this.indexMetric !== this.requestedMetric) {
+      throw new Error(
+        `Vector index at ${this.path} uses the '${this.indexMetric}' similarity metric, ` +
+          `but '${this.requestedMetric}' was requested. ` +
+          'Use the same metric, or re-index with --force to switch.'
+      );
+    }
+
+    const requested = this.requestedQuantization && {
+      scheme: this.requestedQuantization.scheme,
+      keepFullPrecision:
+        this.requestedQuantization.scheme === 'int8' &&
+        (this.requestedQuantization.keepFullPrecision ?? false),
+    };
+    const index = this.indexQuantization;
+    if (requested && index && quantizationLabel(requested) !== quantizationLabel(index)) {
+      throw new Error(
+        `Vector index at ${this.path} uses '${quantizationLabel(index)}' vector quantization, ` +
+          `but '${quantizationLabel(requested)}' was requested. ` +
+          'Use the same quantization, or re-index with --force to switch.'
+      );
+    }
+
+    // Tables that didn't record a length hold whole vectors, which no truncation matches
+    const dimensions = this.requestedDimensions;
+    if (this.table && dimensions !== undefined && this.indexDimensions !== dimensions) {
+      const stored = this.indexDimensions ?? 'full-length';
+      throw new Error(
+        `Vector index at ${this.path} stores ${stored} dimensions per vector, ` +
+          `but truncation to ${dimensions} was requested. ` +
+          'Use the same truncation, or re-index with --force to switch.'
+      );
+    }
+  }
+
+  /**
+   * Read the metric, quantization, and vector length recorded for the existing
+   * table. Tables written before they were recorded hold normalized, whole
+   * float32 vectors, i.e. cosine without quantization or truncation.
+   */
+  private async readStoreMetadata(): Promise<void> {
+    let stored: {
+      metric?: SimilarityMetric;
+      quantization?: Partial<StoredQuantization>;
+      dimensions?: number;
+    } = {};
+    try {
+      const content = await fs.readFile(path.join(this.path, STORE_METADATA_FILE), 'utf-8');
+      stored = JSON.parse(content);
+    } catch {
+      // Missing or unreadable: use the defaults
+    }
+    this.indexMetric = stored.metric && stored.metric in DISTANCE_TYPES ? stored.metric : 'cosine';
+    const int8 = stored.quantization?.scheme === 'int8';
+    this.indexQuantization = {
+      scheme: int8 ? 'int8' : 'none',
+      keepFullPrecision: int8 && stored.quantization?.keepFullPrecision === true,
+    };
+    this.indexDimensions = typeof stored.dimensions === 'number' ? stored.dimensions : null;
+  }
+
+  /**
+   * @param dimensions - Length of the vectors written, so queries are cut to match
+   */
+  private async writeStoreMetadata(
+    metric: SimilarityMetric,
+    dimensions: number | undefined
+  ): Promise<void> {
+    const quantization = this.quantization;
+    await fs.writeFile(
+      path.join(this.path, STORE_METADATA_FILE),
+      JSON.stringify({ metric, quantization, dimensions }, null, 2),
+      'utf-8'
+    );
+    this.indexMetric = metric;
+    this.indexQuantization = quantization;
+    this.indexDimensions = dimensions ?? null;
+  }
+
+  /**
+   * LanceDB rows for records whose vectors are already prepared for the metric.
+   * int8 rows hold `codes` and `scale`, plus `vector` when full precision is kept.
+   */
+  private toRows(records: StoredRecord[]): Record<string, unknown>[] {
+    const { scheme, keepFullPrecision } = this.quantization;
+    return records.map(({ id, text, vector, metadata }) => {
+      const row = { id, text, metadata: JSON.stringify(metadata) };
+      if (scheme !== 'int8') {
+        return { ...row, vector };
+      }
+      const { codes, scale } = quantizeInt8(vector);
+      return {
+        ...row,
+        codes: Buffer.from(codes.buffer),
+        scale,
+        ...(keepFullPrecision ? { vector } : {}),
+      };
+    });
+  }
+
+  /**
+   * Rank float32 vectors with LanceDB
+   */
+  private async searchVectors(
+    vector: number[],
+    limit: number,
+    pathFilter: string | undefined
+  ): Promise<RankedRow[]> {
+    let query = (this.table as Table)
+      .vectorSearch(vector)
+      .distanceType(DISTANCE_TYPES[this.metric]);
+    if (pathFilter !== undefined) {
+      // IDs start with the file path, so paths are filtered in the store before ranking
+      const pattern = pathFilterPattern(pathFilter).replace(/'/g, "''");
+      query = query.where(`regexp_match(id, '${pattern}')`);
+    }
+    return query.limit(limit).toArray();
+  }
+
+  /**
+   * Rank int8 vectors in process. With full precision kept, `rerankFactor`
+   * times as many candidates are reranked by their float32 vectors.
+   */
+  private async searchQuantized(
+    vector: number[],
+    limit: number,
+    pathFilter: string | undefined
+  ): Promise<RankedRow[]> {
+    const table = this.table as Table;
+    const metric = this.metric;
+    const { keepFullPrecision } = this.quantization;
+    const rerankFactor = this.requestedQuantization?.rerankFactor ?? DEFAULT_RERANK_FACTOR;
+    const pattern =
+      pathFilter === undefined ? undefined : new RegExp(pathFilterPattern(pathFilter));
+
+    const ranked: { id: string; distance: number }[] = [];
+    for (const [id, quantized] of await this.loadQuantized()) {
+      if (pattern && !pattern.test(id)) continue;
+      ranked.push({ id, distance: quantizedDistance(vector, quantized, metric) });
+    }
+    const candidates = ranked
+      .sort((a, b) => a.distance - b.distance)
+      .slice(0, keepFullPrecision ? limit * rerankFactor : limit);
+    if (candidates.length === 0) {
+      return [];
+    }
+
+    const distances = new Map(candidates.map((c) => [c.id, c.distance]));
+    const rows = await table
+      .query()
+      .where(idPredicate(candidates.map((c) => c.id)))
+      .select(['id', 'metadata', ...(keepFullPrecision ? ['vector'] : [])])
+      .limit(candidates.length)
+      .toArray();
+    return rows
+      .map((row) => ({
+        id: row.id as string,
+        metadata: row.metadata as string,
+        _distance: keepFullPrecision
+          ? vectorDistance(vector, row.vector as ArrayLike<number>, metric)
+          : (distances.get(row.id as string) as number),
+      }))
+      .sort((a, b) => a._distance - b._distance)
+      .slice(0, limit);
+  }
+
+  /**
+   * int8 vectors of every row, kept in memory until the next write
+   */
+  private async loadQuantized(): Promise<Map<string, QuantizedVector>> {
+    if (!this.quantized) {
+      const table = this.table as Table;
+      const rows = await table
+        .query()
+        .select(['id', 'codes', 'scale'])
+        .limit(await table.countRows())
+        .toArray();
+      this.quantized = new Map(
+        rows.map((row) => [
+          row.id as string,
+          { codes: toCodes(row.codes), scale: row.scale as number },
+        ])
+      );
+    }
+    return this.quantized;
+  }
+
   /**
    * Ensure scalar index exists on 'id' column for fast upsert operations
    */
@@ -2,7 +2,29 @@
  * Vector storage and embedding types
  */
 
-import type { CalleeInfo, DocumentType } from '../scanner/types';
+import type {
+  CalleeInfo,
+  ConstructorConfidence,
+  ContextFinding,
+  CrashContext,
+  CrashSite,
+  DeferredCall,
+  DocumentOverflow,
+  DocumentType,
+  GoDependencyUse,
+  GoDiagnostic,
+  GoExample,
+  GoFuncLiteral,
+  GoIterator,
+  GoTypeSet,
+  HttpRoute,
+  InterfaceAssertion,
+  OpenApiOperation,
+  PlatformVariant,
+  ReturnedError,
+  SqlQuery,
+  StructField,
+} from '../scanner/types';
 
 /**
  * Document to be embedded and stored
@@ -33,11 +55,100 @@ export interface SearchResultMetadata {
   docstring?: string; // Documentation comment
   snippet?: string; // Actual code content (truncated if large)
   imports?: string[]; // File-level imports (module specifiers)
+  module?: string; // Owning module (Go module path)
+  goVersion?: string; // Go: language version from the owning go.mod (e.g. "1.22.3")
+  dependencies?: GoDependencyUse[]; // Go: external modules the file imports, with versions
+  packageDoc?: string; // Go: first paragraph of the package doc comment
   callees?: CalleeInfo[]; // Functions/methods this component calls
+  referencesTypes?: string[]; // Go: types it refers to; other packages' qualified as written
+  complexity?: number; // Cyclomatic complexity (functions/methods)
+  parseError?: string; // Set when the file had syntax errors and was only partially extracted
+  overflow?: DocumentOverflow; // Set when the document was chunked or summarized for size
+  usesCgo?: boolean; // Go: the file imports "C" (cgo); false for pure-Go files
+  crashes?: CrashSite[]; // Go: calls to panic, log.Fatal*, log.Panic*, or os.Exit
+  crashContext?: CrashContext; // Go: library, init, main, or test code
+  recovers?: boolean; // Go: calls recover()
+  defers?: DeferredCall[]; // Go: calls deferred by the function (e.g. `c.Close()`)
+  errorsReturned?: ReturnedError[]; // Go: errors it can return (e.g. `ErrNotFound`, wrapped)
+  constructs?: string; // Go: package type the function constructs
+  constructorConfidence?: ConstructorConfidence; // Go: high, medium, or low
+  iterator?: GoIterator; // Go: iter.Seq/Seq2 (or equivalent func) the function returns
+  example?: GoExample; // Go: symbol, code, and expected output of an Example function
+  lastModified?: string; // Last commit touching the symbol's lines (ISO; when blame is enabled)
+  lastAuthor?: string; // Author of that commit
+  diagnostics?: GoDiagnostic[]; // Go: `go build`/`go vet` findings (tool, line, message)
+  asserts?: InterfaceAssertion; // Go: interface assertion made by a `var _ I = T` declaration
+  routes?: HttpRoute[]; // Go: HTTP routes the function registers (method, path, handler)
+  sqlQueries?: SqlQuery[]; // Go: SQL statements the function runs (operation, tables, text)
+  funcLiteral?: GoFuncLiteral; // Go: enclosing function, captures, and use of a func literal
+  openApiOperation?: OpenApiOperation; // OpenAPI: method, path, and operationId of a spec operation
+  typeSet?: GoTypeSet; // Go constraint interfaces: union terms and comparable
+  typeConstraints?: string[]; // Go generics: constraints its type parameters name (e.g. `Ordered`)
+  contextFindings?: ContextFinding[]; // Go: context.Context hygiene findings (kind, line, call)
+  buildConstraint?: string; // Go: build constraint of the file (e.g. `linux && amd64`)
+  platformVariants?: PlatformVariant[]; // Go: variants for other platforms, folded into this one
+  generated?: boolean; // Go: from a generated file; left out of search unless asked for
+  generator?: string; // Go: generator named in the file's header (e.g. `MockGen`)
+  boilerplateDoc?: boolean; // Doc comment repeated across many symbols; no doc boost
+  embeddingProfile?: string; // Indexing: embedding profile it was embedded with (`docs@1a2b3c4d`)
+  constantType?: string; // Go constants: declared type or untyped kind
+  constantValue?: string; // Go constants: literal value, when trivially evaluable
+  constantExpression?: string; // Go constants: initializer text otherwise
+  fields?: StructField[]; // Go structs: fields in declaration order, embedded ones included
+  repository?: string; // Federated search: registered repository the result came from
+  alsoIn?: string[]; // Federated search: other repositories with an identical symbol
+  expandedTerms?: string[]; // Query expansion: synonyms whose variant query ranked this higher
+  docBoost?: number; // Ranking: doc quality boost added to score when ordering (score unchanged)
+  keywordBoost?: number; // Ranking: IDF-weighted keyword boost added the same way
+  rerankScore?: number; // Ranking: reranker score the top candidates were reordered by
+  scoreDebug?: SearchScoreDebug; // Ranking: how the result was scored, when search debug is on
   // Allow additional custom fields for extensibility (e.g., GitHub indexer uses 'document')
   [key: string]: unknown;
 }
 
+/**
+ * How a search result was scored and ranked (search `debug` option)
+ *
+ * Field names are stable; new signals are added as new fields.
+ */
+export interface SearchScoreDebug {
+  /** Similarity from the vector search (0-1); the result's `score` */
+  vectorScore: number;
+  /** Share of the query's keyword weight matched (0-1); null unless keyword scoring ran */
+  keywordScore: number | null;
+  /** Doc quality boost added for ordering (0 when none) */
+  docBoost: number;
+  /** keywordScore times the keyword weight, added for ordering (when keyword scoring ran) */
+  keywordBoost?: number;
+  /** Effective weight of each query token: IDF times any boost, 0 for stopwords */
+  keywordWeights?: Record<string, number>;
+  /** vectorScore + docBoost + keywordBoost, the value results are ordered by unless reranked */
+  finalScore: number;
+  /** 1-based position by vectorScore alone */
+  vectorRank: number;
+  /** 1-based position after boosts, reranking, and sorting */
+  rank: number;
+  /** Query that produced vectorScore: the original or a synonym variant */
+  matchedQuery: string;
+  /** Text actually embedded for that query, identifier words included */
+  embeddedQuery: string;
+  /** Final ordering: relevance, or most recently changed first */
+  sort: 'relevance' | 'recency';
+  /** Session feedback the query vector was adjusted with, when any */
+  feedback?: { relevant: number; nonRelevant: number };
+  /** Reranking of the top candidates, when a reranker ran (docBoost is 0 then, no keywordBoost) */
+  rerank?: {
+    /** Reranker model */
+    model: string;
+    /** Reranker score, for results among the reranked candidates */
+    score?: number;
+    /** 1-based position before reranking */
+    preRank: number;
+    /** 1-based position after reranking, before a recency sort */
+    postRank: number;
+  };
+}
+
 /**
  * Search result from vector store
  */
@@ -47,6 +158,16 @@ export interface SearchResult {
   metadata: SearchResultMetadata;
 }
 
+/**
+ * A stored row as written: vectors are already normalized for the table's metric
+ */
+export interface StoredRecord {
+  id: string;
+  text: string;
+  vector: number[];
+  metadata: Record<string, unknown>;
+}
+
 /**
  * Search options
  */
@@ -54,6 +175,9 @@ export interface SearchOptions {
   limit?: number; // Number of results to return (default: 10)
   filter?: Record<string, unknown>; // Metadata filters
   scoreThreshold?: number; // Minimum similarity score (default: 0)
+  changedSince?: string; // Only symbols whose lastModified is at or after this date (ISO)
+  pathFilter?: string; // Path prefix or glob (see pathFilterPattern), applied before ranking
+  excludeGenerated?: boolean; // Leave out symbols from generated files (metadata.generated)
 }
 
 /**
@@ -76,8 +200,33 @@ export interface EmbeddingProvider {
 
   /**
    * Generate embeddings for multiple texts (batched for efficiency)
+   * @param signal - Optional signal checked between batches
    */
-  embedBatch(texts: string[]): Promise<number[][]>;
+  embedBatch(texts: string[], signal?: AbortSignal): Promise<number[][]>;
+
+  /**
+   * Count tokens with the model's tokenizer (optional; requires initialize())
+   */
+  countTokens?(text: string): number;
+}
+
+/**
+ * Rerank provider interface
+ * Scores query-document pairs jointly, for reordering a search's top candidates
+ */
+export interface RerankProvider {
+  readonly modelName: string;
+
+  /**
+   * Initialize the reranking model
+   */
+  initialize(): Promise<void>;
+
+  /**
+   * Score how relevant each document is to the query; higher is more relevant
+   * @returns One score per document, in document order
+   */
+  score(query: string, documents: string[]): Promise<number[]>;
 }
 
 /**
@@ -135,6 +284,40 @@ export interface VectorStorageConfig {
   storePath: string; // Path to LanceDB storage
   embeddingModel?: string; // Model name (default: 'Xenova/all-MiniLM-L6-v2')
   dimension?: number; // Embedding dimension (default: 384)
+  embeddingCacheDir?: string; // Reuse vectors for unchanged text across runs (default: no cache)
+  metric?: SimilarityMetric; // Must match the index's metric (default: the index's, or 'cosine')
+  quantization?: QuantizationConfig; // Must match the index's (default: the index's, or none)
+  truncateDimensions?: number; // Keep the first K dimensions; must match the index's (default: all)
+}
+
+/**
+ * How query vectors are compared with stored vectors
+ * - cosine: vectors are unit-normalized on write and query
+ * - dot: raw inner product, for models tuned for dot-product similarity
+ * - euclidean: raw L2 distance
+ */
+export type SimilarityMetric = 'cosine' | 'dot' | 'euclidean';
+
+/**
+ * How stored vectors are encoded
+ * - none: float32, searched by LanceDB
+ * - int8: one signed byte per dimension plus a scale (see ./quantization.ts),
+ *   searched in process at about a quarter of the memory, with a small recall cost
+ */
+export type QuantizationScheme = 'none' | 'int8';
+
+/**
+ * Vector quantization for a new index; recorded with the index like the metric
+ */
+export interface QuantizationConfig {
+  scheme: QuantizationScheme;
+  /**
+   * Also keep float32 vectors on disk and rerank int8 candidates with them,
+   * trading disk space for recall (default: false)
+   */
+  keepFullPrecision?: boolean;
+  /** With full precision kept, int8 candidates reranked per requested result (default: 4) */
+  rerankFactor?: number;
 }
 
 /**
@@ -146,3 +329,20 @@ export interface VectorStats {
   dimension: number;
   modelName: string;
 }
+
+/**
+ * Embedding cache effectiveness
+ */
+export interface EmbeddingCacheStats {
+  hits: number; // Texts whose vector was reused
+  misses: number; // Texts sent to the embedder
+  entries: number; // Vectors currently cached
+}
+
+/**
+ * Accumulated time spent embedding and storing documents
+ */
+export interface StorageTiming {
+  embed: number; // milliseconds
+  store: number; // milliseconds
+}
@@ -0,0 +1,100 @@
+/**
+ * Change Batcher Tests
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { ChangeBatcher, isTransientFile } from '../batcher';
+
+describe('ChangeBatcher', () => {
+  beforeEach(() => {
+    vi.useFakeTimers();
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+  });
+
+  it('should flush once activity settles', () => {
+    const onFlush = vi.fn();
+    const batcher = new ChangeBatcher({ debounceMs: 100, maxWaitMs: 1000, onFlush });
+
+    batcher.add('src/a.ts');
+    vi.advanceTimersByTime(50);
+    batcher.add('src/b.ts');
+    vi.advanceTimersByTime(50);
+    expect(onFlush).not.toHaveBeenCalled();
+
+    vi.advanceTimersByTime(50);
+    expect(onFlush).toHaveBeenCalledWith(['src/a.ts', 'src/b.ts']);
+  });
+
+  it('should coalesce repeated changes to the same file', () => {
+    const onFlush = vi.fn();
+    const batcher = new ChangeBatcher({ debounceMs: 100, maxWaitMs: 1000, onFlush });
+
+    batcher.add('src/a.ts');
+    batcher.add('src/a.ts');
+    batcher.add('src/a.ts');
+    vi.advanceTimersByTime(100);
+
+    expect(onFlush).toHaveBeenCalledTimes(1);
+    expect(onFlush).toHaveBeenCalledWith(['src/a.ts']);
+  });
+
+  it('should force a flush after maxWait under continuous changes', () => {
+    const onFlush = vi.fn();
+    const batcher = new ChangeBatcher({ debounceMs: 100, maxWaitMs: 300, onFlush });
+
+    for (let i = 0; i < 6; i++) {
+      batcher.add(`src/file${i}.ts`);
+      vi.advanceTimersByTime(60);
+    }
+
+    expect(onFlush).toHaveBeenCalledTimes(1);
+    expect(onFlush.mock.calls[0][0]).toHaveLength(5);
+  });
+
+  it('should not flush when empty', () => {
+    const onFlush = vi.fn();
+    const batcher = new ChangeBatcher({ debounceMs: 100, maxWaitMs: 300, onFlush });
+
+    batcher.flush();
+
+    expect(onFlush).not.toHaveBeenCalled();
+  });
+
+  it('should drop pending changes on dispose', () => {
+    const onFlush = vi.fn();
+    const batcher = new ChangeBatcher({ debounceMs: 100, maxWaitMs: 300, onFlush });
+
+    batcher.add('src/a.ts');
+    batcher.dispose();
+    vi.advanceTimersByTime(500);
+
+    expect(onFlush).not.toHaveBeenCalled();
+    expect(batcher.size).toBe(0);
+  });
+});
+
+describe('isTransientFile', () => {
+  it.each([
+    'src/app.ts~',
+    'src/.#app.ts',
+    'src/#app.ts#',
+    'src/.app.ts.swp',
+    '4913',
+    'src/app.ts.tmp',
+    'src/app.ts.tmp.1234.5678',
+    'src/app.ts___jb_tmp___',
+    'src/app.ts.crswap',
+  ])('should treat %s as transient', (file) => {
+    expect(isTransientFile(file)).toBe(true);
+  });
+
+  it.each(['src/app.ts', 'src/tmp/app.ts', 'docs/README.md', 'pkg/server.go'])(
+    'should not treat %s as transient',
+    (file) => {
+      expect(isTransientFile(file)).toBe(false);
+    }
+  );
+});
@@ -0,0 +1,235 @@
+/**
+ * Repository Watcher Tests
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest';
+import { AsyncEventBus } from '../../events/event-bus';
+import type { IndexStats } from '../../indexer/types';
+import type { WatchFactory } from '../types';
+import { RepositoryWatcher } from '../watcher';
+
+function makeStats(overrides: Partial<IndexStats> = {}): IndexStats {
+  return {
+    filesScanned: 0,
+    documentsExtracted: 0,
+    documentsIndexed: 0,
+    vectorsStored: 0,
+    duration: 0,
+    errors: [],
+    startTime: new Date(),
+    endTime: new Date(),
+    repositoryPath: '/repo',
+    ...overrides,
+  };
+}
+
+describe('RepositoryWatcher', () => {
+  let emitChange: (filename: string) => void;
+  let close: ReturnType<typeof vi.fn>;
+  let watch: WatchFactory;
+  let indexer: {
+    update: ReturnType<typeof vi.fn>;
+    getTrackedFiles?: () => string[];
+  };
+
+  beforeEach(() => {
+    vi.useFakeTimers();
+    close = vi.fn();
+    watch = (_root, listener) => {
+      emitChange = (filename) => listener('change', filename);
+      return { close };
+    };
+    indexer = { update: vi.fn().mockResolvedValue(makeStats({ documentsIndexed: 2 })) };
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+  });
+
+  function createWatcher(eventBus?: AsyncEventBus) {
+    return new RepositoryWatcher({
+      repositoryPath: '/repo',
+      indexer,
+      eventBus,
+      watch,
+      debounceMs: 100,
+      respectGitignore: false,
+    });
+  }
+
+  it('should run a catch-up update and emit watch.ready on start', async () => {
+    const eventBus = new AsyncEventBus();
+    const ready = vi.fn();
+    eventBus.on('watch.ready', ready);
+    const watcher = createWatcher(eventBus);
+
+    await watcher.start();
+    await vi.runAllTimersAsync();
+
+    expect(indexer.update).toHaveBeenCalledWith({ signal: expect.any(AbortSignal) });
+    expect(watcher.isReady()).toBe(true);
+    expect(ready).toHaveBeenCalledWith(expect.objectContaining({ path: '/repo' }));
+  });
+
+  it('should re-index only the changed files after debouncing', async () => {
+    const watcher = createWatcher();
+    await watcher.start();
+    indexer.update.mockClear();
+
+    emitChange('src/a.ts');
+    emitChange('src/b.ts');
+    emitChange('src/a.ts');
+    await vi.advanceTimersByTimeAsync(100);
+    await watcher.flush();
+
+    expect(indexer.update).toHaveBeenCalledTimes(1);
+    expect(indexer.update).toHaveBeenCalledWith({
+      files: ['src/a.ts', 'src/b.ts'],
+      signal: expect.any(AbortSignal),
+    });
+  });
+
+  it('should hold batches until the catch-up update finishes', async () => {
+    let finishCatchUp: (stats: IndexStats) => void = () => {};
+    indexer.update.mockImplementationOnce(
+      () =>
+        new Promise<IndexStats>((resolve) => {
+          finishCatchUp = resolve;
+        })
+    );
+    const watcher = createWatcher();
+    const started = watcher.start();
+
+    emitChange('src/a.ts');
+    await vi.advanceTimersByTimeAsync(100);
+    expect(indexer.update).toHaveBeenCalledTimes(1);
+
+    finishCatchUp(makeStats());
+    await started;
+    await watcher.flush();
+
+    expect(indexer.update).toHaveBeenCalledTimes(2);
+    expect(indexer.update).toHaveBeenLastCalledWith({
+      files: ['src/a.ts'],
+      signal: expect.any(AbortSignal),
+    });
+  });
+
+  it('should re-index the tracked files under a deleted directory', async () => {
+    indexer.getTrackedFiles = () => ['src/api/a.ts', 'src/api/b.ts', 'src/apikey.ts'];
+    const watcher = createWatcher();
+    await watcher.start();
+    indexer.update.mockClear();
+
+    emitChange('src/api');
+    await watcher.flush();
+
+    expect(indexer.update).toHaveBeenCalledWith({
+      files: ['src/api/a.ts', 'src/api/b.ts'],
+      signal: expect.any(AbortSignal),
+    });
+  });
+
+  it('should ignore editor temp files from atomic saves', async () => {
+    const watcher = createWatcher();
+    await watcher.start();
+    indexer.update.mockClear();
+
+    // Temp file written, then renamed over the target
+    emitChange('src/a.ts.tmp.1234');
+    emitChange('src/a.ts');
+    await watcher.flush();
+
+    expect(indexer.update).toHaveBeenCalledWith({
+      files: ['src/a.ts'],
+      signal: expect.any(AbortSignal),
+    });
+  });
+
+  it('should ignore excluded and unsupported files', async () => {
+    const watcher = createWatcher();
+
+    expect(watcher.shouldIndex('node_modules/pkg/index.ts')).toBe(false);
+    expect(watcher.shouldIndex('api/service.pb.go')).toBe(false);
+    expect(watcher.shouldIndex('image.png')).toBe(false);
+    expect(watcher.shouldIndex('src/index.ts')).toBe(true);
+  });
+
+  it('should emit watch.reindexed after each batch', async () => {
+    const eventBus = new AsyncEventBus();
+    const reindexed = vi.fn();
+    eventBus.on('watch.reindexed', reindexed);
+    const watcher = createWatcher(eventBus);
+    await watcher.start();
+
+    emitChange('src/a.ts');
+    await watcher.flush();
+    await vi.runAllTimersAsync();
+
+    expect(reindexed).toHaveBeenCalledWith(
+      expect.objectContaining({ files: ['src/a.ts'], documentsIndexed: 2 })
+    );
+  });
+
+  it('should report failures and keep watching', async () => {
+    const eventBus = new AsyncEventBus();
+    const errors = vi.fn();
+    eventBus.on('index.error', errors);
+    const watcher = createWatcher(eventBus);
+    await watcher.start();
+
+    indexer.update.mockRejectedValueOnce(new Error('embedding failed'));
+    emitChange('src/a.ts');
+    await watcher.flush();
+    emitChange('src/b.ts');
+    await watcher.flush();
+    await vi.runAllTimersAsync();
+
+    expect(errors).toHaveBeenCalledWith(
+      expect.objectContaining({ error: 'embedding failed', recoverable: true })
+    );
+    expect(indexer.update).toHaveBeenLastCalledWith({
+      files: ['src/b.ts'],
+      signal: expect.any(AbortSignal),
+    });
+  });
+
+  it('should close the watcher and drop pending changes on stop', async () => {
+    const watcher = createWatcher();
+    await watcher.start();
+    indexer.update.mockClear();
+
+    emitChange('src/a.ts');
+    await watcher.stop();
+    await vi.advanceTimersByTimeAsync(500);
+
+    expect(close).toHaveBeenCalled();
+    expect(indexer.update).not.toHaveBeenCalled();
+    expect(watcher.isReady()).toBe(false);
+  });
+
+  it('should cancel an in-flight re-index on stop without reporting an error', async () => {
+    const eventBus = new AsyncEventBus();
+    const errors = vi.fn();
+    eventBus.on('index.error', errors);
+    const watcher = createWatcher(eventBus);
+    await watcher.start();
+
+    let signal: AbortSignal | undefined;
+    indexer.update.mockImplementationOnce(
+      (options: { signal?: AbortSignal }) =>
+        new Promise((_resolve, reject) => {
+          signal = options.signal;
+          signal?.addEventListener('abort', () => reject(signal?.reason));
+        })
+    );
+    emitChange('src/a.ts');
+    await vi.advanceTimersByTimeAsync(100);
+
+    await watcher.stop();
+    await vi.runAllTimersAsync();
+
+    expect(signal?.aborted).toBe(true);
+    expect(errors).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,117 @@
+/**
+ * Change Batcher
+ * Coalesces file change notifications into debounced batches
+ */
+
+import * as path from 'node:path';
+
+/**
+ * Options for ChangeBatcher
+ */
+export interface ChangeBatcherOptions {
+  /** Quiet period after the last change before flushing */
+  debounceMs: number;
+  /** Maximum time a change may wait before a flush is forced */
+  maxWaitMs: number;
+  /** Called with the unique set of changed paths */
+  onFlush: (files: string[]) => void;
+}
+
+/**
+ * Collects changed paths and flushes them once activity settles
+ *
+ * Each change restarts the debounce timer; the max-wait timer bounds
+ * latency so a long-running bulk operation (e.g. a branch checkout)
+ * still produces periodic batches instead of starving the index.
+ */
+export class ChangeBatcher {
+  private readonly pending = new Set<string>();
+  private debounceTimer: ReturnType<typeof setTimeout> | null = null;
+  private maxWaitTimer: ReturnType<typeof setTimeout> | null = null;
+
+  constructor(private readonly options: ChangeBatcherOptions) {}
+
+  /**
+   * Record a changed path
+   */
+  add(file: string): void {
+    this.pending.add(file);
+
+    if (this.debounceTimer) {
+      clearTimeout(this.debounceTimer);
+    }
+    this.debounceTimer = setTimeout(() => this.flush(), this.options.debounceMs);
+
+    if (!this.maxWaitTimer) {
+      this.maxWaitTimer = setTimeout(() => this.flush(), this.options.maxWaitMs);
+    }
+  }
+
+  /**
+   * Flush pending paths immediately
+   */
+  flush(): void {
+    this.clearTimers();
+    if (this.pending.size === 0) {
+      return;
+    }
+
+    const files = Array.from(this.pending).sort();
+    this.pending.clear();
+    this.options.onFlush(files);
+  }
+
+  /**
+   * Number of paths waiting to be flushed
+   */
+  get size(): number {
+    return this.pending.size;
+  }
+
+  /**
+   * Drop pending paths and cancel timers
+   */
+  dispose(): void {
+    this.clearTimers();
+    this.pending.clear();
+  }
+
+  private clearTimers(): void {
+    if (this.debounceTimer) {
+      clearTimeout(this.debounceTimer);
+      this.debounceTimer = null;
+    }
+    if (this.maxWaitTimer) {
+      clearTimeout(this.maxWaitTimer);
+      this.maxWaitTimer = null;
+    }
+  }
+}
+
+/**
+ * Basename patterns written by editors during atomic saves or as backups
+ */
+const TRANSIENT_FILE_PATTERNS: RegExp[] = [
+  /~$/, // Emacs/vim backups (file.ts~)
+  /^\.#/, // Emacs lock files
+  /^#.*#$/, // Emacs auto-save
+  /\.sw[a-p]$/, // Vim swap files
+  /^4913$/, // Vim write-permission probe
+  /\.tmp$/i,
+  /\.tmp[.-][\w.-]+$/i, // file.ts.tmp.1234, file.tmp-abc
+  /___jb_(tmp|old)___$/, // JetBrains safe-write
+  /\.crswap$/, // Chromium-based editors
+  /^\.DS_Store$/,
+];
+
+/**
+ * Check whether a path is an editor temp or backup file
+ *
+ * These appear briefly during "write temp file, then rename" saves.
+ * Ignoring them avoids indexing throwaway content; the rename target
+ * itself still produces a change for the real file.
+ */
+export function isTransientFile(file: string): boolean {
+  const base = path.basename(file);
+  return TRANSIENT_FILE_PATTERNS.some((pattern) => pattern.test(base));
+}
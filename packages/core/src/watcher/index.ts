@@ -0,0 +1,9 @@
+/**
+ * Watcher Module
+ *
+ * Watch mode: keeps the index current by re-indexing changed files.
+ */
+
+export * from './batcher';
+export * from './types';
+export * from './watcher';
@@ -0,0 +1,69 @@
+/**
+ * Watcher Types
+ */
+
+import type { Logger } from '@lytics/kero';
+import type { EventBus } from '../events/types';
+import type { IndexStats, UpdateOptions } from '../indexer/types';
+
+/**
+ * Minimal indexer surface used by the watcher
+ */
+export interface WatchableIndexer {
+  update(options?: UpdateOptions): Promise<IndexStats>;
+  /** Repository-relative paths of indexed files, to expand directory events */
+  getTrackedFiles?(): string[];
+}
+
+/**
+ * Handle returned by a watch factory
+ */
+export interface WatchHandle {
+  close(): void;
+}
+
+/**
+ * Starts watching a directory recursively
+ *
+ * `filename` is relative to the watched root. Injectable for testing.
+ */
+export type WatchFactory = (
+  root: string,
+  listener: (eventType: string, filename: string | null) => void,
+  onError: (error: Error) => void
+) => WatchHandle;
+
+/**
+ * Configuration for RepositoryWatcher
+ */
+export interface RepositoryWatcherConfig {
+  /** Repository root to watch */
+  repositoryPath: string;
+
+  /** Indexer used to re-index changed files */
+  indexer: WatchableIndexer;
+
+  /** Event bus for watch.* events */
+  eventBus?: EventBus;
+
+  /** Quiet period before re-indexing (default: 300ms) */
+  debounceMs?: number;
+
+  /** Maximum delay under a continuous stream of changes (default: 5000ms) */
+  maxWaitMs?: number;
+
+  /** Glob patterns to exclude (default: scanner default exclusions) */
+  excludePatterns?: string[];
+
+  /** Index-only ignore patterns, combined with .devagentignore */
+  ignorePatterns?: string[];
+
+  /** Respect .gitignore (default: true) */
+  respectGitignore?: boolean;
+
+  /** Logger for watch activity */
+  logger?: Logger;
+
+  /** Watch implementation (default: recursive fs.watch) */
+  watch?: WatchFactory;
+}
@@ -0,0 +1,267 @@
+/**
+ * Repository Watcher
+ * Keeps the index current by re-indexing files as they change
+ */
+
+import * as fs from 'node:fs';
+import * as path from 'node:path';
+import type { Logger } from '@lytics/kero';
+import { isGitIgnored } from 'globby';
+import type { EventBus, WatchReadyEvent, WatchReindexedEvent } from '../events/types';
+import type { IndexStats } from '../indexer/types';
+import { createDefaultRegistry } from '../scanner';
+import { DEFAULT_IGNORE_PATTERNS, resolveIgnorePatterns } from '../scanner/ignore';
+import type { ScannerRegistry } from '../scanner/registry';
+import { matchesGlob } from '../utils/glob';
+import { ChangeBatcher, isTransientFile } from './batcher';
+import type { RepositoryWatcherConfig, WatchableIndexer, WatchFactory, WatchHandle } from './types';
+
+const DEFAULT_DEBOUNCE_MS = 300;
+const DEFAULT_MAX_WAIT_MS = 5000;
+
+/**
+ * Default watch implementation: recursive fs.watch
+ */
+const defaultWatch: WatchFactory = (root, listener, onError) => {
+  const watcher = fs.watch(root, { recursive: true }, (eventType, filename) =>
+    listener(eventType, filename ? filename.toString() : null)
+  );
+  watcher.on('error', onError);
+  return watcher;
+};
+
+/**
+ * Repository Watcher
+ *
+ * Watches the repository, batches changes with debouncing, and re-indexes
+ * only the affected files. Re-index runs are serialized; changes arriving
+ * during a run are queued for the next one.
+ *
+ * Emits on the event bus:
+ * - `watch.ready` once the initial catch-up update completes
+ * - `watch.reindexed` after each incremental batch
+ * - `index.error` when a batch fails (the watcher keeps running)
+ *
+ * Stopping cancels an in-flight re-index; the persisted index is left as it
+ * was before that run.
+ */
+export class RepositoryWatcher {
+  private readonly repositoryPath: string;
+  private readonly indexer: WatchableIndexer;
+  private readonly eventBus?: EventBus;
+  private readonly logger?: Logger;
+  private readonly watchFactory: WatchFactory;
+  private readonly excludePatterns?: string[];
+  private readonly userIgnorePatterns: string[];
+  private ignorePatterns: string[];
+  private readonly respectGitignore: boolean;
+  private readonly registry: ScannerRegistry;
+  private readonly batcher: ChangeBatcher;
+
+  private handle: WatchHandle | null = null;
+  private gitIgnored: ((file: string) => boolean) | null = null;
+  private queue: string[] = [];
+  private running: Promise<void> | null = null;
+  private abortController: AbortController | null = null;
+  private ready = false;
+
+  constructor(config: RepositoryWatcherConfig) {
+    this.repositoryPath = path.resolve(config.repositoryPath);
+    this.indexer = config.indexer;
+    this.eventBus = config.eventBus;
+    this.logger = config.logger?.child({ component: 'watcher' });
+    this.watchFactory = config.watch ?? defaultWatch;
+    this.registry = createDefaultRegistry();
+    this.excludePatterns = config.excludePatterns;
+    this.userIgnorePatterns = config.ignorePatterns ?? [];
+    const excludes = config.excludePatterns ?? this.registry.getDefaultExclusions();
+    this.ignorePatterns = resolveIgnorePatterns(
+      [...excludes, ...DEFAULT_IGNORE_PATTERNS],
+      this.userIgnorePatterns
+    );
+    this.respectGitignore = config.respectGitignore ?? true;
+    this.batcher = new ChangeBatcher({
+      debounceMs: config.debounceMs ?? DEFAULT_DEBOUNCE_MS,
+      maxWaitMs: config.maxWaitMs ?? DEFAULT_MAX_WAIT_MS,
+      onFlush: (files) => this.enqueue(files),
+    });
+  }
+
+  /**
+   * Start watching
+   *
+   * Runs a catch-up update first so changes made while nothing was
+   * watching are picked up, then emits `watch.ready`.
+   */
+  async start(): Promise<void> {
+    if (this.handle) {
+      return;
+    }
+    this.abortController = new AbortController();
+    const { signal } = this.abortController;
+
+    // Pick up .devagentignore alongside configured patterns
+    this.ignorePatterns = await this.registry.resolveIgnore({
+      repoRoot: this.repositoryPath,
+      exclude: this.excludePatterns,
+      ignore: this.userIgnorePatterns,
+    });
+
+    if (this.respectGitignore) {
+      this.gitIgnored = await isGitIgnored({ cwd: this.repositoryPath });
+    }
+
+    this.handle = this.watchFactory(
+      this.repositoryPath,
+      (_eventType, filename) => {
+        if (filename) {
+          this.handleChange(filename);
+        }
+      },
+      (error) => this.reportError(error)
+    );
+
+    // The catch-up counts as a run: batches flushed meanwhile queue behind it,
+    // and stop() and flush() wait for both
+    const startTime = Date.now();
+    const catchUp = this.indexer.update({ signal });
+    this.running = catchUp
+      .then(
+        () => this.drain(),
+        () => this.drain()
+      )
+      .finally(() => {
+        this.running = null;
+      });
+    let stats: IndexStats;
+    try {
+      stats = await catchUp;
+    } catch (error) {
+      // Stopped before the catch-up finished
+      if (signal.aborted) return;
+      throw error;
+    }
+    this.ready = true;
+
+    this.logger?.info({ filesReindexed: stats.filesScanned }, 'Watching for changes');
+    this.emit<WatchReadyEvent>('watch.ready', {
+      path: this.repositoryPath,
+      filesReindexed: stats.filesScanned,
+      duration: Date.now() - startTime,
+    });
+  }
+
+  /**
+   * Stop watching, cancel any in-flight re-index, and wait for it to unwind
+   */
+  async stop(): Promise<void> {
+    this.handle?.close();
+    this.handle = null;
+    this.batcher.dispose();
+    this.queue = [];
+    this.ready = false;
+    this.abortController?.abort();
+    this.abortController = null;
+    await this.running;
+  }
+
+  /**
+   * Whether the initial catch-up has completed
+   */
+  isReady(): boolean {
+    return this.ready;
+  }
+
+  /**
+   * Re-index pending changes now instead of waiting for the debounce
+   */
+  async flush(): Promise<void> {
+    this.batcher.flush();
+    await this.running;
+  }
+
+  /**
+   * Check whether a repository-relative path should trigger re-indexing
+   */
+  shouldIndex(file: string): boolean {
+    if (isTransientFile(file)) {
+      return false;
+    }
+    if (!this.registry.getScannerForFile(file)) {
+      return false;
+    }
+    if (this.ignorePatterns.some((pattern) => matchesGlob(file, pattern))) {
+      return false;
+    }
+    if (this.gitIgnored?.(path.join(this.repositoryPath, file))) {
+      return false;
+    }
+    return true;
+  }
+
+  private handleChange(filename: string): void {
+    const file = filename.split(path.sep).join('/');
+    if (this.shouldIndex(file)) {
+      this.batcher.add(file);
+      return;
+    }
+    // A deleted or renamed directory arrives as one event for the directory;
+    // re-check the tracked files under it so they are removed
+    if (!isTransientFile(file) && !this.registry.getScannerForFile(file)) {
+      const prefix = `${file.replace(/\/+$/, '')}/`;
+      for (const tracked of this.indexer.getTrackedFiles?.() ?? []) {
+        if (tracked.startsWith(prefix)) {
+          this.batcher.add(tracked);
+        }
+      }
+    }
+  }
+
+  private enqueue(files: string[]): void {
+    this.queue.push(...files);
+    if (!this.running) {
+      this.running = this.drain().finally(() => {
+        this.running = null;
+      });
+    }
+  }
+
+  private async drain(): Promise<void> {
+    const signal = this.abortController?.signal;
+    while (this.queue.length > 0) {
+      const files = [...new Set(this.queue)];
+      this.queue = [];
+
+      const startTime = Date.now();
+      try {
+        const stats = await this.indexer.update({ files, signal });
+        this.logger?.info(
+          { files: files.length, documents: stats.documentsIndexed },
+          'Re-indexed changed files'
+        );
+        this.emit<WatchReindexedEvent>('watch.reindexed', {
+          path: this.repositoryPath,
+          files,
+          documentsIndexed: stats.documentsIndexed,
+          duration: Date.now() - startTime,
+        });
+      } catch (error) {
+        if (!signal?.aborted) {
+          this.reportError(error);
+        }
+      }
+    }
+  }
+
+  private reportError(error: unknown): void {
+    const message = error instanceof Error ? error.message : String(error);
+    this.logger?.error({ error: message }, 'Watch re-index failed');
+    this.emit('index.error', { type: 'code', error: message, recoverable: true });
+  }
+
+  private emit<T>(eventName: string, payload: T): void {
+    if (this.eventBus) {
+      void this.eventBus.emit(eventName, payload, { waitForHandlers: false });
+    }
+  }
+}
@@ -11,8 +11,11 @@ import {
   GitIndexer,
   getStorageFilePaths,
   getStoragePath,
+  keywordSettingsFromEnv,
   LocalGitExtractor,
+  OutputTokenizer,
   RepositoryIndexer,
+  rerankerFromEnv,
   SearchService,
   StatsService,
   saveMetadata,
@@ -20,17 +23,54 @@ import {
 } from '@lytics/dev-agent-core';
 import type { SubagentCoordinator } from '@lytics/dev-agent-subagents';
 import {
+  CallPathAdapter,
+  ChangelogAdapter,
+  ConstraintsAdapter,
+  ContextAdapter,
+  DepsAdapter,
+  DiffAdapter,
+  ExplainAdapter,
+  FeedbackAdapter,
   GitHubAdapter,
+  GraphAdapter,
   HealthAdapter,
   HistoryAdapter,
+  ImplAdapter,
   InspectAdapter,
+  LookupAdapter,
   MapAdapter,
+  NeighborsAdapter,
+  OpenApiAdapter,
+  OutlineAdapter,
   PlanAdapter,
+  RecursionAdapter,
   RefsAdapter,
+  ReindexAdapter,
+  RoutesAdapter,
   SearchAdapter,
+  SimilarAdapter,
+  SqlAdapter,
   StatusAdapter,
+  TestAdapter,
+  UsageAdapter,
+  WhereisAdapter,
 } from '../src/adapters/built-in';
+import type { ToolResult } from '../src/adapters/types';
+import {
+  AUTO_REINDEX_ENV,
+  formatFreshness,
+  IndexFreshnessMonitor,
+  isAutoReindexEnabled,
+} from '../src/server/index-freshness';
 import { MCPServer } from '../src/server/mcp-server';
+import { ReindexJobs } from '../src/server/reindex-jobs';
+import { ConsoleLogger } from '../src/utils/logger';
+import { MAX_OUTPUT_TOKENS_ENV, parseMaxOutputTokens } from '../src/utils/output-budget';
+import {
+  parseResultCacheSize,
+  RESULT_CACHE_SIZE_ENV,
+  ResultCache,
+} from '../src/utils/result-cache';
 
 // Get config from environment with smart workspace detection
 // Priority: WORKSPACE_FOLDER_PATHS (Cursor dynamic) > REPOSITORY_PATH (explicit) > cwd (fallback)
@@ -70,7 +110,9 @@ async function _ensureIndexer(): Promise<RepositoryIndexer> {
       statePath: filePaths.indexerState,
     });
 
-    await indexer.initialize();
+    // The embedding model loads on first semantic search, so the server starts, and
+    // name-based tools keep working, when the model can't be loaded
+    await indexer.initialize({ skipEmbedder: true });
 
     // Update metadata
     await saveMetadata(storagePath, repositoryPath);
@@ -121,7 +163,9 @@ async function main() {
       statePath: filePaths.indexerState,
     });
 
-    await indexer.initialize();
+    // The embedding model loads on first semantic search, so the server starts, and
+    // name-based tools keep working, when the model can't be loaded
+    await indexer.initialize({ skipEmbedder: true });
 
     // Update metadata
     await saveMetadata(storagePath, repositoryPath);
@@ -140,19 +184,47 @@ async function main() {
     )) as SubagentCoordinator;
 
     // Create services
-    const searchService = new SearchService({ repositoryPath });
+    // DEV_AGENT_RERANK_MODEL turns on cross-encoder reranking of the top results,
+    // DEV_AGENT_KEYWORD_WEIGHT IDF keyword boosting
+    const searchService = new SearchService({
+      repositoryPath,
+      reranker: rerankerFromEnv(),
+      ...keywordSettingsFromEnv(),
+    });
     const githubService = new GitHubService({ repositoryPath }, async (config) => {
       const { GitHubIndexer } = await import('@lytics/dev-agent-subagents');
       return new GitHubIndexer(config);
     });
     const statsService = new StatsService({ repositoryPath });
 
+    // Repeated queries against the same index are answered from memory
+    // (DEV_AGENT_RESULT_CACHE_SIZE sets the size; 0 disables)
+    const resultCache = new ResultCache<ToolResult>(
+      parseResultCacheSize(process.env[RESULT_CACHE_SIZE_ENV])
+    );
+
     // Create and register adapters
     const searchAdapter = new SearchAdapter({
       searchService,
       repositoryPath,
       defaultFormat: 'compact',
       defaultLimit: 10,
+      resultCache,
+    });
+
+    // Opt-in: DEV_AGENT_AUTO_REINDEX=1 re-indexes stale files on startup
+    const freshnessLogger = new ConsoleLogger('[MCP Freshness]', logLevel);
+    const freshness = new IndexFreshnessMonitor({
+      indexer,
+      autoReindex: isAutoReindexEnabled(process.env[AUTO_REINDEX_ENV]),
+      logger: freshnessLogger,
+    });
+
+    // dev_reindex jobs run in the background; dev_status reports the latest
+    const reindexJobs = new ReindexJobs({
+      indexer,
+      freshness,
+      logger: new ConsoleLogger('[MCP Reindex]', logLevel),
     });
 
     const statusAdapter = new StatusAdapter({
@@ -161,6 +233,9 @@ async function main() {
       vectorStorePath: filePaths.vectors,
       githubService,
       defaultSection: 'summary',
+      freshness,
+      resultCache,
+      reindexJobs,
     });
 
     // Create git extractor and indexer (needed by plan and history adapters)
@@ -168,7 +243,7 @@ async function main() {
     const gitVectorStorage = new VectorStorage({
       storePath: `${filePaths.vectors}-git`,
     });
-    await gitVectorStorage.initialize();
+    await gitVectorStorage.initialize({ skipEmbedder: true });
 
     const gitIndexer = new GitIndexer({
       extractor: gitExtractor,
@@ -209,6 +284,41 @@ async function main() {
       defaultLimit: 20,
     });
 
+    const lookupAdapter = new LookupAdapter({
+      searchService,
+      defaultLimit: 10,
+    });
+
+    const similarAdapter = new SimilarAdapter({
+      searchService,
+      defaultLimit: 10,
+    });
+
+    const contextAdapter = new ContextAdapter({
+      searchService,
+      defaultTokenBudget: 4000,
+    });
+
+    const usageAdapter = new UsageAdapter({
+      searchService,
+      defaultLimit: 5,
+    });
+
+    const outlineAdapter = new OutlineAdapter({
+      searchService,
+      defaultLimit: 30,
+    });
+
+    const implAdapter = new ImplAdapter({
+      searchService,
+      defaultLimit: 50,
+    });
+
+    const testAdapter = new TestAdapter({
+      searchService,
+      defaultDepth: 5,
+    });
+
     const mapAdapter = new MapAdapter({
       repositoryIndexer: indexer,
       repositoryPath,
@@ -223,6 +333,78 @@ async function main() {
       defaultTokenBudget: 2000,
     });
 
+    const diffAdapter = new DiffAdapter({
+      repositoryPath,
+      gitExtractor,
+      defaultTokenBudget: 2000,
+    });
+
+    const changelogAdapter = new ChangelogAdapter({
+      repositoryPath,
+      gitExtractor,
+      defaultTokenBudget: 4000,
+    });
+
+    const whereisAdapter = new WhereisAdapter({
+      searchService,
+    });
+
+    const routesAdapter = new RoutesAdapter({
+      searchService,
+    });
+
+    const graphAdapter = new GraphAdapter({
+      searchService,
+    });
+
+    const recursionAdapter = new RecursionAdapter({
+      searchService,
+    });
+
+    const neighborsAdapter = new NeighborsAdapter({
+      searchService,
+    });
+
+    const callPathAdapter = new CallPathAdapter({
+      searchService,
+    });
+
+    const sqlAdapter = new SqlAdapter({
+      searchService,
+    });
+
+    const openApiAdapter = new OpenApiAdapter({
+      searchService,
+    });
+
+    const constraintsAdapter = new ConstraintsAdapter({
+      searchService,
+    });
+
+    const depsAdapter = new DepsAdapter({
+      searchService,
+    });
+
+    const feedbackAdapter = new FeedbackAdapter({
+      searchService,
+    });
+
+    const explainAdapter = new ExplainAdapter({
+      searchService,
+    });
+
+    const reindexAdapter = new ReindexAdapter({
+      jobs: reindexJobs,
+    });
+
+    // Responses over DEV_AGENT_MAX_TOKENS are trimmed, counted with a chat-model
+    // tokenizer that loads in the background (estimates until it has)
+    const maxTokens = parseMaxOutputTokens(process.env[MAX_OUTPUT_TOKENS_ENV]);
+    const outputTokenizer = new OutputTokenizer();
+    if (maxTokens !== undefined) {
+      void outputTokenizer.initialize();
+    }
+
     // Create MCP server with coordinator
     const server = new MCPServer({
       serverInfo: {
@@ -234,6 +416,8 @@ async function main() {
         logLevel,
       },
       transport: 'stdio',
+      maxTokens,
+      countTokens: (text) => outputTokenizer.countTokens(text),
       adapters: [
         searchAdapter,
         statusAdapter,
@@ -244,6 +428,28 @@ async function main() {
         refsAdapter,
         mapAdapter,
         historyAdapter,
+        diffAdapter,
+        lookupAdapter,
+        similarAdapter,
+        contextAdapter,
+        usageAdapter,
+        testAdapter,
+        outlineAdapter,
+        implAdapter,
+        changelogAdapter,
+        whereisAdapter,
+        routesAdapter,
+        graphAdapter,
+        recursionAdapter,
+        neighborsAdapter,
+        callPathAdapter,
+        sqlAdapter,
+        openApiAdapter,
+        constraintsAdapter,
+        depsAdapter,
+        feedbackAdapter,
+        explainAdapter,
+        reindexAdapter,
       ],
       coordinator,
     });
@@ -264,6 +470,20 @@ async function main() {
     // Start server
     await server.start();
 
+    // Check freshness in the background so a large repository doesn't delay startup
+    freshness
+      .check()
+      .then((report) => {
+        if (report.freshness) {
+          freshnessLogger.info(formatFreshness(report.freshness));
+        }
+      })
+      .catch((error) => {
+        freshnessLogger.warn('Index freshness check failed', {
+          error: error instanceof Error ? error.message : String(error),
+        });
+      });
+
     // Keep process alive (server runs until stdin closes or signal received)
   } catch (error) {
     console.error('Failed to start MCP server:', error);
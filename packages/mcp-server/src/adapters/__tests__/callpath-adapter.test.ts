@@ -0,0 +1,103 @@
+import type { CallPaths, SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { CallPathAdapter } from '../built-in/callpath-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('CallPathAdapter', () => {
+  const symbol = (name: string, file: string): SearchResult => ({
+    id: `${file}:${name}`,
+    score: 1,
+    metadata: { name, type: 'function', path: file, startLine: 9, endLine: 20 },
+  });
+  const handle = symbol('Handle', 'api/handler.go');
+  const checkout = symbol('Checkout', 'shop/checkout.go');
+  const save = symbol('Save', 'store/store.go');
+  const result: CallPaths = {
+    from: handle,
+    to: save,
+    paths: [
+      [
+        { caller: handle, callee: checkout, line: 12, snippet: 'shop.Checkout(ctx, cart)' },
+        { caller: checkout, callee: save, line: 30, snippet: 'store.Save(ctx, order)' },
+      ],
+    ],
+    shortest: 2,
+    maxDepth: 6,
+    truncated: false,
+    staleFiles: [],
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: CallPathAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getCallPaths: vi.fn().mockResolvedValue(result),
+    } as unknown as SearchService;
+
+    adapter = new CallPathAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_callpath tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_callpath');
+    expect(toolDefinition.inputSchema.required).toEqual(['from', 'to']);
+  });
+
+  it('should list the calls on each path', async () => {
+    const output = await adapter.execute({ from: 'Handle', to: 'Save' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getCallPaths).toHaveBeenCalledWith('Handle', 'Save', {
+      limit: 1,
+      maxDepth: 6,
+      includeTests: false,
+    });
+    const data = output.data as string;
+    expect(data).toContain('# Call path from api.Handle to store.Save');
+    expect(data).toContain('2. shop.Checkout → Save (shop/checkout.go:30)');
+    expect(data).toContain('`store.Save(ctx, order)`');
+  });
+
+  it('should say when no path exists', async () => {
+    vi.mocked(mockSearchService.getCallPaths).mockResolvedValue({
+      ...result,
+      paths: [],
+      shortest: null,
+    });
+
+    const output = await adapter.execute({ from: 'Handle', to: 'Save' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(output.data).toContain('No call path from api.Handle to store.Save');
+  });
+
+  it('should report an unknown symbol', async () => {
+    vi.mocked(mockSearchService.getCallPaths).mockResolvedValue({ ...result, to: null });
+
+    const output = await adapter.execute({ from: 'Handle', to: 'Missing' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('SYMBOL_NOT_FOUND');
+    expect(output.error?.message).toContain('"Missing"');
+  });
+
+  it('should reject a depth over the bound', async () => {
+    const output = await adapter.execute({ from: 'Handle', to: 'Save', maxDepth: 50 }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getCallPaths).not.toHaveBeenCalled();
+  });
+});
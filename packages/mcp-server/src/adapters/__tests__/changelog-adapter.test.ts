@@ -0,0 +1,116 @@
+import type { RevisionReader } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { ChangelogAdapter } from '../built-in/changelog-adapter';
+import type { ToolExecutionContext } from '../types';
+
+const BASE_FILES: Record<string, string> = {
+  'server/server.go':
+    'package server\n\n' +
+    'func Listen(addr string) error { return nil }\n\n' +
+    '// Dial connects to addr.\nfunc Dial(addr string) error { return nil }\n\n' +
+    'func Legacy() {}\n',
+};
+
+const HEAD_FILES: Record<string, string> = {
+  'server/server.go':
+    'package server\n\n' +
+    'func Listen(addr string, tls bool) error { return nil }\n\n' +
+    '// Dial connects to addr.\n//\n// Deprecated: Use DialContext instead.\n' +
+    'func Dial(addr string) error { return nil }\n\n' +
+    'func DialContext(ctx context.Context, addr string) error { return nil }\n\n' +
+    'func helper() {}\n',
+  'server/server_test.go': 'package server\n\nfunc TestListen(t *testing.T) {}\n',
+};
+
+describe('ChangelogAdapter', () => {
+  let mockExtractor: RevisionReader;
+  let adapter: ChangelogAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockExtractor = {
+      resolveRevision: vi.fn(async (revision: string) => revision),
+      getChangedFiles: vi.fn().mockResolvedValue(['server/server.go', 'server/server_test.go']),
+      getFileAtRevision: vi.fn(async (revision: string, file: string) => {
+        const files = revision === 'v1.0.0' ? BASE_FILES : HEAD_FILES;
+        return files[file] ?? null;
+      }),
+    };
+
+    adapter = new ChangelogAdapter({
+      repositoryPath: '/nonexistent',
+      gitExtractor: mockExtractor,
+    });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  describe('getToolDefinition', () => {
+    it('should return correct tool definition', () => {
+      const definition = adapter.getToolDefinition();
+
+      expect(definition.name).toBe('dev_changelog');
+      expect(definition.inputSchema.properties).toHaveProperty('base');
+      expect(definition.inputSchema.properties).toHaveProperty('head');
+      expect(definition.inputSchema.properties).toHaveProperty('path');
+      expect(definition.inputSchema.required).toEqual(['base']);
+    });
+  });
+
+  describe('execute', () => {
+    it('should produce grouped release notes', async () => {
+      const result = await adapter.execute({ base: 'v1.0.0', head: 'v1.1.0' }, mockContext);
+
+      expect(result.success).toBe(true);
+      const content = result.data as string;
+      expect(content).toContain('# API Changes: v1.0.0..v1.1.0');
+      expect(content).toContain('## server');
+      expect(content).toContain('**2 breaking change(s)**');
+      expect(content).toContain('- **BREAKING:** `Legacy` (function)');
+      expect(content).toContain('- **BREAKING:** `Listen` (function)');
+      expect(content).toContain('`DialContext` (function)');
+      expect(content).toContain('- `Dial`: Use DialContext instead.');
+    });
+
+    it('should list unexported symbols as internal changes and leave out tests', async () => {
+      const result = await adapter.execute({ base: 'v1.0.0', head: 'v1.1.0' }, mockContext);
+
+      expect(result.data).toContain('### Internal Changes\n\n- `helper` (function) added');
+      expect(result.data).not.toContain('**BREAKING:** `helper`');
+      expect(result.data).not.toContain('TestListen');
+    });
+
+    it('should report when the exported API is unchanged', async () => {
+      vi.mocked(mockExtractor.getChangedFiles).mockResolvedValue([]);
+
+      const result = await adapter.execute({ base: 'v1.0.0' }, mockContext);
+
+      expect(result.success).toBe(true);
+      expect(result.data).toContain('No exported API changes');
+    });
+
+    it('should require a base revision', async () => {
+      const result = await adapter.execute({}, mockContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should return an error for unknown revisions', async () => {
+      vi.mocked(mockExtractor.resolveRevision).mockRejectedValue(new Error('unknown revision'));
+
+      const result = await adapter.execute({ base: 'nope' }, mockContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('CHANGELOG_FAILED');
+    });
+  });
+});
@@ -0,0 +1,103 @@
+import type { ConstraintMap, SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { ConstraintsAdapter } from '../built-in/constraints-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('ConstraintsAdapter', () => {
+  const symbol = (name: string, path: string, startLine: number, signature?: string) =>
+    ({
+      id: `${path}:${name}:${startLine}`,
+      score: 1,
+      metadata: { name, type: 'function', path, language: 'go', startLine, signature },
+    }) as SearchResult;
+  const constraintMap: ConstraintMap = {
+    constraints: [
+      {
+        name: 'Ordered',
+        kind: 'constraint',
+        definition: symbol('Ordered', 'pkg/num/ordered.go', 5),
+        typeSet: { terms: ['Integer', '~string'], comparable: false },
+        underlying: ['~int', '~int64', '~string'],
+        users: [symbol('Max', 'pkg/num/max.go', 3, 'func Max[T Ordered](a, b T) T')],
+      },
+    ],
+    total: 1,
+    omitted: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: ConstraintsAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getConstraintUsages: vi.fn().mockResolvedValue(constraintMap),
+    } as unknown as SearchService;
+
+    adapter = new ConstraintsAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_constraints tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_constraints');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('name');
+  });
+
+  it('should list a constraint with its type set and users', async () => {
+    const output = await adapter.execute({ name: 'Ordered' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getConstraintUsages).toHaveBeenCalledWith({
+      name: 'Ordered',
+      includeTests: false,
+      limit: 100,
+    });
+
+    const data = output.data as string;
+    expect(data).toContain('## Ordered: constraint interface (pkg/num/ordered.go:5)');
+    expect(data).toContain('**Underlying types:** ~int | ~int64 | ~string');
+    expect(data).toContain('- Max (pkg/num/max.go:3): `func Max[T Ordered](a, b T) T`');
+  });
+
+  it('should explain an empty result', async () => {
+    vi.mocked(mockSearchService.getConstraintUsages).mockResolvedValue({
+      constraints: [],
+      total: 0,
+      omitted: 0,
+    });
+
+    const unfiltered = await adapter.execute({}, mockContext);
+    expect(unfiltered.data).toContain('No generic constraints found');
+
+    const named = await adapter.execute({ name: 'Number' }, mockContext);
+    expect(named.data).toContain('No constraint named Number');
+  });
+
+  it('should reject unknown arguments', async () => {
+    const output = await adapter.execute({ constraint: 'Ordered' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getConstraintUsages).not.toHaveBeenCalled();
+  });
+
+  it('should handle listing failures', async () => {
+    vi.mocked(mockSearchService.getConstraintUsages).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({}, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('CONSTRAINTS_FAILED');
+  });
+});
@@ -0,0 +1,139 @@
+import type { SearchService, SymbolContext } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { ContextAdapter } from '../built-in/context-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('ContextAdapter', () => {
+  const context: SymbolContext = {
+    target: {
+      symbol: {
+        id: 'client/retry.go:retryRequest:10',
+        score: 1,
+        metadata: {
+          name: 'retryRequest',
+          type: 'function',
+          path: 'client/retry.go',
+          language: 'go',
+          startLine: 10,
+          endLine: 14,
+        },
+      },
+      relation: 'target',
+      hop: 0,
+      source: 'func retryRequest(req *Request) error {\n\treturn backoff(req)\n}',
+      signatureOnly: false,
+    },
+    entries: [
+      {
+        symbol: {
+          id: 'client/backoff.go:backoff:3',
+          score: 1,
+          metadata: {
+            name: 'backoff',
+            type: 'function',
+            path: 'client/backoff.go',
+            language: 'go',
+            startLine: 3,
+            endLine: 20,
+          },
+        },
+        relation: 'callee',
+        hop: 1,
+        via: 'retryRequest',
+        source: 'func backoff(req *Request) error {',
+        signatureOnly: true,
+      },
+    ],
+    omitted: 2,
+    tokens: 30,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: ContextAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getSymbolContext: vi.fn().mockResolvedValue(context),
+    } as unknown as SearchService;
+
+    adapter = new ContextAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_context tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_context');
+    expect(definition.inputSchema.required).toEqual(['symbol']);
+    expect(definition.inputSchema.properties).toHaveProperty('depth');
+    expect(definition.inputSchema.properties).toHaveProperty('tokenBudget');
+  });
+
+  it('should format the assembled context', async () => {
+    const result = await adapter.execute({ symbol: 'retryRequest' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.getSymbolContext).toHaveBeenCalledWith('retryRequest', {
+      path: undefined,
+      depth: 1,
+      maxTokens: 4000,
+      includeTypes: true,
+    });
+
+    const data = result.data as string;
+    expect(data).toContain('# Context for retryRequest');
+    expect(data).toContain('## Hop 1: Callees');
+    expect(data).toContain('### backoff (function) - client/backoff.go:3-20 via `retryRequest`');
+    expect(data).toContain('(signature only)');
+    expect(data).toContain('2 more related symbol(s) omitted');
+  });
+
+  it('should pass depth, path, and budget through', async () => {
+    await adapter.execute(
+      { symbol: 'retryRequest', path: 'client/', depth: 2, tokenBudget: 1000, includeTypes: false },
+      mockContext
+    );
+
+    expect(mockSearchService.getSymbolContext).toHaveBeenCalledWith('retryRequest', {
+      path: 'client/',
+      depth: 2,
+      maxTokens: 1000,
+      includeTypes: false,
+    });
+  });
+
+  it('should report unknown symbols', async () => {
+    vi.mocked(mockSearchService.getSymbolContext).mockResolvedValue(null);
+
+    const result = await adapter.execute({ symbol: 'missing' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should reject depths beyond the limit', async () => {
+    const result = await adapter.execute({ symbol: 'retryRequest', depth: 5 }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('INVALID_PARAMS');
+  });
+
+  it('should handle search failures', async () => {
+    vi.mocked(mockSearchService.getSymbolContext).mockRejectedValue(new Error('index missing'));
+
+    const result = await adapter.execute({ symbol: 'retryRequest' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('CONTEXT_FAILED');
+  });
+});
@@ -0,0 +1,110 @@
+import type { DependencyMap, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { DepsAdapter } from '../built-in/deps-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('DepsAdapter', () => {
+  const result: DependencyMap = {
+    dependencies: [
+      {
+        dependency: {
+          path: 'golang.org/x/net',
+          version: 'v0.23.0',
+          indirect: false,
+          replacedBy: { path: 'golang.org/x/net', version: 'v0.23.0' },
+          requiredVersion: 'v0.17.0',
+        },
+        requiredBy: 'github.com/acme/api',
+        moduleDir: '.',
+        importers: [
+          { dir: 'client', files: ['client/dial.go'] },
+          { dir: 'server', files: ['server/serve.go', 'server/tls.go'] },
+        ],
+      },
+    ],
+    total: 1,
+    omitted: 0,
+    modules: 1,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: DepsAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getDependencies: vi.fn().mockResolvedValue(result),
+    } as unknown as SearchService;
+
+    adapter = new DepsAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_deps tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_deps');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+  });
+
+  it('should list dependency versions and their importers', async () => {
+    const output = await adapter.execute({ module: 'x/net' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getDependencies).toHaveBeenCalledWith({
+      module: 'x/net',
+      includeIndirect: true,
+      includeTests: false,
+      limit: 200,
+    });
+    const data = output.data as string;
+    expect(data).toContain(
+      '## golang.org/x/net v0.23.0 (direct; replaced by golang.org/x/net v0.23.0; requires v0.17.0)'
+    );
+    expect(data).toContain('- server: serve.go, tls.go');
+  });
+
+  it('should say when no dependency matches', async () => {
+    vi.mocked(mockSearchService.getDependencies).mockResolvedValue({
+      ...result,
+      dependencies: [],
+      total: 0,
+    });
+
+    const output = await adapter.execute({ module: 'jwt' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(output.data).toContain(
+      'No Go module in the repository requires a module matching "jwt"'
+    );
+  });
+
+  it('should say when the repository has no go.mod', async () => {
+    vi.mocked(mockSearchService.getDependencies).mockResolvedValue({
+      dependencies: [],
+      total: 0,
+      omitted: 0,
+      modules: 0,
+    });
+
+    const output = await adapter.execute({}, mockContext);
+
+    expect(output.data).toBe('No go.mod found in the repository.\n');
+  });
+
+  it('should reject a limit below the bound', async () => {
+    const output = await adapter.execute({ limit: 0 }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getDependencies).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,134 @@
+import type { RevisionReader } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { DiffAdapter } from '../built-in/diff-adapter';
+import type { ToolExecutionContext } from '../types';
+
+const BASE_FILES: Record<string, string> = {
+  'src/users.ts':
+    'export function fetchUser(id: string): string {\n  return id;\n}\n\n' +
+    'export function saveUser(id: string): void {}\n',
+  'src/legacy.ts': 'export function legacy(): void {}\n',
+};
+
+const HEAD_FILES: Record<string, string> = {
+  'src/users.ts':
+    'export function getUser(id: string): string {\n  return id;\n}\n\n' +
+    'export function saveUser(id: string, force: boolean): void {}\n',
+  'src/created.ts': 'export function created(): number {\n  return 1;\n}\n',
+};
+
+describe('DiffAdapter', () => {
+  let mockExtractor: RevisionReader;
+  let adapter: DiffAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockExtractor = {
+      resolveRevision: vi.fn(async (revision: string) => revision),
+      getChangedFiles: vi
+        .fn()
+        .mockResolvedValue(['src/created.ts', 'src/legacy.ts', 'src/users.ts']),
+      getFileAtRevision: vi.fn(async (revision: string, file: string) => {
+        const files = revision === 'main' ? BASE_FILES : HEAD_FILES;
+        return files[file] ?? null;
+      }),
+    };
+
+    adapter = new DiffAdapter({
+      repositoryPath: '/nonexistent',
+      gitExtractor: mockExtractor,
+    });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  describe('getToolDefinition', () => {
+    it('should return correct tool definition', () => {
+      const definition = adapter.getToolDefinition();
+
+      expect(definition.name).toBe('dev_diff');
+      expect(definition.inputSchema.properties).toHaveProperty('base');
+      expect(definition.inputSchema.properties).toHaveProperty('head');
+      expect(definition.inputSchema.properties).toHaveProperty('path');
+      expect(definition.inputSchema.required).toEqual(['base']);
+    });
+  });
+
+  describe('execute', () => {
+    it('should report symbol-level changes', async () => {
+      const result = await adapter.execute({ base: 'main' }, mockContext);
+
+      expect(result.success).toBe(true);
+      expect(mockExtractor.resolveRevision).toHaveBeenCalledWith('main');
+      expect(mockExtractor.resolveRevision).toHaveBeenCalledWith('HEAD');
+
+      const content = result.data as string;
+      expect(content).toContain('# Symbol Diff: main..HEAD');
+      expect(content).toContain('## Added');
+      expect(content).toContain('`created`');
+      expect(content).toContain('## Removed');
+      expect(content).toContain('`legacy`');
+      expect(content).toContain('## Renamed');
+      expect(content).toContain('`fetchUser` → `getUser`');
+      expect(content).toContain('## Signature Changes');
+      expect(content).toContain('`saveUser`');
+    });
+
+    it('should include signatures in verbose format', async () => {
+      const result = await adapter.execute({ base: 'main', format: 'verbose' }, mockContext);
+
+      expect(result.success).toBe(true);
+      expect(result.data).toContain('created(): number');
+    });
+
+    it('should report struct tag changes', async () => {
+      vi.mocked(mockExtractor.getChangedFiles).mockResolvedValue(['model/user.go']);
+      vi.mocked(mockExtractor.getFileAtRevision).mockImplementation(async (revision: string) => {
+        const name = revision === 'main' ? 'email' : 'email_address';
+        return `package model\n\ntype User struct {\n\tEmail string \`json:"${name}"\`\n}\n`;
+      });
+
+      const result = await adapter.execute({ base: 'main' }, mockContext);
+
+      expect(result.data).toContain('1 struct tag changes');
+      expect(result.data).toContain(
+        '## Struct Tag Changes\n- `User.Email` - model/user.go:3: `json:"email"` → ' +
+          '`json:"email_address"`'
+      );
+    });
+
+    it('should report when there are no changes', async () => {
+      vi.mocked(mockExtractor.getChangedFiles).mockResolvedValue([]);
+
+      const result = await adapter.execute({ base: 'main' }, mockContext);
+
+      expect(result.success).toBe(true);
+      expect(result.data).toContain('No symbol-level changes');
+    });
+
+    it('should require a base revision', async () => {
+      const result = await adapter.execute({}, mockContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should return an error for unknown revisions', async () => {
+      vi.mocked(mockExtractor.resolveRevision).mockRejectedValue(new Error('unknown revision'));
+
+      const result = await adapter.execute({ base: 'nope' }, mockContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('DIFF_FAILED');
+      expect(result.error?.message).toContain('unknown revision');
+    });
+  });
+});
@@ -0,0 +1,135 @@
+import type { SearchService, SymbolExplanation } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { ExplainAdapter } from '../built-in/explain-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('ExplainAdapter', () => {
+  const explanation: SymbolExplanation = {
+    target: {
+      id: 'backoff/backoff.go:NewExpBackoff:10',
+      score: 1,
+      metadata: {
+        name: 'NewExpBackoff',
+        type: 'function',
+        path: 'backoff/backoff.go',
+        language: 'go',
+        startLine: 10,
+        endLine: 12,
+        signature: 'func NewExpBackoff(base time.Duration) *ExpBackoff',
+        docstring: 'NewExpBackoff returns a backoff doubling from base.',
+      },
+    },
+    package: 'backoff',
+    documentedExamples: [],
+    examples: [
+      {
+        caller: {
+          id: 'client/client.go:NewClient:20',
+          score: 1,
+          metadata: {
+            name: 'NewClient',
+            type: 'function',
+            path: 'client/client.go',
+            language: 'go',
+            startLine: 20,
+            endLine: 23,
+          },
+        },
+        line: 21,
+        call: 'backoff.NewExpBackoff(cfg.Base)',
+        shape: '(selector)',
+        source: '\tb := backoff.NewExpBackoff(cfg.Base)',
+        isTest: false,
+      },
+    ],
+    callees: [],
+    omittedCallees: 0,
+    totalCalls: 4,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: ExplainAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getSymbolExplanation: vi.fn().mockResolvedValue(explanation),
+    } as unknown as SearchService;
+
+    adapter = new ExplainAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_explain tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_explain');
+    expect(definition.inputSchema.required).toEqual(['symbol']);
+    expect(definition.inputSchema.properties).toHaveProperty('examples');
+    expect(definition.inputSchema.properties).toHaveProperty('maxCallees');
+  });
+
+  it('should format the explanation', async () => {
+    const result = await adapter.execute({ symbol: 'NewExpBackoff' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.getSymbolExplanation).toHaveBeenCalledWith('NewExpBackoff', {
+      path: undefined,
+      examples: 2,
+      maxCallees: 10,
+    });
+
+    const data = result.data as string;
+    expect(data).toContain('# NewExpBackoff (function) - backoff/backoff.go:10');
+    expect(data).toContain('NewExpBackoff returns a backoff doubling from base.');
+    expect(data).toContain('## Used in NewClient - client/client.go:21');
+    expect(data).toContain('Called from 4 place(s) in the index.');
+  });
+
+  it('should pass path and limits through', async () => {
+    await adapter.execute(
+      { symbol: 'NewExpBackoff', path: 'backoff/', examples: 1, maxCallees: 3 },
+      mockContext
+    );
+
+    expect(mockSearchService.getSymbolExplanation).toHaveBeenCalledWith('NewExpBackoff', {
+      path: 'backoff/',
+      examples: 1,
+      maxCallees: 3,
+    });
+  });
+
+  it('should reject too many examples', async () => {
+    const result = await adapter.execute({ symbol: 'NewExpBackoff', examples: 10 }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('INVALID_PARAMS');
+  });
+
+  it('should report unknown symbols', async () => {
+    vi.mocked(mockSearchService.getSymbolExplanation).mockResolvedValue(null);
+
+    const result = await adapter.execute({ symbol: 'missing' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should handle failures', async () => {
+    vi.mocked(mockSearchService.getSymbolExplanation).mockRejectedValue(new Error('no index'));
+
+    const result = await adapter.execute({ symbol: 'NewExpBackoff' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('EXPLAIN_FAILED');
+  });
+});
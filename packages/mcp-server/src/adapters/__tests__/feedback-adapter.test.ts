@@ -0,0 +1,100 @@
+import type { SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { FeedbackAdapter } from '../built-in/feedback-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('FeedbackAdapter', () => {
+  const createUser: SearchResult = {
+    id: 'internal/api/users.go:method:Server.CreateUser',
+    score: 1,
+    metadata: { name: 'Server.CreateUser', path: 'internal/api/users.go', startLine: 40 },
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: FeedbackAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      recordFeedback: vi.fn().mockResolvedValue(createUser),
+      getFeedback: vi.fn().mockReturnValue({ relevant: [createUser.id], nonRelevant: ['x'] }),
+      clearFeedback: vi.fn().mockReturnValue(true),
+    } as unknown as SearchService;
+
+    adapter = new FeedbackAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_feedback tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_feedback');
+    expect(toolDefinition.inputSchema.required).toEqual(['session']);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('relevant');
+  });
+
+  it('should mark a result relevant for the session', async () => {
+    const output = await adapter.execute(
+      { session: 'explore-auth', symbol: 'Server.CreateUser' },
+      mockContext
+    );
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.recordFeedback).toHaveBeenCalledWith(
+      'explore-auth',
+      'Server.CreateUser',
+      true
+    );
+    const data = output.data as string;
+    expect(data).toContain(
+      'Marked **Server.CreateUser** (internal/api/users.go:40) relevant for session "explore-auth"'
+    );
+    expect(data).toContain('**Session feedback:** 1 relevant, 1 not relevant');
+  });
+
+  it('should clear a session', async () => {
+    const output = await adapter.execute({ session: 'explore-auth', clear: true }, mockContext);
+
+    expect(mockSearchService.clearFeedback).toHaveBeenCalledWith('explore-auth');
+    expect(output.data).toContain('Cleared the feedback of session "explore-auth"');
+  });
+
+  it('should require a symbol or clear, not both', async () => {
+    const neither = await adapter.execute({ session: 'explore-auth' }, mockContext);
+    const both = await adapter.execute(
+      { session: 'explore-auth', symbol: 'Server.CreateUser', clear: true },
+      mockContext
+    );
+
+    expect(neither.success).toBe(false);
+    expect(both.success).toBe(false);
+    expect(mockSearchService.recordFeedback).not.toHaveBeenCalled();
+  });
+
+  it('should report a symbol that is not indexed', async () => {
+    vi.mocked(mockSearchService.recordFeedback).mockResolvedValue(null);
+
+    const output = await adapter.execute({ session: 'explore-auth', symbol: 'Nope' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should handle failures', async () => {
+    vi.mocked(mockSearchService.recordFeedback).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({ session: 'explore-auth', symbol: 'X' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('FEEDBACK_FAILED');
+  });
+});
@@ -0,0 +1,123 @@
+import type { CallGraph, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { GraphAdapter } from '../built-in/graph-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('GraphAdapter', () => {
+  const callGraph: CallGraph = {
+    scope: 'users.Server.CreateUser',
+    depth: 1,
+    nodes: [
+      {
+        id: 'users/server.go:method:users.Server.CreateUser',
+        label: 'users.Server.CreateUser',
+        kind: 'method',
+        file: 'users/server.go',
+        line: 20,
+        external: false,
+      },
+      {
+        id: 'users/validate.go:function:users.validateUser',
+        label: 'users.validateUser',
+        kind: 'function',
+        file: 'users/validate.go',
+        line: 4,
+        external: false,
+      },
+    ],
+    edges: [
+      {
+        from: 'users/server.go:method:users.Server.CreateUser',
+        to: 'users/validate.go:function:users.validateUser',
+        kind: 'call',
+      },
+    ],
+    omitted: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: GraphAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getCallGraph: vi.fn().mockResolvedValue(callGraph),
+    } as unknown as SearchService;
+
+    adapter = new GraphAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_graph tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_graph');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('symbol');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('package');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('format');
+  });
+
+  it('should export a symbol neighborhood as DOT by default', async () => {
+    const output = await adapter.execute({ symbol: 'Server.CreateUser' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getCallGraph).toHaveBeenCalledWith({
+      symbol: 'Server.CreateUser',
+      depth: 1,
+      direction: 'both',
+      includeExternal: false,
+      includeImplements: true,
+      includeTests: false,
+      limit: 200,
+    });
+
+    const data = output.data as string;
+    expect(data).toContain('digraph "users.Server.CreateUser" {');
+    expect(data).toContain(
+      '"users/server.go:method:users.Server.CreateUser" -> ' +
+        '"users/validate.go:function:users.validateUser";'
+    );
+  });
+
+  it('should export JSON on request', async () => {
+    const output = await adapter.execute({ package: 'users', format: 'json' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(JSON.parse(output.data as string)).toEqual(callGraph);
+  });
+
+  it('should require a symbol or package', async () => {
+    const output = await adapter.execute({ depth: 2 }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getCallGraph).not.toHaveBeenCalled();
+  });
+
+  it('should report an unknown symbol or package', async () => {
+    vi.mocked(mockSearchService.getCallGraph).mockResolvedValue(null);
+
+    const symbol = await adapter.execute({ symbol: 'Missing' }, mockContext);
+    expect(symbol.error?.code).toBe('SYMBOL_NOT_FOUND');
+
+    const pkg = await adapter.execute({ package: 'missing' }, mockContext);
+    expect(pkg.error?.code).toBe('PACKAGE_NOT_FOUND');
+  });
+
+  it('should handle export failures', async () => {
+    vi.mocked(mockSearchService.getCallGraph).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({ symbol: 'Server.CreateUser' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('GRAPH_FAILED');
+  });
+});
@@ -0,0 +1,135 @@
+import type { InterfaceImplementations, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { ImplAdapter } from '../built-in/impl-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('ImplAdapter', () => {
+  const method = {
+    id: 'internal/files/file.go:File.Read:8',
+    score: 1,
+    metadata: {
+      name: 'File.Read',
+      type: 'method',
+      path: 'internal/files/file.go',
+      language: 'go',
+      startLine: 8,
+      signature: 'func (f *File) Read(p []byte) (int, error)',
+    },
+  };
+  const result: InterfaceImplementations = {
+    interface: 'io.Reader',
+    alternatives: [],
+    methods: ['Read'],
+    methodsKnown: true,
+    implementations: [
+      {
+        type: 'File',
+        package: 'internal/files',
+        symbol: {
+          id: 'internal/files/file.go:File:3',
+          score: 1,
+          metadata: {
+            name: 'File',
+            type: 'class',
+            path: 'internal/files/file.go',
+            language: 'go',
+            startLine: 3,
+          },
+        },
+        pointer: true,
+        assertion: {
+          id: 'internal/files/file.go:_:20',
+          score: 1,
+          metadata: {
+            name: '_',
+            type: 'variable',
+            path: 'internal/files/file.go',
+            language: 'go',
+            startLine: 20,
+            asserts: { interface: 'io.Reader', type: 'File', pointer: true },
+          },
+        },
+        structural: true,
+        methods: [
+          { name: 'Read', symbol: method, receiver: 'pointer', pointerOnly: true, match: 'name' },
+        ],
+        confidence: 'high',
+        isTest: false,
+      },
+    ],
+    partial: [],
+    omitted: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: ImplAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getImplementations: vi.fn().mockResolvedValue(result),
+    } as unknown as SearchService;
+
+    adapter = new ImplAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_impl tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_impl');
+    expect(definition.inputSchema.required).toEqual(['interface']);
+    expect(definition.inputSchema.properties).toHaveProperty('includeTests');
+    expect(definition.inputSchema.properties).toHaveProperty('limit');
+  });
+
+  it('should list implementations with their satisfying methods', async () => {
+    const output = await adapter.execute({ interface: 'io.Reader' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getImplementations).toHaveBeenCalledWith('io.Reader', {
+      includeTests: false,
+      limit: 50,
+    });
+
+    const data = output.data as string;
+    expect(data).toContain('# Implementations of io.Reader');
+    expect(data).toContain('## *File (internal/files) - internal/files/file.go:3');
+    expect(data).toContain('- asserted as io.Reader at internal/files/file.go:20');
+    expect(data).toContain('- Read: File.Read (internal/files/file.go:8)');
+  });
+
+  it('should report unknown interfaces', async () => {
+    vi.mocked(mockSearchService.getImplementations).mockResolvedValue(null);
+
+    const output = await adapter.execute({ interface: 'Missing' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('INTERFACE_NOT_FOUND');
+  });
+
+  it('should reject invalid arguments', async () => {
+    const output = await adapter.execute({ interface: '' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getImplementations).not.toHaveBeenCalled();
+  });
+
+  it('should handle lookup failures', async () => {
+    vi.mocked(mockSearchService.getImplementations).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({ interface: 'io.Reader' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('IMPL_FAILED');
+  });
+});
@@ -5,7 +5,7 @@
  */
 
 import * as path from 'node:path';
-import type { SearchResult, SearchService } from '@lytics/dev-agent-core';
+import type { SearchResult, SearchService, SymbolInspection } from '@lytics/dev-agent-core';
 import { beforeEach, describe, expect, it, vi } from 'vitest';
 import { InspectAdapter } from '../built-in/inspect-adapter.js';
 import type { ToolExecutionContext } from '../types.js';
@@ -25,6 +25,7 @@ describe('InspectAdapter', () => {
     mockSearchService = {
       search: vi.fn(),
       findSimilar: vi.fn(),
+      getSymbolInspection: vi.fn(),
     } as unknown as SearchService;
 
     // Create adapter with fixtures directory
@@ -393,6 +394,100 @@ describe('InspectAdapter', () => {
     });
   });
 
+  describe('Symbol Inspection', () => {
+    const target: SearchResult = {
+      id: 'cache/get.go:Get',
+      score: 1,
+      metadata: {
+        name: 'Get',
+        type: 'function',
+        path: 'cache/get.go',
+        language: 'go',
+        startLine: 4,
+        endLine: 9,
+        signature: 'func Get(key string) []byte',
+        docstring: 'Get returns a cached value.',
+      },
+    };
+    const caller: SearchResult = {
+      id: 'cache/load.go:Load',
+      score: 1,
+      metadata: { name: 'Load', type: 'function', path: 'cache/load.go', startLine: 3 },
+    };
+    const inspection: SymbolInspection = {
+      target,
+      package: 'cache',
+      sections: ['definition', 'callers', 'git'],
+      source: 'func Get(key string) []byte {\n\treturn nil\n}',
+      callers: [caller],
+      callees: [],
+      internalViolations: [],
+      implements: [],
+      implementedBy: [],
+      git: { lastModified: '2026-03-02T10:00:00Z', lastAuthor: 'Sam' },
+      omitted: 0,
+      tokens: 20,
+    };
+
+    it('should inspect a symbol in one call', async () => {
+      vi.mocked(mockSearchService.getSymbolInspection).mockResolvedValue(inspection);
+
+      const result = await adapter.execute(
+        { query: 'Get', target: 'symbol', sections: ['definition', 'callers', 'git'] },
+        mockContext
+      );
+
+      expect(result.success).toBe(true);
+      expect(mockSearchService.getSymbolInspection).toHaveBeenCalledWith('Get', {
+        sections: ['definition', 'callers', 'git'],
+        path: undefined,
+        maxTokens: 2000,
+        includeTests: false,
+      });
+      expect(mockSearchService.findSimilar).not.toHaveBeenCalled();
+      expect(result.data).toContain('# Get (function) - cache/get.go:4-9');
+      expect(result.data).toContain('- Load - cache/load.go:3');
+      expect(result.data).toContain('2026-03-02T10:00:00Z by Sam');
+    });
+
+    it('should return structured JSON with only the requested sections', async () => {
+      vi.mocked(mockSearchService.getSymbolInspection).mockResolvedValue(inspection);
+
+      const result = await adapter.execute(
+        { query: 'Get', target: 'symbol', format: 'json' },
+        mockContext
+      );
+
+      expect(result.success).toBe(true);
+      expect(result.data).toMatchObject({
+        symbol: { name: 'Get', path: 'cache/get.go', line: 4, endLine: 9, package: 'cache' },
+        definition: { signature: 'func Get(key string) []byte' },
+        callers: [{ name: 'Load', path: 'cache/load.go', line: 3 }],
+        git: { lastModified: '2026-03-02T10:00:00Z', lastAuthor: 'Sam' },
+      });
+      expect(result.data).not.toHaveProperty('callees');
+    });
+
+    it('should reject JSON output for file inspection', async () => {
+      const result = await adapter.execute(
+        { query: 'modern-typescript.ts', format: 'json' },
+        mockContext
+      );
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should report unknown symbols', async () => {
+      vi.mocked(mockSearchService.getSymbolInspection).mockResolvedValue(null);
+
+      const result = await adapter.execute({ query: 'Missing', target: 'symbol' }, mockContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+    });
+  });
+
   describe('Output Schema Validation', () => {
     it('should validate output schema', async () => {
       const mockResults: SearchResult[] = [
@@ -0,0 +1,112 @@
+import type { SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { LookupStructuredOutputSchema, OUTPUT_SCHEMA_VERSION } from '../../schemas/index.js';
+import { LookupAdapter } from '../built-in/lookup-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('LookupAdapter', () => {
+  const matches: SearchResult[] = [
+    {
+      id: 'retry/backoff.go:ExpBackoff:12',
+      score: 0.83,
+      metadata: {
+        name: 'ExpBackoff',
+        type: 'class',
+        path: 'retry/backoff.go',
+        startLine: 12,
+        signature: 'type ExpBackoff struct',
+      },
+    },
+  ];
+
+  let mockSearchService: SearchService;
+  let adapter: LookupAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      lookupSymbol: vi.fn().mockResolvedValue(matches),
+    } as unknown as SearchService;
+
+    adapter = new LookupAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_lookup tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_lookup');
+    expect(definition.inputSchema.required).toEqual(['query']);
+    expect(definition.inputSchema.properties).toHaveProperty('kind');
+    expect(definition.inputSchema.properties).toHaveProperty('path');
+  });
+
+  it('should return ranked matches with locations and signatures', async () => {
+    const result = await adapter.execute({ query: 'backof' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.lookupSymbol).toHaveBeenCalledWith('backof', {
+      kind: undefined,
+      path: undefined,
+      limit: 10,
+      minScore: 0.3,
+    });
+    expect(result.data).toContain('**ExpBackoff** (class) - `retry/backoff.go:12` [83%]');
+    expect(result.data).toContain('`type ExpBackoff struct`');
+  });
+
+  it('should pass kind and path scopes', async () => {
+    await adapter.execute({ query: 'start', kind: 'method', path: 'server/' }, mockContext);
+
+    expect(mockSearchService.lookupSymbol).toHaveBeenCalledWith(
+      'start',
+      expect.objectContaining({ kind: 'method', path: 'server/' })
+    );
+  });
+
+  it('should report when nothing matches', async () => {
+    vi.mocked(mockSearchService.lookupSymbol).mockResolvedValue([]);
+
+    const result = await adapter.execute({ query: 'zzz' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(result.data).toContain('No matching symbols found');
+  });
+
+  it('should return structured output matching the schema in json format', async () => {
+    const result = await adapter.execute({ query: 'backof', format: 'json' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(LookupStructuredOutputSchema.parse(result.data)).toEqual({
+      schemaVersion: OUTPUT_SCHEMA_VERSION,
+      query: 'backof',
+      matches: [
+        {
+          name: 'ExpBackoff',
+          type: 'class',
+          path: 'retry/backoff.go',
+          startLine: 12,
+          signature: 'type ExpBackoff struct',
+          score: 0.83,
+        },
+      ],
+    });
+    expect(adapter.getToolDefinition().outputSchema?.properties).toHaveProperty('matches');
+  });
+
+  it('should reject invalid kinds', async () => {
+    const result = await adapter.execute({ query: 'x', kind: 'module' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('INVALID_PARAMS');
+  });
+});
@@ -0,0 +1,96 @@
+import type { SearchResult, SearchService, SymbolNeighbors } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { NeighborsAdapter } from '../built-in/neighbors-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('NeighborsAdapter', () => {
+  const symbol = (name: string, type: string, startLine: number): SearchResult => ({
+    id: `internal/store/cache.go:${name}`,
+    score: 1,
+    metadata: { name, type, path: 'internal/store/cache.go', startLine, endLine: startLine + 5 },
+  });
+  const result: SymbolNeighbors = {
+    file: 'internal/store/cache.go',
+    package: 'internal/store',
+    packageDoc: 'Package store keeps entries in memory.',
+    target: symbol('Cache.Get', 'method', 20),
+    before: [symbol('NewCache', 'function', 12)],
+    after: [symbol('Cache.Set', 'method', 30)],
+    group: 'Cache',
+    groupMembers: [symbol('Cache', 'struct', 5), symbol('Cache.Set', 'method', 30)],
+    omittedGroupMembers: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: NeighborsAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getSymbolNeighbors: vi.fn().mockResolvedValue(result),
+    } as unknown as SearchService;
+
+    adapter = new NeighborsAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_neighbors tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_neighbors');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('symbol');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('line');
+  });
+
+  it('should list the neighbors of a symbol', async () => {
+    const output = await adapter.execute({ symbol: 'Cache.Get' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getSymbolNeighbors).toHaveBeenCalledWith({
+      symbol: 'Cache.Get',
+      count: 3,
+    });
+    const data = output.data as string;
+    expect(data).toContain('# Neighbors of internal/store.Cache.Get (internal/store/cache.go:20)');
+    expect(data).toContain('- **NewCache** (function, line 12)');
+    expect(data).toContain('## Cache (2 other members)');
+  });
+
+  it('should look up a file line', async () => {
+    const args = { file: 'internal/store/cache.go', line: 22 };
+    const output = await adapter.execute(args, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getSymbolNeighbors).toHaveBeenCalledWith({
+      file: 'internal/store/cache.go',
+      line: 22,
+      count: 3,
+    });
+  });
+
+  it('should require a symbol or a file and line', async () => {
+    const output = await adapter.execute({ file: 'internal/store/cache.go' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getSymbolNeighbors).not.toHaveBeenCalled();
+  });
+
+  it('should report an unknown symbol or file', async () => {
+    vi.mocked(mockSearchService.getSymbolNeighbors).mockResolvedValue(null);
+
+    const bySymbol = await adapter.execute({ symbol: 'Missing' }, mockContext);
+    const byFile = await adapter.execute({ file: 'missing.go', line: 1 }, mockContext);
+
+    expect(bySymbol.error?.code).toBe('SYMBOL_NOT_FOUND');
+    expect(byFile.error?.code).toBe('FILE_NOT_FOUND');
+  });
+});
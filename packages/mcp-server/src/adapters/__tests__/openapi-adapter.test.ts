@@ -0,0 +1,142 @@
+import type { OpenApiMap, SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { OpenApiAdapter } from '../built-in/openapi-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('OpenApiAdapter', () => {
+  const symbol = (name: string, path: string, startLine: number): SearchResult => ({
+    id: `${path}:${name}:${startLine}`,
+    score: 1,
+    metadata: { name, type: 'method', path, language: 'go', startLine },
+  });
+  const spec: SearchResult = {
+    id: 'api/openapi.yaml:POST /users:17',
+    score: 1,
+    metadata: {
+      name: 'POST /users',
+      type: 'documentation',
+      path: 'api/openapi.yaml',
+      language: 'openapi',
+      startLine: 17,
+    },
+  };
+  const openApiMap: OpenApiMap = {
+    specs: [
+      {
+        file: 'api/openapi.yaml',
+        operations: [
+          {
+            spec,
+            method: 'POST',
+            path: '/users',
+            fullPath: '/api/v1/users',
+            operationId: 'createUser',
+            matches: [
+              {
+                route: {
+                  method: 'POST',
+                  path: '/api/v1/users',
+                  framework: 'chi',
+                  registeredBy: symbol('Server.Routes', 'internal/api/server.go', 20),
+                  line: 24,
+                  handler: 's.CreateUser',
+                  handlerSymbol: symbol('Server.CreateUser', 'internal/api/users.go', 40),
+                  callees: [],
+                },
+                confidence: 'high',
+                reason: 'method and path match',
+              },
+            ],
+          },
+        ],
+      },
+    ],
+    total: 1,
+    linked: 1,
+    omitted: 0,
+    unspecifiedRoutes: [],
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: OpenApiAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getOpenApiOperations: vi.fn().mockResolvedValue(openApiMap),
+    } as unknown as SearchService;
+
+    adapter = new OpenApiAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_openapi tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_openapi');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('pathPrefix');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('unlinkedOnly');
+  });
+
+  it('should list operations with the handlers implementing them', async () => {
+    const output = await adapter.execute({ method: 'POST' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getOpenApiOperations).toHaveBeenCalledWith({
+      pathPrefix: undefined,
+      method: 'POST',
+      tag: undefined,
+      unlinkedOnly: false,
+      limit: 200,
+    });
+
+    const data = output.data as string;
+    expect(data).toContain('# OpenAPI Operations (1 in 1 spec, 1 linked to handlers)');
+    expect(data).toContain('- **POST /api/v1/users** createUser (line 17)');
+    expect(data).toContain(
+      '  - high confidence: Server.CreateUser (internal/api/users.go:40), POST /api/v1/users'
+    );
+  });
+
+  it('should explain an empty result', async () => {
+    vi.mocked(mockSearchService.getOpenApiOperations).mockResolvedValue({
+      specs: [],
+      total: 0,
+      linked: 0,
+      omitted: 0,
+      unspecifiedRoutes: [],
+    });
+
+    const unfiltered = await adapter.execute({}, mockContext);
+    expect(unfiltered.data).toContain('No OpenAPI operations found');
+
+    const filtered = await adapter.execute({ tag: 'billing' }, mockContext);
+    expect(filtered.data).toContain('No OpenAPI operations match these filters');
+  });
+
+  it('should reject unknown arguments', async () => {
+    const output = await adapter.execute({ operationId: 'createUser' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getOpenApiOperations).not.toHaveBeenCalled();
+  });
+
+  it('should handle listing failures', async () => {
+    vi.mocked(mockSearchService.getOpenApiOperations).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({}, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('OPENAPI_FAILED');
+  });
+});
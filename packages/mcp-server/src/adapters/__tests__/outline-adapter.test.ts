@@ -0,0 +1,108 @@
+import type { PackageOutline, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { OutlineAdapter } from '../built-in/outline-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('OutlineAdapter', () => {
+  const outline: PackageOutline = {
+    package: 'internal/retry',
+    types: [
+      {
+        symbol: {
+          id: 'internal/retry/client.go:Client:10',
+          score: 1,
+          metadata: {
+            name: 'Client',
+            type: 'class',
+            path: 'internal/retry/client.go',
+            language: 'go',
+            startLine: 10,
+            docstring: 'Client retries requests.',
+          },
+        },
+        kind: 'struct',
+        embeds: ['base'],
+        implements: ['Doer'],
+        implementedBy: [],
+        constructors: ['NewClient'],
+        methods: ['Do'],
+      },
+    ],
+    functions: [],
+    omittedTypes: 0,
+    omittedFunctions: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: OutlineAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getPackageOutline: vi.fn().mockResolvedValue(outline),
+    } as unknown as SearchService;
+
+    adapter = new OutlineAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_outline tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_outline');
+    expect(definition.inputSchema.required).toEqual(['package']);
+    expect(definition.inputSchema.properties).toHaveProperty('includeUnexported');
+    expect(definition.inputSchema.properties).toHaveProperty('limit');
+  });
+
+  it('should format the package outline', async () => {
+    const result = await adapter.execute({ package: 'retry' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.getPackageOutline).toHaveBeenCalledWith('retry', {
+      includeUnexported: false,
+      limit: 30,
+    });
+
+    const data = result.data as string;
+    expect(data).toContain('# Package internal/retry');
+    expect(data).toContain('### Client (struct) - client.go:10');
+    expect(data).toContain('- embeds: base');
+    expect(data).toContain('- implements: Doer');
+    expect(data).toContain('- constructed by: NewClient');
+  });
+
+  it('should report unknown packages', async () => {
+    vi.mocked(mockSearchService.getPackageOutline).mockResolvedValue(null);
+
+    const result = await adapter.execute({ package: 'missing' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('PACKAGE_NOT_FOUND');
+  });
+
+  it('should reject invalid arguments', async () => {
+    const result = await adapter.execute({ package: 'retry', limit: 0 }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(mockSearchService.getPackageOutline).not.toHaveBeenCalled();
+  });
+
+  it('should handle outline failures', async () => {
+    vi.mocked(mockSearchService.getPackageOutline).mockRejectedValue(new Error('index missing'));
+
+    const result = await adapter.execute({ package: 'retry' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('OUTLINE_FAILED');
+  });
+});
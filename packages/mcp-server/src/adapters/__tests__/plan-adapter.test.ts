@@ -4,6 +4,7 @@
 
 import type { RepositoryIndexer } from '@lytics/dev-agent-core';
 import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { OUTPUT_SCHEMA_VERSION, PlanStructuredOutputSchema } from '../../schemas/index.js';
 import { PlanAdapter } from '../built-in/plan-adapter';
 import type { AdapterContext, ToolExecutionContext } from '../types';
 
@@ -147,7 +148,14 @@ describe('PlanAdapter', () => {
       const formatProperty = definition.inputSchema.properties?.format;
 
       expect(formatProperty).toBeDefined();
-      expect(formatProperty?.enum).toEqual(['compact', 'verbose']);
+      expect(formatProperty?.enum).toEqual(['compact', 'verbose', 'json']);
+    });
+
+    it('should declare a versioned output schema', () => {
+      const definition = adapter.getToolDefinition();
+
+      expect(definition.outputSchema?.properties).toHaveProperty('schemaVersion');
+      expect(definition.outputSchema?.required).toContain('relevantCode');
     });
   });
 
@@ -213,6 +221,16 @@ describe('PlanAdapter', () => {
         expect(result.data).toContain('"relevantCode"');
       });
 
+      it('should return structured output matching the schema in json format', async () => {
+        const result = await adapter.execute({ issue: 29, format: 'json' }, mockExecutionContext);
+
+        expect(result.success).toBe(true);
+        const output = PlanStructuredOutputSchema.parse(result.data);
+        expect(output.schemaVersion).toBe(OUTPUT_SCHEMA_VERSION);
+        expect(output.issue.number).toBe(29);
+        expect(output.relevantCode[0].name).toBe('SearchAdapter');
+      });
+
       it('should include context object in verbose mode', async () => {
         const result = await adapter.execute(
           { issue: 29, format: 'verbose' },
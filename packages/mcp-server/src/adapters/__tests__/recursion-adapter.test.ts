@@ -0,0 +1,84 @@
+import type { RecursionReport, SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { RecursionAdapter } from '../built-in/recursion-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('RecursionAdapter', () => {
+  const walk: SearchResult = {
+    id: 'internal/parser/walk.go:walk',
+    score: 1,
+    metadata: { name: 'walk', type: 'function', path: 'internal/parser/walk.go', startLine: 3 },
+  };
+  const report: RecursionReport = {
+    scope: 'internal/parser',
+    cycles: [
+      {
+        kind: 'self',
+        symbols: [walk],
+        path: ['internal/parser.walk', 'internal/parser.walk'],
+        calls: [{ caller: walk, callee: walk, line: 8 }],
+      },
+    ],
+    omitted: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: RecursionAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getRecursion: vi.fn().mockResolvedValue(report),
+    } as unknown as SearchService;
+
+    adapter = new RecursionAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_recursion tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_recursion');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('package');
+  });
+
+  it('should list the cycles in a package', async () => {
+    const output = await adapter.execute({ package: 'internal/parser' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getRecursion).toHaveBeenCalledWith({
+      package: 'internal/parser',
+      includeTests: false,
+      limit: 50,
+    });
+    const data = output.data as string;
+    expect(data).toContain('# Recursion in internal/parser (1 cycle)');
+    expect(data).toContain('- **internal/parser.walk** calls itself (internal/parser/walk.go:8)');
+  });
+
+  it('should report an unknown package', async () => {
+    vi.mocked(mockSearchService.getRecursion).mockResolvedValue(null);
+
+    const output = await adapter.execute({ package: 'missing' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('PACKAGE_NOT_FOUND');
+  });
+
+  it('should reject unknown arguments', async () => {
+    const output = await adapter.execute({ symbol: 'walk' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getRecursion).not.toHaveBeenCalled();
+  });
+});
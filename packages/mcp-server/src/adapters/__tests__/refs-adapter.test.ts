@@ -4,6 +4,7 @@
 
 import type { SearchResult, SearchService } from '@lytics/dev-agent-core';
 import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { OUTPUT_SCHEMA_VERSION, RefsStructuredOutputSchema } from '../../schemas/index.js';
 import { ConsoleLogger } from '../../utils/logger';
 import { RefsAdapter } from '../built-in/refs-adapter';
 import type { AdapterContext, ToolExecutionContext } from '../types';
@@ -268,6 +269,33 @@ describe('RefsAdapter', () => {
     });
   });
 
+  describe('JSON Output', () => {
+    it('should declare a versioned output schema', () => {
+      const def = adapter.getToolDefinition();
+
+      expect(def.inputSchema.properties?.format?.enum).toEqual(['text', 'json']);
+      expect(def.outputSchema?.properties).toHaveProperty('schemaVersion');
+      expect(def.outputSchema?.properties).toHaveProperty('target');
+      expect(def.outputSchema?.properties).toHaveProperty('results');
+    });
+
+    it('should return structured output matching the schema', async () => {
+      const result = await adapter.execute({ name: 'createPlan', format: 'json' }, execContext);
+
+      expect(result.success).toBe(true);
+      const output = RefsStructuredOutputSchema.parse(result.data);
+      expect(output.schemaVersion).toBe(OUTPUT_SCHEMA_VERSION);
+      expect(output.target).toEqual({
+        name: 'createPlan',
+        file: 'src/planner.ts',
+        line: 10,
+        type: 'function',
+      });
+      expect(output.callees?.[0]).toEqual({ name: 'fetchIssue', file: 'src/github.ts', line: 15 });
+      expect(output.callers?.map((c) => c.name)).toEqual(['runPlan', 'main']);
+    });
+  });
+
   describe('Not Found', () => {
     it('should return error when function not found', async () => {
       // Mock empty results
@@ -280,6 +308,118 @@ describe('RefsAdapter', () => {
     });
   });
 
+  describe('Batch Queries', () => {
+    beforeEach(() => {
+      (mockSearchService as { searchBatch?: unknown }).searchBatch = vi
+        .fn()
+        .mockImplementation(async (queries: string[]) =>
+          queries.map((query) => (query === 'missingFunction' ? [] : mockSearchResults))
+        );
+    });
+
+    it('should resolve every name with batched searches', async () => {
+      const result = await adapter.execute(
+        { name: ['createPlan', 'runPlan'], format: 'json' },
+        execContext
+      );
+
+      expect(result.success).toBe(true);
+      expect(mockSearchService.searchBatch).toHaveBeenCalledTimes(2);
+      expect(mockSearchService.search).not.toHaveBeenCalled();
+
+      const output = RefsStructuredOutputSchema.parse(result.data);
+      expect(Object.keys(output.results ?? {})).toEqual(['createPlan', 'runPlan']);
+      expect(output.results?.runPlan).toMatchObject({
+        target: { name: 'runPlan', file: 'src/executor.ts', line: 5 },
+        callees: [{ name: 'createPlan' }, { name: 'execute' }],
+      });
+    });
+
+    it('should report unresolvable names without failing the batch', async () => {
+      const result = await adapter.execute(
+        { name: ['createPlan', 'missingFunction'], format: 'json' },
+        execContext
+      );
+
+      expect(result.success).toBe(true);
+      const output = RefsStructuredOutputSchema.parse(result.data);
+      expect(output.results?.missingFunction).toEqual({
+        error: {
+          code: 'NOT_FOUND',
+          message: 'Could not find function or method named "missingFunction"',
+        },
+      });
+      expect(output.results?.createPlan).toHaveProperty('target');
+    });
+
+    it('should render a section per name as text', async () => {
+      const result = await adapter.execute(
+        { name: ['createPlan', 'missingFunction'], direction: 'callees' },
+        execContext
+      );
+
+      expect(result.data).toContain('# References for createPlan');
+      expect(result.data).toContain(
+        '# References for missingFunction\n*Could not find function or method named'
+      );
+    });
+
+    it('should reject empty and oversized batches', async () => {
+      const empty = await adapter.execute({ name: [] }, execContext);
+      const oversized = await adapter.execute(
+        { name: Array.from({ length: 26 }, (_, i) => `fn${i}`) },
+        execContext
+      );
+
+      expect(empty.error?.code).toBe('INVALID_PARAMS');
+      expect(oversized.error?.code).toBe('INVALID_PARAMS');
+    });
+  });
+
+  describe('Type References', () => {
+    const goSymbol = (
+      name: string,
+      type: string,
+      file: string,
+      referencesTypes?: string[]
+    ): SearchResult => ({
+      id: `${file}:${name}:1`,
+      score: 0.9,
+      metadata: { path: file, type, name, startLine: 1, language: 'go', referencesTypes },
+    });
+    const goResults = [
+      goSymbol('Config', 'class', 'server/config.go'),
+      goSymbol('NewServer', 'function', 'server/server.go', ['Config', 'Server']),
+      goSymbol('Load', 'function', 'cmd/main.go', ['config.Config']),
+      goSymbol('Run', 'function', 'cmd/main.go', ['server.Config']),
+      goSymbol('Parse', 'function', 'other/parse.go', ['Config']),
+    ];
+
+    beforeEach(() => {
+      vi.mocked(mockSearchService.search).mockResolvedValue(goResults);
+    });
+
+    it('should list the functions that use a type', async () => {
+      const result = await adapter.execute({ name: 'Config', direction: 'callers' }, execContext);
+
+      expect(result.data).toContain('## Used By (functions referring to this type)');
+      expect(result.data).toContain('`NewServer` (function) at server/server.go:1');
+      expect(result.data).toContain('`Run` (function)');
+      // Another package's Config, or one qualified with a different package
+      expect(result.data).not.toContain('`Parse`');
+      expect(result.data).not.toContain('`Load`');
+    });
+
+    it('should list the types a function uses', async () => {
+      const result = await adapter.execute(
+        { name: 'NewServer', direction: 'callees', format: 'json' },
+        execContext
+      );
+
+      expect(result.data).toMatchObject({ referencesTypes: ['Config', 'Server'] });
+    });
+  });
+
   describe('Token Estimation', () => {
     it('should estimate tokens based on limit and direction', () => {
       const bothTokens = adapter.estimateTokens({ limit: 10, direction: 'both' });
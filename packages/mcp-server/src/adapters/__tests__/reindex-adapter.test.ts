@@ -0,0 +1,137 @@
+import type { IndexProgress } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import type { ReindexJob, ReindexJobs } from '../../server/reindex-jobs';
+import { ReindexAdapter } from '../built-in/reindex-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('ReindexAdapter', () => {
+  const running: ReindexJob = {
+    id: 'reindex-1',
+    mode: 'incremental',
+    status: 'running',
+    requests: 1,
+    queuedAt: new Date('2025-11-24T09:00:00Z'),
+    startedAt: new Date('2025-11-24T09:00:00Z'),
+    progress: {
+      phase: 'scanning',
+      filesProcessed: 10,
+      totalFiles: 40,
+      documentsIndexed: 0,
+      percentComplete: 25,
+    },
+  };
+  const completed: ReindexJob = {
+    ...running,
+    status: 'completed',
+    requests: 2,
+    progress: undefined,
+    finishedAt: new Date('2025-11-24T09:00:03Z'),
+    stats: { filesScanned: 40, documentsIndexed: 200, duration: 3200, errors: 0 },
+  };
+
+  let jobs: Pick<ReindexJobs, 'request' | 'get' | 'wait'>;
+  let adapter: ReindexAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    jobs = {
+      request: vi.fn().mockReturnValue({ job: running, joined: false }),
+      get: vi.fn().mockReturnValue(undefined),
+      wait: vi.fn().mockResolvedValue(completed),
+    };
+
+    adapter = new ReindexAdapter({ jobs });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_reindex tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_reindex');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('mode');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('jobId');
+  });
+
+  it('should start an incremental reindex and return its job id', async () => {
+    const output = await adapter.execute({}, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(jobs.request).toHaveBeenCalledWith('incremental');
+
+    const data = output.data as string;
+    expect(data).toContain('## Reindex job reindex-1');
+    expect(data).toContain('**Status:** running (scanning, 25%: 10/40 files)');
+    expect(data).toContain('Poll with `dev_reindex` and `jobId: "reindex-1"`');
+  });
+
+  it('should say when a request joins a job already underway', async () => {
+    vi.mocked(jobs.request).mockReturnValue({ job: running, joined: true });
+
+    const output = await adapter.execute({ mode: 'full' }, mockContext);
+
+    expect(jobs.request).toHaveBeenCalledWith('full');
+    expect(output.data).toContain('this request joined it');
+  });
+
+  it('should poll a job by id without starting another', async () => {
+    vi.mocked(jobs.get).mockReturnValue(completed);
+
+    const output = await adapter.execute({ jobId: 'reindex-1' }, mockContext);
+
+    expect(jobs.request).not.toHaveBeenCalled();
+    const data = output.data as string;
+    expect(data).toContain('**Status:** completed in 3.2s: 40 files scanned, 200 documents');
+    expect(data).toContain('**Requests:** 2');
+    expect(data).toContain('now use the updated index');
+  });
+
+  it('should wait for the job when asked, forwarding rising progress', async () => {
+    const sendProgress = vi.fn().mockResolvedValue(undefined);
+    vi.mocked(jobs.wait).mockImplementation(async (_id, onProgress) => {
+      const progress = running.progress as IndexProgress;
+      onProgress?.({ ...progress, etaSeconds: 30 }, running);
+      onProgress?.({ ...progress, percentComplete: 25.2 }, running);
+      onProgress?.({ ...progress, phase: 'storing', percentComplete: 70 }, running);
+      return completed;
+    });
+
+    const output = await adapter.execute({ wait: true }, { ...mockContext, sendProgress });
+
+    expect(jobs.wait).toHaveBeenCalledWith('reindex-1', expect.any(Function));
+    expect(sendProgress.mock.calls.map(([p]) => p)).toEqual([
+      { progress: 25, total: 100, message: 'scanning, 25%: 10/40 files, ~30s left' },
+      { progress: 70, total: 100, message: 'storing, 70%: 10/40 files' },
+    ]);
+    expect(output.data).toContain('**Status:** completed in 3.2s');
+  });
+
+  it('should not wait unless asked', async () => {
+    await adapter.execute({}, mockContext);
+
+    expect(jobs.wait).not.toHaveBeenCalled();
+  });
+
+  it('should report an unknown job id', async () => {
+    const output = await adapter.execute({ jobId: 'reindex-9' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('JOB_NOT_FOUND');
+  });
+
+  it('should reject an unknown mode', async () => {
+    const output = await adapter.execute({ mode: 'partial' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(jobs.request).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,108 @@
+import type { RouteMap, SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { RoutesAdapter } from '../built-in/routes-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('RoutesAdapter', () => {
+  const symbol = (name: string, path: string, startLine: number): SearchResult => ({
+    id: `${path}:${name}:${startLine}`,
+    score: 1,
+    metadata: { name, type: 'function', path, language: 'go', startLine },
+  });
+  const routeMap: RouteMap = {
+    groups: [
+      {
+        package: 'users',
+        routes: [
+          {
+            method: 'POST',
+            path: '/users',
+            framework: 'chi',
+            registeredBy: symbol('Router', 'users/router.go', 8),
+            line: 12,
+            handler: 'h.CreateUser',
+            handlerSymbol: symbol('Handler.CreateUser', 'users/handlers.go', 20),
+            callees: [symbol('validateUser', 'users/validate.go', 4)],
+          },
+        ],
+      },
+    ],
+    total: 1,
+    omitted: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: RoutesAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getRoutes: vi.fn().mockResolvedValue(routeMap),
+    } as unknown as SearchService;
+
+    adapter = new RoutesAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_routes tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_routes');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('pathPrefix');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('method');
+  });
+
+  it('should list routes with their handlers and callees', async () => {
+    const output = await adapter.execute({ pathPrefix: '/users', method: 'POST' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getRoutes).toHaveBeenCalledWith({
+      pathPrefix: '/users',
+      method: 'POST',
+      includeTests: false,
+      limit: 200,
+    });
+
+    const data = output.data as string;
+    expect(data).toContain('# HTTP Routes (1 in 1 package)');
+    expect(data).toContain('- **POST /users** → Handler.CreateUser (users/handlers.go:20)');
+    expect(data).toContain('  - registered in Router (users/router.go:12, chi)');
+    expect(data).toContain('  - calls validateUser');
+  });
+
+  it('should explain an empty result', async () => {
+    vi.mocked(mockSearchService.getRoutes).mockResolvedValue({ groups: [], total: 0, omitted: 0 });
+
+    const unfiltered = await adapter.execute({}, mockContext);
+    expect(unfiltered.data).toContain('No HTTP routes found');
+
+    const filtered = await adapter.execute({ method: 'PATCH' }, mockContext);
+    expect(filtered.data).toContain('No HTTP routes match these filters');
+  });
+
+  it('should reject invalid arguments', async () => {
+    const output = await adapter.execute({ limit: 0 }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getRoutes).not.toHaveBeenCalled();
+  });
+
+  it('should handle listing failures', async () => {
+    vi.mocked(mockSearchService.getRoutes).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({}, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('ROUTES_FAILED');
+  });
+});
@@ -2,14 +2,22 @@
  * Tests for SearchAdapter
  */
 
-import type { RepositoryIndexer, SearchResult } from '@lytics/dev-agent-core';
+import {
+  EmbeddingsUnavailableError,
+  type RepositoryIndexer,
+  type SearchResult,
+  type SearchService,
+} from '@lytics/dev-agent-core';
 import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { OUTPUT_SCHEMA_VERSION, SearchStructuredOutputSchema } from '../../schemas/index.js';
 import { ConsoleLogger } from '../../utils/logger';
+import { ResultCache } from '../../utils/result-cache';
 import { SearchAdapter } from '../built-in/search-adapter';
-import type { AdapterContext, ToolExecutionContext } from '../types';
+import type { AdapterContext, ToolExecutionContext, ToolResult } from '../types';
 
 describe('SearchAdapter', () => {
   let mockIndexer: RepositoryIndexer;
+  let mockSearchService: SearchService;
   let adapter: SearchAdapter;
   let context: AdapterContext;
   let execContext: ToolExecutionContext;
@@ -59,16 +67,17 @@ describe('SearchAdapter', () => {
 
     // Create adapter
     // Create mock search service
-    const mockSearchService = {
+    mockSearchService = {
       search: mockIndexer.search,
       findSimilar: vi.fn(),
       findRelatedTests: vi.fn(),
       findSymbol: vi.fn(),
       isIndexed: vi.fn(),
-    };
+      getIndexVersion: vi.fn().mockResolvedValue('v1'),
+    } as unknown as SearchService;
 
     adapter = new SearchAdapter({
-      searchService: mockSearchService as any,
+      searchService: mockSearchService,
       defaultFormat: 'compact',
       defaultLimit: 10,
     });
@@ -103,7 +112,8 @@ describe('SearchAdapter', () => {
       expect(def.inputSchema.properties).toHaveProperty('format');
       expect(def.inputSchema.properties).toHaveProperty('limit');
       expect(def.inputSchema.properties).toHaveProperty('scoreThreshold');
-      expect(def.inputSchema.required).toContain('query');
+      expect(def.inputSchema.properties).toHaveProperty('symbols');
+      expect(def.inputSchema.properties).toHaveProperty('pooling');
     });
 
     it('should have correct format enum', () => {
@@ -112,7 +122,17 @@ describe('SearchAdapter', () => {
 
       expect(formatProp).toBeDefined();
       expect(formatProp).toHaveProperty('enum');
-      expect((formatProp as { enum: string[] }).enum).toEqual(['compact', 'verbose']);
+      expect((formatProp as { enum: string[] }).enum).toEqual(['compact', 'verbose', 'json']);
+    });
+
+    it('should declare a versioned output schema', () => {
+      const def = adapter.getToolDefinition();
+
+      expect(def.outputSchema?.type).toBe('object');
+      expect(def.outputSchema?.properties).toHaveProperty('schemaVersion');
+      expect(def.outputSchema?.required).toEqual(
+        expect.arrayContaining(['schemaVersion', 'query', 'results', 'total'])
+      );
     });
   });
 
@@ -294,8 +314,14 @@ describe('SearchAdapter', () => {
       expect(result.metadata).toHaveProperty('duration_ms');
       expect(result.metadata).toHaveProperty('results_total', 2);
       expect(mockIndexer.search).toHaveBeenCalledWith('authentication', {
-        limit: 10,
+        limit: 50,
         scoreThreshold: 0,
+        minScore: 0.3,
+        onMinScoreCutoff: expect.any(Function),
+        includeGenerated: false,
+        expand: false,
+        sort: 'relevance',
+        debug: false,
       });
     });
 
@@ -324,8 +350,14 @@ describe('SearchAdapter', () => {
 
       expect(result.success).toBe(true);
       expect(mockIndexer.search).toHaveBeenCalledWith('test', {
-        limit: 3,
+        limit: 15,
         scoreThreshold: 0,
+        minScore: 0.3,
+        onMinScoreCutoff: expect.any(Function),
+        includeGenerated: false,
+        expand: false,
+        sort: 'relevance',
+        debug: false,
       });
       expect(result.metadata?.results_total).toBe(2); // Mock returns 2 results
     });
@@ -341,11 +373,96 @@ describe('SearchAdapter', () => {
 
       expect(result.success).toBe(true);
       expect(mockIndexer.search).toHaveBeenCalledWith('test', {
-        limit: 10,
+        limit: 50,
         scoreThreshold: 0.9,
+        minScore: 0.3,
+        onMinScoreCutoff: expect.any(Function),
+        includeGenerated: false,
+        expand: false,
+        sort: 'relevance',
+        debug: false,
+      });
+    });
+
+    it('should filter to exported symbols when exportedOnly is set', async () => {
+      const result = await adapter.execute(
+        {
+          query: 'test',
+          exportedOnly: true,
+        },
+        execContext
+      );
+
+      expect(result.success).toBe(true);
+      expect(mockIndexer.search).toHaveBeenCalledWith('test', {
+        limit: 50,
+        scoreThreshold: 0,
+        minScore: 0.3,
+        onMinScoreCutoff: expect.any(Function),
+        filter: { exported: true },
+        includeGenerated: false,
+        expand: false,
+        sort: 'relevance',
+        debug: false,
+      });
+    });
+
+    it('should scope results to a module', async () => {
+      await adapter.execute(
+        {
+          query: 'test',
+          module: 'github.com/acme/api',
+          exportedOnly: true,
+        },
+        execContext
+      );
+
+      expect(mockIndexer.search).toHaveBeenCalledWith('test', {
+        limit: 50,
+        scoreThreshold: 0,
+        minScore: 0.3,
+        onMinScoreCutoff: expect.any(Function),
+        filter: { exported: true, module: 'github.com/acme/api' },
+        includeGenerated: false,
+        expand: false,
+        sort: 'relevance',
+        debug: false,
+      });
+    });
+
+    it('should filter on custom metadata fields', async () => {
+      await adapter.execute(
+        {
+          query: 'test',
+          where: { team: 'payments', tier: 1 },
+          exportedOnly: true,
+        },
+        execContext
+      );
+
+      expect(mockIndexer.search).toHaveBeenCalledWith('test', {
+        limit: 50,
+        scoreThreshold: 0,
+        minScore: 0.3,
+        onMinScoreCutoff: expect.any(Function),
+        filter: { team: 'payments', tier: 1, exported: true },
+        includeGenerated: false,
+        expand: false,
+        sort: 'relevance',
+        debug: false,
       });
     });
 
+    it('should reject non-scalar metadata filter values', async () => {
+      const result = await adapter.execute(
+        { query: 'test', where: { team: ['payments'] } },
+        execContext
+      );
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
     it('compact format should use fewer tokens than verbose', async () => {
       const compactResult = await adapter.execute(
         {
@@ -387,6 +504,615 @@ describe('SearchAdapter', () => {
       expect(result.metadata?.results_total).toBe(0);
       expect(result.data).toContain('No results');
     });
+
+    it('should say when nothing clears minScore', async () => {
+      vi.mocked(mockSearchService.search).mockImplementation(async (_query, options) => {
+        options?.onMinScoreCutoff?.(3);
+        return [];
+      });
+
+      const result = await adapter.execute({ query: 'billing', minScore: 0.6 }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'billing',
+        expect.objectContaining({ minScore: 0.6 })
+      );
+      expect(result.metadata?.no_strong_matches).toBe(true);
+      expect(result.data).toContain('No results scored 0.6 or higher for "billing"');
+
+      const json = await adapter.execute({ query: 'billing', format: 'json' }, execContext);
+      const output = SearchStructuredOutputSchema.parse(json.data);
+      expect(output.minScore).toBe(0.3);
+      expect(output.noStrongMatches).toBe(true);
+    });
+
+    it('should not claim weak matches when a filter left no candidates', async () => {
+      vi.mocked(mockSearchService.search).mockImplementation(async (_query, options) => {
+        options?.onMinScoreCutoff?.(0);
+        return [];
+      });
+
+      const result = await adapter.execute(
+        { query: 'billing', pathFilter: 'internal/billing/' },
+        execContext
+      );
+
+      expect(result.metadata?.no_strong_matches).toBe(false);
+      expect(result.data).toContain('No results');
+      expect(result.data).not.toContain('no strong matches');
+    });
+
+    it('should reject minScore outside 0-1', async () => {
+      const result = await adapter.execute({ query: 'test', minScore: 1.5 }, execContext);
+
+      expect(result.success).toBe(false);
+    });
+
+    it('should reject contextLines outside 0-20', async () => {
+      const result = await adapter.execute({ query: 'test', contextLines: 21 }, execContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should keep indexed snippets when no repository path is configured', async () => {
+      const result = await adapter.execute({ query: 'test', contextLines: 3 }, execContext);
+
+      expect(result.success).toBe(true);
+      expect(result.data).not.toContain(' > ');
+    });
+
+    it('should say when embeddings are unavailable', async () => {
+      vi.mocked(mockIndexer.search).mockRejectedValue(
+        new EmbeddingsUnavailableError('Xenova/all-MiniLM-L6-v2', 'fetch failed')
+      );
+
+      const result = await adapter.execute({ query: 'authentication' }, execContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('EMBEDDINGS_UNAVAILABLE');
+      expect(result.error?.message).toContain('Embeddings unavailable');
+      expect(result.error?.suggestion).toContain('dev_whereis');
+    });
+  });
+
+  describe('Pagination', () => {
+    const manyResults: SearchResult[] = Array.from({ length: 7 }, (_, i) => ({
+      id: `src/file${i}.ts:fn${i}:1`,
+      score: 0.9 - i * 0.01,
+      metadata: {
+        path: `src/file${i}.ts`,
+        type: 'function',
+        name: `fn${i}`,
+        startLine: 1,
+        endLine: 5,
+        language: 'typescript',
+      },
+    }));
+
+    beforeEach(() => {
+      vi.mocked(mockIndexer.search).mockImplementation(async (_query, options) =>
+        manyResults.slice(0, options?.limit)
+      );
+    });
+
+    it('should return a cursor when more results remain', async () => {
+      const result = await adapter.execute({ query: 'test', limit: 3 }, execContext);
+
+      expect(result.success).toBe(true);
+      expect(result.metadata?.results_returned).toBe(3);
+      expect(result.metadata?.results_total).toBe(7);
+      expect(result.metadata?.results_total_is_estimate).toBe(false);
+      expect(result.metadata?.results_truncated).toBe(true);
+      expect(result.metadata?.next_cursor).toEqual(expect.any(String));
+      expect(result.data).toContain('Results 1-3 of 7');
+    });
+
+    it('should return the next page for a cursor', async () => {
+      const first = await adapter.execute({ query: 'test', limit: 3 }, execContext);
+      const second = await adapter.execute(
+        { query: 'test', limit: 3, cursor: first.metadata?.next_cursor },
+        execContext
+      );
+
+      expect(second.success).toBe(true);
+      expect(second.data).toContain('fn3');
+      expect(second.data).not.toContain('fn0');
+      expect(second.data).toContain('Results 4-6 of 7');
+      expect(mockIndexer.search).toHaveBeenLastCalledWith(
+        'test',
+        expect.objectContaining({ limit: 18, scoreThreshold: 0 })
+      );
+    });
+
+    it('should end paging on the last page', async () => {
+      const first = await adapter.execute({ query: 'test', limit: 5 }, execContext);
+      const second = await adapter.execute(
+        { query: 'test', limit: 5, cursor: first.metadata?.next_cursor },
+        execContext
+      );
+
+      expect(second.metadata?.results_returned).toBe(2);
+      expect(second.metadata?.results_truncated).toBe(false);
+      expect(second.metadata?.next_cursor).toBeUndefined();
+    });
+
+    it('should flag the total as an estimate when the ranking window is full', async () => {
+      const result = await adapter.execute({ query: 'test', limit: 1 }, execContext);
+
+      expect(result.metadata?.results_total).toBe(5);
+      expect(result.metadata?.results_total_is_estimate).toBe(true);
+      expect(result.data).toContain('Results 1-1 of at least 5');
+    });
+
+    it('should reject a cursor from a different query', async () => {
+      const first = await adapter.execute({ query: 'test', limit: 3 }, execContext);
+      const result = await adapter.execute(
+        { query: 'other', limit: 3, cursor: first.metadata?.next_cursor },
+        execContext
+      );
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should reject a malformed cursor', async () => {
+      const result = await adapter.execute({ query: 'test', cursor: 'not-a-cursor' }, execContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should expire cursors when the index changes', async () => {
+      const first = await adapter.execute({ query: 'test', limit: 3 }, execContext);
+      vi.mocked(mockSearchService.getIndexVersion).mockResolvedValue('v2');
+
+      const result = await adapter.execute(
+        { query: 'test', limit: 3, cursor: first.metadata?.next_cursor },
+        execContext
+      );
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('CURSOR_EXPIRED');
+      expect(result.error?.recoverable).toBe(true);
+    });
+
+    it('should stream results as progress notifications when requested', async () => {
+      const sendProgress = vi.fn().mockResolvedValue(undefined);
+
+      await adapter.execute({ query: 'test', limit: 2 }, { ...execContext, sendProgress });
+
+      expect(sendProgress).toHaveBeenCalledTimes(2);
+      expect(sendProgress).toHaveBeenNthCalledWith(1, {
+        progress: 1,
+        total: 2,
+        message: '1. fn0 (src/file0.ts:1) [0.90]',
+      });
+    });
+  });
+
+  describe('JSON Output', () => {
+    it('should return structured output matching the schema', async () => {
+      const result = await adapter.execute({ query: 'auth', format: 'json' }, execContext);
+
+      expect(result.success).toBe(true);
+      const output = SearchStructuredOutputSchema.parse(result.data);
+      expect(output.schemaVersion).toBe(OUTPUT_SCHEMA_VERSION);
+      expect(output.query).toBe('auth');
+      expect(output.total).toBe(2);
+      expect(output.totalIsEstimate).toBe(false);
+      expect(output.nextCursor).toBeUndefined();
+      expect(output.results[0]).toEqual({
+        id: 'src/auth.ts:authenticate:10',
+        score: 0.92,
+        name: 'authenticate',
+        type: 'function',
+        path: 'src/auth.ts',
+        startLine: 10,
+        endLine: 25,
+        signature: 'export function authenticate(user: User): boolean',
+        exported: true,
+      });
+    });
+
+    it('should carry the next cursor instead of a markdown footer', async () => {
+      const result = await adapter.execute(
+        { query: 'auth', format: 'json', limit: 1 },
+        execContext
+      );
+
+      const output = SearchStructuredOutputSchema.parse(result.data);
+      expect(output.results).toHaveLength(1);
+      expect(output.nextCursor).toBe(result.metadata?.next_cursor);
+      expect(output.nextCursor).toEqual(expect.any(String));
+    });
+  });
+
+  describe('Query Expansion', () => {
+    beforeEach(() => {
+      vi.mocked(mockSearchService.search).mockResolvedValue([
+        {
+          ...mockSearchResults[0],
+          metadata: { ...mockSearchResults[0].metadata, expandedTerms: ['login'] },
+        },
+        mockSearchResults[1],
+      ]);
+    });
+
+    it('should pass expand to the search service', async () => {
+      await adapter.execute({ query: 'auth', expand: true }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ expand: true })
+      );
+    });
+
+    it('should report the synonyms searched and which contributed', async () => {
+      const result = await adapter.execute({ query: 'auth', expand: true }, execContext);
+
+      expect(result.data).toContain('**Query expansion**');
+      expect(result.data).toContain('- auth → authentication, authorization, login, credentials');
+      expect(result.data).toContain('Contributed: login (1)');
+    });
+
+    it('should include the expansion in JSON output', async () => {
+      const result = await adapter.execute(
+        { query: 'auth', expand: true, format: 'json' },
+        execContext
+      );
+
+      const output = SearchStructuredOutputSchema.parse(result.data);
+      expect(output.expansion).toEqual([
+        { term: 'auth', synonyms: ['authentication', 'authorization', 'login', 'credentials'] },
+      ]);
+      expect(output.results[0].expandedTerms).toEqual(['login']);
+    });
+  });
+
+  describe('Path Filter', () => {
+    it('should pass pathFilter to the search service', async () => {
+      await adapter.execute({ query: 'auth', pathFilter: '**/*_test.go' }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ pathFilter: '**/*_test.go' })
+      );
+    });
+
+    it('should reject an empty pathFilter', async () => {
+      const result = await adapter.execute({ query: 'auth', pathFilter: ' ' }, execContext);
+
+      expect(result.success).toBe(false);
+    });
+  });
+
+  describe('Identifier Constraints', () => {
+    it('should pass mustContain and mustNotContain to the search service', async () => {
+      await adapter.execute(
+        {
+          query: 'password validation',
+          mustContain: ['ValidatePassword'],
+          mustNotContain: ['mock'],
+        },
+        execContext
+      );
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'password validation',
+        expect.objectContaining({ mustContain: ['ValidatePassword'], mustNotContain: ['mock'] })
+      );
+    });
+
+    it('should reject an empty identifier list', async () => {
+      const result = await adapter.execute({ query: 'auth', mustContain: [] }, execContext);
+
+      expect(result.success).toBe(false);
+    });
+  });
+
+  describe('Recency', () => {
+    it('should pass changedSince and sort to the search service', async () => {
+      await adapter.execute(
+        { query: 'auth', changedSince: '2024-06-01', sort: 'recency' },
+        execContext
+      );
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ changedSince: '2024-06-01', sort: 'recency' })
+      );
+    });
+
+    it('should reject an invalid changedSince date', async () => {
+      const result = await adapter.execute(
+        { query: 'auth', changedSince: 'last week' },
+        execContext
+      );
+
+      expect(result.success).toBe(false);
+      expect(result.error?.message).toContain('changedSince must be an ISO date');
+    });
+  });
+
+  describe('Debug', () => {
+    const scoreDebug = {
+      vectorScore: 0.92,
+      keywordScore: null,
+      docBoost: 0.01,
+      finalScore: 0.93,
+      vectorRank: 1,
+      rank: 1,
+      matchedQuery: 'auth',
+      embeddedQuery: 'auth',
+      sort: 'relevance' as const,
+    };
+
+    beforeEach(() => {
+      vi.mocked(mockSearchService.search).mockResolvedValue([
+        {
+          ...mockSearchResults[0],
+          metadata: { ...mockSearchResults[0].metadata, scoreDebug },
+        },
+      ]);
+    });
+
+    it('should pass debug to the search service', async () => {
+      await adapter.execute({ query: 'auth', debug: true }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ debug: true })
+      );
+    });
+
+    it('should append a score breakdown to text output', async () => {
+      const result = await adapter.execute({ query: 'auth', debug: true }, execContext);
+
+      expect(result.data).toContain('**Score breakdown**');
+      expect(result.data).toContain('1. authenticate: vector 0.9200 (#1), keyword n/a');
+      expect(result.data).toContain('doc boost +0.0100, final 0.9300 via "auth"');
+    });
+
+    it('should include the breakdown in JSON output', async () => {
+      const result = await adapter.execute(
+        { query: 'auth', debug: true, format: 'json' },
+        execContext
+      );
+
+      const output = SearchStructuredOutputSchema.parse(result.data);
+      expect(output.results[0].debug).toEqual(scoreDebug);
+    });
+
+    it('should leave normal responses unchanged', async () => {
+      const result = await adapter.execute({ query: 'auth' }, execContext);
+
+      expect(result.data).not.toContain('Score breakdown');
+    });
+
+    it('should show ranks before and after reranking', async () => {
+      const rerank = {
+        model: 'Xenova/ms-marco-MiniLM-L-6-v2',
+        score: 0.8731,
+        preRank: 4,
+        postRank: 1,
+      };
+      vi.mocked(mockSearchService.search).mockResolvedValue([
+        {
+          ...mockSearchResults[0],
+          metadata: {
+            ...mockSearchResults[0].metadata,
+            scoreDebug: { ...scoreDebug, docBoost: 0, finalScore: 0.92, vectorRank: 4, rerank },
+          },
+        },
+      ]);
+
+      const text = await adapter.execute({ query: 'auth', debug: true }, execContext);
+      const json = await adapter.execute(
+        { query: 'auth', debug: true, format: 'json' },
+        execContext
+      );
+
+      expect(text.data).toContain('final 0.9200, rerank 0.8731 (#4 → #1) via "auth"');
+      expect(text.data).toContain('Top candidates reranked by Xenova/ms-marco-MiniLM-L-6-v2.');
+      expect(SearchStructuredOutputSchema.parse(json.data).results[0].debug?.rerank).toEqual(
+        rerank
+      );
+    });
+  });
+
+  describe('Multiple Examples', () => {
+    beforeEach(() => {
+      mockSearchService.searchExamples = vi.fn().mockResolvedValue({
+        results: [mockSearchResults[1]],
+        inputs: [mockSearchResults[0]],
+        unresolved: ['Missing'],
+      });
+    });
+
+    it('should search with queries and symbols together', async () => {
+      const result = await adapter.execute(
+        { query: 'auth', queries: ['login'], symbols: ['authenticate', 'Missing'], pooling: 'max' },
+        execContext
+      );
+
+      expect(result.success).toBe(true);
+      expect(mockSearchService.searchExamples).toHaveBeenCalledWith(
+        { queries: ['auth', 'login'], symbols: ['authenticate', 'Missing'] },
+        expect.objectContaining({ pooling: 'max', excludeInputs: true, limit: 10 })
+      );
+      expect(mockSearchService.search).not.toHaveBeenCalled();
+      expect(result.data).toContain('**More like:** "auth", "login", `authenticate` (max pooling)');
+      expect(result.data).toContain('AuthMiddleware');
+      expect(result.data).toContain('**Not found:** `Missing`');
+    });
+
+    it('should describe the examples in JSON output', async () => {
+      const result = await adapter.execute(
+        { symbols: ['authenticate'], excludeInputs: false, format: 'json' },
+        execContext
+      );
+
+      const output = SearchStructuredOutputSchema.parse(result.data);
+      expect(output.examples).toEqual({
+        queries: [],
+        symbols: ['authenticate'],
+        unresolved: ['Missing'],
+        pooling: 'mean',
+      });
+      expect(output.results.map((r) => r.name)).toEqual(['AuthMiddleware']);
+      expect(mockSearchService.searchExamples).toHaveBeenCalledWith(
+        expect.any(Object),
+        expect.objectContaining({ excludeInputs: false })
+      );
+    });
+
+    it('should report when no example symbol is indexed', async () => {
+      vi.mocked(mockSearchService.searchExamples).mockResolvedValue({
+        results: [],
+        inputs: [],
+        unresolved: ['Missing'],
+      });
+
+      const result = await adapter.execute({ symbols: ['Missing'] }, execContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+      expect(result.error?.recoverable).toBe(true);
+    });
+
+    it('should require a query, queries, or symbols', async () => {
+      const result = await adapter.execute({ limit: 5 }, execContext);
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should reject single-query options with several examples', async () => {
+      const result = await adapter.execute(
+        { symbols: ['authenticate'], expand: true },
+        execContext
+      );
+
+      expect(result.success).toBe(false);
+      expect(result.error?.code).toBe('INVALID_PARAMS');
+    });
+
+    it('should reject a session or keyword weight with several examples', async () => {
+      for (const args of [
+        { queries: ['auth', 'login'], session: 'explore-auth' },
+        { symbols: ['authenticate'], keywordWeight: 0.1 },
+      ]) {
+        const result = await adapter.execute(args, execContext);
+
+        expect(result.success).toBe(false);
+        expect(result.error?.code).toBe('INVALID_PARAMS');
+      }
+      expect(mockSearchService.searchExamples).not.toHaveBeenCalled();
+    });
+  });
+
+  describe('Relevance Feedback', () => {
+    it('should pass the session to the search service', async () => {
+      await adapter.execute({ query: 'auth', session: 'explore-auth' }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledWith(
+        'auth',
+        expect.objectContaining({ session: 'explore-auth' })
+      );
+    });
+
+    it('should say when session feedback adjusted the query', async () => {
+      vi.mocked(mockSearchService.search).mockResolvedValue([
+        {
+          ...mockSearchResults[0],
+          metadata: {
+            ...mockSearchResults[0].metadata,
+            scoreDebug: {
+              vectorScore: 0.9,
+              keywordScore: null,
+              docBoost: 0,
+              finalScore: 0.9,
+              vectorRank: 1,
+              rank: 1,
+              matchedQuery: 'auth',
+              embeddedQuery: 'auth',
+              sort: 'relevance',
+              feedback: { relevant: 2, nonRelevant: 1 },
+            },
+          },
+        },
+      ]);
+
+      const result = await adapter.execute(
+        { query: 'auth', session: 'explore-auth', debug: true },
+        execContext
+      );
+
+      expect(result.data).toContain(
+        'Query adjusted by session feedback: 2 relevant, 1 not relevant.'
+      );
+    });
+  });
+
+  describe('Result Cache', () => {
+    let cache: ResultCache<ToolResult>;
+    let cachedAdapter: SearchAdapter;
+
+    beforeEach(() => {
+      cache = new ResultCache<ToolResult>(10);
+      cachedAdapter = new SearchAdapter({
+        searchService: mockSearchService,
+        defaultFormat: 'compact',
+        defaultLimit: 10,
+        resultCache: cache,
+      });
+    });
+
+    it('should answer a repeated query from the cache', async () => {
+      const first = await cachedAdapter.execute({ query: 'auth' }, execContext);
+      const second = await cachedAdapter.execute({ query: '  auth ' }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledTimes(1);
+      expect(first.metadata?.cached).toBe(false);
+      expect(second.metadata?.cached).toBe(true);
+      expect(second.data).toEqual(first.data);
+      expect(cache.stats()).toMatchObject({ hits: 1, misses: 1 });
+    });
+
+    it('should search again when parameters differ', async () => {
+      await cachedAdapter.execute({ query: 'auth' }, execContext);
+      await cachedAdapter.execute({ query: 'auth', limit: 1 }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledTimes(2);
+    });
+
+    it('should search again after a reindex', async () => {
+      await cachedAdapter.execute({ query: 'auth' }, execContext);
+      vi.mocked(mockSearchService.getIndexVersion).mockResolvedValue('v2');
+      const result = await cachedAdapter.execute({ query: 'auth' }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledTimes(2);
+      expect(result.metadata?.cached).toBe(false);
+    });
+
+    it('should bypass the cache for debug queries', async () => {
+      await cachedAdapter.execute({ query: 'auth' }, execContext);
+      const result = await cachedAdapter.execute({ query: 'auth', debug: true }, execContext);
+      await cachedAdapter.execute({ query: 'auth', debug: true }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledTimes(3);
+      expect(result.metadata?.cached).toBe(false);
+      expect(cache.stats()).toMatchObject({ hits: 0, misses: 1, size: 1 });
+    });
+
+    it('should bypass the cache for feedback sessions', async () => {
+      await cachedAdapter.execute({ query: 'auth', session: 'explore-auth' }, execContext);
+      await cachedAdapter.execute({ query: 'auth', session: 'explore-auth' }, execContext);
+
+      expect(mockSearchService.search).toHaveBeenCalledTimes(2);
+      expect(cache.stats()).toMatchObject({ size: 0 });
+    });
   });
 
   describe('Token Estimation', () => {
@@ -0,0 +1,111 @@
+import type { SearchService, SimilarCodeResult } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { SimilarAdapter } from '../built-in/similar-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('SimilarAdapter', () => {
+  const similar: SimilarCodeResult = {
+    reference: {
+      id: 'client/retry.go:retryRequest:10',
+      score: 1,
+      metadata: { name: 'retryRequest', type: 'function', path: 'client/retry.go', startLine: 10 },
+    },
+    nearIdentical: [
+      {
+        id: 'server/retry.go:retryCall:4',
+        score: 0.97,
+        nearIdentical: true,
+        metadata: { name: 'retryCall', type: 'function', path: 'server/retry.go', startLine: 4 },
+      },
+    ],
+    related: [
+      {
+        id: 'client/backoff.go:Backoff:7',
+        score: 0.81,
+        nearIdentical: false,
+        metadata: { name: 'Backoff', type: 'function', path: 'client/backoff.go', startLine: 7 },
+      },
+    ],
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: SimilarAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      findSimilarCode: vi.fn().mockResolvedValue(similar),
+    } as unknown as SearchService;
+
+    adapter = new SimilarAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_similar tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_similar');
+    expect(definition.inputSchema.properties).toHaveProperty('symbol');
+    expect(definition.inputSchema.properties).toHaveProperty('snippet');
+    expect(definition.inputSchema.properties).toHaveProperty('threshold');
+  });
+
+  it('should separate near-identical and related matches', async () => {
+    const result = await adapter.execute({ symbol: 'retryRequest', limit: 5 }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.findSimilarCode).toHaveBeenCalledWith(
+      { symbol: 'retryRequest', path: undefined, snippet: undefined },
+      { limit: 5, threshold: 0.75, nearIdenticalThreshold: 0.95 }
+    );
+
+    const data = result.data as string;
+    expect(data).toContain('Reference: `client/retry.go:10`');
+    expect(data.indexOf('## Near-Identical')).toBeLessThan(data.indexOf('**retryCall**'));
+    expect(data.indexOf('## Related')).toBeLessThan(data.indexOf('**Backoff**'));
+    expect(data).toContain('[97%]');
+  });
+
+  it('should accept a snippet', async () => {
+    vi.mocked(mockSearchService.findSimilarCode).mockResolvedValue({
+      reference: null,
+      nearIdentical: [],
+      related: [],
+    });
+
+    const result = await adapter.execute({ snippet: 'return do(3)' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(result.data).toContain('# Similar to snippet');
+    expect(result.data).toContain('No similar code found');
+  });
+
+  it('should report unknown symbols', async () => {
+    vi.mocked(mockSearchService.findSimilarCode).mockResolvedValue({
+      reference: null,
+      nearIdentical: [],
+      related: [],
+    });
+
+    const result = await adapter.execute({ symbol: 'missing' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should require a symbol or snippet', async () => {
+    const result = await adapter.execute({ limit: 5 }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('INVALID_PARAMS');
+  });
+});
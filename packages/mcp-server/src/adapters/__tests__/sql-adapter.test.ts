@@ -0,0 +1,112 @@
+import type { SearchResult, SearchService, SqlQueryMap } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { SqlAdapter } from '../built-in/sql-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('SqlAdapter', () => {
+  const symbol = (name: string, path: string, startLine: number): SearchResult => ({
+    id: `${path}:${name}:${startLine}`,
+    score: 1,
+    metadata: { name, type: 'method', path, language: 'go', startLine },
+  });
+  const queryMap: SqlQueryMap = {
+    groups: [
+      {
+        package: 'internal/store',
+        queries: [
+          {
+            operation: 'select',
+            query: 'SELECT id, email FROM users WHERE id = $1',
+            tables: ['users'],
+            runBy: symbol('UserStore.Get', 'internal/store/users.go', 14),
+            line: 16,
+          },
+        ],
+      },
+    ],
+    tables: [{ name: 'users', queries: 1 }],
+    total: 1,
+    omitted: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: SqlAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getSqlQueries: vi.fn().mockResolvedValue(queryMap),
+    } as unknown as SearchService;
+
+    adapter = new SqlAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_sql tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_sql');
+    expect(toolDefinition.inputSchema.required).toEqual([]);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('table');
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('operation');
+  });
+
+  it('should list the functions querying a table', async () => {
+    const output = await adapter.execute({ table: 'users' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getSqlQueries).toHaveBeenCalledWith({
+      table: 'users',
+      operation: undefined,
+      pathPrefix: undefined,
+      includeTests: false,
+      limit: 200,
+    });
+
+    const data = output.data as string;
+    expect(data).toContain('# SQL Queries (1 in 1 package)');
+    expect(data).toContain('**Tables:** users (1)');
+    expect(data).toContain('- **SELECT** users in UserStore.Get (internal/store/users.go:16)');
+    expect(data).toContain('  - `SELECT id, email FROM users WHERE id = $1`');
+  });
+
+  it('should explain an empty result', async () => {
+    vi.mocked(mockSearchService.getSqlQueries).mockResolvedValue({
+      groups: [],
+      tables: [],
+      total: 0,
+      omitted: 0,
+    });
+
+    const unfiltered = await adapter.execute({}, mockContext);
+    expect(unfiltered.data).toContain('No SQL queries found');
+
+    const filtered = await adapter.execute({ table: 'invoices' }, mockContext);
+    expect(filtered.data).toContain('No SQL queries match these filters');
+  });
+
+  it('should reject an unknown operation', async () => {
+    const output = await adapter.execute({ operation: 'merge' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getSqlQueries).not.toHaveBeenCalled();
+  });
+
+  it('should handle listing failures', async () => {
+    vi.mocked(mockSearchService.getSqlQueries).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({}, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('SQL_FAILED');
+  });
+});
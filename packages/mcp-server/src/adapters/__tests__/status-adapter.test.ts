@@ -348,6 +348,99 @@ describe('StatusAdapter', () => {
       });
     });
 
+    describe('index freshness', () => {
+      const staleReport = {
+        freshness: {
+          trackedFiles: 39,
+          changed: ['src/a.ts', 'src/b.ts', 'src/c.ts'],
+          added: ['src/d.ts'],
+          deleted: [],
+          staleFiles: 4,
+          stalePercent: 10,
+          checkedAt: '2025-11-24T09:00:00Z',
+        },
+      };
+
+      it('should report staleness in the summary and repo sections', async () => {
+        const withFreshness = new StatusAdapter({
+          statsService: mockStatsService,
+          repositoryPath: '/test/repo',
+          vectorStorePath: '/test/.dev-agent/vectors.lance',
+          freshness: { latest: staleReport },
+        });
+
+        const summary = await withFreshness.execute({}, mockExecutionContext);
+        expect(summary.data).toContain(
+          '**Freshness:** Index is 10% stale (3 changed, 1 added, 0 deleted of 40 files)'
+        );
+
+        const repo = await withFreshness.execute({ section: 'repo' }, mockExecutionContext);
+        expect(repo.data).toContain('Index is 10% stale');
+      });
+
+      it('should warn in health checks when the index is stale', async () => {
+        const withFreshness = new StatusAdapter({
+          statsService: mockStatsService,
+          repositoryPath: '/test/repo',
+          vectorStorePath: '/test/.dev-agent/vectors.lance',
+          freshness: { latest: staleReport },
+        });
+
+        const result = await withFreshness.execute(
+          { section: 'health', format: 'verbose' },
+          mockExecutionContext
+        );
+        expect(result.data).toContain('⚠️ **Index Freshness:** Index is 10% stale');
+        expect(result.data).toContain('dev update');
+      });
+
+      it('should omit freshness until a check has finished', async () => {
+        const pending = new StatusAdapter({
+          statsService: mockStatsService,
+          repositoryPath: '/test/repo',
+          vectorStorePath: '/test/.dev-agent/vectors.lance',
+          freshness: { latest: undefined },
+        });
+
+        const result = await pending.execute({}, mockExecutionContext);
+        expect(result.data).not.toContain('Freshness');
+      });
+    });
+
+    describe('reindex jobs', () => {
+      it('should report the latest reindex job', async () => {
+        const withReindex = new StatusAdapter({
+          statsService: mockStatsService,
+          repositoryPath: '/test/repo',
+          vectorStorePath: '/test/.dev-agent/vectors.lance',
+          reindexJobs: {
+            latest: {
+              id: 'reindex-2',
+              mode: 'full',
+              status: 'running',
+              requests: 1,
+              queuedAt: new Date('2025-11-24T09:00:00Z'),
+              progress: {
+                phase: 'embedding',
+                filesProcessed: 120,
+                totalFiles: 300,
+                documentsIndexed: 800,
+                percentComplete: 45,
+              },
+            },
+          },
+        });
+
+        const summary = await withReindex.execute({}, mockExecutionContext);
+        expect(summary.data).toContain(
+          '**Reindex:** reindex-2 running (embedding, 45%: 120/300 files)'
+        );
+
+        const repo = await withReindex.execute({ section: 'repo' }, mockExecutionContext);
+        expect(repo.data).toContain('**Reindex:** reindex-2 running');
+      });
+    });
+
     describe('error handling', () => {
       it('should handle errors during status generation', async () => {
         vi.mocked(mockStatsService.getStats).mockRejectedValue(new Error('Database error'));
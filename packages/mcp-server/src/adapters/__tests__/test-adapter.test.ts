@@ -0,0 +1,109 @@
+import type { SearchResult, SearchService, SymbolTests } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { TestAdapter } from '../built-in/test-adapter';
+import type { ToolExecutionContext } from '../types';
+
+function fn(name: string, path: string, startLine: number, exported = false): SearchResult {
+  return {
+    id: `${path}:${name}:${startLine}`,
+    score: 1,
+    metadata: { name, type: 'function', path, language: 'go', startLine, exported },
+  };
+}
+
+describe('TestAdapter', () => {
+  const tests: SymbolTests = {
+    target: fn('Retry', 'retry/retry.go', 10, true),
+    direct: [{ test: fn('TestRetry', 'retry/retry_test.go', 5), depth: 1, via: [] }],
+    transitive: [
+      {
+        test: fn('TestClientDo', 'client/client_test.go', 8),
+        depth: 3,
+        via: [fn('Client.Do', 'client/client.go', 20), fn('Client.retry', 'client/client.go', 40)],
+      },
+    ],
+    depth: 5,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: TestAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getSymbolTests: vi.fn().mockResolvedValue(tests),
+    } as unknown as SearchService;
+
+    adapter = new TestAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_test tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_test');
+    expect(definition.inputSchema.required).toEqual(['symbol']);
+    expect(definition.inputSchema.properties).toHaveProperty('depth');
+  });
+
+  it('should format direct and transitive tests', async () => {
+    const result = await adapter.execute({ symbol: 'Retry' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.getSymbolTests).toHaveBeenCalledWith('Retry', {
+      path: undefined,
+      depth: 5,
+    });
+
+    const data = result.data as string;
+    expect(data).toContain('# Tests for Retry');
+    expect(data).toContain('- **TestRetry** - retry/retry_test.go:5');
+    expect(data).toContain('via Client.Do → Client.retry');
+  });
+
+  it('should flag untested symbols', async () => {
+    vi.mocked(mockSearchService.getSymbolTests).mockResolvedValue({
+      ...tests,
+      direct: [],
+      transitive: [],
+    });
+
+    const result = await adapter.execute({ symbol: 'Retry', depth: 2 }, mockContext);
+
+    expect(result.data).toContain('**Untested:** no test reaches Retry within 5 calls.');
+  });
+
+  it('should reject depth outside 1-10', async () => {
+    const result = await adapter.execute({ symbol: 'Retry', depth: 11 }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('INVALID_PARAMS');
+  });
+
+  it('should report unknown symbols', async () => {
+    vi.mocked(mockSearchService.getSymbolTests).mockResolvedValue(null);
+
+    const result = await adapter.execute({ symbol: 'missing' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should handle search failures', async () => {
+    vi.mocked(mockSearchService.getSymbolTests).mockRejectedValue(new Error('index missing'));
+
+    const result = await adapter.execute({ symbol: 'Retry' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('TEST_LOOKUP_FAILED');
+  });
+});
@@ -0,0 +1,124 @@
+import type { SearchService, SymbolUsages } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { UsageAdapter } from '../built-in/usage-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('UsageAdapter', () => {
+  const usages: SymbolUsages = {
+    target: {
+      id: 'backoff/backoff.go:NewExpBackoff:10',
+      score: 1,
+      metadata: {
+        name: 'NewExpBackoff',
+        type: 'function',
+        path: 'backoff/backoff.go',
+        language: 'go',
+        startLine: 10,
+        endLine: 12,
+      },
+    },
+    examples: [
+      {
+        caller: {
+          id: 'client/client.go:NewClient:20',
+          score: 1,
+          metadata: {
+            name: 'NewClient',
+            type: 'function',
+            path: 'client/client.go',
+            language: 'go',
+            startLine: 20,
+            endLine: 23,
+          },
+        },
+        line: 21,
+        call: 'backoff.NewExpBackoff(cfg.Base)',
+        shape: '(selector)',
+        source: '\tb := backoff.NewExpBackoff(cfg.Base)',
+        isTest: false,
+      },
+    ],
+    documentedExamples: [],
+    testExamples: [],
+    totalCalls: 4,
+    totalTestCalls: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: UsageAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getSymbolUsages: vi.fn().mockResolvedValue(usages),
+    } as unknown as SearchService;
+
+    adapter = new UsageAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_usage tool', () => {
+    const definition = adapter.getToolDefinition();
+
+    expect(definition.name).toBe('dev_usage');
+    expect(definition.inputSchema.required).toEqual(['symbol']);
+    expect(definition.inputSchema.properties).toHaveProperty('limit');
+    expect(definition.inputSchema.properties).toHaveProperty('includeTests');
+  });
+
+  it('should format usage examples', async () => {
+    const result = await adapter.execute({ symbol: 'NewExpBackoff' }, mockContext);
+
+    expect(result.success).toBe(true);
+    expect(mockSearchService.getSymbolUsages).toHaveBeenCalledWith('NewExpBackoff', {
+      path: undefined,
+      limit: 5,
+      includeTests: true,
+    });
+
+    const data = result.data as string;
+    expect(data).toContain('# Usage of NewExpBackoff');
+    expect(data).toContain('## Examples (1 of 4 call sites)');
+    expect(data).toContain('`backoff.NewExpBackoff(cfg.Base)` - args: (selector)');
+  });
+
+  it('should pass path, limit, and test inclusion through', async () => {
+    await adapter.execute(
+      { symbol: 'NewExpBackoff', path: 'backoff/', limit: 10, includeTests: false },
+      mockContext
+    );
+
+    expect(mockSearchService.getSymbolUsages).toHaveBeenCalledWith('NewExpBackoff', {
+      path: 'backoff/',
+      limit: 10,
+      includeTests: false,
+    });
+  });
+
+  it('should report unknown symbols', async () => {
+    vi.mocked(mockSearchService.getSymbolUsages).mockResolvedValue(null);
+
+    const result = await adapter.execute({ symbol: 'missing' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should handle search failures', async () => {
+    vi.mocked(mockSearchService.getSymbolUsages).mockRejectedValue(new Error('index missing'));
+
+    const result = await adapter.execute({ symbol: 'NewExpBackoff' }, mockContext);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.code).toBe('USAGE_FAILED');
+  });
+});
@@ -0,0 +1,95 @@
+import type { Definitions, SearchService } from '@lytics/dev-agent-core';
+import { beforeEach, describe, expect, it, vi } from 'vitest';
+import { WhereisAdapter } from '../built-in/whereis-adapter';
+import type { ToolExecutionContext } from '../types';
+
+describe('WhereisAdapter', () => {
+  const definition = (dir: string, line: number) => ({
+    symbol: {
+      id: `${dir}/get.go:Get:${line}`,
+      score: 1,
+      metadata: {
+        name: 'Get',
+        type: 'function',
+        path: `${dir}/get.go`,
+        language: 'go',
+        startLine: line,
+        signature: 'func Get(key string) ([]byte, error)',
+      },
+    },
+    package: dir,
+    isTest: false,
+  });
+  const result: Definitions = {
+    name: 'Get',
+    definitions: [definition('internal/cache', 5), definition('internal/store', 12)],
+    omittedTests: 0,
+  };
+
+  let mockSearchService: SearchService;
+  let adapter: WhereisAdapter;
+  let mockContext: ToolExecutionContext;
+
+  beforeEach(() => {
+    mockSearchService = {
+      getDefinitions: vi.fn().mockResolvedValue(result),
+    } as unknown as SearchService;
+
+    adapter = new WhereisAdapter({ searchService: mockSearchService });
+
+    mockContext = {
+      logger: {
+        debug: vi.fn(),
+        info: vi.fn(),
+        warn: vi.fn(),
+        error: vi.fn(),
+      },
+      requestId: 'test-request',
+    } as unknown as ToolExecutionContext;
+  });
+
+  it('should define the dev_whereis tool', () => {
+    const toolDefinition = adapter.getToolDefinition();
+
+    expect(toolDefinition.name).toBe('dev_whereis');
+    expect(toolDefinition.inputSchema.required).toEqual(['name']);
+    expect(toolDefinition.inputSchema.properties).toHaveProperty('includeTests');
+  });
+
+  it('should list every definition with its package', async () => {
+    const output = await adapter.execute({ name: 'Get' }, mockContext);
+
+    expect(output.success).toBe(true);
+    expect(mockSearchService.getDefinitions).toHaveBeenCalledWith('Get', { includeTests: false });
+
+    const data = output.data as string;
+    expect(data).toContain('# Definitions of Get (2 in 2 packages)');
+    expect(data).toContain('## internal/cache.Get - internal/cache/get.go:5 (function)');
+    expect(data).toContain('## internal/store.Get - internal/store/get.go:12 (function)');
+  });
+
+  it('should report unknown names', async () => {
+    vi.mocked(mockSearchService.getDefinitions).mockResolvedValue(null);
+
+    const output = await adapter.execute({ name: 'Missing' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('SYMBOL_NOT_FOUND');
+  });
+
+  it('should reject invalid arguments', async () => {
+    const output = await adapter.execute({ name: '', fuzzy: true }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(mockSearchService.getDefinitions).not.toHaveBeenCalled();
+  });
+
+  it('should handle lookup failures', async () => {
+    vi.mocked(mockSearchService.getDefinitions).mockRejectedValue(new Error('index missing'));
+
+    const output = await adapter.execute({ name: 'Get' }, mockContext);
+
+    expect(output.success).toBe(false);
+    expect(output.error?.code).toBe('WHEREIS_FAILED');
+  });
+});
@@ -0,0 +1,161 @@
+/**
+ * Call Path Adapter
+ * Traces how one symbol reaches another through calls via the dev_callpath tool
+ */
+
+import { formatCallPaths, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { CallPathArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Call path adapter configuration
+ */
+export interface CallPathAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Call Path Adapter
+ * Implements the dev_callpath tool: shortest call paths between two symbols
+ */
+export class CallPathAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'callpath-adapter',
+    version: '1.0.0',
+    description: 'Call path adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: CallPathAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('CallPathAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_callpath',
+      description:
+        'Find how one symbol reaches another through calls: the shortest call path (or up ' +
+        'to `limit` paths, shortest first), each call with its file, line, and source ' +
+        'line. Says so explicitly when there is no path. Use when tracing how a request ' +
+        'flows from a handler down to a particular low-level call.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          from: {
+            type: 'string',
+            description: 'Symbol to start from (e.g., "Server.Handle")',
+          },
+          to: {
+            type: 'string',
+            description: 'Symbol to reach (e.g., "Store.Save")',
+          },
+          fromPath: {
+            type: 'string',
+            description: 'Path prefix to pick between start symbols with the same name',
+          },
+          toPath: {
+            type: 'string',
+            description: 'Path prefix to pick between target symbols with the same name',
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum paths (default: 1, the shortest)',
+            minimum: 1,
+            maximum: 10,
+            default: 1,
+          },
+          maxDepth: {
+            type: 'number',
+            description: 'Longest path searched, in calls (default: 6)',
+            minimum: 1,
+            maximum: 12,
+            default: 6,
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Follow calls made from test files (default: false)',
+            default: false,
+          },
+        },
+        required: ['from', 'to'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(CallPathArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { from, to, ...options } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Finding call paths', { from, to, ...options });
+
+      const result = await this.searchService.getCallPaths(from, to, options);
+      const missing = !result.from ? from : !result.to ? to : null;
+      if (missing !== null) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${missing}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const content = formatCallPaths(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Call paths found', {
+        paths: result.paths.length,
+        shortest: result.shortest,
+        truncated: result.truncated,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Finding call paths failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'CALLPATH_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 1;
+    return 100 + limit * 6 * 40;
+  }
+}
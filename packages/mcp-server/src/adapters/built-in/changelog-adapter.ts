@@ -0,0 +1,175 @@
+/**
+ * Changelog Adapter
+ * Generates API-level release notes between two revisions via the dev_changelog tool
+ */
+
+import { formatChangelog, generateChangelog, type RevisionReader } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { ChangelogArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Changelog adapter configuration
+ */
+export interface ChangelogAdapterConfig {
+  /**
+   * Repository root path
+   */
+  repositoryPath: string;
+
+  /**
+   * Git extractor used to read revisions (default: LocalGitExtractor)
+   */
+  gitExtractor?: RevisionReader;
+
+  /**
+   * Default token budget
+   */
+  defaultTokenBudget?: number;
+}
+
+/**
+ * Changelog Adapter
+ * Implements the dev_changelog tool: exported API changes grouped by package,
+ * with breaking changes marked, as markdown for a release
+ */
+export class ChangelogAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'changelog-adapter',
+    version: '1.0.0',
+    description: 'API-level changelog adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private config: ChangelogAdapterConfig & { defaultTokenBudget: number };
+
+  constructor(config: ChangelogAdapterConfig) {
+    super();
+    this.config = {
+      ...config,
+      defaultTokenBudget: config.defaultTokenBudget ?? 4000,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('ChangelogAdapter initialized', {
+      repositoryPath: this.config.repositoryPath,
+      defaultTokenBudget: this.config.defaultTokenBudget,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_changelog',
+      description:
+        'Generate release notes for the exported API between two revisions (usually tags): ' +
+        'added and removed APIs, changed signatures, and new deprecations, grouped by package ' +
+        'with breaking changes marked. Returns markdown ready to paste into a release.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          base: {
+            type: 'string',
+            description: 'Previous release: tag, branch, or commit (e.g., "v1.2.0")',
+          },
+          head: {
+            type: 'string',
+            description: 'New release to compare against base (default: "HEAD")',
+            default: 'HEAD',
+          },
+          path: {
+            type: 'string',
+            description: 'Only include packages under this path (e.g., "pkg/")',
+          },
+          tokenBudget: {
+            type: 'number',
+            description: `Maximum tokens for output (default: ${this.config.defaultTokenBudget})`,
+            minimum: 500,
+            maximum: 10000,
+            default: this.config.defaultTokenBudget,
+          },
+        },
+        required: ['base'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(ChangelogArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { base, head, path, tokenBudget } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Executing changelog', { base, head, path });
+
+      const changelog = await generateChangelog(
+        { repositoryPath: this.config.repositoryPath, base, head, pathPrefix: path },
+        this.config.gitExtractor
+      );
+
+      const content = this.fitToBudget(formatChangelog(changelog), tokenBudget);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Changelog completed', {
+        packages: changelog.packages.length,
+        breakingChanges: changelog.breakingChanges,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Changelog failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'CHANGELOG_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  /**
+   * Cut the notes at a line boundary once the token budget is reached
+   */
+  private fitToBudget(markdown: string, tokenBudget: number): string {
+    if (estimateTokensForText(markdown) <= tokenBudget) {
+      return markdown;
+    }
+
+    const lines = markdown.split('\n');
+    const kept: string[] = [];
+    const reserveTokens = 50; // For footer
+    let tokensUsed = 0;
+    for (const line of lines) {
+      const lineTokens = estimateTokensForText(`${line}\n`);
+      if (tokensUsed + lineTokens + reserveTokens > tokenBudget && kept.length > 0) break;
+      kept.push(line);
+      tokensUsed += lineTokens;
+    }
+
+    kept.push('', `*... ${lines.length - kept.length} more lines (token budget reached)*`);
+    return kept.join('\n');
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { tokenBudget = this.config.defaultTokenBudget } = args;
+    return tokenBudget as number;
+  }
+}
@@ -0,0 +1,140 @@
+/**
+ * Constraints Adapter
+ * Lists generic constraints and the code using them via the dev_constraints tool
+ */
+
+import { formatConstraintUsages, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { ConstraintsArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Constraints adapter configuration
+ */
+export interface ConstraintsAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Constraints Adapter
+ * Implements the dev_constraints tool: constraint interfaces with their type
+ * sets, and the generic functions and types whose type parameters use them
+ */
+export class ConstraintsAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'constraints-adapter',
+    version: '1.0.0',
+    description: 'Generic constraint usage adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: ConstraintsAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('ConstraintsAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_constraints',
+      description:
+        'List Go generic constraints and what uses them: constraint interfaces with their ' +
+        'type sets (union and ~ terms, expanded through nested constraints), method ' +
+        'interfaces used as constraints, and the generic functions and types whose type ' +
+        'parameters name each one. Answers "what uses the Ordered constraint".',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          name: {
+            type: 'string',
+            description:
+              'Only this constraint (e.g., "Ordered"; "Ordered" also matches "constraints.Ordered")',
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include constraints and users in test files (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum constraints to list (default: 100)',
+            minimum: 1,
+            maximum: 500,
+            default: 100,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(ConstraintsArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { name, includeTests, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Listing generic constraints', { name, includeTests, limit });
+
+      const constraintMap = await this.searchService.getConstraintUsages({
+        name,
+        includeTests,
+        limit,
+      });
+
+      let content = formatConstraintUsages(constraintMap);
+      if (constraintMap.total === 0) {
+        content = name
+          ? `No constraint named ${name} is defined or used in the indexed code.\n`
+          : 'No generic constraints found. Re-run `dev index` if the index predates ' +
+            'constraint indexing.\n';
+      }
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Generic constraints listed', {
+        constraints: constraintMap.total,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Generic constraint listing failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'CONSTRAINTS_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 100;
+    return Math.min(limit, 30) * 80;
+  }
+}
@@ -0,0 +1,170 @@
+/**
+ * Context Adapter
+ * Assembles the code around a symbol via the dev_context tool
+ */
+
+import { formatSymbolContext, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { ContextArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Context adapter configuration
+ */
+export interface ContextAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default token budget
+   */
+  defaultTokenBudget?: number;
+}
+
+/**
+ * Context Adapter
+ * Implements the dev_context tool for "everything needed to understand this function"
+ */
+export class ContextAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'context-adapter',
+    version: '1.0.0',
+    description: 'Symbol context assembly adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<ContextAdapterConfig, 'searchService'>>;
+
+  constructor(config: ContextAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultTokenBudget: config.defaultTokenBudget ?? 4000,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('ContextAdapter initialized', {
+      defaultTokenBudget: this.config.defaultTokenBudget,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_context',
+      description:
+        'Get everything needed to understand a symbol: its source plus its callers, callees, ' +
+        'and the types it references, expanded to a hop depth and trimmed to a token budget ' +
+        '(closest hops first). ' +
+        'Use when you need to read or change a specific function, not to find one.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Indexed symbol name (e.g., "retryRequest" or "Client.Do")',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          depth: {
+            type: 'number',
+            description: 'How many caller/callee/type hops to follow (default: 1)',
+            minimum: 1,
+            maximum: 3,
+            default: 1,
+          },
+          tokenBudget: {
+            type: 'number',
+            description: `Maximum tokens of source (default: ${this.config.defaultTokenBudget})`,
+            minimum: 500,
+            maximum: 20000,
+            default: this.config.defaultTokenBudget,
+          },
+          includeTypes: {
+            type: 'boolean',
+            description: 'Include types referenced by each symbol (default: true)',
+            default: true,
+          },
+        },
+        required: ['symbol'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(ContextArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { symbol, path, depth, tokenBudget, includeTypes } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Assembling symbol context', { symbol, path, depth, tokenBudget });
+
+      const result = await this.searchService.getSymbolContext(symbol, {
+        path,
+        depth,
+        maxTokens: tokenBudget,
+        includeTypes,
+      });
+
+      if (!result) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${symbol}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const content = formatSymbolContext(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Symbol context assembled', {
+        symbol,
+        depth,
+        entries: result.entries.length,
+        omitted: result.omitted,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Symbol context failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'CONTEXT_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { tokenBudget = this.config.defaultTokenBudget } = args;
+    return (tokenBudget as number) + 100;
+  }
+}
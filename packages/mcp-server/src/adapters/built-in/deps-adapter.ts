@@ -0,0 +1,155 @@
+/**
+ * Deps Adapter
+ * Lists Go module dependencies and the code importing them via the dev_deps tool
+ */
+
+import { formatDependencies, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { DepsArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Deps adapter configuration
+ */
+export interface DepsAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Deps Adapter
+ * Implements the dev_deps tool: the modules each Go module requires, at the
+ * version in effect after replace directives, and the packages importing them
+ */
+export class DepsAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'deps-adapter',
+    version: '1.0.0',
+    description: 'Go module dependency adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: DepsAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('DepsAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_deps',
+      description:
+        'List Go module dependencies from go.mod (and go.sum for pre-1.17 modules): each ' +
+        'module required, direct or indirect, at the version in effect with replace ' +
+        'directives applied (replaced modules are flagged), and the indexed packages and ' +
+        'files importing it. Answers "do we depend on golang.org/x/net, at what version, ' +
+        'and what code touches it".',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          module: {
+            type: 'string',
+            description:
+              'Only modules whose path (or replacement) contains this, e.g. "golang.org/x/net" ' +
+              'or "jwt"',
+          },
+          includeIndirect: {
+            type: 'boolean',
+            description: 'Include indirect dependencies (default: true)',
+            default: true,
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Count test files among the importers (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum dependencies to list (default: 200)',
+            minimum: 1,
+            maximum: 1000,
+            default: 200,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(DepsArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { module, includeIndirect, includeTests, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Listing Go dependencies', {
+        module,
+        includeIndirect,
+        includeTests,
+        limit,
+      });
+
+      const dependencyMap = await this.searchService.getDependencies({
+        module,
+        includeIndirect,
+        includeTests,
+        limit,
+      });
+
+      let content = formatDependencies(dependencyMap);
+      if (dependencyMap.total === 0) {
+        content =
+          dependencyMap.modules === 0
+            ? 'No go.mod found in the repository.\n'
+            : module
+              ? `No Go module in the repository requires a module matching "${module}".\n`
+              : "The repository's Go modules have no dependencies.\n";
+      }
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Go dependencies listed', {
+        dependencies: dependencyMap.total,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Go dependency listing failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'DEPS_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 200;
+    return Math.min(limit, 50) * 40;
+  }
+}
@@ -0,0 +1,257 @@
+/**
+ * Diff Adapter
+ * Provides symbol-level diffs between two git revisions via the dev_diff tool
+ */
+
+import {
+  diffRevisions,
+  type RevisionDiff,
+  type RevisionReader,
+  type SymbolSnapshot,
+} from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { DiffArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Diff adapter configuration
+ */
+export interface DiffAdapterConfig {
+  /**
+   * Repository root path
+   */
+  repositoryPath: string;
+
+  /**
+   * Git extractor used to read revisions (default: LocalGitExtractor)
+   */
+  gitExtractor?: RevisionReader;
+
+  /**
+   * Default token budget
+   */
+  defaultTokenBudget?: number;
+}
+
+/**
+ * Diff Adapter
+ * Implements the dev_diff tool for comparing two revisions symbol by symbol
+ */
+export class DiffAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'diff-adapter',
+    version: '1.0.0',
+    description: 'Symbol-level revision diff adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private config: DiffAdapterConfig & { defaultTokenBudget: number };
+
+  constructor(config: DiffAdapterConfig) {
+    super();
+    this.config = {
+      ...config,
+      defaultTokenBudget: config.defaultTokenBudget ?? 2000,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('DiffAdapter initialized', {
+      repositoryPath: this.config.repositoryPath,
+      defaultTokenBudget: this.config.defaultTokenBudget,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_diff',
+      description:
+        'Compare two git revisions at the symbol level: added, removed, and renamed functions/types, ' +
+        'plus signature changes. Use for reviewing a branch or release without reading raw diffs.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          base: {
+            type: 'string',
+            description: 'Base revision: branch, tag, or commit (e.g., "main", "v1.2.0", "HEAD~5")',
+          },
+          head: {
+            type: 'string',
+            description: 'Head revision to compare against base (default: "HEAD")',
+            default: 'HEAD',
+          },
+          path: {
+            type: 'string',
+            description: 'Only compare files under this path (e.g., "packages/core/")',
+          },
+          format: {
+            type: 'string',
+            enum: ['compact', 'verbose'],
+            description:
+              'Output format: "compact" lists symbol names (default), "verbose" includes signatures',
+            default: 'compact',
+          },
+          tokenBudget: {
+            type: 'number',
+            description: `Maximum tokens for output (default: ${this.config.defaultTokenBudget})`,
+            minimum: 500,
+            maximum: 10000,
+            default: this.config.defaultTokenBudget,
+          },
+        },
+        required: ['base'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(DiffArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { base, head, path, format, tokenBudget } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Executing diff', { base, head, path });
+
+      const diff = await diffRevisions(
+        { repositoryPath: this.config.repositoryPath, base, head, pathPrefix: path },
+        this.config.gitExtractor
+      );
+
+      const content = this.formatDiff(diff, format === 'verbose', tokenBudget);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Diff completed', {
+        filesChanged: diff.filesChanged.length,
+        added: diff.added.length,
+        removed: diff.removed.length,
+        changed: diff.changed.length,
+        renamed: diff.renamed.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Diff failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'DIFF_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  /**
+   * Format a revision diff as markdown within the token budget
+   */
+  private formatDiff(diff: RevisionDiff, verbose: boolean, tokenBudget: number): string {
+    const lines: string[] = [];
+    lines.push(`# Symbol Diff: ${diff.base}..${diff.head}`);
+    lines.push(
+      `${diff.filesChanged.length} files changed | ` +
+        `+${diff.added.length} added, -${diff.removed.length} removed, ` +
+        `${diff.renamed.length} renamed, ${diff.changed.length} signature changes, ` +
+        `${diff.tagsChanged.length} struct tag changes`
+    );
+
+    const total =
+      diff.added.length +
+      diff.removed.length +
+      diff.renamed.length +
+      diff.changed.length +
+      diff.tagsChanged.length;
+    if (total === 0) {
+      lines.push('');
+      lines.push('*No symbol-level changes*');
+      return lines.join('\n');
+    }
+
+    const entries: Array<{ section: string; lines: string[] }> = [
+      ...diff.changed.map((c) => ({
+        section: 'Signature Changes',
+        lines: [
+          `- ${this.formatSymbol(c.after)}`,
+          `  - before: \`${c.before.signature ?? ''}\``,
+          `  - after: \`${c.after.signature ?? ''}\``,
+        ],
+      })),
+      ...diff.tagsChanged.map((t) => ({
+        section: 'Struct Tag Changes',
+        lines: [
+          `- \`${t.struct.name}.${t.field.name}\` - ${t.struct.file}:${t.struct.startLine}: ` +
+            `${t.before ? `\`${t.before}\`` : '(untagged)'} → ` +
+            `${t.after ? `\`${t.after}\`` : '(untagged)'}`,
+        ],
+      })),
+      ...diff.renamed.map((r) => ({
+        section: 'Renamed',
+        lines: [
+          `- \`${r.before.name}\` → ${this.formatSymbol(r.after)}`,
+          ...(verbose && r.after.signature ? [`  - \`${r.after.signature}\``] : []),
+        ],
+      })),
+      ...diff.removed.map((s) => ({ section: 'Removed', lines: this.formatEntry(s, verbose) })),
+      ...diff.added.map((s) => ({ section: 'Added', lines: this.formatEntry(s, verbose) })),
+    ];
+
+    let tokensUsed = estimateTokensForText(lines.join('\n'));
+    const reserveTokens = 50; // For footer
+    let currentSection = '';
+
+    for (let i = 0; i < entries.length; i++) {
+      const entry = entries[i];
+      const block =
+        entry.section !== currentSection
+          ? ['', `## ${entry.section}`, ...entry.lines]
+          : entry.lines;
+      const blockTokens = estimateTokensForText(block.join('\n'));
+
+      if (tokensUsed + blockTokens + reserveTokens > tokenBudget && i > 0) {
+        lines.push('');
+        lines.push(`*... ${entries.length - i} more changes (token budget reached)*`);
+        break;
+      }
+
+      lines.push(...block);
+      currentSection = entry.section;
+      tokensUsed += blockTokens;
+    }
+
+    return lines.join('\n');
+  }
+
+  private formatEntry(symbol: SymbolSnapshot, verbose: boolean): string[] {
+    const lines = [`- ${this.formatSymbol(symbol)}`];
+    if (verbose && symbol.signature) {
+      lines.push(`  - \`${symbol.signature}\``);
+    }
+    return lines;
+  }
+
+  private formatSymbol(symbol: SymbolSnapshot): string {
+    const visibility = symbol.exported ? '' : ' (unexported)';
+    return `\`${symbol.name}\` (${symbol.type}) - ${symbol.file}:${symbol.startLine}${visibility}`;
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { tokenBudget = this.config.defaultTokenBudget } = args;
+    return tokenBudget as number;
+  }
+}
@@ -0,0 +1,155 @@
+/**
+ * Explain Adapter
+ * Explains what a symbol does and how it is used via the dev_explain tool
+ */
+
+import { formatSymbolExplanation, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { ExplainArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Explain adapter configuration
+ */
+export interface ExplainAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Explain Adapter
+ * Implements the dev_explain tool: a symbol's doc comment and signature, one
+ * or two usage examples, and its direct callees, curated for reading rather
+ * than exhaustive like dev_inspect
+ */
+export class ExplainAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'explain-adapter',
+    version: '1.0.0',
+    description: 'Symbol explanation adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: ExplainAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('ExplainAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_explain',
+      description:
+        'Answer "what does this do and how is it used" in one compact block: the doc ' +
+        'comment and signature, one or two usage examples (a Go Example function first, ' +
+        'then real call sites), and the direct callees with one-line summaries. Curated ' +
+        'for reading; use dev_inspect for everything about a symbol, dev_usage for all ' +
+        'call sites.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Indexed symbol name (e.g., "NewExpBackoff" or "Client.Do")',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          examples: {
+            type: 'number',
+            description: 'Usage examples to show (default: 2)',
+            minimum: 0,
+            maximum: 5,
+            default: 2,
+          },
+          maxCallees: {
+            type: 'number',
+            description: 'Maximum callees to list (default: 10)',
+            minimum: 0,
+            maximum: 50,
+            default: 10,
+          },
+        },
+        required: ['symbol'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(ExplainArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { symbol, path, examples, maxCallees } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Explaining symbol', { symbol, path, examples, maxCallees });
+
+      const explanation = await this.searchService.getSymbolExplanation(symbol, {
+        path,
+        examples,
+        maxCallees,
+      });
+
+      if (!explanation) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${symbol}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const content = formatSymbolExplanation(explanation);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Symbol explained', {
+        symbol,
+        examples: explanation.documentedExamples.length + explanation.examples.length,
+        callees: explanation.callees.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Symbol explanation failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'EXPLAIN_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const examples = typeof args.examples === 'number' ? args.examples : 2;
+    return 150 + examples * 120;
+  }
+}
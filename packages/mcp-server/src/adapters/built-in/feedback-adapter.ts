@@ -0,0 +1,164 @@
+/**
+ * Feedback Adapter
+ * Records relevance feedback for a search session via the dev_feedback tool
+ */
+
+import type { SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { FeedbackArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Feedback adapter configuration
+ */
+export interface FeedbackAdapterConfig {
+  /**
+   * Search service instance; it keeps the feedback dev_search reads
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Feedback Adapter
+ * Implements the dev_feedback tool: mark search results relevant or not so
+ * later dev_search calls in the same session are biased by them
+ */
+export class FeedbackAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'feedback-adapter',
+    version: '1.0.0',
+    description: 'Search relevance feedback adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: FeedbackAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('FeedbackAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_feedback',
+      description:
+        'Mark a dev_search result relevant (the one you picked) or not relevant for a session ' +
+        'id of your choosing. Later dev_search calls passing the same session are biased ' +
+        'toward relevant results and away from the others. Helps when a vague first query ' +
+        'is refined over several turns. Feedback lasts for the server session only and ' +
+        'never changes the index.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          session: {
+            type: 'string',
+            description: 'Session id, passed again as `session` to dev_search',
+          },
+          symbol: {
+            type: 'string',
+            description: 'Result to mark: a symbol name (e.g., "Server.CreateUser") or document ID',
+          },
+          relevant: {
+            type: 'boolean',
+            description: 'False to mark the result not relevant (default: true)',
+            default: true,
+          },
+          clear: {
+            type: 'boolean',
+            description: "Forget the session's feedback instead of marking a result",
+            default: false,
+          },
+        },
+        required: ['session'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(FeedbackArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { session, symbol, relevant, clear } = validation.data;
+
+    try {
+      const timer = startTimer();
+      let content: string;
+      if (clear) {
+        const cleared = this.searchService.clearFeedback(session);
+        content = cleared
+          ? `Cleared the feedback of session "${session}".\n`
+          : `Session "${session}" had no feedback.\n`;
+      } else {
+        const marked = await this.searchService.recordFeedback(session, symbol as string, relevant);
+        if (!marked) {
+          return {
+            success: false,
+            error: {
+              code: 'SYMBOL_NOT_FOUND',
+              message: `Symbol "${symbol}" not found in the index`,
+              recoverable: true,
+              suggestion: 'Pass a name or document ID exactly as dev_search returned it',
+            },
+          };
+        }
+        const feedback = this.searchService.getFeedback(session);
+        content = formatFeedback(session, marked.metadata, relevant, feedback);
+      }
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Search feedback recorded', { session, symbol, relevant, clear });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Recording search feedback failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'FEEDBACK_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(): number {
+    return 80;
+  }
+}
+
+function formatFeedback(
+  session: string,
+  marked: { name?: string; path?: string; startLine?: number },
+  relevant: boolean,
+  feedback: { relevant: string[]; nonRelevant: string[] }
+): string {
+  const verdict = relevant ? 'relevant' : 'not relevant';
+  return [
+    `Marked **${marked.name}** (${marked.path}:${marked.startLine}) ${verdict} ` +
+      `for session "${session}".`,
+    '',
+    `**Session feedback:** ${feedback.relevant.length} relevant, ` +
+      `${feedback.nonRelevant.length} not relevant`,
+    '',
+    `Pass \`session: "${session}"\` to dev_search to use it.`,
+    '',
+  ].join('\n');
+}
@@ -0,0 +1,189 @@
+/**
+ * Graph Adapter
+ * Exports the call graph around a symbol or package via the dev_graph tool
+ */
+
+import { formatCallGraph, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { GraphArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Graph adapter configuration
+ */
+export interface GraphAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Graph Adapter
+ * Implements the dev_graph tool: caller/callee and implements edges as DOT or JSON
+ */
+export class GraphAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'graph-adapter',
+    version: '1.0.0',
+    description: 'Call graph export adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: GraphAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('GraphAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_graph',
+      description:
+        'Export the call graph around a symbol, or across a package, as Graphviz DOT or a ' +
+        'JSON node/edge list. Nodes are package-qualified symbol names; edges are marked ' +
+        'as calls or implements (type to interface). Bound it by hop depth and leave out ' +
+        'standard library and third-party calls. Use it to render and share how code ' +
+        'fits together.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Start from this symbol (e.g., "Server.CreateUser")',
+          },
+          package: {
+            type: 'string',
+            description:
+              'Start from every symbol in this package directory (e.g., "internal/users")',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          depth: {
+            type: 'number',
+            description: 'Hops to follow from the starting symbols (default: 1)',
+            minimum: 0,
+            maximum: 5,
+            default: 1,
+          },
+          direction: {
+            type: 'string',
+            enum: ['callees', 'callers', 'both'],
+            description: 'Follow calls out, calls in, or both (default: both)',
+            default: 'both',
+          },
+          format: {
+            type: 'string',
+            enum: ['dot', 'json'],
+            description: 'Graphviz DOT or a JSON node/edge list (default: dot)',
+            default: 'dot',
+          },
+          includeExternal: {
+            type: 'boolean',
+            description:
+              'Include calls to symbols outside the index, such as the standard library ' +
+              '(default: false)',
+            default: false,
+          },
+          includeImplements: {
+            type: 'boolean',
+            description: 'Include implements edges between types and interfaces (default: true)',
+            default: true,
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include symbols in test files (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum nodes (default: 200)',
+            minimum: 1,
+            maximum: 1000,
+            default: 200,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(GraphArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { format, ...options } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Exporting call graph', { ...options, format });
+
+      const graph = await this.searchService.getCallGraph(options);
+      if (!graph) {
+        return {
+          success: false,
+          error: options.symbol
+            ? {
+                code: 'SYMBOL_NOT_FOUND',
+                message: `Symbol "${options.symbol}" not found in the index`,
+                recoverable: true,
+                suggestion: 'Use dev_lookup to find the exact symbol name',
+              }
+            : {
+                code: 'PACKAGE_NOT_FOUND',
+                message: `No indexed files in package "${options.package}"`,
+                recoverable: true,
+                suggestion: 'Use dev_map to see the indexed directories',
+              },
+        };
+      }
+
+      const content = formatCallGraph(graph, format);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Call graph exported', {
+        scope: graph.scope,
+        nodes: graph.nodes.length,
+        edges: graph.edges.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Call graph export failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'GRAPH_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 200;
+    return Math.min(limit, 100) * 30;
+  }
+}
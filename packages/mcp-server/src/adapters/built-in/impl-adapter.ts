@@ -0,0 +1,158 @@
+/**
+ * Impl Adapter
+ * Lists the types implementing an interface via the dev_impl tool
+ */
+
+import { formatImplementations, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { ImplArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Impl adapter configuration
+ */
+export interface ImplAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default maximum implementations returned
+   */
+  defaultLimit?: number;
+}
+
+/**
+ * Impl Adapter
+ * Implements the dev_impl tool for "what satisfies this interface?"
+ */
+export class ImplAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'impl-adapter',
+    version: '1.0.0',
+    description: 'Interface implementations adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<ImplAdapterConfig, 'searchService'>>;
+
+  constructor(config: ImplAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultLimit: config.defaultLimit ?? 50,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('ImplAdapter initialized', {
+      defaultLimit: this.config.defaultLimit,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_impl',
+      description:
+        'List every type in the repository that implements an interface (e.g. "io.Reader" ' +
+        'or "store.Store"), with locations. Combines explicit `var _ I = T` assertions with ' +
+        "structural matches where a type's method set, promoted methods included, covers " +
+        'the interface, across packages and with signatures compared, and shows which ' +
+        'method satisfies each requirement and on which receiver, flags types only *T ' +
+        'implements, and rates each match high/medium/low confidence. Types one method ' +
+        'short are listed as partial implementations. Use before changing an interface, ' +
+        'or to find the concrete types behind one.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          interface: {
+            type: 'string',
+            description:
+              'Interface name, optionally package-qualified (e.g., "io.Reader", "Store", ' +
+              '"store.Store")',
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include types and assertions in test files (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: `Maximum implementations (default: ${this.config.defaultLimit})`,
+            minimum: 1,
+            maximum: 200,
+            default: this.config.defaultLimit,
+          },
+        },
+        required: ['interface'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(ImplArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { interface: iface, includeTests, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Listing implementations', { interface: iface, includeTests, limit });
+
+      const result = await this.searchService.getImplementations(iface, { includeTests, limit });
+
+      if (!result) {
+        return {
+          success: false,
+          error: {
+            code: 'INTERFACE_NOT_FOUND',
+            message: `Interface "${iface}" is not indexed, well known, or asserted anywhere`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the interface name, or qualify it (e.g. io.Reader)',
+          },
+        };
+      }
+
+      const content = formatImplementations(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Implementations listed', {
+        interface: result.interface,
+        implementations: result.implementations.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Implementation lookup failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'IMPL_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { limit = this.config.defaultLimit } = args;
+    return (limit as number) * 60 + 50;
+  }
+}
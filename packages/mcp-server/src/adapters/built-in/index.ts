@@ -3,9 +3,19 @@
  * Production-ready adapters included with the MCP server
  */
 
+export { CallPathAdapter, type CallPathAdapterConfig } from './callpath-adapter.js';
+export { ChangelogAdapter, type ChangelogAdapterConfig } from './changelog-adapter.js';
+export { ConstraintsAdapter, type ConstraintsAdapterConfig } from './constraints-adapter.js';
+export { ContextAdapter, type ContextAdapterConfig } from './context-adapter.js';
+export { DepsAdapter, type DepsAdapterConfig } from './deps-adapter.js';
+export { DiffAdapter, type DiffAdapterConfig } from './diff-adapter.js';
+export { ExplainAdapter, type ExplainAdapterConfig } from './explain-adapter.js';
+export { FeedbackAdapter, type FeedbackAdapterConfig } from './feedback-adapter.js';
 export { GitHubAdapter, type GitHubAdapterConfig } from './github-adapter.js';
+export { GraphAdapter, type GraphAdapterConfig } from './graph-adapter.js';
 export { HealthAdapter, type HealthCheckConfig } from './health-adapter.js';
 export { HistoryAdapter, type HistoryAdapterConfig } from './history-adapter.js';
+export { ImplAdapter, type ImplAdapterConfig } from './impl-adapter.js';
 // Legacy: Re-export InspectAdapter as ExploreAdapter for backward compatibility (deprecated)
 export {
   InspectAdapter,
@@ -13,8 +23,20 @@ export {
   type InspectAdapterConfig,
   type InspectAdapterConfig as ExploreAdapterConfig,
 } from './inspect-adapter.js';
+export { LookupAdapter, type LookupAdapterConfig } from './lookup-adapter.js';
 export { MapAdapter, type MapAdapterConfig } from './map-adapter.js';
+export { NeighborsAdapter, type NeighborsAdapterConfig } from './neighbors-adapter.js';
+export { OpenApiAdapter, type OpenApiAdapterConfig } from './openapi-adapter.js';
+export { OutlineAdapter, type OutlineAdapterConfig } from './outline-adapter.js';
 export { PlanAdapter, type PlanAdapterConfig } from './plan-adapter.js';
+export { RecursionAdapter, type RecursionAdapterConfig } from './recursion-adapter.js';
 export { RefsAdapter, type RefsAdapterConfig } from './refs-adapter.js';
+export { ReindexAdapter, type ReindexAdapterConfig } from './reindex-adapter.js';
+export { RoutesAdapter, type RoutesAdapterConfig } from './routes-adapter.js';
 export { SearchAdapter, type SearchAdapterConfig } from './search-adapter.js';
+export { SimilarAdapter, type SimilarAdapterConfig } from './similar-adapter.js';
+export { SqlAdapter, type SqlAdapterConfig } from './sql-adapter.js';
 export { StatusAdapter, type StatusAdapterConfig } from './status-adapter.js';
+export { TestAdapter, type TestAdapterConfig } from './test-adapter.js';
+export { UsageAdapter, type UsageAdapterConfig } from './usage-adapter.js';
+export { WhereisAdapter, type WhereisAdapterConfig } from './whereis-adapter.js';
@@ -2,15 +2,21 @@
  * Inspect Adapter
  * Exposes code inspection capabilities via MCP (dev_inspect tool)
  *
- * Provides file-level analysis: similarity comparison and pattern consistency checking.
+ * Provides file-level analysis (similarity comparison and pattern consistency
+ * checking) and symbol-level analysis (definition, callers, callees, implements
+ * edges, and git info in one response).
  */
 
 import {
+  formatSymbolInspection,
   PatternAnalysisService,
   type PatternComparison,
+  type SearchResult,
   type SearchService,
+  type SymbolInspection,
 } from '@lytics/dev-agent-core';
-import { InspectArgsSchema } from '../../schemas/index.js';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { type InspectArgs, InspectArgsSchema } from '../../schemas/index.js';
 import { ToolAdapter } from '../tool-adapter.js';
 import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types.js';
 import { validateArgs } from '../validation.js';
@@ -24,10 +30,11 @@ export interface InspectAdapterConfig {
 }
 
 /**
- * InspectAdapter - Deep file analysis
+ * InspectAdapter - Deep file and symbol analysis
  *
  * Provides comprehensive file inspection: finds similar code and analyzes
  * patterns against the codebase. Returns facts (not judgments) for AI to interpret.
+ * For a symbol, gathers what would otherwise take several tool calls.
  */
 export class InspectAdapter extends ToolAdapter {
   metadata = {
@@ -74,13 +81,24 @@ export class InspectAdapter extends ToolAdapter {
       description:
         'Inspect a file for pattern analysis. Finds similar code and compares patterns ' +
         '(error handling, naming, types, structure). Returns facts about how this file ' +
-        'compares to similar code, without making judgments.',
+        'compares to similar code, without making judgments. ' +
+        'With target "symbol", returns everything about a symbol in one call instead: ' +
+        'definition, signature, doc, callers, callees, implements edges, and last-modified ' +
+        'git info, trimmed to a token budget. Pick sections to control size.',
       inputSchema: {
         type: 'object',
         properties: {
           query: {
             type: 'string',
-            description: 'File path to inspect (e.g., "src/auth/middleware.ts")',
+            description:
+              'File path to inspect (e.g., "src/auth/middleware.ts"), or a symbol name ' +
+              '(e.g., "Client.Do") with target "symbol"',
+          },
+          target: {
+            type: 'string',
+            enum: ['file', 'symbol'],
+            description: 'What query names: a file (default) or a symbol',
+            default: 'file',
           },
           limit: {
             type: 'number',
@@ -96,11 +114,36 @@ export class InspectAdapter extends ToolAdapter {
             minimum: 0,
             maximum: 1,
           },
+          sections: {
+            type: 'array',
+            items: {
+              type: 'string',
+              enum: ['definition', 'callers', 'callees', 'implements', 'git'],
+            },
+            description: 'Symbol sections to include (default: all)',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          tokenBudget: {
+            type: 'number',
+            description: 'Maximum tokens of symbol source and listed symbols (default: 2000)',
+            minimum: 200,
+            maximum: 20000,
+            default: 2000,
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include callers and implementations in test files (default: false)',
+            default: false,
+          },
           format: {
             type: 'string',
-            enum: ['compact', 'verbose'],
+            enum: ['compact', 'verbose', 'json'],
             description:
-              'Output format: "compact" for summaries (default), "verbose" for full details',
+              'Output format: "compact" for summaries (default), "verbose" for full details, ' +
+              '"json" for a structured symbol inspection',
             default: this.defaultFormat,
           },
         },
@@ -116,6 +159,10 @@ export class InspectAdapter extends ToolAdapter {
       return validation.error;
     }
 
+    if (validation.data.target === 'symbol') {
+      return this.executeSymbol(validation.data, context);
+    }
+
     const { query, limit, threshold, format } = validation.data;
 
     try {
@@ -192,6 +239,124 @@ export class InspectAdapter extends ToolAdapter {
     }
   }
 
+  /**
+   * Symbol inspection: everything about one symbol in a single response
+   */
+  private async executeSymbol(
+    args: InspectArgs,
+    context: ToolExecutionContext
+  ): Promise<ToolResult> {
+    const { query, sections, path, tokenBudget, includeTests, format } = args;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Executing symbol inspection', { query, sections, path, tokenBudget });
+
+      const inspection = await this.searchService.getSymbolInspection(query, {
+        sections,
+        path,
+        maxTokens: tokenBudget,
+        includeTests,
+      });
+
+      if (!inspection) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${query}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const data =
+        format === 'json'
+          ? this.structureInspection(inspection)
+          : formatSymbolInspection(inspection);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Symbol inspection completed', {
+        query,
+        sections: inspection.sections,
+        omitted: inspection.omitted,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data,
+        metadata: {
+          tokens: estimateTokensForText(typeof data === 'string' ? data : JSON.stringify(data)),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Symbol inspection failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'INSPECTION_ERROR',
+          message: error instanceof Error ? error.message : 'Unknown inspection error',
+        },
+      };
+    }
+  }
+
+  /**
+   * Symbol inspection as plain JSON, with symbols reduced to name and location
+   */
+  private structureInspection(inspection: SymbolInspection) {
+    const ref = (symbol: SearchResult) => ({
+      name: symbol.metadata.name,
+      type: symbol.metadata.type,
+      path: symbol.metadata.path,
+      line: symbol.metadata.startLine,
+    });
+    const edges = (list: SymbolInspection['implements']) =>
+      list.map((edge) => ({
+        name: edge.name,
+        pointer: edge.pointer,
+        ...(edge.symbol && {
+          path: edge.symbol.metadata.path,
+          line: edge.symbol.metadata.startLine,
+        }),
+      }));
+    const { metadata } = inspection.target;
+    const included = new Set(inspection.sections);
+
+    return {
+      symbol: { ...ref(inspection.target), endLine: metadata.endLine, package: inspection.package },
+      ...(included.has('definition') && {
+        definition: {
+          signature: metadata.signature,
+          doc: metadata.docstring,
+          diagnostics: metadata.diagnostics,
+          source: inspection.source || undefined,
+        },
+      }),
+      ...(included.has('callers') && { callers: inspection.callers.map(ref) }),
+      ...(included.has('callees') && {
+        callees: inspection.callees.map(ref),
+        internalViolations: inspection.internalViolations.map(({ call, target }) => ({
+          call: call.name,
+          line: call.line,
+          target: ref(target),
+        })),
+      }),
+      ...(included.has('implements') && {
+        implements: edges(inspection.implements),
+        implementedBy: edges(inspection.implementedBy),
+      }),
+      ...(included.has('git') && { git: inspection.git ?? null }),
+      omitted: inspection.omitted,
+      tokens: inspection.tokens,
+    };
+  }
+
   /**
    * Comprehensive file inspection
    *
@@ -0,0 +1,214 @@
+/**
+ * Lookup Adapter
+ * Provides fuzzy symbol-name lookup via the dev_lookup tool
+ */
+
+import type { SearchResult, SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import {
+  LookupArgsSchema,
+  type LookupStructuredOutput,
+  LookupStructuredOutputSchema,
+  OUTPUT_SCHEMA_VERSION,
+  toOutputJsonSchema,
+} from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Lookup adapter configuration
+ */
+export interface LookupAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default result limit
+   */
+  defaultLimit?: number;
+}
+
+/**
+ * Lookup Adapter
+ * Implements the dev_lookup tool for finding symbols by approximate name
+ */
+export class LookupAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'lookup-adapter',
+    version: '1.0.0',
+    description: 'Fuzzy symbol-name lookup adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<LookupAdapterConfig, 'searchService'>>;
+
+  constructor(config: LookupAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultLimit: config.defaultLimit ?? 10,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('LookupAdapter initialized', {
+      defaultLimit: this.config.defaultLimit,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_lookup',
+      description:
+        'Find a symbol when you only half-remember its name (e.g., "backof" → ExpBackoff). ' +
+        'Fuzzy matches symbol names (typos, partial names) and returns locations and signatures. ' +
+        'Use dev_search instead for conceptual queries.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          query: {
+            type: 'string',
+            description: 'Partial or misspelled symbol name',
+          },
+          kind: {
+            type: 'string',
+            enum: ['function', 'method', 'class', 'interface', 'type', 'variable'],
+            description: 'Only match symbols of this kind (Go structs are "class")',
+          },
+          path: {
+            type: 'string',
+            description: 'Only match symbols under this path prefix (e.g., "internal/retry/")',
+          },
+          limit: {
+            type: 'number',
+            description: `Maximum number of matches (default: ${this.config.defaultLimit})`,
+            minimum: 1,
+            maximum: 50,
+            default: this.config.defaultLimit,
+          },
+          minScore: {
+            type: 'number',
+            description: 'Minimum match score (0-1). Higher = stricter (default: 0.3)',
+            minimum: 0,
+            maximum: 1,
+            default: 0.3,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description:
+              'Output format: "text" for markdown (default), "json" for structured results ' +
+              'matching the output schema',
+            default: 'text',
+          },
+        },
+        required: ['query'],
+      },
+      outputSchema: toOutputJsonSchema(LookupStructuredOutputSchema),
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(LookupArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { query, kind, path, limit, minScore, format } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Executing lookup', { query, kind, path, limit, minScore, format });
+
+      const results = await this.searchService.lookupSymbol(query, {
+        kind,
+        path,
+        limit,
+        minScore,
+      });
+
+      const data =
+        format === 'json'
+          ? this.structureResults(query, results)
+          : this.formatResults(query, results);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Lookup completed', {
+        query,
+        matches: results.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data,
+        metadata: {
+          tokens: estimateTokensForText(typeof data === 'string' ? data : JSON.stringify(data)),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Lookup failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'LOOKUP_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  /**
+   * Format lookup results as markdown
+   */
+  private formatResults(query: string, results: SearchResult[]): string {
+    const lines = [`# Symbol Lookup: "${query}"`, ''];
+
+    if (results.length === 0) {
+      lines.push('*No matching symbols found*');
+      return lines.join('\n');
+    }
+
+    for (const result of results) {
+      const { name, type, path, startLine, signature } = result.metadata;
+      const score = (result.score * 100).toFixed(0);
+      lines.push(`- **${name}** (${type}) - \`${path}:${startLine}\` [${score}%]`);
+      if (signature) {
+        lines.push(`  \`${signature.split('\n')[0]}\``);
+      }
+    }
+
+    return lines.join('\n');
+  }
+
+  /**
+   * Shape lookup results as structured output
+   */
+  private structureResults(query: string, results: SearchResult[]): LookupStructuredOutput {
+    return {
+      schemaVersion: OUTPUT_SCHEMA_VERSION,
+      query,
+      matches: results.map((result) => ({
+        name: result.metadata.name ?? '',
+        type: result.metadata.type ?? 'unknown',
+        path: result.metadata.path ?? '',
+        startLine: result.metadata.startLine ?? 0,
+        signature: result.metadata.signature,
+        score: result.score,
+      })),
+    };
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { limit = this.config.defaultLimit } = args;
+    return (limit as number) * 30 + 20;
+  }
+}
@@ -0,0 +1,157 @@
+/**
+ * Neighbors Adapter
+ * Lists what's defined near a symbol or line via the dev_neighbors tool
+ */
+
+import { formatSymbolNeighbors, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { NeighborsArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Neighbors adapter configuration
+ */
+export interface NeighborsAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Neighbors Adapter
+ * Implements the dev_neighbors tool: nearby declarations, type members, package doc
+ */
+export class NeighborsAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'neighbors-adapter',
+    version: '1.0.0',
+    description: 'Nearby symbol adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: NeighborsAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('NeighborsAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_neighbors',
+      description:
+        "Show what's defined near a symbol or a file line: the declarations just before " +
+        "and after it in the same file, the other members of its type (a method's receiver " +
+        'and its methods), and the package doc. Exact lookup, no semantic search. Use to ' +
+        'find the helpers and variants written next to the code you are reading.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Symbol to start from (e.g., "Cache.Get")',
+          },
+          file: {
+            type: 'string',
+            description: 'File to start from instead, with line (e.g., "internal/store/cache.go")',
+          },
+          line: {
+            type: 'number',
+            description: 'Line in file; the innermost symbol containing it is the target',
+            minimum: 1,
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          count: {
+            type: 'number',
+            description: 'Symbols listed on each side (default: 3)',
+            minimum: 1,
+            maximum: 20,
+            default: 3,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(NeighborsArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const options = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Finding neighbors', options);
+
+      const result = await this.searchService.getSymbolNeighbors(options);
+      if (!result) {
+        return {
+          success: false,
+          error: options.symbol
+            ? {
+                code: 'SYMBOL_NOT_FOUND',
+                message: `Symbol "${options.symbol}" not found in the index`,
+                recoverable: true,
+                suggestion: 'Use dev_lookup to find the exact symbol name',
+              }
+            : {
+                code: 'FILE_NOT_FOUND',
+                message: `File "${options.file}" is not indexed`,
+                recoverable: true,
+                suggestion: 'Use a path relative to the repository root',
+              },
+        };
+      }
+
+      const content = formatSymbolNeighbors(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Neighbors found', {
+        file: result.file,
+        before: result.before.length,
+        after: result.after.length,
+        groupMembers: result.groupMembers.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Finding neighbors failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'NEIGHBORS_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const count = typeof args.count === 'number' ? args.count : 3;
+    return 200 + count * 2 * 40;
+  }
+}
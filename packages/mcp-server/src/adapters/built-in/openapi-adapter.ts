@@ -0,0 +1,153 @@
+/**
+ * OpenAPI Adapter
+ * Links OpenAPI spec operations to the handlers implementing them via the dev_openapi tool
+ */
+
+import { formatOpenApiOperations, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { OpenApiArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * OpenAPI adapter configuration
+ */
+export interface OpenApiAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * OpenAPI Adapter
+ * Implements the dev_openapi tool: spec operations, the routes and handlers
+ * likely implementing them, and routes the specs leave out
+ */
+export class OpenApiAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'openapi-adapter',
+    version: '1.0.0',
+    description: 'OpenAPI spec to handler linking adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: OpenApiAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('OpenApiAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_openapi',
+      description:
+        'List the operations of the indexed OpenAPI/Swagger specs, each linked to the Go route ' +
+        'and handler that likely implements it, with a confidence (high: method and path ' +
+        'match; medium/low: looser path or operationId-to-handler-name matches). Also lists ' +
+        'routes no spec operation describes. Use it to check a spec against the code.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          pathPrefix: {
+            type: 'string',
+            description:
+              'Only operations whose path starts with this, with or without the spec base path',
+          },
+          method: {
+            type: 'string',
+            description: 'Only operations with this HTTP method (e.g., "POST")',
+          },
+          tag: {
+            type: 'string',
+            description: 'Only operations with this spec tag',
+          },
+          unlinkedOnly: {
+            type: 'boolean',
+            description: 'Only operations no route matches (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum operations to list (default: 200)',
+            minimum: 1,
+            maximum: 1000,
+            default: 200,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(OpenApiArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { pathPrefix, method, tag, unlinkedOnly, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Listing OpenAPI operations', { pathPrefix, method, tag, limit });
+
+      const openApiMap = await this.searchService.getOpenApiOperations({
+        pathPrefix,
+        method,
+        tag,
+        unlinkedOnly,
+        limit,
+      });
+
+      let content = formatOpenApiOperations(openApiMap);
+      if (openApiMap.total === 0 && openApiMap.unspecifiedRoutes.length === 0) {
+        content =
+          pathPrefix || method || tag || unlinkedOnly
+            ? 'No OpenAPI operations match these filters.\n'
+            : 'No OpenAPI operations found. Specs are indexed from files named openapi.* or ' +
+              'swagger.* (or kept in an openapi/ directory); re-run `dev index` if the index ' +
+              'predates spec indexing.\n';
+      }
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('OpenAPI operations listed', {
+        operations: openApiMap.total,
+        linked: openApiMap.linked,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('OpenAPI operation listing failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'OPENAPI_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 200;
+    return Math.min(limit, 50) * 60;
+  }
+}
@@ -0,0 +1,153 @@
+/**
+ * Outline Adapter
+ * Summarizes a package's types and their relationships via the dev_outline tool
+ */
+
+import { formatPackageOutline, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { OutlineArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Outline adapter configuration
+ */
+export interface OutlineAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default maximum types (and functions) per outline
+   */
+  defaultLimit?: number;
+}
+
+/**
+ * Outline Adapter
+ * Implements the dev_outline tool for "how is this package put together?"
+ */
+export class OutlineAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'outline-adapter',
+    version: '1.0.0',
+    description: 'Package outline adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<OutlineAdapterConfig, 'searchService'>>;
+
+  constructor(config: OutlineAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultLimit: config.defaultLimit ?? 30,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('OutlineAdapter initialized', {
+      defaultLimit: this.config.defaultLimit,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_outline',
+      description:
+        "Outline a package's exported types and how they relate: which types embed which, " +
+        'which implement the package interfaces, and which functions construct them, ' +
+        'plus methods and other functions. Use when onboarding to a package, before reading ' +
+        'its files one by one.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          package: {
+            type: 'string',
+            description: 'Package directory (e.g., "internal/retry"), or a trailing part of it',
+          },
+          includeUnexported: {
+            type: 'boolean',
+            description: 'Include unexported types, functions, and methods (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: `Maximum types and functions (default: ${this.config.defaultLimit})`,
+            minimum: 1,
+            maximum: 100,
+            default: this.config.defaultLimit,
+          },
+        },
+        required: ['package'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(OutlineArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { package: pkg, includeUnexported, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Outlining package', { package: pkg, includeUnexported, limit });
+
+      const outline = await this.searchService.getPackageOutline(pkg, { includeUnexported, limit });
+
+      if (!outline) {
+        return {
+          success: false,
+          error: {
+            code: 'PACKAGE_NOT_FOUND',
+            message: `No indexed files in package "${pkg}"`,
+            recoverable: true,
+            suggestion: 'Use dev_map to see the indexed directories',
+          },
+        };
+      }
+
+      const content = formatPackageOutline(outline);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Package outlined', {
+        package: outline.package,
+        types: outline.types.length,
+        functions: outline.functions.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Package outline failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'OUTLINE_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { limit = this.config.defaultLimit } = args;
+    return (limit as number) * 40 + 50;
+  }
+}
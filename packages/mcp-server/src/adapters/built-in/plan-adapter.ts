@@ -9,7 +9,13 @@ import type { GitIndexer, RepositoryIndexer } from '@lytics/dev-agent-core';
 import type { ContextAssemblyOptions } from '@lytics/dev-agent-subagents';
 import { assembleContext, formatContextPackage } from '@lytics/dev-agent-subagents';
 import { estimateTokensForText, startTimer } from '../../formatters/utils';
-import { PlanArgsSchema } from '../../schemas/index.js';
+import {
+  OUTPUT_SCHEMA_VERSION,
+  PlanArgsSchema,
+  type PlanStructuredOutput,
+  PlanStructuredOutputSchema,
+  toOutputJsonSchema,
+} from '../../schemas/index.js';
 import { ToolAdapter } from '../tool-adapter';
 import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
 import { validateArgs } from '../validation.js';
@@ -96,8 +102,10 @@ export class PlanAdapter extends ToolAdapter {
           },
           format: {
             type: 'string',
-            enum: ['compact', 'verbose'],
-            description: 'Output format: "compact" for markdown (default), "verbose" for JSON',
+            enum: ['compact', 'verbose', 'json'],
+            description:
+              'Output format: "compact" for markdown (default), "verbose" for the raw context ' +
+              'as JSON text, "json" for structured results matching the output schema',
             default: this.defaultFormat,
           },
           includeCode: {
@@ -123,6 +131,7 @@ export class PlanAdapter extends ToolAdapter {
         },
         required: ['issue'],
       },
+      outputSchema: toOutputJsonSchema(PlanStructuredOutputSchema),
     };
   }
 
@@ -151,7 +160,8 @@ export class PlanAdapter extends ToolAdapter {
       const options: ContextAssemblyOptions = {
         includeCode: includeCode as boolean,
         includePatterns: includePatterns as boolean,
-        includeHistory: false, // TODO: Enable when GitHub indexer integration is ready
+        // Related PRs/issues are mined from git history
+        includeHistory: (includeGitHistory as boolean) && !!this.gitIndexer,
         includeGitHistory: (includeGitHistory as boolean) && !!this.gitIndexer,
         maxCodeResults: 10,
         maxGitCommitResults: 5,
@@ -169,28 +179,30 @@ export class PlanAdapter extends ToolAdapter {
       );
 
       // Format output
-      const content =
-        format === 'verbose'
-          ? JSON.stringify(contextPackage, null, 2)
-          : formatContextPackage(contextPackage);
+      const data: string | PlanStructuredOutput =
+        format === 'json'
+          ? { schemaVersion: OUTPUT_SCHEMA_VERSION, ...contextPackage }
+          : format === 'verbose'
+            ? JSON.stringify(contextPackage, null, 2)
+            : formatContextPackage(contextPackage);
 
-      const tokens = estimateTokensForText(content);
+      const tokens = estimateTokensForText(typeof data === 'string' ? data : JSON.stringify(data));
       const duration_ms = timer.elapsed();
 
       context.logger.info('Context assembled', {
         issue,
         codeResults: contextPackage.relevantCode.length,
         commitResults: contextPackage.relatedCommits.length,
+        historyResults: contextPackage.relatedHistory.length,
         hasPatterns: !!contextPackage.codebasePatterns.testPattern,
         tokens,
         duration_ms,
       });
 
-      // Validate output with Zod
       // Return formatted content (MCP will wrap in content blocks)
       return {
         success: true,
-        data: content,
+        data,
         metadata: {
           tokens,
           duration_ms,
@@ -0,0 +1,141 @@
+/**
+ * Recursion Adapter
+ * Lists self-recursive and mutually recursive functions via the dev_recursion tool
+ */
+
+import { formatRecursion, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { RecursionArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Recursion adapter configuration
+ */
+export interface RecursionAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Recursion Adapter
+ * Implements the dev_recursion tool: recursive cycles in the call graph
+ */
+export class RecursionAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'recursion-adapter',
+    version: '1.0.0',
+    description: 'Recursive call cycle adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: RecursionAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('RecursionAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_recursion',
+      description:
+        'List recursive functions: those calling themselves directly, and groups that call ' +
+        'each other in a cycle (mutual recursion), each with the shortest cycle and the ' +
+        'call lines forming it. Scope it to a package. Use when chasing stack overflows ' +
+        'or reviewing recursion depth, e.g. in parsers and tree walkers.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          package: {
+            type: 'string',
+            description:
+              'Only cycles with a function in this package directory (e.g., "internal/parser")',
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include functions in test files (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum cycles (default: 50)',
+            minimum: 1,
+            maximum: 500,
+            default: 50,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(RecursionArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const options = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Finding recursion', options);
+
+      const report = await this.searchService.getRecursion(options);
+      if (!report) {
+        return {
+          success: false,
+          error: {
+            code: 'PACKAGE_NOT_FOUND',
+            message: `No indexed files in package "${options.package}"`,
+            recoverable: true,
+            suggestion: 'Use dev_map to see the indexed directories',
+          },
+        };
+      }
+
+      const content = formatRecursion(report);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Recursion found', {
+        scope: report.scope,
+        cycles: report.cycles.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Finding recursion failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'RECURSION_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 50;
+    return Math.min(limit, 50) * 60;
+  }
+}
@@ -1,11 +1,25 @@
 /**
  * Refs Adapter
- * Provides call graph queries via the dev_refs tool
+ * Provides call graph and type reference queries via the dev_refs tool
  */
 
-import type { CalleeInfo, SearchResult, SearchService } from '@lytics/dev-agent-core';
+import * as path from 'node:path';
+import {
+  type CalleeInfo,
+  canImportInternal,
+  referencesType,
+  type SearchResult,
+  type SearchService,
+} from '@lytics/dev-agent-core';
 import { estimateTokensForText, startTimer } from '../../formatters/utils';
-import { RefsArgsSchema } from '../../schemas/index.js';
+import {
+  OUTPUT_SCHEMA_VERSION,
+  RefsArgsSchema,
+  type RefsStructuredOutput,
+  RefsStructuredOutputSchema,
+  toOutputJsonSchema,
+} from '../../schemas/index.js';
+import { embeddingsUnavailableResult } from '../errors';
 import { ToolAdapter } from '../tool-adapter';
 import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
 import { validateArgs } from '../validation.js';
@@ -15,6 +29,9 @@ import { validateArgs } from '../validation.js';
  */
 export type RefDirection = 'callees' | 'callers' | 'both';
 
+/** Document types that declare a named type, which functions can reference */
+const TYPE_KINDS = new Set(['class', 'interface', 'type']);
+
 /**
  * Refs adapter configuration
  */
@@ -38,7 +55,34 @@ interface RefResult {
   file?: string;
   line: number;
   type?: string;
-  snippet?: string;
+  signature?: string;
+  receiverType?: string;
+  guarded?: boolean;
+}
+
+/**
+ * References of one resolved symbol
+ */
+interface RefsQueryResult {
+  target: {
+    name: string;
+    file: string;
+    line: number;
+    type: string;
+  };
+  callees?: RefResult[];
+  callers?: RefResult[];
+  /** Types a function refers to (Go), with the callees */
+  referencesTypes?: string[];
+  /** Functions referring to a type, with the callers */
+  referencedBy?: RefResult[];
+}
+
+/**
+ * A symbol in a batch that couldn't be resolved
+ */
+interface RefsQueryError {
+  error: { code: string; message: string };
 }
 
 /**
@@ -78,20 +122,29 @@ export class RefsAdapter extends ToolAdapter {
       name: 'dev_refs',
       description:
         'Find who calls a function and what it calls. Use when you have a SPECIFIC symbol name and need to trace dependencies. ' +
+        'For a type, also lists the functions that use it in their signature or body ' +
+        '("what uses Config"); for a Go function, the types it uses. ' +
+        'Pass an array of names to query several symbols in one call. ' +
         'For conceptual queries like "where is auth used", use dev_search instead.',
       inputSchema: {
         type: 'object',
         properties: {
           name: {
-            type: 'string',
+            anyOf: [
+              { type: 'string' },
+              { type: 'array', items: { type: 'string' }, minItems: 1, maxItems: 25 },
+            ],
             description:
-              'Name of the function or method to query (e.g., "createPlan", "SearchAdapter.execute")',
+              'Name of the function or method to query (e.g., "createPlan", ' +
+              '"SearchAdapter.execute"), or an array of up to 25 names to batch; a batch returns results per name, and ' +
+              'names that cannot be resolved get an error entry without failing the rest',
           },
           direction: {
             type: 'string',
             enum: ['callees', 'callers', 'both'],
             description:
-              'Direction of query: "callees" (what this calls), "callers" (what calls this), or "both" (default)',
+              'Direction of query: "callees" (what this calls and the types it uses), "callers" ' +
+              '(what calls this, or uses this type), or "both" (default)',
             default: 'both',
           },
           limit: {
@@ -101,9 +154,18 @@ export class RefsAdapter extends ToolAdapter {
             maximum: 50,
             default: this.config.defaultLimit,
           },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description:
+              'Output format: "text" for markdown (default), "json" for structured results ' +
+              'matching the output schema',
+            default: 'text',
+          },
         },
         required: ['name'],
       },
+      outputSchema: toOutputJsonSchema(RefsStructuredOutputSchema),
     };
   }
 
@@ -114,71 +176,57 @@ export class RefsAdapter extends ToolAdapter {
       return validation.error;
     }
 
-    const { name, direction, limit } = validation.data;
+    const { name, direction, limit, format } = validation.data;
+    const names = Array.isArray(name) ? [...new Set(name)] : [name];
 
     try {
       const timer = startTimer();
-      context.logger.debug('Executing refs query', { name, direction, limit });
-
-      // First, find the target component
-      const searchResults = await this.searchService.search(name, { limit: 10 });
-      const target = this.findBestMatch(searchResults, name);
-
-      if (!target) {
-        return {
-          success: false,
-          error: {
-            code: 'NOT_FOUND',
-            message: `Could not find function or method named "${name}"`,
-          },
-        };
-      }
-
-      const result: {
-        target: {
-          name: string;
-          file: string;
-          line: number;
-          type: string;
-        };
-        callees?: RefResult[];
-        callers?: RefResult[];
-      } = {
-        target: {
-          name: target.metadata.name || name,
-          file: target.metadata.path || '',
-          line: target.metadata.startLine || 0,
-          type: (target.metadata.type as string) || 'unknown',
-        },
-      };
-
-      // Get callees if requested
-      if (direction === 'callees' || direction === 'both') {
-        result.callees = this.getCallees(target, limit);
-      }
-
-      // Get callers if requested
-      if (direction === 'callers' || direction === 'both') {
-        result.callers = await this.getCallers(target, limit);
+      context.logger.debug('Executing refs query', { names, direction, limit, format });
+
+      const results = await this.queryRefs(names, direction, limit);
+
+      let data: string | RefsStructuredOutput;
+      if (Array.isArray(name)) {
+        data =
+          format === 'json'
+            ? { schemaVersion: OUTPUT_SCHEMA_VERSION, results: Object.fromEntries(results) }
+            : [...results]
+                .map(([symbol, result]) =>
+                  'error' in result
+                    ? `# References for ${symbol}\n*${result.error.message}*\n`
+                    : this.formatOutput(result, direction)
+                )
+                .join('\n');
+      } else {
+        const result = results.get(name) as RefsQueryResult | RefsQueryError;
+        if ('error' in result) {
+          return { success: false, error: result.error };
+        }
+        data =
+          format === 'json'
+            ? { schemaVersion: OUTPUT_SCHEMA_VERSION, ...result }
+            : this.formatOutput(result, direction);
       }
-
-      const content = this.formatOutput(result, direction);
       const duration_ms = timer.elapsed();
 
+      const resolved = [...results.values()].filter(
+        (result): result is RefsQueryResult => !('error' in result)
+      );
       context.logger.info('Refs query completed', {
-        name,
+        names,
         direction,
-        calleesCount: result.callees?.length ?? 0,
-        callersCount: result.callers?.length ?? 0,
+        unresolved: names.length - resolved.length,
+        calleesCount: resolved.reduce((sum, r) => sum + (r.callees?.length ?? 0), 0),
+        callersCount: resolved.reduce((sum, r) => sum + (r.callers?.length ?? 0), 0),
         duration_ms,
       });
 
-      const tokens = estimateTokensForText(content);
+      const tokens = estimateTokensForText(typeof data === 'string' ? data : JSON.stringify(data));
 
       // Return formatted content (MCP will wrap in content blocks)
       return {
         success: true,
-        data: content,
+        data,
         metadata: {
           tokens,
           duration_ms,
@@ -187,6 +235,11 @@ export class RefsAdapter extends ToolAdapter {
         },
       };
     } catch (error) {
+      const unavailable = embeddingsUnavailableResult(error);
+      if (unavailable) {
+        context.logger.warn('Embeddings unavailable', { error: unavailable.error?.message });
+        return unavailable;
+      }
       context.logger.error('Refs query failed', { error });
       return {
         success: false,
@@ -199,6 +252,84 @@ export class RefsAdapter extends ToolAdapter {
     }
   }
 
+  /**
+   * Resolve each name and collect its references
+   *
+   * Target resolution and caller candidates are each fetched in one batch,
+   * so the index is loaded twice however many names are queried.
+   */
+  private async queryRefs(
+    names: string[],
+    direction: RefDirection,
+    limit: number
+  ): Promise<Map<string, RefsQueryResult | RefsQueryError>> {
+    const matches = await this.searchAll(names, { limit: 10 });
+    const targets = names.map((name, i) => this.findBestMatch(matches[i], name));
+
+    let candidates: SearchResult[][] = [];
+    const callerQueries = targets.map((target) => target?.metadata.name ?? '');
+    if (direction === 'callers' || direction === 'both') {
+      candidates = await this.searchAll(callerQueries.filter(Boolean), { limit: 100 });
+    }
+
+    const results = new Map<string, RefsQueryResult | RefsQueryError>();
+    let candidateIndex = 0;
+    names.forEach((name, i) => {
+      const target = targets[i];
+      if (!target) {
+        results.set(name, {
+          error: {
+            code: 'NOT_FOUND',
+            message: `Could not find function or method named "${name}"`,
+          },
+        });
+        return;
+      }
+
+      const result: RefsQueryResult = {
+        target: {
+          name: target.metadata.name || name,
+          file: target.metadata.path || '',
+          line: target.metadata.startLine || 0,
+          type: (target.metadata.type as string) || 'unknown',
+        },
+      };
+
+      // Get callees if requested
+      if (direction === 'callees' || direction === 'both') {
+        result.callees = this.getCallees(target, limit);
+        const types = target.metadata.referencesTypes;
+        if (types) result.referencesTypes = types.slice(0, limit);
+      }
+
+      // Get callers if requested
+      if (direction === 'callers' || direction === 'both') {
+        const searched = callerQueries[i] ? candidates[candidateIndex++] : [];
+        result.callers = this.getCallers(target, searched, limit);
+        if (TYPE_KINDS.has(String(target.metadata.type))) {
+          result.referencedBy = this.getReferencedBy(target, searched, limit);
+        }
+      }
+
+      results.set(name, result);
+    });
+    return results;
+  }
+
+  /**
+   * Search several queries; one query goes through plain search
+   */
+  private async searchAll(
+    queries: string[],
+    options: { limit: number }
+  ): Promise<SearchResult[][]> {
+    if (queries.length === 0) return [];
+    if (queries.length === 1) {
+      return [await this.searchService.search(queries[0], options)];
+    }
+    return this.searchService.searchBatch(queries, options);
+  }
+
   /**
    * Find the best matching result for a name query
    */
@@ -226,26 +357,38 @@ export class RefsAdapter extends ToolAdapter {
       name: c.name,
       file: c.file,
       line: c.line,
+      ...(c.receiverType ? { receiverType: c.receiverType } : {}),
+      ...(c.guarded ? { guarded: true } : {}),
     }));
   }
 
   /**
-   * Find callers by searching all indexed components for callees that reference the target
+   * Find callers among components that came up when searching for the target's
+   * name (a broad search, filtered here by callees)
    */
-  private async getCallers(target: SearchResult, limit: number): Promise<RefResult[]> {
+  private getCallers(target: SearchResult, candidates: SearchResult[], limit: number): RefResult[] {
     const targetName = target.metadata.name;
     if (!targetName) return [];
 
-    // Search for components that might call this target
-    // We search broadly and then filter by callees
-    const candidates = await this.searchService.search(targetName, { limit: 100 });
-
     const callers: RefResult[] = [];
 
     for (const candidate of candidates) {
       // Skip the target itself
       if (candidate.id === target.id) continue;
 
+      // Go code can't call into an internal package outside its tree; a match
+      // there is a different symbol with the same name
+      if (
+        candidate.metadata.language === 'go' &&
+        target.metadata.language === 'go' &&
+        !canImportInternal(
+          path.posix.dirname(candidate.metadata.path ?? ''),
+          path.posix.dirname(target.metadata.path ?? '')
+        )
+      ) {
+        continue;
+      }
+
       const callees = candidate.metadata.callees as CalleeInfo[] | undefined;
       if (!callees) continue;
 
@@ -263,7 +406,7 @@ export class RefsAdapter extends ToolAdapter {
           file: candidate.metadata.path,
           line: candidate.metadata.startLine || 0,
           type: candidate.metadata.type as string,
-          snippet: candidate.metadata.signature as string | undefined,
+          signature: candidate.metadata.signature as string | undefined,
         });
 
         if (callers.length >= limit) break;
@@ -273,17 +416,36 @@ export class RefsAdapter extends ToolAdapter {
     return callers;
   }
 
+  /**
+   * Find functions among the candidates whose recorded type references name the target type
+   */
+  private getReferencedBy(
+    target: SearchResult,
+    candidates: SearchResult[],
+    limit: number
+  ): RefResult[] {
+    const referencedBy: RefResult[] = [];
+    for (const candidate of candidates) {
+      if (candidate.id === target.id) continue;
+      const references = candidate.metadata.referencesTypes ?? [];
+      if (!references.some((reference) => referencesType(reference, candidate, target))) continue;
+
+      referencedBy.push({
+        name: candidate.metadata.name || 'unknown',
+        file: candidate.metadata.path,
+        line: candidate.metadata.startLine || 0,
+        type: candidate.metadata.type as string,
+        signature: candidate.metadata.signature as string | undefined,
+      });
+      if (referencedBy.length >= limit) break;
+    }
+    return referencedBy;
+  }
+
   /**
    * Format the output as readable text
    */
-  private formatOutput(
-    result: {
-      target: { name: string; file: string; line: number; type: string };
-      callees?: RefResult[];
-      callers?: RefResult[];
-    },
-    direction: RefDirection
-  ): string {
+  private formatOutput(result: RefsQueryResult, direction: RefDirection): string {
     const lines: string[] = [];
 
     lines.push(`# References for ${result.target.name}`);
@@ -296,12 +458,23 @@ export class RefsAdapter extends ToolAdapter {
       if (result.callees && result.callees.length > 0) {
         for (const callee of result.callees) {
           const location = callee.file ? `${callee.file}:${callee.line}` : `line ${callee.line}`;
-          lines.push(`- \`${callee.name}\` at ${location}`);
+          const narrowed = callee.receiverType
+            ? ` on ${callee.receiverType}${callee.guarded ? ' (guarded by a type check)' : ''}`
+            : '';
+          lines.push(`- \`${callee.name}\`${narrowed} at ${location}`);
         }
       } else {
         lines.push('*No callees found*');
       }
       lines.push('');
+
+      if (result.referencesTypes) {
+        lines.push('## Types Used (in its signature and body)');
+        for (const type of result.referencesTypes) {
+          lines.push(`- \`${type}\``);
+        }
+        lines.push('');
+      }
     }
 
     if (direction === 'callers' || direction === 'both') {
@@ -315,14 +488,25 @@ export class RefsAdapter extends ToolAdapter {
         lines.push('*No callers found in indexed code*');
       }
       lines.push('');
+
+      if (result.referencedBy) {
+        lines.push('## Used By (functions referring to this type)');
+        for (const user of result.referencedBy) {
+          const location = user.file ? `${user.file}:${user.line}` : `line ${user.line}`;
+          lines.push(`- \`${user.name}\` (${user.type}) at ${location}`);
+        }
+        if (result.referencedBy.length === 0) lines.push('*No functions found using this type*');
+        lines.push('');
+      }
     }
 
     return lines.join('\n');
   }
 
   estimateTokens(args: Record<string, unknown>): number {
-    const { limit = this.config.defaultLimit, direction = 'both' } = args;
+    const { name, limit = this.config.defaultLimit, direction = 'both' } = args;
     const multiplier = direction === 'both' ? 2 : 1;
-    return (limit as number) * 15 * multiplier + 50;
+    const symbols = Array.isArray(name) ? name.length : 1;
+    return ((limit as number) * 15 * multiplier + 50) * symbols;
   }
 }
@@ -0,0 +1,182 @@
+/**
+ * Reindex Adapter
+ * Starts and monitors background reindexing via the dev_reindex tool
+ */
+
+import type { IndexProgress } from '@lytics/dev-agent-core';
+import { estimateTokensForText } from '../../formatters/utils';
+import { ReindexArgsSchema } from '../../schemas/index.js';
+import {
+  formatIndexProgress,
+  formatReindexJob,
+  type ReindexJob,
+  type ReindexJobs,
+} from '../../server/reindex-jobs';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Reindex adapter configuration
+ */
+export interface ReindexAdapterConfig {
+  /**
+   * Job runner shared with the status tool
+   */
+  jobs: Pick<ReindexJobs, 'request' | 'get' | 'wait'>;
+}
+
+/**
+ * Reindex Adapter
+ * Implements the dev_reindex tool: request a reindex, then poll its job
+ */
+export class ReindexAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'reindex-adapter',
+    version: '1.0.0',
+    description: 'Background reindexing adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private jobs: ReindexAdapterConfig['jobs'];
+
+  constructor(config: ReindexAdapterConfig) {
+    super();
+    this.jobs = config.jobs;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('ReindexAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_reindex',
+      description:
+        'Bring the code index up to date in the background and return a job id at once. ' +
+        'Call again with the jobId to poll progress until the job completes. Requests made ' +
+        'while a job runs join it instead of starting another. Use after editing files, or ' +
+        'when dev_status reports a stale index, before relying on search results. With ' +
+        'wait, the call returns when the job finishes instead, sending progress ' +
+        'notifications (phase, percent, ETA) meanwhile if the client asked for them.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          mode: {
+            type: 'string',
+            enum: ['incremental', 'full'],
+            description:
+              'Incremental re-indexes changed, added, and deleted files (default); full ' +
+              're-scans and re-embeds the whole repository',
+            default: 'incremental',
+          },
+          jobId: {
+            type: 'string',
+            description: 'Poll this job (from an earlier dev_reindex call) instead of starting one',
+          },
+          wait: {
+            type: 'boolean',
+            description: 'Return when the job finishes rather than at once (default: false)',
+            default: false,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(ReindexArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { mode, jobId, wait } = validation.data;
+
+    let job: ReindexJob | undefined;
+    let joined = false;
+    if (jobId) {
+      job = this.jobs.get(jobId);
+      if (!job) {
+        return {
+          success: false,
+          error: {
+            code: 'JOB_NOT_FOUND',
+            message: `No reindex job ${jobId}; finished jobs are only kept for a while`,
+            suggestion: 'Call dev_reindex without a jobId to request a new reindex.',
+          },
+        };
+      }
+    } else {
+      ({ job, joined } = this.jobs.request(mode));
+      context.logger.info('Reindex requested', { mode, job: job.id, joined });
+    }
+
+    if (wait) {
+      job = (await this.jobs.wait(job.id, progressNotifier(context))) ?? job;
+    }
+
+    const content = formatJob(job, joined);
+    return {
+      success: true,
+      data: content,
+      metadata: {
+        tokens: estimateTokensForText(content),
+        duration_ms: 0,
+        timestamp: new Date().toISOString(),
+        cached: false,
+      },
+    };
+  }
+}
+
+/**
+ * Forward job progress as MCP progress notifications, when the client asked for them
+ *
+ * Notifications must increase, so only whole-percent gains are sent.
+ */
+function progressNotifier(
+  context: ToolExecutionContext
+): ((progress: IndexProgress) => void) | undefined {
+  const send = context.sendProgress;
+  if (!send) return undefined;
+
+  let sent = -1;
+  return (progress) => {
+    const percent = Math.round(progress.percentComplete);
+    if (percent <= sent) return;
+    sent = percent;
+    send({ progress: percent, total: 100, message: formatIndexProgress(progress) }).catch(
+      (error) => {
+        context.logger.debug('Could not send reindex progress', { error });
+      }
+    );
+  };
+}
+
+function formatJob(job: ReindexJob, joined: boolean): string {
+  const lines = [
+    `## Reindex job ${job.id}`,
+    '',
+    `**Status:** ${formatReindexJob(job)}`,
+    `**Mode:** ${job.mode}`,
+  ];
+  if (job.startedAt) {
+    lines.push(`**Started:** ${job.startedAt.toISOString()}`);
+  }
+  if (job.requests > 1) {
+    lines.push(`**Requests:** ${job.requests} (overlapping requests share this job)`);
+  }
+
+  lines.push('');
+  if (joined) {
+    lines.push('A job covering this request was already underway; this request joined it.');
+  }
+  if (job.status === 'queued' || job.status === 'running') {
+    lines.push(`Poll with \`dev_reindex\` and \`jobId: "${job.id}"\` until it completes.`);
+  } else if (job.status === 'completed') {
+    lines.push('Search and other tools now use the updated index.');
+  }
+
+  return `${lines.join('\n').trimEnd()}\n`;
+}
@@ -0,0 +1,147 @@
+/**
+ * Routes Adapter
+ * Lists the HTTP endpoints registered in the indexed code via the dev_routes tool
+ */
+
+import { formatRoutes, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { RoutesArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Routes adapter configuration
+ */
+export interface RoutesAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Routes Adapter
+ * Implements the dev_routes tool: the API surface of a service, handler by handler
+ */
+export class RoutesAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'routes-adapter',
+    version: '1.0.0',
+    description: 'HTTP route listing adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: RoutesAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('RoutesAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_routes',
+      description:
+        'List the HTTP endpoints registered in the indexed Go code (net/http, chi, gin, echo), ' +
+        'grouped by package. Each route links to its handler symbol with file and line, ' +
+        'and lists what the handler calls, so you can go from "POST /users" to the code ' +
+        'handling it. Use it to learn the API surface of an unfamiliar service.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          pathPrefix: {
+            type: 'string',
+            description: 'Only routes whose path starts with this (e.g., "/api/v1/users")',
+          },
+          method: {
+            type: 'string',
+            description:
+              'Only routes serving this HTTP method (e.g., "POST"); routes accepting any ' +
+              'method are kept',
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include routes registered in test files (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum routes to list (default: 200)',
+            minimum: 1,
+            maximum: 1000,
+            default: 200,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(RoutesArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { pathPrefix, method, includeTests, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Listing routes', { pathPrefix, method, includeTests, limit });
+
+      const routeMap = await this.searchService.getRoutes({
+        pathPrefix,
+        method,
+        includeTests,
+        limit,
+      });
+
+      let content = formatRoutes(routeMap);
+      if (routeMap.total === 0) {
+        content =
+          pathPrefix || method
+            ? 'No HTTP routes match these filters.\n'
+            : 'No HTTP routes found. Routes are extracted from Go code at index time; ' +
+              're-run `dev index` if the index predates route extraction.\n';
+      }
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Routes listed', {
+        routes: routeMap.total,
+        packages: routeMap.groups.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Route listing failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'ROUTES_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 200;
+    return Math.min(limit, 50) * 40;
+  }
+}
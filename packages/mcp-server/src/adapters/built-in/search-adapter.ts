@@ -3,14 +3,51 @@
  * Provides semantic code search via the dev_search tool
  */
 
-import type { SearchService } from '@lytics/dev-agent-core';
-import { CompactFormatter, type FormatMode, VerboseFormatter } from '../../formatters';
-import { SearchArgsSchema } from '../../schemas/index.js';
-import { findRelatedTestFiles, formatRelatedFiles } from '../../utils/related-files';
+import {
+  DEFAULT_MIN_SCORE,
+  expandQuery,
+  type QueryExpansion,
+  type SearchResult,
+  type SearchService,
+} from '@lytics/dev-agent-core';
+import {
+  CompactFormatter,
+  estimateTokensForText,
+  type FormatMode,
+  type FormattedResult,
+  VerboseFormatter,
+} from '../../formatters';
+import {
+  OUTPUT_SCHEMA_VERSION,
+  type SearchArgs,
+  SearchArgsSchema,
+  type SearchStructuredOutput,
+  SearchStructuredOutputSchema,
+  toOutputJsonSchema,
+} from '../../schemas/index.js';
+import { decodeCursor, encodeCursor, fingerprintQuery } from '../../utils/cursor';
+import {
+  findRelatedTestFiles,
+  formatRelatedFiles,
+  type RelatedFile,
+} from '../../utils/related-files';
+import { type ResultCache, resultCacheKey } from '../../utils/result-cache';
+import {
+  addSourceContext,
+  maxSourceContextLines,
+  SourceFileCache,
+} from '../../utils/source-context';
+import { embeddingsUnavailableResult } from '../errors';
 import { ToolAdapter } from '../tool-adapter';
 import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
 import { validateArgs } from '../validation.js';
 
+/** Most ranked results a query can page through */
+const MAX_RESULTS_WINDOW = 500;
+
+/** Pages ranked ahead of the current one, to estimate the total */
+const ESTIMATE_PAGES = 5;
+
 /**
  * Search adapter configuration
  */
@@ -21,7 +58,7 @@ export interface SearchAdapterConfig {
   searchService: SearchService;
 
   /**
-   * Repository root path (for finding related files)
+   * Repository root path (for finding related files and reading source context)
    */
   repositoryPath?: string;
 
@@ -39,6 +76,11 @@ export interface SearchAdapterConfig {
    * Include related test files in results
    */
   includeRelatedFiles?: boolean;
+
+  /**
+   * Cache of recent results; repeated queries against the same index skip the search
+   */
+  resultCache?: ResultCache<ToolResult>;
 }
 
 /**
@@ -54,13 +96,15 @@ export class SearchAdapter extends ToolAdapter {
   };
 
   private searchService: SearchService;
-  private config: Required<Omit<SearchAdapterConfig, 'repositoryPath'>> & {
+  private resultCache?: ResultCache<ToolResult>;
+  private config: Required<Omit<SearchAdapterConfig, 'repositoryPath' | 'resultCache'>> & {
     repositoryPath?: string;
   };
 
   constructor(config: SearchAdapterConfig) {
     super();
     this.searchService = config.searchService;
+    this.resultCache = config.resultCache;
     this.config = {
       searchService: config.searchService,
       repositoryPath: config.repositoryPath,
@@ -92,11 +136,40 @@ export class SearchAdapter extends ToolAdapter {
             description:
               'Natural language search query (e.g., "authentication middleware", "database connection logic")',
           },
+          queries: {
+            type: 'array',
+            items: { type: 'string' },
+            description:
+              'Several example queries searched together; combine with symbols for ' +
+              '"more code like these" (max 10)',
+          },
+          symbols: {
+            type: 'array',
+            items: { type: 'string' },
+            description:
+              'Indexed symbols to find more code like (names such as "Server.CreateUser", ' +
+              'or document IDs); their stored embeddings are used (max 10)',
+          },
+          pooling: {
+            type: 'string',
+            enum: ['mean', 'max', 'separate'],
+            description:
+              'How several examples combine: "mean" (default) averages them and favors what ' +
+              'they share, "max" keeps features strong in any one, "separate" searches each ' +
+              'and merges the results by best score',
+            default: 'mean',
+          },
+          excludeInputs: {
+            type: 'boolean',
+            description: 'Leave the example symbols out of the results (default: true)',
+            default: true,
+          },
           format: {
             type: 'string',
-            enum: ['compact', 'verbose'],
+            enum: ['compact', 'verbose', 'json'],
             description:
-              'Output format: "compact" for summaries (default), "verbose" for full details',
+              'Output format: "compact" for summaries (default), "verbose" for full details, ' +
+              '"json" for structured results matching the output schema',
             default: this.config.defaultFormat,
           },
           limit: {
@@ -113,6 +186,16 @@ export class SearchAdapter extends ToolAdapter {
             maximum: 1,
             default: 0,
           },
+          minScore: {
+            type: 'number',
+            description:
+              'Drop results whose final score (similarity plus doc boost, 0-1) is below this; ' +
+              'when nothing clears it the response says there are no strong matches. ' +
+              `Around 0.8+ is a strong match, under 0.5 is weak (default: ${DEFAULT_MIN_SCORE})`,
+            minimum: 0,
+            maximum: 1,
+            default: DEFAULT_MIN_SCORE,
+          },
           tokenBudget: {
             type: 'number',
             description:
@@ -120,9 +203,118 @@ export class SearchAdapter extends ToolAdapter {
             minimum: 500,
             maximum: 10000,
           },
+          exportedOnly: {
+            type: 'boolean',
+            description: 'Only return exported (public API) symbols (default: false)',
+            default: false,
+          },
+          includeGenerated: {
+            type: 'boolean',
+            description:
+              'Also return symbols from generated files such as mocks (default: false). ' +
+              'Use where {"generated": true} to search only generated code',
+            default: false,
+          },
+          module: {
+            type: 'string',
+            description:
+              'Only return symbols from this module (Go module path, e.g. "github.com/acme/api")',
+          },
+          where: {
+            type: 'object',
+            description:
+              'Only return symbols whose metadata matches every field exactly, e.g. ' +
+              '{"team": "payments"} for a field added by an index-time enricher',
+            additionalProperties: { type: ['string', 'number', 'boolean'] },
+          },
+          pathFilter: {
+            type: 'string',
+            description:
+              'Only search files under this path prefix (e.g., "packages/core/src/scanner") ' +
+              'or matching this glob (e.g., "**/*_test.go", "src/**/*.{ts,tsx}")',
+          },
+          mustContain: {
+            type: 'array',
+            items: { type: 'string' },
+            description:
+              'Identifiers every result must contain in its name, signature, or code, ' +
+              'e.g. ["ValidatePassword"] with query "password validation". Ranking stays ' +
+              'semantic; camelCase and snake_case parts match too ("password")',
+          },
+          mustNotContain: {
+            type: 'array',
+            items: { type: 'string' },
+            description: 'Identifiers no result may contain, matched the same way as mustContain',
+          },
+          contextLines: {
+            type: 'number',
+            description:
+              'Show N source lines before/after each match, with matched lines marked ">". ' +
+              'Call-site matches show the call in context (default: 0, indexed snippet only)',
+            minimum: 0,
+            maximum: 20,
+            default: 0,
+          },
+          cursor: {
+            type: 'string',
+            description:
+              'Cursor from a previous response to fetch the next page of the same query. ' +
+              'Cursors expire when the index changes',
+          },
+          expand: {
+            type: 'boolean',
+            description:
+              'Also search synonyms of query words (e.g., "auth" -> "login", "credentials") ' +
+              'for better recall; reports which synonyms surfaced results (default: false)',
+            default: false,
+          },
+          changedSince: {
+            type: 'string',
+            description:
+              'Only symbols last changed on or after this ISO date (e.g., "2024-06-01"). ' +
+              'Requires an index built with git blame enabled',
+          },
+          sort: {
+            type: 'string',
+            enum: ['relevance', 'recency'],
+            description:
+              'Order of the top matches: "relevance" (default) or "recency" ' +
+              '(most recently changed first; requires git blame data)',
+            default: 'relevance',
+          },
+          docWeight: {
+            type: 'number',
+            description:
+              'Ranking nudge toward well-documented exported symbols, added to the score ' +
+              'only to order near ties (0-0.2, default: 0.02; 0 disables)',
+            minimum: 0,
+            maximum: 0.2,
+          },
+          keywordWeight: {
+            type: 'number',
+            description:
+              'Boost for results containing distinctive query tokens, each weighted by its ' +
+              'rarity in the index so common words like "get" or "handler" barely count ' +
+              '(0-0.2, default: 0 unless DEV_AGENT_KEYWORD_WEIGHT is set; 0 disables)',
+            minimum: 0,
+            maximum: 0.2,
+          },
+          debug: {
+            type: 'boolean',
+            description:
+              'Explain ranking: per result, the vector score, keyword score, boosts, ' +
+              'final score, and the query text that matched (default: false)',
+            default: false,
+          },
+          session: {
+            type: 'string',
+            description:
+              'Session id used with dev_feedback: results are biased toward those marked ' +
+              'relevant in this session and away from those marked not relevant',
+          },
         },
-        required: ['query'],
       },
+      outputSchema: toOutputJsonSchema(SearchStructuredOutputSchema),
     };
   }
 
@@ -133,7 +325,36 @@ export class SearchAdapter extends ToolAdapter {
       return validation.error;
     }
 
-    const { query, format, limit, scoreThreshold, tokenBudget } = validation.data;
+    const {
+      query,
+      queries,
+      symbols,
+      format,
+      limit,
+      scoreThreshold,
+      minScore,
+      tokenBudget,
+      exportedOnly,
+      includeGenerated,
+      module,
+      where,
+      pathFilter,
+      mustContain,
+      mustNotContain,
+      contextLines,
+      cursor,
+      expand,
+      changedSince,
+      sort,
+      docWeight,
+      keywordWeight,
+      debug,
+      session,
+    } = validation.data;
+
+    if (query === undefined || queries || symbols) {
+      return this.executeExamples(validation.data, context);
+    }
 
     try {
       const startTime = Date.now();
@@ -142,75 +363,401 @@ export class SearchAdapter extends ToolAdapter {
         format,
         limit,
         scoreThreshold,
+        minScore,
         tokenBudget,
+        exportedOnly,
+        includeGenerated,
+        module,
+        pathFilter,
+        mustContain,
+        mustNotContain,
+        contextLines,
+        expand,
+        changedSince,
+        sort,
+        docWeight,
+        keywordWeight,
+        debug,
+        session,
+        paged: cursor !== undefined,
       });
 
-      // Perform search using SearchService
-      const results = await this.searchService.search(query as string, {
-        limit: limit as number,
+      // Debug output explains a fresh ranking, and session feedback changes between
+      // calls, so neither kind of query touches the cache
+      const cacheKey =
+        this.resultCache?.enabled && !debug && !session
+          ? resultCacheKey('dev_search', validation.data)
+          : undefined;
+      const cacheVersion = cacheKey ? await this.searchService.getIndexVersion() : null;
+      if (cacheKey && cacheVersion) {
+        const hit = this.resultCache?.get(cacheVersion, cacheKey);
+        if (hit?.metadata) {
+          context.logger.debug('Search served from cache', { query });
+          return {
+            ...hit,
+            metadata: {
+              ...hit.metadata,
+              duration_ms: Date.now() - startTime,
+              timestamp: new Date().toISOString(),
+              cached: true,
+            },
+          };
+        }
+      }
+
+      // Metadata filters (exact match)
+      const filter: Record<string, unknown> = { ...where };
+      if (exportedOnly) filter.exported = true;
+      if (module) filter.module = module;
+
+      // A cursor must come from the same query against the same index
+      const queryFingerprint = fingerprintQuery({
+        query,
+        scoreThreshold,
+        minScore,
+        exportedOnly,
+        includeGenerated,
+        module,
+        where,
+        pathFilter,
+        mustContain,
+        mustNotContain,
+        expand,
+        changedSince,
+        sort,
+        docWeight,
+        keywordWeight,
+        session,
+      });
+      let offset = 0;
+      if (cursor !== undefined) {
+        const page = decodeCursor(cursor);
+        if (!page || page.queryFingerprint !== queryFingerprint) {
+          return {
+            success: false,
+            error: {
+              code: 'INVALID_PARAMS',
+              message: 'Cursor is malformed or belongs to a different query',
+              recoverable: true,
+              suggestion:
+                'Pass the cursor back with the same query, exportedOnly, module, and pathFilter',
+            },
+          };
+        }
+        if (page.indexVersion !== (await this.searchService.getIndexVersion())) {
+          return {
+            success: false,
+            error: {
+              code: 'CURSOR_EXPIRED',
+              message: 'The index changed since this cursor was issued',
+              recoverable: true,
+              suggestion: 'Run the search again without a cursor',
+            },
+          };
+        }
+        offset = page.offset;
+      }
+
+      // Rank a few pages ahead so callers know roughly how many results remain
+      const window = Math.min(MAX_RESULTS_WINDOW, offset + (limit as number) * ESTIMATE_PAGES);
+      let belowMinScore = 0;
+      const ranked = await this.searchService.search(query as string, {
+        limit: window,
         scoreThreshold: scoreThreshold as number,
+        minScore,
+        onMinScoreCutoff: (dropped) => {
+          belowMinScore = dropped;
+        },
+        filter: Object.keys(filter).length > 0 ? filter : undefined,
+        changedSince,
+        pathFilter,
+        mustContain,
+        mustNotContain,
+        includeGenerated,
+        expand,
+        sort,
+        docWeight,
+        keywordWeight,
+        debug,
+        session,
       });
+      const expansion = expand ? expandQuery(query) : undefined;
+      let results = ranked.slice(offset, offset + (limit as number));
+      const hasMore = ranked.length > offset + results.length;
+      const totalIsEstimate = ranked.length === window;
+      // Only when there were candidates and every one scored under minScore
+      const noStrongMatches = ranked.length === 0 && belowMinScore > 0;
+      const nextCursor = hasMore
+        ? encodeCursor({
+            indexVersion: (await this.searchService.getIndexVersion()) ?? '',
+            queryFingerprint,
+            offset: offset + results.length,
+          })
+        : undefined;
+
+      // Swap indexed snippets for source lines around each match
+      const contextRoot = contextLines > 0 ? this.config.repositoryPath : undefined;
+      if (contextRoot) {
+        results = await addSourceContext(results, {
+          contextLines,
+          query,
+          cache: new SourceFileCache(contextRoot),
+        });
+      }
+      const maxSnippetLines = contextRoot ? maxSourceContextLines(contextLines) : undefined;
 
-      // Create formatter with token budget if specified
-      const formatter =
-        format === 'verbose'
-          ? new VerboseFormatter({
-              maxResults: limit as number,
-              tokenBudget: (tokenBudget as number | undefined) ?? 5000,
-              includeSnippets: true,
-              includeImports: true,
-            })
-          : new CompactFormatter({
-              maxResults: limit as number,
-              tokenBudget: (tokenBudget as number | undefined) ?? 2000,
-              includeSnippets: true,
-              includeImports: true,
-            });
-
-      const formatted = formatter.formatResults(results);
+      await this.streamResults(results, offset, context);
 
       // Find related test files if enabled and repository path is available
-      let relatedFilesSection = '';
-      let relatedFilesCount = 0;
+      let relatedFiles: RelatedFile[] = [];
       if (this.config.includeRelatedFiles && this.config.repositoryPath && results.length > 0) {
         const sourcePaths = results
           .map((r) => r.metadata.path)
           .filter((p): p is string => typeof p === 'string');
 
         if (sourcePaths.length > 0) {
-          const relatedFiles = await findRelatedTestFiles(sourcePaths, this.config.repositoryPath);
-          relatedFilesCount = relatedFiles.length;
-          relatedFilesSection = formatRelatedFiles(relatedFiles);
+          relatedFiles = await findRelatedTestFiles(sourcePaths, this.config.repositoryPath);
         }
       }
 
+      let data: string | SearchStructuredOutput;
+      let tokens: number;
+      if (format === 'json') {
+        data = {
+          schemaVersion: OUTPUT_SCHEMA_VERSION,
+          query,
+          results: results.map(toStructuredResult),
+          total: ranked.length,
+          totalIsEstimate,
+          minScore,
+          noStrongMatches,
+          nextCursor,
+          relatedFiles,
+          expansion: expansion ? searchedTerms(expansion) : undefined,
+        };
+        tokens = estimateTokensForText(JSON.stringify(data));
+      } else {
+        // Create formatter with token budget if specified
+        const formatter =
+          format === 'verbose'
+            ? new VerboseFormatter({
+                maxResults: limit as number,
+                tokenBudget: (tokenBudget as number | undefined) ?? 5000,
+                includeSnippets: true,
+                includeImports: true,
+                maxSnippetLines,
+              })
+            : new CompactFormatter({
+                maxResults: limit as number,
+                tokenBudget: (tokenBudget as number | undefined) ?? 2000,
+                includeSnippets: true,
+                includeImports: true,
+                maxSnippetLines,
+              });
+
+        const formatted = noStrongMatches
+          ? formatNoStrongMatches(query, minScore)
+          : formatter.formatResults(results);
+        const pageSection =
+          offset > 0 || hasMore
+            ? formatPage(offset, results.length, ranked.length, totalIsEstimate, nextCursor)
+            : '';
+        const expansionSection = expansion ? formatExpansion(expansion, results) : '';
+        const debugSection = debug ? formatScoreDebug(results) : '';
+        data =
+          formatted.content +
+          formatRelatedFiles(relatedFiles) +
+          expansionSection +
+          debugSection +
+          pageSection;
+        tokens = formatted.tokens;
+      }
+
       const duration_ms = Date.now() - startTime;
 
       context.logger.info('Search completed', {
         query,
+        format,
+        resultCount: results.length,
+        relatedFilesCount: relatedFiles.length,
+        tokens,
+        duration_ms,
+      });
+
+      // MCP wraps markdown in content blocks; JSON also goes out as structured content
+      const result: ToolResult = {
+        success: true,
+        data,
+        metadata: {
+          tokens,
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+          results_total: ranked.length,
+          results_returned: results.length,
+          results_truncated: hasMore,
+          results_total_is_estimate: totalIsEstimate,
+          no_strong_matches: noStrongMatches,
+          next_cursor: nextCursor,
+          related_files_count: relatedFiles.length,
+        },
+      };
+      if (cacheKey && cacheVersion) {
+        this.resultCache?.set(cacheVersion, cacheKey, result);
+      }
+      return result;
+    } catch (error) {
+      const unavailable = embeddingsUnavailableResult(error);
+      if (unavailable) {
+        context.logger.warn('Embeddings unavailable', { error: unavailable.error?.message });
+        return unavailable;
+      }
+      context.logger.error('Search failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'SEARCH_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  /**
+   * Search with several examples at once: query texts, indexed symbols, or both
+   */
+  private async executeExamples(
+    args: SearchArgs,
+    context: ToolExecutionContext
+  ): Promise<ToolResult> {
+    const { queries = [], symbols = [], pooling, format, limit, minScore, contextLines } = args;
+    const texts = args.query ? [args.query, ...queries] : queries;
+
+    try {
+      const startTime = Date.now();
+      context.logger.debug('Executing example search', { queries: texts, symbols, pooling });
+
+      const filter: Record<string, unknown> = { ...args.where };
+      if (args.exportedOnly) filter.exported = true;
+      if (args.module) filter.module = args.module;
+
+      let belowMinScore = 0;
+      const found = await this.searchService.searchExamples(
+        { queries: texts, symbols },
+        {
+          limit,
+          scoreThreshold: args.scoreThreshold,
+          minScore,
+          onMinScoreCutoff: (dropped) => {
+            belowMinScore = dropped;
+          },
+          filter: Object.keys(filter).length > 0 ? filter : undefined,
+          changedSince: args.changedSince,
+          pathFilter: args.pathFilter,
+          mustContain: args.mustContain,
+          mustNotContain: args.mustNotContain,
+          includeGenerated: args.includeGenerated,
+          sort: args.sort,
+          docWeight: args.docWeight,
+          pooling,
+          excludeInputs: args.excludeInputs,
+        }
+      );
+      if (texts.length === 0 && found.inputs.length === 0) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `None of the example symbols are indexed: ${found.unresolved.join(', ')}`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol names',
+          },
+        };
+      }
+
+      const inputNames = found.inputs.map((input) => input.metadata.name ?? input.id);
+      const label = [...texts.map((text) => `"${text}"`), ...inputNames.map((n) => `\`${n}\``)];
+      const noStrongMatches = found.results.length === 0 && belowMinScore > 0;
+
+      let results = found.results;
+      const contextRoot = contextLines > 0 ? this.config.repositoryPath : undefined;
+      if (contextRoot) {
+        results = await addSourceContext(results, {
+          contextLines,
+          query: [...texts, ...inputNames].join(' '),
+          cache: new SourceFileCache(contextRoot),
+        });
+      }
+      await this.streamResults(results, 0, context);
+
+      let data: string | SearchStructuredOutput;
+      let tokens: number;
+      if (format === 'json') {
+        data = {
+          schemaVersion: OUTPUT_SCHEMA_VERSION,
+          query: label.join(', '),
+          results: results.map(toStructuredResult),
+          total: results.length,
+          totalIsEstimate: false,
+          minScore,
+          noStrongMatches,
+          relatedFiles: [],
+          examples: { queries: texts, symbols: inputNames, unresolved: found.unresolved, pooling },
+        };
+        tokens = estimateTokensForText(JSON.stringify(data));
+      } else {
+        const formatterOptions = {
+          maxResults: limit,
+          includeSnippets: true,
+          includeImports: true,
+          maxSnippetLines: contextRoot ? maxSourceContextLines(contextLines) : undefined,
+        };
+        const formatter =
+          format === 'verbose'
+            ? new VerboseFormatter({ ...formatterOptions, tokenBudget: args.tokenBudget ?? 5000 })
+            : new CompactFormatter({ ...formatterOptions, tokenBudget: args.tokenBudget ?? 2000 });
+        const formatted = noStrongMatches
+          ? formatNoStrongMatches(label.join(', '), minScore)
+          : formatter.formatResults(results);
+        const notFound =
+          found.unresolved.length > 0
+            ? `\n\n**Not found:** ${found.unresolved.map((s) => `\`${s}\``).join(', ')}\n`
+            : '';
+        const header = `**More like:** ${label.join(', ')} (${pooling} pooling)`;
+        data = `${header}\n\n${formatted.content}${notFound}`;
+        tokens = formatted.tokens;
+      }
+
+      const duration_ms = Date.now() - startTime;
+      context.logger.info('Example search completed', {
+        examples: texts.length + found.inputs.length,
+        pooling,
         resultCount: results.length,
-        relatedFilesCount,
-        tokens: formatted.tokens,
         duration_ms,
       });
 
-      // Return markdown content (MCP will wrap in content blocks)
       return {
         success: true,
-        data: formatted.content + relatedFilesSection,
+        data,
         metadata: {
-          tokens: formatted.tokens,
+          tokens,
           duration_ms,
           timestamp: new Date().toISOString(),
           cached: false,
           results_total: results.length,
-          results_returned: Math.min(results.length, limit as number),
-          results_truncated: results.length > (limit as number),
-          related_files_count: relatedFilesCount,
+          results_returned: results.length,
+          results_truncated: false,
+          no_strong_matches: noStrongMatches,
         },
       };
     } catch (error) {
-      context.logger.error('Search failed', { error });
+      const unavailable = embeddingsUnavailableResult(error);
+      if (unavailable) {
+        context.logger.warn('Embeddings unavailable', { error: unavailable.error?.message });
+        return unavailable;
+      }
+      context.logger.error('Example search failed', { error });
       return {
         success: false,
         error: {
@@ -222,11 +769,186 @@ export class SearchAdapter extends ToolAdapter {
     }
   }
 
+  /**
+   * Send each result as a progress notification, in rank order, when the client asked for progress
+   */
+  private async streamResults(
+    results: SearchResult[],
+    offset: number,
+    context: ToolExecutionContext
+  ): Promise<void> {
+    if (!context.sendProgress) return;
+
+    for (const [i, result] of results.entries()) {
+      const { name, type, path, startLine } = result.metadata;
+      const score = result.score.toFixed(2);
+      await context.sendProgress({
+        progress: i + 1,
+        total: results.length,
+        message: `${offset + i + 1}. ${name ?? type} (${path}:${startLine}) [${score}]`,
+      });
+    }
+  }
+
   estimateTokens(args: Record<string, unknown>): number {
     const { format = this.config.defaultFormat, limit = this.config.defaultLimit } = args;
 
-    // Rough estimate based on format and limit
-    const tokensPerResult = format === 'verbose' ? 100 : 20;
+    // Rough estimate based on format and limit; JSON carries full snippets like verbose
+    const tokensPerResult = format === 'compact' ? 20 : 100;
     return (limit as number) * tokensPerResult + 50; // +50 for overhead
   }
 }
+
+/**
+ * Project a search result onto the structured output's result shape
+ */
+function toStructuredResult(result: SearchResult): SearchStructuredOutput['results'][number] {
+  const {
+    name,
+    type,
+    path,
+    startLine,
+    endLine,
+    signature,
+    exported,
+    snippet,
+    goVersion,
+    expandedTerms,
+    lastModified,
+    lastAuthor,
+    docBoost,
+    keywordBoost,
+    scoreDebug,
+  } = result.metadata;
+  return {
+    id: result.id,
+    score: result.score,
+    name,
+    type,
+    path,
+    startLine,
+    endLine,
+    signature,
+    exported,
+    snippet,
+    goVersion,
+    expandedTerms,
+    lastModified,
+    lastAuthor,
+    docBoost,
+    keywordBoost,
+    debug: scoreDebug,
+  };
+}
+
+/**
+ * Synonyms actually searched per query word (variants are capped)
+ */
+function searchedTerms(expansion: QueryExpansion): { term: string; synonyms: string[] }[] {
+  return expansion.terms
+    .map(({ term }) => ({
+      term,
+      synonyms: expansion.variants.filter((v) => v.term === term).map((v) => v.synonym),
+    }))
+    .filter(({ synonyms }) => synonyms.length > 0);
+}
+
+/**
+ * Section listing the synonyms searched and how many shown results each surfaced
+ */
+function formatExpansion(expansion: QueryExpansion, results: SearchResult[]): string {
+  const terms = searchedTerms(expansion);
+  if (terms.length === 0) {
+    return '\n\n---\n**Query expansion:** no synonyms for this query\n';
+  }
+
+  const counts = new Map<string, number>();
+  for (const result of results) {
+    for (const term of result.metadata.expandedTerms ?? []) {
+      counts.set(term, (counts.get(term) ?? 0) + 1);
+    }
+  }
+  const searched = terms.map(({ term, synonyms }) => `- ${term} → ${synonyms.join(', ')}`);
+  const contributed = [...counts].map(([term, count]) => `${term} (${count})`).join(', ');
+  return (
+    `\n\n---\n**Query expansion**\n${searched.join('\n')}\n` +
+    `Contributed: ${contributed || 'none'}\n`
+  );
+}
+
+/**
+ * Section breaking down each shown result's score
+ */
+function formatScoreDebug(results: SearchResult[]): string {
+  const lines = ['', '', '---', '**Score breakdown**'];
+  for (const result of results) {
+    const debug = result.metadata.scoreDebug;
+    if (!debug) continue;
+    const keyword =
+      debug.keywordScore === null
+        ? 'n/a'
+        : `${debug.keywordScore.toFixed(4)} (+${(debug.keywordBoost ?? 0).toFixed(4)})`;
+    const rerank = debug.rerank
+      ? `, rerank ${debug.rerank.score?.toFixed(4) ?? 'n/a'} ` +
+        `(#${debug.rerank.preRank} → #${debug.rerank.postRank})`
+      : '';
+    lines.push(
+      `${debug.rank}. ${result.metadata.name ?? result.id}: ` +
+        `vector ${debug.vectorScore.toFixed(4)} (#${debug.vectorRank}), ` +
+        `keyword ${keyword}, doc boost +${debug.docBoost.toFixed(4)}, ` +
+        `final ${debug.finalScore.toFixed(4)}${rerank}` +
+        (debug.matchedQuery === debug.embeddedQuery
+          ? ` via "${debug.matchedQuery}"`
+          : ` via "${debug.matchedQuery}" (embedded as "${debug.embeddedQuery}")`)
+    );
+  }
+  if (results[0]?.metadata.scoreDebug?.sort === 'recency') {
+    lines.push('Ordered by recency; final scores are shown for comparison only.');
+  }
+  const keywordWeights = results[0]?.metadata.scoreDebug?.keywordWeights;
+  if (keywordWeights) {
+    const weights = Object.entries(keywordWeights).map(
+      ([token, weight]) => `${token}=${weight.toFixed(2)}`
+    );
+    lines.push(`Keyword weights: ${weights.join(', ') || 'none'}.`);
+  }
+  const reranker = results[0]?.metadata.scoreDebug?.rerank;
+  if (reranker) {
+    lines.push(`Top candidates reranked by ${reranker.model}.`);
+  }
+  const feedback = results[0]?.metadata.scoreDebug?.feedback;
+  if (feedback) {
+    lines.push(
+      `Query adjusted by session feedback: ${feedback.relevant} relevant, ` +
+        `${feedback.nonRelevant} not relevant.`
+    );
+  }
+  return `${lines.join('\n')}\n`;
+}
+
+/**
+ * Say plainly that the index has no good answer, instead of an empty list
+ */
+function formatNoStrongMatches(query: string, minScore: number): FormattedResult {
+  const content =
+    `No results scored ${minScore} or higher for "${query}", so there are no strong matches. ` +
+    'The indexed code likely has no answer to this query; try other terms, ' +
+    'or lower minScore to see weak matches.';
+  return { content, tokens: estimateTokensForText(content) };
+}
+
+/**
+ * Footer telling the caller where this page sits and how to get the next one
+ */
+function formatPage(
+  offset: number,
+  returned: number,
+  total: number,
+  totalIsEstimate: boolean,
+  nextCursor: string | undefined
+): string {
+  const range = returned > 0 ? `${offset + 1}-${offset + returned}` : 'none';
+  const of = totalIsEstimate ? `at least ${total}` : `${total}`;
+  const next = nextCursor ? `\n\nNext page: \`cursor: "${nextCursor}"\`` : '';
+  return `\n\n---\n**Results ${range} of ${of}**${next}\n`;
+}
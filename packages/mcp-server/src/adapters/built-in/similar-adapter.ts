@@ -0,0 +1,214 @@
+/**
+ * Similar Adapter
+ * Finds semantically similar code via the dev_similar tool
+ */
+
+import type { SearchService, SimilarCodeMatch, SimilarCodeResult } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { SimilarArgsSchema } from '../../schemas/index.js';
+import { embeddingsUnavailableResult } from '../errors';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Similar adapter configuration
+ */
+export interface SimilarAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default result limit
+   */
+  defaultLimit?: number;
+}
+
+/**
+ * Similar Adapter
+ * Implements the dev_similar tool for finding duplicated or related logic
+ */
+export class SimilarAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'similar-adapter',
+    version: '1.0.0',
+    description: 'Embedding-based similar code adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<SimilarAdapterConfig, 'searchService'>>;
+
+  constructor(config: SimilarAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultLimit: config.defaultLimit ?? 10,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('SimilarAdapter initialized', {
+      defaultLimit: this.config.defaultLimit,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_similar',
+      description:
+        'Find code semantically similar to a symbol or snippet elsewhere in the repo. ' +
+        'Near-identical bodies (copy-paste) are listed separately from merely related code, ' +
+        'which makes this useful for spotting duplicated logic worth refactoring.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Indexed symbol name (e.g., "retryRequest" or "Client.Do")',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          snippet: {
+            type: 'string',
+            description: 'Code snippet to compare against (used when no symbol is given)',
+          },
+          limit: {
+            type: 'number',
+            description: `Maximum number of matches (default: ${this.config.defaultLimit})`,
+            minimum: 1,
+            maximum: 50,
+            default: this.config.defaultLimit,
+          },
+          threshold: {
+            type: 'number',
+            description: 'Minimum similarity score (0-1). Lower = more results (default: 0.75)',
+            minimum: 0,
+            maximum: 1,
+            default: 0.75,
+          },
+          nearIdenticalThreshold: {
+            type: 'number',
+            description: 'Score at or above which a match is flagged near-identical (default: 0.95)',
+            minimum: 0,
+            maximum: 1,
+            default: 0.95,
+          },
+        },
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(SimilarArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { symbol, path, snippet, limit, threshold, nearIdenticalThreshold } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Finding similar code', { symbol, path, limit, threshold });
+
+      const result = await this.searchService.findSimilarCode(
+        { symbol, path, snippet },
+        { limit, threshold, nearIdenticalThreshold }
+      );
+
+      if (symbol && !result.reference) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${symbol}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const content = this.formatResult(result, symbol);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Similar code found', {
+        symbol,
+        nearIdentical: result.nearIdentical.length,
+        related: result.related.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      const unavailable = embeddingsUnavailableResult(error);
+      if (unavailable) {
+        context.logger.warn('Embeddings unavailable', { error: unavailable.error?.message });
+        return unavailable;
+      }
+      context.logger.error('Similar code search failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'SIMILAR_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  /**
+   * Format similar code results as markdown
+   */
+  private formatResult(result: SimilarCodeResult, symbol?: string): string {
+    const lines: string[] = [];
+
+    if (result.reference) {
+      const { name, path, startLine } = result.reference.metadata;
+      lines.push(`# Similar to ${name}`, '', `Reference: \`${path}:${startLine}\``, '');
+    } else {
+      lines.push(symbol ? `# Similar to ${symbol}` : '# Similar to snippet', '');
+    }
+
+    if (result.nearIdentical.length === 0 && result.related.length === 0) {
+      lines.push('*No similar code found above the threshold*');
+      return lines.join('\n');
+    }
+
+    if (result.nearIdentical.length > 0) {
+      lines.push('## Near-Identical', '');
+      lines.push(...result.nearIdentical.map((match) => this.formatMatch(match)), '');
+    }
+
+    if (result.related.length > 0) {
+      lines.push('## Related', '');
+      lines.push(...result.related.map((match) => this.formatMatch(match)), '');
+    }
+
+    return lines.join('\n').trimEnd();
+  }
+
+  private formatMatch(match: SimilarCodeMatch): string {
+    const { name, type, path, startLine } = match.metadata;
+    const score = (match.score * 100).toFixed(0);
+    return `- **${name}** (${type}) - \`${path}:${startLine}\` [${score}%]`;
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { limit = this.config.defaultLimit } = args;
+    return (limit as number) * 25 + 30;
+  }
+}
@@ -0,0 +1,152 @@
+/**
+ * SQL Adapter
+ * Lists the SQL queries embedded in the indexed code via the dev_sql tool
+ */
+
+import { formatSqlQueries, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { SqlArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * SQL adapter configuration
+ */
+export interface SqlAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * SQL Adapter
+ * Implements the dev_sql tool: which functions run which queries, against which tables
+ */
+export class SqlAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'sql-adapter',
+    version: '1.0.0',
+    description: 'Embedded SQL query listing adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: SqlAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('SqlAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_sql',
+      description:
+        'List the SQL queries written as string literals in the indexed Go code, grouped by ' +
+        'package, with the function or method running each one and the tables it names. ' +
+        'Use it to answer "which functions query the users table" or to see what a ' +
+        'package does to the database.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          table: {
+            type: 'string',
+            description:
+              'Only queries naming this table (e.g., "users"; also matches "public.users")',
+          },
+          operation: {
+            type: 'string',
+            enum: ['select', 'insert', 'update', 'delete'],
+            description: 'Only queries of this kind',
+          },
+          pathPrefix: {
+            type: 'string',
+            description: 'Only queries in files under this path (e.g., "internal/store")',
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include queries in test files (default: false)',
+            default: false,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum queries to list (default: 200)',
+            minimum: 1,
+            maximum: 1000,
+            default: 200,
+          },
+        },
+        required: [],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(SqlArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { table, operation, pathPrefix, includeTests, limit } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Listing SQL queries', { table, operation, pathPrefix, limit });
+
+      const queryMap = await this.searchService.getSqlQueries({
+        table,
+        operation,
+        pathPrefix,
+        includeTests,
+        limit,
+      });
+
+      let content = formatSqlQueries(queryMap);
+      if (queryMap.total === 0) {
+        content =
+          table || operation || pathPrefix
+            ? 'No SQL queries match these filters.\n'
+            : 'No SQL queries found. Queries are extracted from Go string literals at index ' +
+              'time; re-run `dev index` if the index predates query extraction.\n';
+      }
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('SQL queries listed', {
+        queries: queryMap.total,
+        tables: queryMap.tables.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('SQL query listing failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'SQL_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const limit = typeof args.limit === 'number' ? args.limit : 200;
+    return Math.min(limit, 50) * 50;
+  }
+}
@@ -8,6 +8,9 @@ import * as path from 'node:path';
 import type { GitHubService, StatsService } from '@lytics/dev-agent-core';
 import { estimateTokensForText } from '../../formatters/utils';
 import { StatusArgsSchema } from '../../schemas/index.js';
+import { formatFreshness, type IndexFreshnessMonitor } from '../../server/index-freshness';
+import { formatReindexJob, type ReindexJobs } from '../../server/reindex-jobs';
+import { formatResultCacheStats, type ResultCache } from '../../utils/result-cache';
 import { ToolAdapter } from '../tool-adapter';
 import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
 import { validateArgs } from '../validation.js';
@@ -45,6 +48,21 @@ export interface StatusAdapterConfig {
    * Default section to display
    */
   defaultSection?: StatusSection;
+
+  /**
+   * Optional monitor reporting how far the index has drifted from the repository
+   */
+  freshness?: Pick<IndexFreshnessMonitor, 'latest'>;
+
+  /**
+   * Optional result cache whose hit rate is reported
+   */
+  resultCache?: Pick<ResultCache, 'stats'>;
+
+  /**
+   * Optional reindex job runner whose latest job is reported
+   */
+  reindexJobs?: Pick<ReindexJobs, 'latest'>;
 }
 
 /**
@@ -64,6 +82,9 @@ export class StatusAdapter extends ToolAdapter {
   private vectorStorePath: string;
   private defaultSection: StatusSection;
   private githubService?: GitHubService;
+  private freshness?: Pick<IndexFreshnessMonitor, 'latest'>;
+  private resultCache?: Pick<ResultCache, 'stats'>;
+  private reindexJobs?: Pick<ReindexJobs, 'latest'>;
   private githubStatePath?: string; // Track state file path for reload
   private lastStateFileModTime?: number; // Track state file modification time for auto-reload
 
@@ -73,6 +94,9 @@ export class StatusAdapter extends ToolAdapter {
     this.repositoryPath = config.repositoryPath;
     this.vectorStorePath = config.vectorStorePath;
     this.githubService = config.githubService;
+    this.freshness = config.freshness;
+    this.resultCache = config.resultCache;
+    this.reindexJobs = config.reindexJobs;
     this.defaultSection = config.defaultSection ?? 'summary';
   }
 
@@ -259,9 +283,17 @@ export class StatusAdapter extends ToolAdapter {
         `**Repository:** ${this.repositoryPath} (${repoStats.filesScanned} files indexed)`
       );
       lines.push(`**Last Scan:** ${timeAgo}`);
+      const freshness = this.describeFreshness();
+      if (freshness) {
+        lines.push(`**Freshness:** ${freshness}`);
+      }
     } else {
       lines.push(`**Repository:** ${this.repositoryPath} (not indexed)`);
     }
+    const reindex = this.describeReindex();
+    if (reindex) {
+      lines.push(`**Reindex:** ${reindex}`);
+    }
 
     lines.push('');
 
@@ -281,6 +313,9 @@ export class StatusAdapter extends ToolAdapter {
       const storageSize = await this.getStorageSize();
       lines.push(`**Storage:** ${this.formatBytes(storageSize)} (LanceDB)`);
     }
+    if (this.resultCache) {
+      lines.push(`**Result Cache:** ${formatResultCacheStats(this.resultCache.stats())}`);
+    }
 
     lines.push('');
 
@@ -333,6 +368,9 @@ export class StatusAdapter extends ToolAdapter {
     } else {
       lines.push('- **GitHub Index:** Not indexed');
     }
+    if (this.resultCache) {
+      lines.push(`- **Result Cache:** ${formatResultCacheStats(this.resultCache.stats())}`);
+    }
     lines.push('');
 
     // Health
@@ -373,6 +411,14 @@ export class StatusAdapter extends ToolAdapter {
     const startTimeISO =
       typeof stats.startTime === 'string' ? stats.startTime : stats.startTime.toISOString();
     lines.push(`**Last Scan:** ${startTimeISO} (${this.formatTimeAgo(stats.startTime)})`);
+    const freshness = this.describeFreshness();
+    if (freshness) {
+      lines.push(`**Freshness:** ${freshness}`);
+    }
+    const reindex = this.describeReindex();
+    if (reindex) {
+      lines.push(`**Reindex:** ${reindex}`);
+    }
 
     if (format === 'verbose' && stats.errors.length > 0) {
       lines.push('');
@@ -558,6 +604,23 @@ export class StatusAdapter extends ToolAdapter {
       });
     }
 
+    // Index freshness (once the startup check has finished)
+    const freshness = this.freshness?.latest?.freshness;
+    if (freshness && freshness.staleFiles > 0) {
+      checks.push({
+        name: 'Index Freshness',
+        status: 'warning',
+        message: formatFreshness(freshness),
+        details: 'Run "dev update" to re-index changed files',
+      });
+    } else if (freshness) {
+      checks.push({
+        name: 'Index Freshness',
+        status: 'ok',
+        message: formatFreshness(freshness),
+      });
+    }
+
     // GitHub CLI
     try {
       const { execSync } = await import('node:child_process');
@@ -635,6 +698,28 @@ export class StatusAdapter extends ToolAdapter {
     return checks;
   }
 
+  /**
+   * Freshness summary from the last check, if one has finished
+   */
+  private describeFreshness(): string | undefined {
+    const report = this.freshness?.latest;
+    if (!report?.freshness) {
+      return undefined;
+    }
+    const summary = formatFreshness(report.freshness);
+    return report.reindexedFiles
+      ? `${summary} after re-indexing ${report.reindexedFiles} stale files`
+      : summary;
+  }
+
+  /**
+   * The latest dev_reindex job: "reindex-2 running (embedding, 45%)"
+   */
+  private describeReindex(): string | undefined {
+    const job = this.reindexJobs?.latest;
+    return job ? `${job.id} ${formatReindexJob(job)}` : undefined;
+  }
+
   /**
    * Get total storage size for vector indexes
    */
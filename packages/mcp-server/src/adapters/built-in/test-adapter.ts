@@ -0,0 +1,152 @@
+/**
+ * Test Adapter
+ * Finds the tests exercising a symbol via the dev_test tool
+ */
+
+import { formatSymbolTests, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { TestArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Test adapter configuration
+ */
+export interface TestAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default call hops followed toward tests
+   */
+  defaultDepth?: number;
+}
+
+/**
+ * Test Adapter
+ * Implements the dev_test tool for "is this code tested, and where"
+ */
+export class TestAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'test-adapter',
+    version: '1.0.0',
+    description: 'Symbol test coverage adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<TestAdapterConfig, 'searchService'>>;
+
+  constructor(config: TestAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultDepth: config.defaultDepth ?? 5,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('TestAdapter initialized', {
+      defaultDepth: this.config.defaultDepth,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_test',
+      description:
+        'Find the test functions that exercise a function or method, following the call graph. ' +
+        'Direct tests call it themselves; transitive tests reach it through other code, shown ' +
+        'with the call chain. Symbols no test reaches are flagged as untested. ' +
+        'Use to answer "is this code tested, and where" before changing it.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Indexed symbol name (e.g., "NewExpBackoff" or "Client.Do")',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          depth: {
+            type: 'number',
+            description: `Call hops followed toward tests (default: ${this.config.defaultDepth})`,
+            minimum: 1,
+            maximum: 10,
+            default: this.config.defaultDepth,
+          },
+        },
+        required: ['symbol'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(TestArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { symbol, path, depth } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Finding symbol tests', { symbol, path, depth });
+
+      const result = await this.searchService.getSymbolTests(symbol, { path, depth });
+
+      if (!result) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${symbol}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const content = formatSymbolTests(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Symbol tests found', {
+        symbol,
+        direct: result.direct.length,
+        transitive: result.transitive.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Symbol test lookup failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'TEST_LOOKUP_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { depth = this.config.defaultDepth } = args;
+    return (depth as number) * 60 + 100;
+  }
+}
@@ -0,0 +1,161 @@
+/**
+ * Usage Adapter
+ * Shows how a symbol is actually called across the codebase via the dev_usage tool
+ */
+
+import { formatSymbolUsages, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { UsageArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Usage adapter configuration
+ */
+export interface UsageAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+
+  /**
+   * Default examples per group
+   */
+  defaultLimit?: number;
+}
+
+/**
+ * Usage Adapter
+ * Implements the dev_usage tool for "copy-pasteable examples from our own repo"
+ */
+export class UsageAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'usage-adapter',
+    version: '1.0.0',
+    description: 'Symbol usage examples adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+  private config: Required<Omit<UsageAdapterConfig, 'searchService'>>;
+
+  constructor(config: UsageAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+    this.config = {
+      defaultLimit: config.defaultLimit ?? 5,
+    };
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('UsageAdapter initialized', {
+      defaultLimit: this.config.defaultLimit,
+    });
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_usage',
+      description:
+        'Show real call sites of a symbol with the code around each call, so you can copy how ' +
+        'this codebase already uses it. Examples with different argument shapes come first; ' +
+        'test usages are listed separately since they often show canonical usage. ' +
+        'Use before calling an unfamiliar function or constructor.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Indexed symbol name (e.g., "NewExpBackoff" or "Client.Do")',
+          },
+          path: {
+            type: 'string',
+            description: 'Path prefix to pick between symbols with the same name',
+          },
+          limit: {
+            type: 'number',
+            description: `Maximum examples per group (default: ${this.config.defaultLimit})`,
+            minimum: 1,
+            maximum: 20,
+            default: this.config.defaultLimit,
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include examples from tests (default: true)',
+            default: true,
+          },
+        },
+        required: ['symbol'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(UsageArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { symbol, path, limit, includeTests } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Collecting symbol usages', { symbol, path, limit, includeTests });
+
+      const result = await this.searchService.getSymbolUsages(symbol, {
+        path,
+        limit,
+        includeTests,
+      });
+
+      if (!result) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `Symbol "${symbol}" not found in the index`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup to find the exact symbol name',
+          },
+        };
+      }
+
+      const content = formatSymbolUsages(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Symbol usages collected', {
+        symbol,
+        calls: result.totalCalls,
+        testCalls: result.totalTestCalls,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Symbol usage lookup failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'USAGE_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(args: Record<string, unknown>): number {
+    const { limit = this.config.defaultLimit, includeTests = true } = args;
+    return (limit as number) * (includeTests ? 2 : 1) * 120 + 50;
+  }
+}
@@ -0,0 +1,135 @@
+/**
+ * Whereis Adapter
+ * Finds symbol definitions by exact name via the dev_whereis tool
+ */
+
+import { formatDefinitions, type SearchService } from '@lytics/dev-agent-core';
+import { estimateTokensForText, startTimer } from '../../formatters/utils';
+import { WhereisArgsSchema } from '../../schemas/index.js';
+import { ToolAdapter } from '../tool-adapter';
+import type { AdapterContext, ToolDefinition, ToolExecutionContext, ToolResult } from '../types';
+import { validateArgs } from '../validation.js';
+
+/**
+ * Whereis adapter configuration
+ */
+export interface WhereisAdapterConfig {
+  /**
+   * Search service instance
+   */
+  searchService: SearchService;
+}
+
+/**
+ * Whereis Adapter
+ * Implements the dev_whereis tool, the "go to definition" primitive
+ */
+export class WhereisAdapter extends ToolAdapter {
+  readonly metadata = {
+    name: 'whereis-adapter',
+    version: '1.0.0',
+    description: 'Symbol definition lookup adapter',
+    author: 'Dev-Agent Team',
+  };
+
+  private searchService: SearchService;
+
+  constructor(config: WhereisAdapterConfig) {
+    super();
+    this.searchService = config.searchService;
+  }
+
+  async initialize(context: AdapterContext): Promise<void> {
+    context.logger.info('WhereisAdapter initialized');
+  }
+
+  getToolDefinition(): ToolDefinition {
+    return {
+      name: 'dev_whereis',
+      description:
+        'Go to definition: where a symbol is defined, by exact name (e.g. "NewServer", ' +
+        '"Client.Do", "store.Get"), with file, line, and signature. No fuzzy matching ' +
+        'and no semantic search; when several packages define the name, every definition ' +
+        'is listed with its package. Use dev_lookup when you only know part of the name.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          name: {
+            type: 'string',
+            description:
+              'Exact symbol name; methods as Type.Method. Optionally qualified by package ' +
+              'or package directory (e.g., "store.Get", "internal/store.Cache.Get")',
+          },
+          includeTests: {
+            type: 'boolean',
+            description: 'Include definitions in test files (default: false)',
+            default: false,
+          },
+        },
+        required: ['name'],
+      },
+    };
+  }
+
+  async execute(args: Record<string, unknown>, context: ToolExecutionContext): Promise<ToolResult> {
+    const validation = validateArgs(WhereisArgsSchema, args);
+    if (!validation.success) {
+      return validation.error;
+    }
+
+    const { name, includeTests } = validation.data;
+
+    try {
+      const timer = startTimer();
+      context.logger.debug('Finding definitions', { name, includeTests });
+
+      const result = await this.searchService.getDefinitions(name, { includeTests });
+
+      if (!result) {
+        return {
+          success: false,
+          error: {
+            code: 'SYMBOL_NOT_FOUND',
+            message: `No indexed symbol is named "${name}"`,
+            recoverable: true,
+            suggestion: 'Use dev_lookup for partial or misspelled names',
+          },
+        };
+      }
+
+      const content = formatDefinitions(result);
+      const duration_ms = timer.elapsed();
+
+      context.logger.info('Definitions found', {
+        name,
+        definitions: result.definitions.length,
+        duration_ms,
+      });
+
+      return {
+        success: true,
+        data: content,
+        metadata: {
+          tokens: estimateTokensForText(content),
+          duration_ms,
+          timestamp: new Date().toISOString(),
+          cached: false,
+        },
+      };
+    } catch (error) {
+      context.logger.error('Definition lookup failed', { error });
+      return {
+        success: false,
+        error: {
+          code: 'WHEREIS_FAILED',
+          message: error instanceof Error ? error.message : 'Unknown error',
+          details: error,
+        },
+      };
+    }
+  }
+
+  estimateTokens(): number {
+    return 150;
+  }
+}
@@ -0,0 +1,28 @@
+/**
+ * Error results shared by adapters
+ */
+
+import { EmbeddingsUnavailableError } from '@lytics/dev-agent-core';
+import type { ToolResult } from './types';
+
+/**
+ * Result for a tool that needs embeddings when the embedding model can't be
+ * loaded, or null for any other error
+ *
+ * Tools that look symbols up by name don't need the model, so the suggestion
+ * points there.
+ */
+export function embeddingsUnavailableResult(error: unknown): ToolResult | null {
+  if (!(error instanceof EmbeddingsUnavailableError)) return null;
+  return {
+    success: false,
+    error: {
+      code: 'EMBEDDINGS_UNAVAILABLE',
+      message: error.message,
+      recoverable: true,
+      suggestion:
+        'Semantic search needs the embedding model. Name-based tools still work: ' +
+        'dev_whereis, dev_lookup, dev_outline, dev_inspect',
+    },
+  };
+}
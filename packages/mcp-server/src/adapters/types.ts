@@ -23,6 +23,18 @@ export interface AdapterContext {
 // Tool Execution Context (provided during tool execution)
 export interface ToolExecutionContext extends AdapterContext {
   userId?: string; // For multi-user scenarios
+  /**
+   * Send an MCP progress notification for this call.
+   * Only set when the client asked for progress (a `progressToken` in `_meta`).
+   */
+  sendProgress?: (progress: ToolProgress) => Promise<void>;
+}
+
+// Progress update for a running tool call (MCP `notifications/progress`)
+export interface ToolProgress {
+  progress: number;
+  total?: number;
+  message?: string;
 }
 
 // Logger Interface
@@ -65,6 +77,10 @@ export interface MCPMetadata {
   results_returned?: number;
   /** Whether results were truncated due to limits */
   results_truncated?: boolean;
+  /** Whether results_total is a lower bound (more matches may exist) */
+  results_total_is_estimate?: boolean;
+  /** Cursor for the next page of results, if there is one */
+  next_cursor?: string;
 
   // Related files (optional)
   /** Number of related test files found */
@@ -132,6 +132,19 @@ describe('Formatters', () => {
       expect(formatted).toContain('lines: 28'); // endLine - startLine + 1
     });
 
+    it('should name the generator of generated code', () => {
+      const formatter = new VerboseFormatter();
+      const mock = { ...mockResults[0], metadata: { ...mockResults[0].metadata, language: 'go' } };
+
+      const formatted = formatter.formatResult({
+        ...mock,
+        metadata: { ...mock.metadata, generated: true, generator: 'MockGen' },
+      });
+
+      expect(formatted).toContain('generated by MockGen');
+      expect(formatter.formatResult(mock)).not.toContain('generated');
+    });
+
     it('should format multiple results with separators', () => {
       const formatter = new VerboseFormatter();
       const result = formatter.formatResults(mockResults);
@@ -141,6 +141,11 @@ export class VerboseFormatter implements ResultFormatter {
       metadata.push(`exported: ${result.metadata.exported}`);
     }
 
+    if (result.metadata.generated) {
+      const { generator } = result.metadata;
+      metadata.push(generator ? `generated by ${generator}` : 'generated');
+    }
+
     if (
       typeof result.metadata.endLine === 'number' &&
       typeof result.metadata.startLine === 'number' &&
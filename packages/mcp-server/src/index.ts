@@ -12,7 +12,26 @@ export * from './adapters/types';
 // Formatter exports
 export * from './formatters';
 // Core exports
+export {
+  AUTO_REINDEX_ENV,
+  type FreshnessReport,
+  formatFreshness,
+  IndexFreshnessMonitor,
+  type IndexFreshnessMonitorConfig,
+  isAutoReindexEnabled,
+} from './server/index-freshness';
 export { MCPServer, type MCPServerConfig } from './server/mcp-server';
+export {
+  formatIndexProgress,
+  formatReindexJob,
+  type ReindexJob,
+  ReindexJobs,
+  type ReindexJobStatus,
+  type ReindexJobsConfig,
+  type ReindexMode,
+  type ReindexProgressListener,
+  type ReindexRequest,
+} from './server/reindex-jobs';
 // Protocol exports
 export * from './server/protocol/jsonrpc';
 export * from './server/protocol/types';
@@ -25,3 +44,20 @@ export {
 } from './server/transport/transport';
 // Utility exports
 export { ConsoleLogger } from './utils/logger';
+export {
+  fitOutputToBudget,
+  MAX_OUTPUT_TOKENS_ENV,
+  MIN_OUTPUT_TOKENS,
+  type OutputBudgetResult,
+  type OutputTokenCounter,
+  parseMaxOutputTokens,
+} from './utils/output-budget';
+export {
+  DEFAULT_RESULT_CACHE_SIZE,
+  formatResultCacheStats,
+  parseResultCacheSize,
+  RESULT_CACHE_SIZE_ENV,
+  ResultCache,
+  type ResultCacheStats,
+  resultCacheKey,
+} from './utils/result-cache';
@@ -6,6 +6,7 @@
  */
 
 import { z } from 'zod';
+import type { JSONSchema } from '../server/protocol/types';
 
 // ============================================================================
 // Shared Base Schemas
@@ -16,6 +17,16 @@ import { z } from 'zod';
  */
 export const FormatSchema = z.enum(['compact', 'verbose']);
 
+/**
+ * Output format for tools that offer strict JSON alongside their markdown
+ */
+export const StructuredFormatSchema = z.enum(['compact', 'verbose', 'json']);
+
+/**
+ * Output format for tools with a single markdown layout
+ */
+export const TextOrJsonFormatSchema = z.enum(['text', 'json']);
+
 /**
  * Base schema for queries with pagination and formatting
  */
@@ -30,10 +41,23 @@ export const BaseQuerySchema = z.object({
 
 export const InspectArgsSchema = z
   .object({
-    query: z.string().min(1, 'Query must be a non-empty string (file path)'),
+    query: z.string().min(1, 'Query must be a non-empty string (file path or symbol)'),
+    target: z.enum(['file', 'symbol']).default('file'),
+    // File inspection
     limit: z.number().int().min(1).max(50).default(10),
     threshold: z.number().min(0).max(1).default(0.7),
-    format: FormatSchema.default('compact'),
+    // Symbol inspection
+    sections: z
+      .array(z.enum(['definition', 'callers', 'callees', 'implements', 'git']))
+      .min(1)
+      .optional(),
+    path: z.string().optional(), // Disambiguates symbols with the same name
+    tokenBudget: z.number().int().min(200).max(20000).default(2000),
+    includeTests: z.boolean().default(false),
+    format: StructuredFormatSchema.default('compact'),
+  })
+  .refine((data) => data.format !== 'json' || data.target === 'symbol', {
+    message: 'JSON output is only available for symbol inspection',
   })
   .strict(); // Reject unknown properties
 
@@ -57,32 +81,394 @@ export type ExploreArgs = z.infer<typeof ExploreArgsSchema>;
 // Search Adapter
 // ============================================================================
 
+const SearchExampleSchema = z.string().min(1, 'Examples must be non-empty strings');
+
 export const SearchArgsSchema = z
   .object({
-    query: z.string().min(1, 'Query must be a non-empty string'),
-    format: FormatSchema.default('compact'),
+    query: z.string().min(1, 'Query must be a non-empty string').optional(),
+    // Several examples searched together ("more like these"); see core search/multi-vector.ts
+    queries: z.array(SearchExampleSchema).min(1).max(10).optional(),
+    symbols: z.array(SearchExampleSchema).min(1).max(10).optional(), // Names or document IDs
+    pooling: z.enum(['mean', 'max', 'separate']).default('mean'),
+    excludeInputs: z.boolean().default(true),
+    format: StructuredFormatSchema.default('compact'),
     limit: z.number().int().min(1).max(50).default(10),
     scoreThreshold: z.number().min(0).max(1).default(0),
+    minScore: z.number().min(0).max(1).default(0.3), // Final-score cutoff (core DEFAULT_MIN_SCORE)
     tokenBudget: z.number().int().min(500).max(10000).optional(),
+    exportedOnly: z.boolean().default(false),
+    includeGenerated: z.boolean().default(false), // where: { generated: true } searches only them
+    module: z.string().min(1).optional(),
+    // Exact-match metadata filters, e.g. custom fields added by index-time enrichers
+    where: z.record(z.string().min(1), z.union([z.string(), z.number(), z.boolean()])).optional(),
+    pathFilter: z.string().trim().min(1).optional(), // Path prefix or glob (e.g. "**/*_test.go")
+    // Identifiers results must (not) contain, by identifier tokens (e.g. "ValidatePassword")
+    mustContain: z.array(z.string().trim().min(1)).min(1).max(10).optional(),
+    mustNotContain: z.array(z.string().trim().min(1)).min(1).max(10).optional(),
+    contextLines: z.number().int().min(0).max(20).default(0),
+    cursor: z.string().min(1).optional(), // Opaque; from a previous page's next_cursor
+    expand: z.boolean().default(false), // Also search synonym variants of the query
+    changedSince: z
+      .string()
+      .refine((value) => !Number.isNaN(Date.parse(value)), 'changedSince must be an ISO date')
+      .optional(), // Requires an index built with blame enabled
+    sort: z.enum(['relevance', 'recency']).default('relevance'),
+    docWeight: z.number().min(0).max(0.2).optional(), // Doc quality tie-breaker; service default
+    keywordWeight: z.number().min(0).max(0.2).optional(), // IDF keyword boost; service default
+    debug: z.boolean().default(false), // Per-result score breakdown
+    session: z.string().min(1).max(200).optional(), // Relevance feedback from dev_feedback
   })
-  .strict();
+  .strict()
+  .refine((data) => Boolean(data.query || data.queries || data.symbols), {
+    message: 'Provide a query, queries, or symbols',
+    path: ['query'],
+  })
+  .refine(
+    (data) =>
+      !(data.queries || data.symbols) ||
+      !(
+        data.cursor ||
+        data.expand ||
+        data.debug ||
+        data.session ||
+        data.keywordWeight !== undefined
+      ),
+    {
+      message:
+        'cursor, expand, debug, session, and keywordWeight apply to a single query, ' +
+        'not to queries or symbols',
+      path: ['queries'],
+    }
+  );
 
 export type SearchArgs = z.infer<typeof SearchArgsSchema>;
 
+// ============================================================================
+// Feedback Adapter
+// ============================================================================
+
+export const FeedbackArgsSchema = z
+  .object({
+    session: z.string().min(1).max(200), // Same id later passed to dev_search
+    symbol: z.string().min(1).optional(), // Symbol name or document ID of a search result
+    relevant: z.boolean().default(true),
+    clear: z.boolean().default(false), // Forget the session's feedback instead
+  })
+  .strict()
+  .refine((data) => Boolean(data.symbol) !== data.clear, {
+    message: 'Provide a symbol to mark, or clear: true (not both)',
+    path: ['symbol'],
+  });
+
+export type FeedbackArgs = z.infer<typeof FeedbackArgsSchema>;
+
 // ============================================================================
 // Refs Adapter
 // ============================================================================
 
+const RefsNameSchema = z.string().min(1, 'Name must be a non-empty string');
+
 export const RefsArgsSchema = z
   .object({
-    name: z.string().min(1, 'Name must be a non-empty string'),
+    // One symbol, or a batch resolved against a single index load
+    name: z.union([RefsNameSchema, z.array(RefsNameSchema).min(1).max(25)]),
     direction: z.enum(['callees', 'callers', 'both']).default('both'),
     limit: z.number().int().min(1).max(50).default(20),
+    format: TextOrJsonFormatSchema.default('text'),
   })
   .strict();
 
 export type RefsArgs = z.infer<typeof RefsArgsSchema>;
 
+// ============================================================================
+// Lookup Adapter
+// ============================================================================
+
+export const LookupArgsSchema = z
+  .object({
+    query: z.string().min(1, 'Query must be a non-empty string'),
+    kind: z.enum(['function', 'method', 'class', 'interface', 'type', 'variable']).optional(),
+    path: z.string().optional(),
+    limit: z.number().int().min(1).max(50).default(10),
+    minScore: z.number().min(0).max(1).default(0.3),
+    format: TextOrJsonFormatSchema.default('text'),
+  })
+  .strict();
+
+export type LookupArgs = z.infer<typeof LookupArgsSchema>;
+
+// ============================================================================
+// Similar Adapter
+// ============================================================================
+
+export const SimilarArgsSchema = z
+  .object({
+    symbol: z.string().min(1).optional(),
+    path: z.string().optional(), // Disambiguates symbols with the same name
+    snippet: z.string().min(1).optional(),
+    limit: z.number().int().min(1).max(50).default(10),
+    threshold: z.number().min(0).max(1).default(0.75),
+    nearIdenticalThreshold: z.number().min(0).max(1).default(0.95),
+  })
+  .refine((data) => data.symbol || data.snippet, {
+    message: 'Either symbol or snippet must be provided',
+  })
+  .strict();
+
+export type SimilarArgs = z.infer<typeof SimilarArgsSchema>;
+
+// ============================================================================
+// Context Adapter
+// ============================================================================
+
+export const ContextArgsSchema = z
+  .object({
+    symbol: z.string().min(1),
+    path: z.string().optional(), // Disambiguates symbols with the same name
+    depth: z.number().int().min(1).max(3).default(1),
+    tokenBudget: z.number().int().min(500).max(20000).default(4000),
+    includeTypes: z.boolean().default(true),
+  })
+  .strict();
+
+export type ContextArgs = z.infer<typeof ContextArgsSchema>;
+
+// ============================================================================
+// Usage Adapter
+// ============================================================================
+
+export const UsageArgsSchema = z
+  .object({
+    symbol: z.string().min(1),
+    path: z.string().optional(), // Disambiguates symbols with the same name
+    limit: z.number().int().min(1).max(20).default(5),
+    includeTests: z.boolean().default(true),
+  })
+  .strict();
+
+export type UsageArgs = z.infer<typeof UsageArgsSchema>;
+
+// ============================================================================
+// Explain Adapter
+// ============================================================================
+
+export const ExplainArgsSchema = z
+  .object({
+    symbol: z.string().min(1),
+    path: z.string().optional(), // Disambiguates symbols with the same name
+    examples: z.number().int().min(0).max(5).default(2), // Go Example functions first
+    maxCallees: z.number().int().min(0).max(50).default(10),
+  })
+  .strict();
+
+export type ExplainArgs = z.infer<typeof ExplainArgsSchema>;
+
+// ============================================================================
+// Test Adapter
+// ============================================================================
+
+export const TestArgsSchema = z
+  .object({
+    symbol: z.string().min(1),
+    path: z.string().optional(), // Disambiguates symbols with the same name
+    depth: z.number().int().min(1).max(10).default(5),
+  })
+  .strict();
+
+export type TestArgs = z.infer<typeof TestArgsSchema>;
+
+// ============================================================================
+// Outline Adapter
+// ============================================================================
+
+export const OutlineArgsSchema = z
+  .object({
+    package: z.string().min(1), // Package directory, or a trailing part of it
+    includeUnexported: z.boolean().default(false),
+    limit: z.number().int().min(1).max(100).default(30),
+  })
+  .strict();
+
+export type OutlineArgs = z.infer<typeof OutlineArgsSchema>;
+
+// ============================================================================
+// Impl Adapter
+// ============================================================================
+
+export const ImplArgsSchema = z
+  .object({
+    interface: z.string().min(1), // Interface name, optionally package-qualified (io.Reader)
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(200).default(50),
+  })
+  .strict();
+
+export type ImplArgs = z.infer<typeof ImplArgsSchema>;
+
+// ============================================================================
+// Whereis Adapter
+// ============================================================================
+
+export const WhereisArgsSchema = z
+  .object({
+    name: z.string().min(1), // Exact symbol name, optionally package-qualified (store.Get)
+    includeTests: z.boolean().default(false),
+  })
+  .strict();
+
+export type WhereisArgs = z.infer<typeof WhereisArgsSchema>;
+
+// ============================================================================
+// Routes Adapter
+// ============================================================================
+
+export const RoutesArgsSchema = z
+  .object({
+    pathPrefix: z.string().optional(), // Only routes under this path (/api/users)
+    method: z.string().min(1).optional(), // Only routes serving this HTTP method
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(1000).default(200),
+  })
+  .strict();
+
+export type RoutesArgs = z.infer<typeof RoutesArgsSchema>;
+
+// ============================================================================
+// OpenAPI Adapter
+// ============================================================================
+
+export const OpenApiArgsSchema = z
+  .object({
+    pathPrefix: z.string().optional(), // Only operations under this path (/users)
+    method: z.string().min(1).optional(), // Only operations with this HTTP method
+    tag: z.string().min(1).optional(), // Only operations with this spec tag
+    unlinkedOnly: z.boolean().default(false), // Only operations no route implements
+    limit: z.number().int().min(1).max(1000).default(200),
+  })
+  .strict();
+
+export type OpenApiArgs = z.infer<typeof OpenApiArgsSchema>;
+
+// ============================================================================
+// SQL Adapter
+// ============================================================================
+
+export const SqlArgsSchema = z
+  .object({
+    table: z.string().min(1).optional(), // Only queries naming this table (users)
+    operation: z.enum(['select', 'insert', 'update', 'delete']).optional(),
+    pathPrefix: z.string().optional(), // Only queries in files under this path (internal/store)
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(1000).default(200),
+  })
+  .strict();
+
+export type SqlArgs = z.infer<typeof SqlArgsSchema>;
+
+// ============================================================================
+// Constraints Adapter
+// ============================================================================
+
+export const ConstraintsArgsSchema = z
+  .object({
+    name: z.string().min(1).optional(), // Only this constraint (Ordered, constraints.Ordered)
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(500).default(100),
+  })
+  .strict();
+
+export type ConstraintsArgs = z.infer<typeof ConstraintsArgsSchema>;
+
+// ============================================================================
+// Graph Adapter
+// ============================================================================
+
+export const GraphArgsSchema = z
+  .object({
+    symbol: z.string().min(1).optional(), // Start from a symbol (Server.CreateUser)
+    package: z.string().min(1).optional(), // Or every symbol in a package (internal/users)
+    path: z.string().optional(), // Path prefix to disambiguate the symbol
+    depth: z.number().int().min(0).max(5).default(1),
+    direction: z.enum(['callees', 'callers', 'both']).default('both'),
+    format: z.enum(['dot', 'json']).default('dot'),
+    includeExternal: z.boolean().default(false),
+    includeImplements: z.boolean().default(true),
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(1000).default(200),
+  })
+  .strict()
+  .refine((data) => data.symbol || data.package, {
+    message: 'Either symbol or package must be provided',
+  });
+
+export type GraphArgs = z.infer<typeof GraphArgsSchema>;
+
+// ============================================================================
+// Recursion Adapter
+// ============================================================================
+
+export const RecursionArgsSchema = z
+  .object({
+    package: z.string().min(1).optional(), // Only cycles touching this package (internal/parser)
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(500).default(50),
+  })
+  .strict();
+
+export type RecursionArgs = z.infer<typeof RecursionArgsSchema>;
+
+// ============================================================================
+// Call Path Adapter
+// ============================================================================
+
+export const CallPathArgsSchema = z
+  .object({
+    from: z.string().min(1), // Symbol to start from (Server.Handle)
+    to: z.string().min(1), // Symbol to reach (Store.Save)
+    fromPath: z.string().optional(), // Path prefixes to disambiguate either symbol
+    toPath: z.string().optional(),
+    limit: z.number().int().min(1).max(10).default(1),
+    maxDepth: z.number().int().min(1).max(12).default(6),
+    includeTests: z.boolean().default(false),
+  })
+  .strict();
+
+export type CallPathArgs = z.infer<typeof CallPathArgsSchema>;
+
+// ============================================================================
+// Deps Adapter
+// ============================================================================
+
+export const DepsArgsSchema = z
+  .object({
+    module: z.string().trim().min(1).optional(), // Substring of the module path (golang.org/x/net)
+    includeIndirect: z.boolean().default(true),
+    includeTests: z.boolean().default(false),
+    limit: z.number().int().min(1).max(1000).default(200),
+  })
+  .strict();
+
+export type DepsArgs = z.infer<typeof DepsArgsSchema>;
+
+// ============================================================================
+// Neighbors Adapter
+// ============================================================================
+
+export const NeighborsArgsSchema = z
+  .object({
+    symbol: z.string().min(1).optional(), // Start from a symbol (Cache.Get)
+    file: z.string().min(1).optional(), // Or from a line in a file
+    line: z.number().int().min(1).optional(),
+    path: z.string().optional(), // Path prefix to disambiguate the symbol
+    count: z.number().int().min(1).max(20).default(3),
+  })
+  .strict()
+  .refine((data) => data.symbol || (data.file && data.line !== undefined), {
+    message: 'Either symbol or file and line must be provided',
+  });
+
+export type NeighborsArgs = z.infer<typeof NeighborsArgsSchema>;
+
 // ============================================================================
 // Map Adapter
 // ============================================================================
@@ -130,7 +516,7 @@ export const PlanArgsSchema = z
     includeGitHistory: z.boolean().default(true),
     includePatterns: z.boolean().default(true),
     tokenBudget: z.number().int().min(1000).max(10000).default(4000),
-    format: FormatSchema.default('compact'),
+    format: StructuredFormatSchema.default('compact'),
   })
   .strict();
 
@@ -197,6 +583,20 @@ export const StatusOutputSchema = z.object({
 
 export type StatusOutput = z.infer<typeof StatusOutputSchema>;
 
+// ============================================================================
+// Reindex Adapter
+// ============================================================================
+
+export const ReindexArgsSchema = z
+  .object({
+    mode: z.enum(['incremental', 'full']).default('incremental'),
+    jobId: z.string().min(1).optional(), // Poll a job instead of requesting one
+    wait: z.boolean().default(false), // Block until the job finishes, streaming progress
+  })
+  .strict();
+
+export type ReindexArgs = z.infer<typeof ReindexArgsSchema>;
+
 // ============================================================================
 // Health Adapter
 // ============================================================================
@@ -209,6 +609,37 @@ export const HealthArgsSchema = z
 
 export type HealthArgs = z.infer<typeof HealthArgsSchema>;
 
+// ============================================================================
+// Diff Adapter
+// ============================================================================
+
+export const DiffArgsSchema = z
+  .object({
+    base: z.string().min(1, 'Base revision is required'),
+    head: z.string().min(1).default('HEAD'),
+    path: z.string().optional(),
+    format: FormatSchema.default('compact'),
+    tokenBudget: z.number().int().min(500).max(10000).default(2000),
+  })
+  .strict();
+
+export type DiffArgs = z.infer<typeof DiffArgsSchema>;
+
+// ============================================================================
+// Changelog Adapter
+// ============================================================================
+
+export const ChangelogArgsSchema = z
+  .object({
+    base: z.string().min(1, 'Base revision is required'),
+    head: z.string().min(1).default('HEAD'),
+    path: z.string().optional(),
+    tokenBudget: z.number().int().min(500).max(10000).default(4000),
+  })
+  .strict();
+
+export type ChangelogArgs = z.infer<typeof ChangelogArgsSchema>;
+
 // ============================================================================
 // Output Schemas (Runtime validation for adapter responses)
 // ============================================================================
@@ -357,3 +788,244 @@ export const ExploreOutputSchema = z.object({
 });
 
 export type ExploreOutput = z.infer<typeof ExploreOutputSchema>;
+
+// ============================================================================
+// Structured Output (format: "json")
+// ============================================================================
+
+/**
+ * Version of the structured output schemas, sent as `schemaVersion` in every
+ * JSON result. Bumped when a field is removed, renamed, or changes type;
+ * adding an optional field keeps the version.
+ */
+export const OUTPUT_SCHEMA_VERSION = 1;
+
+const SchemaVersionSchema = z.literal(OUTPUT_SCHEMA_VERSION);
+
+/**
+ * JSON Schema for a structured output, for a tool's `outputSchema`
+ */
+export function toOutputJsonSchema(schema: z.ZodType): JSONSchema {
+  // zod's JSON Schema type is structurally wider than the protocol's
+  return z.toJSONSchema(schema, { io: 'output' }) as JSONSchema;
+}
+
+export const SearchStructuredOutputSchema = z.object({
+  schemaVersion: SchemaVersionSchema,
+  query: z.string(),
+  results: z.array(
+    z.object({
+      id: z.string(),
+      score: z.number(),
+      name: z.string().optional(),
+      type: z.string().optional(),
+      path: z.string().optional(),
+      startLine: z.number().optional(),
+      endLine: z.number().optional(),
+      signature: z.string().optional(),
+      exported: z.boolean().optional(),
+      snippet: z.string().optional(),
+      goVersion: z.string().optional(), // Go: language version from the owning go.mod
+      expandedTerms: z.array(z.string()).optional(), // Synonyms that ranked this result higher
+      lastModified: z.string().optional(), // With blame enabled: last commit date (ISO)
+      lastAuthor: z.string().optional(),
+      docBoost: z.number().optional(), // Doc quality boost used for ordering; not in score
+      keywordBoost: z.number().optional(), // Keyword boost used for ordering; not in score
+      debug: z
+        .object({
+          vectorScore: z.number(),
+          keywordScore: z.number().nullable(), // Null unless keyword scoring ran
+          docBoost: z.number(),
+          keywordBoost: z.number().optional(),
+          // Effective query token weights: IDF times any boost, 0 for stopwords
+          keywordWeights: z.record(z.string(), z.number()).optional(),
+          finalScore: z.number(), // vectorScore + docBoost + keywordBoost; the ordering value
+          vectorRank: z.number(),
+          rank: z.number(),
+          matchedQuery: z.string(),
+          embeddedQuery: z.string(),
+          sort: z.enum(['relevance', 'recency']),
+          // Session feedback the query vector was adjusted with (dev_feedback)
+          feedback: z.object({ relevant: z.number(), nonRelevant: z.number() }).optional(),
+          // Reranker that reordered the top candidates, with positions before and after
+          rerank: z
+            .object({
+              model: z.string(),
+              score: z.number().optional(),
+              preRank: z.number(),
+              postRank: z.number(),
+            })
+            .optional(),
+        })
+        .optional(), // Score breakdown, when debug is set
+    })
+  ),
+  expansion: z
+    .array(z.object({ term: z.string(), synonyms: z.array(z.string()) }))
+    .optional(), // Synonyms searched, when expand is set
+  examples: z
+    .object({
+      queries: z.array(z.string()),
+      symbols: z.array(z.string()), // Resolved example symbols, as names
+      unresolved: z.array(z.string()), // Symbol examples not found in the index
+      pooling: z.enum(['mean', 'max', 'separate']),
+    })
+    .optional(), // Multi-example searches (queries or symbols)
+  total: z.number(), // Ranked matches; a lower bound when totalIsEstimate
+  totalIsEstimate: z.boolean(),
+  minScore: z.number(), // Final-score cutoff applied
+  noStrongMatches: z.boolean(), // True when nothing scored at or above minScore
+  nextCursor: z.string().optional(),
+  relatedFiles: z.array(
+    z.object({
+      sourcePath: z.string(),
+      relatedPath: z.string(),
+      type: z.string(),
+    })
+  ),
+});
+
+export type SearchStructuredOutput = z.infer<typeof SearchStructuredOutputSchema>;
+
+const RefsResultSchema = z.object({
+  target: z.object({
+    name: z.string(),
+    file: z.string(),
+    line: z.number(),
+    type: z.string(),
+  }),
+  callees: z
+    .array(
+      z.object({
+        name: z.string(),
+        file: z.string().optional(), // Set when the call was resolved
+        line: z.number(),
+      })
+    )
+    .optional(),
+  callers: z
+    .array(
+      z.object({
+        name: z.string(),
+        file: z.string().optional(),
+        line: z.number(),
+        type: z.string().optional(),
+        signature: z.string().optional(),
+      })
+    )
+    .optional(),
+  referencesTypes: z.array(z.string()).optional(), // Go: types a function uses, as written
+  referencedBy: z
+    .array(
+      z.object({
+        name: z.string(),
+        file: z.string().optional(),
+        line: z.number(),
+        type: z.string().optional(),
+        signature: z.string().optional(),
+      })
+    )
+    .optional(), // Functions using a type
+});
+
+export const RefsStructuredOutputSchema = RefsResultSchema.partial({ target: true }).extend({
+  schemaVersion: SchemaVersionSchema,
+  // Batched queries (name is an array): per-symbol results or errors, keyed by name.
+  // Single queries set target, callees, and callers at the top level instead.
+  results: z
+    .record(
+      z.string(),
+      z.union([
+        RefsResultSchema,
+        z.object({ error: z.object({ code: z.string(), message: z.string() }) }),
+      ])
+    )
+    .optional(),
+});
+
+export type RefsStructuredOutput = z.infer<typeof RefsStructuredOutputSchema>;
+
+export const LookupStructuredOutputSchema = z.object({
+  schemaVersion: SchemaVersionSchema,
+  query: z.string(),
+  matches: z.array(
+    z.object({
+      name: z.string(),
+      type: z.string(),
+      path: z.string(),
+      startLine: z.number(),
+      signature: z.string().optional(),
+      score: z.number(),
+    })
+  ),
+});
+
+export type LookupStructuredOutput = z.infer<typeof LookupStructuredOutputSchema>;
+
+export const PlanStructuredOutputSchema = z.object({
+  schemaVersion: SchemaVersionSchema,
+  issue: z.object({
+    number: z.number(),
+    title: z.string(),
+    body: z.string(),
+    labels: z.array(z.string()),
+    author: z.string(),
+    createdAt: z.string(),
+    updatedAt: z.string(),
+    state: z.enum(['open', 'closed']),
+    comments: z.array(
+      z.object({
+        author: z.string(),
+        body: z.string(),
+        createdAt: z.string(),
+      })
+    ),
+  }),
+  relevantCode: z.array(
+    z.object({
+      file: z.string(),
+      name: z.string(),
+      type: z.string(),
+      snippet: z.string(),
+      relevanceScore: z.number(),
+      reason: z.string(),
+    })
+  ),
+  codebasePatterns: z.object({
+    testPattern: z.string().optional(),
+    testLocation: z.string().optional(),
+    importPatterns: z.array(z.string()).optional(),
+    namingConventions: z.string().optional(),
+  }),
+  relatedHistory: z.array(
+    z.object({
+      type: z.enum(['issue', 'pr']),
+      number: z.number(),
+      title: z.string(),
+      state: z.enum(['open', 'closed', 'merged']),
+      relevanceScore: z.number(),
+      summary: z.string().optional(),
+    })
+  ),
+  relatedCommits: z.array(
+    z.object({
+      hash: z.string(),
+      subject: z.string(),
+      author: z.string(),
+      date: z.string(),
+      filesChanged: z.array(z.string()),
+      issueRefs: z.array(z.number()),
+      relevanceScore: z.number(),
+    })
+  ),
+  metadata: z.object({
+    generatedAt: z.string(),
+    tokensUsed: z.number(),
+    codeSearchUsed: z.boolean(),
+    historySearchUsed: z.boolean(),
+    gitHistorySearchUsed: z.boolean(),
+    repositoryPath: z.string(),
+  }),
+});
+
+export type PlanStructuredOutput = z.infer<typeof PlanStructuredOutputSchema>;
@@ -0,0 +1,120 @@
+import type { IndexFreshness } from '@lytics/dev-agent-core';
+import { describe, expect, it, vi } from 'vitest';
+import { formatFreshness, IndexFreshnessMonitor, isAutoReindexEnabled } from '../index-freshness';
+
+function freshness(overrides: Partial<IndexFreshness> = {}): IndexFreshness {
+  return {
+    trackedFiles: 20,
+    changed: [],
+    added: [],
+    deleted: [],
+    staleFiles: 0,
+    stalePercent: 0,
+    checkedAt: '2025-11-24T09:00:00Z',
+    ...overrides,
+  };
+}
+
+const stale = freshness({
+  changed: ['src/a.ts', 'src/b.ts'],
+  deleted: ['src/old.ts'],
+  staleFiles: 3,
+  stalePercent: 15,
+});
+
+describe('IndexFreshnessMonitor', () => {
+  it('should report staleness without re-indexing by default', async () => {
+    const indexer = {
+      checkFreshness: vi.fn().mockResolvedValue(stale),
+      update: vi.fn(),
+    };
+    const logger = { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() };
+    const monitor = new IndexFreshnessMonitor({ indexer, logger });
+
+    expect(monitor.latest).toBeUndefined();
+    const report = await monitor.check();
+
+    expect(report).toEqual({ freshness: stale, reindexedFiles: undefined });
+    expect(monitor.latest).toBe(report);
+    expect(indexer.update).not.toHaveBeenCalled();
+    expect(logger.warn).toHaveBeenCalledWith(
+      'Index is 15% stale (2 changed, 0 added, 1 deleted of 20 files)',
+      { changed: 2, added: 0, deleted: 1 }
+    );
+  });
+
+  it('should re-index stale files when auto-reindex is enabled', async () => {
+    const indexer = {
+      checkFreshness: vi.fn().mockResolvedValueOnce(stale).mockResolvedValueOnce(freshness()),
+      update: vi.fn().mockResolvedValue(undefined),
+    };
+    const monitor = new IndexFreshnessMonitor({ indexer, autoReindex: true });
+
+    const report = await monitor.check();
+
+    expect(indexer.update).toHaveBeenCalledTimes(1);
+    expect(report.reindexedFiles).toBe(3);
+    expect(report.freshness?.staleFiles).toBe(0);
+  });
+
+  it('should not re-index an up-to-date or unindexed repository', async () => {
+    const indexer = {
+      checkFreshness: vi.fn().mockResolvedValueOnce(freshness()).mockResolvedValueOnce(null),
+      update: vi.fn(),
+    };
+    const monitor = new IndexFreshnessMonitor({ indexer, autoReindex: true });
+
+    await monitor.check();
+    expect((await monitor.check()).freshness).toBeNull();
+    expect(indexer.update).not.toHaveBeenCalled();
+  });
+
+  it('should expose the check in flight until it settles', async () => {
+    const indexer = {
+      checkFreshness: vi.fn().mockRejectedValue(new Error('index locked')),
+      update: vi.fn(),
+    };
+    const monitor = new IndexFreshnessMonitor({ indexer });
+    expect(monitor.pending).toBeUndefined();
+
+    const check = monitor.check();
+    const pending = monitor.pending;
+
+    await expect(check).rejects.toThrow('index locked');
+    await expect(pending).resolves.toBeUndefined();
+    expect(monitor.pending).toBeUndefined();
+  });
+
+  it('should share one check between concurrent callers', async () => {
+    const indexer = { checkFreshness: vi.fn().mockResolvedValue(freshness()), update: vi.fn() };
+    const monitor = new IndexFreshnessMonitor({ indexer });
+
+    const [first, second] = await Promise.all([monitor.check(), monitor.check()]);
+
+    expect(first).toBe(second);
+    expect(indexer.checkFreshness).toHaveBeenCalledTimes(1);
+  });
+});
+
+describe('formatFreshness', () => {
+  it('should describe an up-to-date index', () => {
+    expect(formatFreshness(freshness())).toBe('Index is up to date (20 files checked)');
+  });
+
+  it('should count added files in the total', () => {
+    const text = formatFreshness(
+      freshness({ added: ['a.ts', 'b.ts'], staleFiles: 2, stalePercent: 9.1 })
+    );
+    expect(text).toBe('Index is 9.1% stale (0 changed, 2 added, 0 deleted of 22 files)');
+  });
+});
+
+describe('isAutoReindexEnabled', () => {
+  it('should accept common truthy values only', () => {
+    expect(isAutoReindexEnabled('1')).toBe(true);
+    expect(isAutoReindexEnabled(' TRUE ')).toBe(true);
+    expect(isAutoReindexEnabled('yes')).toBe(true);
+    expect(isAutoReindexEnabled('0')).toBe(false);
+    expect(isAutoReindexEnabled(undefined)).toBe(false);
+  });
+});
@@ -0,0 +1,251 @@
+import type { IndexProgress, IndexStats } from '@lytics/dev-agent-core';
+import { describe, expect, it, vi } from 'vitest';
+import {
+  formatIndexProgress,
+  formatReindexJob,
+  type ReindexJob,
+  ReindexJobs,
+} from '../reindex-jobs';
+
+function stats(overrides: Partial<IndexStats> = {}): IndexStats {
+  return {
+    filesScanned: 12,
+    documentsIndexed: 80,
+    vectorsStored: 80,
+    duration: 3200,
+    errors: [],
+    startTime: new Date('2025-11-24T09:00:00Z'),
+    endTime: new Date('2025-11-24T09:00:03Z'),
+    repositoryPath: '/test/repo',
+    ...overrides,
+  };
+}
+
+/**
+ * An indexer whose runs finish when the test says so
+ */
+function deferredIndexer() {
+  const pending: Array<{ resolve: (stats: IndexStats) => void; reject: (e: Error) => void }> = [];
+  const run = () =>
+    new Promise<IndexStats>((resolve, reject) => {
+      pending.push({ resolve, reject });
+    });
+  return {
+    index: vi.fn(run),
+    update: vi.fn(run),
+    pending,
+  };
+}
+
+const settle = () => new Promise((resolve) => setTimeout(resolve, 0));
+
+describe('ReindexJobs', () => {
+  it('should start a job at once and complete it in the background', async () => {
+    const indexer = deferredIndexer();
+    const freshness = {
+      check: vi.fn().mockResolvedValue({ freshness: null }),
+      pending: undefined,
+    };
+    const jobs = new ReindexJobs({ indexer, freshness });
+
+    const { job, joined } = jobs.request('incremental');
+
+    expect(joined).toBe(false);
+    expect(job).toMatchObject({ id: 'reindex-1', mode: 'incremental', status: 'running' });
+    expect(indexer.update).toHaveBeenCalledTimes(1);
+    expect(jobs.current).toBe(job);
+
+    indexer.pending[0].resolve(stats());
+    await settle();
+
+    expect(job.status).toBe('completed');
+    expect(job.stats).toEqual({
+      filesScanned: 12,
+      documentsIndexed: 80,
+      duration: 3200,
+      errors: 0,
+    });
+    expect(freshness.check).toHaveBeenCalledTimes(1);
+    expect(jobs.current).toBeUndefined();
+    expect(jobs.get('reindex-1')).toBe(job);
+  });
+
+  it('should wait for a freshness check that is re-indexing', async () => {
+    const indexer = deferredIndexer();
+    let finishCheck: () => void = () => {};
+    const freshness = {
+      check: vi.fn().mockResolvedValue({ freshness: null }),
+      pending: new Promise<void>((resolve) => {
+        finishCheck = resolve;
+      }),
+    };
+    const jobs = new ReindexJobs({ indexer, freshness });
+
+    const { job } = jobs.request('incremental');
+    await settle();
+    expect(indexer.update).not.toHaveBeenCalled();
+    expect(jobs.request('incremental').joined).toBe(true);
+
+    finishCheck();
+    await settle();
+    expect(job.status).toBe('running');
+    expect(indexer.update).toHaveBeenCalledTimes(1);
+  });
+
+  it('should join requests a running job already covers', () => {
+    const indexer = deferredIndexer();
+    const jobs = new ReindexJobs({ indexer });
+
+    const first = jobs.request('full');
+    const second = jobs.request('incremental');
+
+    expect(second).toEqual({ job: first.job, joined: true });
+    expect(first.job.requests).toBe(2);
+    expect(indexer.index).toHaveBeenCalledTimes(1);
+    expect(indexer.update).not.toHaveBeenCalled();
+  });
+
+  it('should queue a full reindex behind an incremental one and share it', async () => {
+    const indexer = deferredIndexer();
+    const jobs = new ReindexJobs({ indexer });
+
+    const incremental = jobs.request('incremental').job;
+    const queued = jobs.request('full');
+    const later = jobs.request('incremental');
+
+    expect(queued.joined).toBe(false);
+    expect(queued.job).toMatchObject({ id: 'reindex-2', mode: 'full', status: 'queued' });
+    expect(later).toEqual({ job: queued.job, joined: true });
+    expect(jobs.latest).toBe(queued.job);
+
+    indexer.pending[0].resolve(stats());
+    await settle();
+
+    expect(incremental.status).toBe('completed');
+    expect(queued.job.status).toBe('running');
+    expect(indexer.index).toHaveBeenCalledWith(expect.objectContaining({ force: true }));
+  });
+
+  it('should record failures and keep serving requests', async () => {
+    const indexer = deferredIndexer();
+    const logger = { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() };
+    const jobs = new ReindexJobs({ indexer, logger });
+
+    const failed = jobs.request('incremental').job;
+    indexer.pending[0].reject(new Error('disk full'));
+    await settle();
+
+    expect(failed).toMatchObject({ status: 'failed', error: 'disk full' });
+    expect(logger.error).toHaveBeenCalledWith('Reindex failed', {
+      job: 'reindex-1',
+      error: 'disk full',
+    });
+
+    const retry = jobs.request('incremental');
+    expect(retry.joined).toBe(false);
+    expect(retry.job.status).toBe('running');
+  });
+
+  it('should track progress reported by the indexer', () => {
+    const indexer = deferredIndexer();
+    const jobs = new ReindexJobs({ indexer });
+
+    const { job } = jobs.request('incremental');
+    const { onProgress } = indexer.update.mock.calls[0][0] as {
+      onProgress: (progress: IndexProgress) => void;
+    };
+    onProgress({
+      phase: 'embedding',
+      filesProcessed: 120,
+      totalFiles: 300,
+      documentsIndexed: 800,
+      percentComplete: 45.4,
+    });
+
+    expect(formatReindexJob(job)).toBe('running (embedding, 45%: 120/300 files)');
+  });
+
+  it('should stream progress to waiters and resolve when the job finishes', async () => {
+    const indexer = deferredIndexer();
+    const jobs = new ReindexJobs({ indexer });
+
+    const { job } = jobs.request('full');
+    const listener = vi.fn();
+    const waiting = jobs.wait(job.id, listener);
+    const { onProgress } = indexer.index.mock.calls[0][0] as {
+      onProgress: (progress: IndexProgress) => void;
+    };
+    const progress: IndexProgress = {
+      phase: 'scanning',
+      filesProcessed: 10,
+      totalFiles: 40,
+      documentsIndexed: 30,
+      percentComplete: 8,
+      etaSeconds: 12,
+    };
+    onProgress(progress);
+    indexer.pending[0].resolve(stats());
+
+    expect(listener).toHaveBeenCalledWith(progress, job);
+    expect(await waiting).toBe(job);
+    expect(job.status).toBe('completed');
+    expect(await jobs.wait(job.id)).toBe(job);
+    expect(await jobs.wait('reindex-9')).toBeUndefined();
+  });
+
+  it('should forget the oldest finished jobs', async () => {
+    const indexer = {
+      index: vi.fn(),
+      update: vi.fn().mockResolvedValue(stats()),
+    };
+    const jobs = new ReindexJobs({ indexer });
+
+    for (let i = 0; i < 12; i++) {
+      jobs.request('incremental');
+      await settle();
+    }
+
+    expect(jobs.get('reindex-1')).toBeUndefined();
+    expect(jobs.get('reindex-2')).toBeDefined();
+    expect(jobs.latest?.id).toBe('reindex-12');
+  });
+});
+
+describe('formatReindexJob', () => {
+  const job: ReindexJob = {
+    id: 'reindex-1',
+    mode: 'incremental',
+    status: 'queued',
+    requests: 1,
+    queuedAt: new Date('2025-11-24T09:00:00Z'),
+  };
+
+  it('should describe each status', () => {
+    expect(formatReindexJob(job)).toBe('queued behind the running job');
+    expect(formatReindexJob({ ...job, status: 'running' })).toBe('running (starting)');
+    expect(
+      formatReindexJob({
+        ...job,
+        status: 'completed',
+        stats: { filesScanned: 12, documentsIndexed: 80, duration: 3200, errors: 2 },
+      })
+    ).toBe('completed in 3.2s: 12 files scanned, 80 documents indexed, 2 error(s)');
+    expect(formatReindexJob({ ...job, status: 'failed', error: 'disk full' })).toBe(
+      'failed: disk full'
+    );
+  });
+
+  it('should show the time left when the indexer estimates it', () => {
+    const progress: IndexProgress = {
+      phase: 'storing',
+      filesProcessed: 300,
+      totalFiles: 300,
+      documentsIndexed: 400,
+      percentComplete: 80,
+      etaSeconds: 95,
+    };
+
+    expect(formatIndexProgress(progress)).toBe('storing, 80%: 300/300 files, ~2m left');
+    expect(formatIndexProgress({ ...progress, etaSeconds: 20 })).toContain('~20s left');
+  });
+});
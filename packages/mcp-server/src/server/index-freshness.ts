@@ -0,0 +1,144 @@
+/**
+ * Index Freshness
+ * Checks a loaded index against the repository on disk, so clients can tell
+ * how far to trust results, and optionally brings it up to date
+ */
+
+import type { IndexFreshness, RepositoryIndexer } from '@lytics/dev-agent-core';
+import type { Logger } from '../adapters/types';
+
+/**
+ * Environment variable that opts in to re-indexing a stale index on startup
+ */
+export const AUTO_REINDEX_ENV = 'DEV_AGENT_AUTO_REINDEX';
+
+/**
+ * Index freshness monitor configuration
+ */
+export interface IndexFreshnessMonitorConfig {
+  /**
+   * Indexer whose stored hashes are checked (and updated, with autoReindex)
+   */
+  indexer: Pick<RepositoryIndexer, 'checkFreshness' | 'update'>;
+
+  /**
+   * Run an incremental update when the check finds stale files (default: false)
+   */
+  autoReindex?: boolean;
+
+  /**
+   * Optional logger
+   */
+  logger?: Logger;
+}
+
+/**
+ * Result of the last freshness check
+ */
+export interface FreshnessReport {
+  /** Freshness after any re-index; null when the repository isn't indexed */
+  freshness: IndexFreshness | null;
+  /** Stale files an automatic re-index brought up to date */
+  reindexedFiles?: number;
+}
+
+/**
+ * Index Freshness Monitor
+ * Runs the check once on load and keeps the result for status tools
+ */
+export class IndexFreshnessMonitor {
+  private indexer: IndexFreshnessMonitorConfig['indexer'];
+  private autoReindex: boolean;
+  private logger?: Logger;
+  private report?: FreshnessReport;
+  private running?: Promise<FreshnessReport>;
+
+  constructor(config: IndexFreshnessMonitorConfig) {
+    this.indexer = config.indexer;
+    this.autoReindex = config.autoReindex ?? false;
+    this.logger = config.logger;
+  }
+
+  /**
+   * Latest report, or undefined while the first check is still running
+   */
+  get latest(): FreshnessReport | undefined {
+    return this.report;
+  }
+
+  /**
+   * Whether stale indexes are re-indexed automatically
+   */
+  get autoReindexEnabled(): boolean {
+    return this.autoReindex;
+  }
+
+  /**
+   * Compare the index with the repository, re-indexing first if enabled and stale
+   *
+   * Concurrent calls share one check.
+   */
+  async check(): Promise<FreshnessReport> {
+    if (!this.running) {
+      this.running = this.runCheck().finally(() => {
+        this.running = undefined;
+      });
+    }
+    return this.running;
+  }
+
+  /**
+   * The check in flight, including its re-index, settled without its result;
+   * undefined when no check is running
+   */
+  get pending(): Promise<void> | undefined {
+    return this.running?.then(
+      () => undefined,
+      () => undefined
+    );
+  }
+
+  private async runCheck(): Promise<FreshnessReport> {
+    let freshness = await this.indexer.checkFreshness();
+    if (freshness && freshness.staleFiles > 0) {
+      this.logger?.warn(formatFreshness(freshness), {
+        changed: freshness.changed.length,
+        added: freshness.added.length,
+        deleted: freshness.deleted.length,
+      });
+    }
+
+    let reindexedFiles: number | undefined;
+    if (this.autoReindex && freshness && freshness.staleFiles > 0) {
+      this.logger?.info('Re-indexing stale files', { files: freshness.staleFiles });
+      reindexedFiles = freshness.staleFiles;
+      await this.indexer.update();
+      freshness = await this.indexer.checkFreshness();
+    }
+
+    this.report = { freshness, reindexedFiles };
+    return this.report;
+  }
+}
+
+/**
+ * One-line summary: "Index is 12.5% stale (3 changed, 1 added, 0 deleted of 32 files)"
+ */
+export function formatFreshness(freshness: IndexFreshness): string {
+  if (freshness.staleFiles === 0) {
+    return `Index is up to date (${freshness.trackedFiles} files checked)`;
+  }
+  const { changed, added, deleted } = freshness;
+  return (
+    `Index is ${freshness.stalePercent}% stale (${changed.length} changed, ` +
+    `${added.length} added, ${deleted.length} deleted of ` +
+    `${freshness.trackedFiles + added.length} files)`
+  );
+}
+
+/**
+ * Whether an environment value turns auto-reindex on ("1", "true", "yes", "on")
+ */
+export function isAutoReindexEnabled(value: string | undefined): boolean {
+  return ['1', 'true', 'yes', 'on'].includes((value ?? '').trim().toLowerCase());
+}
@@ -8,8 +8,19 @@ import { AdapterRegistry, type RegistryConfig } from '../adapters/adapter-regist
 import type { ToolAdapter } from '../adapters/tool-adapter';
 import type { AdapterContext, Config, ToolExecutionContext } from '../adapters/types';
 import { ConsoleLogger } from '../utils/logger';
+import {
+  fitOutputToBudget,
+  fitStructuredToBudget,
+  type OutputTokenCounter,
+} from '../utils/output-budget';
 import { PromptRegistry } from './prompts';
-import { createError, createErrorResponse, createResponse, isRequest } from './protocol/jsonrpc';
+import {
+  createError,
+  createErrorResponse,
+  createNotification,
+  createResponse,
+  isRequest,
+} from './protocol/jsonrpc';
 import type {
   ErrorCode,
   InitializeResult,
@@ -29,6 +40,10 @@ export interface MCPServerConfig {
   adapters?: ToolAdapter[];
   /** Optional coordinator for routing through subagents */
   coordinator?: SubagentCoordinator;
+  /** Most tokens any tool response may use; text output over it is trimmed (default: no limit) */
+  maxTokens?: number;
+  /** Tokenizer-backed counter for the budget (default: calibrated estimate) */
+  countTokens?: OutputTokenCounter;
 }
 
 export class MCPServer {
@@ -40,9 +55,13 @@ export class MCPServer {
   private serverInfo: ServerInfo;
   private clientProtocolVersion?: string;
   private coordinator?: SubagentCoordinator;
+  private maxTokens?: number;
+  private countTokens?: OutputTokenCounter;
 
   constructor(config: MCPServerConfig) {
     this.config = config.config;
+    this.maxTokens = config.maxTokens;
+    this.countTokens = config.countTokens;
     this.serverInfo = config.serverInfo;
     this.registry = new AdapterRegistry(config.registry || {});
     this.promptRegistry = new PromptRegistry();
@@ -190,7 +209,11 @@ export class MCPServer {
 
       case 'tools/call':
         return this.handleToolsCall(
-          request.params as { name: string; arguments: Record<string, unknown> }
+          request.params as {
+            name: string;
+            arguments: Record<string, unknown>;
+            _meta?: { progressToken?: string | number; maxTokens?: number };
+          }
         );
 
       case 'prompts/list':
@@ -258,6 +281,7 @@ export class MCPServer {
       INTERNAL_ERROR: -32603,
       GITHUB_CLI_ERROR: -32003,
       INDEXER_ERROR: -32004,
+      EMBEDDINGS_UNAVAILABLE: -32005,
     };
     return code ? codeMap[code] || -32001 : -32001;
   }
@@ -268,12 +292,26 @@ export class MCPServer {
   private async handleToolsCall(params: {
     name: string;
     arguments: Record<string, unknown>;
+    _meta?: { progressToken?: string | number; maxTokens?: number };
   }): Promise<unknown> {
     const { name, arguments: args } = params;
+    const progressToken = params._meta?.progressToken;
+    // A client can raise or lower the budget for one call to get more of a trimmed result
+    const requested = params._meta?.maxTokens;
+    const maxTokens =
+      typeof requested === 'number' && requested > 0 ? Math.floor(requested) : this.maxTokens;
 
     const context: ToolExecutionContext = {
       logger: this.logger,
       config: this.config,
+      // Clients opt in to progress notifications by sending a progress token
+      sendProgress:
+        progressToken === undefined
+          ? undefined
+          : (progress) =>
+              this.transport.send(
+                createNotification('notifications/progress', { progressToken, ...progress })
+              ),
     };
 
     const result = await this.registry.executeTool(name, args, context);
@@ -290,15 +328,32 @@ export class MCPServer {
     }
 
     // Format response according to MCP protocol
-    // Always return content blocks (even for tools with outputSchema)
+    // Always return content blocks (even for tools with outputSchema); structured
+    // results (format: "json") are also sent as structuredContent
+    const structured = typeof result.data === 'object' && result.data !== null;
+    let data = result.data;
+    let text = typeof data === 'string' ? data : JSON.stringify(data, null, 2);
+
+    // Markdown is trimmed block by block; structured output drops whole array
+    // items, since JSON cut mid-way would no longer parse
+    let tokensOmitted: number | undefined;
+    if (maxTokens !== undefined) {
+      const fitted =
+        typeof data === 'object' && data !== null
+          ? fitStructuredToBudget(data, maxTokens, this.countTokens)
+          : fitOutputToBudget(text, maxTokens, this.countTokens);
+      if (fitted.truncated) {
+        if ('data' in fitted) data = fitted.data;
+        text = fitted.text;
+        tokensOmitted = fitted.omittedTokens;
+        this.logger.info('Tool output trimmed to budget', { name, maxTokens, tokensOmitted });
+      }
+    }
+
     return {
-      content: [
-        {
-          type: 'text',
-          text:
-            typeof result.data === 'string' ? result.data : JSON.stringify(result.data, null, 2),
-        },
-      ],
+      content: [{ type: 'text', text }],
+      ...(structured ? { structuredContent: data } : {}),
+      ...(tokensOmitted !== undefined ? { _meta: { maxTokens, tokensOmitted } } : {}),
     };
   }
 
@@ -55,6 +55,7 @@ export interface ToolCall {
 
 export interface MCPToolResult {
   content?: unknown;
+  structuredContent?: unknown; // Matches the tool's outputSchema
   isError?: boolean;
 }
 
@@ -0,0 +1,291 @@
+/**
+ * Reindex Jobs
+ * Runs reindexing requested by clients in the background, one job at a time
+ *
+ * A request returns a job at once; the client polls it by id. Overlapping
+ * requests share work: a request a running or queued job already covers
+ * joins that job, and a full reindex requested during an incremental one is
+ * queued to run next (later requests join the queued job). Finished jobs are
+ * kept for a while so a late poll still finds the outcome. A caller that
+ * would rather block can wait on a job, receiving its progress as it runs.
+ */
+
+import type { IndexProgress, IndexStats, RepositoryIndexer } from '@lytics/dev-agent-core';
+import type { Logger } from '../adapters/types';
+import type { IndexFreshnessMonitor } from './index-freshness';
+
+/**
+ * Finished jobs kept for polling
+ */
+const MAX_FINISHED_JOBS = 10;
+
+/**
+ * Incremental updates only changed, added, and deleted files; full re-scans
+ * and re-embeds everything
+ */
+export type ReindexMode = 'incremental' | 'full';
+
+export type ReindexJobStatus = 'queued' | 'running' | 'completed' | 'failed';
+
+/**
+ * A reindex run and its progress
+ */
+export interface ReindexJob {
+  /** Id to poll with (`reindex-3`) */
+  id: string;
+  mode: ReindexMode;
+  status: ReindexJobStatus;
+  /** Requests this job answers, the one that created it included */
+  requests: number;
+  queuedAt: Date;
+  startedAt?: Date;
+  finishedAt?: Date;
+  /** Latest progress reported by the indexer */
+  progress?: IndexProgress;
+  /** Outcome of a completed job */
+  stats?: Pick<IndexStats, 'filesScanned' | 'documentsIndexed' | 'duration'> & { errors: number };
+  /** Why a failed job failed */
+  error?: string;
+}
+
+/**
+ * A job answering a reindex request
+ */
+export interface ReindexRequest {
+  job: ReindexJob;
+  /** Whether the request joined a job started by an earlier one */
+  joined: boolean;
+}
+
+/**
+ * Receives a job's progress while waiting on it
+ */
+export type ReindexProgressListener = (progress: IndexProgress, job: ReindexJob) => void;
+
+interface ReindexWaiter {
+  onProgress?: ReindexProgressListener;
+  resolve: (job: ReindexJob) => void;
+}
+
+/**
+ * Reindex job runner configuration
+ */
+export interface ReindexJobsConfig {
+  /**
+   * Indexer that runs the jobs
+   */
+  indexer: Pick<RepositoryIndexer, 'index' | 'update'>;
+
+  /**
+   * Optional freshness monitor, re-checked after each job so status stays current.
+   * Jobs wait for a check in flight, whose auto-reindex also updates the index.
+   */
+  freshness?: Pick<IndexFreshnessMonitor, 'check' | 'pending'>;
+
+  /**
+   * Optional logger
+   */
+  logger?: Logger;
+}
+
+/**
+ * Reindex Jobs
+ * Background reindexing with deduplicated requests
+ */
+export class ReindexJobs {
+  private indexer: ReindexJobsConfig['indexer'];
+  private freshness?: ReindexJobsConfig['freshness'];
+  private logger?: Logger;
+  private jobs = new Map<string, ReindexJob>();
+  private running?: ReindexJob;
+  private queued?: ReindexJob;
+  private waiters = new Map<string, ReindexWaiter[]>();
+  private nextId = 1;
+
+  constructor(config: ReindexJobsConfig) {
+    this.indexer = config.indexer;
+    this.freshness = config.freshness;
+    this.logger = config.logger;
+  }
+
+  /**
+   * The job running now, or queued next
+   */
+  get current(): ReindexJob | undefined {
+    return this.running ?? this.queued;
+  }
+
+  /**
+   * The most recently requested job
+   */
+  get latest(): ReindexJob | undefined {
+    return [...this.jobs.values()].at(-1);
+  }
+
+  /**
+   * A job by id, while it runs or is among the recently finished
+   */
+  get(id: string): ReindexJob | undefined {
+    return this.jobs.get(id);
+  }
+
+  /**
+   * Wait for a job to finish, receiving its progress until then
+   *
+   * @returns The finished job, or undefined if no job has that id
+   */
+  wait(id: string, onProgress?: ReindexProgressListener): Promise<ReindexJob | undefined> {
+    const job = this.jobs.get(id);
+    if (!job || job.finishedAt) {
+      return Promise.resolve(job);
+    }
+    return new Promise((resolve) => {
+      const waiters = this.waiters.get(id) ?? [];
+      waiters.push({ onProgress, resolve });
+      this.waiters.set(id, waiters);
+    });
+  }
+
+  /**
+   * Request a reindex; returns without waiting for it
+   */
+  request(mode: ReindexMode): ReindexRequest {
+    if (this.queued) {
+      // Only a full reindex is ever queued, so it covers any request
+      return this.join(this.queued);
+    }
+    if (this.running && (this.running.mode === 'full' || mode === 'incremental')) {
+      return this.join(this.running);
+    }
+
+    const job = this.create(mode);
+    if (this.running) {
+      this.queued = job;
+    } else {
+      void this.run(job);
+    }
+    return { job, joined: false };
+  }
+
+  private join(job: ReindexJob): ReindexRequest {
+    job.requests++;
+    return { job, joined: true };
+  }
+
+  private create(mode: ReindexMode): ReindexJob {
+    const job: ReindexJob = {
+      id: `reindex-${this.nextId++}`,
+      mode,
+      status: 'queued',
+      requests: 1,
+      queuedAt: new Date(),
+    };
+    this.jobs.set(job.id, job);
+    this.prune();
+    return job;
+  }
+
+  private async run(job: ReindexJob): Promise<void> {
+    this.running = job;
+    // Two updates at once would race on the vector store and the state file
+    const check = this.freshness?.pending;
+    if (check) {
+      await check;
+    }
+    job.status = 'running';
+    job.startedAt = new Date();
+    this.logger?.info('Reindex started', { job: job.id, mode: job.mode });
+
+    const onProgress = (progress: IndexProgress) => {
+      job.progress = progress;
+      for (const waiter of this.waiters.get(job.id) ?? []) {
+        waiter.onProgress?.(progress, job);
+      }
+    };
+    try {
+      const stats =
+        job.mode === 'full'
+          ? await this.indexer.index({ force: true, onProgress })
+          : await this.indexer.update({ onProgress });
+      job.stats = {
+        filesScanned: stats.filesScanned,
+        documentsIndexed: stats.documentsIndexed,
+        duration: stats.duration,
+        errors: stats.errors.length,
+      };
+      job.status = 'completed';
+      this.logger?.info('Reindex completed', { job: job.id, ...job.stats });
+    } catch (error) {
+      job.status = 'failed';
+      job.error = error instanceof Error ? error.message : String(error);
+      this.logger?.error('Reindex failed', { job: job.id, error: job.error });
+    }
+    job.finishedAt = new Date();
+
+    await this.freshness?.check().catch((error) => {
+      this.logger?.warn('Freshness check after reindex failed', { error });
+    });
+
+    for (const waiter of this.waiters.get(job.id) ?? []) {
+      waiter.resolve(job);
+    }
+    this.waiters.delete(job.id);
+
+    this.running = undefined;
+    const next = this.queued;
+    this.queued = undefined;
+    if (next) {
+      void this.run(next);
+    }
+  }
+
+  /**
+   * Forget the oldest finished jobs past MAX_FINISHED_JOBS
+   */
+  private prune(): void {
+    const finished = [...this.jobs.values()].filter((job) => job.finishedAt);
+    for (const job of finished.slice(0, Math.max(finished.length - MAX_FINISHED_JOBS, 0))) {
+      this.jobs.delete(job.id);
+    }
+  }
+}
+
+/**
+ * Progress summary: "embedding, 45%: 120/300 files, ~2m left"
+ */
+export function formatIndexProgress(progress: IndexProgress): string {
+  const files =
+    progress.totalFiles > 0 ? `: ${progress.filesProcessed}/${progress.totalFiles} files` : '';
+  const eta = progress.etaSeconds !== undefined ? `, ${formatEta(progress.etaSeconds)}` : '';
+  return `${progress.phase}, ${Math.round(progress.percentComplete)}%${files}${eta}`;
+}
+
+function formatEta(seconds: number): string {
+  return seconds < 60 ? `~${seconds}s left` : `~${Math.ceil(seconds / 60)}m left`;
+}
+
+/**
+ * One-line summary: "running (embedding, 45%: 120/300 files, ~2m left)" or
+ * "completed in 3.2s: 12 files scanned, 80 documents indexed"
+ */
+export function formatReindexJob(job: ReindexJob): string {
+  switch (job.status) {
+    case 'queued':
+      return 'queued behind the running job';
+    case 'running': {
+      if (!job.progress) return 'running (starting)';
+      return `running (${formatIndexProgress(job.progress)})`;
+    }
+    case 'completed': {
+      const stats = job.stats;
+      if (!stats) return 'completed';
+      const errors = stats.errors > 0 ? `, ${stats.errors} error(s)` : '';
+      return (
+        `completed in ${(stats.duration / 1000).toFixed(1)}s: ${stats.filesScanned} files ` +
+        `scanned, ${stats.documentsIndexed} documents indexed${errors}`
+      );
+    }
+    case 'failed':
+      return `failed: ${job.error ?? 'unknown error'}`;
+  }
+}
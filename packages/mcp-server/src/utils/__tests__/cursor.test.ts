@@ -0,0 +1,37 @@
+/**
+ * Tests for Pagination Cursors
+ */
+
+import { describe, expect, it } from 'vitest';
+import { decodeCursor, encodeCursor, fingerprintQuery } from '../cursor';
+
+describe('Pagination Cursors', () => {
+  it('should round-trip a cursor', () => {
+    const cursor = { indexVersion: '1700000000000:42', queryFingerprint: 'abc123', offset: 20 };
+
+    expect(decodeCursor(encodeCursor(cursor))).toEqual(cursor);
+  });
+
+  it('should reject malformed cursors', () => {
+    expect(decodeCursor('not-a-cursor')).toBeNull();
+    expect(decodeCursor(Buffer.from('{"offset":1}').toString('base64url'))).toBeNull();
+    expect(decodeCursor(Buffer.from('["v","f",-1]').toString('base64url'))).toBeNull();
+    expect(decodeCursor(Buffer.from('["v","f",1.5]').toString('base64url'))).toBeNull();
+  });
+
+  it('should fingerprint queries independent of key order', () => {
+    expect(fingerprintQuery({ query: 'auth', module: 'api' })).toBe(
+      fingerprintQuery({ module: 'api', query: 'auth' })
+    );
+  });
+
+  it('should treat undefined and null options alike', () => {
+    expect(fingerprintQuery({ query: 'auth', module: undefined })).toBe(
+      fingerprintQuery({ query: 'auth', module: null })
+    );
+  });
+
+  it('should fingerprint different queries differently', () => {
+    expect(fingerprintQuery({ query: 'auth' })).not.toBe(fingerprintQuery({ query: 'login' }));
+  });
+});
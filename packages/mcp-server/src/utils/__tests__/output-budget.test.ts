@@ -0,0 +1,139 @@
+import { describe, expect, it } from 'vitest';
+import {
+  fitOutputToBudget,
+  fitStructuredToBudget,
+  parseMaxOutputTokens,
+} from '../output-budget';
+
+const countWords = (text: string) => text.split(/\s+/).filter(Boolean).length;
+
+describe('fitOutputToBudget', () => {
+  const body = Array.from({ length: 30 }, (_, i) => `\tstep${i}()`).join('\n');
+  const output = [
+    '# Context for Checkout',
+    '',
+    '## Target',
+    '',
+    '```go',
+    `func Checkout() {\n${body}\n}`,
+    '```',
+    '',
+    '## Callers',
+    '',
+    '- **api.Handle** (api/handler.go:12)',
+    '  - calls Checkout on line 14',
+    '- **cli.Run** (cli/run.go:40)',
+    '  - calls Checkout on line 44',
+    '',
+    '## Distant callers',
+    '',
+    '- **main.main** (main.go:3) via cli.Run and several other hops in between',
+    '',
+  ].join('\n');
+
+  it('should leave output within the budget untouched', () => {
+    const fitted = fitOutputToBudget(output, 1000, countWords);
+
+    expect(fitted).toEqual({
+      text: output,
+      tokens: countWords(output),
+      omittedTokens: 0,
+      truncated: false,
+    });
+  });
+
+  it('should shorten long code bodies before dropping anything', () => {
+    const fitted = fitOutputToBudget(output, 75, countWords);
+
+    expect(fitted.truncated).toBe(true);
+    expect(fitted.text).toContain('\tstep4()\n... (26 more lines)\n```');
+    expect(fitted.text).not.toContain('step5()');
+    expect(fitted.text).toContain('main.main');
+    expect(fitted.tokens).toBeLessThanOrEqual(75);
+  });
+
+  it('should drop the last blocks and headings left empty', () => {
+    const fitted = fitOutputToBudget(output, 60, countWords);
+
+    expect(fitted.text).toContain('## Callers');
+    expect(fitted.text).toContain('- **api.Handle** (api/handler.go:12)\n  - calls Checkout');
+    expect(fitted.text).not.toContain('main.main');
+    expect(fitted.text).not.toContain('## Distant callers');
+    expect(fitted.tokens).toBeLessThanOrEqual(60);
+  });
+
+  it('should keep the first heading and block', () => {
+    const fitted = fitOutputToBudget(output, 45, countWords);
+
+    expect(fitted.text.startsWith('# Context for Checkout\n\n## Target\n\n```go')).toBe(true);
+    expect(fitted.text).not.toContain('## Callers');
+  });
+
+  it('should report what was omitted in a marker', () => {
+    const fitted = fitOutputToBudget(output, 60, countWords);
+    const kept = fitted.text.slice(0, fitted.text.lastIndexOf('---'));
+
+    expect(fitted.omittedTokens).toBe(countWords(output) - countWords(kept));
+    expect(fitted.text.trimEnd()).toMatch(
+      new RegExp(
+        `\\*Output trimmed to the 60-token budget: ~${fitted.omittedTokens} tokens omitted\\. ` +
+          'Narrow the request, or raise maxTokens, to see the rest\\.\\*$'
+      )
+    );
+  });
+
+  it('should cut a single oversized block at a line break', () => {
+    const text = Array.from({ length: 100 }, (_, i) => `line ${i}`).join('\n');
+    const fitted = fitOutputToBudget(text, 40, countWords);
+
+    expect(fitted.tokens).toBeLessThanOrEqual(40);
+    expect(fitted.text).toMatch(/^line 0\nline 1\n/);
+    expect(fitted.text).toMatch(/line \d+\n\n---\n/);
+  });
+});
+
+describe('fitStructuredToBudget', () => {
+  const data = {
+    query: 'auth',
+    results: Array.from({ length: 20 }, (_, i) => ({ id: `auth.go:Check${i}:1`, score: 0.9 })),
+    inputs: [{ id: 'auth.go:Login:1' }, { id: 'auth.go:Logout:1' }],
+  };
+
+  it('should leave output within the budget untouched', () => {
+    const fitted = fitStructuredToBudget(data, 10000, countWords);
+
+    expect(fitted.truncated).toBe(false);
+    expect(fitted.data).toBe(data);
+  });
+
+  it('should drop trailing items from the longest array until it fits', () => {
+    const fitted = fitStructuredToBudget(data, 60, countWords);
+
+    expect(fitted.truncated).toBe(true);
+    expect(countWords(fitted.text)).toBeLessThanOrEqual(60);
+    expect(JSON.parse(fitted.text)).toEqual(fitted.data);
+    expect(fitted.data.results[0]).toEqual(data.results[0]);
+    expect(fitted.data.results.length).toBeLessThan(data.results.length);
+    expect(fitted.data.inputs).toEqual(data.inputs);
+    expect(fitted.omittedTokens).toBeGreaterThan(0);
+    expect(data.results).toHaveLength(20);
+  });
+
+  it('should keep the first item of each array', () => {
+    const fitted = fitStructuredToBudget(data, 1, countWords);
+
+    expect(fitted.data.results).toEqual([data.results[0]]);
+    expect(fitted.data.inputs).toEqual([data.inputs[0]]);
+  });
+});
+
+describe('parseMaxOutputTokens', () => {
+  it('should parse a budget and reject values that are too small or invalid', () => {
+    expect(parseMaxOutputTokens('4000')).toBe(4000);
+    expect(parseMaxOutputTokens(' 800 ')).toBe(800);
+    expect(parseMaxOutputTokens('50')).toBeUndefined();
+    expect(parseMaxOutputTokens('lots')).toBeUndefined();
+    expect(parseMaxOutputTokens('')).toBeUndefined();
+    expect(parseMaxOutputTokens(undefined)).toBeUndefined();
+  });
+});
@@ -0,0 +1,79 @@
+/**
+ * Tests for Result Cache
+ */
+
+import { describe, expect, it } from 'vitest';
+import {
+  DEFAULT_RESULT_CACHE_SIZE,
+  formatResultCacheStats,
+  parseResultCacheSize,
+  ResultCache,
+  resultCacheKey,
+} from '../result-cache';
+
+describe('ResultCache', () => {
+  it('should return cached values and count hits and misses', () => {
+    const cache = new ResultCache<string>(10);
+
+    expect(cache.get('v1', 'a')).toBeUndefined();
+    cache.set('v1', 'a', 'result');
+
+    expect(cache.get('v1', 'a')).toBe('result');
+    expect(cache.stats()).toMatchObject({ hits: 1, misses: 1, hitRate: 0.5, size: 1 });
+  });
+
+  it('should evict the least recently used entry', () => {
+    const cache = new ResultCache<string>(2);
+    cache.set('v1', 'a', 'A');
+    cache.set('v1', 'b', 'B');
+    cache.get('v1', 'a');
+    cache.set('v1', 'c', 'C');
+
+    expect(cache.get('v1', 'a')).toBe('A');
+    expect(cache.get('v1', 'b')).toBeUndefined();
+    expect(cache.get('v1', 'c')).toBe('C');
+  });
+
+  it('should drop everything when the index version changes', () => {
+    const cache = new ResultCache<string>(10);
+    cache.set('v1', 'a', 'A');
+
+    expect(cache.get('v2', 'a')).toBeUndefined();
+    expect(cache.stats()).toMatchObject({ size: 0, invalidations: 1 });
+  });
+
+  it('should cache nothing with a capacity of 0', () => {
+    const cache = new ResultCache<string>(0);
+    cache.set('v1', 'a', 'A');
+
+    expect(cache.enabled).toBe(false);
+    expect(cache.get('v1', 'a')).toBeUndefined();
+    expect(formatResultCacheStats(cache.stats())).toBe('disabled');
+  });
+});
+
+describe('resultCacheKey', () => {
+  it('should ignore surrounding and repeated whitespace in strings', () => {
+    expect(resultCacheKey('dev_search', { query: '  auth   flow ' })).toBe(
+      resultCacheKey('dev_search', { query: 'auth flow' })
+    );
+  });
+
+  it('should separate tools and parameters', () => {
+    const key = resultCacheKey('dev_search', { query: 'auth', limit: 10 });
+
+    expect(resultCacheKey('dev_search', { query: 'auth', limit: 5 })).not.toBe(key);
+    expect(resultCacheKey('dev_similar', { query: 'auth', limit: 10 })).not.toBe(key);
+  });
+});
+
+describe('parseResultCacheSize', () => {
+  it('should accept non-negative integers and fall back otherwise', () => {
+    expect(parseResultCacheSize('50')).toBe(50);
+    expect(parseResultCacheSize('0')).toBe(0);
+    expect(parseResultCacheSize(undefined)).toBe(DEFAULT_RESULT_CACHE_SIZE);
+    expect(parseResultCacheSize('')).toBe(DEFAULT_RESULT_CACHE_SIZE);
+    expect(parseResultCacheSize('-1')).toBe(DEFAULT_RESULT_CACHE_SIZE);
+    expect(parseResultCacheSize('lots')).toBe(DEFAULT_RESULT_CACHE_SIZE);
+  });
+});
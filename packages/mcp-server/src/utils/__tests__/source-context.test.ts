@@ -0,0 +1,119 @@
+/**
+ * Tests for Source Context Utility
+ */
+
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+import type { SearchResult } from '@lytics/dev-agent-core';
+import { afterEach, beforeEach, describe, expect, it } from 'vitest';
+import { addSourceContext, maxSourceContextLines, SourceFileCache } from '../source-context';
+
+const SOURCE = [
+  'package auth', // 1
+  '', // 2
+  'import "errors"', // 3
+  '', // 4
+  '// Login checks credentials.', // 5
+  'func Login(user, pass string) error {', // 6
+  '\tif !verify(user, pass) {', // 7
+  '\t\treturn errors.New("denied")', // 8
+  '\t}', // 9
+  '\treturn nil', // 10
+  '}', // 11
+  '', // 12
+  'func verify(user, pass string) bool { return true }', // 13
+].join('\n');
+
+function result(overrides: Partial<SearchResult['metadata']> = {}): SearchResult {
+  return {
+    id: 'auth/login.go:Login:6',
+    score: 0.9,
+    metadata: {
+      path: 'auth/login.go',
+      name: 'Login',
+      type: 'function',
+      startLine: 6,
+      endLine: 11,
+      snippet: 'indexed snippet',
+      ...overrides,
+    },
+  };
+}
+
+describe('Source Context Utility', () => {
+  let tempDir: string;
+  let cache: SourceFileCache;
+
+  beforeEach(async () => {
+    tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'source-context-test-'));
+    await fs.mkdir(path.join(tempDir, 'auth'));
+    await fs.writeFile(path.join(tempDir, 'auth', 'login.go'), SOURCE);
+    cache = new SourceFileCache(tempDir);
+  });
+
+  afterEach(async () => {
+    await fs.rm(tempDir, { recursive: true, force: true });
+  });
+
+  it('should show context lines around the symbol and mark its range', async () => {
+    const [withContext] = await addSourceContext([result()], {
+      contextLines: 2,
+      query: 'login handler',
+      cache,
+    });
+
+    expect(withContext.metadata.snippet).toBe(
+      [
+        ' 4   ',
+        ' 5   // Login checks credentials.',
+        ' 6 > func Login(user, pass string) error {',
+        ' 7 > \tif !verify(user, pass) {',
+        ' 8 > \t\treturn errors.New("denied")',
+        ' 9 > \t}',
+        '10 > \treturn nil',
+        '11 > }',
+        '12   ',
+        '13   func verify(user, pass string) bool { return true }',
+      ].join('\n')
+    );
+  });
+
+  it('should show the call in context for call-site matches', async () => {
+    const [withContext] = await addSourceContext(
+      [result({ callees: [{ name: 'verify', line: 7 }, { name: 'errors.New', line: 8 }] })],
+      { contextLines: 1, query: 'where is verify called', cache }
+    );
+
+    expect(withContext.metadata.snippet).toBe(
+      [
+        '6   func Login(user, pass string) error {',
+        '7 > \tif !verify(user, pass) {',
+        '8   \t\treturn errors.New("denied")',
+      ].join('\n')
+    );
+  });
+
+  it('should read each file once per cache', async () => {
+    const first = cache.getLines('auth/login.go');
+    const second = cache.getLines('auth/login.go');
+
+    expect(second).toBe(first);
+    expect((await first)?.[5]).toBe('func Login(user, pass string) error {');
+  });
+
+  it('should keep the indexed snippet when the file cannot be read', async () => {
+    const [unchanged] = await addSourceContext([result({ path: 'missing.go' })], {
+      contextLines: 3,
+      query: 'login',
+      cache,
+    });
+
+    expect(unchanged.metadata.snippet).toBe('indexed snippet');
+  });
+
+  it('should size snippet limits to fit the context', () => {
+    expect(maxSourceContextLines(0)).toBeGreaterThan(20);
+    expect(maxSourceContextLines(10)).toBeGreaterThan(maxSourceContextLines(2));
+  });
+});
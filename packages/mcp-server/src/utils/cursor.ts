@@ -0,0 +1,62 @@
+/**
+ * Pagination Cursors
+ * Opaque cursors for paging through ranked results
+ *
+ * A cursor records the offset of the next page, the index version it was
+ * issued against, and a fingerprint of the query, so a page is never taken
+ * from a different index or a different query than the one before it.
+ */
+
+import * as crypto from 'node:crypto';
+
+/**
+ * Decoded cursor contents
+ */
+export interface PageCursor {
+  /** Index version the cursor was issued against */
+  indexVersion: string;
+  /** Fingerprint of the query and options that produced the ranking */
+  queryFingerprint: string;
+  /** Offset of the first result on the next page */
+  offset: number;
+}
+
+/**
+ * Fingerprint the parts of a request that determine its ranking
+ */
+export function fingerprintQuery(parts: Record<string, unknown>): string {
+  const canonical = JSON.stringify(
+    Object.keys(parts)
+      .sort()
+      .map((key) => [key, parts[key] ?? null])
+  );
+  return crypto.createHash('sha1').update(canonical).digest('hex').slice(0, 16);
+}
+
+export function encodeCursor(cursor: PageCursor): string {
+  const payload = [cursor.indexVersion, cursor.queryFingerprint, cursor.offset];
+  return Buffer.from(JSON.stringify(payload)).toString('base64url');
+}
+
+/**
+ * Decode a cursor, or return null if it is malformed
+ */
+export function decodeCursor(cursor: string): PageCursor | null {
+  try {
+    const payload: unknown = JSON.parse(Buffer.from(cursor, 'base64url').toString('utf-8'));
+    if (!Array.isArray(payload) || payload.length !== 3) return null;
+
+    const [indexVersion, queryFingerprint, offset] = payload;
+    if (
+      typeof indexVersion !== 'string' ||
+      typeof queryFingerprint !== 'string' ||
+      !Number.isInteger(offset) ||
+      offset < 0
+    ) {
+      return null;
+    }
+    return { indexVersion, queryFingerprint, offset };
+  } catch {
+    return null;
+  }
+}
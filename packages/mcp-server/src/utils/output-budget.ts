@@ -0,0 +1,340 @@
+/**
+ * Output Budget
+ * Trims tool output to a token budget, lowest-priority content first
+ *
+ * Every tool's markdown puts the most useful content first: the target
+ * symbol before its callers, near hops before distant ones, strong matches
+ * before weak ones. The budget relies on that ordering rather than each tool
+ * truncating its own way. Long code bodies are shortened to their opening
+ * lines first, latest first; then whole blocks (list items with their
+ * details, paragraphs, code blocks) are dropped from the end, along with
+ * headings left without content. The first heading and the first block after
+ * it are always kept. A marker at the end says how much was left out.
+ */
+
+import { estimateTokensForText } from '../formatters/utils';
+
+/**
+ * Environment variable that caps the tokens of every tool response
+ */
+export const MAX_OUTPUT_TOKENS_ENV = 'DEV_AGENT_MAX_TOKENS';
+
+/**
+ * Smallest budget accepted; below this the marker alone would crowd out the answer
+ */
+export const MIN_OUTPUT_TOKENS = 200;
+
+/**
+ * Count the tokens in a piece of text
+ */
+export type OutputTokenCounter = (text: string) => number;
+
+/**
+ * Tool output after fitting it to a budget
+ */
+export interface OutputBudgetResult {
+  /** The output, with a truncation marker when trimmed */
+  text: string;
+  /** Tokens in the returned text, marker included */
+  tokens: number;
+  /** Tokens of the original output that were left out */
+  omittedTokens: number;
+  /** Whether anything was trimmed */
+  truncated: boolean;
+}
+
+/**
+ * Structured tool output after fitting it to a budget
+ */
+export interface StructuredBudgetResult<T> {
+  /** The payload, with items dropped from its arrays when trimmed */
+  data: T;
+  /** The payload as JSON text */
+  text: string;
+  /** Tokens of the original JSON that were left out */
+  omittedTokens: number;
+  /** Whether anything was trimmed */
+  truncated: boolean;
+}
+
+/** Lines a shortened code body keeps */
+const KEPT_BODY_LINES = 6;
+
+interface OutputBlock {
+  kind: 'heading' | 'code' | 'text';
+  /** Heading level (1-6); 0 for other blocks */
+  level: number;
+  lines: string[];
+  /** Tokens in the block's lines */
+  tokens: number;
+  dropped: boolean;
+}
+
+/**
+ * Fit tool output into a token budget
+ *
+ * @param text - Markdown output of a tool
+ * @param maxTokens - Most tokens the returned text may use
+ * @param countTokens - Tokenizer-backed counter (default: calibrated estimate)
+ */
+export function fitOutputToBudget(
+  text: string,
+  maxTokens: number,
+  countTokens: OutputTokenCounter = estimateTokensForText
+): OutputBudgetResult {
+  const originalTokens = countTokens(text);
+  if (originalTokens <= maxTokens) {
+    return { text, tokens: originalTokens, omittedTokens: 0, truncated: false };
+  }
+
+  const blocks = parseBlocks(text);
+  for (const block of blocks) {
+    block.tokens = countTokens(block.lines.join('\n'));
+  }
+  // Reserve room for a marker; its numbers are at most as long as these
+  const available = maxTokens - countTokens(truncationMarker(maxTokens, originalTokens));
+  // Summing block counts avoids re-tokenizing the whole output after every change
+  const fits = () =>
+    blocks.reduce((sum, block) => sum + (block.dropped ? 0 : block.tokens), 0) <= available;
+
+  // Long bodies first: they cost the most and their opening lines carry the gist
+  for (let i = blocks.length - 1; i >= 0 && !fits(); i--) {
+    shortenCode(blocks[i], countTokens);
+  }
+
+  // Then whole blocks from the end; the first heading and block after it stay
+  const protectedCount = blocks.findIndex((block) => block.kind !== 'heading') + 1;
+  let next = blocks.length - 1;
+  const dropUntil = (done: () => boolean) => {
+    for (; next >= protectedCount && !done(); next--) {
+      if (blocks[next].kind !== 'heading') blocks[next].dropped = true;
+    }
+  };
+  dropUntil(fits);
+  // Block counts only approximate the joined text; confirm with a real count
+  dropUntil(() => countTokens(render(blocks)) <= available);
+
+  let kept = render(blocks);
+  if (countTokens(kept) > available) {
+    kept = cutToBudget(kept, available, countTokens);
+  }
+
+  const omittedTokens = Math.max(originalTokens - countTokens(kept), 0);
+  const output = `${kept.trimEnd()}\n\n${truncationMarker(maxTokens, omittedTokens)}\n`;
+  return { text: output, tokens: countTokens(output), omittedTokens, truncated: true };
+}
+
+/**
+ * Fit structured (JSON) tool output into a token budget
+ *
+ * JSON cut mid-way would no longer parse, so whole items are dropped from the
+ * end of the payload's top-level arrays instead, longest array first. Each
+ * array keeps its first item, so a payload with little to drop can stay over
+ * the budget.
+ *
+ * @param data - Structured output of a tool
+ * @param maxTokens - Most tokens the JSON text should use
+ * @param countTokens - Tokenizer-backed counter (default: calibrated estimate)
+ */
+export function fitStructuredToBudget<T extends object>(
+  data: T,
+  maxTokens: number,
+  countTokens: OutputTokenCounter = estimateTokensForText
+): StructuredBudgetResult<T> {
+  const serialize = (value: unknown) => JSON.stringify(value, null, 2);
+  const text = serialize(data);
+  const originalTokens = countTokens(text);
+  if (originalTokens <= maxTokens || Array.isArray(data)) {
+    return { data, text, omittedTokens: 0, truncated: false };
+  }
+
+  const trimmed: Record<string, unknown> = { ...data };
+  const arrays = Object.entries(trimmed)
+    .filter((entry): entry is [string, unknown[]] => Array.isArray(entry[1]))
+    .map(([key, items]) => {
+      const copy = [...items];
+      trimmed[key] = copy;
+      return copy;
+    });
+
+  let kept = text;
+  let tokens = originalTokens;
+  while (tokens > maxTokens) {
+    const longest = arrays.reduce<unknown[] | undefined>(
+      (best, items) => (items.length > 1 && items.length > (best?.length ?? 1) ? items : best),
+      undefined
+    );
+    if (!longest) break;
+    longest.pop();
+    kept = serialize(trimmed);
+    tokens = countTokens(kept);
+  }
+
+  if (kept === text) {
+    return { data, text, omittedTokens: 0, truncated: false };
+  }
+  return {
+    data: trimmed as T,
+    text: kept,
+    omittedTokens: originalTokens - tokens,
+    truncated: true,
+  };
+}
+
+/**
+ * Budget from an environment value; undefined (no budget) if it isn't an integer
+ * of at least MIN_OUTPUT_TOKENS
+ */
+export function parseMaxOutputTokens(value: string | undefined): number | undefined {
+  const tokens = Number((value ?? '').trim());
+  return value?.trim() && Number.isInteger(tokens) && tokens >= MIN_OUTPUT_TOKENS
+    ? tokens
+    : undefined;
+}
+
+function truncationMarker(maxTokens: number, omittedTokens: number): string {
+  return (
+    `---\n*Output trimmed to the ${maxTokens}-token budget: ~${omittedTokens} tokens omitted. ` +
+    'Narrow the request, or raise maxTokens, to see the rest.*'
+  );
+}
+
+/**
+ * Split markdown into headings, fenced code blocks, and text blocks; a
+ * top-level list item keeps its indented lines (nested items, details)
+ */
+function parseBlocks(text: string): OutputBlock[] {
+  const blocks: OutputBlock[] = [];
+  const lines = text.split('\n');
+  let current: OutputBlock | null = null;
+  let open = false;
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i];
+
+    if (/^\s*```/.test(line)) {
+      const fence = [line];
+      while (i + 1 < lines.length) {
+        fence.push(lines[++i]);
+        if (/^\s*```\s*$/.test(lines[i])) break;
+      }
+      if (open && current && /^\s/.test(line)) {
+        // A code block indented under a list item belongs to the item
+        current.lines.push(...fence);
+      } else {
+        current = { kind: 'code', level: 0, lines: fence, tokens: 0, dropped: false };
+        blocks.push(current);
+        open = false;
+      }
+      continue;
+    }
+
+    if (line.trim() === '') {
+      if (current) {
+        current.lines.push(line);
+      } else {
+        current = { kind: 'text', level: 0, lines: [line], tokens: 0, dropped: false };
+        blocks.push(current);
+      }
+      open = false;
+      continue;
+    }
+
+    const heading = line.match(/^(#{1,6})\s/);
+    const startsItem = /^([-*+]|\d+\.)\s/.test(line);
+    const continues = open && current?.kind === 'text' && !heading && !startsItem;
+    if (continues && current) {
+      current.lines.push(line);
+      continue;
+    }
+
+    current = {
+      kind: heading ? 'heading' : 'text',
+      level: heading ? heading[1].length : 0,
+      lines: [line],
+      tokens: 0,
+      dropped: false,
+    };
+    blocks.push(current);
+    open = !heading;
+  }
+
+  return blocks;
+}
+
+/**
+ * Cut a fenced code block down to its opening lines
+ */
+function shortenCode(block: OutputBlock, countTokens: OutputTokenCounter): void {
+  if (block.kind !== 'code') return;
+  let closing = block.lines.length;
+  for (let i = block.lines.length - 1; i > 0; i--) {
+    if (/^\s*```\s*$/.test(block.lines[i])) {
+      closing = i;
+      break;
+    }
+  }
+  const body = block.lines.slice(1, closing);
+  if (body.length <= KEPT_BODY_LINES + 1) return;
+
+  block.lines = [
+    block.lines[0],
+    ...body.slice(0, KEPT_BODY_LINES),
+    `... (${body.length - KEPT_BODY_LINES} more lines)`,
+    ...block.lines.slice(closing),
+  ];
+  block.tokens = countTokens(block.lines.join('\n'));
+}
+
+/**
+ * Kept blocks as markdown; a heading is kept while anything under it is
+ */
+function render(blocks: OutputBlock[]): string {
+  const lines: string[] = [];
+  let firstHeading = true;
+
+  for (let i = 0; i < blocks.length; i++) {
+    const block = blocks[i];
+    if (block.dropped) continue;
+    if (block.kind === 'heading') {
+      if (!firstHeading && !hasContent(blocks, i)) continue;
+      firstHeading = false;
+    }
+    lines.push(...block.lines);
+  }
+
+  return lines.join('\n');
+}
+
+/**
+ * Whether a heading has a kept block before the next heading of the same or higher level
+ */
+function hasContent(blocks: OutputBlock[], headingIndex: number): boolean {
+  const level = blocks[headingIndex].level;
+  for (let i = headingIndex + 1; i < blocks.length; i++) {
+    const block = blocks[i];
+    if (block.kind === 'heading' && block.level <= level) return false;
+    if (block.kind !== 'heading' && !block.dropped) return true;
+  }
+  return false;
+}
+
+/**
+ * Longest prefix of the text, cut at a line break where possible, within the budget
+ */
+function cutToBudget(text: string, maxTokens: number, countTokens: OutputTokenCounter): string {
+  let low = 0;
+  let high = text.length;
+  while (low < high) {
+    const mid = Math.ceil((low + high) / 2);
+    if (countTokens(text.slice(0, mid)) <= maxTokens) {
+      low = mid;
+    } else {
+      high = mid - 1;
+    }
+  }
+
+  const prefix = text.slice(0, low);
+  const lineBreak = prefix.lastIndexOf('\n');
+  return lineBreak > prefix.length / 2 ? prefix.slice(0, lineBreak) : prefix;
+}
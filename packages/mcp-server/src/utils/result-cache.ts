@@ -0,0 +1,150 @@
+/**
+ * Result Cache
+ * LRU cache of tool results, keyed by request and index version
+ *
+ * Agents often retry identical queries. Entries are only valid for the index
+ * version they were computed against; the first lookup against a new version
+ * empties the cache, so a reindex invalidates everything at once.
+ */
+
+import { fingerprintQuery } from './cursor';
+
+/**
+ * Environment variable that sets how many results are cached (0 disables the cache)
+ */
+export const RESULT_CACHE_SIZE_ENV = 'DEV_AGENT_RESULT_CACHE_SIZE';
+
+/**
+ * Results kept when no size is configured
+ */
+export const DEFAULT_RESULT_CACHE_SIZE = 200;
+
+/**
+ * Cache effectiveness since the server started
+ */
+export interface ResultCacheStats {
+  hits: number;
+  misses: number;
+  /** hits / (hits + misses), or 0 before the first lookup */
+  hitRate: number;
+  /** Entries currently cached */
+  size: number;
+  /** Most entries kept before the least recently used is evicted */
+  capacity: number;
+  /** Times a new index version emptied the cache */
+  invalidations: number;
+}
+
+/**
+ * Result Cache
+ * Least recently used entries are evicted first
+ */
+export class ResultCache<T = unknown> {
+  private entries = new Map<string, T>();
+  private indexVersion?: string;
+  private hits = 0;
+  private misses = 0;
+  private invalidations = 0;
+
+  /**
+   * @param capacity Most entries to keep; 0 turns caching off
+   */
+  constructor(readonly capacity: number = DEFAULT_RESULT_CACHE_SIZE) {}
+
+  get enabled(): boolean {
+    return this.capacity > 0;
+  }
+
+  /**
+   * Cached value for a key, counting the lookup as a hit or miss
+   */
+  get(indexVersion: string, key: string): T | undefined {
+    if (!this.enabled) return undefined;
+    this.syncVersion(indexVersion);
+
+    const value = this.entries.get(key);
+    if (value === undefined) {
+      this.misses++;
+      return undefined;
+    }
+    // Re-insert so the entry becomes the most recently used
+    this.entries.delete(key);
+    this.entries.set(key, value);
+    this.hits++;
+    return value;
+  }
+
+  set(indexVersion: string, key: string, value: T): void {
+    if (!this.enabled) return;
+    this.syncVersion(indexVersion);
+
+    this.entries.delete(key);
+    this.entries.set(key, value);
+    while (this.entries.size > this.capacity) {
+      const oldest = this.entries.keys().next().value as string;
+      this.entries.delete(oldest);
+    }
+  }
+
+  clear(): void {
+    this.entries.clear();
+  }
+
+  stats(): ResultCacheStats {
+    const lookups = this.hits + this.misses;
+    return {
+      hits: this.hits,
+      misses: this.misses,
+      hitRate: lookups > 0 ? this.hits / lookups : 0,
+      size: this.entries.size,
+      capacity: this.capacity,
+      invalidations: this.invalidations,
+    };
+  }
+
+  /**
+   * Drop every entry computed against a different index version
+   */
+  private syncVersion(indexVersion: string): void {
+    if (this.indexVersion === indexVersion) return;
+    if (this.indexVersion !== undefined && this.entries.size > 0) {
+      this.invalidations++;
+    }
+    this.entries.clear();
+    this.indexVersion = indexVersion;
+  }
+}
+
+/**
+ * Cache key for a tool request; query text is compared with whitespace collapsed
+ */
+export function resultCacheKey(tool: string, args: Record<string, unknown>): string {
+  const normalized: Record<string, unknown> = {};
+  for (const [name, value] of Object.entries(args)) {
+    normalized[name] = typeof value === 'string' ? value.trim().replace(/\s+/g, ' ') : value;
+  }
+  return `${tool}:${fingerprintQuery(normalized)}`;
+}
+
+/**
+ * Cache size from an environment value, or the default if it isn't a non-negative integer
+ */
+export function parseResultCacheSize(value: string | undefined): number {
+  const size = Number((value ?? '').trim());
+  return value?.trim() && Number.isInteger(size) && size >= 0 ? size : DEFAULT_RESULT_CACHE_SIZE;
+}
+
+/**
+ * One-line hit-rate summary for status output
+ */
+export function formatResultCacheStats(stats: ResultCacheStats): string {
+  if (stats.capacity === 0) {
+    return 'disabled';
+  }
+  const lookups = stats.hits + stats.misses;
+  const rate = (stats.hitRate * 100).toFixed(0);
+  return (
+    `${stats.hits}/${lookups} hits (${rate}%), ` +
+    `${stats.size}/${stats.capacity} entries, ${stats.invalidations} invalidated by reindex`
+  );
+}
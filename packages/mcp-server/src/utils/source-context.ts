@@ -0,0 +1,182 @@
+/**
+ * Source Context Utility
+ * Replaces search result snippets with numbered source lines around the match
+ *
+ * Matched lines (the symbol's range, or the call for call-site matches) are
+ * marked with `>` in the gutter. Files are read once per cache, so a query
+ * that hits the same file several times only reads it once.
+ */
+
+import * as fs from 'node:fs/promises';
+import * as path from 'node:path';
+import type { SearchResult } from '@lytics/dev-agent-core';
+
+/** Symbol lines shown before the middle of a long symbol is collapsed */
+const MAX_CONTEXT_SYMBOL_LINES = 20;
+
+/** Call sites shown for a call-site match */
+const MAX_CALL_SITES = 3;
+
+/**
+ * Reads source files at most once (cache one per query)
+ */
+export class SourceFileCache {
+  private readonly files = new Map<string, Promise<string[] | null>>();
+
+  constructor(private readonly repositoryPath: string) {}
+
+  /**
+   * Lines of a repository-relative file, or null if it can't be read
+   */
+  getLines(file: string): Promise<string[] | null> {
+    let lines = this.files.get(file);
+    if (!lines) {
+      lines = fs
+        .readFile(path.join(this.repositoryPath, file), 'utf-8')
+        .then((content) => content.split('\n'))
+        .catch(() => null);
+      this.files.set(file, lines);
+    }
+    return lines;
+  }
+}
+
+/**
+ * Options for adding source context
+ */
+export interface SourceContextOptions {
+  /** Lines to include before and after the match */
+  contextLines: number;
+  /** The search query, used to detect call-site matches */
+  query: string;
+  /** File cache for this query */
+  cache: SourceFileCache;
+}
+
+/**
+ * Replace each result's snippet with source lines around the match
+ *
+ * A result whose callees include a name from the query is a call-site match:
+ * it shows the calls in context instead of the whole symbol. Results whose
+ * file can't be read keep their indexed snippet.
+ */
+export async function addSourceContext(
+  results: SearchResult[],
+  options: SourceContextOptions
+): Promise<SearchResult[]> {
+  const queryTerms = new Set(
+    (options.query.match(/[A-Za-z_][A-Za-z0-9_]*/g) ?? []).map((t) => t.toLowerCase())
+  );
+
+  return Promise.all(
+    results.map(async (result) => {
+      const { path: file, startLine, endLine } = result.metadata;
+      if (typeof file !== 'string' || typeof startLine !== 'number') {
+        return result;
+      }
+
+      const lines = await options.cache.getLines(file);
+      if (!lines) {
+        return result;
+      }
+
+      const callLines = matchingCallLines(result, queryTerms);
+      const snippet =
+        callLines.length > 0
+          ? formatCallSites(lines, callLines, options.contextLines)
+          : formatSymbol(lines, startLine, endLine ?? startLine, options.contextLines);
+
+      return { ...result, metadata: { ...result.metadata, snippet } };
+    })
+  );
+}
+
+/**
+ * Most lines a context snippet can have, for sizing formatter snippet limits
+ */
+export function maxSourceContextLines(contextLines: number): number {
+  const symbol = MAX_CONTEXT_SYMBOL_LINES + 2 * contextLines + 1;
+  const callSites = MAX_CALL_SITES * (2 * contextLines + 2);
+  return Math.max(symbol, callSites);
+}
+
+/**
+ * Lines of calls whose callee name appears in the query
+ */
+function matchingCallLines(result: SearchResult, queryTerms: Set<string>): number[] {
+  const lines = new Set<number>();
+  for (const callee of result.metadata.callees ?? []) {
+    const name = callee.name.slice(callee.name.lastIndexOf('.') + 1).toLowerCase();
+    if (queryTerms.has(name)) {
+      lines.add(callee.line);
+    }
+  }
+  return Array.from(lines)
+    .sort((a, b) => a - b)
+    .slice(0, MAX_CALL_SITES);
+}
+
+/**
+ * The symbol with context lines around it; long symbols are collapsed in the middle
+ */
+function formatSymbol(
+  lines: string[],
+  startLine: number,
+  endLine: number,
+  contextLines: number
+): string {
+  const first = Math.max(1, startLine - contextLines);
+  const last = Math.min(lines.length, endLine + contextLines);
+  const isMatch = (line: number) => line >= startLine && line <= endLine;
+
+  const symbolLines = endLine - startLine + 1;
+  if (symbolLines <= MAX_CONTEXT_SYMBOL_LINES) {
+    return formatRange(lines, first, last, isMatch);
+  }
+
+  const head = Math.floor(MAX_CONTEXT_SYMBOL_LINES / 2);
+  const tail = MAX_CONTEXT_SYMBOL_LINES - head;
+  const omitted = symbolLines - MAX_CONTEXT_SYMBOL_LINES;
+  return [
+    formatRange(lines, first, startLine + head - 1, isMatch),
+    `     ... ${omitted} more lines`,
+    formatRange(lines, endLine - tail + 1, last, isMatch),
+  ].join('\n');
+}
+
+/**
+ * Each call with context lines around it; overlapping windows are merged
+ */
+function formatCallSites(lines: string[], callLines: number[], contextLines: number): string {
+  const calls = new Set(callLines);
+  const windows: Array<[number, number]> = [];
+  for (const line of callLines) {
+    const first = Math.max(1, line - contextLines);
+    const last = Math.min(lines.length, line + contextLines);
+    const previous = windows[windows.length - 1];
+    if (previous && first <= previous[1] + 1) {
+      previous[1] = last;
+    } else {
+      windows.push([first, last]);
+    }
+  }
+
+  return windows
+    .map(([first, last]) => formatRange(lines, first, last, (line) => calls.has(line)))
+    .join('\n     ...\n');
+}
+
+function formatRange(
+  lines: string[],
+  first: number,
+  last: number,
+  isMatch: (line: number) => boolean
+): string {
+  const width = String(last).length;
+  const output: string[] = [];
+  for (let line = first; line <= last; line++) {
+    const marker = isMatch(line) ? '>' : ' ';
+    output.push(`${String(line).padStart(width)} ${marker} ${lines[line - 1] ?? ''}`);
+  }
+  return output.join('\n');
+}
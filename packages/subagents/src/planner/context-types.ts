@@ -83,8 +83,8 @@ export interface RelatedHistory {
   number: number;
   /** Title */
   title: string;
-  /** Current state */
-  state: 'open' | 'closed' | 'merged';
+  /** Current state, when known (history mined from commits doesn't say) */
+  state?: 'open' | 'closed' | 'merged';
   /** Relevance score (0-1) */
   relevanceScore: number;
   /** Brief summary if available */
@@ -544,5 +544,63 @@ describe('Context Assembler', () => {
 
       expect(result.relatedCommits).toHaveLength(0);
     });
+
+    it('should mine related PRs from commit history', async () => {
+      const historyGitIndexer = {
+        search: vi.fn().mockResolvedValue([
+          {
+            hash: 'aaa',
+            shortHash: 'aaa1111',
+            subject: 'feat: add session auth (#10)',
+            author: { name: 'developer', date: '2025-01-10T00:00:00Z' },
+            files: [{ path: 'src/auth/jwt.ts' }],
+            refs: { issueRefs: [10, 42], prRefs: [10] },
+          },
+        ]),
+        getFileHistory: vi.fn().mockResolvedValue([
+          {
+            hash: 'bbb',
+            shortHash: 'bbb2222',
+            subject: 'fix: token expiry (#7)',
+            author: { name: 'developer', date: '2024-12-01T00:00:00Z' },
+            files: [{ path: 'src/auth/jwt.ts' }],
+            refs: { issueRefs: [7], prRefs: [] },
+          },
+        ]),
+      };
+
+      const result = await assembleContext(
+        42,
+        { indexer: mockIndexer, gitIndexer: historyGitIndexer as any },
+        '/repo'
+      );
+
+      expect(historyGitIndexer.getFileHistory).toHaveBeenCalledWith('src/auth/jwt.ts', {
+        limit: 10,
+      });
+      expect(result.relatedHistory.map((h) => `${h.type}:${h.number}`)).toEqual([
+        'pr:10',
+        'issue:7',
+      ]);
+      expect(result.relatedHistory[0].state).toBeUndefined();
+      expect(result.metadata.historySearchUsed).toBe(true);
+    });
+
+    it('should skip related history when includeHistory is false', async () => {
+      const historyGitIndexer = {
+        search: vi.fn().mockResolvedValue([]),
+        getFileHistory: vi.fn().mockResolvedValue([]),
+      };
+
+      const result = await assembleContext(
+        42,
+        { indexer: mockIndexer, gitIndexer: historyGitIndexer as any },
+        '/repo',
+        { includeHistory: false }
+      );
+
+      expect(result.relatedHistory).toHaveLength(0);
+      expect(historyGitIndexer.getFileHistory).not.toHaveBeenCalled();
+    });
   });
 });
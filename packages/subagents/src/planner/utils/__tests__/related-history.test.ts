@@ -0,0 +1,126 @@
+import type { GitCommit } from '@lytics/dev-agent-core';
+import { describe, expect, it } from 'vitest';
+import { mergeHistoryCandidates, rankRelatedHistory } from '../related-history';
+
+function makeCommit(
+  hash: string,
+  date: string,
+  refs: { issueRefs?: number[]; prRefs?: number[] } = {}
+): GitCommit {
+  return {
+    hash,
+    shortHash: hash.slice(0, 7),
+    message: `commit ${hash}`,
+    subject: `commit ${hash}`,
+    body: '',
+    author: { name: 'dev', email: 'dev@example.com', date },
+    committer: { name: 'dev', email: 'dev@example.com', date },
+    files: [{ path: 'src/a.ts', status: 'modified', additions: 1, deletions: 0 }],
+    stats: { additions: 1, deletions: 0, filesChanged: 1 },
+    refs: {
+      branches: [],
+      tags: [],
+      issueRefs: refs.issueRefs ?? [],
+      prRefs: refs.prRefs ?? [],
+    },
+    parents: [],
+  };
+}
+
+describe('Related History', () => {
+  describe('mergeHistoryCandidates', () => {
+    it('should merge commits by hash and flag file overlap', () => {
+      const a = makeCommit('aaaaaaaa', '2025-01-01T00:00:00Z');
+      const b = makeCommit('bbbbbbbb', '2025-01-02T00:00:00Z');
+
+      const candidates = mergeHistoryCandidates([a], [a, b]);
+
+      expect(candidates).toHaveLength(2);
+      expect(candidates[0]).toMatchObject({ semanticRank: 0, touchesRelevantFiles: true });
+      expect(candidates[1]).toMatchObject({ touchesRelevantFiles: true });
+      expect(candidates[1].semanticRank).toBeUndefined();
+    });
+  });
+
+  describe('rankRelatedHistory', () => {
+    it('should dedupe by PR number keeping the best commit', () => {
+      const candidates = mergeHistoryCandidates(
+        [
+          makeCommit('aaaaaaaa', '2025-01-03T00:00:00Z', { prRefs: [12] }),
+          makeCommit('bbbbbbbb', '2025-01-01T00:00:00Z', { prRefs: [12] }),
+        ],
+        []
+      );
+
+      const history = rankRelatedHistory(candidates, { maxResults: 5 });
+
+      expect(history).toHaveLength(1);
+      expect(history[0]).toMatchObject({ type: 'pr', number: 12 });
+      expect(history[0].state).toBeUndefined();
+      expect(history[0].title).toBe('commit aaaaaaaa');
+    });
+
+    it('should compare unrounded scores when deduping', () => {
+      const date = '2025-01-01T00:00:00Z';
+      const history = rankRelatedHistory(
+        [
+          { commit: makeCommit('aaaaaaaa', date, { prRefs: [12] }), semanticRank: 1 },
+          { commit: makeCommit('bbbbbbbb', date, { prRefs: [12] }), semanticRank: 0 },
+        ],
+        { maxResults: 5, semanticTotal: 1000 }
+      );
+
+      // Both round to 1, but the second commit is the closer match
+      expect(history[0].title).toBe('commit bbbbbbbb');
+    });
+
+    it('should not duplicate a PR number as an issue', () => {
+      const candidates = mergeHistoryCandidates(
+        [makeCommit('aaaaaaaa', '2025-01-01T00:00:00Z', { prRefs: [3], issueRefs: [3, 4] })],
+        []
+      );
+
+      const history = rankRelatedHistory(candidates, { maxResults: 5 });
+
+      expect(history.map((h) => `${h.type}:${h.number}`)).toEqual(['pr:3', 'issue:4']);
+    });
+
+    it('should exclude the issue being planned', () => {
+      const candidates = mergeHistoryCandidates(
+        [makeCommit('aaaaaaaa', '2025-01-01T00:00:00Z', { issueRefs: [42] })],
+        []
+      );
+
+      expect(rankRelatedHistory(candidates, { maxResults: 5, excludeIssue: 42 })).toEqual([]);
+    });
+
+    it('should rank semantic matches above file-history-only commits', () => {
+      const candidates = mergeHistoryCandidates(
+        [
+          makeCommit('aaaaaaaa', '2024-01-01T00:00:00Z', { prRefs: [1] }),
+          makeCommit('bbbbbbbb', '2025-01-01T00:00:00Z', { prRefs: [2] }),
+        ],
+        [makeCommit('cccccccc', '2023-01-01T00:00:00Z', { prRefs: [3] })]
+      );
+
+      const history = rankRelatedHistory(candidates, { maxResults: 5 });
+
+      expect(history.map((h) => h.number)).toEqual([1, 2, 3]);
+    });
+
+    it('should cap results at maxResults', () => {
+      const candidates = mergeHistoryCandidates(
+        [1, 2, 3, 4].map((n) =>
+          makeCommit(`${n}`.repeat(8), `2025-01-0${n}T00:00:00Z`, { prRefs: [n] })
+        ),
+        []
+      );
+
+      expect(rankRelatedHistory(candidates, { maxResults: 2 })).toHaveLength(2);
+    });
+
+    it('should return empty for no candidates', () => {
+      expect(rankRelatedHistory([], { maxResults: 5 })).toEqual([]);
+    });
+  });
+});
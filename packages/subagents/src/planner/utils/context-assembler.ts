@@ -5,7 +5,7 @@
  * Philosophy: Provide raw, structured context - let the LLM do the reasoning
  */
 
-import type { GitIndexer, RepositoryIndexer } from '@lytics/dev-agent-core';
+import type { GitCommit, GitIndexer, RepositoryIndexer } from '@lytics/dev-agent-core';
 import type {
   CodebasePatterns,
   ContextAssemblyOptions,
@@ -18,6 +18,7 @@ import type {
 } from '../context-types';
 import type { GitHubIssue } from '../types';
 import { fetchGitHubIssue } from './github';
+import { mergeHistoryCandidates, rankRelatedHistory } from './related-history';
 
 /** Default options for context assembly */
 const DEFAULT_OPTIONS: Required<ContextAssemblyOptions> = {
@@ -101,11 +102,16 @@ export async function assembleContext(
     codebasePatterns = await detectCodebasePatterns(context.indexer);
   }
 
-  // 4. Find related history (TODO: implement when GitHub indexer is available)
-  const relatedHistory: RelatedHistory[] = [];
-  // if (opts.includeHistory && githubIndexer) {
-  //   relatedHistory = await findRelatedHistory(issue, githubIndexer, opts.maxHistoryResults);
-  // }
+  // 4. Find related PRs/issues mined from git history
+  let relatedHistory: RelatedHistory[] = [];
+  if (opts.includeHistory && opts.includeGitHistory && context.gitIndexer) {
+    relatedHistory = await findRelatedHistory(
+      issue,
+      context.gitIndexer,
+      relevantCode,
+      opts.maxHistoryResults
+    );
+  }
 
   // 5. Find related git commits
   let relatedCommits: RelatedCommit[] = [];
@@ -272,6 +278,53 @@ async function findRelatedCommits(
   }
 }
 
+/** Max relevant files whose history is inspected */
+const MAX_HISTORY_FILES = 5;
+/** Commits fetched per relevant file */
+const COMMITS_PER_FILE = 10;
+
+/**
+ * Find prior PRs/issues from git history
+ *
+ * Combines commits with similar messages and commits that touched the
+ * same files as the relevant code, then dedupes by PR/issue number.
+ */
+async function findRelatedHistory(
+  issue: GitHubIssue,
+  gitIndexer: GitIndexer,
+  relevantCode: RelevantCodeContext[],
+  maxResults: number
+): Promise<RelatedHistory[]> {
+  const searchQuery = buildSearchQuery(issue);
+
+  let semantic: GitCommit[] = [];
+  try {
+    // Over-fetch: many commits carry no PR/issue references
+    semantic = await gitIndexer.search(searchQuery, { limit: maxResults * 4 });
+  } catch {
+    // Fall through with file history only
+  }
+
+  const files = [...new Set(relevantCode.map((c) => c.file).filter(Boolean))].slice(
+    0,
+    MAX_HISTORY_FILES
+  );
+  const fileTouch: GitCommit[] = [];
+  for (const file of files) {
+    try {
+      fileTouch.push(...(await gitIndexer.getFileHistory(file, { limit: COMMITS_PER_FILE })));
+    } catch {
+      // Ignore files without history
+    }
+  }
+
+  return rankRelatedHistory(mergeHistoryCandidates(semantic, fileTouch), {
+    maxResults,
+    excludeIssue: issue.number,
+    semanticTotal: semantic.length,
+  });
+}
+
 /**
  * Detect codebase patterns from indexed data
  */
@@ -417,7 +470,11 @@ export function formatContextPackage(context: ContextPackage): string {
     lines.push('');
     for (const item of context.relatedHistory) {
       const typeLabel = item.type === 'pr' ? 'PR' : 'Issue';
-      lines.push(`- **${typeLabel} #${item.number}:** ${item.title} (${item.state})`);
+      const state = item.state ? ` (${item.state})` : '';
+      lines.push(`- **${typeLabel} #${item.number}:** ${item.title}${state}`);
+      if (item.summary) {
+        lines.push(`  - ${item.summary}`);
+      }
     }
     lines.push('');
   }
@@ -43,3 +43,10 @@ export {
   extractTechnicalRequirements,
   inferPriority,
 } from './parsing';
+// Related history utilities
+export {
+  type HistoryCandidate,
+  mergeHistoryCandidates,
+  type RankHistoryOptions,
+  rankRelatedHistory,
+} from './related-history';
@@ -0,0 +1,173 @@
+/**
+ * Related History Utilities
+ * Mine prior PRs and issues from git commit history
+ *
+ * Commits are collected from two sources: semantic search over commit
+ * messages, and the history of files that the relevant code lives in.
+ * Issue/PR references extracted by the GitExtractor are then deduped
+ * and ranked by topical similarity and recency.
+ */
+
+import type { GitCommit } from '@lytics/dev-agent-core';
+import type { RelatedHistory } from '../context-types';
+
+/** Weight of topical similarity in the final score */
+const SIMILARITY_WEIGHT = 0.7;
+/** Weight of recency in the final score */
+const RECENCY_WEIGHT = 0.3;
+/** Similarity assigned to commits found only via file history */
+const FILE_TOUCH_SIMILARITY = 0.4;
+/** Bonus when a commit is both similar and touched the same files */
+const FILE_TOUCH_BONUS = 0.15;
+
+/**
+ * A commit candidate for related history
+ */
+export interface HistoryCandidate {
+  /** The commit */
+  commit: GitCommit;
+  /** Rank in semantic search results (0 = most similar), if found via search */
+  semanticRank?: number;
+  /** Whether the commit touched files related to the issue */
+  touchesRelevantFiles?: boolean;
+}
+
+/**
+ * Options for ranking related history
+ */
+export interface RankHistoryOptions {
+  /** Maximum number of entries to return */
+  maxResults: number;
+  /** Issue number being planned (excluded from results) */
+  excludeIssue?: number;
+  /** Total number of semantic results (used to normalize rank) */
+  semanticTotal?: number;
+}
+
+/**
+ * Merge commit candidates from multiple sources, keyed by commit hash
+ *
+ * @param semantic - Commits from semantic search, in similarity order
+ * @param fileTouch - Commits that touched relevant files
+ * @returns Merged candidates
+ */
+export function mergeHistoryCandidates(
+  semantic: GitCommit[],
+  fileTouch: GitCommit[]
+): HistoryCandidate[] {
+  const byHash = new Map<string, HistoryCandidate>();
+
+  semantic.forEach((commit, index) => {
+    if (!byHash.has(commit.hash)) {
+      byHash.set(commit.hash, { commit, semanticRank: index });
+    }
+  });
+
+  for (const commit of fileTouch) {
+    const existing = byHash.get(commit.hash);
+    if (existing) {
+      existing.touchesRelevantFiles = true;
+    } else {
+      byHash.set(commit.hash, { commit, touchesRelevantFiles: true });
+    }
+  }
+
+  return Array.from(byHash.values());
+}
+
+/**
+ * Score a candidate's topical similarity (0-1)
+ */
+function similarityScore(candidate: HistoryCandidate, semanticTotal: number): number {
+  let score = 0;
+  if (candidate.semanticRank !== undefined) {
+    score = 1 - candidate.semanticRank / Math.max(semanticTotal, 1);
+  } else if (candidate.touchesRelevantFiles) {
+    score = FILE_TOUCH_SIMILARITY;
+  }
+  if (candidate.semanticRank !== undefined && candidate.touchesRelevantFiles) {
+    score += FILE_TOUCH_BONUS;
+  }
+  return Math.min(score, 1);
+}
+
+/**
+ * Rank commit candidates into deduped PR/issue history entries
+ *
+ * PR references take precedence over issue references. Each PR or issue
+ * number appears at most once, keeping its best-scoring commit.
+ *
+ * @param candidates - Merged commit candidates
+ * @param options - Ranking options
+ * @returns Related history sorted by relevance, capped at maxResults
+ */
+export function rankRelatedHistory(
+  candidates: HistoryCandidate[],
+  options: RankHistoryOptions
+): RelatedHistory[] {
+  if (candidates.length === 0 || options.maxResults <= 0) {
+    return [];
+  }
+
+  const semanticTotal =
+    options.semanticTotal ?? candidates.filter((c) => c.semanticRank !== undefined).length;
+
+  const times = candidates.map((c) => new Date(c.commit.author.date).getTime() || 0);
+  const oldest = Math.min(...times);
+  const newest = Math.max(...times);
+  const span = newest - oldest;
+
+  const best = new Map<string, { entry: RelatedHistory; score: number }>();
+
+  candidates.forEach((candidate, index) => {
+    const recency = span > 0 ? (times[index] - oldest) / span : 1;
+    const score =
+      similarityScore(candidate, semanticTotal) * SIMILARITY_WEIGHT + recency * RECENCY_WEIGHT;
+
+    const { commit } = candidate;
+    const prRefs = commit.refs.prRefs ?? [];
+    const issueRefs = (commit.refs.issueRefs ?? []).filter(
+      (n) => n !== options.excludeIssue && !prRefs.includes(n)
+    );
+
+    const entries: Array<{ type: RelatedHistory['type']; number: number }> = [
+      ...prRefs.map((number) => ({ type: 'pr' as const, number })),
+      ...issueRefs.map((number) => ({ type: 'issue' as const, number })),
+    ];
+
+    for (const entry of entries) {
+      const key = `${entry.type}:${entry.number}`;
+      const existing = best.get(key);
+      if (existing && existing.score >= score) {
+        continue;
+      }
+      // Commits don't say whether the PR or issue is still open, so state is left unset
+      best.set(key, {
+        entry: {
+          type: entry.type,
+          number: entry.number,
+          title: commit.subject,
+          relevanceScore: Math.round(score * 100) / 100,
+          summary: buildSummary(commit),
+        },
+        score,
+      });
+    }
+  });
+
+  return Array.from(best.values())
+    .sort((a, b) => b.score - a.score)
+    .slice(0, options.maxResults)
+    .map(({ entry }) => entry);
+}
+
+/**
+ * Build a one-line summary of the commit behind a history entry
+ */
+function buildSummary(commit: GitCommit): string {
+  const date = new Date(commit.author.date);
+  const day = Number.isNaN(date.getTime()) ? '' : ` on ${date.toISOString().split('T')[0]}`;
+  const files = commit.files?.length ?? 0;
+  const fileText = files === 1 ? '1 file' : `${files} files`;
+  return `${commit.shortHash} by ${commit.author.name}${day}, ${fileText} changed`;
+}